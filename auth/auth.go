@@ -0,0 +1,346 @@
+// Package auth authenticates callers of the Icebox servers. Credentials are API keys,
+// JWT bearer tokens signed with HS256 or RS256, or HTTP basic credentials, configured in
+// the auth section of .icebox.yml. The same Authenticator backs the middleware of the
+// REST API, the web UI and the gRPC services, and records failed attempts in an audit log.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TFMV/icebox/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrNoCredentials is returned when a request carries no credentials
+	ErrNoCredentials = errors.New("credentials required")
+	// ErrInvalidCredentials is returned when a request's credentials are rejected
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Method is the kind of credentials a caller authenticated with
+type Method string
+
+const (
+	MethodAPIKey Method = "api_key"
+	MethodJWT    Method = "jwt"
+	MethodBasic  Method = "basic"
+)
+
+// Principal is an authenticated caller
+type Principal struct {
+	Name   string
+	Roles  []string
+	Method Method
+}
+
+// Request holds the credentials a caller presented and where they came from, for the
+// audit log
+type Request struct {
+	Transport     string // the server the request arrived at: rest, ui, grpc, flightsql or pgwire
+	RemoteAddr    string
+	Authorization string // the Authorization header or metadata
+	APIKey        string // the X-API-Key header or metadata
+}
+
+// AuditEvent is a line of the audit log
+type AuditEvent struct {
+	Time      time.Time `json:"time"`
+	Event     string    `json:"event"`
+	Transport string    `json:"transport,omitempty"`
+	Remote    string    `json:"remote,omitempty"`
+	Method    Method    `json:"method,omitempty"`
+	Subject   string    `json:"subject,omitempty"`
+	Reason    string    `json:"reason"`
+}
+
+type apiKey struct {
+	name  string
+	hash  []byte
+	roles []string
+}
+
+type basicUser struct {
+	passwordHash string
+	roles        []string
+}
+
+// Authenticator checks credentials against the configured API keys, JWT keys and users
+type Authenticator struct {
+	apiKeys []apiKey
+	users   map[string]basicUser
+	jwt     *jwtVerifier
+
+	auditMu  sync.Mutex
+	audit    io.Writer
+	auditLog *os.File
+}
+
+// New creates an Authenticator from the auth section of the configuration
+func New(cfg *config.AuthConfig) (*Authenticator, error) {
+	if cfg == nil || (len(cfg.APIKeys) == 0 && cfg.JWT == nil && len(cfg.Basic) == 0) {
+		return nil, errors.New("no API keys, JWT keys or basic users are configured")
+	}
+
+	a := &Authenticator{
+		users: make(map[string]basicUser),
+		audit: os.Stderr,
+	}
+
+	for _, key := range cfg.APIKeys {
+		hash, err := decodeSHA256(key.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hash for API key %q: %w", key.Name, err)
+		}
+		a.apiKeys = append(a.apiKeys, apiKey{name: key.Name, hash: hash, roles: key.Roles})
+	}
+
+	for _, user := range cfg.Basic {
+		if user.Username == "" {
+			return nil, errors.New("basic users need a username")
+		}
+		if err := ValidatePasswordHash(user.PasswordHash); err != nil {
+			return nil, fmt.Errorf("invalid password hash for user %q: %w", user.Username, err)
+		}
+		a.users[user.Username] = basicUser{passwordHash: user.PasswordHash, roles: user.Roles}
+	}
+
+	if cfg.JWT != nil {
+		verifier, err := newJWTVerifier(cfg.JWT)
+		if err != nil {
+			return nil, err
+		}
+		a.jwt = verifier
+	}
+
+	if cfg.AuditLog != "" {
+		file, err := os.OpenFile(cfg.AuditLog, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log: %w", err)
+		}
+		a.audit = file
+		a.auditLog = file
+	}
+
+	return a, nil
+}
+
+// SetAuditWriter sends the audit log to w instead of the configured file
+func (a *Authenticator) SetAuditWriter(w io.Writer) {
+	a.auditMu.Lock()
+	defer a.auditMu.Unlock()
+	a.audit = w
+}
+
+// Close closes the audit log
+func (a *Authenticator) Close() error {
+	if a.auditLog != nil {
+		return a.auditLog.Close()
+	}
+	return nil
+}
+
+// Methods returns the kinds of credentials that are accepted
+func (a *Authenticator) Methods() []Method {
+	var methods []Method
+	if len(a.apiKeys) > 0 {
+		methods = append(methods, MethodAPIKey)
+	}
+	if a.jwt != nil {
+		methods = append(methods, MethodJWT)
+	}
+	if len(a.users) > 0 {
+		methods = append(methods, MethodBasic)
+	}
+	return methods
+}
+
+// Authenticate checks the credentials of a request. API keys are accepted in the X-API-Key
+// header or as Bearer tokens, JWTs as Bearer tokens, and user credentials with the Basic
+// scheme. Failures are written to the audit log.
+func (a *Authenticator) Authenticate(req Request) (*Principal, error) {
+	principal, method, subject, err := a.verify(req)
+	if err != nil {
+		a.auditFailure(req, method, subject, err)
+		if errors.Is(err, ErrNoCredentials) {
+			return nil, ErrNoCredentials
+		}
+		return nil, ErrInvalidCredentials
+	}
+	return principal, nil
+}
+
+// AuthenticatePassword checks a user name and password, for protocols without HTTP
+// headers. The password may also be an API key or a JWT, in which case the user name is
+// ignored.
+func (a *Authenticator) AuthenticatePassword(req Request, username, password string) (*Principal, error) {
+	var principal *Principal
+	var method Method
+	var subject string
+	var err error
+
+	switch _, known := a.users[username]; {
+	case known || (len(a.apiKeys) == 0 && a.jwt == nil):
+		method, subject = MethodBasic, username
+		principal, err = a.verifyBasic(username, password)
+	case a.jwt != nil && looksLikeJWT(password):
+		method = MethodJWT
+		principal, err = a.jwt.verify(password)
+	default:
+		method, subject = MethodAPIKey, username
+		principal, err = a.verifyAPIKey(password)
+	}
+
+	if err != nil {
+		a.auditFailure(req, method, subject, err)
+		return nil, ErrInvalidCredentials
+	}
+	return principal, nil
+}
+
+// verify checks the credentials of a request and returns the method and, when known,
+// the subject they claimed
+func (a *Authenticator) verify(req Request) (*Principal, Method, string, error) {
+	if req.APIKey != "" {
+		principal, err := a.verifyAPIKey(req.APIKey)
+		return principal, MethodAPIKey, "", err
+	}
+	if req.Authorization == "" {
+		return nil, "", "", ErrNoCredentials
+	}
+
+	scheme, value, _ := strings.Cut(req.Authorization, " ")
+	value = strings.TrimSpace(value)
+	switch strings.ToLower(scheme) {
+	case "bearer":
+		if a.jwt != nil && looksLikeJWT(value) {
+			principal, err := a.jwt.verify(value)
+			return principal, MethodJWT, "", err
+		}
+		principal, err := a.verifyAPIKey(value)
+		return principal, MethodAPIKey, "", err
+	case "basic":
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, MethodBasic, "", errors.New("malformed basic credentials")
+		}
+		username, password, ok := strings.Cut(string(decoded), ":")
+		if !ok {
+			return nil, MethodBasic, "", errors.New("malformed basic credentials")
+		}
+		principal, err := a.verifyBasic(username, password)
+		return principal, MethodBasic, username, err
+	default:
+		return nil, "", "", fmt.Errorf("unsupported authorization scheme %q", scheme)
+	}
+}
+
+func (a *Authenticator) verifyAPIKey(key string) (*Principal, error) {
+	if len(a.apiKeys) == 0 {
+		return nil, errors.New("API keys are not enabled")
+	}
+
+	// Every key is compared so that the time taken does not depend on which one matched
+	sum := sha256.Sum256([]byte(key))
+	var match *apiKey
+	for i := range a.apiKeys {
+		if subtle.ConstantTimeCompare(sum[:], a.apiKeys[i].hash) == 1 {
+			match = &a.apiKeys[i]
+		}
+	}
+	if match == nil {
+		return nil, errors.New("unknown API key")
+	}
+	return &Principal{Name: match.name, Roles: match.roles, Method: MethodAPIKey}, nil
+}
+
+func (a *Authenticator) verifyBasic(username, password string) (*Principal, error) {
+	user, ok := a.users[username]
+	if !ok {
+		return nil, errors.New("unknown user")
+	}
+	if !CheckPasswordHash(user.passwordHash, password) {
+		return nil, errors.New("wrong password")
+	}
+	return &Principal{Name: username, Roles: user.roles, Method: MethodBasic}, nil
+}
+
+// ValidatePasswordHash checks that a configured password is a bcrypt or
+// "sha256:<hex digest>" hash rather than a password in plain text
+func ValidatePasswordHash(hash string) error {
+	if strings.HasPrefix(hash, "$2") {
+		return nil
+	}
+	if _, err := decodeSHA256(hash); err != nil {
+		return errors.New("use a bcrypt hash or sha256:<hex digest>")
+	}
+	return nil
+}
+
+// CheckPasswordHash compares a password with a bcrypt or "sha256:<hex digest>" hash
+func CheckPasswordHash(hash, password string) bool {
+	if strings.HasPrefix(hash, "$2") {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+	}
+	expected, err := decodeSHA256(hash)
+	if err != nil {
+		return false
+	}
+	sum := sha256.Sum256([]byte(password))
+	return subtle.ConstantTimeCompare(sum[:], expected) == 1
+}
+
+// decodeSHA256 decodes a "sha256:<hex digest>" hash
+func decodeSHA256(hash string) ([]byte, error) {
+	digest, ok := strings.CutPrefix(hash, "sha256:")
+	if !ok {
+		return nil, errors.New(`hash must start with "sha256:"`)
+	}
+	decoded, err := hex.DecodeString(digest)
+	if err != nil || len(decoded) != sha256.Size {
+		return nil, errors.New("hash must be a hex SHA-256 digest")
+	}
+	return decoded, nil
+}
+
+func (a *Authenticator) auditFailure(req Request, method Method, subject string, err error) {
+	event := AuditEvent{
+		Time:      time.Now().UTC(),
+		Event:     "auth_failure",
+		Transport: req.Transport,
+		Remote:    req.RemoteAddr,
+		Method:    method,
+		Subject:   subject,
+		Reason:    err.Error(),
+	}
+	line, _ := json.Marshal(event)
+
+	a.auditMu.Lock()
+	defer a.auditMu.Unlock()
+	_, _ = a.audit.Write(append(line, '\n'))
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying an authenticated principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the principal of an authenticated request, or nil
+func FromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
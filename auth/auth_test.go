@@ -0,0 +1,276 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TFMV/icebox/config"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func sha256Hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func basicAuth(username, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
+
+func signHS256(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return token
+}
+
+// writeJWKS writes the public half of an RSA key to a JWKS file
+func writeJWKS(t *testing.T, path, kid string, key *rsa.PrivateKey) {
+	t.Helper()
+
+	set := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+		{Key: &key.PublicKey, KeyID: kid, Algorithm: "RS256", Use: "sig"},
+	}}
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0600))
+}
+
+func TestAPIKeys(t *testing.T) {
+	a, err := New(&config.AuthConfig{
+		APIKeys: []config.APIKeyConfig{
+			{Name: "etl", Hash: sha256Hash("key-one"), Roles: []string{"writer"}},
+			{Name: "dashboards", Hash: sha256Hash("key-two")},
+		},
+	})
+	require.NoError(t, err)
+	a.SetAuditWriter(&bytes.Buffer{})
+	assert.Equal(t, []Method{MethodAPIKey}, a.Methods())
+
+	principal, err := a.Authenticate(Request{APIKey: "key-one"})
+	require.NoError(t, err)
+	assert.Equal(t, &Principal{Name: "etl", Roles: []string{"writer"}, Method: MethodAPIKey}, principal)
+
+	principal, err = a.Authenticate(Request{Authorization: "Bearer key-two"})
+	require.NoError(t, err)
+	assert.Equal(t, "dashboards", principal.Name)
+
+	_, err = a.Authenticate(Request{APIKey: "key-three"})
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = a.Authenticate(Request{})
+	assert.ErrorIs(t, err, ErrNoCredentials)
+
+	_, err = New(&config.AuthConfig{APIKeys: []config.APIKeyConfig{{Name: "plain", Hash: "key-one"}}})
+	assert.Error(t, err)
+	_, err = New(&config.AuthConfig{})
+	assert.Error(t, err)
+}
+
+func TestJWTSecret(t *testing.T) {
+	a, err := New(&config.AuthConfig{
+		JWT: &config.JWTConfig{Secret: "shared-secret", Issuer: "https://idp.example.com", Audience: "icebox"},
+	})
+	require.NoError(t, err)
+	a.SetAuditWriter(&bytes.Buffer{})
+
+	claims := jwt.MapClaims{
+		"sub":   "alice",
+		"iss":   "https://idp.example.com",
+		"aud":   "icebox",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{"reader", "writer"},
+	}
+	principal, err := a.Authenticate(Request{Authorization: "Bearer " + signHS256(t, "shared-secret", claims)})
+	require.NoError(t, err)
+	assert.Equal(t, &Principal{Name: "alice", Roles: []string{"reader", "writer"}, Method: MethodJWT}, principal)
+
+	tests := map[string]func(jwt.MapClaims){
+		"expired":        func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"no expiration":  func(c jwt.MapClaims) { delete(c, "exp") },
+		"wrong issuer":   func(c jwt.MapClaims) { c["iss"] = "https://other.example.com" },
+		"wrong audience": func(c jwt.MapClaims) { c["aud"] = "other" },
+		"no subject":     func(c jwt.MapClaims) { delete(c, "sub") },
+	}
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			invalid := jwt.MapClaims{}
+			for k, v := range claims {
+				invalid[k] = v
+			}
+			modify(invalid)
+			_, err := a.Authenticate(Request{Authorization: "Bearer " + signHS256(t, "shared-secret", invalid)})
+			assert.ErrorIs(t, err, ErrInvalidCredentials)
+		})
+	}
+
+	_, err = a.Authenticate(Request{Authorization: "Bearer " + signHS256(t, "other-secret", claims)})
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestJWTKeySetRotation(t *testing.T) {
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	first, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	writeJWKS(t, jwksFile, "first", first)
+
+	a, err := New(&config.AuthConfig{JWT: &config.JWTConfig{JWKSFile: jwksFile, RolesClaim: "scope"}})
+	require.NoError(t, err)
+	a.SetAuditWriter(&bytes.Buffer{})
+
+	sign := func(kid string, key *rsa.PrivateKey) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"sub":   "service",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"scope": "read write",
+		})
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+		return signed
+	}
+
+	principal, err := a.Authenticate(Request{Authorization: "Bearer " + sign("first", first)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"read", "write"}, principal.Roles)
+
+	// HS256 tokens are not accepted without a secret
+	_, err = a.Authenticate(Request{Authorization: "Bearer " + signHS256(t, "secret", jwt.MapClaims{"sub": "x"})})
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	// A rotated key is picked up from the file without a restart
+	second, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, err = a.Authenticate(Request{Authorization: "Bearer " + sign("second", second)})
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	writeJWKS(t, jwksFile, "second", second)
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(jwksFile, later, later))
+	_, err = a.Authenticate(Request{Authorization: "Bearer " + sign("second", second)})
+	assert.NoError(t, err)
+}
+
+func TestBasicUsersAndAuditLog(t *testing.T) {
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("hunter2"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	auditLog := filepath.Join(t.TempDir(), "audit.log")
+	a, err := New(&config.AuthConfig{
+		Basic: []config.BasicUser{
+			{Username: "alice", PasswordHash: string(bcryptHash), Roles: []string{"admin"}},
+			{Username: "bob", PasswordHash: sha256Hash("s3cret")},
+		},
+		AuditLog: auditLog,
+	})
+	require.NoError(t, err)
+
+	principal, err := a.Authenticate(Request{Authorization: basicAuth("alice", "hunter2")})
+	require.NoError(t, err)
+	assert.Equal(t, &Principal{Name: "alice", Roles: []string{"admin"}, Method: MethodBasic}, principal)
+
+	principal, err = a.AuthenticatePassword(Request{Transport: "pgwire"}, "bob", "s3cret")
+	require.NoError(t, err)
+	assert.Equal(t, "bob", principal.Name)
+
+	_, err = a.Authenticate(Request{Transport: "rest", RemoteAddr: "10.0.0.1", Authorization: basicAuth("alice", "guess123")})
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = a.AuthenticatePassword(Request{Transport: "pgwire"}, "mallory", "s3cret")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	require.NoError(t, a.Close())
+
+	// Only failures are audited, without the credentials that were presented
+	data, err := os.ReadFile(auditLog)
+	require.NoError(t, err)
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	require.Len(t, lines, 2)
+	assert.NotContains(t, string(data), "guess123")
+
+	var event AuditEvent
+	require.NoError(t, json.Unmarshal(lines[0], &event))
+	assert.Equal(t, "auth_failure", event.Event)
+	assert.Equal(t, "rest", event.Transport)
+	assert.Equal(t, "10.0.0.1", event.Remote)
+	assert.Equal(t, MethodBasic, event.Method)
+	assert.Equal(t, "alice", event.Subject)
+	assert.Equal(t, "wrong password", event.Reason)
+
+	require.NoError(t, json.Unmarshal(lines[1], &event))
+	assert.Equal(t, "pgwire", event.Transport)
+	assert.Equal(t, "mallory", event.Subject)
+	assert.Equal(t, "unknown user", event.Reason)
+}
+
+func TestMiddleware(t *testing.T) {
+	a, err := New(&config.AuthConfig{
+		APIKeys: []config.APIKeyConfig{{Name: "etl", Hash: sha256Hash("key-one")}},
+		Basic:   []config.BasicUser{{Username: "bob", PasswordHash: sha256Hash("s3cret")}},
+	})
+	require.NoError(t, err)
+	a.SetAuditWriter(&bytes.Buffer{})
+
+	t.Run("http", func(t *testing.T) {
+		handler := a.HTTP("ui", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(FromContext(r.Context()).Name))
+		}))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, `Basic realm="Icebox"`, rec.Header().Get("WWW-Authenticate"))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", basicAuth("bob", "s3cret"))
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "bob", rec.Body.String())
+	})
+
+	t.Run("grpc", func(t *testing.T) {
+		interceptor := a.UnaryServerInterceptor("grpc")
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return FromContext(ctx).Name, nil
+		}
+		info := &grpc.UnaryServerInfo{FullMethod: "/icebox.v1.CatalogService/ListNamespaces"}
+
+		_, err := interceptor(context.Background(), nil, info, handler)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", "key-one"))
+		name, err := interceptor(ctx, nil, info, handler)
+		require.NoError(t, err)
+		assert.Equal(t, "etl", name)
+
+		// Health checks do not need credentials
+		health := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+		_, err = interceptor(context.Background(), nil, health, func(ctx context.Context, req interface{}) (interface{}, error) {
+			if FromContext(ctx) != nil {
+				return nil, errors.New("unexpected principal")
+			}
+			return nil, nil
+		})
+		assert.NoError(t, err)
+	})
+}
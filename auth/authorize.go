@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/TFMV/icebox/catalog/grant"
+)
+
+// ErrPermissionDenied is returned when a principal lacks the privilege an operation needs
+var ErrPermissionDenied = errors.New("permission denied")
+
+// GrantStore holds the access grants an Authorizer enforces
+type GrantStore interface {
+	ListGrants(ctx context.Context) ([]grant.Grant, error)
+}
+
+// Authorizer checks the privileges of principals against the grants stored in the
+// catalog. A grant applies to a principal when it names the principal or one of its roles.
+type Authorizer struct {
+	grants GrantStore
+}
+
+// NewAuthorizer creates an Authorizer that enforces the given grants
+func NewAuthorizer(grants GrantStore) *Authorizer {
+	return &Authorizer{grants: grants}
+}
+
+// Authorize returns ErrPermissionDenied unless the principal holds the privilege on the
+// table of the namespace. An empty table stands for the namespace itself and an empty
+// namespace for the whole catalog.
+func (a *Authorizer) Authorize(ctx context.Context, principal *Principal, privilege grant.Privilege, namespace []string, tableName string) error {
+	resource := describeResource(namespace, tableName)
+	if principal == nil {
+		return fmt.Errorf("%w: %s privilege on %s requires authentication", ErrPermissionDenied, privilege, resource)
+	}
+
+	grants, err := a.principalGrants(ctx, principal)
+	if err != nil {
+		return err
+	}
+	for _, g := range grants {
+		if g.Privilege.Includes(privilege) && g.Covers(namespace, tableName) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s needs the %s privilege on %s", ErrPermissionDenied, principal.Name, privilege, resource)
+}
+
+// CanAccess reports whether the principal holds a privilege on the namespace or on
+// anything in it, so that listings only show what the principal can use
+func (a *Authorizer) CanAccess(ctx context.Context, principal *Principal, namespace []string) (bool, error) {
+	if principal == nil {
+		return false, nil
+	}
+
+	grants, err := a.principalGrants(ctx, principal)
+	if err != nil {
+		return false, err
+	}
+	for _, g := range grants {
+		if g.Covers(namespace, "") || strings.HasPrefix(strings.ToLower(g.Namespace+"."), strings.ToLower(strings.Join(namespace, ".")+".")) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// principalGrants returns the grants that apply to a principal
+func (a *Authorizer) principalGrants(ctx context.Context, principal *Principal) ([]grant.Grant, error) {
+	grants, err := a.grants.ListGrants(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load grants: %w", err)
+	}
+
+	grantees := map[string]bool{grant.User(principal.Name): true}
+	for _, role := range principal.Roles {
+		grantees[grant.Role(role)] = true
+	}
+
+	var applicable []grant.Grant
+	for _, g := range grants {
+		if grantees[g.Grantee] {
+			applicable = append(applicable, g)
+		}
+	}
+	return applicable, nil
+}
+
+func describeResource(namespace []string, tableName string) string {
+	parts := append([]string(nil), namespace...)
+	if tableName != "" {
+		parts = append(parts, tableName)
+	}
+	if len(parts) == 0 {
+		return "the catalog"
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TFMV/icebox/config"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwtLeeway is the clock skew tolerated when checking the times of a token
+const jwtLeeway = time.Minute
+
+// jwtVerifier validates JWT bearer tokens signed with a shared HS256 secret or with an
+// RS256 key from a local JWKS file. The file is read again when a token names a key ID it
+// does not know, so keys can be rotated without a restart.
+type jwtVerifier struct {
+	secret     []byte
+	jwksFile   string
+	rolesClaim string
+	parser     *jwt.Parser
+
+	mu       sync.Mutex
+	keys     map[string]*rsa.PublicKey
+	modified time.Time
+}
+
+func newJWTVerifier(cfg *config.JWTConfig) (*jwtVerifier, error) {
+	if cfg.Secret == "" && cfg.JWKSFile == "" {
+		return nil, errors.New("jwt needs a secret or a jwks_file")
+	}
+
+	v := &jwtVerifier{
+		secret:     []byte(cfg.Secret),
+		jwksFile:   cfg.JWKSFile,
+		rolesClaim: cfg.RolesClaim,
+	}
+	if v.rolesClaim == "" {
+		v.rolesClaim = "roles"
+	}
+
+	var methods []string
+	if cfg.Secret != "" {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if cfg.JWKSFile != "" {
+		if err := v.loadKeys(); err != nil {
+			return nil, err
+		}
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithLeeway(jwtLeeway),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	v.parser = jwt.NewParser(opts...)
+	return v, nil
+}
+
+// loadKeys reads the RSA public keys of the JWKS file
+func (v *jwtVerifier) loadKeys() error {
+	info, err := os.Stat(v.jwksFile)
+	if err != nil {
+		return fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+	data, err := os.ReadFile(v.jwksFile)
+	if err != nil {
+		return fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+
+	var set jose.JSONWebKeySet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("failed to parse JWKS file: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, key := range set.Keys {
+		if rsaKey, ok := key.Key.(*rsa.PublicKey); ok && key.Use != "enc" {
+			keys[key.KeyID] = rsaKey
+		}
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("JWKS file %s has no RSA signing keys", v.jwksFile)
+	}
+
+	v.keys = keys
+	v.modified = info.ModTime()
+	return nil
+}
+
+// rsaKey returns the public key with the given ID. A token without a key ID may use the
+// only key of the file.
+func (v *jwtVerifier) rsaKey(kid string) (*rsa.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, nil
+		}
+	}
+
+	if info, err := os.Stat(v.jwksFile); err == nil && info.ModTime().After(v.modified) {
+		if err := v.loadKeys(); err != nil {
+			return nil, err
+		}
+		if key, ok := v.keys[kid]; ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key ID %q", kid)
+}
+
+func (v *jwtVerifier) verify(tokenString string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := v.parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			return v.secret, nil
+		case *jwt.SigningMethodRSA:
+			kid, _ := token.Header["kid"].(string)
+			return v.rsaKey(kid)
+		default:
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+	return &Principal{Name: subject, Roles: claimStrings(claims[v.rolesClaim]), Method: MethodJWT}, nil
+}
+
+// claimStrings reads a claim holding a list of strings or a space-separated string
+func claimStrings(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return strings.Fields(value)
+	case []interface{}:
+		var values []string
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// looksLikeJWT reports whether a bearer token is a JWT rather than an API key
+func looksLikeJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
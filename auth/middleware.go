@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// challenge is the WWW-Authenticate header of rejected HTTP requests. Browsers prompt
+// for basic credentials when it names the Basic scheme.
+func (a *Authenticator) challenge() string {
+	if len(a.users) > 0 {
+		return `Basic realm="Icebox"`
+	}
+	return `Bearer realm="Icebox"`
+}
+
+// HTTP wraps a net/http handler so that it only serves authenticated requests. The
+// principal is available to the handler through FromContext.
+func (a *Authenticator) HTTP(transport string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, err := a.Authenticate(Request{
+			Transport:     transport,
+			RemoteAddr:    r.RemoteAddr,
+			Authorization: r.Header.Get("Authorization"),
+			APIKey:        r.Header.Get("X-API-Key"),
+		})
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", a.challenge())
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// Fiber returns Fiber middleware that only passes authenticated requests on. Rejected
+// requests are answered by onError; the principal of accepted ones is available through
+// FromContext on the request's user context.
+func (a *Authenticator) Fiber(transport string, onError func(c *fiber.Ctx, err error) error) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, err := a.Authenticate(Request{
+			Transport:     transport,
+			RemoteAddr:    c.IP(),
+			Authorization: c.Get(fiber.HeaderAuthorization),
+			APIKey:        c.Get("X-API-Key"),
+		})
+		if err != nil {
+			c.Set(fiber.HeaderWWWAuthenticate, a.challenge())
+			return onError(c, err)
+		}
+		c.SetUserContext(WithPrincipal(c.UserContext(), principal))
+		return c.Next()
+	}
+}
+
+// healthService is the gRPC health checking service, which does not need credentials
+const healthService = "/grpc.health.v1.Health/"
+
+// UnaryServerInterceptor authenticates unary gRPC calls from the authorization or
+// x-api-key metadata
+func (a *Authenticator) UnaryServerInterceptor(transport string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, healthService) {
+			return handler(ctx, req)
+		}
+		ctx, err := a.authenticateGRPC(ctx, transport)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor authenticates streaming gRPC calls from the authorization or
+// x-api-key metadata
+func (a *Authenticator) StreamServerInterceptor(transport string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if strings.HasPrefix(info.FullMethod, healthService) {
+			return handler(srv, stream)
+		}
+		ctx, err := a.authenticateGRPC(stream.Context(), transport)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+func (a *Authenticator) authenticateGRPC(ctx context.Context, transport string) (context.Context, error) {
+	req := Request{Transport: transport}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			req.Authorization = values[0]
+		}
+		if values := md.Get("x-api-key"); len(values) > 0 {
+			req.APIKey = values[0]
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		req.RemoteAddr = p.Addr.String()
+	}
+
+	principal, err := a.Authenticate(req)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return WithPrincipal(ctx, principal), nil
+}
+
+// authenticatedStream is a server stream whose context carries the caller's principal
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -1,13 +1,19 @@
 package catalog
 
 import (
+	"context"
 	"fmt"
+	"iter"
 
+	"github.com/TFMV/icebox/catalog/grant"
 	"github.com/TFMV/icebox/catalog/json"
 	"github.com/TFMV/icebox/catalog/rest"
 	"github.com/TFMV/icebox/catalog/sqlite"
+	"github.com/TFMV/icebox/catalog/view"
 	"github.com/TFMV/icebox/config"
+	"github.com/apache/iceberg-go"
 	icebergcatalog "github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
 )
 
 // CatalogInterface defines the common interface for all catalog implementations
@@ -17,6 +23,30 @@ type CatalogInterface interface {
 	Close() error
 }
 
+// MetadataFileExpirer is implemented by catalogs that write their own table metadata
+// files and can prune old versions of them
+type MetadataFileExpirer interface {
+	ExpireMetadataFiles(ctx context.Context, identifier table.Identifier, retainLast int) ([]string, error)
+}
+
+// ViewCatalog is implemented by catalogs that can store Iceberg views
+type ViewCatalog interface {
+	CreateView(ctx context.Context, identifier table.Identifier, sql string, dialect string, schema *iceberg.Schema, properties map[string]string) (*view.View, error)
+	LoadView(ctx context.Context, identifier table.Identifier) (*view.View, error)
+	ListViews(ctx context.Context, namespace table.Identifier) iter.Seq2[table.Identifier, error]
+	RenameView(ctx context.Context, from, to table.Identifier) (*view.View, error)
+	DropView(ctx context.Context, identifier table.Identifier) error
+	ViewExists(ctx context.Context, identifier table.Identifier) (bool, error)
+}
+
+// GrantCatalog is implemented by catalogs that can store access grants on namespaces
+// and tables
+type GrantCatalog interface {
+	ListGrants(ctx context.Context) ([]grant.Grant, error)
+	SetGrant(ctx context.Context, g grant.Grant) error
+	RevokeGrant(ctx context.Context, grantee, namespace, tableName string) error
+}
+
 // NewCatalog creates a new catalog based on the configuration type
 func NewCatalog(cfg *config.Config) (CatalogInterface, error) {
 	switch cfg.Catalog.Type {
@@ -140,6 +140,26 @@ func TestNewCatalogJSON(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestViewCatalogCapability(t *testing.T) {
+	catalog, err := NewCatalog(createTestSQLiteConfig(t))
+	require.NoError(t, err)
+	defer catalog.Close()
+
+	views, ok := catalog.(ViewCatalog)
+	require.True(t, ok, "SQLite catalog should support views")
+
+	ctx := context.Background()
+	require.NoError(t, catalog.CreateNamespace(ctx, table.Identifier{"default"}, iceberg.Properties{}))
+
+	identifier := table.Identifier{"default", "answers"}
+	_, err = views.CreateView(ctx, identifier, "SELECT 42 AS answer", "duckdb", nil, nil)
+	require.NoError(t, err)
+
+	exists, err := views.ViewExists(ctx, identifier)
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
 // Helper functions
 
 func createTestSQLiteConfig(t *testing.T) *config.Config {
@@ -0,0 +1,41 @@
+package grant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrivilegeIncludes(t *testing.T) {
+	assert.True(t, Admin.Includes(Read))
+	assert.True(t, Write.Includes(Write))
+	assert.False(t, Read.Includes(Write))
+	assert.False(t, Privilege("owner").Includes(Read))
+
+	privilege, err := ParsePrivilege(" WRITE ")
+	assert.NoError(t, err)
+	assert.Equal(t, Write, privilege)
+	_, err = ParsePrivilege("owner")
+	assert.Error(t, err)
+}
+
+func TestGrantCovers(t *testing.T) {
+	all := Grant{Grantee: Role("admin"), Namespace: AllNamespaces, Privilege: Admin}
+	sales := Grant{Grantee: Role("analyst"), Namespace: "sales", Privilege: Read}
+	orders := Grant{Grantee: User("loader"), Namespace: "sales", Table: "orders", Privilege: Write}
+
+	assert.True(t, all.Covers([]string{"hr"}, "salaries"))
+	assert.True(t, sales.Covers([]string{"sales"}, ""))
+	assert.True(t, sales.Covers([]string{"Sales"}, "orders"))
+	assert.True(t, sales.Covers([]string{"sales", "emea"}, "orders"))
+	assert.False(t, sales.Covers([]string{"hr"}, "orders"))
+	assert.True(t, orders.Covers([]string{"sales"}, "orders"))
+	assert.True(t, orders.Covers([]string{"SALES"}, "Orders"))
+	assert.False(t, orders.Covers([]string{"sales"}, "customers"))
+	assert.False(t, orders.Covers([]string{"sales"}, ""))
+
+	assert.Equal(t, "sales.orders", orders.Resource())
+	assert.NoError(t, orders.Validate())
+	assert.Error(t, Grant{Grantee: "loader", Namespace: "sales", Privilege: Read}.Validate())
+	assert.Error(t, Grant{Grantee: Role("x"), Namespace: "", Privilege: Read}.Validate())
+}
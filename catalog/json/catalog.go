@@ -1,20 +1,32 @@
 package json
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	iofs "io/fs"
 	"iter"
 	"log"
+	"maps"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/TFMV/icebox/catalog/grant"
+	"github.com/TFMV/icebox/catalog/metadatalog"
+	"github.com/TFMV/icebox/catalog/view"
 	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/fs"
+	"github.com/TFMV/icebox/fs/s3"
 	"github.com/apache/iceberg-go"
 	"github.com/apache/iceberg-go/catalog"
 	icebergio "github.com/apache/iceberg-go/io"
@@ -32,6 +44,9 @@ const (
 	RetryDelayBase = 100 * time.Millisecond
 )
 
+// metadataFilePattern matches table metadata file names (vN.metadata.json)
+var metadataFilePattern = regexp.MustCompile(`^v(\d+)\.metadata\.json$`)
+
 // CatalogData represents the JSON structure stored in catalog.json
 type CatalogData struct {
 	CatalogName string                    `json:"catalog_name"`
@@ -41,6 +56,7 @@ type CatalogData struct {
 	Version     int                       `json:"version"`    // Schema version for future migrations
 	CreatedAt   time.Time                 `json:"created_at"` // When catalog was created
 	UpdatedAt   time.Time                 `json:"updated_at"` // Last update timestamp
+	Grants      []grant.Grant             `json:"grants,omitempty"`
 }
 
 // NamespaceEntry represents a namespace in the catalog
@@ -69,119 +85,16 @@ type ViewEntry struct {
 	UpdatedAt        time.Time `json:"updated_at"`
 }
 
-// ViewMetadata represents the complete view metadata according to Iceberg V2 spec
-type ViewMetadata struct {
-	ViewUUID         string                `json:"view-uuid"`
-	FormatVersion    int                   `json:"format-version"`
-	Location         string                `json:"location"`
-	Schemas          []ViewSchema          `json:"schemas"`
-	CurrentVersionID int                   `json:"current-version-id"`
-	Versions         []ViewVersion         `json:"versions"`
-	VersionLog       []ViewVersionLogEntry `json:"version-log"`
-	Properties       map[string]string     `json:"properties,omitempty"`
-}
-
-// ViewSchema represents a view schema
-type ViewSchema struct {
-	SchemaID int               `json:"schema-id"`
-	Type     string            `json:"type"`
-	Fields   []ViewSchemaField `json:"fields"`
-}
-
-// ViewSchemaField represents a field in a view schema
-type ViewSchemaField struct {
-	ID       int    `json:"id"`
-	Name     string `json:"name"`
-	Required bool   `json:"required"`
-	Type     string `json:"type"`
-	Doc      string `json:"doc,omitempty"`
-}
-
-// ViewVersion represents a version of a view
-type ViewVersion struct {
-	VersionID        int                  `json:"version-id"`
-	SchemaID         int                  `json:"schema-id"`
-	TimestampMs      int64                `json:"timestamp-ms"`
-	Summary          map[string]string    `json:"summary"`
-	Representations  []ViewRepresentation `json:"representations"`
-	DefaultCatalog   *string              `json:"default-catalog,omitempty"`
-	DefaultNamespace []string             `json:"default-namespace"`
-}
-
-// ViewRepresentation represents a view representation (SQL, etc.)
-type ViewRepresentation struct {
-	Type    string `json:"type"`
-	SQL     string `json:"sql"`
-	Dialect string `json:"dialect"`
-}
-
-// ViewVersionLogEntry represents an entry in the view version log
-type ViewVersionLogEntry struct {
-	TimestampMs int64 `json:"timestamp-ms"`
-	VersionID   int   `json:"version-id"`
-}
-
-// View represents a view interface for compatibility
-type View struct {
-	identifier table.Identifier
-	metadata   *ViewMetadata
-}
-
-// Identifier returns the view identifier
-func (v *View) Identifier() table.Identifier {
-	return v.identifier
-}
-
-// Metadata returns the view metadata
-func (v *View) Metadata() *ViewMetadata {
-	return v.metadata
-}
-
-// Schema returns the current schema of the view
-func (v *View) Schema() *ViewSchema {
-	for _, schema := range v.metadata.Schemas {
-		if schema.SchemaID == v.currentVersion().SchemaID {
-			return &schema
-		}
-	}
-	return nil
-}
-
-// CurrentVersion returns the current version of the view
-func (v *View) currentVersion() *ViewVersion {
-	for _, version := range v.metadata.Versions {
-		if version.VersionID == v.metadata.CurrentVersionID {
-			return &version
-		}
-	}
-	return nil
-}
-
-// SQL returns the SQL representation of the current version
-func (v *View) SQL() string {
-	version := v.currentVersion()
-	if version != nil {
-		for _, repr := range version.Representations {
-			if repr.Type == "sql" {
-				return repr.SQL
-			}
-		}
-	}
-	return ""
-}
-
-// Dialect returns the SQL dialect of the current version
-func (v *View) Dialect() string {
-	version := v.currentVersion()
-	if version != nil {
-		for _, repr := range version.Representations {
-			if repr.Type == "sql" {
-				return repr.Dialect
-			}
-		}
-	}
-	return ""
-}
+// View metadata types are shared with the other catalogs
+type (
+	ViewMetadata        = view.Metadata
+	ViewSchema          = view.Schema
+	ViewSchemaField     = view.SchemaField
+	ViewVersion         = view.Version
+	ViewRepresentation  = view.Representation
+	ViewVersionLogEntry = view.VersionLogEntry
+	View                = view.View
+)
 
 // ConcurrentModificationError represents a concurrent modification error
 type ConcurrentModificationError struct {
@@ -208,6 +121,8 @@ type Catalog struct {
 	uri       string
 	warehouse string
 	fileIO    icebergio.IO
+	storage   fs.FileSystem
+	s3Config  *config.S3Config
 	mutex     sync.RWMutex // For concurrent access protection
 	logger    *log.Logger
 	cache     *catalogCache   // Optional caching layer
@@ -406,6 +321,11 @@ func NewCatalog(cfg *config.Config) (*Catalog, error) {
 	uri := cfg.Catalog.JSON.URI
 	warehouse := cfg.Catalog.JSON.Warehouse
 
+	// With S3 storage, tables live in the bucket while catalog.json stays local
+	if warehouse == "" && cfg.Storage.S3 != nil {
+		warehouse = s3.Warehouse(cfg.Storage.S3)
+	}
+
 	// If warehouse is not provided, infer it from URI
 	if warehouse == "" && uri != "" {
 		warehouse = filepath.Dir(uri)
@@ -421,14 +341,33 @@ func NewCatalog(cfg *config.Config) (*Catalog, error) {
 	}
 
 	// Ensure warehouse directory exists with proper permissions
-	if warehouse != "" {
+	if warehouse != "" && !s3.IsRemoteLocation(warehouse) {
 		if err := os.MkdirAll(warehouse, 0755); err != nil {
 			return nil, fmt.Errorf("failed to create warehouse directory: %w", err)
 		}
 	}
 
 	// Create FileIO for metadata operations
-	fileIO := icebergio.LocalFS{}
+	var fileIO icebergio.IO = icebergio.LocalFS{}
+	if s3.IsRemoteLocation(warehouse) {
+		if cfg.Storage.S3 == nil {
+			return nil, &ValidationError{
+				Field:   "storage.s3",
+				Message: fmt.Sprintf("S3 storage configuration is required for warehouse %s", warehouse),
+			}
+		}
+
+		var err error
+		if fileIO, err = s3.NewFileIO(context.Background(), cfg.Storage.S3); err != nil {
+			return nil, err
+		}
+	}
+
+	// Metadata files outside the local filesystem are written through the warehouse's storage
+	storage, err := fs.Resolve(context.Background(), warehouse, cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	// Initialize logger
 	logger := log.New(os.Stdout, fmt.Sprintf("[JSON-Catalog-%s] ", cfg.Name), log.LstdFlags|log.Lshortfile)
@@ -438,6 +377,8 @@ func NewCatalog(cfg *config.Config) (*Catalog, error) {
 		uri:       uri,
 		warehouse: warehouse,
 		fileIO:    fileIO,
+		storage:   storage,
+		s3Config:  cfg.Storage.S3,
 		logger:    logger,
 		cache:     newCatalogCache(30 * time.Second), // 30 second cache TTL
 		metrics:   &CatalogMetrics{},                 // Initialize metrics
@@ -469,8 +410,8 @@ func validateJSONConfig(cfg *config.JSONConfig) error {
 		}
 	}
 
-	// Validate warehouse path if provided
-	if cfg.Warehouse != "" {
+	// Validate warehouse path if provided; object store URLs are also accepted
+	if cfg.Warehouse != "" && !s3.IsRemoteLocation(cfg.Warehouse) {
 		if !filepath.IsAbs(cfg.Warehouse) && !strings.HasPrefix(cfg.Warehouse, "./") && !strings.HasPrefix(cfg.Warehouse, "../") {
 			return &ValidationError{
 				Field:   "warehouse",
@@ -487,6 +428,11 @@ func (c *Catalog) Name() string {
 	return c.name
 }
 
+// GetS3Config returns the S3 storage configuration, or nil when tables are stored locally
+func (c *Catalog) GetS3Config() *config.S3Config {
+	return c.s3Config
+}
+
 // CatalogType returns the catalog type
 func (c *Catalog) CatalogType() catalog.Type {
 	return catalog.Hive // JSON catalogs are similar to Hive metastores
@@ -561,48 +507,8 @@ func (c *Catalog) CreateView(ctx context.Context, identifier table.Identifier, s
 
 	// Generate view metadata
 	now := time.Now()
-	viewUUID := generateUUID()
-	viewLocation := c.defaultViewLocation(identifier)
 	metadataLocation := c.newViewMetadataLocation(identifier, 1)
-
-	// Convert iceberg schema to view schema
-	viewSchema := c.convertIcebergSchemaToViewSchema(schema, 1)
-
-	// Create view metadata
-	viewMetadata := &ViewMetadata{
-		ViewUUID:         viewUUID,
-		FormatVersion:    1,
-		Location:         viewLocation,
-		Schemas:          []ViewSchema{viewSchema},
-		CurrentVersionID: 1,
-		Versions: []ViewVersion{
-			{
-				VersionID:   1,
-				SchemaID:    1,
-				TimestampMs: now.UnixMilli(),
-				Summary: map[string]string{
-					"engine-name":    "icebox",
-					"engine-version": "1.0.0",
-				},
-				Representations: []ViewRepresentation{
-					{
-						Type:    "sql",
-						SQL:     sql,
-						Dialect: dialect,
-					},
-				},
-				DefaultCatalog:   &c.name,
-				DefaultNamespace: namespace,
-			},
-		},
-		VersionLog: []ViewVersionLogEntry{
-			{
-				TimestampMs: now.UnixMilli(),
-				VersionID:   1,
-			},
-		},
-		Properties: properties,
-	}
+	viewMetadata := view.NewMetadata(c.defaultViewLocation(identifier), sql, dialect, schema, c.name, namespace, properties)
 
 	// Write view metadata file
 	if err := c.writeViewMetadata(viewMetadata, metadataLocation); err != nil {
@@ -619,6 +525,7 @@ func (c *Catalog) CreateView(ctx context.Context, identifier table.Identifier, s
 		Namespaces:  make(map[string]NamespaceEntry),
 		Tables:      make(map[string]TableEntry),
 		Views:       make(map[string]ViewEntry),
+		Grants:      data.Grants,
 	}
 
 	// Copy existing namespaces
@@ -653,10 +560,7 @@ func (c *Catalog) CreateView(ctx context.Context, identifier table.Identifier, s
 	c.metrics.IncrementViewsCreated()
 	c.logger.Printf("Created view: %s", namespaceToString(identifier))
 
-	return &View{
-		identifier: identifier,
-		metadata:   viewMetadata,
-	}, nil
+	return view.New(identifier, viewMetadata), nil
 }
 
 // LoadView loads a view from the catalog
@@ -686,10 +590,7 @@ func (c *Catalog) LoadView(ctx context.Context, identifier table.Identifier) (*V
 		return nil, fmt.Errorf("failed to load view metadata: %w", err)
 	}
 
-	return &View{
-		identifier: identifier,
-		metadata:   viewMetadata,
-	}, nil
+	return view.New(identifier, viewMetadata), nil
 }
 
 // DropView drops a view from the catalog
@@ -723,6 +624,7 @@ func (c *Catalog) DropView(ctx context.Context, identifier table.Identifier) err
 		Namespaces:  make(map[string]NamespaceEntry),
 		Tables:      make(map[string]TableEntry),
 		Views:       make(map[string]ViewEntry),
+		Grants:      data.Grants,
 	}
 
 	// Copy existing namespaces
@@ -854,6 +756,7 @@ func (c *Catalog) RenameView(ctx context.Context, from, to table.Identifier) (*V
 		Namespaces:  make(map[string]NamespaceEntry),
 		Tables:      make(map[string]TableEntry),
 		Views:       make(map[string]ViewEntry),
+		Grants:      data.Grants,
 	}
 
 	// Copy existing namespaces
@@ -1293,20 +1196,18 @@ func (c *Catalog) defaultViewLocation(identifier table.Identifier) string {
 	namespace := identifier[:len(identifier)-1]
 	viewName := identifier[len(identifier)-1]
 
-	namespacePath := strings.Join(namespace, string(filepath.Separator))
-	return filepath.Join(c.warehouse, namespacePath, viewName)
+	return joinLocation(c.warehouse, append(namespace, viewName)...)
 }
 
 // newViewMetadataLocation generates a new metadata file location for a view
 func (c *Catalog) newViewMetadataLocation(identifier table.Identifier, version int) string {
 	viewLocation := c.defaultViewLocation(identifier)
-	metadataDir := filepath.Join(viewLocation, "metadata")
 
 	// Generate UUID for the metadata file
 	uuid := generateUUID()
 	filename := fmt.Sprintf("%05d-%s.metadata.json", version, uuid)
 
-	return filepath.Join(metadataDir, filename)
+	return joinLocation(viewLocation, "metadata", filename)
 }
 
 // CreateNamespace creates a new namespace in the catalog
@@ -1354,6 +1255,7 @@ func (c *Catalog) CreateNamespace(ctx context.Context, namespace table.Identifie
 		Namespaces:  make(map[string]NamespaceEntry),
 		Tables:      make(map[string]TableEntry),
 		Views:       make(map[string]ViewEntry),
+		Grants:      data.Grants,
 	}
 
 	// Copy existing namespaces
@@ -1417,6 +1319,7 @@ func (c *Catalog) DropNamespace(ctx context.Context, namespace table.Identifier)
 		Namespaces:  make(map[string]NamespaceEntry),
 		Tables:      make(map[string]TableEntry),
 		Views:       make(map[string]ViewEntry),
+		Grants:      data.Grants,
 	}
 
 	// Copy existing namespaces except the one being deleted
@@ -1536,6 +1439,7 @@ func (c *Catalog) UpdateNamespaceProperties(ctx context.Context, namespace table
 		Namespaces:  make(map[string]NamespaceEntry),
 		Tables:      make(map[string]TableEntry),
 		Views:       make(map[string]ViewEntry),
+		Grants:      data.Grants,
 	}
 
 	// Copy existing namespaces
@@ -1799,6 +1703,7 @@ func (c *Catalog) CreateTable(ctx context.Context, identifier table.Identifier,
 		Namespaces:  make(map[string]NamespaceEntry),
 		Tables:      make(map[string]TableEntry),
 		Views:       make(map[string]ViewEntry),
+		Grants:      data.Grants,
 	}
 
 	// Copy existing namespaces
@@ -1886,6 +1791,7 @@ func (c *Catalog) DropTable(ctx context.Context, identifier table.Identifier) er
 		Namespaces:  make(map[string]NamespaceEntry),
 		Tables:      make(map[string]TableEntry),
 		Views:       make(map[string]ViewEntry),
+		Grants:      data.Grants,
 	}
 
 	// Copy existing namespaces
@@ -2072,8 +1978,8 @@ func (c *Catalog) CommitTable(ctx context.Context, tbl *table.Table, reqs []tabl
 		return currentMetadata, currentMetadataLocation, nil
 	}
 
-	// Stage the table updates (simplified - in production would apply actual updates)
-	stagedMetadataLocation, err := c.stageTableUpdates(identifier, currentMetadata, updates)
+	// Stage the table updates
+	stagedMetadataLocation, stagedMetadata, err := c.stageTableUpdates(identifier, currentMetadataLocation, currentMetadata, updates)
 	if err != nil {
 		c.metrics.IncrementOperationErrors()
 		return nil, "", fmt.Errorf("failed to stage table updates: %w", err)
@@ -2088,13 +1994,18 @@ func (c *Catalog) CommitTable(ctx context.Context, tbl *table.Table, reqs []tabl
 
 	if err := c.writeCatalogDataAtomic(data, etag); err != nil {
 		// Clean up staged metadata on failure
-		os.Remove(stagedMetadataLocation)
+		c.fileIO.Remove(stagedMetadataLocation)
 		c.metrics.IncrementOperationErrors()
 		return nil, "", fmt.Errorf("failed to update catalog: %w", err)
 	}
 
 	c.logger.Printf("Successfully committed table changes for %s", tableKey)
 
+	// Remove metadata files that fell out of the log when the table asks for it
+	if stagedMetadata.Properties().GetBool(table.MetadataDeleteAfterCommitEnabledKey, table.MetadataDeleteAfterCommitEnabledDefault) {
+		c.deleteRemovedMetadataFiles(currentMetadata, stagedMetadata)
+	}
+
 	// Load the updated table to get the new metadata
 	updatedTable, err := c.LoadTable(ctx, identifier, nil)
 	if err != nil {
@@ -2105,73 +2016,173 @@ func (c *Catalog) CommitTable(ctx context.Context, tbl *table.Table, reqs []tabl
 }
 
 // stageTableUpdates creates a new metadata version with the applied updates
-func (c *Catalog) stageTableUpdates(identifier table.Identifier, currentMetadata table.Metadata, updates []table.Update) (string, error) {
+func (c *Catalog) stageTableUpdates(identifier table.Identifier, currentMetadataLocation string, currentMetadata table.Metadata, updates []table.Update) (string, table.Metadata, error) {
 	// Generate new metadata location
-	newVersion, err := c.getNextMetadataVersion(identifier)
+	newVersion, err := c.getNextMetadataVersion(identifier, currentMetadataLocation)
 	if err != nil {
-		return "", fmt.Errorf("failed to get next metadata version: %w", err)
+		return "", nil, fmt.Errorf("failed to get next metadata version: %w", err)
 	}
 	newMetadataLocation := c.newMetadataLocation(identifier, newVersion)
 
 	c.logger.Printf("Staging %d updates for table %s (new version: %d)", len(updates), namespaceToString(identifier), newVersion)
 
-	// Read current metadata file
-	currentMetadataBytes, err := os.ReadFile(currentMetadata.Location())
+	metadataBuilder, err := table.MetadataBuilderFromBase(currentMetadata)
 	if err != nil {
-		return "", fmt.Errorf("failed to read current metadata: %w", err)
+		return "", nil, fmt.Errorf("failed to create metadata builder: %w", err)
 	}
 
-	// Parse current metadata
-	var metadata map[string]interface{}
-	if err := json.Unmarshal(currentMetadataBytes, &metadata); err != nil {
-		return "", fmt.Errorf("failed to parse current metadata: %w", err)
+	for _, update := range updates {
+		if err := update.Apply(metadataBuilder); err != nil {
+			return "", nil, fmt.Errorf("failed to apply update %s: %w", update.Action(), err)
+		}
 	}
 
-	// Apply updates to metadata using generic approach
-	if err := c.applyUpdatesToMetadata(metadata, updates); err != nil {
-		return "", fmt.Errorf("failed to apply updates to metadata: %w", err)
+	newMetadata, err := metadataBuilder.Build()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to build new metadata: %w", err)
 	}
 
-	// Update metadata with new version info
-	metadata["last-updated-ms"] = time.Now().UnixMilli()
+	// Update metadata log, bounded by write.metadata.previous-versions-max
+	maxPrevious := newMetadata.Properties().GetInt(table.MetadataPreviousVersionsMaxKey, table.MetadataPreviousVersionsMaxDefault)
+	metadataBuilder.AppendMetadataLog(table.MetadataLogEntry{
+		MetadataFile: currentMetadataLocation,
+		TimestampMs:  currentMetadata.LastUpdatedMillis(),
+	}).TrimMetadataLogs(max(maxPrevious, 0))
 
-	// Update metadata log
-	if metadataLog, ok := metadata["metadata-log"].([]interface{}); ok {
-		logEntry := map[string]interface{}{
-			"timestamp-ms":  time.Now().UnixMilli(),
-			"metadata-file": newMetadataLocation,
-		}
-		metadata["metadata-log"] = append(metadataLog, logEntry)
+	newMetadata, err = metadataBuilder.Build()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to build new metadata: %w", err)
 	}
 
 	// Write new metadata file atomically
-	if err := c.writeMetadataFile(newMetadataLocation, metadata); err != nil {
-		return "", fmt.Errorf("failed to write new metadata file: %w", err)
+	if err := c.writeMetadataFile(newMetadataLocation, newMetadata); err != nil {
+		return "", nil, fmt.Errorf("failed to write new metadata file: %w", err)
 	}
 
-	return newMetadataLocation, nil
+	return newMetadataLocation, newMetadata, nil
 }
 
-// applyUpdatesToMetadata applies table updates to the metadata structure using reflection and type analysis
-func (c *Catalog) applyUpdatesToMetadata(metadata map[string]interface{}, updates []table.Update) error {
-	for i, update := range updates {
-		updateType := fmt.Sprintf("%T", update)
-		c.logger.Printf("Applying update %d of type %s", i+1, updateType)
+// deleteRemovedMetadataFiles deletes metadata files that were tracked by the previous
+// metadata log but are no longer tracked after a commit
+func (c *Catalog) deleteRemovedMetadataFiles(base, updated table.Metadata) {
+	kept := make(map[string]struct{})
+	for entry := range updated.PreviousFiles() {
+		kept[entry.MetadataFile] = struct{}{}
+	}
 
-		// Generic handling based on update interface
-		// Since we don't know the exact types, we handle this generically
-		// by updating the timestamp and logging the change
+	for entry := range base.PreviousFiles() {
+		if _, ok := kept[entry.MetadataFile]; ok {
+			continue
+		}
+		if err := c.fileIO.Remove(entry.MetadataFile); err != nil && !os.IsNotExist(err) {
+			c.logger.Printf("Warning: failed to delete old metadata file %s: %v", entry.MetadataFile, err)
+		}
+	}
+}
+
+// ExpireMetadataFiles deletes vN.metadata.json files of a table that are older than the
+// retainLast most recent previous versions. The current metadata file is never deleted.
+// Expired files are first removed from the metadata log with a commit, so the log never
+// points at deleted files; the commit is retried when another commit gets in first. It
+// returns the locations of the deleted files, oldest first.
+func (c *Catalog) ExpireMetadataFiles(ctx context.Context, identifier table.Identifier, retainLast int) ([]string, error) {
+	var candidates map[int]string
+	for attempt := 1; ; attempt++ {
+		tbl, err := c.LoadTable(ctx, identifier, nil)
+		if err != nil {
+			return nil, err
+		}
 
-		// This is a production-ready approach that works with any iceberg-go version
-		// The actual update application would be handled by iceberg-go's internal mechanisms
-		c.logger.Printf("Applied generic update of type %s", updateType)
+		var logged []string
+		candidates, logged, err = c.expiredMetadataFiles(tbl, retainLast)
+		if err != nil {
+			return nil, err
+		}
+		if len(logged) == 0 {
+			break
+		}
+
+		update := metadatalog.NewRemoveUpdate(logged)
+		_, _, err = c.CommitTable(ctx, tbl, metadatalog.Requirements(tbl.Metadata()), []table.Update{update})
+		if err == nil {
+			break
+		}
+		var conflict *ConcurrentModificationError
+		if !errors.As(err, &conflict) || attempt == MaxRetryAttempts {
+			return nil, fmt.Errorf("failed to remove expired files from the metadata log: %w", err)
+		}
 	}
 
-	return nil
+	// Files already gone, such as those the commit removed when the table deletes
+	// metadata files after commits, count as expired
+	var deleted []string
+	for _, version := range slices.Sorted(maps.Keys(candidates)) {
+		location := candidates[version]
+		if err := c.fileIO.Remove(location); err != nil && !os.IsNotExist(err) {
+			return deleted, fmt.Errorf("failed to delete metadata file %s: %w", location, err)
+		}
+		deleted = append(deleted, location)
+	}
+
+	c.logger.Printf("Expired %d metadata files for table %s", len(deleted), c.tableKey(catalog.NamespaceFromIdent(identifier), catalog.TableNameFromIdent(identifier)))
+	return deleted, nil
+}
+
+// expiredMetadataFiles returns the metadata files of a table older than the retainLast
+// most recent previous versions by version, and those of them in the metadata log
+func (c *Catalog) expiredMetadataFiles(tbl *table.Table, retainLast int) (map[int]string, []string, error) {
+	currentVersion, ok := parseMetadataVersion(tbl.MetadataLocation())
+	if !ok {
+		return nil, nil, fmt.Errorf("cannot determine metadata version from %s", tbl.MetadataLocation())
+	}
+	cutoff := currentVersion - max(retainLast, 0)
+
+	// Files tracked by the metadata log, and older ones only found in the metadata directory
+	candidates := make(map[int]string)
+	var logged []string
+	for entry := range tbl.Metadata().PreviousFiles() {
+		if version, ok := parseMetadataVersion(entry.MetadataFile); ok && version < cutoff {
+			candidates[version] = entry.MetadataFile
+			logged = append(logged, entry.MetadataFile)
+		}
+	}
+
+	metadataDir := metadataDirectory(tbl.MetadataLocation())
+	storage, err := c.storageFor(metadataDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	entries, err := storage.List(metadataDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list metadata directory: %w", err)
+	}
+	for _, entry := range entries {
+		version, ok := parseMetadataVersion(entry.Name())
+		if !ok || entry.IsDir() || version >= cutoff {
+			continue
+		}
+		if _, seen := candidates[version]; !seen {
+			candidates[version] = joinLocation(metadataDir, entry.Name())
+		}
+	}
+
+	return candidates, logged, nil
 }
 
 // writeMetadataFile writes metadata to a file atomically
-func (c *Catalog) writeMetadataFile(metadataLocation string, metadata map[string]interface{}) error {
+func (c *Catalog) writeMetadataFile(metadataLocation string, metadata interface{}) error {
+	// Objects are replaced atomically, so object store writes need no temporary file
+	if s3.IsRemoteLocation(metadataLocation) {
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(metadata); err != nil {
+			return fmt.Errorf("failed to encode metadata JSON: %w", err)
+		}
+		return c.writeRemoteFile(metadataLocation, buf.Bytes())
+	}
+
 	// Ensure destination directory exists
 	if err := os.MkdirAll(filepath.Dir(metadataLocation), 0755); err != nil {
 		return fmt.Errorf("failed to create metadata directory: %w", err)
@@ -2236,6 +2247,10 @@ func (c *Catalog) validateRequirement(req table.Requirement, metadata table.Meta
 		return fmt.Errorf("invalid current schema ID %d, requirement validation failed for %s", currentSchemaID, requirementType)
 	}
 
+	if err := req.Validate(metadata); err != nil {
+		return err
+	}
+
 	// Requirement validation passed
 	c.logger.Printf("Requirement validation passed for type %s", requirementType)
 	return nil
@@ -2258,7 +2273,7 @@ func (c *Catalog) RegisterTable(ctx context.Context, identifier table.Identifier
 	}
 
 	// Validate that metadata file exists
-	if _, err := os.Stat(metadataLocation); os.IsNotExist(err) {
+	if !c.fileExists(metadataLocation) {
 		return nil, &ValidationError{
 			Field:   "metadata_location",
 			Message: fmt.Sprintf("metadata file does not exist at %s", metadataLocation),
@@ -2316,7 +2331,7 @@ func (c *Catalog) defaultTableLocation(identifier table.Identifier) string {
 	tableName := catalog.TableNameFromIdent(identifier)
 
 	parts := append(namespace, tableName)
-	return filepath.Join(c.warehouse, "data", filepath.Join(parts...))
+	return joinLocation(c.warehouse, append([]string{"data"}, parts...)...)
 }
 
 // newMetadataLocation creates a new metadata location for a table
@@ -2325,18 +2340,86 @@ func (c *Catalog) newMetadataLocation(identifier table.Identifier, version int)
 	tableName := catalog.TableNameFromIdent(identifier)
 
 	parts := append(namespace, tableName)
-	metadataDir := filepath.Join(c.warehouse, "metadata", filepath.Join(parts...))
 	filename := fmt.Sprintf("v%d.metadata.json", version)
-	return filepath.Join(metadataDir, filename)
+	return joinLocation(c.warehouse, append(append([]string{"metadata"}, parts...), filename)...)
 }
 
-// writeEnhancedMetadata writes table metadata to storage with enterprise-grade features
-func (c *Catalog) writeEnhancedMetadata(schema *iceberg.Schema, location, metadataLocation string) error {
-	// Ensure metadata directory exists
-	if err := os.MkdirAll(filepath.Dir(metadataLocation), 0755); err != nil {
-		return fmt.Errorf("failed to create metadata directory: %w", err)
+// metadataDirectory returns the directory holding a metadata file
+func metadataDirectory(location string) string {
+	if s3.IsRemoteLocation(location) {
+		return location[:strings.LastIndex(location, "/")]
 	}
+	return filepath.Dir(location)
+}
 
+// joinLocation joins path elements onto a base location, using "/" for object store URLs
+func joinLocation(base string, elem ...string) string {
+	if s3.IsRemoteLocation(base) {
+		return strings.TrimSuffix(base, "/") + "/" + path.Join(elem...)
+	}
+	return filepath.Join(append([]string{base}, elem...)...)
+}
+
+// storageFor returns the file system holding a location, which is the warehouse's
+// unless the location uses another scheme
+func (c *Catalog) storageFor(location string) (fs.FileSystem, error) {
+	if c.storage != nil && fs.Scheme(location) == fs.Scheme(c.warehouse) {
+		return c.storage, nil
+	}
+	return fs.Resolve(context.Background(), location, &config.Config{Storage: config.StorageConfig{S3: c.s3Config}})
+}
+
+// writeRemoteFile writes a file to an object store location through its file system
+func (c *Catalog) writeRemoteFile(location string, data []byte) error {
+	storage, err := c.storageFor(location)
+	if err != nil {
+		return err
+	}
+
+	file, err := storage.Create(location)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", location, err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", location, err)
+	}
+	// Object stores upload the file when it is closed
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", location, err)
+	}
+	return nil
+}
+
+// readRemoteFile reads a file from an object store location through its file system
+func (c *Catalog) readRemoteFile(location string) ([]byte, error) {
+	storage, err := c.storageFor(location)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := storage.Open(location)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
+
+// fileExists reports whether a local path or object store location exists
+func (c *Catalog) fileExists(location string) bool {
+	storage, err := c.storageFor(location)
+	if err != nil {
+		return false
+	}
+
+	_, err = storage.Stat(location)
+	return !errors.Is(err, iofs.ErrNotExist)
+}
+
+// writeEnhancedMetadata writes table metadata to storage with enterprise-grade features
+func (c *Catalog) writeEnhancedMetadata(schema *iceberg.Schema, location, metadataLocation string) error {
 	// Generate proper UUID
 	tableUUID := generateUUID()
 
@@ -2400,44 +2483,24 @@ func (c *Catalog) writeEnhancedMetadata(schema *iceberg.Schema, location, metada
 		"properties":            map[string]interface{}{},
 	}
 
-	// Write metadata atomically
-	tempFile := metadataLocation + ".tmp"
-	defer os.Remove(tempFile) // Clean up temp file
-
-	file, err := os.OpenFile(tempFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, CatalogFilePermissions)
-	if err != nil {
-		return fmt.Errorf("failed to create temporary metadata file: %w", err)
-	}
-
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	encoder.SetEscapeHTML(false)
-
-	if err := encoder.Encode(metadata); err != nil {
-		file.Close()
-		return fmt.Errorf("failed to encode metadata JSON: %w", err)
-	}
-
-	if err := file.Sync(); err != nil {
-		file.Close()
-		return fmt.Errorf("failed to sync metadata file: %w", err)
-	}
-
-	if err := file.Close(); err != nil {
-		return fmt.Errorf("failed to close metadata file: %w", err)
-	}
-
-	// Atomic rename
-	if err := os.Rename(tempFile, metadataLocation); err != nil {
-		return fmt.Errorf("failed to atomically write metadata file: %w", err)
+	if err := c.writeMetadataFile(metadataLocation, metadata); err != nil {
+		return err
 	}
 
 	c.logger.Printf("Created table metadata at %s", metadataLocation)
 	return nil
 }
 
-// getNextMetadataVersion determines the next version number by parsing existing metadata files
-func (c *Catalog) getNextMetadataVersion(identifier table.Identifier) (int, error) {
+// getNextMetadataVersion determines the next version number by parsing existing metadata files.
+// Object store warehouses are not listed; the version follows the current metadata file.
+func (c *Catalog) getNextMetadataVersion(identifier table.Identifier, currentMetadataLocation string) (int, error) {
+	if s3.IsRemoteLocation(c.warehouse) {
+		if version, ok := parseMetadataVersion(currentMetadataLocation); ok {
+			return version + 1, nil
+		}
+		return 1, nil
+	}
+
 	namespace := catalog.NamespaceFromIdent(identifier)
 	tableName := catalog.TableNameFromIdent(identifier)
 
@@ -2457,7 +2520,6 @@ func (c *Catalog) getNextMetadataVersion(identifier table.Identifier) (int, erro
 	}
 
 	maxVersion := 0
-	metadataFilePattern := regexp.MustCompile(`^v(\d+)\.metadata\.json$`)
 
 	for _, entry := range entries {
 		if entry.IsDir() {
@@ -2477,50 +2539,37 @@ func (c *Catalog) getNextMetadataVersion(identifier table.Identifier) (int, erro
 	return maxVersion + 1, nil
 }
 
-// convertIcebergSchemaToViewSchema converts an Iceberg schema to a view schema
-func (c *Catalog) convertIcebergSchemaToViewSchema(schema *iceberg.Schema, schemaID int) ViewSchema {
-	fields := make([]ViewSchemaField, 0, len(schema.Fields()))
-
-	for _, field := range schema.Fields() {
-		typeStr := ""
-		if typeInterface := convertIcebergTypeToMetadata(field.Type); typeInterface != nil {
-			if str, ok := typeInterface.(string); ok {
-				typeStr = str
-			}
-		}
-
-		viewField := ViewSchemaField{
-			ID:       field.ID,
-			Name:     field.Name,
-			Required: field.Required,
-			Type:     typeStr,
-		}
-		if field.Doc != "" {
-			viewField.Doc = field.Doc
-		}
-		fields = append(fields, viewField)
+// parseMetadataVersion extracts N from a .../vN.metadata.json location
+func parseMetadataVersion(location string) (int, bool) {
+	matches := metadataFilePattern.FindStringSubmatch(filepath.Base(location))
+	if len(matches) != 2 {
+		return 0, false
 	}
 
-	return ViewSchema{
-		SchemaID: schemaID,
-		Type:     "struct",
-		Fields:   fields,
+	version, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, false
 	}
+	return version, true
 }
 
 // writeViewMetadata writes view metadata to storage
 func (c *Catalog) writeViewMetadata(metadata *ViewMetadata, metadataLocation string) error {
-	// Ensure metadata directory exists
-	if err := os.MkdirAll(filepath.Dir(metadataLocation), 0755); err != nil {
-		return fmt.Errorf("failed to create metadata directory: %w", err)
-	}
-
 	// Marshal metadata to JSON
 	data, err := json.MarshalIndent(metadata, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal view metadata: %w", err)
 	}
 
+	if s3.IsRemoteLocation(metadataLocation) {
+		return c.writeRemoteFile(metadataLocation, data)
+	}
+
+	// Ensure metadata directory exists
+	if err := os.MkdirAll(filepath.Dir(metadataLocation), 0755); err != nil {
+		return fmt.Errorf("failed to create metadata directory: %w", err)
+	}
+
 	// Write to temporary file first for atomic operation
 	tempFile := metadataLocation + ".tmp"
 	if err := os.WriteFile(tempFile, data, 0644); err != nil {
@@ -2538,15 +2587,16 @@ func (c *Catalog) writeViewMetadata(metadata *ViewMetadata, metadataLocation str
 
 // loadViewMetadata loads view metadata from storage
 func (c *Catalog) loadViewMetadata(metadataLocation string) (*ViewMetadata, error) {
-	data, err := os.ReadFile(metadataLocation)
+	var data []byte
+	var err error
+	if s3.IsRemoteLocation(metadataLocation) {
+		data, err = c.readRemoteFile(metadataLocation)
+	} else {
+		data, err = os.ReadFile(metadataLocation)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to read view metadata file: %w", err)
 	}
 
-	var metadata ViewMetadata
-	if err := json.Unmarshal(data, &metadata); err != nil {
-		return nil, fmt.Errorf("failed to parse view metadata: %w", err)
-	}
-
-	return &metadata, nil
+	return view.ParseMetadata(data)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"sort"
@@ -13,10 +14,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/TFMV/icebox/catalog/grant"
 	"github.com/TFMV/icebox/config"
 	"github.com/apache/iceberg-go"
 	icebergcatalog "github.com/apache/iceberg-go/catalog"
 	"github.com/apache/iceberg-go/table"
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -842,6 +846,9 @@ func TestConfigurationValidation(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			// Relative paths resolve against the working directory, which must not be the source tree
+			t.Chdir(t.TempDir())
+
 			_, err := NewCatalog(tt.config)
 			if tt.expectError {
 				assert.Error(t, err)
@@ -2223,3 +2230,189 @@ func TestViewErrorHandling(t *testing.T) {
 	_, err = catalog.RenameView(ctx, table.Identifier{}, table.Identifier{"test_namespace", "new_name"})
 	assert.Error(t, err)
 }
+
+func TestCommitTableAppliesUpdates(t *testing.T) {
+	catalog, _ := createTestCatalog(t)
+	ctx := context.Background()
+
+	namespace := table.Identifier{"test_namespace"}
+	tableIdent := table.Identifier{"test_namespace", "test_table"}
+	require.NoError(t, catalog.CreateNamespace(ctx, namespace, iceberg.Properties{}))
+
+	schema := iceberg.NewSchema(1, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true})
+	tbl, err := catalog.CreateTable(ctx, tableIdent, schema)
+	require.NoError(t, err)
+	initialLocation := tbl.MetadataLocation()
+
+	updates := []table.Update{table.NewSetPropertiesUpdate(iceberg.Properties{"owner": "analytics"})}
+	metadata, location, err := catalog.CommitTable(ctx, tbl, nil, updates)
+	require.NoError(t, err)
+	assert.NotEqual(t, initialLocation, location)
+	assert.Equal(t, "analytics", metadata.Properties()["owner"])
+
+	// The committed metadata must be visible on reload, with the replaced file logged
+	reloaded, err := catalog.LoadTable(ctx, tableIdent, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "analytics", reloaded.Properties()["owner"])
+
+	var logged []string
+	for entry := range reloaded.Metadata().PreviousFiles() {
+		logged = append(logged, entry.MetadataFile)
+	}
+	assert.Equal(t, []string{initialLocation}, logged)
+}
+
+func TestExpireMetadataFiles(t *testing.T) {
+	catalog, _ := createTestCatalog(t)
+	ctx := context.Background()
+
+	namespace := table.Identifier{"test_namespace"}
+	tableIdent := table.Identifier{"test_namespace", "test_table"}
+	require.NoError(t, catalog.CreateNamespace(ctx, namespace, iceberg.Properties{}))
+
+	schema := iceberg.NewSchema(1, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true})
+	_, err := catalog.CreateTable(ctx, tableIdent, schema)
+	require.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		tbl, err := catalog.LoadTable(ctx, tableIdent, nil)
+		require.NoError(t, err)
+		updates := []table.Update{table.NewSetPropertiesUpdate(iceberg.Properties{"commit": fmt.Sprintf("%d", i)})}
+		_, _, err = catalog.CommitTable(ctx, tbl, nil, updates)
+		require.NoError(t, err)
+	}
+
+	tbl, err := catalog.LoadTable(ctx, tableIdent, nil)
+	require.NoError(t, err)
+
+	metadataDir := filepath.Dir(tbl.MetadataLocation())
+	deleted, err := catalog.ExpireMetadataFiles(ctx, tableIdent, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(metadataDir, "v1.metadata.json"), filepath.Join(metadataDir, "v2.metadata.json")}, deleted)
+
+	// Removing the expired files from the metadata log is committed as v6
+	entries, err := os.ReadDir(metadataDir)
+	require.NoError(t, err)
+	var remaining []string
+	for _, entry := range entries {
+		remaining = append(remaining, entry.Name())
+	}
+	sort.Strings(remaining)
+	assert.Equal(t, []string{"v3.metadata.json", "v4.metadata.json", "v5.metadata.json", "v6.metadata.json"}, remaining)
+
+	reloaded, err := catalog.LoadTable(ctx, tableIdent, nil)
+	require.NoError(t, err)
+	var logged []string
+	for entry := range reloaded.Metadata().PreviousFiles() {
+		logged = append(logged, filepath.Base(entry.MetadataFile))
+	}
+	assert.Equal(t, []string{"v3.metadata.json", "v4.metadata.json", "v5.metadata.json"}, logged)
+}
+
+func TestS3Storage(t *testing.T) {
+	backend := s3mem.New()
+	require.NoError(t, backend.CreateBucket("warehouse"))
+	server := httptest.NewServer(gofakes3.New(backend).Server())
+	t.Cleanup(server.Close)
+
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		Name: "test-catalog",
+		Catalog: config.CatalogConfig{
+			Type: "json",
+			JSON: &config.JSONConfig{URI: filepath.Join(tempDir, "catalog.json")},
+		},
+		Storage: config.StorageConfig{
+			Type: "s3",
+			S3: &config.S3Config{
+				Bucket:          "warehouse",
+				Endpoint:        server.URL,
+				AccessKeyID:     "test",
+				SecretAccessKey: "test",
+			},
+		},
+	}
+
+	catalog, err := NewCatalog(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "s3://warehouse", catalog.warehouse)
+	assert.Equal(t, cfg.Storage.S3, catalog.GetS3Config())
+
+	ctx := context.Background()
+	require.NoError(t, catalog.CreateNamespace(ctx, table.Identifier{"analytics"}, nil))
+
+	tableIdent := table.Identifier{"analytics", "events"}
+	schema := iceberg.NewSchema(1, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true})
+	tbl, err := catalog.CreateTable(ctx, tableIdent, schema)
+	require.NoError(t, err)
+	assert.Equal(t, "s3://warehouse/metadata/analytics/events/v1.metadata.json", tbl.MetadataLocation())
+	assert.Equal(t, "s3://warehouse/data/analytics/events", tbl.Location())
+
+	// Commits continue from the current metadata file since the bucket is not listed
+	_, location, err := catalog.CommitTable(ctx, tbl, nil, []table.Update{
+		table.NewSetPropertiesUpdate(iceberg.Properties{"owner": "analytics"}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "s3://warehouse/metadata/analytics/events/v2.metadata.json", location)
+
+	tbl, err = catalog.LoadTable(ctx, tableIdent, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "analytics", tbl.Properties()["owner"])
+
+	// Views are stored in the bucket too
+	_, err = catalog.CreateView(ctx, table.Identifier{"analytics", "recent"}, "SELECT * FROM events", "duckdb", schema, nil)
+	require.NoError(t, err)
+	view, err := catalog.LoadView(ctx, table.Identifier{"analytics", "recent"})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM events", view.SQL())
+
+	objects, err := backend.ListBucket("warehouse", &gofakes3.Prefix{}, gofakes3.ListBucketPage{})
+	require.NoError(t, err)
+	var keys []string
+	for _, object := range objects.Contents {
+		keys = append(keys, object.Key)
+	}
+	assert.Contains(t, keys, "metadata/analytics/events/v1.metadata.json")
+	assert.Contains(t, keys, "metadata/analytics/events/v2.metadata.json")
+	assert.Len(t, keys, 3)
+
+	// Nothing is written to the local filesystem apart from the catalog file
+	entries, err := os.ReadDir(tempDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	// Old metadata files are found by listing the bucket
+	deleted, err := catalog.ExpireMetadataFiles(ctx, tableIdent, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s3://warehouse/metadata/analytics/events/v1.metadata.json"}, deleted)
+	_, err = backend.HeadObject("warehouse", "metadata/analytics/events/v1.metadata.json")
+	assert.Error(t, err)
+}
+
+func TestGrants(t *testing.T) {
+	catalog, _ := createTestCatalog(t)
+	ctx := context.Background()
+
+	analysts := grant.Grant{Grantee: grant.Role("analyst"), Namespace: "sales", Privilege: grant.Read}
+	require.NoError(t, catalog.SetGrant(ctx, analysts))
+	require.NoError(t, catalog.SetGrant(ctx, grant.Grant{Grantee: grant.User("loader"), Namespace: "sales", Table: "orders", Privilege: grant.Write}))
+
+	analysts.Privilege = grant.Admin
+	require.NoError(t, catalog.SetGrant(ctx, analysts))
+	assert.Error(t, catalog.SetGrant(ctx, grant.Grant{Grantee: grant.Role("analyst"), Namespace: "*", Table: "orders", Privilege: grant.Read}))
+
+	// Other catalog changes keep the grants
+	require.NoError(t, catalog.CreateNamespace(ctx, table.Identifier{"sales"}, iceberg.Properties{}))
+
+	grants, err := catalog.ListGrants(ctx)
+	require.NoError(t, err)
+	require.Len(t, grants, 2)
+	assert.Equal(t, analysts, grants[0])
+
+	require.NoError(t, catalog.RevokeGrant(ctx, grant.User("loader"), "sales", "orders"))
+	assert.ErrorIs(t, catalog.RevokeGrant(ctx, grant.User("loader"), "sales", "orders"), grant.ErrNoSuchGrant)
+
+	grants, err = catalog.ListGrants(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []grant.Grant{analysts}, grants)
+}
@@ -0,0 +1,76 @@
+package json
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TFMV/icebox/catalog/grant"
+)
+
+// ListGrants returns the access grants stored in the catalog
+func (c *Catalog) ListGrants(ctx context.Context) ([]grant.Grant, error) {
+	data, _, err := c.readCatalogData()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog: %w", err)
+	}
+	return append([]grant.Grant(nil), data.Grants...), nil
+}
+
+// SetGrant gives a grantee a privilege on a resource, replacing the privilege it held
+// there before
+func (c *Catalog) SetGrant(ctx context.Context, g grant.Grant) error {
+	if err := g.Validate(); err != nil {
+		return err
+	}
+
+	return c.updateGrants(func(grants []grant.Grant) ([]grant.Grant, error) {
+		for i, existing := range grants {
+			if sameResource(existing, g) {
+				grants[i] = g
+				return grants, nil
+			}
+		}
+		return append(grants, g), nil
+	})
+}
+
+// RevokeGrant removes a grantee's privilege on a resource
+func (c *Catalog) RevokeGrant(ctx context.Context, grantee, namespace, tableName string) error {
+	revoked := grant.Grant{Grantee: grantee, Namespace: namespace, Table: tableName}
+	return c.updateGrants(func(grants []grant.Grant) ([]grant.Grant, error) {
+		for i, existing := range grants {
+			if sameResource(existing, revoked) {
+				return append(grants[:i], grants[i+1:]...), nil
+			}
+		}
+		return nil, grant.ErrNoSuchGrant
+	})
+}
+
+// updateGrants writes the catalog with the grants returned by update, which receives a
+// copy of the current ones
+func (c *Catalog) updateGrants(update func([]grant.Grant) ([]grant.Grant, error)) error {
+	data, etag, err := c.readCatalogData()
+	if err != nil {
+		c.metrics.IncrementOperationErrors()
+		return fmt.Errorf("failed to read catalog: %w", err)
+	}
+
+	grants, err := update(append([]grant.Grant(nil), data.Grants...))
+	if err != nil {
+		return err
+	}
+
+	// The other sections are not modified, so they are shared with the cached data
+	dataCopy := *data
+	dataCopy.Grants = grants
+	if err := c.writeCatalogDataAtomic(&dataCopy, etag); err != nil {
+		c.metrics.IncrementOperationErrors()
+		return err
+	}
+	return nil
+}
+
+func sameResource(a, b grant.Grant) bool {
+	return a.Grantee == b.Grantee && a.Namespace == b.Namespace && a.Table == b.Table
+}
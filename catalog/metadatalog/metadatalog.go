@@ -0,0 +1,56 @@
+// Package metadatalog edits the metadata log of Iceberg tables, the list of previous
+// metadata files that each metadata file records, for the catalog implementations
+package metadatalog
+
+import (
+	"fmt"
+
+	"github.com/apache/iceberg-go/table"
+)
+
+// removeUpdate rebuilds the metadata log without the entries of removed files
+type removeUpdate struct {
+	removed map[string]struct{}
+}
+
+// NewRemoveUpdate returns an update that removes metadata files from the metadata log.
+// The entries that are kept are taken from the metadata the update is committed
+// against, so entries logged by commits since the files were chosen are kept too.
+func NewRemoveUpdate(files []string) table.Update {
+	removed := make(map[string]struct{}, len(files))
+	for _, file := range files {
+		removed[file] = struct{}{}
+	}
+	return &removeUpdate{removed: removed}
+}
+
+func (u *removeUpdate) Action() string {
+	return "remove-metadata-log-entries"
+}
+
+func (u *removeUpdate) Apply(b *table.MetadataBuilder) error {
+	// The builder only exposes its log through the metadata it builds, which shares the
+	// log with it, so the kept entries are copied before the log is trimmed
+	current, err := b.Build()
+	if err != nil {
+		return fmt.Errorf("failed to read the metadata log: %w", err)
+	}
+	var kept []table.MetadataLogEntry
+	for entry := range current.PreviousFiles() {
+		if _, ok := u.removed[entry.MetadataFile]; !ok {
+			kept = append(kept, entry)
+		}
+	}
+
+	b.TrimMetadataLogs(0)
+	for _, entry := range kept {
+		b.AppendMetadataLog(entry)
+	}
+	return nil
+}
+
+// Requirements returns the requirements an update from NewRemoveUpdate is committed
+// with, so that it is never applied to another table that took the place of base
+func Requirements(base table.Metadata) []table.Requirement {
+	return []table.Requirement{table.AssertTableUUID(base.TableUUID())}
+}
@@ -0,0 +1,57 @@
+package metadatalog
+
+import (
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRemoveUpdate(t *testing.T) {
+	schema := iceberg.NewSchema(1, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true})
+	metadata, err := table.NewMetadata(schema, iceberg.UnpartitionedSpec, table.UnsortedSortOrder, "/warehouse/t", nil)
+	require.NoError(t, err)
+
+	builder, err := table.MetadataBuilderFromBase(metadata)
+	require.NoError(t, err)
+	for _, file := range []string{"v1.metadata.json", "v2.metadata.json", "v3.metadata.json"} {
+		builder.AppendMetadataLog(table.MetadataLogEntry{MetadataFile: file})
+	}
+	base, err := builder.Build()
+	require.NoError(t, err)
+
+	// The kept entries are those of the metadata the update is applied to, which may
+	// have logged more files since the removed ones were chosen
+	update := NewRemoveUpdate([]string{"v1.metadata.json", "v3.metadata.json"})
+	builder, err = table.MetadataBuilderFromBase(base)
+	require.NoError(t, err)
+	builder.AppendMetadataLog(table.MetadataLogEntry{MetadataFile: "v4.metadata.json"})
+	require.NoError(t, update.Apply(builder))
+	updated, err := builder.Build()
+	require.NoError(t, err)
+
+	var logged []string
+	for entry := range updated.PreviousFiles() {
+		logged = append(logged, entry.MetadataFile)
+	}
+	assert.Equal(t, []string{"v2.metadata.json", "v4.metadata.json"}, logged)
+}
+
+func TestRequirements(t *testing.T) {
+	schema := iceberg.NewSchema(1, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true})
+	base, err := table.NewMetadata(schema, iceberg.UnpartitionedSpec, table.UnsortedSortOrder, "/warehouse/t", nil)
+	require.NoError(t, err)
+
+	for _, req := range Requirements(base) {
+		assert.NoError(t, req.Validate(base))
+	}
+
+	// Another table created in its place fails the requirements
+	other, err := table.NewMetadata(schema, iceberg.UnpartitionedSpec, table.UnsortedSortOrder, "/warehouse/t", nil)
+	require.NoError(t, err)
+	for _, req := range Requirements(base) {
+		assert.Error(t, req.Validate(other))
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/url"
 
 	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/fs/s3"
 	"github.com/apache/iceberg-go"
 	icebergcatalog "github.com/apache/iceberg-go/catalog"
 	icebergrest "github.com/apache/iceberg-go/catalog/rest"
@@ -86,21 +87,26 @@ func NewCatalog(cfg *config.Config) (*Catalog, error) {
 		opts = append(opts, icebergrest.WithPrefix(restConfig.Prefix))
 	}
 
-	// Add additional properties if present
-	if len(restConfig.AdditionalProps) > 0 {
-		props := make(iceberg.Properties)
-		for k, v := range restConfig.AdditionalProps {
-			props[k] = v
-		}
-		opts = append(opts, icebergrest.WithAdditionalProps(props))
+	// Additional properties, credentials and S3 settings share one property map since
+	// each WithAdditionalProps option replaces the previous one
+	props := make(iceberg.Properties)
+	for k, v := range restConfig.AdditionalProps {
+		props[k] = v
+	}
+	for k, v := range restConfig.Credentials {
+		props[k] = v
 	}
 
-	// Add credentials as additional properties
-	if len(restConfig.Credentials) > 0 {
-		props := make(iceberg.Properties)
-		for k, v := range restConfig.Credentials {
-			props[k] = v
+	// S3 settings let tables loaded from the catalog read and write the bucket
+	if cfg.Storage.S3 != nil {
+		for k, v := range s3.Properties(cfg.Storage.S3) {
+			if _, ok := props[k]; !ok {
+				props[k] = v
+			}
 		}
+	}
+
+	if len(props) > 0 {
 		opts = append(opts, icebergrest.WithAdditionalProps(props))
 	}
 
@@ -116,6 +122,11 @@ func NewCatalog(cfg *config.Config) (*Catalog, error) {
 	switch cfg.Storage.Type {
 	case "fs":
 		fileIO = io.LocalFS{}
+	case "s3", "minio":
+		fileIO, err = s3.NewFileIO(ctx, cfg.Storage.S3)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		// Use the default FileIO provided by iceberg-go
 		fileIO = io.LocalFS{}
@@ -129,6 +140,17 @@ func NewCatalog(cfg *config.Config) (*Catalog, error) {
 	}, nil
 }
 
+// GetS3Config returns the S3 storage configuration, or nil when tables are stored locally
+func (c *Catalog) GetS3Config() *config.S3Config {
+	return c.config.Storage.S3
+}
+
+// GetRESTConfig returns the REST catalog configuration, which the SQL engine uses to
+// attach the catalog
+func (c *Catalog) GetRESTConfig() *config.RESTConfig {
+	return c.config.Catalog.REST
+}
+
 // CatalogType returns the catalog type
 func (c *Catalog) CatalogType() icebergcatalog.Type {
 	return icebergcatalog.REST
@@ -4,17 +4,22 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"iter"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
+	"github.com/TFMV/icebox/catalog/metadatalog"
 	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/fs"
 	"github.com/TFMV/icebox/fs/local"
+	"github.com/TFMV/icebox/fs/s3"
 	"github.com/apache/iceberg-go"
 	"github.com/apache/iceberg-go/catalog"
 	icebergio "github.com/apache/iceberg-go/io"
@@ -22,19 +27,23 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 )
 
-// FileSystemInterface abstracts file operations for different storage backends
-type FileSystemInterface interface {
-	Create(path string) (io.WriteCloser, error)
-}
+// ErrCommitConflict is returned by CommitTable when the table was committed to since the
+// table being committed against was loaded
+var ErrCommitConflict = errors.New("table has been updated by another commit")
+
+// maxCommitAttempts is how often ExpireMetadataFiles tries to commit before giving up
+// on concurrent commits
+const maxCommitAttempts = 3
 
 // Catalog implements the iceberg-go catalog.Catalog interface using SQLite
 type Catalog struct {
 	name       string
 	dbPath     string
 	db         *sql.DB
-	fileSystem FileSystemInterface
+	fileSystem fs.FileSystem
 	fileIO     icebergio.IO
 	warehouse  string
+	s3Config   *config.S3Config
 }
 
 // NewCatalog creates a new SQLite-based catalog
@@ -57,21 +66,48 @@ func NewCatalog(cfg *config.Config) (*Catalog, error) {
 
 	// Determine warehouse location and create FileIO
 	warehouse := ""
-	var fileSystem FileSystemInterface
 	var fileIO icebergio.IO
 
-	if cfg.Storage.FileSystem != nil {
+	switch {
+	case cfg.Storage.S3 != nil:
+		// Metadata and data files live in the bucket and are written through the S3 FileIO
+		warehouse = s3.Warehouse(cfg.Storage.S3)
+		fileIO, err = s3.NewFileIO(context.Background(), cfg.Storage.S3)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+	case cfg.Storage.FileSystem != nil:
 		warehouse = cfg.Storage.FileSystem.RootPath
-		fileSystem = local.NewFileSystem(warehouse)
 		// Create a local FileIO implementation
 		fileIO = icebergio.LocalFS{}
 	}
 
-	return NewCatalogWithIO(cfg.Name, dbPath, db, fileSystem, fileIO, warehouse)
+	// Metadata files are written through the file system of the warehouse location
+	var fileSystem fs.FileSystem
+	if warehouse != "" {
+		fileSystem, err = fs.Resolve(context.Background(), warehouse, cfg)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	cat, err := NewCatalogWithIO(cfg.Name, dbPath, db, fileSystem, fileIO, warehouse)
+	if err != nil {
+		return nil, err
+	}
+	cat.s3Config = cfg.Storage.S3
+	return cat, nil
+}
+
+// GetS3Config returns the S3 storage configuration, or nil when tables are stored locally
+func (c *Catalog) GetS3Config() *config.S3Config {
+	return c.s3Config
 }
 
 // NewCatalogWithIO creates a new SQLite-based catalog with custom file IO
-func NewCatalogWithIO(name, dbPath string, db *sql.DB, fileSystem FileSystemInterface, fileIO icebergio.IO, warehouse string) (*Catalog, error) {
+func NewCatalogWithIO(name, dbPath string, db *sql.DB, fileSystem fs.FileSystem, fileIO icebergio.IO, warehouse string) (*Catalog, error) {
 	cat := &Catalog{
 		name:       name,
 		dbPath:     dbPath,
@@ -138,6 +174,36 @@ func (c *Catalog) initializeDatabase() error {
 		return fmt.Errorf("failed to create iceberg_namespace_properties table: %w", err)
 	}
 
+	// Create iceberg_views table
+	createViewsSQL := `
+	CREATE TABLE IF NOT EXISTS iceberg_views (
+		catalog_name TEXT NOT NULL,
+		view_namespace TEXT NOT NULL,
+		view_name TEXT NOT NULL,
+		metadata_location TEXT,
+		previous_metadata_location TEXT,
+		PRIMARY KEY (catalog_name, view_namespace, view_name)
+	)`
+
+	if _, err := c.db.Exec(createViewsSQL); err != nil {
+		return fmt.Errorf("failed to create iceberg_views table: %w", err)
+	}
+
+	// Create iceberg_grants table
+	createGrantsSQL := `
+	CREATE TABLE IF NOT EXISTS iceberg_grants (
+		catalog_name TEXT NOT NULL,
+		grantee TEXT NOT NULL,
+		namespace TEXT NOT NULL,
+		table_name TEXT NOT NULL DEFAULT '',
+		privilege TEXT NOT NULL,
+		PRIMARY KEY (catalog_name, grantee, namespace, table_name)
+	)`
+
+	if _, err := c.db.Exec(createGrantsSQL); err != nil {
+		return fmt.Errorf("failed to create iceberg_grants table: %w", err)
+	}
+
 	return nil
 }
 
@@ -168,21 +234,30 @@ func (c *Catalog) CreateTable(ctx context.Context, identifier table.Identifier,
 		return nil, catalog.ErrTableAlreadyExists
 	}
 
-	// Parse options using a simple configuration structure
-	location := c.defaultTableLocation(identifier)
-	properties := make(iceberg.Properties)
+	// Tables and views share a name space
+	viewExists, err := c.ViewExists(ctx, identifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check view existence: %w", err)
+	}
+	if viewExists {
+		return nil, catalog.ErrViewAlreadyExists
+	}
 
+	// Apply the location, partition spec, sort order and properties options
+	cfg := catalog.CreateTableCfg{SortOrder: table.UnsortedSortOrder}
 	for _, opt := range opts {
-		// Apply options - this is a simplified approach
-		_ = opt // For now, acknowledge the option
+		if opt != nil {
+			opt(&cfg)
+		}
 	}
+	if cfg.Location == "" {
+		cfg.Location = c.defaultTableLocation(identifier)
+	}
+	properties := make(iceberg.Properties)
 
-	// Create basic table metadata (simplified for demonstration)
-	// TODO: use the actual iceberg-go APIs
 	metadataLocation := c.newMetadataLocation(identifier, 1)
 
-	// Write metadata to storage (simplified)
-	if err := c.writeMetadata(schema, location, metadataLocation); err != nil {
+	if err := c.writeMetadata(schema, cfg, metadataLocation); err != nil {
 		return nil, fmt.Errorf("failed to write table metadata: %w", err)
 	}
 
@@ -219,6 +294,12 @@ func (c *Catalog) CommitTable(ctx context.Context, tbl *table.Table, reqs []tabl
 		return nil, "", fmt.Errorf("failed to query current metadata: %w", err)
 	}
 
+	// Commits are only applied to the current metadata, so a table loaded before another
+	// commit must be reloaded
+	if currentMetadataLocation.String != tbl.MetadataLocation() {
+		return nil, "", fmt.Errorf("%w: %s", ErrCommitConflict, strings.Join(identifier, "."))
+	}
+
 	// Validate requirements against current metadata
 	currentMetadata := tbl.Metadata()
 	for _, req := range reqs {
@@ -246,6 +327,21 @@ func (c *Catalog) CommitTable(ctx context.Context, tbl *table.Table, reqs []tabl
 		return nil, "", fmt.Errorf("failed to build new metadata: %w", err)
 	}
 
+	// Record the replaced metadata file in the metadata log, bounded by
+	// write.metadata.previous-versions-max (read from the updated properties)
+	if currentMetadataLocation.String != "" {
+		maxPrevious := newMetadata.Properties().GetInt(table.MetadataPreviousVersionsMaxKey, table.MetadataPreviousVersionsMaxDefault)
+		metadataBuilder.AppendMetadataLog(table.MetadataLogEntry{
+			MetadataFile: currentMetadataLocation.String,
+			TimestampMs:  currentMetadata.LastUpdatedMillis(),
+		}).TrimMetadataLogs(max(maxPrevious, 0))
+
+		newMetadata, err = metadataBuilder.Build()
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to build new metadata: %w", err)
+		}
+	}
+
 	// Determine the new metadata version and location
 	newVersion := c.getNextMetadataVersion(currentMetadataLocation.String)
 	newMetadataLocation := c.newMetadataLocation(identifier, newVersion)
@@ -255,16 +351,131 @@ func (c *Catalog) CommitTable(ctx context.Context, tbl *table.Table, reqs []tabl
 		return nil, "", fmt.Errorf("failed to write metadata file: %w", err)
 	}
 
-	// Update database with the new metadata location
-	updateSQL := `UPDATE iceberg_tables SET metadata_location = ?, previous_metadata_location = ? WHERE catalog_name = ? AND table_namespace = ? AND table_name = ?`
-	_, err = c.db.ExecContext(ctx, updateSQL, newMetadataLocation, currentMetadataLocation.String, c.name, namespaceStr, tableName)
+	// Update database with the new metadata location, unless another commit replaced
+	// the current one in the meantime
+	updateSQL := `UPDATE iceberg_tables SET metadata_location = ?, previous_metadata_location = ? WHERE catalog_name = ? AND table_namespace = ? AND table_name = ? AND metadata_location IS ?`
+	res, err := c.db.ExecContext(ctx, updateSQL, newMetadataLocation, currentMetadataLocation.String, c.name, namespaceStr, tableName, currentMetadataLocation)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to update table metadata location: %w", err)
 	}
+	if updated, err := res.RowsAffected(); err == nil && updated == 0 {
+		if c.fileIO != nil {
+			c.fileIO.Remove(strings.TrimPrefix(newMetadataLocation, "file://"))
+		}
+		return nil, "", fmt.Errorf("%w: %s", ErrCommitConflict, strings.Join(identifier, "."))
+	}
+
+	// Remove metadata files that fell out of the log when the table asks for it
+	if newMetadata.Properties().GetBool(table.MetadataDeleteAfterCommitEnabledKey, table.MetadataDeleteAfterCommitEnabledDefault) {
+		c.deleteRemovedMetadataFiles(currentMetadata, newMetadata)
+	}
 
 	return newMetadata, newMetadataLocation, nil
 }
 
+// deleteRemovedMetadataFiles deletes metadata files that were tracked by the previous
+// metadata log but are no longer tracked after a commit. Failures are logged, not returned,
+// since the commit itself has already succeeded.
+func (c *Catalog) deleteRemovedMetadataFiles(base, updated table.Metadata) {
+	if c.fileIO == nil || base == nil {
+		return
+	}
+
+	kept := make(map[string]struct{})
+	for entry := range updated.PreviousFiles() {
+		kept[entry.MetadataFile] = struct{}{}
+	}
+
+	for entry := range base.PreviousFiles() {
+		if _, ok := kept[entry.MetadataFile]; ok {
+			continue
+		}
+		if err := c.fileIO.Remove(strings.TrimPrefix(entry.MetadataFile, "file://")); err != nil && !os.IsNotExist(err) {
+			log.Printf("Warning: failed to delete old metadata file %s: %v", entry.MetadataFile, err)
+		}
+	}
+}
+
+// ExpireMetadataFiles deletes vN.metadata.json files of a table that are older than the
+// retainLast most recent previous versions. The current metadata file is never deleted.
+// Expired files are first removed from the metadata log with a commit, so the log never
+// points at deleted files; the commit is retried when another commit gets in first. It
+// returns the locations of the deleted files, oldest first.
+func (c *Catalog) ExpireMetadataFiles(ctx context.Context, identifier table.Identifier, retainLast int) ([]string, error) {
+	if c.fileIO == nil {
+		return nil, fmt.Errorf("catalog has no file IO configured")
+	}
+
+	var candidates map[int]string
+	for attempt := 1; ; attempt++ {
+		tbl, err := c.LoadTable(ctx, identifier, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var logged []string
+		candidates, logged = c.expiredMetadataFiles(tbl, retainLast)
+		if len(logged) == 0 {
+			break
+		}
+
+		update := metadatalog.NewRemoveUpdate(logged)
+		_, _, err = c.CommitTable(ctx, tbl, metadatalog.Requirements(tbl.Metadata()), []table.Update{update})
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, ErrCommitConflict) || attempt == maxCommitAttempts {
+			return nil, fmt.Errorf("failed to remove expired files from the metadata log: %w", err)
+		}
+	}
+
+	// Files already gone, such as those the commit removed when the table deletes
+	// metadata files after commits, count as expired
+	var deleted []string
+	for _, version := range slices.Sorted(maps.Keys(candidates)) {
+		location := candidates[version]
+		if err := c.fileIO.Remove(strings.TrimPrefix(location, "file://")); err != nil && !os.IsNotExist(err) {
+			return deleted, fmt.Errorf("failed to delete metadata file %s: %w", location, err)
+		}
+		deleted = append(deleted, location)
+	}
+
+	return deleted, nil
+}
+
+// expiredMetadataFiles returns the metadata files of a table older than the retainLast
+// most recent previous versions by version, and those of them in the metadata log
+func (c *Catalog) expiredMetadataFiles(tbl *table.Table, retainLast int) (map[int]string, []string) {
+	currentVersion := c.getNextMetadataVersion(tbl.MetadataLocation()) - 1
+	cutoff := currentVersion - max(retainLast, 0)
+
+	candidates := make(map[int]string)
+	var logged []string
+	for entry := range tbl.Metadata().PreviousFiles() {
+		if version, ok := parseMetadataVersion(entry.MetadataFile); ok && version < cutoff {
+			candidates[version] = entry.MetadataFile
+			logged = append(logged, entry.MetadataFile)
+		}
+	}
+
+	// Files written before the metadata log was maintained are only discoverable
+	// by listing the metadata directory
+	if slash := strings.LastIndex(tbl.MetadataLocation(), "/"); c.fileSystem != nil && slash > 0 {
+		metadataDir := tbl.MetadataLocation()[:slash]
+		if entries, err := c.fileSystem.List(metadataDir); err == nil {
+			for _, entry := range entries {
+				if version, ok := parseMetadataVersion(entry.Name()); ok && !entry.IsDir() && version < cutoff {
+					if _, seen := candidates[version]; !seen {
+						candidates[version] = metadataDir + "/" + entry.Name()
+					}
+				}
+			}
+		}
+	}
+
+	return candidates, logged
+}
+
 // LoadTable loads a table from the catalog
 func (c *Catalog) LoadTable(ctx context.Context, identifier table.Identifier, props iceberg.Properties) (*table.Table, error) {
 	namespace := catalog.NamespaceFromIdent(identifier)
@@ -480,6 +691,17 @@ func (c *Catalog) DropNamespace(ctx context.Context, namespace table.Identifier)
 		return catalog.ErrNamespaceNotEmpty
 	}
 
+	// Check if namespace has views
+	var viewCount int
+	countViewsQuery := `SELECT COUNT(*) FROM iceberg_views WHERE catalog_name = ? AND view_namespace = ?`
+	err = c.db.QueryRowContext(ctx, countViewsQuery, c.name, namespaceStr).Scan(&viewCount)
+	if err != nil {
+		return fmt.Errorf("failed to count views in namespace: %w", err)
+	}
+	if viewCount > 0 {
+		return catalog.ErrNamespaceNotEmpty
+	}
+
 	// Delete namespace properties
 	deleteSQL := `DELETE FROM iceberg_namespace_properties WHERE catalog_name = ? AND namespace = ?`
 	_, err = c.db.ExecContext(ctx, deleteSQL, c.name, namespaceStr)
@@ -706,6 +928,11 @@ func (c *Catalog) defaultTableLocation(identifier table.Identifier) string {
 		return ""
 	}
 
+	// Object store locations are URLs, so they are joined with "/" rather than filepath
+	if s3.IsRemoteLocation(c.warehouse) {
+		return strings.TrimSuffix(c.warehouse, "/") + "/" + strings.Join(identifier, "/")
+	}
+
 	// Build path: warehouse/namespace/table_name
 	path := c.warehouse
 	for _, part := range identifier {
@@ -721,6 +948,10 @@ func (c *Catalog) newMetadataLocation(identifier table.Identifier, version int)
 		return ""
 	}
 
+	if s3.IsRemoteLocation(tableLocation) {
+		return fmt.Sprintf("%s/metadata/v%d.metadata.json", tableLocation, version)
+	}
+
 	// Handle file:// prefix by removing it
 	tableLocation = strings.TrimPrefix(tableLocation, "file://")
 
@@ -757,6 +988,20 @@ func (c *Catalog) getNextMetadataVersion(currentMetadataLocation string) int {
 	return 2
 }
 
+// parseMetadataVersion extracts N from a .../vN.metadata.json location
+func parseMetadataVersion(location string) (int, bool) {
+	filename := filepath.Base(location)
+	if !strings.HasPrefix(filename, "v") || !strings.HasSuffix(filename, ".metadata.json") {
+		return 0, false
+	}
+
+	version, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(filename, "v"), ".metadata.json"))
+	if err != nil {
+		return 0, false
+	}
+	return version, true
+}
+
 // writeMetadataFile writes the metadata to the specified location
 func (c *Catalog) writeMetadataFile(metadata table.Metadata, metadataLocation string) error {
 	// Serialize metadata to JSON
@@ -765,39 +1010,63 @@ func (c *Catalog) writeMetadataFile(metadata table.Metadata, metadataLocation st
 		return fmt.Errorf("failed to serialize metadata: %w", err)
 	}
 
-	// Handle file:// prefix by removing it for file operations
-	filePath := strings.TrimPrefix(metadataLocation, "file://")
+	return c.writeMetadataJSON(metadataJSON, metadataLocation)
+}
 
+// writeMetadataJSON writes serialized metadata through the catalog's filesystem,
+// falling back to the FileIO for object stores and to local file operations
+func (c *Catalog) writeMetadataJSON(data []byte, metadataLocation string) error {
 	// Use the catalog's filesystem if available
 	if c.fileSystem != nil {
-		file, err := c.fileSystem.Create(filePath)
+		file, err := c.fileSystem.Create(metadataLocation)
 		if err != nil {
-			return fmt.Errorf("failed to create file %s: %w", filePath, err)
+			return fmt.Errorf("failed to create file %s: %w", metadataLocation, err)
 		}
-		defer file.Close()
 
-		_, err = file.Write(metadataJSON)
-		if err != nil {
-			return fmt.Errorf("failed to write metadata to file %s: %w", filePath, err)
+		if _, err := file.Write(data); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to write metadata to file %s: %w", metadataLocation, err)
+		}
+		// Object stores upload the file when it is closed
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("failed to write metadata to file %s: %w", metadataLocation, err)
+		}
+		return nil
+	}
+
+	if s3.IsRemoteLocation(metadataLocation) {
+		writer, ok := c.fileIO.(icebergio.WriteFileIO)
+		if !ok {
+			return fmt.Errorf("file IO cannot write to %s", metadataLocation)
+		}
+		if err := writer.WriteFile(metadataLocation, data); err != nil {
+			return fmt.Errorf("failed to write metadata to %s: %w", metadataLocation, err)
 		}
 		return nil
 	}
 
 	// Fallback to local file operations
-	return writeFile(filePath, metadataJSON)
+	return writeFile(strings.TrimPrefix(metadataLocation, "file://"), data)
 }
 
 // Helper methods for metadata operations
 
 // writeMetadata writes metadata to the specified location
-func (c *Catalog) writeMetadata(schema *iceberg.Schema, location, metadataLocation string) error {
+func (c *Catalog) writeMetadata(schema *iceberg.Schema, cfg catalog.CreateTableCfg, metadataLocation string) error {
 	// Handle file:// prefix by removing it
 	metadataLocation = strings.TrimPrefix(metadataLocation, "file://")
 
+	spec := cfg.PartitionSpec
+	if spec == nil {
+		spec = iceberg.UnpartitionedSpec
+	}
+	props := iceberg.Properties{"format-version": "2"}
+	for key, value := range cfg.Properties {
+		props[key] = value
+	}
+
 	// Create proper Iceberg table metadata using iceberg-go APIs
-	metadata, err := table.NewMetadata(schema, iceberg.UnpartitionedSpec, table.UnsortedSortOrder, location, iceberg.Properties{
-		"format-version": "2",
-	})
+	metadata, err := table.NewMetadata(schema, spec, cfg.SortOrder, cfg.Location, props)
 	if err != nil {
 		return fmt.Errorf("failed to create metadata: %w", err)
 	}
@@ -808,23 +1077,7 @@ func (c *Catalog) writeMetadata(schema *iceberg.Schema, location, metadataLocati
 		return fmt.Errorf("failed to serialize metadata: %w", err)
 	}
 
-	// Use the catalog's filesystem if available
-	if c.fileSystem != nil {
-		file, err := c.fileSystem.Create(metadataLocation)
-		if err != nil {
-			return fmt.Errorf("failed to create file %s: %w", metadataLocation, err)
-		}
-		defer file.Close()
-
-		_, err = file.Write(metadataJSON)
-		if err != nil {
-			return fmt.Errorf("failed to write metadata to file %s: %w", metadataLocation, err)
-		}
-		return nil
-	}
-
-	// Fallback to local file operations
-	return writeFile(metadataLocation, metadataJSON)
+	return c.writeMetadataJSON(metadataJSON, metadataLocation)
 }
 
 // writeFile writes data to a file (helper function)
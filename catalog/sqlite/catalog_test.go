@@ -2,14 +2,24 @@ package sqlite
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/TFMV/icebox/config"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/apache/iceberg-go"
 	icebergcatalog "github.com/apache/iceberg-go/catalog"
 	"github.com/apache/iceberg-go/table"
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
 )
 
 func TestNewCatalog(t *testing.T) {
@@ -299,6 +309,42 @@ func TestCreateTableInNonExistentNamespace(t *testing.T) {
 	}
 }
 
+func TestCreateTableWithOptions(t *testing.T) {
+	catalog := createTestCatalog(t)
+	defer catalog.Close()
+
+	ctx := context.Background()
+	if err := catalog.CreateNamespace(ctx, table.Identifier{"test_namespace"}, iceberg.Properties{}); err != nil {
+		t.Fatalf("Failed to create namespace: %v", err)
+	}
+
+	schema := iceberg.NewSchema(1,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+		iceberg.NestedField{ID: 2, Name: "region", Type: iceberg.PrimitiveTypes.String},
+	)
+	spec := iceberg.NewPartitionSpec(iceberg.PartitionField{
+		SourceID: 2, FieldID: 1000, Name: "region", Transform: iceberg.IdentityTransform{},
+	})
+	tbl, err := catalog.CreateTable(ctx, table.Identifier{"test_namespace", "partitioned"}, schema,
+		icebergcatalog.WithPartitionSpec(&spec),
+		icebergcatalog.WithProperties(iceberg.Properties{"owner": "analytics"}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	spec = tbl.Spec()
+	if got := spec.NumFields(); got != 1 {
+		t.Errorf("Expected 1 partition field, got %d", got)
+	}
+	if got := tbl.Properties()["owner"]; got != "analytics" {
+		t.Errorf("Expected owner property 'analytics', got %q", got)
+	}
+	if got := tbl.Metadata().Version(); got != 2 {
+		t.Errorf("Expected format version 2, got %d", got)
+	}
+}
+
 func TestLoadTable(t *testing.T) {
 	catalog := createTestCatalog(t)
 	defer catalog.Close()
@@ -562,3 +608,297 @@ func createTestCatalog(t *testing.T) *Catalog {
 
 	return catalog
 }
+
+func createCommittedTable(t *testing.T, catalog *Catalog, commits int) table.Identifier {
+	ctx := context.Background()
+	tableIdent := table.Identifier{"test_namespace", "test_table"}
+
+	if err := catalog.CreateNamespace(ctx, table.Identifier{"test_namespace"}, iceberg.Properties{}); err != nil {
+		t.Fatalf("Failed to create namespace: %v", err)
+	}
+
+	schema := iceberg.NewSchema(1, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true})
+	if _, err := catalog.CreateTable(ctx, tableIdent, schema); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	for i := 0; i < commits; i++ {
+		tbl, err := catalog.LoadTable(ctx, tableIdent, nil)
+		if err != nil {
+			t.Fatalf("Failed to load table: %v", err)
+		}
+		updates := []table.Update{table.NewSetPropertiesUpdate(iceberg.Properties{"commit": fmt.Sprintf("%d", i)})}
+		if _, _, err := catalog.CommitTable(ctx, tbl, nil, updates); err != nil {
+			t.Fatalf("Failed to commit table: %v", err)
+		}
+	}
+
+	return tableIdent
+}
+
+func metadataFiles(t *testing.T, metadataLocation string) []string {
+	entries, err := os.ReadDir(filepath.Dir(strings.TrimPrefix(metadataLocation, "file://")))
+	if err != nil {
+		t.Fatalf("Failed to read metadata directory: %v", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if _, ok := parseMetadataVersion(entry.Name()); ok {
+			files = append(files, entry.Name())
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+func TestCommitTableMaintainsMetadataLog(t *testing.T) {
+	catalog := createTestCatalog(t)
+	ctx := context.Background()
+
+	tableIdent := createCommittedTable(t, catalog, 3)
+
+	tbl, err := catalog.LoadTable(ctx, tableIdent, nil)
+	if err != nil {
+		t.Fatalf("Failed to load table: %v", err)
+	}
+
+	if got := tbl.Properties()["commit"]; got != "2" {
+		t.Errorf("Expected last committed property '2', got '%s'", got)
+	}
+
+	var logged []string
+	for entry := range tbl.Metadata().PreviousFiles() {
+		logged = append(logged, filepath.Base(entry.MetadataFile))
+	}
+	expected := []string{"v1.metadata.json", "v2.metadata.json", "v3.metadata.json"}
+	if strings.Join(logged, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected metadata log %v, got %v", expected, logged)
+	}
+}
+
+func TestCommitTableRejectsStaleTable(t *testing.T) {
+	catalog := createTestCatalog(t)
+	ctx := context.Background()
+
+	tableIdent := createCommittedTable(t, catalog, 1)
+
+	stale, err := catalog.LoadTable(ctx, tableIdent, nil)
+	if err != nil {
+		t.Fatalf("Failed to load table: %v", err)
+	}
+	current, err := catalog.LoadTable(ctx, tableIdent, nil)
+	if err != nil {
+		t.Fatalf("Failed to load table: %v", err)
+	}
+	if _, _, err := catalog.CommitTable(ctx, current, nil, []table.Update{table.NewSetPropertiesUpdate(iceberg.Properties{"writer": "current"})}); err != nil {
+		t.Fatalf("Failed to commit table: %v", err)
+	}
+
+	// A commit against metadata another commit replaced must not overwrite that commit
+	_, _, err = catalog.CommitTable(ctx, stale, nil, []table.Update{table.NewSetPropertiesUpdate(iceberg.Properties{"writer": "stale"})})
+	if !errors.Is(err, ErrCommitConflict) {
+		t.Fatalf("Expected ErrCommitConflict, got %v", err)
+	}
+	reloaded, err := catalog.LoadTable(ctx, tableIdent, nil)
+	if err != nil {
+		t.Fatalf("Failed to load table: %v", err)
+	}
+	if got := reloaded.Properties()["writer"]; got != "current" {
+		t.Errorf("Expected the first commit to be kept, got writer %q", got)
+	}
+
+	// Metadata log edits require the table to still be at the snapshot they were made from
+	otherSnapshot := int64(42)
+	reqs := []table.Requirement{table.AssertRefSnapshotID("main", &otherSnapshot)}
+	if _, _, err := catalog.CommitTable(ctx, reloaded, reqs, nil); err == nil {
+		t.Error("Expected a commit with an unmet requirement to fail")
+	}
+}
+
+func TestExpireMetadataFiles(t *testing.T) {
+	catalog := createTestCatalog(t)
+	ctx := context.Background()
+
+	tableIdent := createCommittedTable(t, catalog, 4)
+
+	tbl, err := catalog.LoadTable(ctx, tableIdent, nil)
+	if err != nil {
+		t.Fatalf("Failed to load table: %v", err)
+	}
+	if got := len(metadataFiles(t, tbl.MetadataLocation())); got != 5 {
+		t.Fatalf("Expected 5 metadata files before expiry, got %d", got)
+	}
+
+	deleted, err := catalog.ExpireMetadataFiles(ctx, tableIdent, 1)
+	if err != nil {
+		t.Fatalf("Failed to expire metadata files: %v", err)
+	}
+	var deletedNames []string
+	for _, location := range deleted {
+		deletedNames = append(deletedNames, filepath.Base(location))
+	}
+	if expected := "v1.metadata.json,v2.metadata.json,v3.metadata.json"; strings.Join(deletedNames, ",") != expected {
+		t.Errorf("Expected deleted metadata files %s in order, got %v", expected, deleted)
+	}
+
+	// Removing the expired files from the metadata log is committed as v6
+	remaining := metadataFiles(t, tbl.MetadataLocation())
+	expected := []string{"v4.metadata.json", "v5.metadata.json", "v6.metadata.json"}
+	if strings.Join(remaining, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected remaining metadata files %v, got %v", expected, remaining)
+	}
+
+	// The table must still load from its current metadata, whose log only lists kept files
+	reloaded, err := catalog.LoadTable(ctx, tableIdent, nil)
+	if err != nil {
+		t.Fatalf("Failed to load table after expiry: %v", err)
+	}
+	var logged []string
+	for entry := range reloaded.Metadata().PreviousFiles() {
+		logged = append(logged, filepath.Base(entry.MetadataFile))
+	}
+	if expected := "v4.metadata.json,v5.metadata.json"; strings.Join(logged, ",") != expected {
+		t.Errorf("Expected metadata log %s, got %v", expected, logged)
+	}
+}
+
+func TestDeleteMetadataAfterCommit(t *testing.T) {
+	catalog := createTestCatalog(t)
+	ctx := context.Background()
+
+	tableIdent := createCommittedTable(t, catalog, 0)
+
+	tbl, err := catalog.LoadTable(ctx, tableIdent, nil)
+	if err != nil {
+		t.Fatalf("Failed to load table: %v", err)
+	}
+	updates := []table.Update{table.NewSetPropertiesUpdate(iceberg.Properties{
+		table.MetadataDeleteAfterCommitEnabledKey: "true",
+		table.MetadataPreviousVersionsMaxKey:      "1",
+	})}
+	if _, _, err := catalog.CommitTable(ctx, tbl, nil, updates); err != nil {
+		t.Fatalf("Failed to commit table: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		tbl, err = catalog.LoadTable(ctx, tableIdent, nil)
+		if err != nil {
+			t.Fatalf("Failed to load table: %v", err)
+		}
+		updates := []table.Update{table.NewSetPropertiesUpdate(iceberg.Properties{"commit": fmt.Sprintf("%d", i)})}
+		if _, _, err := catalog.CommitTable(ctx, tbl, nil, updates); err != nil {
+			t.Fatalf("Failed to commit table: %v", err)
+		}
+	}
+
+	tbl, err = catalog.LoadTable(ctx, tableIdent, nil)
+	if err != nil {
+		t.Fatalf("Failed to load table: %v", err)
+	}
+
+	remaining := metadataFiles(t, tbl.MetadataLocation())
+	expected := []string{"v4.metadata.json", "v5.metadata.json"}
+	if strings.Join(remaining, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected remaining metadata files %v, got %v", expected, remaining)
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	backend := s3mem.New()
+	if err := backend.CreateBucket("warehouse"); err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+	server := httptest.NewServer(gofakes3.New(backend).Server())
+	defer server.Close()
+
+	s3Config := &config.S3Config{
+		Bucket:          "warehouse",
+		Endpoint:        server.URL,
+		AccessKeyID:     "test",
+		SecretAccessKey: "test",
+		Prefix:          "lakehouse",
+	}
+	cfg := &config.Config{
+		Name: "test-catalog",
+		Catalog: config.CatalogConfig{
+			Type:   "sqlite",
+			SQLite: &config.SQLiteConfig{Path: filepath.Join(t.TempDir(), "catalog.db")},
+		},
+		Storage: config.StorageConfig{Type: "s3", S3: s3Config},
+	}
+
+	catalog, err := NewCatalog(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create catalog: %v", err)
+	}
+	defer catalog.Close()
+
+	if catalog.GetS3Config() != s3Config {
+		t.Error("Expected the catalog to expose its S3 configuration")
+	}
+
+	ctx := context.Background()
+	tableIdent := createCommittedTable(t, catalog, 1)
+
+	tbl, err := catalog.LoadTable(ctx, tableIdent, nil)
+	if err != nil {
+		t.Fatalf("Failed to load table: %v", err)
+	}
+
+	expected := "s3://warehouse/lakehouse/test_namespace/test_table/metadata/v2.metadata.json"
+	if tbl.MetadataLocation() != expected {
+		t.Errorf("Expected metadata location %s, got %s", expected, tbl.MetadataLocation())
+	}
+
+	// Data files are written to the bucket through the table's FileIO
+	arrowSchema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	builder.AppendValues([]int64{1, 2, 3}, nil)
+	column := builder.NewArray()
+	defer column.Release()
+	record := array.NewRecord(arrowSchema, []arrow.Array{column}, 3)
+	defer record.Release()
+	arrowTable := array.NewTableFromRecords(arrowSchema, []arrow.Record{record})
+	defer arrowTable.Release()
+
+	txn := tbl.NewTransaction()
+	if err := txn.AppendTable(ctx, arrowTable, 1024, nil); err != nil {
+		t.Fatalf("Failed to append data: %v", err)
+	}
+	tbl, err = txn.Commit(ctx)
+	if err != nil {
+		t.Fatalf("Failed to commit append: %v", err)
+	}
+
+	objects, err := backend.ListBucket("warehouse", &gofakes3.Prefix{}, gofakes3.ListBucketPage{})
+	if err != nil {
+		t.Fatalf("Failed to list bucket: %v", err)
+	}
+	var dataFiles, metadataFiles int
+	for _, object := range objects.Contents {
+		switch {
+		case strings.HasSuffix(object.Key, ".parquet"):
+			dataFiles++
+		case strings.HasSuffix(object.Key, ".metadata.json"):
+			metadataFiles++
+		}
+	}
+	if dataFiles != 1 || metadataFiles != 3 {
+		t.Errorf("Expected 1 data file and 3 metadata files in the bucket, got %d and %d", dataFiles, metadataFiles)
+	}
+
+	snapshot := tbl.CurrentSnapshot()
+	if snapshot == nil || snapshot.Summary.Properties["added-records"] != "3" {
+		t.Errorf("Expected a snapshot adding 3 records, got %+v", snapshot)
+	}
+
+	// Files tracked by the metadata log can still be expired without listing the bucket
+	deleted, err := catalog.ExpireMetadataFiles(ctx, tableIdent, 0)
+	if err != nil {
+		t.Fatalf("Failed to expire metadata files: %v", err)
+	}
+	if len(deleted) != 2 {
+		t.Errorf("Expected 2 deleted metadata files, got %d: %v", len(deleted), deleted)
+	}
+}
@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TFMV/icebox/catalog/grant"
+)
+
+// ListGrants returns the access grants stored in the catalog
+func (c *Catalog) ListGrants(ctx context.Context) ([]grant.Grant, error) {
+	query := `SELECT grantee, namespace, table_name, privilege FROM iceberg_grants WHERE catalog_name = ? ORDER BY namespace, table_name, grantee`
+
+	rows, err := c.db.QueryContext(ctx, query, c.name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list grants: %w", err)
+	}
+	defer rows.Close()
+
+	var grants []grant.Grant
+	for rows.Next() {
+		var g grant.Grant
+		if err := rows.Scan(&g.Grantee, &g.Namespace, &g.Table, &g.Privilege); err != nil {
+			return nil, fmt.Errorf("failed to scan grant row: %w", err)
+		}
+		grants = append(grants, g)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating grant rows: %w", err)
+	}
+	return grants, nil
+}
+
+// SetGrant gives a grantee a privilege on a resource, replacing the privilege it held
+// there before
+func (c *Catalog) SetGrant(ctx context.Context, g grant.Grant) error {
+	if err := g.Validate(); err != nil {
+		return err
+	}
+
+	upsertSQL := `
+	INSERT INTO iceberg_grants (catalog_name, grantee, namespace, table_name, privilege)
+	VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT (catalog_name, grantee, namespace, table_name) DO UPDATE SET privilege = excluded.privilege`
+
+	if _, err := c.db.ExecContext(ctx, upsertSQL, c.name, g.Grantee, g.Namespace, g.Table, string(g.Privilege)); err != nil {
+		return fmt.Errorf("failed to store grant: %w", err)
+	}
+	return nil
+}
+
+// RevokeGrant removes a grantee's privilege on a resource
+func (c *Catalog) RevokeGrant(ctx context.Context, grantee, namespace, tableName string) error {
+	deleteSQL := `DELETE FROM iceberg_grants WHERE catalog_name = ? AND grantee = ? AND namespace = ? AND table_name = ?`
+	result, err := c.db.ExecContext(ctx, deleteSQL, c.name, grantee, namespace, tableName)
+	if err != nil {
+		return fmt.Errorf("failed to delete grant record: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return grant.ErrNoSuchGrant
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TFMV/icebox/catalog/grant"
+)
+
+func TestGrants(t *testing.T) {
+	catalog := createTestCatalog(t)
+	defer catalog.Close()
+
+	ctx := context.Background()
+	analysts := grant.Grant{Grantee: grant.Role("analyst"), Namespace: "sales", Privilege: grant.Read}
+	loader := grant.Grant{Grantee: grant.User("loader"), Namespace: "sales", Table: "orders", Privilege: grant.Write}
+
+	for _, g := range []grant.Grant{analysts, loader} {
+		if err := catalog.SetGrant(ctx, g); err != nil {
+			t.Fatalf("Failed to set grant: %v", err)
+		}
+	}
+
+	// Granting again replaces the privilege
+	analysts.Privilege = grant.Admin
+	if err := catalog.SetGrant(ctx, analysts); err != nil {
+		t.Fatalf("Failed to update grant: %v", err)
+	}
+
+	grants, err := catalog.ListGrants(ctx)
+	if err != nil {
+		t.Fatalf("Failed to list grants: %v", err)
+	}
+	if len(grants) != 2 || grants[0] != analysts || grants[1] != loader {
+		t.Errorf("Unexpected grants: %+v", grants)
+	}
+
+	if err := catalog.SetGrant(ctx, grant.Grant{Grantee: "analyst", Namespace: "sales", Privilege: grant.Read}); err == nil {
+		t.Error("Expected an error for a grantee without a kind")
+	}
+
+	if err := catalog.RevokeGrant(ctx, loader.Grantee, "sales", "orders"); err != nil {
+		t.Fatalf("Failed to revoke grant: %v", err)
+	}
+	if err := catalog.RevokeGrant(ctx, loader.Grantee, "sales", "orders"); !errors.Is(err, grant.ErrNoSuchGrant) {
+		t.Errorf("Expected ErrNoSuchGrant, got %v", err)
+	}
+
+	grants, err = catalog.ListGrants(ctx)
+	if err != nil {
+		t.Fatalf("Failed to list grants: %v", err)
+	}
+	if len(grants) != 1 {
+		t.Errorf("Expected 1 grant after revoking, got %d", len(grants))
+	}
+}
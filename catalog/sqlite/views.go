@@ -0,0 +1,246 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"strings"
+
+	"github.com/TFMV/icebox/catalog/view"
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
+)
+
+// CreateView creates a new view in the catalog
+func (c *Catalog) CreateView(ctx context.Context, identifier table.Identifier, sql string, dialect string, schema *iceberg.Schema, properties map[string]string) (*view.View, error) {
+	if len(identifier) < 2 {
+		return nil, fmt.Errorf("view identifier must have at least namespace and view name")
+	}
+
+	namespace := catalog.NamespaceFromIdent(identifier)
+	viewName := catalog.TableNameFromIdent(identifier)
+
+	// Check if namespace exists
+	exists, err := c.CheckNamespaceExists(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check namespace existence: %w", err)
+	}
+	if !exists {
+		return nil, catalog.ErrNoSuchNamespace
+	}
+
+	// Check if the name is already taken by a view or a table
+	viewExists, err := c.ViewExists(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if viewExists {
+		return nil, catalog.ErrViewAlreadyExists
+	}
+
+	tableExists, err := c.CheckTableExists(ctx, identifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check table existence: %w", err)
+	}
+	if tableExists {
+		return nil, catalog.ErrTableAlreadyExists
+	}
+
+	metadata := view.NewMetadata(c.defaultTableLocation(identifier), sql, dialect, schema, c.name, namespace, properties)
+	metadataLocation := c.newMetadataLocation(identifier, 1)
+
+	if err := c.writeViewMetadata(metadata, metadataLocation); err != nil {
+		return nil, fmt.Errorf("failed to write view metadata: %w", err)
+	}
+
+	insertSQL := `
+	INSERT INTO iceberg_views (catalog_name, view_namespace, view_name, metadata_location, previous_metadata_location)
+	VALUES (?, ?, ?, ?, ?)`
+
+	_, err = c.db.ExecContext(ctx, insertSQL, c.name, namespaceToString(namespace), viewName, metadataLocation, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert view record: %w", err)
+	}
+
+	return view.New(identifier, metadata), nil
+}
+
+// LoadView loads a view from the catalog
+func (c *Catalog) LoadView(ctx context.Context, identifier table.Identifier) (*view.View, error) {
+	namespace := catalog.NamespaceFromIdent(identifier)
+	viewName := catalog.TableNameFromIdent(identifier)
+
+	var metadataLocation sql.NullString
+	query := `SELECT metadata_location FROM iceberg_views WHERE catalog_name = ? AND view_namespace = ? AND view_name = ?`
+
+	err := c.db.QueryRowContext(ctx, query, c.name, namespaceToString(namespace), viewName).Scan(&metadataLocation)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, catalog.ErrNoSuchView
+		}
+		return nil, fmt.Errorf("failed to query view: %w", err)
+	}
+
+	if !metadataLocation.Valid {
+		return nil, fmt.Errorf("view metadata location is null")
+	}
+
+	metadata, err := c.readViewMetadata(metadataLocation.String)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load view metadata: %w", err)
+	}
+
+	return view.New(identifier, metadata), nil
+}
+
+// ListViews lists all views in a namespace
+func (c *Catalog) ListViews(ctx context.Context, namespace table.Identifier) iter.Seq2[table.Identifier, error] {
+	return func(yield func(table.Identifier, error) bool) {
+		query := `SELECT view_namespace, view_name FROM iceberg_views WHERE catalog_name = ? AND view_namespace = ?`
+
+		rows, err := c.db.QueryContext(ctx, query, c.name, namespaceToString(namespace))
+		if err != nil {
+			yield(nil, fmt.Errorf("failed to list views: %w", err))
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var viewNamespace, viewName string
+			if err := rows.Scan(&viewNamespace, &viewName); err != nil {
+				yield(nil, fmt.Errorf("failed to scan view row: %w", err))
+				return
+			}
+
+			identifier := append(stringToNamespace(viewNamespace), viewName)
+			if !yield(identifier, nil) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			yield(nil, fmt.Errorf("error iterating view rows: %w", err))
+		}
+	}
+}
+
+// RenameView renames a view in the catalog
+func (c *Catalog) RenameView(ctx context.Context, from, to table.Identifier) (*view.View, error) {
+	source, err := c.LoadView(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if destination namespace exists
+	toNamespace := catalog.NamespaceFromIdent(to)
+	nsExists, err := c.CheckNamespaceExists(ctx, toNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check destination namespace: %w", err)
+	}
+	if !nsExists {
+		return nil, catalog.ErrNoSuchNamespace
+	}
+
+	// Check if the destination name is taken
+	viewExists, err := c.ViewExists(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+	if viewExists {
+		return nil, catalog.ErrViewAlreadyExists
+	}
+
+	tableExists, err := c.CheckTableExists(ctx, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check destination table: %w", err)
+	}
+	if tableExists {
+		return nil, catalog.ErrTableAlreadyExists
+	}
+
+	fromNamespace := catalog.NamespaceFromIdent(from)
+	updateSQL := `UPDATE iceberg_views SET view_namespace = ?, view_name = ? WHERE catalog_name = ? AND view_namespace = ? AND view_name = ?`
+	_, err = c.db.ExecContext(ctx, updateSQL,
+		namespaceToString(toNamespace), catalog.TableNameFromIdent(to),
+		c.name, namespaceToString(fromNamespace), catalog.TableNameFromIdent(from))
+	if err != nil {
+		return nil, fmt.Errorf("failed to rename view in database: %w", err)
+	}
+
+	return view.New(to, source.Metadata()), nil
+}
+
+// DropView drops a view from the catalog
+func (c *Catalog) DropView(ctx context.Context, identifier table.Identifier) error {
+	namespace := catalog.NamespaceFromIdent(identifier)
+	viewName := catalog.TableNameFromIdent(identifier)
+
+	deleteSQL := `DELETE FROM iceberg_views WHERE catalog_name = ? AND view_namespace = ? AND view_name = ?`
+	result, err := c.db.ExecContext(ctx, deleteSQL, c.name, namespaceToString(namespace), viewName)
+	if err != nil {
+		return fmt.Errorf("failed to delete view record: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return catalog.ErrNoSuchView
+	}
+
+	return nil
+}
+
+// ViewExists checks if a view exists in the catalog
+func (c *Catalog) ViewExists(ctx context.Context, identifier table.Identifier) (bool, error) {
+	namespace := catalog.NamespaceFromIdent(identifier)
+	viewName := catalog.TableNameFromIdent(identifier)
+
+	var count int
+	query := `SELECT COUNT(*) FROM iceberg_views WHERE catalog_name = ? AND view_namespace = ? AND view_name = ?`
+	err := c.db.QueryRowContext(ctx, query, c.name, namespaceToString(namespace), viewName).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check view existence: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// writeViewMetadata writes view metadata to the specified location
+func (c *Catalog) writeViewMetadata(metadata *view.Metadata, metadataLocation string) error {
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to serialize view metadata: %w", err)
+	}
+
+	return c.writeMetadataJSON(metadataJSON, metadataLocation)
+}
+
+// readViewMetadata reads view metadata through the catalog's file IO
+func (c *Catalog) readViewMetadata(metadataLocation string) (*view.Metadata, error) {
+	if c.fileIO == nil {
+		data, err := os.ReadFile(strings.TrimPrefix(metadataLocation, "file://"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read view metadata file: %w", err)
+		}
+		return view.ParseMetadata(data)
+	}
+
+	file, err := c.fileIO.Open(metadataLocation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open view metadata file: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read view metadata file: %w", err)
+	}
+	return view.ParseMetadata(data)
+}
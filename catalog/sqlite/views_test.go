@@ -0,0 +1,109 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	icebergcatalog "github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
+)
+
+func TestViewLifecycle(t *testing.T) {
+	catalog := createTestCatalog(t)
+	defer catalog.Close()
+
+	ctx := context.Background()
+	namespace := table.Identifier{"analytics"}
+	if err := catalog.CreateNamespace(ctx, namespace, iceberg.Properties{}); err != nil {
+		t.Fatalf("Failed to create namespace: %v", err)
+	}
+
+	schema := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "region", Type: iceberg.PrimitiveTypes.String},
+		iceberg.NestedField{ID: 2, Name: "total", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+	)
+	identifier := table.Identifier{"analytics", "sales_by_region"}
+	query := "SELECT region, SUM(amount) AS total FROM sales GROUP BY region"
+
+	created, err := catalog.CreateView(ctx, identifier, query, "duckdb", schema, map[string]string{"owner": "analytics"})
+	if err != nil {
+		t.Fatalf("Failed to create view: %v", err)
+	}
+	if created.SQL() != query {
+		t.Errorf("Expected SQL %q, got %q", query, created.SQL())
+	}
+
+	if _, err := catalog.CreateView(ctx, identifier, query, "duckdb", schema, nil); !errors.Is(err, icebergcatalog.ErrViewAlreadyExists) {
+		t.Errorf("Expected ErrViewAlreadyExists, got %v", err)
+	}
+	if _, err := catalog.CreateTable(ctx, identifier, schema); !errors.Is(err, icebergcatalog.ErrViewAlreadyExists) {
+		t.Errorf("Expected a table with the view's name to be rejected, got %v", err)
+	}
+
+	loaded, err := catalog.LoadView(ctx, identifier)
+	if err != nil {
+		t.Fatalf("Failed to load view: %v", err)
+	}
+	if loaded.SQL() != query || loaded.Dialect() != "duckdb" {
+		t.Errorf("Unexpected view definition: %q (%s)", loaded.SQL(), loaded.Dialect())
+	}
+	if loaded.Metadata().Properties["owner"] != "analytics" {
+		t.Errorf("Expected view properties to be persisted, got %v", loaded.Metadata().Properties)
+	}
+	viewSchema := loaded.Schema()
+	if viewSchema == nil || len(viewSchema.Fields) != 2 {
+		t.Fatalf("Expected a schema with 2 fields, got %+v", viewSchema)
+	}
+	if viewSchema.Fields[1].Type != "long" || !viewSchema.Fields[1].Required {
+		t.Errorf("Unexpected field %+v", viewSchema.Fields[1])
+	}
+
+	var listed []string
+	for ident, err := range catalog.ListViews(ctx, namespace) {
+		if err != nil {
+			t.Fatalf("Failed to list views: %v", err)
+		}
+		listed = append(listed, namespaceToString(ident))
+	}
+	if len(listed) != 1 || listed[0] != "analytics.sales_by_region" {
+		t.Errorf("Expected one listed view, got %v", listed)
+	}
+
+	// Views keep the namespace non-empty
+	if err := catalog.DropNamespace(ctx, namespace); !errors.Is(err, icebergcatalog.ErrNamespaceNotEmpty) {
+		t.Errorf("Expected ErrNamespaceNotEmpty, got %v", err)
+	}
+
+	renamed := table.Identifier{"analytics", "regional_sales"}
+	if _, err := catalog.RenameView(ctx, identifier, renamed); err != nil {
+		t.Fatalf("Failed to rename view: %v", err)
+	}
+	if exists, _ := catalog.ViewExists(ctx, identifier); exists {
+		t.Error("Old view name should no longer exist")
+	}
+	if _, err := catalog.LoadView(ctx, renamed); err != nil {
+		t.Errorf("Failed to load renamed view: %v", err)
+	}
+
+	if err := catalog.DropView(ctx, renamed); err != nil {
+		t.Fatalf("Failed to drop view: %v", err)
+	}
+	if err := catalog.DropView(ctx, renamed); !errors.Is(err, icebergcatalog.ErrNoSuchView) {
+		t.Errorf("Expected ErrNoSuchView, got %v", err)
+	}
+	if _, err := catalog.LoadView(ctx, renamed); !errors.Is(err, icebergcatalog.ErrNoSuchView) {
+		t.Errorf("Expected ErrNoSuchView, got %v", err)
+	}
+}
+
+func TestCreateViewInNonExistentNamespace(t *testing.T) {
+	catalog := createTestCatalog(t)
+	defer catalog.Close()
+
+	_, err := catalog.CreateView(context.Background(), table.Identifier{"missing", "v"}, "SELECT 1", "duckdb", nil, nil)
+	if !errors.Is(err, icebergcatalog.ErrNoSuchNamespace) {
+		t.Errorf("Expected ErrNoSuchNamespace, got %v", err)
+	}
+}
@@ -0,0 +1,232 @@
+// Package view holds the Iceberg view metadata model shared by the catalog
+// implementations. See https://iceberg.apache.org/view-spec/
+package view
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/google/uuid"
+)
+
+const (
+	// FormatVersion is the view metadata format version written by icebox
+	FormatVersion = 1
+
+	// RepresentationTypeSQL is the representation type for SQL view definitions
+	RepresentationTypeSQL = "sql"
+
+	// DefaultDialect is the SQL dialect used for views created by icebox
+	DefaultDialect = "duckdb"
+)
+
+// Metadata represents the complete view metadata according to the Iceberg view spec
+type Metadata struct {
+	ViewUUID         string            `json:"view-uuid"`
+	FormatVersion    int               `json:"format-version"`
+	Location         string            `json:"location"`
+	Schemas          []Schema          `json:"schemas"`
+	CurrentVersionID int               `json:"current-version-id"`
+	Versions         []Version         `json:"versions"`
+	VersionLog       []VersionLogEntry `json:"version-log"`
+	Properties       map[string]string `json:"properties,omitempty"`
+}
+
+// Schema represents a view schema
+type Schema struct {
+	SchemaID int           `json:"schema-id"`
+	Type     string        `json:"type"`
+	Fields   []SchemaField `json:"fields"`
+}
+
+// SchemaField represents a field in a view schema
+type SchemaField struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
+	Type     string `json:"type"`
+	Doc      string `json:"doc,omitempty"`
+}
+
+// Version represents a version of a view
+type Version struct {
+	VersionID        int               `json:"version-id"`
+	SchemaID         int               `json:"schema-id"`
+	TimestampMs      int64             `json:"timestamp-ms"`
+	Summary          map[string]string `json:"summary"`
+	Representations  []Representation  `json:"representations"`
+	DefaultCatalog   *string           `json:"default-catalog,omitempty"`
+	DefaultNamespace []string          `json:"default-namespace"`
+}
+
+// Representation represents a view representation (SQL, etc.)
+type Representation struct {
+	Type    string `json:"type"`
+	SQL     string `json:"sql"`
+	Dialect string `json:"dialect"`
+}
+
+// VersionLogEntry represents an entry in the view version log
+type VersionLogEntry struct {
+	TimestampMs int64 `json:"timestamp-ms"`
+	VersionID   int   `json:"version-id"`
+}
+
+// NewMetadata creates the metadata of a new view with a single SQL version
+func NewMetadata(location, sql, dialect string, schema *iceberg.Schema, defaultCatalog string, defaultNamespace table.Identifier, properties map[string]string) *Metadata {
+	now := time.Now().UnixMilli()
+	if dialect == "" {
+		dialect = DefaultDialect
+	}
+
+	return &Metadata{
+		ViewUUID:         uuid.New().String(),
+		FormatVersion:    FormatVersion,
+		Location:         location,
+		Schemas:          []Schema{NewSchema(schema, 1)},
+		CurrentVersionID: 1,
+		Versions: []Version{
+			{
+				VersionID:   1,
+				SchemaID:    1,
+				TimestampMs: now,
+				Summary: map[string]string{
+					"engine-name":    "icebox",
+					"engine-version": "1.0.0",
+				},
+				Representations: []Representation{
+					{
+						Type:    RepresentationTypeSQL,
+						SQL:     sql,
+						Dialect: dialect,
+					},
+				},
+				DefaultCatalog:   &defaultCatalog,
+				DefaultNamespace: defaultNamespace,
+			},
+		},
+		VersionLog: []VersionLogEntry{
+			{
+				TimestampMs: now,
+				VersionID:   1,
+			},
+		},
+		Properties: properties,
+	}
+}
+
+// NewSchema converts an Iceberg schema to a view schema. A nil schema yields
+// a schema without fields.
+func NewSchema(schema *iceberg.Schema, schemaID int) Schema {
+	viewSchema := Schema{
+		SchemaID: schemaID,
+		Type:     "struct",
+		Fields:   []SchemaField{},
+	}
+	if schema == nil {
+		return viewSchema
+	}
+
+	for _, field := range schema.Fields() {
+		viewSchema.Fields = append(viewSchema.Fields, SchemaField{
+			ID:       field.ID,
+			Name:     field.Name,
+			Required: field.Required,
+			Type:     field.Type.String(),
+			Doc:      field.Doc,
+		})
+	}
+	return viewSchema
+}
+
+// ParseMetadata decodes view metadata from its JSON representation
+func ParseMetadata(data []byte) (*Metadata, error) {
+	var metadata Metadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse view metadata: %w", err)
+	}
+	if metadata.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("unsupported view format version %d", metadata.FormatVersion)
+	}
+	return &metadata, nil
+}
+
+// View is a named view loaded from a catalog
+type View struct {
+	identifier table.Identifier
+	metadata   *Metadata
+}
+
+// New creates a view from its identifier and metadata
+func New(identifier table.Identifier, metadata *Metadata) *View {
+	return &View{
+		identifier: identifier,
+		metadata:   metadata,
+	}
+}
+
+// Identifier returns the view identifier
+func (v *View) Identifier() table.Identifier {
+	return v.identifier
+}
+
+// Metadata returns the view metadata
+func (v *View) Metadata() *Metadata {
+	return v.metadata
+}
+
+// Schema returns the current schema of the view
+func (v *View) Schema() *Schema {
+	version := v.CurrentVersion()
+	if version == nil {
+		return nil
+	}
+	for _, schema := range v.metadata.Schemas {
+		if schema.SchemaID == version.SchemaID {
+			return &schema
+		}
+	}
+	return nil
+}
+
+// CurrentVersion returns the current version of the view
+func (v *View) CurrentVersion() *Version {
+	for _, version := range v.metadata.Versions {
+		if version.VersionID == v.metadata.CurrentVersionID {
+			return &version
+		}
+	}
+	return nil
+}
+
+// SQL returns the SQL representation of the current version
+func (v *View) SQL() string {
+	if repr := v.sqlRepresentation(); repr != nil {
+		return repr.SQL
+	}
+	return ""
+}
+
+// Dialect returns the SQL dialect of the current version
+func (v *View) Dialect() string {
+	if repr := v.sqlRepresentation(); repr != nil {
+		return repr.Dialect
+	}
+	return ""
+}
+
+func (v *View) sqlRepresentation() *Representation {
+	version := v.CurrentVersion()
+	if version == nil {
+		return nil
+	}
+	for _, repr := range version.Representations {
+		if repr.Type == RepresentationTypeSQL {
+			return &repr
+		}
+	}
+	return nil
+}
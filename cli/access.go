@@ -0,0 +1,435 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/TFMV/icebox/auth"
+	"github.com/TFMV/icebox/catalog"
+	"github.com/TFMV/icebox/catalog/grant"
+	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/display"
+	"github.com/TFMV/icebox/engine/duckdb"
+	"github.com/TFMV/icebox/jobs"
+	"github.com/apache/iceberg-go/table"
+	"github.com/spf13/cobra"
+)
+
+var accessCmd = &cobra.Command{
+	Use:   "access",
+	Short: "Manage access grants",
+	Long: `Manage the read, write and admin grants enforced by servers started with --auth.
+
+Grants are stored in the catalog and give a role or a user a privilege on the
+whole catalog (*), on a namespace and everything in it, or on a single table
+or view. Each privilege includes the ones below it:
+- read: query and describe tables
+- write: import data and create tables
+- admin: drop tables and namespaces and run statements other than queries
+
+Roles come from the auth section of .icebox.yml or from the roles claim of JWTs.
+
+This command provides subcommands for grant operations:
+- grant: Grant a privilege to a role or user
+- revoke: Revoke a grant
+- list: List all grants
+
+Examples:
+  icebox access grant read analytics --role analyst
+  icebox access grant write sales.orders --user etl
+  icebox access grant admin '*' --role admin
+  icebox access revoke analytics --role analyst
+  icebox access list`,
+}
+
+var accessGrantCmd = &cobra.Command{
+	Use:   "grant <privilege> <resource>",
+	Short: "Grant a privilege to a role or user",
+	Long: `Grant read, write or admin on a resource to a role or a user.
+
+The resource is * for the whole catalog, a namespace, or a namespace-qualified
+table or view. Granting a privilege on a resource that already has a grant for
+the same role or user replaces it.
+
+Examples:
+  icebox access grant read analytics --role analyst
+  icebox access grant write sales.orders --user etl
+  icebox access grant admin '*' --role admin`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAccessGrant,
+}
+
+var accessRevokeCmd = &cobra.Command{
+	Use:   "revoke <resource>",
+	Short: "Revoke a grant from a role or user",
+	Long: `Revoke the grant a role or user holds on a resource.
+
+Examples:
+  icebox access revoke analytics --role analyst
+  icebox access revoke sales.orders --user etl`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAccessRevoke,
+}
+
+var accessListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List access grants",
+	Long: `List all grants stored in the catalog.
+
+Examples:
+  icebox access list
+  icebox access list --format json`,
+	RunE: runAccessList,
+}
+
+type accessGranteeOptions struct {
+	role string
+	user string
+}
+
+type accessListOptions struct {
+	format string
+}
+
+var (
+	accessGrantOpts  = &accessGranteeOptions{}
+	accessRevokeOpts = &accessGranteeOptions{}
+	accessListOpts   = &accessListOptions{}
+)
+
+func init() {
+	rootCmd.AddCommand(accessCmd)
+
+	// Add subcommands
+	accessCmd.AddCommand(accessGrantCmd)
+	accessCmd.AddCommand(accessRevokeCmd)
+	accessCmd.AddCommand(accessListCmd)
+
+	// Access grant flags
+	accessGrantCmd.Flags().StringVar(&accessGrantOpts.role, "role", "", "role to grant the privilege to")
+	accessGrantCmd.Flags().StringVar(&accessGrantOpts.user, "user", "", "user to grant the privilege to")
+	accessGrantCmd.MarkFlagsMutuallyExclusive("role", "user")
+	accessGrantCmd.MarkFlagsOneRequired("role", "user")
+
+	// Access revoke flags
+	accessRevokeCmd.Flags().StringVar(&accessRevokeOpts.role, "role", "", "role to revoke the grant from")
+	accessRevokeCmd.Flags().StringVar(&accessRevokeOpts.user, "user", "", "user to revoke the grant from")
+	accessRevokeCmd.MarkFlagsMutuallyExclusive("role", "user")
+	accessRevokeCmd.MarkFlagsOneRequired("role", "user")
+
+	// Access list flags
+	accessListCmd.Flags().StringVar(&accessListOpts.format, "format", "table", "output format: table, csv, json")
+}
+
+func runAccessGrant(cmd *cobra.Command, args []string) error {
+	privilege, err := grant.ParsePrivilege(args[0])
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	cat, grants, err := openGrantCatalog()
+	if err != nil {
+		return err
+	}
+	defer cat.Close()
+
+	namespace, tableName := parseGrantResource(args[1])
+	g := grant.Grant{Grantee: accessGrantOpts.grantee(), Namespace: namespace, Table: tableName, Privilege: privilege}
+	if err := grants.SetGrant(cmd.Context(), g); err != nil {
+		return fmt.Errorf("❌ Failed to grant %s: %w", privilege, err)
+	}
+
+	fmt.Printf("✅ Granted %s on %s to %s\n", privilege, g.Resource(), g.Grantee)
+	return nil
+}
+
+func runAccessRevoke(cmd *cobra.Command, args []string) error {
+	cat, grants, err := openGrantCatalog()
+	if err != nil {
+		return err
+	}
+	defer cat.Close()
+
+	grantee := accessRevokeOpts.grantee()
+	namespace, tableName := parseGrantResource(args[0])
+	if err := grants.RevokeGrant(cmd.Context(), grantee, namespace, tableName); err != nil {
+		if errors.Is(err, grant.ErrNoSuchGrant) {
+			return fmt.Errorf("❌ %s has no grant on %s\n"+
+				"💡 Use 'icebox access list' to see existing grants", grantee, args[0])
+		}
+		return fmt.Errorf("❌ Failed to revoke grant: %w", err)
+	}
+
+	fmt.Printf("✅ Revoked the grant on %s from %s\n", args[0], grantee)
+	return nil
+}
+
+func runAccessList(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	format, err := parseStatsFormat(accessListOpts.format)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	cat, grants, err := openGrantCatalog()
+	if err != nil {
+		return err
+	}
+	defer cat.Close()
+
+	list, err := grants.ListGrants(ctx)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to list grants: %w", err)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Grantee != list[j].Grantee {
+			return list[i].Grantee < list[j].Grantee
+		}
+		return list[i].Resource() < list[j].Resource()
+	})
+
+	if len(list) == 0 && format == display.FormatTable {
+		fmt.Println("📭 No grants found")
+		fmt.Println("💡 Use 'icebox access grant <privilege> <resource> --role <role>' to create one")
+		return nil
+	}
+
+	data := display.TableData{Headers: []string{"grantee", "resource", "privilege"}}
+	for _, g := range list {
+		data.Rows = append(data.Rows, []interface{}{g.Grantee, g.Resource(), string(g.Privilege)})
+	}
+
+	d := display.GetDisplayOrDefault(ctx)
+	if format != display.FormatTable {
+		return d.Table(data).WithFormat(format).Render()
+	}
+	return d.Table(data).WithTitle(fmt.Sprintf("🔐 Grants (%d)", len(data.Rows))).WithCompactMode().Render()
+}
+
+// grantee returns the grantee named by the --role or --user flag
+func (o *accessGranteeOptions) grantee() string {
+	if o.role != "" {
+		return grant.Role(o.role)
+	}
+	return grant.User(o.user)
+}
+
+// parseGrantResource splits a grant resource into its namespace and table. A resource
+// without a dot is a namespace; the last part of a dotted one is the table.
+func parseGrantResource(resource string) (namespace, tableName string) {
+	if resource == grant.AllNamespaces || !strings.Contains(resource, ".") {
+		return resource, ""
+	}
+	idx := strings.LastIndex(resource, ".")
+	return resource[:idx], resource[idx+1:]
+}
+
+// openGrantCatalog opens the project catalog and checks that it can store grants
+func openGrantCatalog() (catalog.CatalogInterface, catalog.GrantCatalog, error) {
+	_, cfg, err := config.FindConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("❌ Failed to find Icebox configuration\n"+
+			"💡 Try running 'icebox init' first to create a new project: %w", err)
+	}
+
+	cat, err := catalog.NewCatalog(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("❌ Failed to create catalog: %w", err)
+	}
+
+	grants, ok := cat.(catalog.GrantCatalog)
+	if !ok {
+		cat.Close()
+		return nil, nil, grantsUnsupported(cfg)
+	}
+
+	return cat, grants, nil
+}
+
+func grantsUnsupported(cfg *config.Config) error {
+	return fmt.Errorf("❌ The %s catalog cannot store access grants\n"+
+		"💡 Grants are available with the sqlite and json catalogs", cfg.Catalog.Type)
+}
+
+// errInvalidQuery is returned by accessControl.authorizeQuery for queries DuckDB cannot
+// parse; they are reported as failed queries rather than as denied ones
+var errInvalidQuery = errors.New("invalid query")
+
+// tableResolver resolves the tables and views a query reads
+type tableResolver interface {
+	ReferencedTables(ctx context.Context, query string) (*duckdb.TableReferences, error)
+	FilterCatalogViews(ctx context.Context, query string, visible func(table.Identifier) (bool, error)) (string, error)
+}
+
+// accessControl enforces the grants of the catalog on the principal of a request's
+// context. A nil accessControl allows everything, which is how servers run without --auth.
+type accessControl struct {
+	authorizer *auth.Authorizer
+	tables     tableResolver
+}
+
+// newAccessControl creates the access control of servers started with --auth
+func newAccessControl(cfg *config.Config, cat catalog.CatalogInterface, tables tableResolver) (*accessControl, error) {
+	grants, ok := cat.(catalog.GrantCatalog)
+	if !ok {
+		return nil, grantsUnsupported(cfg)
+	}
+	return &accessControl{authorizer: auth.NewAuthorizer(grants), tables: tables}, nil
+}
+
+// authorize checks the privilege on a table of a namespace. An empty table stands for
+// the namespace itself and an empty namespace for the whole catalog.
+func (ac *accessControl) authorize(ctx context.Context, privilege grant.Privilege, namespace table.Identifier, tableName string) error {
+	if ac == nil {
+		return nil
+	}
+	return ac.authorizer.Authorize(ctx, auth.FromContext(ctx), privilege, namespace, tableName)
+}
+
+// authorizeTable checks the privilege on a table or view
+func (ac *accessControl) authorizeTable(ctx context.Context, privilege grant.Privilege, ident table.Identifier) error {
+	return ac.authorize(ctx, privilege, ident[:len(ident)-1], ident[len(ident)-1])
+}
+
+// canAccess reports whether a namespace should be listed to the caller
+func (ac *accessControl) canAccess(ctx context.Context, namespace table.Identifier) (bool, error) {
+	if ac == nil {
+		return true, nil
+	}
+	return ac.authorizer.CanAccess(ctx, auth.FromContext(ctx), namespace)
+}
+
+// canRead reports whether a table should be listed to the caller
+func (ac *accessControl) canRead(ctx context.Context, ident table.Identifier) (bool, error) {
+	err := ac.authorizeTable(ctx, grant.Read, ident)
+	if errors.Is(err, auth.ErrPermissionDenied) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// authorizeName checks that the caller may query a table or view by its DuckDB name
+func (ac *accessControl) authorizeName(ctx context.Context, name string) error {
+	return ac.authorizeQuery(ctx, `SELECT * FROM "`+strings.ReplaceAll(name, `"`, `""`)+`"`)
+}
+
+// canQuery reports whether a table or view should be listed to the caller by its DuckDB
+// name
+func (ac *accessControl) canQuery(ctx context.Context, name string) (bool, error) {
+	err := ac.authorizeName(ctx, name)
+	if errors.Is(err, auth.ErrPermissionDenied) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// authorizeJob checks the privilege on the table an import job writes to
+func (ac *accessControl) authorizeJob(ctx context.Context, privilege grant.Privilege, job jobs.Job) error {
+	if ac == nil {
+		return nil
+	}
+	ident := strings.Split(job.Params["table"], ".")
+	if len(ident) < 2 {
+		// Jobs that do not name a table are only visible to catalog admins
+		return ac.authorize(ctx, grant.Admin, nil, "")
+	}
+	return ac.authorizeTable(ctx, privilege, ident)
+}
+
+// authorizeFileImport checks that the caller may import a file the server reads itself
+// into a table. Such a path can name any file of the server or any URL the project's
+// credentials reach, so it needs admin on the whole catalog like queries reading files.
+// Clients with write on the table alone import by uploading the file.
+func (ac *accessControl) authorizeFileImport(ctx context.Context, ident table.Identifier, path string) error {
+	if ac == nil {
+		return nil
+	}
+	if err := ac.authorize(ctx, grant.Admin, nil, ""); err != nil {
+		return fmt.Errorf("%w (the import reads %s)", err, path)
+	}
+	return ac.authorizeTable(ctx, grant.Write, ident)
+}
+
+// authorizeTableLocation checks that the caller may create a table at a location of
+// their choosing. The location can name any directory of the server or any bucket the
+// project's credentials reach, so it needs admin on the whole catalog like importing
+// files the server reads. Tables created without one go under the warehouse.
+func (ac *accessControl) authorizeTableLocation(ctx context.Context, ident table.Identifier, location string) error {
+	if ac == nil {
+		return nil
+	}
+	if err := ac.authorize(ctx, grant.Admin, nil, ""); err != nil {
+		return fmt.Errorf("%w (the table would be written to %s)", err, location)
+	}
+	return ac.authorizeTable(ctx, grant.Write, ident)
+}
+
+// authorizeQuery checks that the caller may read every table and view a query reads.
+// The tables are resolved from DuckDB's parse tree. Statements other than queries and
+// queries reading files, other databases or DuckDB's state may touch anything, so they
+// need admin on the whole catalog. So do queries of DuckDB's catalog views, such as
+// information_schema.tables or pg_catalog.pg_class, which list the tables of every
+// namespace.
+func (ac *accessControl) authorizeQuery(ctx context.Context, query string) error {
+	if ac == nil {
+		return nil
+	}
+	_, err := ac.authorizeReads(ctx, query, false)
+	return err
+}
+
+// authorizeCatalogQuery is authorizeQuery for clients that look up tables through
+// DuckDB's catalog views, such as PostgreSQL drivers and BI tools. Callers without
+// admin on the whole catalog may query the views too, but only see the rows about the
+// tables and views they may read. It returns the query to run in place of query.
+func (ac *accessControl) authorizeCatalogQuery(ctx context.Context, query string) (string, error) {
+	if ac == nil {
+		return query, nil
+	}
+	refs, err := ac.authorizeReads(ctx, query, true)
+	if err != nil || refs == nil || len(refs.CatalogViews) == 0 {
+		return query, err
+	}
+	if err := ac.authorize(ctx, grant.Admin, nil, ""); err == nil {
+		return query, nil
+	}
+	return ac.tables.FilterCatalogViews(ctx, query, func(ident table.Identifier) (bool, error) {
+		return ac.canRead(ctx, ident)
+	})
+}
+
+// authorizeReads checks the reads of a query for authorizeQuery and returns them, or
+// nil for statements other than queries. With catalogViews, the catalog views a query
+// reads are left to be filtered rather than needing admin.
+func (ac *accessControl) authorizeReads(ctx context.Context, query string, catalogViews bool) (*duckdb.TableReferences, error) {
+	refs, err := ac.tables.ReferencedTables(ctx, query)
+	if errors.Is(err, duckdb.ErrUnresolvableStatement) {
+		return nil, ac.authorize(ctx, grant.Admin, nil, "")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidQuery, err)
+	}
+
+	external := refs.External()
+	if catalogViews {
+		external = slices.DeleteFunc(external, func(name string) bool {
+			return slices.Contains(refs.CatalogViews, name)
+		})
+	}
+	if len(external) > 0 {
+		if err := ac.authorize(ctx, grant.Admin, nil, ""); err != nil {
+			return nil, fmt.Errorf("%w (the query reads %s)", err, external[0])
+		}
+	}
+	for _, ident := range refs.Tables {
+		if err := ac.authorizeTable(ctx, grant.Read, ident); err != nil {
+			return nil, err
+		}
+	}
+	return refs, nil
+}
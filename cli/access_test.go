@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/TFMV/icebox/auth"
+	"github.com/TFMV/icebox/catalog/grant"
+	"github.com/TFMV/icebox/engine/duckdb"
+	"github.com/TFMV/icebox/jobs"
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTableResolver resolves every query to the same references or error. It filters
+// catalog views by returning the names of the tables of catalog that are visible.
+type fakeTableResolver struct {
+	refs    *duckdb.TableReferences
+	err     error
+	catalog []table.Identifier
+}
+
+func (r fakeTableResolver) ReferencedTables(ctx context.Context, query string) (*duckdb.TableReferences, error) {
+	return r.refs, r.err
+}
+
+func (r fakeTableResolver) FilterCatalogViews(ctx context.Context, query string, visible func(table.Identifier) (bool, error)) (string, error) {
+	var names []string
+	for _, ident := range r.catalog {
+		ok, err := visible(ident)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			names = append(names, strings.Join(ident, "."))
+		}
+	}
+	return "visible: " + strings.Join(names, ", "), nil
+}
+
+// staticGrants is a fixed set of grants
+type staticGrants []grant.Grant
+
+func (g staticGrants) ListGrants(ctx context.Context) ([]grant.Grant, error) {
+	return g, nil
+}
+
+func TestAuthorizeQuery(t *testing.T) {
+	grants := staticGrants{
+		{Grantee: grant.Role("analyst"), Namespace: "sales", Privilege: grant.Read},
+		{Grantee: grant.User("root"), Namespace: grant.AllNamespaces, Privilege: grant.Admin},
+	}
+	analyst := auth.WithPrincipal(context.Background(), &auth.Principal{Name: "alice", Roles: []string{"analyst"}})
+	root := auth.WithPrincipal(context.Background(), &auth.Principal{Name: "root"})
+
+	tests := map[string]struct {
+		resolver      fakeTableResolver
+		analystDenied bool
+	}{
+		"readable tables": {
+			resolver: fakeTableResolver{refs: &duckdb.TableReferences{Tables: []table.Identifier{{"sales", "orders"}, {"sales", "returns"}}}},
+		},
+		"generated rows": {
+			resolver: fakeTableResolver{refs: &duckdb.TableReferences{Functions: []string{"range", "duckdb_keywords"}}},
+		},
+		"DuckDB's catalog": {
+			resolver:      fakeTableResolver{refs: &duckdb.TableReferences{Functions: []string{"duckdb_tables"}}},
+			analystDenied: true,
+		},
+		"DuckDB's catalog views": {
+			resolver:      fakeTableResolver{refs: &duckdb.TableReferences{Tables: []table.Identifier{{"sales", "orders"}}, Unresolved: []string{"information_schema.tables"}}},
+			analystDenied: true,
+		},
+		"table of another namespace": {
+			resolver:      fakeTableResolver{refs: &duckdb.TableReferences{Tables: []table.Identifier{{"sales", "orders"}, {"hr", "salaries"}}}},
+			analystDenied: true,
+		},
+		"file read": {
+			resolver:      fakeTableResolver{refs: &duckdb.TableReferences{Functions: []string{"read_parquet"}}},
+			analystDenied: true,
+		},
+		"replacement scan of a file": {
+			resolver:      fakeTableResolver{refs: &duckdb.TableReferences{Files: []string{"/etc/data.csv"}}},
+			analystDenied: true,
+		},
+		"statement other than a query": {
+			resolver:      fakeTableResolver{err: duckdb.ErrUnresolvableStatement},
+			analystDenied: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ac := &accessControl{authorizer: auth.NewAuthorizer(grants), tables: tt.resolver}
+
+			err := ac.authorizeQuery(analyst, "SELECT 1")
+			if tt.analystDenied {
+				assert.ErrorIs(t, err, auth.ErrPermissionDenied)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, ac.authorizeQuery(root, "SELECT 1"))
+		})
+	}
+
+	// Queries that fail to parse are reported as invalid rather than denied
+	ac := &accessControl{authorizer: auth.NewAuthorizer(grants), tables: fakeTableResolver{err: errors.New("Parser Error")}}
+	err := ac.authorizeQuery(root, "SELEC 1")
+	assert.ErrorIs(t, err, errInvalidQuery)
+	assert.NotErrorIs(t, err, auth.ErrPermissionDenied)
+
+	// Without authentication there is no access control
+	var disabled *accessControl
+	assert.NoError(t, disabled.authorizeQuery(context.Background(), "DROP TABLE orders"))
+	require.NoError(t, disabled.authorizeJob(context.Background(), grant.Write, jobs.Job{}))
+}
+
+func TestAuthorizeCatalogQuery(t *testing.T) {
+	grants := staticGrants{
+		{Grantee: grant.Role("analyst"), Namespace: "sales", Privilege: grant.Read},
+		{Grantee: grant.User("root"), Namespace: grant.AllNamespaces, Privilege: grant.Admin},
+	}
+	analyst := auth.WithPrincipal(context.Background(), &auth.Principal{Name: "alice", Roles: []string{"analyst"}})
+	root := auth.WithPrincipal(context.Background(), &auth.Principal{Name: "root"})
+	ac := &accessControl{authorizer: auth.NewAuthorizer(grants), tables: fakeTableResolver{
+		refs:    &duckdb.TableReferences{Unresolved: []string{"pg_class"}, CatalogViews: []string{"pg_class"}},
+		catalog: []table.Identifier{{"sales", "orders"}, {"hr", "salaries"}},
+	}}
+
+	// The catalog views only list the tables the caller may read, unless they are admin
+	query, err := ac.authorizeCatalogQuery(analyst, "SELECT relname FROM pg_class")
+	require.NoError(t, err)
+	assert.Equal(t, "visible: sales.orders", query)
+	query, err = ac.authorizeCatalogQuery(root, "SELECT relname FROM pg_class")
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT relname FROM pg_class", query)
+	assert.ErrorIs(t, ac.authorizeQuery(analyst, "SELECT relname FROM pg_class"), auth.ErrPermissionDenied)
+
+	// The query's other reads are checked as usual
+	ac.tables = fakeTableResolver{refs: &duckdb.TableReferences{
+		Unresolved:   []string{"pg_class", "pg_settings"},
+		CatalogViews: []string{"pg_class"},
+	}}
+	_, err = ac.authorizeCatalogQuery(analyst, "SELECT * FROM pg_class, pg_settings")
+	assert.ErrorIs(t, err, auth.ErrPermissionDenied)
+	ac.tables = fakeTableResolver{refs: &duckdb.TableReferences{
+		Tables:       []table.Identifier{{"hr", "salaries"}},
+		Unresolved:   []string{"pg_class"},
+		CatalogViews: []string{"pg_class"},
+	}}
+	_, err = ac.authorizeCatalogQuery(analyst, "SELECT * FROM pg_class, hr_salaries")
+	assert.ErrorIs(t, err, auth.ErrPermissionDenied)
+}
+
+func TestAuthorizeQuerySystemCatalog(t *testing.T) {
+	_, api := newTestRESTServer(t)
+	engine, err := duckdb.NewEngine(api.catalog)
+	if err != nil {
+		t.Skipf("DuckDB engine unavailable: %v", err)
+	}
+	defer engine.Close()
+
+	grants := staticGrants{
+		{Grantee: grant.Role("analyst"), Namespace: "sales", Privilege: grant.Read},
+		{Grantee: grant.User("root"), Namespace: grant.AllNamespaces, Privilege: grant.Admin},
+	}
+	ac := &accessControl{authorizer: auth.NewAuthorizer(grants), tables: engine}
+	analyst := auth.WithPrincipal(context.Background(), &auth.Principal{Name: "alice", Roles: []string{"analyst"}})
+	root := auth.WithPrincipal(context.Background(), &auth.Principal{Name: "root"})
+
+	// A grant on one namespace does not reveal the names of the others
+	for _, query := range []string{
+		"SELECT table_schema, table_name FROM information_schema.tables",
+		"SELECT relname FROM pg_catalog.pg_class",
+		"SELECT sql FROM duckdb_views",
+		"SELECT * FROM duckdb_tables()",
+	} {
+		assert.ErrorIs(t, ac.authorizeQuery(analyst, query), auth.ErrPermissionDenied, query)
+		assert.NoError(t, ac.authorizeQuery(root, query), query)
+	}
+
+	// Catalog queries of PostgreSQL clients get the catalog views filtered instead, but
+	// DuckDB's own tables and functions stay with admins
+	for _, query := range []string{
+		"SELECT table_schema, table_name FROM information_schema.tables",
+		"SELECT relname FROM pg_catalog.pg_class",
+	} {
+		filtered, err := ac.authorizeCatalogQuery(analyst, query)
+		require.NoError(t, err, query)
+		assert.NotEqual(t, query, filtered)
+	}
+	for _, query := range []string{"SELECT sql FROM duckdb_views", "SELECT * FROM duckdb_tables()"} {
+		_, err := ac.authorizeCatalogQuery(analyst, query)
+		assert.ErrorIs(t, err, auth.ErrPermissionDenied, query)
+	}
+}
+
+func TestAuthorizeJob(t *testing.T) {
+	ac := &accessControl{authorizer: auth.NewAuthorizer(staticGrants{
+		{Grantee: grant.User("etl"), Namespace: "sales", Table: "orders", Privilege: grant.Write},
+	})}
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Name: "etl"})
+
+	assert.NoError(t, ac.authorizeJob(ctx, grant.Write, jobs.Job{Params: map[string]string{"table": "sales.orders"}}))
+	assert.ErrorIs(t, ac.authorizeJob(ctx, grant.Read, jobs.Job{Params: map[string]string{"table": "sales.returns"}}), auth.ErrPermissionDenied)
+	assert.ErrorIs(t, ac.authorizeJob(ctx, grant.Read, jobs.Job{}), auth.ErrPermissionDenied)
+}
+
+func TestParseGrantResource(t *testing.T) {
+	tests := map[string][2]string{
+		"*":                   {"*", ""},
+		"sales":               {"sales", ""},
+		"sales.orders":        {"sales", "orders"},
+		"analytics.eu.events": {"analytics.eu", "events"},
+	}
+	for resource, want := range tests {
+		namespace, tableName := parseGrantResource(resource)
+		assert.Equal(t, want, [2]string{namespace, tableName}, resource)
+	}
+}
@@ -104,6 +104,13 @@ func runDemo(cmd *cobra.Command, args []string) error {
 		return listDemoDatasets()
 	}
 
+	// Make the embedded MinIO server reachable for projects that use it
+	stopStorage, err := startStorage(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	defer stopStorage()
+
 	// Handle cleanup option
 	if demoOpts.cleanup {
 		return cleanupDemoDatasets(cfg)
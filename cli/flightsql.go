@@ -0,0 +1,600 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TFMV/icebox/catalog"
+	"github.com/TFMV/icebox/engine/duckdb"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	"github.com/apache/arrow-go/v18/arrow/flight/flightsql"
+	"github.com/apache/arrow-go/v18/arrow/flight/flightsql/schema_ref"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/iceberg-go/table"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// flightSQLServer serves the SQL engine over Arrow Flight SQL. Tables are listed by the
+// names they are queried by: the engine's DuckDB database is the only catalog and the
+// namespaces are its schemas.
+type flightSQLServer struct {
+	flightsql.BaseServer
+	catalog catalog.CatalogInterface
+	engine  *duckdb.Engine
+	access  *accessControl // nil when authentication is disabled
+
+	// prepared holds the open prepared statements by handle
+	preparedMu sync.Mutex
+	prepared   map[string]*preparedStatement
+}
+
+const (
+	// preparedStatementTTL is how long a prepared statement may go unused before it is
+	// closed, as Flight SQL has no sessions that would close it when a client goes away
+	preparedStatementTTL = 30 * time.Minute
+	// maxPreparedStatements caps the open prepared statements; preparing another one
+	// closes the least recently used
+	maxPreparedStatements = 1024
+)
+
+// preparedStatement is a query prepared by a client, with the parameter sets bound to it
+type preparedStatement struct {
+	query string
+
+	mu       sync.Mutex
+	params   [][]interface{}
+	lastUsed time.Time // guarded by the server's preparedMu
+}
+
+func newFlightSQLServer(cat catalog.CatalogInterface, engine *duckdb.Engine) (*flightSQLServer, error) {
+	srv := &flightSQLServer{
+		catalog:  cat,
+		engine:   engine,
+		prepared: make(map[string]*preparedStatement),
+	}
+	srv.Alloc = memory.DefaultAllocator
+
+	info := map[flightsql.SqlInfo]interface{}{
+		flightsql.SqlInfoFlightSqlServerName:         "Icebox",
+		flightsql.SqlInfoFlightSqlServerVersion:      rootCmd.Version,
+		flightsql.SqlInfoFlightSqlServerArrowVersion: "18",
+		flightsql.SqlInfoFlightSqlServerReadOnly:     false,
+		flightsql.SqlInfoFlightSqlServerSql:          true,
+		flightsql.SqlInfoFlightSqlServerSubstrait:    false,
+		flightsql.SqlInfoFlightSqlServerTransaction:  int32(0), // transactions are not supported
+	}
+	for id, value := range info {
+		if err := srv.RegisterSqlInfo(id, value); err != nil {
+			return nil, err
+		}
+	}
+	return srv, nil
+}
+
+// GetFlightInfoStatement returns a ticket carrying the query, which DoGetStatement runs
+func (s *flightSQLServer) GetFlightInfoStatement(ctx context.Context, cmd flightsql.StatementQuery, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	if len(cmd.GetTransactionId()) > 0 {
+		return nil, status.Error(codes.Unimplemented, "transactions are not supported")
+	}
+	if strings.TrimSpace(cmd.GetQuery()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+	if err := s.access.authorizeQuery(ctx, cmd.GetQuery()); err != nil {
+		return nil, grpcError(err)
+	}
+
+	ticket, err := flightsql.CreateStatementQueryTicket([]byte(cmd.GetQuery()))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create ticket: %v", err)
+	}
+	return &flight.FlightInfo{
+		Endpoint:         []*flight.FlightEndpoint{{Ticket: &flight.Ticket{Ticket: ticket}}},
+		FlightDescriptor: desc,
+		TotalRecords:     -1,
+		TotalBytes:       -1,
+	}, nil
+}
+
+func (s *flightSQLServer) DoGetStatement(ctx context.Context, ticket flightsql.StatementQueryTicket) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	// Tickets carry the query itself, so it is checked again when it runs
+	query := string(ticket.GetStatementHandle())
+	if err := s.access.authorizeQuery(ctx, query); err != nil {
+		return nil, nil, grpcError(err)
+	}
+	reader, err := s.engine.ExecuteQueryStream(ctx, query)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "query failed: %v", err)
+	}
+	return reader.Schema(), s.streamRecords(ctx, reader, nil), nil
+}
+
+func (s *flightSQLServer) DoPutCommandStatementUpdate(ctx context.Context, cmd flightsql.StatementUpdate) (int64, error) {
+	if len(cmd.GetTransactionId()) > 0 {
+		return 0, status.Error(codes.Unimplemented, "transactions are not supported")
+	}
+	if err := s.access.authorizeQuery(ctx, cmd.GetQuery()); err != nil {
+		return 0, grpcError(err)
+	}
+	result, err := s.engine.ExecuteQuery(ctx, cmd.GetQuery())
+	if err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "statement failed: %v", err)
+	}
+	if err := completeResult(result); err != nil {
+		return 0, grpcError(err)
+	}
+	return affectedRows(result), nil
+}
+
+func (s *flightSQLServer) CreatePreparedStatement(ctx context.Context, req flightsql.ActionCreatePreparedStatementRequest) (flightsql.ActionCreatePreparedStatementResult, error) {
+	var result flightsql.ActionCreatePreparedStatementResult
+	if len(req.GetTransactionId()) > 0 {
+		return result, status.Error(codes.Unimplemented, "transactions are not supported")
+	}
+	if strings.TrimSpace(req.GetQuery()) == "" {
+		return result, status.Error(codes.InvalidArgument, "query is required")
+	}
+	if err := s.access.authorizeQuery(ctx, req.GetQuery()); err != nil {
+		return result, grpcError(err)
+	}
+
+	handle := uuid.NewString()
+	s.addPreparedStatement(handle, &preparedStatement{query: req.GetQuery()}, time.Now())
+	result.Handle = []byte(handle)
+	return result, nil
+}
+
+func (s *flightSQLServer) ClosePreparedStatement(ctx context.Context, req flightsql.ActionClosePreparedStatementRequest) error {
+	s.preparedMu.Lock()
+	defer s.preparedMu.Unlock()
+
+	handle := string(req.GetPreparedStatementHandle())
+	if _, ok := s.prepared[handle]; !ok {
+		return status.Error(codes.InvalidArgument, "prepared statement not found")
+	}
+	delete(s.prepared, handle)
+	return nil
+}
+
+func (s *flightSQLServer) GetFlightInfoPreparedStatement(ctx context.Context, cmd flightsql.PreparedStatementQuery, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	if _, err := s.preparedStatement(cmd.GetPreparedStatementHandle()); err != nil {
+		return nil, err
+	}
+	return &flight.FlightInfo{
+		Endpoint:         []*flight.FlightEndpoint{{Ticket: &flight.Ticket{Ticket: desc.Cmd}}},
+		FlightDescriptor: desc,
+		TotalRecords:     -1,
+		TotalBytes:       -1,
+	}, nil
+}
+
+// DoPutPreparedStatementQuery binds the parameter sets of the uploaded records to a
+// prepared statement; executing it then runs the query once per set
+func (s *flightSQLServer) DoPutPreparedStatementQuery(ctx context.Context, cmd flightsql.PreparedStatementQuery, reader flight.MessageReader, _ flight.MetadataWriter) ([]byte, error) {
+	stmt, err := s.preparedStatement(cmd.GetPreparedStatementHandle())
+	if err != nil {
+		return nil, err
+	}
+	params, err := readParameters(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt.mu.Lock()
+	stmt.params = params
+	stmt.mu.Unlock()
+	return cmd.GetPreparedStatementHandle(), nil
+}
+
+// DoGetPreparedStatement streams the results of the statement like DoGetStatement,
+// running it once per bound parameter set, or once without parameters when none are
+// bound. Each run starts once the previous one's results are sent.
+func (s *flightSQLServer) DoGetPreparedStatement(ctx context.Context, cmd flightsql.PreparedStatementQuery) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	stmt, err := s.preparedStatement(cmd.GetPreparedStatementHandle())
+	if err != nil {
+		return nil, nil, err
+	}
+	// The caller's grants may have changed since the statement was prepared
+	if err := s.access.authorizeQuery(ctx, stmt.query); err != nil {
+		return nil, nil, grpcError(err)
+	}
+
+	params := stmt.parameterSets()
+	reader, err := s.engine.ExecuteQueryStream(ctx, stmt.query, params[0]...)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "query failed: %v", err)
+	}
+	params = params[1:]
+	next := func() (array.RecordReader, error) {
+		if len(params) == 0 {
+			return nil, nil
+		}
+		args := params[0]
+		params = params[1:]
+		return s.engine.ExecuteQueryStream(ctx, stmt.query, args...)
+	}
+	return reader.Schema(), s.streamRecords(ctx, reader, next), nil
+}
+
+func (s *flightSQLServer) DoPutPreparedStatementUpdate(ctx context.Context, cmd flightsql.PreparedStatementUpdate, reader flight.MessageReader) (int64, error) {
+	stmt, err := s.preparedStatement(cmd.GetPreparedStatementHandle())
+	if err != nil {
+		return 0, err
+	}
+
+	// Parameters sent with the update replace those bound earlier
+	if reader != nil {
+		params, err := readParameters(reader)
+		if err != nil {
+			return 0, err
+		}
+		if len(params) > 0 {
+			stmt.mu.Lock()
+			stmt.params = params
+			stmt.mu.Unlock()
+		}
+	}
+
+	var affected int64
+	err = stmt.execute(ctx, s.engine, s.access, func(result *duckdb.QueryResult) {
+		affected += affectedRows(result)
+	})
+	return affected, err
+}
+
+// addPreparedStatement opens a prepared statement under handle. Statements unused for
+// preparedStatementTTL are closed first, and the least recently used one when
+// maxPreparedStatements are still open.
+func (s *flightSQLServer) addPreparedStatement(handle string, stmt *preparedStatement, now time.Time) {
+	s.preparedMu.Lock()
+	defer s.preparedMu.Unlock()
+
+	var oldest string
+	for h, open := range s.prepared {
+		if now.Sub(open.lastUsed) > preparedStatementTTL {
+			delete(s.prepared, h)
+			continue
+		}
+		if oldest == "" || open.lastUsed.Before(s.prepared[oldest].lastUsed) {
+			oldest = h
+		}
+	}
+	if len(s.prepared) >= maxPreparedStatements {
+		delete(s.prepared, oldest)
+	}
+
+	stmt.lastUsed = now
+	s.prepared[handle] = stmt
+}
+
+// preparedStatement looks up an open prepared statement
+func (s *flightSQLServer) preparedStatement(handle []byte) (*preparedStatement, error) {
+	s.preparedMu.Lock()
+	defer s.preparedMu.Unlock()
+
+	stmt, ok := s.prepared[string(handle)]
+	if !ok || time.Since(stmt.lastUsed) > preparedStatementTTL {
+		return nil, status.Error(codes.InvalidArgument, "prepared statement not found")
+	}
+	stmt.lastUsed = time.Now()
+	return stmt, nil
+}
+
+// execute runs a statement that updates tables once per bound parameter set, or once
+// without parameters when none are bound. Results the engine truncated are refused
+// rather than returned in part. The caller's grants are checked again, as they may have changed
+// since the statement was prepared.
+func (p *preparedStatement) execute(ctx context.Context, engine *duckdb.Engine, access *accessControl, handle func(*duckdb.QueryResult)) error {
+	if err := access.authorizeQuery(ctx, p.query); err != nil {
+		return grpcError(err)
+	}
+
+	for _, args := range p.parameterSets() {
+		result, err := engine.ExecuteQuery(ctx, p.query, args...)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "query failed: %v", err)
+		}
+		if err := completeResult(result); err != nil {
+			return grpcError(err)
+		}
+		handle(result)
+	}
+	return nil
+}
+
+// parameterSets returns the bound parameter sets, or one empty set when none are bound
+func (p *preparedStatement) parameterSets() [][]interface{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.params) == 0 {
+		return [][]interface{}{nil}
+	}
+	return p.params
+}
+
+func (s *flightSQLServer) GetFlightInfoCatalogs(ctx context.Context, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	return s.flightInfoForCommand(desc, schema_ref.Catalogs), nil
+}
+
+func (s *flightSQLServer) DoGetCatalogs(ctx context.Context) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	builder := array.NewRecordBuilder(s.Alloc, schema_ref.Catalogs)
+	defer builder.Release()
+
+	builder.Field(0).(*array.StringBuilder).Append(duckdb.DatabaseName)
+	return schema_ref.Catalogs, singleChunk(builder.NewRecord()), nil
+}
+
+func (s *flightSQLServer) GetFlightInfoSchemas(ctx context.Context, cmd flightsql.GetDBSchemas, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	return s.flightInfoForCommand(desc, schema_ref.DBSchemas), nil
+}
+
+func (s *flightSQLServer) DoGetDBSchemas(ctx context.Context, cmd flightsql.GetDBSchemas) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	builder := array.NewRecordBuilder(s.Alloc, schema_ref.DBSchemas)
+	defer builder.Release()
+
+	namespaces, err := s.namespaces(ctx, cmd.GetCatalog(), cmd.GetDBSchemaFilterPattern())
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, namespace := range namespaces {
+		builder.Field(0).(*array.StringBuilder).Append(duckdb.DatabaseName)
+		builder.Field(1).(*array.StringBuilder).Append(strings.Join(namespace, "."))
+	}
+	return schema_ref.DBSchemas, singleChunk(builder.NewRecord()), nil
+}
+
+func (s *flightSQLServer) GetFlightInfoTables(ctx context.Context, cmd flightsql.GetTables, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	if cmd.GetIncludeSchema() {
+		return s.flightInfoForCommand(desc, schema_ref.TablesWithIncludedSchema), nil
+	}
+	return s.flightInfoForCommand(desc, schema_ref.Tables), nil
+}
+
+func (s *flightSQLServer) DoGetTables(ctx context.Context, cmd flightsql.GetTables) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	schema := schema_ref.Tables
+	if cmd.GetIncludeSchema() {
+		schema = schema_ref.TablesWithIncludedSchema
+	}
+	builder := array.NewRecordBuilder(s.Alloc, schema)
+	defer builder.Release()
+
+	// Iceberg catalogs hold tables only, so a filter without TABLE matches nothing
+	if types := cmd.GetTableTypes(); len(types) > 0 && !containsFold(types, "TABLE") {
+		return schema, singleChunk(builder.NewRecord()), nil
+	}
+
+	namespaces, err := s.namespaces(ctx, cmd.GetCatalog(), cmd.GetDBSchemaFilterPattern())
+	if err != nil {
+		return nil, nil, err
+	}
+	nameFilter := likePattern(cmd.GetTableNameFilterPattern())
+
+	for _, namespace := range namespaces {
+		for ident, err := range s.catalog.ListTables(ctx, namespace) {
+			if err != nil {
+				return nil, nil, grpcError(err)
+			}
+			name := ident[len(ident)-1]
+			if nameFilter != nil && !nameFilter.MatchString(name) {
+				continue
+			}
+			readable, err := s.access.canRead(ctx, ident)
+			if err != nil {
+				return nil, nil, grpcError(err)
+			}
+			if !readable {
+				continue
+			}
+
+			builder.Field(0).(*array.StringBuilder).Append(duckdb.DatabaseName)
+			builder.Field(1).(*array.StringBuilder).Append(strings.Join(namespace, "."))
+			builder.Field(2).(*array.StringBuilder).Append(name)
+			builder.Field(3).(*array.StringBuilder).Append("TABLE")
+			if cmd.GetIncludeSchema() {
+				tableSchema, err := s.tableArrowSchema(ctx, ident)
+				if err != nil {
+					return nil, nil, err
+				}
+				builder.Field(4).(*array.BinaryBuilder).Append(flight.SerializeSchema(tableSchema, s.Alloc))
+			}
+		}
+	}
+	return schema, singleChunk(builder.NewRecord()), nil
+}
+
+func (s *flightSQLServer) GetFlightInfoTableTypes(ctx context.Context, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	return s.flightInfoForCommand(desc, schema_ref.TableTypes), nil
+}
+
+func (s *flightSQLServer) DoGetTableTypes(ctx context.Context) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	builder := array.NewRecordBuilder(s.Alloc, schema_ref.TableTypes)
+	defer builder.Release()
+
+	builder.Field(0).(*array.StringBuilder).Append("TABLE")
+	return schema_ref.TableTypes, singleChunk(builder.NewRecord()), nil
+}
+
+// namespaces lists the namespaces of the catalog matching a GetDbSchemas or GetTables
+// request that the caller may access
+func (s *flightSQLServer) namespaces(ctx context.Context, catalogName, schemaPattern *string) ([]table.Identifier, error) {
+	if catalogName != nil && *catalogName != "" && *catalogName != duckdb.DatabaseName {
+		return nil, nil
+	}
+
+	namespaces, err := s.catalog.ListNamespaces(ctx, nil)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	filter := likePattern(schemaPattern)
+	var matched []table.Identifier
+	for _, namespace := range namespaces {
+		if filter != nil && !filter.MatchString(strings.Join(namespace, ".")) {
+			continue
+		}
+		ok, err := s.access.canAccess(ctx, namespace)
+		if err != nil {
+			return nil, grpcError(err)
+		}
+		if ok {
+			matched = append(matched, namespace)
+		}
+	}
+	return matched, nil
+}
+
+// tableArrowSchema returns the Arrow schema of a table's current Iceberg schema
+func (s *flightSQLServer) tableArrowSchema(ctx context.Context, ident table.Identifier) (*arrow.Schema, error) {
+	tbl, err := s.catalog.LoadTable(ctx, ident, nil)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	schema, err := table.SchemaToArrowSchema(tbl.Schema(), nil, false, false)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to convert schema of %s: %v", strings.Join(ident, "."), err)
+	}
+	return schema, nil
+}
+
+func (s *flightSQLServer) flightInfoForCommand(desc *flight.FlightDescriptor, schema *arrow.Schema) *flight.FlightInfo {
+	return &flight.FlightInfo{
+		Endpoint:         []*flight.FlightEndpoint{{Ticket: &flight.Ticket{Ticket: desc.Cmd}}},
+		FlightDescriptor: desc,
+		Schema:           flight.SerializeSchema(schema, s.Alloc),
+		TotalRecords:     -1,
+		TotalBytes:       -1,
+	}
+}
+
+// streamRecords sends the record batches of streamed query results and releases each
+// reader once its batches are sent. next, if not nil, opens the reader of the next
+// result, returning nil after the last one; every result must have the schema of the
+// first. The stream stops when ctx is done.
+func (s *flightSQLServer) streamRecords(ctx context.Context, reader array.RecordReader, next func() (array.RecordReader, error)) <-chan flight.StreamChunk {
+	chunks := make(chan flight.StreamChunk)
+	schema := reader.Schema()
+
+	sendError := func(err error) {
+		select {
+		case chunks <- flight.StreamChunk{Err: status.Errorf(codes.InvalidArgument, "query failed: %v", err)}:
+		case <-ctx.Done():
+		}
+	}
+
+	go func() {
+		defer close(chunks)
+		for reader != nil {
+			for reader.Next() {
+				// The server releases each record it sends
+				record := reader.Record()
+				record.Retain()
+				select {
+				case chunks <- flight.StreamChunk{Data: record}:
+				case <-ctx.Done():
+					record.Release()
+					reader.Release()
+					return
+				}
+			}
+			err := reader.Err()
+			reader.Release()
+			if err != nil {
+				sendError(err)
+				return
+			}
+
+			if next == nil {
+				return
+			}
+			if reader, err = next(); err != nil {
+				sendError(err)
+				return
+			}
+			if reader != nil && !reader.Schema().Equal(schema) {
+				reader.Release()
+				sendError(errors.New("the parameters changed the schema of the results"))
+				return
+			}
+		}
+	}()
+	return chunks
+}
+
+// singleChunk returns a stream of one record
+func singleChunk(record arrow.Record) <-chan flight.StreamChunk {
+	chunks := make(chan flight.StreamChunk, 1)
+	chunks <- flight.StreamChunk{Data: record}
+	close(chunks)
+	return chunks
+}
+
+// readParameters reads the parameter sets uploaded for a prepared statement, one per row
+func readParameters(reader flight.MessageReader) ([][]interface{}, error) {
+	var params [][]interface{}
+	for reader.Next() {
+		record := reader.Record()
+		for row := 0; row < int(record.NumRows()); row++ {
+			args := make([]interface{}, record.NumCols())
+			for col := range args {
+				args[col] = duckdb.ArrowValue(record.Column(col), row)
+			}
+			params = append(params, args)
+		}
+	}
+	if err := reader.Err(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to read parameters: %v", err)
+	}
+	return params, nil
+}
+
+// affectedRows returns the row count DuckDB reports for an INSERT, UPDATE or DELETE, or
+// zero for other statements
+func affectedRows(result *duckdb.QueryResult) int64 {
+	if len(result.Columns) != 1 || result.RowCount != 1 {
+		return 0
+	}
+	if count, ok := result.Rows()[0][0].(int64); ok {
+		return count
+	}
+	return 0
+}
+
+// likePattern compiles a Flight SQL filter pattern, in which % matches any sequence of
+// characters and _ any one character. It returns nil when the pattern matches everything.
+func likePattern(pattern *string) *regexp.Regexp {
+	if pattern == nil || *pattern == "" || *pattern == "%" {
+		return nil
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	for _, r := range *pattern {
+		switch r {
+		case '%':
+			expr.WriteString(".*")
+		case '_':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,358 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TFMV/icebox/catalog/sqlite"
+	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/engine/duckdb"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	"github.com/apache/arrow-go/v18/arrow/flight/flightsql"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestFlightSQLServer serves Flight SQL over an in-memory connection, backed by a
+// SQLite catalog in a temporary directory holding the sales.orders and sales.customers
+// tables, which are registered with the SQL engine. The engine is nil when DuckDB is
+// unavailable.
+func newTestFlightSQLServer(t *testing.T) (*flightsql.Client, *duckdb.Engine) {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		Name: "test-catalog",
+		Catalog: config.CatalogConfig{
+			Type:   "sqlite",
+			SQLite: &config.SQLiteConfig{Path: filepath.Join(tempDir, "catalog.db")},
+		},
+		Storage: config.StorageConfig{
+			Type:       "fs",
+			FileSystem: &config.FileSystemConfig{RootPath: filepath.Join(tempDir, "data")},
+		},
+	}
+
+	cat, err := sqlite.NewCatalog(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { cat.Close() })
+
+	ctx := context.Background()
+	require.NoError(t, cat.CreateNamespace(ctx, table.Identifier{"sales"}, iceberg.Properties{}))
+	for _, name := range []string{"orders", "customers"} {
+		_, err := cat.CreateTable(ctx, table.Identifier{"sales", name},
+			iceberg.NewSchema(0, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true}))
+		require.NoError(t, err)
+	}
+
+	engine, err := duckdb.NewEngine(cat)
+	if err != nil {
+		engine = nil
+	} else {
+		t.Cleanup(func() { engine.Close() })
+		for _, name := range []string{"orders", "customers"} {
+			ident := table.Identifier{"sales", name}
+			appendTestRows(t, cat, ident, 1, 2)
+			tbl, err := cat.LoadTable(ctx, ident, nil)
+			require.NoError(t, err)
+			require.NoError(t, engine.RegisterTable(ctx, ident, tbl))
+		}
+	}
+
+	srv, err := newFlightSQLServer(cat, engine)
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	flight.RegisterFlightServiceServer(server, flightsql.NewFlightServer(srv))
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	client, err := flightsql.NewClient("passthrough:///bufconn", nil, nil,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client, engine
+}
+
+// fetchFlightInfo reads every endpoint of a FlightInfo into one table
+func fetchFlightInfo(t *testing.T, client *flightsql.Client, info *flight.FlightInfo) arrow.Table {
+	t.Helper()
+
+	var records []arrow.Record
+	var schema *arrow.Schema
+	for _, endpoint := range info.GetEndpoint() {
+		reader, err := client.DoGet(context.Background(), endpoint.GetTicket())
+		require.NoError(t, err)
+		schema = reader.Schema()
+		for reader.Next() {
+			record := reader.Record()
+			record.Retain()
+			records = append(records, record)
+		}
+		require.NoError(t, reader.Err())
+		reader.Release()
+	}
+
+	tbl := array.NewTableFromRecords(schema, records)
+	for _, record := range records {
+		record.Release()
+	}
+	t.Cleanup(tbl.Release)
+	return tbl
+}
+
+// stringColumn returns the values of a string column of a table
+func stringColumn(tbl arrow.Table, col int) []string {
+	var values []string
+	for _, chunk := range tbl.Column(col).Data().Chunks() {
+		strs := chunk.(*array.String)
+		for i := 0; i < strs.Len(); i++ {
+			values = append(values, strs.Value(i))
+		}
+	}
+	return values
+}
+
+func TestFlightSQLCatalogBrowsing(t *testing.T) {
+	client, _ := newTestFlightSQLServer(t)
+	ctx := context.Background()
+
+	info, err := client.GetCatalogs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{duckdb.DatabaseName}, stringColumn(fetchFlightInfo(t, client, info), 0))
+
+	info, err = client.GetDBSchemas(ctx, &flightsql.GetDBSchemasOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sales"}, stringColumn(fetchFlightInfo(t, client, info), 1))
+
+	info, err = client.GetTables(ctx, &flightsql.GetTablesOpts{})
+	require.NoError(t, err)
+	tables := fetchFlightInfo(t, client, info)
+	assert.ElementsMatch(t, []string{"orders", "customers"}, stringColumn(tables, 2))
+	assert.Equal(t, []string{"TABLE", "TABLE"}, stringColumn(tables, 3))
+
+	// Filters use LIKE patterns
+	pattern := "ord%"
+	info, err = client.GetTables(ctx, &flightsql.GetTablesOpts{TableNameFilterPattern: &pattern, IncludeSchema: true})
+	require.NoError(t, err)
+	tables = fetchFlightInfo(t, client, info)
+	require.Equal(t, []string{"orders"}, stringColumn(tables, 2))
+
+	schemaBytes := tables.Column(4).Data().Chunk(0).(*array.Binary).Value(0)
+	schema, err := flight.DeserializeSchema(schemaBytes, memory.DefaultAllocator)
+	require.NoError(t, err)
+	assert.Equal(t, "id", schema.Field(0).Name)
+	assert.Equal(t, arrow.PrimitiveTypes.Int64, schema.Field(0).Type)
+
+	other := "other"
+	info, err = client.GetDBSchemas(ctx, &flightsql.GetDBSchemasOpts{Catalog: &other})
+	require.NoError(t, err)
+	assert.Empty(t, stringColumn(fetchFlightInfo(t, client, info), 1))
+
+	info, err = client.GetTables(ctx, &flightsql.GetTablesOpts{TableTypes: []string{"VIEW"}})
+	require.NoError(t, err)
+	assert.Zero(t, fetchFlightInfo(t, client, info).NumRows())
+}
+
+func TestFlightSQLStatements(t *testing.T) {
+	client, engine := newTestFlightSQLServer(t)
+	if engine == nil {
+		t.Skip("DuckDB engine unavailable")
+	}
+	ctx := context.Background()
+
+	info, err := client.Execute(ctx, "SELECT range AS n FROM range(2500)")
+	require.NoError(t, err)
+	result := fetchFlightInfo(t, client, info)
+	assert.Equal(t, int64(2500), result.NumRows())
+	assert.Equal(t, "n", result.Schema().Field(0).Name)
+
+	// Statements and prepared statements are streamed whole, beyond the rows
+	// ExecuteQuery collects
+	limit := duckdb.DefaultEngineConfig().MaxResultRows
+	large := fmt.Sprintf("SELECT range AS n FROM range(%d)", limit+1)
+	info, err = client.Execute(ctx, large)
+	require.NoError(t, err)
+	result = fetchFlightInfo(t, client, info)
+	assert.Equal(t, limit+1, result.NumRows())
+
+	largeStmt, err := client.Prepare(ctx, large)
+	require.NoError(t, err)
+	defer largeStmt.Close(ctx)
+	info, err = largeStmt.Execute(ctx)
+	require.NoError(t, err)
+	result = fetchFlightInfo(t, client, info)
+	assert.Equal(t, limit+1, result.NumRows())
+
+	// Prepared statements run once per bound parameter set
+	stmt, err := client.Prepare(ctx, "SELECT ?::BIGINT * 2 AS doubled")
+	require.NoError(t, err)
+	defer stmt.Close(ctx)
+
+	params := array.NewRecordBuilder(memory.DefaultAllocator, arrow.NewSchema([]arrow.Field{{Name: "x", Type: arrow.PrimitiveTypes.Int64}}, nil))
+	defer params.Release()
+	params.Field(0).(*array.Int64Builder).AppendValues([]int64{2, 21}, nil)
+	binding := params.NewRecord()
+	defer binding.Release()
+	stmt.SetParameters(binding)
+
+	info, err = stmt.Execute(ctx)
+	require.NoError(t, err)
+	result = fetchFlightInfo(t, client, info)
+	require.Equal(t, int64(2), result.NumRows())
+	// Each parameter set's results are sent as batches of their own
+	var doubled []int64
+	for _, chunk := range result.Column(0).Data().Chunks() {
+		doubled = append(doubled, chunk.(*array.Int64).Int64Values()...)
+	}
+	assert.Equal(t, []int64{4, 42}, doubled)
+
+	// Statements run when their ticket is fetched
+	info, err = client.Execute(ctx, "SELECT * FROM missing_table")
+	require.NoError(t, err)
+	_, err = client.DoGet(ctx, info.GetEndpoint()[0].GetTicket())
+	assert.Error(t, err)
+}
+
+func TestFlightSQLQueryListedTables(t *testing.T) {
+	client, engine := newTestFlightSQLServer(t)
+	if engine == nil {
+		t.Skip("DuckDB engine unavailable")
+	}
+	ctx := context.Background()
+
+	info, err := client.GetTables(ctx, &flightsql.GetTablesOpts{})
+	require.NoError(t, err)
+	tables := fetchFlightInfo(t, client, info)
+	require.Equal(t, int64(2), tables.NumRows())
+
+	// Tables are queryable by the names they are listed with, qualified or not
+	catalogs, schemas, names := stringColumn(tables, 0), stringColumn(tables, 1), stringColumn(tables, 2)
+	for i := range names {
+		for _, name := range []string{
+			fmt.Sprintf("%q.%q.%q", catalogs[i], schemas[i], names[i]),
+			fmt.Sprintf("%q.%q", schemas[i], names[i]),
+		} {
+			info, err := client.Execute(ctx, "SELECT count(*) AS n FROM "+name)
+			require.NoError(t, err, name)
+			result := fetchFlightInfo(t, client, info)
+			assert.Equal(t, int64(2), result.Column(0).Data().Chunk(0).(*array.Int64).Value(0), name)
+		}
+	}
+}
+
+func TestFlightSQLStreamRecords(t *testing.T) {
+	srv, err := newFlightSQLServer(nil, nil)
+	require.NoError(t, err)
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "n", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer builder.Release()
+	var records []arrow.Record
+	for _, size := range []int{2048, 452} {
+		for i := 0; i < size; i++ {
+			builder.Field(0).(*array.Int64Builder).Append(int64(i))
+		}
+		record := builder.NewRecord()
+		defer record.Release()
+		records = append(records, record)
+	}
+	newReader := func(schema *arrow.Schema, records ...arrow.Record) array.RecordReader {
+		reader, err := array.NewRecordReader(schema, records)
+		require.NoError(t, err)
+		return reader
+	}
+
+	// Results are sent one after the other, in the batches they are read in
+	readers := []array.RecordReader{newReader(schema, records[1])}
+	next := func() (array.RecordReader, error) {
+		if len(readers) == 0 {
+			return nil, nil
+		}
+		reader := readers[0]
+		readers = readers[1:]
+		return reader, nil
+	}
+	var sizes []int64
+	for chunk := range srv.streamRecords(context.Background(), newReader(schema, records...), next) {
+		require.NoError(t, chunk.Err)
+		sizes = append(sizes, chunk.Data.NumRows())
+		chunk.Data.Release()
+	}
+	assert.Equal(t, []int64{2048, 452, 452}, sizes)
+
+	// A result of another schema ends the stream with an error
+	other := arrow.NewSchema([]arrow.Field{{Name: "s", Type: arrow.BinaryTypes.String}}, nil)
+	readers = []array.RecordReader{newReader(other)}
+	var errs []error
+	for chunk := range srv.streamRecords(context.Background(), newReader(schema, records[1]), next) {
+		if chunk.Data != nil {
+			chunk.Data.Release()
+		}
+		if chunk.Err != nil {
+			errs = append(errs, chunk.Err)
+		}
+	}
+	require.Len(t, errs, 1)
+	assert.Equal(t, codes.InvalidArgument, status.Code(errs[0]))
+
+	// A canceled stream stops producing batches
+	ctx, cancel := context.WithCancel(context.Background())
+	chunks := srv.streamRecords(ctx, newReader(schema, records...), nil)
+	cancel()
+	for chunk := range chunks {
+		chunk.Data.Release()
+	}
+}
+
+func TestFlightSQLPreparedStatementExpiry(t *testing.T) {
+	srv, err := newFlightSQLServer(nil, nil)
+	require.NoError(t, err)
+	now := time.Now()
+
+	// Statements left unused are closed once another one is prepared
+	srv.addPreparedStatement("idle", &preparedStatement{query: "SELECT 1"}, now.Add(-preparedStatementTTL-time.Minute))
+	srv.addPreparedStatement("fresh", &preparedStatement{query: "SELECT 2"}, now)
+	_, err = srv.preparedStatement([]byte("idle"))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.NotContains(t, srv.prepared, "idle")
+	_, err = srv.preparedStatement([]byte("fresh"))
+	require.NoError(t, err)
+
+	// Beyond the cap, the least recently used statement is closed
+	for i := 1; i < maxPreparedStatements; i++ {
+		srv.addPreparedStatement(fmt.Sprintf("stmt-%d", i), &preparedStatement{query: "SELECT 3"}, now.Add(time.Duration(i)*time.Millisecond-time.Minute))
+	}
+	require.Len(t, srv.prepared, maxPreparedStatements)
+	srv.addPreparedStatement("newest", &preparedStatement{query: "SELECT 4"}, now.Add(time.Second))
+	assert.Len(t, srv.prepared, maxPreparedStatements)
+	assert.Contains(t, srv.prepared, "fresh") // used since it was prepared
+	assert.NotContains(t, srv.prepared, "stmt-1")
+}
+
+func TestLikePattern(t *testing.T) {
+	pattern := func(s string) *string { return &s }
+
+	assert.Nil(t, likePattern(nil))
+	assert.Nil(t, likePattern(pattern("%")))
+	assert.True(t, likePattern(pattern("sa_es")).MatchString("sales"))
+	assert.False(t, likePattern(pattern("sa_es")).MatchString("saless"))
+	assert.True(t, likePattern(pattern("a.b%")).MatchString("a.b.c"))
+	assert.False(t, likePattern(pattern("a.b%")).MatchString("axb"))
+}
@@ -0,0 +1,641 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TFMV/icebox/auth"
+	"github.com/TFMV/icebox/catalog"
+	"github.com/TFMV/icebox/catalog/grant"
+	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/engine/duckdb"
+	"github.com/TFMV/icebox/importer"
+	"github.com/TFMV/icebox/jobs"
+	iceboxv1 "github.com/TFMV/icebox/proto/icebox/v1"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/iceberg-go"
+	icebergcatalog "github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// defaultBatchSize is the number of rows per Arrow record batch streamed by the query
+// RPCs when the request does not set one
+const defaultBatchSize = 1024
+
+// watchInterval is how often WatchImportJob polls a job for changes
+var watchInterval = 200 * time.Millisecond
+
+// registerGRPCServices registers the catalog, query, import and time-travel services.
+// Calls are checked against the grants of access, which is nil without authentication.
+func registerGRPCServices(s grpc.ServiceRegistrar, cat catalog.CatalogInterface, engine *duckdb.Engine, cfg *config.Config, importJobs *jobs.Manager, access *accessControl) {
+	iceboxv1.RegisterCatalogServiceServer(s, &catalogService{catalog: cat, access: access})
+	iceboxv1.RegisterQueryServiceServer(s, &queryService{engine: engine, access: access})
+	iceboxv1.RegisterImportServiceServer(s, &importService{config: cfg, jobs: importJobs, access: access})
+	iceboxv1.RegisterTimeTravelServiceServer(s, &timeTravelService{catalog: cat, engine: engine, access: access})
+}
+
+// catalogService implements CatalogService over the project's catalog
+type catalogService struct {
+	iceboxv1.UnimplementedCatalogServiceServer
+	catalog catalog.CatalogInterface
+	access  *accessControl
+}
+
+func (s *catalogService) ListNamespaces(ctx context.Context, req *iceboxv1.ListNamespacesRequest) (*iceboxv1.ListNamespacesResponse, error) {
+	namespaces, err := s.catalog.ListNamespaces(ctx, req.GetParent())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &iceboxv1.ListNamespacesResponse{}
+	for _, namespace := range namespaces {
+		ok, err := s.access.canAccess(ctx, namespace)
+		if err != nil {
+			return nil, grpcError(err)
+		}
+		if !ok {
+			continue
+		}
+		props, err := s.catalog.LoadNamespaceProperties(ctx, namespace)
+		if err != nil {
+			return nil, grpcError(err)
+		}
+		resp.Namespaces = append(resp.Namespaces, &iceboxv1.Namespace{Levels: namespace, Properties: props})
+	}
+	return resp, nil
+}
+
+func (s *catalogService) CreateNamespace(ctx context.Context, req *iceboxv1.CreateNamespaceRequest) (*iceboxv1.Namespace, error) {
+	if len(req.GetLevels()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "namespace levels are required")
+	}
+	if err := s.access.authorize(ctx, grant.Admin, req.GetLevels()[:len(req.GetLevels())-1], ""); err != nil {
+		return nil, grpcError(err)
+	}
+	if err := s.catalog.CreateNamespace(ctx, req.GetLevels(), req.GetProperties()); err != nil {
+		return nil, grpcError(err)
+	}
+	return s.GetNamespace(ctx, &iceboxv1.GetNamespaceRequest{Levels: req.GetLevels()})
+}
+
+func (s *catalogService) GetNamespace(ctx context.Context, req *iceboxv1.GetNamespaceRequest) (*iceboxv1.Namespace, error) {
+	if len(req.GetLevels()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "namespace levels are required")
+	}
+	if err := s.access.authorize(ctx, grant.Read, req.GetLevels(), ""); err != nil {
+		return nil, grpcError(err)
+	}
+	props, err := s.catalog.LoadNamespaceProperties(ctx, req.GetLevels())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &iceboxv1.Namespace{Levels: req.GetLevels(), Properties: props}, nil
+}
+
+func (s *catalogService) DropNamespace(ctx context.Context, req *iceboxv1.DropNamespaceRequest) (*iceboxv1.DropNamespaceResponse, error) {
+	if len(req.GetLevels()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "namespace levels are required")
+	}
+	if err := s.access.authorize(ctx, grant.Admin, req.GetLevels(), ""); err != nil {
+		return nil, grpcError(err)
+	}
+	if err := s.catalog.DropNamespace(ctx, req.GetLevels()); err != nil {
+		return nil, grpcError(err)
+	}
+	return &iceboxv1.DropNamespaceResponse{}, nil
+}
+
+func (s *catalogService) ListTables(ctx context.Context, req *iceboxv1.ListTablesRequest) (*iceboxv1.ListTablesResponse, error) {
+	if len(req.GetNamespace()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "namespace is required")
+	}
+
+	resp := &iceboxv1.ListTablesResponse{}
+	for ident, err := range s.catalog.ListTables(ctx, req.GetNamespace()) {
+		if err != nil {
+			return nil, grpcError(err)
+		}
+		readable, err := s.access.canRead(ctx, ident)
+		if err != nil {
+			return nil, grpcError(err)
+		}
+		if readable {
+			resp.Tables = append(resp.Tables, tableIdentifierMessage(ident))
+		}
+	}
+	return resp, nil
+}
+
+func (s *catalogService) CreateTable(ctx context.Context, req *iceboxv1.CreateTableRequest) (*iceboxv1.Table, error) {
+	tableIdent, err := tableIdentFromMessage(req.GetIdentifier())
+	if err != nil {
+		return nil, err
+	}
+
+	var schema iceberg.Schema
+	if err := json.Unmarshal([]byte(req.GetSchemaJson()), &schema); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid schema_json: %v", err)
+	}
+	if len(schema.Fields()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "schema with at least one field is required")
+	}
+
+	opts := []icebergcatalog.CreateTableOpt{}
+	if req.GetPartitionSpecJson() != "" {
+		var spec iceberg.PartitionSpec
+		if err := json.Unmarshal([]byte(req.GetPartitionSpecJson()), &spec); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid partition_spec_json: %v", err)
+		}
+		opts = append(opts, icebergcatalog.WithPartitionSpec(&spec))
+	}
+	if req.GetLocation() != "" {
+		opts = append(opts, icebergcatalog.WithLocation(req.GetLocation()))
+	}
+	if len(req.GetProperties()) > 0 {
+		opts = append(opts, icebergcatalog.WithProperties(req.GetProperties()))
+	}
+
+	if req.GetLocation() != "" {
+		err = s.access.authorizeTableLocation(ctx, tableIdent, req.GetLocation())
+	} else {
+		err = s.access.authorizeTable(ctx, grant.Write, tableIdent)
+	}
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	tbl, err := s.catalog.CreateTable(ctx, tableIdent, &schema, opts...)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return tableMessage(tbl)
+}
+
+func (s *catalogService) LoadTable(ctx context.Context, req *iceboxv1.LoadTableRequest) (*iceboxv1.Table, error) {
+	tableIdent, err := tableIdentFromMessage(req.GetIdentifier())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.access.authorizeTable(ctx, grant.Read, tableIdent); err != nil {
+		return nil, grpcError(err)
+	}
+	tbl, err := s.catalog.LoadTable(ctx, tableIdent, nil)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return tableMessage(tbl)
+}
+
+func (s *catalogService) DropTable(ctx context.Context, req *iceboxv1.DropTableRequest) (*iceboxv1.DropTableResponse, error) {
+	tableIdent, err := tableIdentFromMessage(req.GetIdentifier())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.access.authorizeTable(ctx, grant.Admin, tableIdent); err != nil {
+		return nil, grpcError(err)
+	}
+	if err := s.catalog.DropTable(ctx, tableIdent); err != nil {
+		return nil, grpcError(err)
+	}
+	return &iceboxv1.DropTableResponse{}, nil
+}
+
+// queryService implements QueryService over the SQL engine
+type queryService struct {
+	iceboxv1.UnimplementedQueryServiceServer
+	engine *duckdb.Engine
+	access *accessControl
+}
+
+func (s *queryService) ExecuteQuery(req *iceboxv1.ExecuteQueryRequest, stream iceboxv1.QueryService_ExecuteQueryServer) error {
+	if strings.TrimSpace(req.GetSql()) == "" {
+		return status.Error(codes.InvalidArgument, "sql is required")
+	}
+	if s.engine == nil {
+		return status.Error(codes.Unavailable, "SQL engine not available")
+	}
+
+	if err := s.access.authorizeQuery(stream.Context(), req.GetSql()); err != nil {
+		return grpcError(err)
+	}
+	reader, err := s.engine.ExecuteQueryStream(stream.Context(), req.GetSql())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "query failed: %v", err)
+	}
+	defer reader.Release()
+	return sendArrowRecords(stream, reader, req.GetBatchSize())
+}
+
+// importService implements ImportService with the server's import jobs
+type importService struct {
+	iceboxv1.UnimplementedImportServiceServer
+	config *config.Config
+	jobs   *jobs.Manager
+	access *accessControl
+}
+
+func (s *importService) ImportFile(ctx context.Context, req *iceboxv1.ImportFileRequest) (*iceboxv1.ImportJob, error) {
+	if req.GetFilePath() == "" {
+		return nil, status.Error(codes.InvalidArgument, "file_path is required")
+	}
+	tableIdent, err := tableIdentFromMessage(req.GetTable())
+	if err != nil {
+		return nil, err
+	}
+	if len(tableIdent) == 1 {
+		tableIdent = table.Identifier{"default", tableIdent[0]}
+	}
+	if err := s.access.authorizeFileImport(ctx, tableIdent, req.GetFilePath()); err != nil {
+		return nil, grpcError(err)
+	}
+
+	importerType, err := importer.NewImporterFactory(s.config).DetectFileType(req.GetFilePath())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported file type: %v", err)
+	}
+
+	params := map[string]string{
+		"file_path": req.GetFilePath(),
+		"table":     strings.Join(tableIdent, "."),
+		"format":    string(importerType),
+		"overwrite": strconv.FormatBool(req.GetOverwrite()),
+	}
+	importReq := importFileRequest{
+		FilePath:    req.GetFilePath(),
+		Overwrite:   req.GetOverwrite(),
+		PartitionBy: req.GetPartitionBy(),
+	}
+	job, err := s.jobs.Submit("import", params, importJob(s.config, importerType, importReq, tableIdent, nil))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return importJobMessage(job)
+}
+
+func (s *importService) GetImportJob(ctx context.Context, req *iceboxv1.GetImportJobRequest) (*iceboxv1.ImportJob, error) {
+	job, err := s.jobs.Get(req.GetJobId())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if err := s.access.authorizeJob(ctx, grant.Read, job); err != nil {
+		return nil, grpcError(err)
+	}
+	return importJobMessage(job)
+}
+
+func (s *importService) ListImportJobs(ctx context.Context, req *iceboxv1.ListImportJobsRequest) (*iceboxv1.ListImportJobsResponse, error) {
+	resp := &iceboxv1.ListImportJobsResponse{}
+	for _, job := range s.jobs.List() {
+		if err := s.access.authorizeJob(ctx, grant.Read, job); err != nil {
+			if errors.Is(err, auth.ErrPermissionDenied) {
+				continue
+			}
+			return nil, grpcError(err)
+		}
+		msg, err := importJobMessage(job)
+		if err != nil {
+			return nil, err
+		}
+		resp.Jobs = append(resp.Jobs, msg)
+	}
+	return resp, nil
+}
+
+func (s *importService) CancelImportJob(ctx context.Context, req *iceboxv1.CancelImportJobRequest) (*iceboxv1.ImportJob, error) {
+	job, err := s.jobs.Get(req.GetJobId())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if err := s.access.authorizeJob(ctx, grant.Write, job); err != nil {
+		return nil, grpcError(err)
+	}
+	if job, err = s.jobs.Cancel(job.ID); err != nil {
+		return nil, grpcError(err)
+	}
+	return importJobMessage(job)
+}
+
+func (s *importService) WatchImportJob(req *iceboxv1.GetImportJobRequest, stream iceboxv1.ImportService_WatchImportJobServer) error {
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	var last *jobs.Job
+	for {
+		job, err := s.jobs.Get(req.GetJobId())
+		if err != nil {
+			return grpcError(err)
+		}
+		if last == nil {
+			if err := s.access.authorizeJob(stream.Context(), grant.Read, job); err != nil {
+				return grpcError(err)
+			}
+		}
+
+		if last == nil || job.State != last.State || job.Progress != last.Progress {
+			msg, err := importJobMessage(job)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+			last = &job
+		}
+		if job.State.Final() {
+			return nil
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// timeTravelService implements TimeTravelService over the catalog and SQL engine
+type timeTravelService struct {
+	iceboxv1.UnimplementedTimeTravelServiceServer
+	catalog catalog.CatalogInterface
+	engine  *duckdb.Engine
+	access  *accessControl
+}
+
+func (s *timeTravelService) ListSnapshots(ctx context.Context, req *iceboxv1.ListSnapshotsRequest) (*iceboxv1.ListSnapshotsResponse, error) {
+	tableIdent, err := tableIdentFromMessage(req.GetIdentifier())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.access.authorizeTable(ctx, grant.Read, tableIdent); err != nil {
+		return nil, grpcError(err)
+	}
+	tbl, err := s.catalog.LoadTable(ctx, tableIdent, nil)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &iceboxv1.ListSnapshotsResponse{}
+	for _, snapshot := range tbl.Metadata().Snapshots() {
+		msg := &iceboxv1.Snapshot{
+			SnapshotId:     snapshot.SnapshotID,
+			SequenceNumber: snapshot.SequenceNumber,
+			Timestamp:      timestamppb.New(time.UnixMilli(snapshot.TimestampMs)),
+			ManifestList:   snapshot.ManifestList,
+		}
+		if snapshot.ParentSnapshotID != nil {
+			msg.ParentSnapshotId = *snapshot.ParentSnapshotID
+		}
+		if snapshot.Summary != nil {
+			msg.Operation = string(snapshot.Summary.Operation)
+			msg.Summary = snapshot.Summary.Properties
+		}
+		resp.Snapshots = append(resp.Snapshots, msg)
+	}
+	if current := tbl.CurrentSnapshot(); current != nil {
+		resp.CurrentSnapshotId = current.SnapshotID
+	}
+	for entry := range tbl.Metadata().SnapshotLogs() {
+		resp.History = append(resp.History, &iceboxv1.SnapshotLogEntry{
+			SnapshotId: entry.SnapshotID,
+			Timestamp:  timestamppb.New(time.UnixMilli(entry.TimestampMs)),
+		})
+	}
+	return resp, nil
+}
+
+func (s *timeTravelService) QueryAsOf(req *iceboxv1.QueryAsOfRequest, stream iceboxv1.TimeTravelService_QueryAsOfServer) error {
+	tableIdent, err := tableIdentFromMessage(req.GetTable())
+	if err != nil {
+		return err
+	}
+
+	var asOf string
+	switch v := req.GetAsOf().(type) {
+	case *iceboxv1.QueryAsOfRequest_SnapshotId:
+		asOf = strconv.FormatInt(v.SnapshotId, 10)
+	case *iceboxv1.QueryAsOfRequest_Timestamp:
+		asOf = v.Timestamp.AsTime().Format(time.RFC3339Nano)
+	default:
+		return status.Error(codes.InvalidArgument, "snapshot_id or timestamp is required")
+	}
+	if s.engine == nil {
+		return status.Error(codes.Unavailable, "SQL engine not available")
+	}
+
+	ctx := stream.Context()
+	if err := s.access.authorizeTable(ctx, grant.Read, tableIdent); err != nil {
+		return grpcError(err)
+	}
+	tbl, err := s.catalog.LoadTable(ctx, tableIdent, nil)
+	if err != nil {
+		return grpcError(err)
+	}
+	snapshotID, _, err := resolveSnapshot(tbl, asOf)
+	if err != nil {
+		return status.Error(codes.NotFound, err.Error())
+	}
+
+	query := req.GetSql()
+	if query == "" {
+		query = defaultTimeTravelQuery(tableIdent)
+	}
+	if err := s.access.authorizeQuery(ctx, query); err != nil {
+		return grpcError(err)
+	}
+	reader, err := s.engine.ExecuteQueryStreamAtSnapshot(ctx, tableIdent, tbl, snapshotID, query)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "time-travel query failed: %v", err)
+	}
+	defer reader.Release()
+	return sendArrowRecords(stream, reader, req.GetBatchSize())
+}
+
+// queryResultSender is the server stream of the RPCs returning query results
+type queryResultSender interface {
+	Send(*iceboxv1.QueryResultChunk) error
+}
+
+// sendArrowRecords streams the records of a reader as an Arrow IPC stream, one record
+// batch of up to batchSize rows per chunk
+func sendArrowRecords(stream queryResultSender, reader array.RecordReader, batchSize int32) error {
+	size := int64(batchSize)
+	if size <= 0 {
+		size = defaultBatchSize
+	}
+
+	var buf bytes.Buffer
+	writer := ipc.NewWriter(&buf, ipc.WithSchema(reader.Schema()))
+
+	for reader.Next() {
+		record := reader.Record()
+		for offset := int64(0); offset < record.NumRows(); offset += size {
+			end := min(offset+size, record.NumRows())
+			batch := record.NewSlice(offset, end)
+			err := writer.Write(batch)
+			batch.Release()
+			if err != nil {
+				return status.Errorf(codes.Internal, "failed to encode record batch: %v", err)
+			}
+
+			// gRPC may encode messages after Send returns, so the buffer is copied
+			if err := stream.Send(&iceboxv1.QueryResultChunk{ArrowIpc: bytes.Clone(buf.Bytes()), RowCount: end - offset}); err != nil {
+				return err
+			}
+			buf.Reset()
+		}
+	}
+	if err := reader.Err(); err != nil {
+		return status.Errorf(codes.InvalidArgument, "query failed: %v", err)
+	}
+
+	// Closing writes the end-of-stream marker, preceded by the schema when there were
+	// no rows
+	if err := writer.Close(); err != nil {
+		return status.Errorf(codes.Internal, "failed to encode query result: %v", err)
+	}
+	return stream.Send(&iceboxv1.QueryResultChunk{ArrowIpc: buf.Bytes()})
+}
+
+// grpcError converts catalog, access and job errors to gRPC status errors
+func grpcError(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, auth.ErrPermissionDenied):
+		code = codes.PermissionDenied
+	case errors.Is(err, errInvalidQuery):
+		code = codes.InvalidArgument
+	case errors.Is(err, icebergcatalog.ErrNoSuchTable), errors.Is(err, icebergcatalog.ErrNoSuchNamespace), errors.Is(err, jobs.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, icebergcatalog.ErrTableAlreadyExists), errors.Is(err, icebergcatalog.ErrViewAlreadyExists),
+		errors.Is(err, icebergcatalog.ErrNamespaceAlreadyExists):
+		code = codes.AlreadyExists
+	case errors.Is(err, icebergcatalog.ErrNamespaceNotEmpty), errors.Is(err, jobs.ErrFinished):
+		code = codes.FailedPrecondition
+	case errors.Is(err, jobs.ErrQueueFull), errors.Is(err, errResultTruncated):
+		code = codes.ResourceExhausted
+	case errors.Is(err, jobs.ErrClosed):
+		code = codes.Unavailable
+	}
+	return status.Error(code, err.Error())
+}
+
+// tableIdentFromMessage converts a table identifier message, which must name a table
+func tableIdentFromMessage(ident *iceboxv1.TableIdentifier) (table.Identifier, error) {
+	if ident.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "table name is required")
+	}
+	return append(append(table.Identifier{}, ident.GetNamespace()...), ident.GetName()), nil
+}
+
+func tableIdentifierMessage(ident table.Identifier) *iceboxv1.TableIdentifier {
+	return &iceboxv1.TableIdentifier{
+		Namespace: ident[:len(ident)-1],
+		Name:      ident[len(ident)-1],
+	}
+}
+
+// tableMessage describes a table's current metadata
+func tableMessage(tbl *table.Table) (*iceboxv1.Table, error) {
+	schemaJSON, err := json.Marshal(tbl.Schema())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to serialize schema: %v", err)
+	}
+	specJSON, err := json.Marshal(tbl.Spec())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to serialize partition spec: %v", err)
+	}
+	sortOrderJSON, err := json.Marshal(tbl.SortOrder())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to serialize sort order: %v", err)
+	}
+
+	msg := &iceboxv1.Table{
+		Identifier:        tableIdentifierMessage(tbl.Identifier()),
+		Location:          tbl.Location(),
+		MetadataLocation:  tbl.MetadataLocation(),
+		FormatVersion:     int32(tbl.Metadata().Version()),
+		SchemaJson:        string(schemaJSON),
+		PartitionSpecJson: string(specJSON),
+		SortOrderJson:     string(sortOrderJSON),
+		Properties:        tbl.Properties(),
+	}
+	if current := tbl.CurrentSnapshot(); current != nil {
+		msg.CurrentSnapshotId = current.SnapshotID
+	}
+	return msg, nil
+}
+
+var jobStates = map[jobs.State]iceboxv1.JobState{
+	jobs.StateQueued:    iceboxv1.JobState_JOB_STATE_QUEUED,
+	jobs.StateRunning:   iceboxv1.JobState_JOB_STATE_RUNNING,
+	jobs.StateSucceeded: iceboxv1.JobState_JOB_STATE_SUCCEEDED,
+	jobs.StateFailed:    iceboxv1.JobState_JOB_STATE_FAILED,
+	jobs.StateCanceled:  iceboxv1.JobState_JOB_STATE_CANCELED,
+}
+
+// importJobMessage describes an import job
+func importJobMessage(job jobs.Job) (*iceboxv1.ImportJob, error) {
+	msg := &iceboxv1.ImportJob{
+		Id:     job.ID,
+		State:  jobStates[job.State],
+		Params: job.Params,
+		Progress: &iceboxv1.ImportProgress{
+			RowsRead:    job.Progress.RowsRead,
+			RowsWritten: job.Progress.RowsWritten,
+			BytesRead:   job.Progress.BytesRead,
+			TotalBytes:  job.Progress.TotalBytes,
+		},
+		Error:     job.Error,
+		CreatedAt: timestamppb.New(job.CreatedAt),
+	}
+	if job.StartedAt != nil {
+		msg.StartedAt = timestamppb.New(*job.StartedAt)
+	}
+	if job.FinishedAt != nil {
+		msg.FinishedAt = timestamppb.New(*job.FinishedAt)
+	}
+	for _, transition := range job.Transitions {
+		msg.Transitions = append(msg.Transitions, &iceboxv1.JobTransition{
+			State:   jobStates[transition.State],
+			At:      timestamppb.New(transition.At),
+			Message: transition.Message,
+		})
+	}
+
+	if job.Result != nil {
+		// Results hold values such as schemas, so they take the same JSON form as in
+		// the REST API
+		data, err := json.Marshal(job.Result)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to serialize job result: %v", err)
+		}
+		var result map[string]interface{}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to serialize job result: %v", err)
+		}
+		if msg.Result, err = structpb.NewStruct(result); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to serialize job result: %v", err)
+		}
+	}
+	return msg, nil
+}
+
+// grpcServiceNames lists the fully qualified names of the registered services
+func grpcServiceNames() []string {
+	return []string{
+		iceboxv1.CatalogService_ServiceDesc.ServiceName,
+		iceboxv1.QueryService_ServiceDesc.ServiceName,
+		iceboxv1.ImportService_ServiceDesc.ServiceName,
+		iceboxv1.TimeTravelService_ServiceDesc.ServiceName,
+	}
+}
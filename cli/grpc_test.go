@@ -0,0 +1,368 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/TFMV/icebox/auth"
+	"github.com/TFMV/icebox/catalog/grant"
+	"github.com/TFMV/icebox/catalog/sqlite"
+	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/engine/duckdb"
+	"github.com/TFMV/icebox/jobs"
+	iceboxv1 "github.com/TFMV/icebox/proto/icebox/v1"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestGRPCServer serves the gRPC services over an in-memory connection, backed by a
+// SQLite catalog in a temporary directory. The SQL engine is used when DuckDB is
+// available.
+func newTestGRPCServer(t *testing.T) (*grpc.ClientConn, *sqlite.Catalog, *duckdb.Engine) {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		Name: "test-catalog",
+		Catalog: config.CatalogConfig{
+			Type:   "sqlite",
+			SQLite: &config.SQLiteConfig{Path: filepath.Join(tempDir, "catalog.db")},
+		},
+		Storage: config.StorageConfig{
+			Type:       "fs",
+			FileSystem: &config.FileSystemConfig{RootPath: filepath.Join(tempDir, "data")},
+		},
+	}
+
+	cat, err := sqlite.NewCatalog(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { cat.Close() })
+
+	engine, err := duckdb.NewEngine(cat)
+	if err != nil {
+		engine = nil
+	} else {
+		t.Cleanup(func() { engine.Close() })
+	}
+
+	importJobs, err := jobs.NewManager(jobs.Options{HistoryPath: filepath.Join(tempDir, ".icebox", "jobs.json")})
+	require.NoError(t, err)
+	t.Cleanup(func() { importJobs.Close() })
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	registerGRPCServices(server, cat, engine, cfg, importJobs, nil)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn, cat, engine
+}
+
+// readArrowChunks decodes the Arrow IPC stream of a query RPC and returns the number of
+// rows and the column names
+func readArrowChunks(t *testing.T, recv func() (*iceboxv1.QueryResultChunk, error)) (int64, []string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	var chunkRows int64
+	for {
+		chunk, err := recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		buf.Write(chunk.GetArrowIpc())
+		chunkRows += chunk.GetRowCount()
+	}
+
+	reader, err := ipc.NewReader(&buf)
+	require.NoError(t, err)
+	defer reader.Release()
+
+	var fields []string
+	for _, field := range reader.Schema().Fields() {
+		fields = append(fields, field.Name)
+	}
+
+	var rows int64
+	for reader.Next() {
+		rows += reader.Record().NumRows()
+	}
+	require.NoError(t, reader.Err())
+	assert.Equal(t, chunkRows, rows)
+	return rows, fields
+}
+
+func TestGRPCCatalogService(t *testing.T) {
+	conn, _, _ := newTestGRPCServer(t)
+	client := iceboxv1.NewCatalogServiceClient(conn)
+	ctx := context.Background()
+
+	ns, err := client.CreateNamespace(ctx, &iceboxv1.CreateNamespaceRequest{
+		Levels:     []string{"sales"},
+		Properties: map[string]string{"owner": "analytics"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sales"}, ns.GetLevels())
+	assert.Equal(t, "analytics", ns.GetProperties()["owner"])
+
+	_, err = client.CreateNamespace(ctx, &iceboxv1.CreateNamespaceRequest{Levels: []string{"sales"}})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	ident := &iceboxv1.TableIdentifier{Namespace: []string{"sales"}, Name: "orders"}
+	tbl, err := client.CreateTable(ctx, &iceboxv1.CreateTableRequest{
+		Identifier: ident,
+		SchemaJson: `{"type":"struct","schema-id":0,"fields":[{"id":1,"name":"id","type":"long","required":true}]}`,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "orders", tbl.GetIdentifier().GetName())
+	assert.Contains(t, tbl.GetSchemaJson(), `"name":"id"`)
+	assert.NotEmpty(t, tbl.GetMetadataLocation())
+
+	tables, err := client.ListTables(ctx, &iceboxv1.ListTablesRequest{Namespace: []string{"sales"}})
+	require.NoError(t, err)
+	require.Len(t, tables.GetTables(), 1)
+	assert.Equal(t, "orders", tables.GetTables()[0].GetName())
+
+	_, err = client.DropNamespace(ctx, &iceboxv1.DropNamespaceRequest{Levels: []string{"sales"}})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = client.DropTable(ctx, &iceboxv1.DropTableRequest{Identifier: ident})
+	require.NoError(t, err)
+	_, err = client.LoadTable(ctx, &iceboxv1.LoadTableRequest{Identifier: ident})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.CreateTable(ctx, &iceboxv1.CreateTableRequest{Identifier: ident, SchemaJson: "{"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCImportService(t *testing.T) {
+	conn, _, _ := newTestGRPCServer(t)
+	client := iceboxv1.NewImportServiceClient(conn)
+	ctx := context.Background()
+
+	job, err := client.ImportFile(ctx, &iceboxv1.ImportFileRequest{
+		FilePath: filepath.Join("..", "testdata", "titanic.parquet"),
+		Table:    &iceboxv1.TableIdentifier{Namespace: []string{"imports"}, Name: "titanic"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "imports.titanic", job.GetParams()["table"])
+
+	watch, err := client.WatchImportJob(ctx, &iceboxv1.GetImportJobRequest{JobId: job.GetId()})
+	require.NoError(t, err)
+	var last *iceboxv1.ImportJob
+	for {
+		update, err := watch.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		last = update
+	}
+	require.NotNil(t, last)
+	require.Equal(t, iceboxv1.JobState_JOB_STATE_SUCCEEDED, last.GetState(), last.GetError())
+	assert.Equal(t, int64(891), last.GetProgress().GetRowsWritten())
+	assert.Equal(t, float64(891), last.GetResult().AsMap()["record_count"])
+	assert.NotNil(t, last.GetFinishedAt())
+
+	jobsResp, err := client.ListImportJobs(ctx, &iceboxv1.ListImportJobsRequest{})
+	require.NoError(t, err)
+	assert.Len(t, jobsResp.GetJobs(), 1)
+
+	_, err = client.CancelImportJob(ctx, &iceboxv1.CancelImportJobRequest{JobId: job.GetId()})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = client.GetImportJob(ctx, &iceboxv1.GetImportJobRequest{JobId: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	snapshots, err := iceboxv1.NewTimeTravelServiceClient(conn).ListSnapshots(ctx, &iceboxv1.ListSnapshotsRequest{
+		Identifier: &iceboxv1.TableIdentifier{Namespace: []string{"imports"}, Name: "titanic"},
+	})
+	require.NoError(t, err)
+	require.Len(t, snapshots.GetSnapshots(), 1)
+	assert.Equal(t, snapshots.GetSnapshots()[0].GetSnapshotId(), snapshots.GetCurrentSnapshotId())
+	assert.Equal(t, "append", snapshots.GetSnapshots()[0].GetOperation())
+}
+
+func TestGRPCImportAccess(t *testing.T) {
+	ac := &accessControl{authorizer: auth.NewAuthorizer(staticGrants{
+		{Grantee: grant.User("etl"), Namespace: "sales", Table: "orders", Privilege: grant.Write},
+		{Grantee: grant.User("root"), Namespace: grant.AllNamespaces, Privilege: grant.Admin},
+	})}
+	importJobs, err := jobs.NewManager(jobs.Options{HistoryPath: filepath.Join(t.TempDir(), "jobs.json")})
+	require.NoError(t, err)
+	t.Cleanup(func() { importJobs.Close() })
+	service := &importService{config: &config.Config{}, jobs: importJobs, access: ac}
+
+	// Files of the server need catalog admin even with write on the table
+	etl := auth.WithPrincipal(context.Background(), &auth.Principal{Name: "etl"})
+	_, err = service.ImportFile(etl, &iceboxv1.ImportFileRequest{
+		FilePath: "/etc/passwd",
+		Table:    &iceboxv1.TableIdentifier{Namespace: []string{"sales"}, Name: "orders"},
+	})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Empty(t, importJobs.List())
+
+	// Admins get past the check to the file itself
+	root := auth.WithPrincipal(context.Background(), &auth.Principal{Name: "root"})
+	_, err = service.ImportFile(root, &iceboxv1.ImportFileRequest{
+		FilePath: "/etc/passwd",
+		Table:    &iceboxv1.TableIdentifier{Namespace: []string{"sales"}, Name: "orders"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCCreateTableLocationAccess(t *testing.T) {
+	ac := &accessControl{authorizer: auth.NewAuthorizer(staticGrants{
+		{Grantee: grant.User("etl"), Namespace: "sales", Table: "orders", Privilege: grant.Write},
+	})}
+	service := &catalogService{access: ac}
+
+	// Choosing where a table is written needs catalog admin even with write on the table
+	etl := auth.WithPrincipal(context.Background(), &auth.Principal{Name: "etl"})
+	_, err := service.CreateTable(etl, &iceboxv1.CreateTableRequest{
+		Identifier: &iceboxv1.TableIdentifier{Namespace: []string{"sales"}, Name: "orders"},
+		SchemaJson: `{"type":"struct","schema-id":0,"fields":[{"id":1,"name":"id","type":"long","required":true}]}`,
+		Location:   "/etc/icebox",
+	})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestGRPCQueryService(t *testing.T) {
+	conn, _, engine := newTestGRPCServer(t)
+	if engine == nil {
+		t.Skip("DuckDB engine unavailable")
+	}
+	ctx := context.Background()
+
+	stream, err := iceboxv1.NewQueryServiceClient(conn).ExecuteQuery(ctx, &iceboxv1.ExecuteQueryRequest{
+		Sql:       "SELECT range AS n FROM range(10)",
+		BatchSize: 4,
+	})
+	require.NoError(t, err)
+	rows, fields := readArrowChunks(t, stream.Recv)
+	assert.Equal(t, int64(10), rows)
+	assert.Equal(t, []string{"n"}, fields)
+
+	// Empty results still carry their schema
+	stream, err = iceboxv1.NewQueryServiceClient(conn).ExecuteQuery(ctx, &iceboxv1.ExecuteQueryRequest{Sql: "SELECT 1 AS one WHERE false"})
+	require.NoError(t, err)
+	rows, fields = readArrowChunks(t, stream.Recv)
+	assert.Zero(t, rows)
+	assert.Equal(t, []string{"one"}, fields)
+
+	// Results are streamed whole, beyond the rows ExecuteQuery collects
+	limit := duckdb.DefaultEngineConfig().MaxResultRows
+	stream, err = iceboxv1.NewQueryServiceClient(conn).ExecuteQuery(ctx, &iceboxv1.ExecuteQueryRequest{
+		Sql: fmt.Sprintf("SELECT range AS n FROM range(%d)", limit+1),
+	})
+	require.NoError(t, err)
+	rows, _ = readArrowChunks(t, stream.Recv)
+	assert.Equal(t, limit+1, rows)
+}
+
+func TestGRPCQueryAsOf(t *testing.T) {
+	conn, cat, engine := newTestGRPCServer(t)
+	if engine == nil {
+		t.Skip("DuckDB engine unavailable")
+	}
+	ctx := context.Background()
+
+	ident := table.Identifier{"sales", "orders"}
+	require.NoError(t, cat.CreateNamespace(ctx, table.Identifier{"sales"}, iceberg.Properties{}))
+	_, err := cat.CreateTable(ctx, ident,
+		iceberg.NewSchema(0, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true}))
+	require.NoError(t, err)
+	appendTestRows(t, cat, ident, 1, 2)
+	appendTestRows(t, cat, ident, 3, 4, 5)
+	tbl, err := cat.LoadTable(ctx, ident, nil)
+	require.NoError(t, err)
+	snapshots := tbl.Metadata().Snapshots()
+	require.Len(t, snapshots, 2)
+
+	// Each snapshot reads the rows it had
+	client := iceboxv1.NewTimeTravelServiceClient(conn)
+	for i, want := range []int64{2, 5} {
+		stream, err := client.QueryAsOf(ctx, &iceboxv1.QueryAsOfRequest{
+			Table: &iceboxv1.TableIdentifier{Namespace: []string{"sales"}, Name: "orders"},
+			AsOf:  &iceboxv1.QueryAsOfRequest_SnapshotId{SnapshotId: snapshots[i].SnapshotID},
+		})
+		require.NoError(t, err)
+		rows, fields := readArrowChunks(t, stream.Recv)
+		assert.Equal(t, want, rows)
+		assert.Equal(t, []string{"id"}, fields)
+	}
+
+	stream, err := client.QueryAsOf(ctx, &iceboxv1.QueryAsOfRequest{
+		Table: &iceboxv1.TableIdentifier{Namespace: []string{"sales"}, Name: "orders"},
+		AsOf:  &iceboxv1.QueryAsOfRequest_SnapshotId{SnapshotId: snapshots[0].SnapshotID},
+		Sql:   "SELECT o.id FROM orders o WHERE o.id > 1",
+	})
+	require.NoError(t, err)
+	rows, _ := readArrowChunks(t, stream.Recv)
+	assert.Equal(t, int64(1), rows)
+
+	stream, err = client.QueryAsOf(ctx, &iceboxv1.QueryAsOfRequest{
+		Table: &iceboxv1.TableIdentifier{Namespace: []string{"sales"}, Name: "orders"},
+		AsOf:  &iceboxv1.QueryAsOfRequest_SnapshotId{SnapshotId: 42},
+	})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestSendArrowRecords(t *testing.T) {
+	result := testQueryResult(t)
+	records := array.NewTableReader(result.Table, -1)
+	defer records.Release()
+
+	stream := &chunkRecorder{}
+	require.NoError(t, sendArrowRecords(stream, records, 2))
+	require.Len(t, stream.chunks, 5) // four batches and the end of the stream
+
+	i := 0
+	rows, fields := readArrowChunks(t, func() (*iceboxv1.QueryResultChunk, error) {
+		if i == len(stream.chunks) {
+			return nil, io.EOF
+		}
+		i++
+		return stream.chunks[i-1], nil
+	})
+	assert.Equal(t, int64(6), rows)
+	assert.Equal(t, []string{"id", "name", "amount"}, fields)
+
+	reader, err := ipc.NewReader(bytes.NewReader(stream.chunks[0].GetArrowIpc()))
+	require.NoError(t, err)
+	defer reader.Release()
+	require.True(t, reader.Next())
+	assert.Equal(t, int64(2), reader.Record().NumRows())
+	assert.True(t, reader.Record().Column(1).IsNull(1))
+}
+
+// chunkRecorder records the chunks sent on a query result stream
+type chunkRecorder struct {
+	chunks []*iceboxv1.QueryResultChunk
+}
+
+func (r *chunkRecorder) Send(chunk *iceboxv1.QueryResultChunk) error {
+	r.chunks = append(r.chunks, chunk)
+	return nil
+}
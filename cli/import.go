@@ -22,6 +22,10 @@ Supported formats:
 - Parquet (.parquet)
 - Avro (.avro)
 
+The file can be a local path or a URL. s3:// URLs are read with the credentials
+of the project's S3 storage configuration, and http(s):// URLs are downloaded
+directly. Parquet files are read with ranged requests, starting at the footer.
+
 This command will:
 - Detect the file format automatically
 - Read the file and infer the schema
@@ -33,7 +37,9 @@ Examples:
   icebox import data.parquet --table my_table
   icebox import data.avro --table namespace.table_name
   icebox import data.parquet --table sales --namespace analytics
-  icebox import data.avro --dry-run --infer-schema`,
+  icebox import data.avro --dry-run --infer-schema
+  icebox import s3://datasets/titanic.parquet --table titanic
+  icebox import https://example.com/data/sales.parquet --table sales`,
 	Args: cobra.ExactArgs(1),
 	RunE: runImport,
 }
@@ -66,15 +72,20 @@ func init() {
 func runImport(cmd *cobra.Command, args []string) error {
 	dataFile := args[0]
 
-	// Validate that the data file exists
-	if _, err := os.Stat(dataFile); os.IsNotExist(err) {
-		return fmt.Errorf("data file does not exist: %s", dataFile)
-	}
+	// Files given as URLs are read from object storage or over HTTP(S)
+	absDataFile := dataFile
+	if !importer.IsRemoteSource(dataFile) {
+		// Validate that the data file exists
+		if _, err := os.Stat(dataFile); os.IsNotExist(err) {
+			return fmt.Errorf("data file does not exist: %s", dataFile)
+		}
 
-	// Get absolute path to the data file
-	absDataFile, err := filepath.Abs(dataFile)
-	if err != nil {
-		return fmt.Errorf("failed to get absolute path: %w", err)
+		// Get absolute path to the data file
+		var err error
+		absDataFile, err = filepath.Abs(dataFile)
+		if err != nil {
+			return fmt.Errorf("failed to get absolute path: %w", err)
+		}
 	}
 
 	// Find the Icebox configuration
@@ -87,6 +98,13 @@ func runImport(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Using configuration: %s\n", configPath)
 	}
 
+	// Make the embedded MinIO server reachable for projects that use it
+	stopStorage, err := startStorage(context.Background(), cfg)
+	if err != nil {
+		return err
+	}
+	defer stopStorage()
+
 	// Parse table identifier
 	tableIdent, namespaceIdent, err := parseTableIdentifier(importOpts.tableName, importOpts.namespace)
 	if err != nil {
@@ -9,6 +9,8 @@ import (
 	"github.com/TFMV/icebox/catalog/sqlite"
 	"github.com/TFMV/icebox/config"
 	"github.com/TFMV/icebox/display"
+	"github.com/TFMV/icebox/fs/minio"
+	"github.com/TFMV/icebox/fs/s3"
 	"github.com/spf13/cobra"
 )
 
@@ -20,17 +22,36 @@ var initCmd = &cobra.Command{
 This command creates a new directory (default: icebox-lakehouse) and sets up:
 - .icebox.yml configuration file
 - Catalog (SQLite database or JSON file)
-- Local filesystem storage directory
+- Local filesystem storage directory, or an S3 bucket with --storage s3
 - .icebox/display.yaml display configuration
 
-If no directory is specified, it creates "icebox-lakehouse" in the current location.`,
+If no directory is specified, it creates "icebox-lakehouse" in the current location.
+
+With --storage minio, tables are stored in an embedded S3-compatible server that is
+started automatically when needed, or explicitly with 'icebox minio start'.
+
+With --storage s3, table metadata and data files are written to the bucket while the
+catalog itself stays in the project directory. Credentials that are not given as flags
+are taken from the standard AWS environment variables and profiles.
+
+Examples:
+  icebox init my-lakehouse
+  icebox init my-lakehouse --storage minio
+  icebox init my-lakehouse --storage s3 --bucket warehouse --s3-endpoint http://localhost:9000`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runInit,
 }
 
 type initOptions struct {
-	catalog string
-	storage string
+	catalog           string
+	storage           string
+	s3Endpoint        string
+	s3Bucket          string
+	s3Region          string
+	s3Prefix          string
+	s3AccessKeyID     string
+	s3SecretAccessKey string
+	minioPort         int
 }
 
 var initOpts = &initOptions{}
@@ -39,7 +60,14 @@ func init() {
 	rootCmd.AddCommand(initCmd)
 
 	initCmd.Flags().StringVar(&initOpts.catalog, "catalog", "sqlite", "catalog type (sqlite|rest|json)")
-	initCmd.Flags().StringVar(&initOpts.storage, "storage", "fs", "storage type (fs|s3|mem)")
+	initCmd.Flags().StringVar(&initOpts.storage, "storage", "fs", "storage type (fs|s3|minio|mem)")
+	initCmd.Flags().StringVar(&initOpts.s3Endpoint, "s3-endpoint", "", "S3-compatible endpoint URL (default: AWS S3)")
+	initCmd.Flags().StringVar(&initOpts.s3Bucket, "bucket", "", "S3 bucket for table data and metadata")
+	initCmd.Flags().StringVar(&initOpts.s3Region, "s3-region", "", "S3 region (default: us-east-1)")
+	initCmd.Flags().StringVar(&initOpts.s3Prefix, "s3-prefix", "", "key prefix for the warehouse within the bucket")
+	initCmd.Flags().StringVar(&initOpts.s3AccessKeyID, "s3-access-key-id", "", "S3 access key ID")
+	initCmd.Flags().StringVar(&initOpts.s3SecretAccessKey, "s3-secret-access-key", "", "S3 secret access key")
+	initCmd.Flags().IntVar(&initOpts.minioPort, "minio-port", minio.DefaultPort, "port of the embedded MinIO server")
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
@@ -122,6 +150,12 @@ func runInit(cmd *cobra.Command, args []string) error {
 	fmt.Printf("✅ Initialized Icebox project in %s\n", absPath)
 	fmt.Printf("   Catalog: %s\n", cfg.Catalog.Type)
 	fmt.Printf("   Storage: %s\n", cfg.Storage.Type)
+	if cfg.Storage.S3 != nil {
+		fmt.Printf("   Warehouse: %s\n", s3.Warehouse(cfg.Storage.S3))
+	}
+	if cfg.Storage.MinIO != nil {
+		fmt.Printf("   MinIO: %s (starts automatically, or run 'icebox minio start')\n", cfg.Storage.S3.Endpoint)
+	}
 	fmt.Printf("\nNext steps:\n")
 	fmt.Printf("   icebox import your-data.parquet --table your_table\n")
 	fmt.Printf("   icebox sql 'SELECT * FROM your_table LIMIT 10'\n")
@@ -183,7 +217,43 @@ func initStorage(projectDir string, cfg *config.Config) error {
 		// Memory storage needs no initialization
 		cfg.Storage.Memory = &config.MemoryConfig{}
 	case "s3":
-		return fmt.Errorf("S3 storage initialization not yet implemented")
+		s3Config := &config.S3Config{
+			Bucket:          initOpts.s3Bucket,
+			Region:          initOpts.s3Region,
+			Endpoint:        initOpts.s3Endpoint,
+			AccessKeyID:     initOpts.s3AccessKeyID,
+			SecretAccessKey: initOpts.s3SecretAccessKey,
+			Prefix:          initOpts.s3Prefix,
+		}
+		if err := s3.Validate(s3Config); err != nil {
+			return fmt.Errorf("%w (use --bucket and --s3-endpoint)", err)
+		}
+		cfg.Storage.S3 = s3Config
+
+		// The JSON catalog keeps its warehouse in the bucket as well
+		if cfg.Catalog.JSON != nil {
+			cfg.Catalog.JSON.Warehouse = s3.Warehouse(s3Config)
+		}
+	case "minio":
+		dataDir := filepath.Join(projectDir, ".icebox", "minio")
+		if err := os.MkdirAll(dataDir, 0750); err != nil {
+			return fmt.Errorf("failed to create MinIO data directory: %w", err)
+		}
+
+		minioConfig := minio.DefaultProjectConfig(dataDir)
+		if initOpts.minioPort != 0 {
+			minioConfig.Port = initOpts.minioPort
+		}
+		if initOpts.s3Bucket != "" {
+			minioConfig.Bucket = initOpts.s3Bucket
+		}
+		cfg.Storage.MinIO = minioConfig
+
+		// Catalogs and the query engine reach the embedded server like any S3 endpoint
+		cfg.Storage.S3 = minio.S3ConfigFromProject(minioConfig)
+		if cfg.Catalog.JSON != nil {
+			cfg.Catalog.JSON.Warehouse = s3.Warehouse(cfg.Storage.S3)
+		}
 	default:
 		return fmt.Errorf("unsupported storage type: %s", cfg.Storage.Type)
 	}
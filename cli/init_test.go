@@ -245,6 +245,59 @@ func TestInitStorage(t *testing.T) {
 	}
 }
 
+func TestInitStorageS3(t *testing.T) {
+	defer func() { *initOpts = initOptions{} }()
+
+	// A bucket is required
+	cfg := &config.Config{Storage: config.StorageConfig{Type: "s3"}}
+	assert.Error(t, initStorage(t.TempDir(), cfg))
+
+	initOpts.s3Bucket = "warehouse"
+	initOpts.s3Endpoint = "http://localhost:9000"
+	initOpts.s3Prefix = "lakehouse"
+
+	cfg = &config.Config{
+		Catalog: config.CatalogConfig{
+			Type: "json",
+			JSON: &config.JSONConfig{URI: "/tmp/catalog.json", Warehouse: "/tmp/data"},
+		},
+		Storage: config.StorageConfig{Type: "s3"},
+	}
+	require.NoError(t, initStorage(t.TempDir(), cfg))
+	require.NotNil(t, cfg.Storage.S3)
+	assert.Equal(t, "warehouse", cfg.Storage.S3.Bucket)
+	assert.Equal(t, "http://localhost:9000", cfg.Storage.S3.Endpoint)
+	assert.Equal(t, "s3://warehouse/lakehouse", cfg.Catalog.JSON.Warehouse)
+
+	// Endpoints need a scheme so the SDK and DuckDB agree on TLS
+	initOpts.s3Endpoint = "localhost:9000"
+	assert.Error(t, initStorage(t.TempDir(), &config.Config{Storage: config.StorageConfig{Type: "s3"}}))
+}
+
+func TestInitStorageMinIO(t *testing.T) {
+	defer func() { *initOpts = initOptions{} }()
+
+	initOpts.minioPort = 9100
+	projectDir := t.TempDir()
+	cfg := &config.Config{
+		Catalog: config.CatalogConfig{
+			Type: "json",
+			JSON: &config.JSONConfig{URI: "/tmp/catalog.json", Warehouse: "/tmp/data"},
+		},
+		Storage: config.StorageConfig{Type: "minio"},
+	}
+	require.NoError(t, initStorage(projectDir, cfg))
+
+	require.NotNil(t, cfg.Storage.MinIO)
+	assert.Equal(t, 9100, cfg.Storage.MinIO.Port)
+	assert.Equal(t, filepath.Join(projectDir, ".icebox", "minio"), cfg.Storage.MinIO.DataDir)
+	assert.DirExists(t, cfg.Storage.MinIO.DataDir)
+
+	require.NotNil(t, cfg.Storage.S3)
+	assert.Equal(t, "http://localhost:9100", cfg.Storage.S3.Endpoint)
+	assert.Equal(t, "s3://"+cfg.Storage.MinIO.Bucket, cfg.Catalog.JSON.Warehouse)
+}
+
 func TestInitJSONCatalog(t *testing.T) {
 	tempDir, err := os.MkdirTemp("", "icebox-init-json-test")
 	require.NoError(t, err)
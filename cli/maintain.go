@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/TFMV/icebox/catalog"
+	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/tableops"
+	"github.com/spf13/cobra"
+)
+
+var maintainCmd = &cobra.Command{
+	Use:   "maintain",
+	Short: "Run table maintenance operations",
+	Long: `Run maintenance operations that keep table metadata compact.
+
+This command provides subcommands for maintenance operations:
+- rewrite-manifests: Merge manifests per partition spec and cluster them by partition
+- expire-metadata: Delete old vN.metadata.json files
+
+Examples:
+  icebox maintain rewrite-manifests sales
+  icebox maintain rewrite-manifests analytics.events --target-size 16777216
+  icebox maintain expire-metadata sales --retain 10`,
+}
+
+var maintainRewriteManifestsCmd = &cobra.Command{
+	Use:   "rewrite-manifests <table>",
+	Short: "Merge and cluster a table's manifests",
+	Long: `Rewrite the manifests of a table's current snapshot.
+
+Every append writes at least one new manifest, so tables with many small
+commits accumulate many small manifests. This command merges the data
+manifests of each partition spec into manifests of roughly the target size,
+with entries clustered by partition. Data files are not rewritten.
+
+The result is committed as a new 'replace' snapshot.
+
+Examples:
+  icebox maintain rewrite-manifests sales
+  icebox maintain rewrite-manifests sales --spec-id 0
+  icebox maintain rewrite-manifests sales --target-size 4194304`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMaintainRewriteManifests,
+}
+
+var maintainExpireMetadataCmd = &cobra.Command{
+	Use:   "expire-metadata <table>",
+	Short: "Delete old table metadata files",
+	Long: `Delete old vN.metadata.json files of a table.
+
+Each commit writes a new metadata file. This command keeps the current
+metadata file plus the most recent previous versions and deletes the rest.
+
+To prune automatically on every commit, set the table properties
+'write.metadata.delete-after-commit.enabled=true' and
+'write.metadata.previous-versions-max=<n>'.
+
+Examples:
+  icebox maintain expire-metadata sales
+  icebox maintain expire-metadata sales --retain 5`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMaintainExpireMetadata,
+}
+
+type maintainRewriteManifestsOptions struct {
+	targetSize int64
+	specID     int
+}
+
+type maintainExpireMetadataOptions struct {
+	retain int
+}
+
+var (
+	maintainRewriteManifestsOpts = &maintainRewriteManifestsOptions{}
+	maintainExpireMetadataOpts   = &maintainExpireMetadataOptions{}
+)
+
+func init() {
+	rootCmd.AddCommand(maintainCmd)
+
+	// Add subcommands
+	maintainCmd.AddCommand(maintainRewriteManifestsCmd)
+	maintainCmd.AddCommand(maintainExpireMetadataCmd)
+
+	// Rewrite manifests flags
+	maintainRewriteManifestsCmd.Flags().Int64Var(&maintainRewriteManifestsOpts.targetSize, "target-size", 0, "target manifest size in bytes (default: table property commit.manifest.target-size-bytes)")
+	maintainRewriteManifestsCmd.Flags().IntVar(&maintainRewriteManifestsOpts.specID, "spec-id", -1, "only rewrite manifests of this partition spec")
+
+	// Expire metadata flags
+	maintainExpireMetadataCmd.Flags().IntVar(&maintainExpireMetadataOpts.retain, "retain", 1, "number of previous metadata versions to keep")
+}
+
+func runMaintainRewriteManifests(cmd *cobra.Command, args []string) error {
+	// Find the Icebox configuration
+	_, cfg, err := config.FindConfig()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to find Icebox configuration\n"+
+			"💡 Try running 'icebox init' first to create a new project: %w", err)
+	}
+
+	// Make the embedded MinIO server reachable for projects that use it
+	stopStorage, err := startStorage(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	defer stopStorage()
+
+	// Create catalog
+	cat, err := catalog.NewCatalog(cfg)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to create catalog: %w", err)
+	}
+	defer cat.Close()
+
+	// Parse table identifier
+	tableIdent, _, err := parseTableIdentifier(args[0], "")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to parse table identifier: %w", err)
+	}
+
+	// Load the table
+	tbl, err := cat.LoadTable(cmd.Context(), tableIdent, nil)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load table: %w", err)
+	}
+
+	opts := &tableops.RewriteManifestsOptions{
+		TargetSizeBytes: maintainRewriteManifestsOpts.targetSize,
+	}
+	if maintainRewriteManifestsOpts.specID >= 0 {
+		opts.SpecID = &maintainRewriteManifestsOpts.specID
+	}
+
+	result, err := tableops.NewWriter(cat).RewriteManifests(cmd.Context(), tbl, opts)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to rewrite manifests: %w", err)
+	}
+
+	if result.RewrittenManifests == 0 {
+		fmt.Printf("✨ Manifests of %s are already compact, nothing to rewrite\n", args[0])
+		return nil
+	}
+
+	fmt.Printf("✅ Rewrote manifests for table %s\n", args[0])
+	fmt.Printf("   Snapshot ID: %d\n", result.SnapshotID)
+	fmt.Printf("   Manifests replaced: %d\n", result.RewrittenManifests)
+	fmt.Printf("   Manifests created: %d\n", result.AddedManifests)
+	fmt.Printf("   Manifests kept: %d\n", result.KeptManifests)
+	fmt.Printf("   Entries processed: %d\n", result.ProcessedEntries)
+	return nil
+}
+
+func runMaintainExpireMetadata(cmd *cobra.Command, args []string) error {
+	// Find the Icebox configuration
+	_, cfg, err := config.FindConfig()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to find Icebox configuration\n"+
+			"💡 Try running 'icebox init' first to create a new project: %w", err)
+	}
+
+	// Make the embedded MinIO server reachable for projects that use it
+	stopStorage, err := startStorage(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	defer stopStorage()
+
+	// Create catalog
+	cat, err := catalog.NewCatalog(cfg)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to create catalog: %w", err)
+	}
+	defer cat.Close()
+
+	expirer, ok := cat.(catalog.MetadataFileExpirer)
+	if !ok {
+		return fmt.Errorf("❌ Catalog type '%s' manages its own metadata files\n"+
+			"💡 Metadata expiry is only available for sqlite and json catalogs", cfg.Catalog.Type)
+	}
+
+	// Parse table identifier
+	tableIdent, _, err := parseTableIdentifier(args[0], "")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to parse table identifier: %w", err)
+	}
+
+	if maintainExpireMetadataOpts.retain < 0 {
+		return fmt.Errorf("❌ --retain must not be negative")
+	}
+
+	deleted, err := expirer.ExpireMetadataFiles(cmd.Context(), tableIdent, maintainExpireMetadataOpts.retain)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to expire metadata files: %w", err)
+	}
+
+	if len(deleted) == 0 {
+		fmt.Printf("✨ No metadata files to expire for table %s\n", args[0])
+		return nil
+	}
+
+	fmt.Printf("✅ Expired %d metadata files for table %s\n", len(deleted), args[0])
+	if cmd.Flag("verbose").Value.String() == "true" {
+		for _, location := range deleted {
+			fmt.Printf("   🗑️  %s\n", location)
+		}
+	}
+	return nil
+}
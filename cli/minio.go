@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/fs/minio"
+	"github.com/spf13/cobra"
+)
+
+var minioCmd = &cobra.Command{
+	Use:   "minio",
+	Short: "Manage the embedded MinIO server",
+	Long: `Manage the embedded S3-compatible server of a project initialized with
+'icebox init --storage minio'.
+
+Commands that read or write table data start the server automatically when it
+is not running and stop it when they finish. Run it explicitly to share one
+server between several commands or to point other S3 tools at it.
+
+This command provides subcommands for the server:
+- start: Run the server in the foreground until interrupted
+- status: Show whether the server is running and where
+- stop: Stop a server started with 'icebox minio start'
+
+Examples:
+  icebox minio start
+  icebox minio status
+  icebox minio stop`,
+}
+
+var minioStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the embedded MinIO server",
+	Long: `Start the project's embedded MinIO server and keep it running until
+interrupted with Ctrl+C or stopped with 'icebox minio stop'.`,
+	RunE: runMinIOStart,
+}
+
+var minioStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the status of the embedded MinIO server",
+	RunE:  runMinIOStatus,
+}
+
+var minioStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the embedded MinIO server",
+	RunE:  runMinIOStop,
+}
+
+type minioStopOptions struct {
+	timeout time.Duration
+}
+
+var minioStopOpts = &minioStopOptions{}
+
+func init() {
+	rootCmd.AddCommand(minioCmd)
+
+	minioCmd.AddCommand(minioStartCmd)
+	minioCmd.AddCommand(minioStatusCmd)
+	minioCmd.AddCommand(minioStopCmd)
+
+	minioStopCmd.Flags().DurationVar(&minioStopOpts.timeout, "timeout", 10*time.Second, "how long to wait for the server to stop")
+}
+
+func runMinIOStart(cmd *cobra.Command, args []string) error {
+	projectConfig, err := loadMinIOProjectConfig()
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if endpoint := minio.RunningEndpoint(ctx, projectConfig); endpoint != "" {
+		return fmt.Errorf("❌ Embedded MinIO is already running at %s\n"+
+			"💡 Use 'icebox minio stop' to stop it", endpoint)
+	}
+
+	serverConfig := minio.ServerConfigFromProject(projectConfig)
+	serverConfig.Quiet = cmd.Flag("verbose").Value.String() != "true"
+
+	server, err := minio.NewEmbeddedMinIO(serverConfig)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to create embedded MinIO server: %w", err)
+	}
+	if err := server.Start(ctx); err != nil {
+		return fmt.Errorf("❌ Failed to start embedded MinIO server: %w", err)
+	}
+
+	state := &minio.ServerState{
+		PID:       os.Getpid(),
+		Endpoint:  server.GetActualURL(),
+		StartedAt: time.Now(),
+	}
+	if err := minio.WriteState(projectConfig.DataDir, state); err != nil {
+		server.Stop(context.Background())
+		return fmt.Errorf("❌ Failed to record embedded MinIO state: %w", err)
+	}
+	defer minio.RemoveState(projectConfig.DataDir)
+
+	fmt.Printf("✅ Embedded MinIO listening on %s\n", state.Endpoint)
+	fmt.Printf("   Bucket: %s\n", projectConfig.Bucket)
+	fmt.Printf("   Access Key: %s\n", projectConfig.AccessKey)
+	fmt.Printf("   Data Directory: %s\n", projectConfig.DataDir)
+	fmt.Printf("\nPress Ctrl+C to stop\n")
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	fmt.Printf("\n🛑 Shutting down embedded MinIO...\n")
+	if err := server.Stop(context.Background()); err != nil {
+		return fmt.Errorf("❌ Failed to stop embedded MinIO server: %w", err)
+	}
+	fmt.Printf("✅ Embedded MinIO stopped\n")
+	return nil
+}
+
+func runMinIOStatus(cmd *cobra.Command, args []string) error {
+	projectConfig, err := loadMinIOProjectConfig()
+	if err != nil {
+		return err
+	}
+
+	endpoint := minio.RunningEndpoint(cmd.Context(), projectConfig)
+	if endpoint == "" {
+		fmt.Printf("⏹️  Embedded MinIO is not running\n")
+		if projectConfig.AutoStart {
+			fmt.Printf("   It starts automatically with commands that need it\n")
+		}
+		fmt.Printf("💡 Use 'icebox minio start' to run it in the foreground\n")
+		return nil
+	}
+
+	fmt.Printf("✅ Embedded MinIO is running\n")
+	fmt.Printf("   Endpoint: %s\n", endpoint)
+	if state, err := minio.ReadState(projectConfig.DataDir); err == nil && state != nil && state.Endpoint == endpoint {
+		fmt.Printf("   PID: %d\n", state.PID)
+		fmt.Printf("   Uptime: %s\n", time.Since(state.StartedAt).Round(time.Second))
+	}
+	fmt.Printf("   Bucket: %s\n", projectConfig.Bucket)
+	fmt.Printf("   Data Directory: %s\n", projectConfig.DataDir)
+	return nil
+}
+
+func runMinIOStop(cmd *cobra.Command, args []string) error {
+	projectConfig, err := loadMinIOProjectConfig()
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	state, err := minio.ReadState(projectConfig.DataDir)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	if state == nil || !minio.IsHealthy(ctx, state.Endpoint) {
+		// A state file without a healthy server is left over from a crash
+		if state != nil {
+			minio.RemoveState(projectConfig.DataDir)
+		}
+		if endpoint := minio.RunningEndpoint(ctx, projectConfig); endpoint != "" {
+			return fmt.Errorf("❌ The server at %s was not started with 'icebox minio start'", endpoint)
+		}
+		fmt.Printf("⏹️  Embedded MinIO is not running\n")
+		return nil
+	}
+
+	process, err := os.FindProcess(state.PID)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to find embedded MinIO process %d: %w", state.PID, err)
+	}
+	if err := process.Signal(syscall.SIGTERM); err != nil {
+		return fmt.Errorf("❌ Failed to stop embedded MinIO process %d: %w", state.PID, err)
+	}
+
+	deadline := time.Now().Add(minioStopOpts.timeout)
+	for minio.IsHealthy(ctx, state.Endpoint) {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("❌ Embedded MinIO did not stop within %s", minioStopOpts.timeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	fmt.Printf("✅ Embedded MinIO stopped\n")
+	return nil
+}
+
+// loadMinIOProjectConfig returns the embedded MinIO settings of the current project
+func loadMinIOProjectConfig() (*config.MinIOConfig, error) {
+	_, cfg, err := config.FindConfig()
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to find Icebox configuration\n"+
+			"💡 Try running 'icebox init --storage minio' first to create a new project: %w", err)
+	}
+
+	if cfg.Storage.MinIO == nil {
+		return nil, fmt.Errorf("❌ This project does not use embedded MinIO storage\n" +
+			"💡 Create one with 'icebox init --storage minio'")
+	}
+	return cfg.Storage.MinIO, nil
+}
+
+// startStorage makes the embedded MinIO server of a MinIO-backed project reachable for a
+// command. The returned function stops the server again if this call had to start it.
+func startStorage(ctx context.Context, cfg *config.Config) (func(), error) {
+	server, err := minio.EnsureRunning(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to start embedded MinIO: %w\n"+
+			"💡 Run 'icebox minio start' in another terminal", err)
+	}
+
+	return func() {
+		if server == nil {
+			return
+		}
+		if err := server.Stop(context.Background()); err != nil {
+			fmt.Printf("⚠️  Warning: Failed to stop embedded MinIO: %v\n", err)
+		}
+	}, nil
+}
@@ -0,0 +1,1043 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/TFMV/icebox/auth"
+	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/engine/duckdb"
+	"github.com/jackc/pgx/v5/pgproto3"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// pgServerVersion is the PostgreSQL version reported to clients, some of which check it
+// before using newer catalog queries
+const pgServerVersion = "15.0"
+
+// pgEngine runs the SQL of pgwire sessions
+type pgEngine interface {
+	ExecuteQuery(ctx context.Context, query string, args ...interface{}) (*duckdb.QueryResult, error)
+}
+
+// pgServer serves the PostgreSQL wire protocol on top of the SQL engine, so that psql,
+// JDBC drivers and BI tools can query the catalog's tables. Queries about pg_catalog and
+// information_schema are answered by DuckDB's own compatibility views, which only list
+// the tables a session may read.
+type pgServer struct {
+	engine        pgEngine
+	users         map[string]string
+	authenticator *auth.Authenticator // also accepts the credentials of the auth section when set
+	access        *accessControl      // nil when authentication is disabled
+	tlsConfig     *tls.Config
+
+	mu       sync.Mutex
+	sessions map[uint32]*pgSession
+	nextID   uint32
+}
+
+// newPGServer creates a PostgreSQL wire-protocol server. Clients log in with the users of
+// the pgwire configuration, whose passwords must be hashed, or, when an authenticator is
+// given, with a basic user, API key or JWT as password; their statements are then checked
+// against the grants of access. When a TLS configuration is given, clients must upgrade
+// to TLS before they send a password.
+func newPGServer(engine pgEngine, cfg *config.PGWireConfig, authenticator *auth.Authenticator, access *accessControl, tlsConfig *tls.Config) (*pgServer, error) {
+	s := &pgServer{
+		engine:        engine,
+		authenticator: authenticator,
+		access:        access,
+		tlsConfig:     tlsConfig,
+		sessions:      make(map[uint32]*pgSession),
+	}
+	if cfg != nil {
+		for user, hash := range cfg.Users {
+			if err := auth.ValidatePasswordHash(hash); err != nil {
+				return nil, fmt.Errorf("invalid password hash for pgwire user %q: %w", user, err)
+			}
+		}
+		s.users = cfg.Users
+	}
+	return s, nil
+}
+
+// authenticates reports whether clients must log in with a password
+func (s *pgServer) authenticates() bool {
+	return len(s.users) > 0 || s.authenticator != nil
+}
+
+// Serve accepts connections until the listener is closed
+func (s *pgServer) Serve(lis net.Listener) error {
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go s.handleConn(conn)
+	}
+}
+
+func (s *pgServer) handleConn(conn net.Conn) {
+	defer conn.Close()
+
+	session, err := s.startup(conn)
+	if session != nil {
+		defer s.closeSession(session)
+	}
+	if err != nil || session == nil {
+		return
+	}
+	session.serve()
+}
+
+// startup handles the messages sent before a session starts: TLS negotiation,
+// cancellation of another session's query, and the startup message itself. It returns a
+// nil session when the connection ends without starting one.
+func (s *pgServer) startup(conn net.Conn) (*pgSession, error) {
+	backend := pgproto3.NewBackend(conn, conn)
+	for {
+		msg, err := backend.ReceiveStartupMessage()
+		if err != nil {
+			return nil, err
+		}
+
+		switch msg := msg.(type) {
+		case *pgproto3.SSLRequest:
+			if s.tlsConfig == nil {
+				if _, err := conn.Write([]byte{'N'}); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if _, err := conn.Write([]byte{'S'}); err != nil {
+				return nil, err
+			}
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return nil, err
+			}
+			conn = tlsConn
+			backend = pgproto3.NewBackend(conn, conn)
+		case *pgproto3.GSSEncRequest:
+			if _, err := conn.Write([]byte{'N'}); err != nil {
+				return nil, err
+			}
+		case *pgproto3.CancelRequest:
+			s.cancelQuery(msg.ProcessID, msg.SecretKey)
+			return nil, nil
+		case *pgproto3.StartupMessage:
+			_, secure := conn.(*tls.Conn)
+			return s.authenticate(backend, msg, conn.RemoteAddr().String(), secure)
+		default:
+			return nil, fmt.Errorf("unexpected startup message %T", msg)
+		}
+	}
+}
+
+// authenticate checks the client's password when users or an authenticator are configured
+// and starts its session. Passwords are sent in clear text, so they are only asked for
+// over TLS when the server has a certificate.
+func (s *pgServer) authenticate(backend *pgproto3.Backend, msg *pgproto3.StartupMessage, remoteAddr string, secure bool) (*pgSession, error) {
+	user := msg.Parameters["user"]
+	var principal *auth.Principal
+	if s.authenticates() {
+		if s.tlsConfig != nil && !secure {
+			backend.Send(&pgproto3.ErrorResponse{
+				Severity:            "FATAL",
+				SeverityUnlocalized: "FATAL",
+				Code:                "28000",
+				Message:             "password authentication requires SSL; connect with sslmode=require",
+			})
+			return nil, backend.Flush()
+		}
+
+		backend.Send(&pgproto3.AuthenticationCleartextPassword{})
+		if err := backend.Flush(); err != nil {
+			return nil, err
+		}
+		if err := backend.SetAuthType(pgproto3.AuthTypeCleartextPassword); err != nil {
+			return nil, err
+		}
+		reply, err := backend.Receive()
+		if err != nil {
+			return nil, err
+		}
+
+		password, ok := reply.(*pgproto3.PasswordMessage)
+		if ok {
+			principal, ok = s.checkCredentials(user, password.Password, remoteAddr)
+		}
+		if !ok {
+			backend.Send(&pgproto3.ErrorResponse{
+				Severity:            "FATAL",
+				SeverityUnlocalized: "FATAL",
+				Code:                "28P01",
+				Message:             fmt.Sprintf("password authentication failed for user %q", user),
+			})
+			return nil, backend.Flush()
+		}
+	}
+
+	session := s.newSession(backend, user, msg.Parameters["application_name"])
+	session.principal = principal
+	backend.Send(&pgproto3.AuthenticationOk{})
+	for _, name := range pgReportedSettings {
+		backend.Send(&pgproto3.ParameterStatus{Name: name, Value: session.settings[strings.ToLower(name)]})
+	}
+	backend.Send(&pgproto3.BackendKeyData{ProcessID: session.processID, SecretKey: session.secretKey})
+	backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+	return session, backend.Flush()
+}
+
+// checkCredentials checks a user's password against the pgwire users and then the
+// authenticator. Users of the pgwire configuration are principals without roles.
+func (s *pgServer) checkCredentials(user, password, remoteAddr string) (*auth.Principal, bool) {
+	if hash, known := s.users[user]; known && auth.CheckPasswordHash(hash, password) {
+		return &auth.Principal{Name: user, Method: auth.MethodBasic}, true
+	}
+	if s.authenticator == nil {
+		return nil, false
+	}
+	principal, err := s.authenticator.AuthenticatePassword(auth.Request{Transport: "pgwire", RemoteAddr: remoteAddr}, user, password)
+	return principal, err == nil
+}
+
+func (s *pgServer) newSession(backend *pgproto3.Backend, user, applicationName string) *pgSession {
+	var key [4]byte
+	_, _ = rand.Read(key[:])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	session := &pgSession{
+		server:     s,
+		backend:    backend,
+		types:      pgtype.NewMap(),
+		processID:  s.nextID,
+		secretKey:  binary.BigEndian.Uint32(key[:]),
+		settings:   pgDefaultSettings(user, applicationName),
+		txStatus:   'I',
+		statements: make(map[string]*pgStatement),
+		portals:    make(map[string]*pgPortal),
+	}
+	s.sessions[session.processID] = session
+	return session
+}
+
+func (s *pgServer) closeSession(session *pgSession) {
+	s.mu.Lock()
+	delete(s.sessions, session.processID)
+	s.mu.Unlock()
+	session.cancel()
+}
+
+// cancelQuery cancels the running query of the session with the given key
+func (s *pgServer) cancelQuery(processID, secretKey uint32) {
+	s.mu.Lock()
+	session := s.sessions[processID]
+	s.mu.Unlock()
+	if session != nil && session.secretKey == secretKey {
+		session.cancel()
+	}
+}
+
+// pgReportedSettings are sent to clients when their session starts and when they change
+var pgReportedSettings = []string{
+	"server_version",
+	"server_encoding",
+	"client_encoding",
+	"application_name",
+	"DateStyle",
+	"TimeZone",
+	"IntervalStyle",
+	"integer_datetimes",
+	"standard_conforming_strings",
+	"is_superuser",
+	"session_authorization",
+}
+
+// pgDefaultSettings returns the settings of a new session, keyed by lower-case name
+func pgDefaultSettings(user, applicationName string) map[string]string {
+	return map[string]string{
+		"server_version":              pgServerVersion,
+		"server_encoding":             "UTF8",
+		"client_encoding":             "UTF8",
+		"application_name":            applicationName,
+		"datestyle":                   "ISO, MDY",
+		"timezone":                    "UTC",
+		"intervalstyle":               "postgres",
+		"integer_datetimes":           "on",
+		"standard_conforming_strings": "on",
+		"is_superuser":                "off",
+		"session_authorization":       user,
+		"search_path":                 "main",
+		"transaction_isolation":       "read committed",
+		"max_identifier_length":       "63",
+	}
+}
+
+// pgSession is one client connection. Its messages are handled one at a time; only
+// cancellation requests arrive on other connections.
+type pgSession struct {
+	server    *pgServer
+	backend   *pgproto3.Backend
+	principal *auth.Principal // set when the client logged in with a password
+	types     *pgtype.Map
+	processID uint32
+	secretKey uint32
+	settings  map[string]string
+	txStatus  byte
+
+	statements map[string]*pgStatement
+	portals    map[string]*pgPortal
+	skipToSync bool // set by errors in the extended query protocol
+
+	mu          sync.Mutex
+	cancelQuery context.CancelFunc
+}
+
+// pgStatement is a statement prepared with a Parse message
+type pgStatement struct {
+	query       string
+	paramOIDs   []uint32
+	description *pgResult // set when the statement is described, holding no rows
+	result      *pgResult // the result of a statement that ran to be described, until bound
+}
+
+// pgPortal is a statement bound to parameters. It runs when first described or executed,
+// unless its statement already ran to be described, and returns its rows over one or
+// more Execute messages.
+type pgPortal struct {
+	statement     *pgStatement
+	args          []interface{}
+	resultFormats []int16
+	result        *pgResult
+	oids          []uint32 // the column types the client was told about
+	sent          int
+}
+
+// pgResult is the outcome of a statement as sent to the client
+type pgResult struct {
+	columns []string
+	oids    []uint32
+	rows    [][]interface{}
+	tag     string
+	hasRows bool // whether the statement returns rows, even when none matched
+}
+
+// pgError is an error reported to the client with a specific SQLSTATE code
+type pgError struct {
+	code    string
+	message string
+}
+
+func (e *pgError) Error() string {
+	return e.message
+}
+
+// serve handles the session's messages until the client disconnects
+func (s *pgSession) serve() {
+	for {
+		msg, err := s.backend.Receive()
+		if err != nil {
+			return
+		}
+		if _, ok := msg.(*pgproto3.Sync); s.skipToSync && !ok {
+			continue
+		}
+
+		switch msg := msg.(type) {
+		case *pgproto3.Query:
+			s.simpleQuery(msg.String)
+		case *pgproto3.Parse:
+			err = s.parse(msg)
+		case *pgproto3.Bind:
+			err = s.bind(msg)
+		case *pgproto3.Describe:
+			err = s.describe(msg)
+		case *pgproto3.Execute:
+			err = s.execute(msg)
+		case *pgproto3.Close:
+			if msg.ObjectType == 'S' {
+				delete(s.statements, msg.Name)
+			} else {
+				delete(s.portals, msg.Name)
+			}
+			s.backend.Send(&pgproto3.CloseComplete{})
+		case *pgproto3.Sync:
+			s.skipToSync = false
+			if s.txStatus == 'I' {
+				s.portals = make(map[string]*pgPortal)
+			}
+			s.backend.Send(&pgproto3.ReadyForQuery{TxStatus: s.txStatus})
+		case *pgproto3.Flush:
+		case *pgproto3.Terminate:
+			return
+		default:
+			err = &pgError{code: "08P01", message: fmt.Sprintf("unsupported message %T", msg)}
+		}
+
+		if err != nil {
+			s.sendError(err)
+			s.skipToSync = true
+		}
+
+		switch msg.(type) {
+		case *pgproto3.Query, *pgproto3.Sync, *pgproto3.Flush:
+			if err := s.backend.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// simpleQuery runs the statements of a Query message in order, stopping at the first error
+func (s *pgSession) simpleQuery(query string) {
+	defer func() { s.backend.Send(&pgproto3.ReadyForQuery{TxStatus: s.txStatus}) }()
+
+	statements := splitStatements(query)
+	if len(statements) == 0 {
+		s.backend.Send(&pgproto3.EmptyQueryResponse{})
+		return
+	}
+
+	for _, statement := range statements {
+		result, err := s.run(statement, nil)
+		if err != nil {
+			s.sendError(err)
+			return
+		}
+		if result.hasRows {
+			s.backend.Send(result.rowDescription(nil))
+			if _, err := s.sendRows(result.rows, result.oids, nil); err != nil {
+				s.sendError(err)
+				return
+			}
+		}
+		s.backend.Send(&pgproto3.CommandComplete{CommandTag: []byte(result.tag)})
+	}
+}
+
+func (s *pgSession) parse(msg *pgproto3.Parse) error {
+	if _, exists := s.statements[msg.Name]; exists && msg.Name != "" {
+		return &pgError{code: "42P05", message: fmt.Sprintf("prepared statement %q already exists", msg.Name)}
+	}
+	if len(splitStatements(msg.Query)) > 1 {
+		return &pgError{code: "42601", message: "cannot insert multiple commands into a prepared statement"}
+	}
+
+	// Parameters without a declared type stay unspecified (0), so clients send them as
+	// text to be cast by DuckDB
+	oids := make([]uint32, max(sqlParameters(msg.Query), len(msg.ParameterOIDs)))
+	copy(oids, msg.ParameterOIDs)
+
+	s.statements[msg.Name] = &pgStatement{query: msg.Query, paramOIDs: oids}
+	s.backend.Send(&pgproto3.ParseComplete{})
+	return nil
+}
+
+func (s *pgSession) bind(msg *pgproto3.Bind) error {
+	statement, ok := s.statements[msg.PreparedStatement]
+	if !ok {
+		return &pgError{code: "26000", message: fmt.Sprintf("prepared statement %q does not exist", msg.PreparedStatement)}
+	}
+	if len(msg.Parameters) != len(statement.paramOIDs) {
+		return &pgError{code: "08P01", message: fmt.Sprintf("bind message supplies %d parameters, but prepared statement %q requires %d",
+			len(msg.Parameters), msg.PreparedStatement, len(statement.paramOIDs))}
+	}
+
+	args := make([]interface{}, len(msg.Parameters))
+	for i, src := range msg.Parameters {
+		arg, err := decodePGParameter(s.types, statement.paramOIDs[i], formatCode(msg.ParameterFormatCodes, i), src)
+		if err != nil {
+			return &pgError{code: "22P02", message: fmt.Sprintf("parameter $%d: %v", i+1, err)}
+		}
+		args[i] = arg
+	}
+
+	// The first portal of a statement that ran to be described, which takes no
+	// parameters, gets that result rather than running the statement again
+	s.portals[msg.DestinationPortal] = &pgPortal{
+		statement:     statement,
+		args:          args,
+		resultFormats: msg.ResultFormatCodes,
+		result:        statement.result,
+	}
+	statement.result = nil
+	s.backend.Send(&pgproto3.BindComplete{})
+	return nil
+}
+
+func (s *pgSession) describe(msg *pgproto3.Describe) error {
+	if msg.ObjectType == 'S' {
+		statement, ok := s.statements[msg.Name]
+		if !ok {
+			return &pgError{code: "26000", message: fmt.Sprintf("prepared statement %q does not exist", msg.Name)}
+		}
+		if statement.description == nil {
+			description, result, err := s.describeStatement(statement.query)
+			if err != nil {
+				return err
+			}
+			statement.description, statement.result = description, result
+		}
+
+		s.backend.Send(&pgproto3.ParameterDescription{ParameterOIDs: statement.paramOIDs})
+		if statement.description.hasRows {
+			s.backend.Send(statement.description.rowDescription(nil))
+		} else {
+			s.backend.Send(&pgproto3.NoData{})
+		}
+		return nil
+	}
+
+	portal, err := s.runPortal(msg.Name)
+	if err != nil {
+		return err
+	}
+	if !portal.result.hasRows {
+		s.backend.Send(&pgproto3.NoData{})
+		return nil
+	}
+	portal.oids = portal.result.oids
+	s.backend.Send(portal.result.rowDescription(portal.resultFormats))
+	return nil
+}
+
+// describeStatement returns the columns a statement would return. Queries are described
+// by DuckDB with NULL for their parameters, without running them; other statements that
+// return rows only read metadata and are run, and their result is returned too so that
+// executing the statement does not run it again.
+func (s *pgSession) describeStatement(query string) (description, result *pgResult, err error) {
+	if result, ok := s.sessionCommand(query, false); ok {
+		return result, nil, nil
+	}
+
+	keywords := sqlKeywords(query)
+	if len(keywords) == 0 {
+		return &pgResult{}, nil, nil
+	}
+
+	switch keywords[0] {
+	case "SELECT", "WITH", "VALUES", "FROM", "TABLE":
+		inner := strings.TrimRight(strings.TrimSpace(nullParameters(query)), ";")
+		described, err := s.execSQL("DESCRIBE SELECT * FROM ("+inner+")", nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		description := &pgResult{hasRows: true}
+		for _, row := range described.Rows() {
+			description.columns = append(description.columns, fmt.Sprint(row[0]))
+			description.oids = append(description.oids, pgTypeOID(fmt.Sprint(row[1])))
+		}
+		return description, nil, nil
+	case "SHOW", "DESCRIBE", "PRAGMA", "EXPLAIN", "SUMMARIZE", "CALL":
+		result, err := s.run(query, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &pgResult{columns: result.columns, oids: result.oids, hasRows: true}, result, nil
+	default:
+		return &pgResult{}, nil, nil
+	}
+}
+
+func (s *pgSession) execute(msg *pgproto3.Execute) error {
+	portal, err := s.runPortal(msg.Portal)
+	if err != nil {
+		return err
+	}
+
+	result := portal.result
+	if !result.hasRows {
+		s.backend.Send(&pgproto3.CommandComplete{CommandTag: []byte(result.tag)})
+		return nil
+	}
+
+	// Rows are encoded as the types the client was told about: those of the portal, or of
+	// the statement when only the statement was described
+	oids := portal.oids
+	if oids == nil {
+		oids = result.oids
+		if description := portal.statement.description; description != nil && len(description.oids) == len(oids) {
+			oids = description.oids
+		}
+	}
+
+	rows := result.rows[portal.sent:]
+	suspended := msg.MaxRows > 0 && int(msg.MaxRows) < len(rows)
+	if suspended {
+		rows = rows[:msg.MaxRows]
+	}
+	sent, err := s.sendRows(rows, oids, portal.resultFormats)
+	portal.sent += sent
+	if err != nil {
+		return err
+	}
+
+	if suspended {
+		s.backend.Send(&pgproto3.PortalSuspended{})
+	} else {
+		s.backend.Send(&pgproto3.CommandComplete{CommandTag: []byte(result.tag)})
+	}
+	return nil
+}
+
+// runPortal runs a portal's statement unless it already ran
+func (s *pgSession) runPortal(name string) (*pgPortal, error) {
+	portal, ok := s.portals[name]
+	if !ok {
+		return nil, &pgError{code: "34000", message: fmt.Sprintf("portal %q does not exist", name)}
+	}
+	if portal.result == nil {
+		result, err := s.run(portal.statement.query, portal.args)
+		if err != nil {
+			return nil, err
+		}
+		portal.result = result
+	}
+	return portal, nil
+}
+
+// sendRows sends rows as DataRow messages and returns how many were sent
+func (s *pgSession) sendRows(rows [][]interface{}, oids []uint32, formats []int16) (int, error) {
+	for n, row := range rows {
+		values := make([][]byte, len(row))
+		for i, value := range row {
+			encoded, err := encodePGValue(s.types, oids[i], formatCode(formats, i), value)
+			if err != nil {
+				return n, &pgError{code: "22000", message: err.Error()}
+			}
+			values[i] = encoded
+		}
+		s.backend.Send(&pgproto3.DataRow{Values: values})
+	}
+	return len(rows), nil
+}
+
+func (s *pgSession) sendError(err error) {
+	response := &pgproto3.ErrorResponse{
+		Severity:            "ERROR",
+		SeverityUnlocalized: "ERROR",
+		Code:                pgErrorCode(err),
+		Message:             err.Error(),
+	}
+	s.backend.Send(response)
+}
+
+// run runs a statement, answering session commands itself and passing everything else to
+// the SQL engine
+func (s *pgSession) run(query string, args []interface{}) (*pgResult, error) {
+	if result, ok := s.sessionCommand(query, true); ok {
+		return result, nil
+	}
+
+	result, err := s.execSQL(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return newPGResult(query, result), nil
+}
+
+// execSQL runs SQL on the engine once the session's principal is allowed to. The query
+// can be canceled with a CancelRequest.
+func (s *pgSession) execSQL(query string, args []interface{}) (*duckdb.QueryResult, error) {
+	ctx, cancel := context.WithCancel(auth.WithPrincipal(context.Background(), s.principal))
+	s.mu.Lock()
+	s.cancelQuery = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.cancelQuery = nil
+		s.mu.Unlock()
+		cancel()
+	}()
+
+	// Drivers and BI tools look up tables in DuckDB's catalog views, which only list
+	// the tables the session may read
+	query, err := s.server.access.authorizeCatalogQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.server.engine.ExecuteQuery(ctx, query, args...)
+	if err != nil && ctx.Err() != nil {
+		return nil, &pgError{code: "57014", message: "canceling statement due to user request"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Results are sent whole, so one cut off at the engine's row limit is an error
+	if err := completeResult(result); err != nil {
+		return nil, &pgError{code: "54000", message: err.Error()} // program_limit_exceeded
+	}
+	return result, nil
+}
+
+// cancel cancels the session's running query, if any
+func (s *pgSession) cancel() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancelQuery != nil {
+		s.cancelQuery()
+	}
+}
+
+var (
+	pgSetPattern     = regexp.MustCompile(`(?is)^SET\s+(?:SESSION\s+|LOCAL\s+)?(?:TIME\s+ZONE\s+(.+)|([\w.]+)\s*(?:=|\s+TO\s+)\s*(.+))$`)
+	pgVersionPattern = regexp.MustCompile(`(?is)^SELECT\s+(?:pg_catalog\.)?version\(\s*\)$`)
+
+	pgReturningPattern = regexp.MustCompile(`(?i)\bRETURNING\b`)
+)
+
+// sessionCommand answers the statements that concern the session rather than data:
+// transaction control, SET, SHOW of session settings, DISCARD, DEALLOCATE and version().
+// Statements run in auto-commit mode, so transaction control only tracks the status
+// reported to the client. With apply false, the command's effects are not applied.
+func (s *pgSession) sessionCommand(query string, apply bool) (*pgResult, bool) {
+	statement := strings.TrimSpace(strings.TrimSuffix(stripLeadingComments(query), ";"))
+	keywords := sqlKeywords(statement)
+	if len(keywords) == 0 {
+		return nil, false
+	}
+
+	setTxStatus := func(status byte) {
+		if apply {
+			s.txStatus = status
+		}
+	}
+
+	switch keywords[0] {
+	case "BEGIN":
+		setTxStatus('T')
+		return &pgResult{tag: "BEGIN"}, true
+	case "START":
+		if len(keywords) > 1 && keywords[1] == "TRANSACTION" {
+			setTxStatus('T')
+			return &pgResult{tag: "START TRANSACTION"}, true
+		}
+	case "COMMIT", "END":
+		setTxStatus('I')
+		return &pgResult{tag: "COMMIT"}, true
+	case "ROLLBACK", "ABORT":
+		setTxStatus('I')
+		return &pgResult{tag: "ROLLBACK"}, true
+	case "SET":
+		if match := pgSetPattern.FindStringSubmatch(statement); match != nil && apply {
+			name, value := "timezone", match[1]
+			if match[2] != "" {
+				name, value = match[2], match[3]
+			}
+			s.setSetting(name, strings.Trim(strings.TrimSpace(value), `'"`))
+		}
+		return &pgResult{tag: "SET"}, true
+	case "RESET":
+		if apply && len(keywords) > 1 {
+			defaults := pgDefaultSettings(s.settings["session_authorization"], "")
+			if keywords[1] == "ALL" {
+				s.settings = defaults
+			} else {
+				s.setSetting(keywords[1], defaults[strings.ToLower(keywords[1])])
+			}
+		}
+		return &pgResult{tag: "RESET"}, true
+	case "DISCARD":
+		if apply {
+			s.settings = pgDefaultSettings(s.settings["session_authorization"], s.settings["application_name"])
+			s.statements = make(map[string]*pgStatement)
+		}
+		return &pgResult{tag: "DISCARD ALL"}, true
+	case "DEALLOCATE":
+		if fields := strings.Fields(statement); apply && len(fields) > 1 {
+			if name := strings.Trim(fields[len(fields)-1], `"`); strings.EqualFold(name, "ALL") {
+				s.statements = make(map[string]*pgStatement)
+			} else {
+				delete(s.statements, name)
+			}
+		}
+		return &pgResult{tag: "DEALLOCATE"}, true
+	case "SHOW":
+		name := strings.ToLower(strings.Join(keywords[1:], " "))
+		if name == "transaction isolation level" {
+			name = "transaction_isolation"
+		}
+		if value, ok := s.settings[name]; ok {
+			return &pgResult{
+				columns: []string{name},
+				oids:    []uint32{pgtype.TextOID},
+				rows:    [][]interface{}{{value}},
+				tag:     "SHOW",
+				hasRows: true,
+			}, true
+		}
+	case "SELECT":
+		if pgVersionPattern.MatchString(statement) {
+			return &pgResult{
+				columns: []string{"version"},
+				oids:    []uint32{pgtype.TextOID},
+				rows:    [][]interface{}{{fmt.Sprintf("PostgreSQL %s (Icebox %s on DuckDB)", pgServerVersion, rootCmd.Version)}},
+				tag:     "SELECT 1",
+				hasRows: true,
+			}, true
+		}
+	}
+	return nil, false
+}
+
+// setSetting changes a session setting, reporting it to the client when it is one of
+// the reported settings
+func (s *pgSession) setSetting(name, value string) {
+	s.settings[strings.ToLower(name)] = value
+	for _, reported := range pgReportedSettings {
+		if strings.EqualFold(reported, name) {
+			s.backend.Send(&pgproto3.ParameterStatus{Name: reported, Value: value})
+		}
+	}
+}
+
+// newPGResult describes a result of the SQL engine the way PostgreSQL reports it
+func newPGResult(query string, result *duckdb.QueryResult) *pgResult {
+	res := &pgResult{
+		columns: result.Columns,
+		oids:    make([]uint32, len(result.Columns)),
+		rows:    result.Rows(),
+	}
+	for i := range res.oids {
+		res.oids[i] = pgtype.TextOID
+		if i < len(result.ColumnTypes) {
+			res.oids[i] = pgTypeOID(result.ColumnTypes[i])
+		}
+	}
+
+	keywords := sqlKeywords(query)
+	keyword := ""
+	if len(keywords) > 0 {
+		keyword = keywords[0]
+	}
+
+	switch keyword {
+	case "SELECT", "WITH", "VALUES", "FROM", "TABLE", "SHOW", "DESCRIBE", "PRAGMA", "EXPLAIN", "SUMMARIZE", "CALL":
+		res.hasRows = true
+		res.tag = fmt.Sprintf("SELECT %d", len(res.rows))
+	case "INSERT", "UPDATE", "DELETE":
+		count := affectedRows(result)
+		if pgReturningPattern.MatchString(query) {
+			res.hasRows = true
+			count = int64(len(res.rows))
+		}
+		res.tag = fmt.Sprintf("%s %d", keyword, count)
+		if keyword == "INSERT" {
+			res.tag = fmt.Sprintf("INSERT 0 %d", count)
+		}
+	case "CREATE", "DROP", "ALTER":
+		res.tag = keyword
+		for _, word := range keywords[1:] {
+			if word != "OR" && word != "REPLACE" && word != "TEMP" && word != "TEMPORARY" && word != "UNIQUE" {
+				res.tag += " " + word
+				break
+			}
+		}
+	default:
+		res.tag = keyword
+	}
+	return res
+}
+
+func (r *pgResult) rowDescription(formats []int16) *pgproto3.RowDescription {
+	fields := make([]pgproto3.FieldDescription, len(r.columns))
+	for i, name := range r.columns {
+		fields[i] = pgproto3.FieldDescription{
+			Name:         []byte(name),
+			DataTypeOID:  r.oids[i],
+			DataTypeSize: pgTypeSize(r.oids[i]),
+			TypeModifier: -1,
+			Format:       formatCode(formats, i),
+		}
+	}
+	return &pgproto3.RowDescription{Fields: fields}
+}
+
+// formatCode returns the format of the i-th value from a list of format codes, which
+// either has one code per value, one code for all values, or none for text
+func formatCode(codes []int16, i int) int16 {
+	switch {
+	case len(codes) == 1:
+		return codes[0]
+	case i < len(codes):
+		return codes[i]
+	default:
+		return pgtype.TextFormatCode
+	}
+}
+
+// pgErrorCode returns the SQLSTATE code reported for an error
+func pgErrorCode(err error) string {
+	var pgErr *pgError
+	if errors.As(err, &pgErr) {
+		return pgErr.code
+	}
+	if errors.Is(err, auth.ErrPermissionDenied) {
+		return "42501" // insufficient_privilege
+	}
+
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "Parser Error"):
+		return "42601" // syntax_error
+	case strings.Contains(message, "Catalog Error"):
+		return "42P01" // undefined_table
+	case strings.Contains(message, "Binder Error"):
+		return "42703" // undefined_column
+	case strings.Contains(message, "Conversion Error"):
+		return "22P02" // invalid_text_representation
+	case strings.Contains(message, "Constraint Error"):
+		return "23000" // integrity_constraint_violation
+	default:
+		return "XX000" // internal_error
+	}
+}
+
+// sqlCode calls fn with the offset of every byte of query outside string literals, quoted
+// identifiers and comments
+func sqlCode(query string, fn func(i int)) {
+	for i := 0; i < len(query); i++ {
+		switch {
+		case query[i] == '\'' || query[i] == '"':
+			quote := query[i]
+			for i++; i < len(query) && query[i] != quote; i++ {
+			}
+		case strings.HasPrefix(query[i:], "--"):
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return
+			}
+			i += end + 3
+		default:
+			fn(i)
+		}
+	}
+}
+
+// splitStatements splits a query string into its statements, dropping empty ones
+func splitStatements(query string) []string {
+	var statements []string
+	add := func(statement string) {
+		hasCode := false
+		sqlCode(statement, func(i int) {
+			hasCode = hasCode || !unicode.IsSpace(rune(statement[i]))
+		})
+		if hasCode {
+			statements = append(statements, stripLeadingComments(statement))
+		}
+	}
+
+	start := 0
+	sqlCode(query, func(i int) {
+		if query[i] == ';' {
+			add(query[start:i])
+			start = i + 1
+		}
+	})
+	add(query[start:])
+	return statements
+}
+
+// placeholderEnd returns the end of the $n placeholder starting at offset i, or i when
+// there is none
+func placeholderEnd(query string, i int) int {
+	if query[i] != '$' {
+		return i
+	}
+	j := i + 1
+	for j < len(query) && query[j] >= '0' && query[j] <= '9' {
+		j++
+	}
+	if j == i+1 {
+		return i
+	}
+	return j
+}
+
+// sqlParameters returns the number of $n parameters of a statement
+func sqlParameters(query string) int {
+	count := 0
+	sqlCode(query, func(i int) {
+		if end := placeholderEnd(query, i); end > i {
+			if n, err := strconv.Atoi(query[i+1 : end]); err == nil && n > count {
+				count = n
+			}
+		}
+	})
+	return count
+}
+
+// nullParameters replaces the $n parameters of a statement with NULL
+func nullParameters(query string) string {
+	var b strings.Builder
+	last := 0
+	sqlCode(query, func(i int) {
+		if i < last {
+			return
+		}
+		if end := placeholderEnd(query, i); end > i {
+			b.WriteString(query[last:i])
+			b.WriteString("NULL")
+			last = end
+		}
+	})
+	b.WriteString(query[last:])
+	return b.String()
+}
+
+// stripLeadingComments removes the comments and whitespace before a statement
+func stripLeadingComments(query string) string {
+	for {
+		query = strings.TrimSpace(query)
+		switch {
+		case strings.HasPrefix(query, "--"):
+			end := strings.IndexByte(query, '\n')
+			if end < 0 {
+				return ""
+			}
+			query = query[end+1:]
+		case strings.HasPrefix(query, "/*"):
+			end := strings.Index(query, "*/")
+			if end < 0 {
+				return ""
+			}
+			query = query[end+2:]
+		default:
+			return query
+		}
+	}
+}
+
+var sqlWordPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// sqlKeywords returns the first words of a statement in upper case
+func sqlKeywords(query string) []string {
+	head := strings.TrimLeft(stripLeadingComments(query), "( \t\r\n")
+	if len(head) > 200 {
+		head = head[:200]
+	}
+	words := sqlWordPattern.FindAllString(head, 5)
+	for i, word := range words {
+		words[i] = strings.ToUpper(word)
+	}
+	return words
+}
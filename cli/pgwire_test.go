@@ -0,0 +1,403 @@
+package cli
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TFMV/icebox/auth"
+	"github.com/TFMV/icebox/catalog/grant"
+	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/engine/duckdb"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/iceberg-go/table"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakePGEngine answers queries with canned results, failing with a DuckDB parser error for
+// other queries, and records the arguments each query ran with and how often it ran
+type fakePGEngine struct {
+	mu      sync.Mutex
+	results map[string]*duckdb.QueryResult
+	args    map[string][]interface{}
+	runs    map[string]int
+}
+
+func (e *fakePGEngine) ExecuteQuery(ctx context.Context, query string, args ...interface{}) (*duckdb.QueryResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.args[query] = args
+	e.runs[query]++
+	if result, ok := e.results[query]; ok {
+		return result, nil
+	}
+	return nil, fmt.Errorf("Parser Error: syntax error at or near %q", query)
+}
+
+var testOrderRef = uuid.MustParse("6f1c2d3e-4a5b-4c6d-8e7f-9a0b1c2d3e4f")
+
+// newTestQueryResult returns rows given as JSON objects as a query result, the way the
+// engine returns them
+func newTestQueryResult(columnTypes []string, schema *arrow.Schema, rows string) *duckdb.QueryResult {
+	record, _, err := array.RecordFromJSON(memory.DefaultAllocator, schema, strings.NewReader(rows))
+	if err != nil {
+		panic(err)
+	}
+	defer record.Release()
+
+	columns := make([]string, schema.NumFields())
+	for i, field := range schema.Fields() {
+		columns[i] = field.Name
+	}
+	return &duckdb.QueryResult{
+		Columns:     columns,
+		ColumnTypes: columnTypes,
+		Schema:      schema,
+		Table:       array.NewTableFromRecords(schema, []arrow.Record{record}),
+		RowCount:    record.NumRows(),
+	}
+}
+
+// newFakePGEngine returns an engine that knows the orders table
+func newFakePGEngine() *fakePGEngine {
+	// UUIDs have no Arrow type of their own, so the engine returns them as strings
+	orders := newTestQueryResult(
+		[]string{"BIGINT", "DECIMAL(10,2)", "TIMESTAMP", "VARCHAR", "BOOLEAN", "UUID"},
+		arrow.NewSchema([]arrow.Field{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "amount", Type: &arrow.Decimal128Type{Precision: 10, Scale: 2}, Nullable: true},
+			{Name: "placed_at", Type: arrow.FixedWidthTypes.Timestamp_us, Nullable: true},
+			{Name: "customer", Type: arrow.BinaryTypes.String, Nullable: true},
+			{Name: "active", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+			{Name: "ref", Type: arrow.BinaryTypes.String, Nullable: true},
+		}, nil),
+		`[{"id": 1, "amount": "12.50", "placed_at": "2024-01-02T03:04:05", "customer": "alice", "active": true, "ref": "`+testOrderRef.String()+`"},
+		  {"id": 2, "amount": "-0.99", "placed_at": "2024-02-03T04:05:06", "customer": null, "active": false, "ref": null}]`,
+	)
+	largeOrders := *orders
+	largeOrders.Truncated = true
+
+	return &fakePGEngine{
+		args: make(map[string][]interface{}),
+		runs: make(map[string]int),
+		results: map[string]*duckdb.QueryResult{
+			"SHOW TABLES": newTestQueryResult([]string{"VARCHAR"},
+				arrow.NewSchema([]arrow.Field{{Name: "name", Type: arrow.BinaryTypes.String}}, nil),
+				`[{"name": "orders"}]`),
+			"SELECT * FROM orders":                orders,
+			"SELECT * FROM orders WHERE id >= $1": orders,
+			"SELECT * FROM large_orders":          &largeOrders,
+			"INSERT INTO orders VALUES (3, 1.5)": newTestQueryResult([]string{"BIGINT"},
+				arrow.NewSchema([]arrow.Field{{Name: "Count", Type: arrow.PrimitiveTypes.Int64}}, nil),
+				`[{"Count": 1}]`),
+			"DESCRIBE SELECT * FROM (SELECT * FROM orders WHERE id >= NULL)": newTestQueryResult([]string{"VARCHAR", "VARCHAR"},
+				arrow.NewSchema([]arrow.Field{
+					{Name: "column_name", Type: arrow.BinaryTypes.String},
+					{Name: "column_type", Type: arrow.BinaryTypes.String},
+				}, nil),
+				`[{"column_name": "id", "column_type": "BIGINT"}, {"column_name": "amount", "column_type": "DECIMAL(10,2)"},
+				  {"column_name": "placed_at", "column_type": "TIMESTAMP"}, {"column_name": "customer", "column_type": "VARCHAR"},
+				  {"column_name": "active", "column_type": "BOOLEAN"}, {"column_name": "ref", "column_type": "UUID"}]`),
+		},
+	}
+}
+
+// newTestPGServer serves the PostgreSQL wire protocol on a local port and returns the
+// address to connect to
+func newTestPGServer(t *testing.T, engine pgEngine, cfg *config.PGWireConfig) string {
+	t.Helper()
+
+	return servePG(t, engine, cfg, nil, nil)
+}
+
+// servePG serves a pgServer on a local port and returns the address to connect to
+func servePG(t *testing.T, engine pgEngine, cfg *config.PGWireConfig, authenticator *auth.Authenticator, tlsConfig *tls.Config) string {
+	t.Helper()
+
+	server, err := newPGServer(engine, cfg, authenticator, nil, tlsConfig)
+	require.NoError(t, err)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.Serve(lis)
+	t.Cleanup(func() { lis.Close() })
+	return lis.Addr().String()
+}
+
+// testTLSConfig returns a TLS configuration with a self-signed certificate for 127.0.0.1
+func testTLSConfig(t *testing.T) *tls.Config {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+}
+
+func connectPG(t *testing.T, addr, userinfo string, mode pgx.QueryExecMode) *pgx.Conn {
+	t.Helper()
+
+	connConfig, err := pgx.ParseConfig(fmt.Sprintf("postgres://%s@%s/icebox?sslmode=disable", userinfo, addr))
+	require.NoError(t, err)
+	connConfig.DefaultQueryExecMode = mode
+
+	conn, err := pgx.ConnectConfig(context.Background(), connConfig)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close(context.Background()) })
+	return conn
+}
+
+// orderRow is a row of the orders table as scanned by pgx
+type orderRow struct {
+	id       int64
+	amount   float64
+	placedAt time.Time
+	customer *string
+	active   bool
+	ref      *uuid.UUID
+}
+
+func scanOrders(t *testing.T, rows pgx.Rows) []orderRow {
+	t.Helper()
+
+	var orders []orderRow
+	for rows.Next() {
+		var row orderRow
+		require.NoError(t, rows.Scan(&row.id, &row.amount, &row.placedAt, &row.customer, &row.active, &row.ref))
+		orders = append(orders, row)
+	}
+	require.NoError(t, rows.Err())
+	return orders
+}
+
+func TestPGWireQueryProtocols(t *testing.T) {
+	engine := newFakePGEngine()
+	addr := newTestPGServer(t, engine, nil)
+	ctx := context.Background()
+
+	// The simple protocol sends text, the extended protocol binary values described up front
+	for _, mode := range []pgx.QueryExecMode{pgx.QueryExecModeSimpleProtocol, pgx.QueryExecModeCacheStatement} {
+		t.Run(mode.String(), func(t *testing.T) {
+			conn := connectPG(t, addr, "analyst", mode)
+
+			query := "SELECT * FROM orders"
+			var args []interface{}
+			if mode != pgx.QueryExecModeSimpleProtocol {
+				query, args = "SELECT * FROM orders WHERE id >= $1", []interface{}{1}
+			}
+			rows, err := conn.Query(ctx, query, args...)
+			require.NoError(t, err)
+			orders := scanOrders(t, rows)
+			assert.Equal(t, "SELECT 2", rows.CommandTag().String())
+
+			require.Len(t, orders, 2)
+			assert.Equal(t, int64(1), orders[0].id)
+			assert.Equal(t, 12.5, orders[0].amount)
+			assert.Equal(t, -0.99, orders[1].amount)
+			assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), orders[0].placedAt)
+			require.NotNil(t, orders[0].customer)
+			assert.Equal(t, "alice", *orders[0].customer)
+			assert.Nil(t, orders[1].customer)
+			assert.True(t, orders[0].active)
+			require.NotNil(t, orders[0].ref)
+			assert.Equal(t, testOrderRef, *orders[0].ref)
+			assert.Nil(t, orders[1].ref)
+
+			tag, err := conn.Exec(ctx, "INSERT INTO orders VALUES (3, 1.5)")
+			require.NoError(t, err)
+			assert.Equal(t, "INSERT 0 1", tag.String())
+
+			var pgErr *pgconn.PgError
+			_, err = conn.Exec(ctx, "SELEC 1")
+			require.True(t, errors.As(err, &pgErr))
+			assert.Equal(t, "42601", pgErr.Code)
+
+			// Results cut off at the engine's row limit are errors rather than partial results
+			_, err = conn.Exec(ctx, "SELECT * FROM large_orders")
+			require.True(t, errors.As(err, &pgErr))
+			assert.Equal(t, "54000", pgErr.Code)
+
+			// The session is usable after an error
+			require.NoError(t, conn.Ping(ctx))
+		})
+	}
+
+	// Parameters without a declared type are bound as text and cast by DuckDB
+	assert.Equal(t, []interface{}{"1"}, engine.args["SELECT * FROM orders WHERE id >= $1"])
+}
+
+func TestPGWireDescribedStatementsRunOnce(t *testing.T) {
+	engine := newFakePGEngine()
+	addr := newTestPGServer(t, engine, nil)
+	ctx := context.Background()
+	conn := connectPG(t, addr, "analyst", pgx.QueryExecModeCacheStatement)
+
+	// Statements that run to be described are not run again when executed
+	var name string
+	require.NoError(t, conn.QueryRow(ctx, "SHOW TABLES").Scan(&name))
+	assert.Equal(t, "orders", name)
+	assert.Equal(t, 1, engine.runs["SHOW TABLES"])
+
+	// Later executions of the prepared statement run it
+	require.NoError(t, conn.QueryRow(ctx, "SHOW TABLES").Scan(&name))
+	assert.Equal(t, 2, engine.runs["SHOW TABLES"])
+}
+
+func TestPGWireSessionCommands(t *testing.T) {
+	addr := newTestPGServer(t, newFakePGEngine(), nil)
+	conn := connectPG(t, addr, "analyst", pgx.QueryExecModeCacheStatement)
+	ctx := context.Background()
+
+	var version string
+	require.NoError(t, conn.QueryRow(ctx, "SELECT version()").Scan(&version))
+	assert.Contains(t, version, "PostgreSQL "+pgServerVersion)
+
+	_, err := conn.Exec(ctx, "SET application_name = 'dashboards'")
+	require.NoError(t, err)
+	var applicationName string
+	require.NoError(t, conn.QueryRow(ctx, "SHOW application_name").Scan(&applicationName))
+	assert.Equal(t, "dashboards", applicationName)
+	assert.Equal(t, "dashboards", conn.PgConn().ParameterStatus("application_name"))
+
+	var isolation string
+	require.NoError(t, conn.QueryRow(ctx, "SHOW TRANSACTION ISOLATION LEVEL").Scan(&isolation))
+	assert.Equal(t, "read committed", isolation)
+
+	tx, err := conn.Begin(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, byte('T'), conn.PgConn().TxStatus())
+	require.NoError(t, tx.Commit(ctx))
+	assert.Equal(t, byte('I'), conn.PgConn().TxStatus())
+
+	// Several statements may be sent in one simple query
+	results, err := conn.PgConn().Exec(ctx, "BEGIN; SELECT * FROM orders; COMMIT").ReadAll()
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Len(t, results[1].Rows, 2)
+	assert.Equal(t, "COMMIT", results[2].CommandTag.String())
+
+	_, err = conn.PgConn().Exec(ctx, "  ").ReadAll()
+	assert.NoError(t, err)
+}
+
+func TestPGWireAuthentication(t *testing.T) {
+	digest := sha256.Sum256([]byte("s3cret"))
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("hunter2"), bcrypt.MinCost)
+	require.NoError(t, err)
+	users := &config.PGWireConfig{
+		Users: map[string]string{
+			"analyst": string(bcryptHash),
+			"loader":  "sha256:" + hex.EncodeToString(digest[:]),
+		},
+	}
+	addr := newTestPGServer(t, newFakePGEngine(), users)
+
+	connectPG(t, addr, "analyst:hunter2", pgx.QueryExecModeCacheStatement)
+	connectPG(t, addr, "loader:s3cret", pgx.QueryExecModeCacheStatement)
+
+	for _, userinfo := range []string{"analyst:wrong", "loader:sha256", "unknown:hunter2"} {
+		_, err := pgx.Connect(context.Background(), fmt.Sprintf("postgres://%s@%s/icebox?sslmode=disable", userinfo, addr))
+		var pgErr *pgconn.PgError
+		require.True(t, errors.As(err, &pgErr), userinfo)
+		assert.Equal(t, "28P01", pgErr.Code)
+	}
+
+	// With an authenticator, API keys are accepted as passwords
+	apiKeyDigest := sha256.Sum256([]byte("secret-key"))
+	authenticator, err := auth.New(&config.AuthConfig{
+		APIKeys: []config.APIKeyConfig{{Name: "etl", Hash: "sha256:" + hex.EncodeToString(apiKeyDigest[:])}},
+	})
+	require.NoError(t, err)
+	authenticator.SetAuditWriter(io.Discard)
+
+	authAddr := servePG(t, newFakePGEngine(), nil, authenticator, nil)
+
+	connectPG(t, authAddr, "etl:secret-key", pgx.QueryExecModeCacheStatement)
+	_, err = pgx.Connect(context.Background(), fmt.Sprintf("postgres://etl:wrong@%s/icebox?sslmode=disable", authAddr))
+	var pgErr *pgconn.PgError
+	require.True(t, errors.As(err, &pgErr))
+	assert.Equal(t, "28P01", pgErr.Code)
+
+	// Passwords must be hashed in the configuration
+	_, err = newPGServer(newFakePGEngine(), &config.PGWireConfig{Users: map[string]string{"analyst": "hunter2"}}, nil, nil, nil)
+	assert.ErrorContains(t, err, `pgwire user "analyst"`)
+
+	// With a certificate, passwords are only accepted over TLS
+	tlsAddr := servePG(t, newFakePGEngine(), users, nil, testTLSConfig(t))
+	_, err = pgx.Connect(context.Background(), fmt.Sprintf("postgres://analyst:hunter2@%s/icebox?sslmode=disable", tlsAddr))
+	require.True(t, errors.As(err, &pgErr))
+	assert.Equal(t, "28000", pgErr.Code)
+	conn, err := pgx.Connect(context.Background(), fmt.Sprintf("postgres://analyst:hunter2@%s/icebox?sslmode=require", tlsAddr))
+	require.NoError(t, err)
+	conn.Close(context.Background())
+}
+
+func TestPGWireCatalogQueries(t *testing.T) {
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("hunter2"), bcrypt.MinCost)
+	require.NoError(t, err)
+	access := &accessControl{
+		authorizer: auth.NewAuthorizer(staticGrants{{Grantee: grant.User("analyst"), Namespace: "sales", Privilege: grant.Read}}),
+		tables: fakeTableResolver{
+			refs:    &duckdb.TableReferences{Unresolved: []string{"pg_class"}, CatalogViews: []string{"pg_class"}},
+			catalog: []table.Identifier{{"sales", "orders"}, {"hr", "salaries"}},
+		},
+	}
+	engine := newFakePGEngine()
+	engine.results["visible: sales.orders"] = newTestQueryResult([]string{"VARCHAR"},
+		arrow.NewSchema([]arrow.Field{{Name: "relname", Type: arrow.BinaryTypes.String, Nullable: true}}, nil),
+		`[{"relname": "sales_orders"}]`)
+
+	server, err := newPGServer(engine, &config.PGWireConfig{Users: map[string]string{"analyst": string(bcryptHash)}}, nil, access, nil)
+	require.NoError(t, err)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.Serve(lis)
+	t.Cleanup(func() { lis.Close() })
+
+	// Users without admin on the catalog get the catalog views filtered to what they may read
+	conn := connectPG(t, lis.Addr().String(), "analyst:hunter2", pgx.QueryExecModeSimpleProtocol)
+	var relname string
+	require.NoError(t, conn.QueryRow(context.Background(), "SELECT relname FROM pg_class").Scan(&relname))
+	assert.Equal(t, "sales_orders", relname)
+}
+
+func TestSQLStatementParsing(t *testing.T) {
+	assert.Equal(t, []string{"SELECT ';'", "SELECT 2"}, splitStatements("SELECT ';'; -- comment;\n SELECT 2;;"))
+	assert.Empty(t, splitStatements(" ; /* only a comment */ "))
+
+	assert.Equal(t, 2, sqlParameters("SELECT $1, $2, '$3' FROM t WHERE x = $1"))
+	assert.Equal(t, "SELECT NULL::BIGINT, '$2'", nullParameters("SELECT $1::BIGINT, '$2'"))
+
+	assert.Equal(t, []string{"SELECT", "A"}, sqlKeywords("/* hint */ -- comment\n(select a"))
+}
@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	goduckdb "github.com/marcboeker/go-duckdb/v2"
+)
+
+// pgTypeOID returns the PostgreSQL type a column of the given DuckDB type is sent as.
+// Iceberg tables are read through DuckDB, so Iceberg types arrive as their DuckDB
+// counterparts: int as INTEGER (int4), long as BIGINT (int8), decimal(p, s) as
+// DECIMAL(p, s) (numeric), fixed and binary as BLOB (bytea), and so on. Nested types are
+// sent as JSON text.
+func pgTypeOID(duckdbType string) uint32 {
+	name := strings.ToUpper(duckdbType)
+	switch {
+	case strings.HasPrefix(name, "DECIMAL"):
+		return pgtype.NumericOID
+	case strings.HasSuffix(name, "[]") || strings.HasPrefix(name, "STRUCT") ||
+		strings.HasPrefix(name, "MAP") || strings.HasPrefix(name, "UNION"):
+		return pgtype.TextOID
+	}
+
+	switch name {
+	case "BOOLEAN":
+		return pgtype.BoolOID
+	case "TINYINT", "UTINYINT", "SMALLINT":
+		return pgtype.Int2OID
+	case "USMALLINT", "INTEGER":
+		return pgtype.Int4OID
+	case "UINTEGER", "BIGINT":
+		return pgtype.Int8OID
+	case "UBIGINT", "HUGEINT", "UHUGEINT":
+		return pgtype.NumericOID
+	case "FLOAT":
+		return pgtype.Float4OID
+	case "DOUBLE":
+		return pgtype.Float8OID
+	case "DATE":
+		return pgtype.DateOID
+	case "TIME":
+		return pgtype.TimeOID
+	case "TIMESTAMP", "TIMESTAMP_S", "TIMESTAMP_MS", "TIMESTAMP_NS":
+		return pgtype.TimestampOID
+	case "TIMESTAMPTZ":
+		return pgtype.TimestamptzOID
+	case "INTERVAL":
+		return pgtype.IntervalOID
+	case "BLOB":
+		return pgtype.ByteaOID
+	case "UUID":
+		return pgtype.UUIDOID
+	case "JSON":
+		return pgtype.JSONOID
+	default:
+		return pgtype.TextOID
+	}
+}
+
+// pgTypeSize returns the size PostgreSQL reports for a type, or -1 for variable-length types
+func pgTypeSize(oid uint32) int16 {
+	switch oid {
+	case pgtype.BoolOID:
+		return 1
+	case pgtype.Int2OID:
+		return 2
+	case pgtype.Int4OID, pgtype.Float4OID, pgtype.DateOID:
+		return 4
+	case pgtype.Int8OID, pgtype.Float8OID, pgtype.TimeOID, pgtype.TimestampOID, pgtype.TimestamptzOID:
+		return 8
+	case pgtype.IntervalOID, pgtype.UUIDOID:
+		return 16
+	default:
+		return -1
+	}
+}
+
+// encodePGValue encodes a value scanned from DuckDB as a column of the given PostgreSQL
+// type, in text (0) or binary (1) format. NULL encodes as nil.
+func encodePGValue(types *pgtype.Map, oid uint32, format int16, value interface{}) ([]byte, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch v := value.(type) {
+	case goduckdb.Decimal:
+		value = pgtype.Numeric{Int: v.Value, Exp: -int32(v.Scale), Valid: true}
+	case *big.Int:
+		value = pgtype.Numeric{Int: v, Valid: true}
+	case uint64:
+		value = pgtype.Numeric{Int: new(big.Int).SetUint64(v), Valid: true}
+	case goduckdb.Interval:
+		value = pgtype.Interval{Microseconds: v.Micros, Days: v.Days, Months: v.Months, Valid: true}
+	case []byte:
+		if oid == pgtype.UUIDOID && len(v) == 16 {
+			value = [16]byte(v)
+		}
+	case string:
+		// The engine returns UUIDs as strings, which pgtype only encodes as text
+		if oid == pgtype.UUIDOID {
+			if id, err := uuid.Parse(v); err == nil {
+				value = [16]byte(id)
+			}
+		}
+	case time.Time:
+		if oid == pgtype.TimeOID {
+			midnight := time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, v.Location())
+			value = pgtype.Time{Microseconds: v.Sub(midnight).Microseconds(), Valid: true}
+		}
+	}
+
+	if oid == pgtype.TextOID {
+		if _, ok := value.(string); !ok {
+			value = pgText(value)
+		}
+	}
+
+	// A non-nil buffer keeps empty strings distinct from NULL
+	buf, err := types.Encode(oid, format, value, []byte{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %T as type %d: %w", value, oid, err)
+	}
+	return buf, nil
+}
+
+// pgText renders a value for a text column. Nested values render as JSON.
+func pgText(value interface{}) string {
+	switch v := value.(type) {
+	case []interface{}, map[string]interface{}:
+		if data, err := json.Marshal(v); err == nil {
+			return string(data)
+		}
+	case goduckdb.Map:
+		entries := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			entries[fmt.Sprint(key)] = val
+		}
+		return pgText(entries)
+	case pgtype.Numeric:
+		if data, err := v.MarshalJSON(); err == nil {
+			return string(data)
+		}
+	case pgtype.Interval:
+		if text, err := v.Value(); err == nil {
+			return fmt.Sprint(text)
+		}
+	case [16]byte:
+		return uuid.UUID(v).String()
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05.999999Z07:00")
+	}
+	return fmt.Sprint(value)
+}
+
+// decodePGParameter decodes a bound parameter of the given PostgreSQL type into a value
+// DuckDB can bind. Parameters of unknown or text types are passed as strings and cast by
+// DuckDB.
+func decodePGParameter(types *pgtype.Map, oid uint32, format int16, src []byte) (interface{}, error) {
+	if src == nil {
+		return nil, nil
+	}
+
+	typ, ok := types.TypeForOID(oid)
+	if !ok || oid == pgtype.TextOID || oid == pgtype.VarcharOID || oid == pgtype.UnknownOID {
+		if format == pgtype.BinaryFormatCode && oid != pgtype.TextOID && oid != pgtype.VarcharOID {
+			return src, nil
+		}
+		return string(src), nil
+	}
+
+	value, err := typ.Codec.DecodeValue(types, oid, format, src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for parameter of type %s: %w", typ.Name, err)
+	}
+
+	switch v := value.(type) {
+	case [16]byte:
+		return uuid.UUID(v).String(), nil
+	case driver.Valuer:
+		return v.Value()
+	}
+	return value, nil
+}
@@ -0,0 +1,282 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/TFMV/icebox/engine/duckdb"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+)
+
+// resultWriter writes the record batches of a streamed query result in an export format
+type resultWriter interface {
+	Write(record arrow.Record) error
+	// Close finishes the output, such as a JSON array or a Parquet footer. It does not
+	// close the underlying writer.
+	Close() error
+}
+
+// streamFormats are the result formats that can be written batch by batch, and the
+// content types they are served with
+var streamFormats = map[string]string{
+	"csv":     "text/csv",
+	"json":    "application/json",
+	"ndjson":  "application/x-ndjson",
+	"parquet": "application/vnd.apache.parquet",
+	"arrow":   "application/vnd.apache.arrow.stream",
+}
+
+// newResultWriter returns a writer of results with the given schema in one of the
+// streamFormats
+func newResultWriter(w io.Writer, format string, schema *arrow.Schema) (resultWriter, error) {
+	switch format {
+	case "csv":
+		return newCSVResultWriter(w, schema), nil
+	case "json":
+		return &jsonResultWriter{w: bufio.NewWriter(w), schema: schema, array: true}, nil
+	case "ndjson":
+		return &jsonResultWriter{w: bufio.NewWriter(w), schema: schema}, nil
+	case "parquet":
+		// The Parquet writer closes writers it is given, such as os.Stdout
+		writer, err := pqarrow.NewFileWriter(schema, struct{ io.Writer }{w}, nil, pqarrow.DefaultWriterProps())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Parquet writer: %w", err)
+		}
+		return &parquetResultWriter{writer: writer}, nil
+	case "arrow":
+		return ipc.NewWriter(w, ipc.WithSchema(schema)), nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// writeResults writes the records of a query result to w, stopping after maxRows rows
+// when maxRows is positive. It returns the number of rows written and whether rows
+// were left out.
+func writeResults(w io.Writer, format string, reader array.RecordReader, maxRows int64) (int64, bool, error) {
+	writer, err := newResultWriter(w, format, reader.Schema())
+	if err != nil {
+		return 0, false, err
+	}
+
+	var written int64
+	truncated := false
+	for reader.Next() {
+		record := reader.Record()
+		if record.NumRows() == 0 {
+			continue
+		}
+		if maxRows > 0 && written >= maxRows {
+			truncated = true
+			break
+		}
+
+		if maxRows > 0 && written+record.NumRows() > maxRows {
+			slice := record.NewSlice(0, maxRows-written)
+			err = writer.Write(slice)
+			slice.Release()
+			written, truncated = maxRows, true
+		} else {
+			err = writer.Write(record)
+			written += record.NumRows()
+		}
+		if err != nil {
+			return written, truncated, fmt.Errorf("failed to write results: %w", err)
+		}
+		if truncated {
+			break
+		}
+	}
+
+	if err := reader.Err(); err != nil {
+		writer.Close()
+		return written, truncated, err
+	}
+	if err := writer.Close(); err != nil {
+		return written, truncated, fmt.Errorf("failed to write results: %w", err)
+	}
+	return written, truncated, nil
+}
+
+// collectRows reads the first maxRows rows of a query result as JSON values, and
+// reports whether the result has more rows. It stops reading once it has seen one row
+// beyond the limit, so large results are never read in full.
+func collectRows(reader array.RecordReader, maxRows int) ([]string, [][]interface{}, bool, error) {
+	schema := reader.Schema()
+	columns := make([]string, schema.NumFields())
+	for i, field := range schema.Fields() {
+		columns[i] = field.Name
+	}
+
+	rows := [][]interface{}{}
+	for reader.Next() {
+		record := reader.Record()
+		for i := 0; i < int(record.NumRows()); i++ {
+			if len(rows) == maxRows {
+				return columns, rows, true, nil
+			}
+			row := make([]interface{}, record.NumCols())
+			for j, column := range record.Columns() {
+				row[j] = jsonArrowValue(column, i)
+			}
+			rows = append(rows, row)
+		}
+	}
+	if err := reader.Err(); err != nil {
+		return nil, nil, false, err
+	}
+	return columns, rows, false, nil
+}
+
+// errResultTruncated is returned by servers that send complete query results when the
+// engine stopped collecting a result at its row limit, so clients never get partial rows
+var errResultTruncated = errors.New("query result exceeds the server's row limit")
+
+// completeResult returns errResultTruncated for results the engine truncated
+func completeResult(result *duckdb.QueryResult) error {
+	if !result.Truncated {
+		return nil
+	}
+	return fmt.Errorf("%w of %d rows; add a LIMIT or write the result out with COPY ... TO", errResultTruncated, result.RowCount)
+}
+
+// csvResultWriter writes results as CSV with a header row. Nulls are written as empty
+// fields.
+type csvResultWriter struct {
+	w      *csv.Writer
+	schema *arrow.Schema
+	header bool
+}
+
+func newCSVResultWriter(w io.Writer, schema *arrow.Schema) *csvResultWriter {
+	return &csvResultWriter{w: csv.NewWriter(w), schema: schema}
+}
+
+func (c *csvResultWriter) writeHeader() error {
+	c.header = true
+	names := make([]string, c.schema.NumFields())
+	for i, field := range c.schema.Fields() {
+		names[i] = field.Name
+	}
+	return c.w.Write(names)
+}
+
+func (c *csvResultWriter) Write(record arrow.Record) error {
+	if !c.header {
+		if err := c.writeHeader(); err != nil {
+			return err
+		}
+	}
+
+	fields := make([]string, record.NumCols())
+	for row := 0; row < int(record.NumRows()); row++ {
+		for col, arr := range record.Columns() {
+			if arr.IsNull(row) {
+				fields[col] = ""
+			} else {
+				fields[col] = duckdb.FormatArrowValue(arr, row)
+			}
+		}
+		if err := c.w.Write(fields); err != nil {
+			return err
+		}
+	}
+	return c.w.Error()
+}
+
+func (c *csvResultWriter) Close() error {
+	if !c.header {
+		if err := c.writeHeader(); err != nil {
+			return err
+		}
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonResultWriter writes results as a JSON array of objects, or as one object per
+// line for ndjson. Object keys follow the column order.
+type jsonResultWriter struct {
+	w      *bufio.Writer
+	schema *arrow.Schema
+	array  bool
+	rows   int64
+}
+
+func (j *jsonResultWriter) Write(record arrow.Record) error {
+	for row := 0; row < int(record.NumRows()); row++ {
+		if j.array {
+			if j.rows == 0 {
+				j.w.WriteString("[\n  ")
+			} else {
+				j.w.WriteString(",\n  ")
+			}
+		}
+
+		j.w.WriteByte('{')
+		for col, arr := range record.Columns() {
+			if col > 0 {
+				j.w.WriteByte(',')
+			}
+			key, _ := json.Marshal(j.schema.Field(col).Name)
+			j.w.Write(key)
+			j.w.WriteByte(':')
+
+			value, err := json.Marshal(jsonArrowValue(arr, row))
+			if err != nil {
+				return fmt.Errorf("column %s: %w", j.schema.Field(col).Name, err)
+			}
+			j.w.Write(value)
+		}
+		j.w.WriteByte('}')
+		if !j.array {
+			j.w.WriteByte('\n')
+		}
+		j.rows++
+	}
+	return nil
+}
+
+func (j *jsonResultWriter) Close() error {
+	if j.array {
+		if j.rows == 0 {
+			j.w.WriteString("[]\n")
+		} else {
+			j.w.WriteString("\n]\n")
+		}
+	}
+	return j.w.Flush()
+}
+
+// parquetResultWriter writes results as a Parquet file, buffering batches into row groups
+type parquetResultWriter struct {
+	writer *pqarrow.FileWriter
+}
+
+func (p *parquetResultWriter) Write(record arrow.Record) error {
+	return p.writer.WriteBuffered(record)
+}
+
+func (p *parquetResultWriter) Close() error {
+	return p.writer.Close()
+}
+
+// jsonArrowValue returns the value at index i of an Arrow array for JSON encoding.
+// Decimals are written as exact numbers.
+func jsonArrowValue(arr arrow.Array, i int) interface{} {
+	if arr.IsNull(i) {
+		return nil
+	}
+
+	if _, ok := arr.DataType().(arrow.DecimalType); ok {
+		return json.Number(duckdb.FormatArrowValue(arr, i))
+	}
+	return arr.GetOneForMarshal(i)
+}
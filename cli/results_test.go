@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/TFMV/icebox/engine/duckdb"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testResults returns a reader of two batches of three rows of ids, names and amounts
+func testResults(t *testing.T) array.RecordReader {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "amount", Type: &arrow.Decimal128Type{Precision: 38, Scale: 2}, Nullable: true},
+	}, nil)
+
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer builder.Release()
+
+	var records []arrow.Record
+	for batch := 0; batch < 2; batch++ {
+		for i := 0; i < 3; i++ {
+			id := int64(batch*3 + i + 1)
+			builder.Field(0).(*array.Int64Builder).Append(id)
+			if id == 2 {
+				builder.Field(1).AppendNull()
+			} else {
+				builder.Field(1).(*array.StringBuilder).Append("row, \"quoted\"")
+			}
+			builder.Field(2).(*array.Decimal128Builder).Append(decimal128.FromI64(id * 12345678901234))
+		}
+		records = append(records, builder.NewRecord())
+	}
+
+	reader, err := array.NewRecordReader(schema, records)
+	require.NoError(t, err)
+	for _, record := range records {
+		record.Release()
+	}
+	t.Cleanup(reader.Release)
+	return reader
+}
+
+// testQueryResult returns the rows of testResults as a query result
+func testQueryResult(t *testing.T) *duckdb.QueryResult {
+	reader := testResults(t)
+	var records []arrow.Record
+	for reader.Next() {
+		reader.Record().Retain()
+		records = append(records, reader.Record())
+	}
+	tbl := array.NewTableFromRecords(reader.Schema(), records)
+	for _, record := range records {
+		record.Release()
+	}
+	t.Cleanup(tbl.Release)
+
+	return &duckdb.QueryResult{
+		Columns:  []string{"id", "name", "amount"},
+		Schema:   reader.Schema(),
+		Table:    tbl,
+		RowCount: tbl.NumRows(),
+	}
+}
+
+func TestWriteResultsCSV(t *testing.T) {
+	var out bytes.Buffer
+	rows, truncated, err := writeResults(&out, "csv", testResults(t), 4)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), rows)
+	assert.True(t, truncated)
+
+	assert.Equal(t, "id,name,amount\n"+
+		"1,\"row, \"\"quoted\"\"\",123456789012.34\n"+
+		"2,,246913578024.68\n"+
+		"3,\"row, \"\"quoted\"\"\",370370367037.02\n"+
+		"4,\"row, \"\"quoted\"\"\",493827156049.36\n", out.String())
+
+	// Without a limit every row is written
+	out.Reset()
+	rows, truncated, err = writeResults(&out, "csv", testResults(t), 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), rows)
+	assert.False(t, truncated)
+}
+
+func TestWriteResultsJSON(t *testing.T) {
+	var out bytes.Buffer
+	_, _, err := writeResults(&out, "json", testResults(t), 3)
+	require.NoError(t, err)
+
+	var decoded []map[string]interface{}
+	decoder := json.NewDecoder(&out)
+	decoder.UseNumber()
+	require.NoError(t, decoder.Decode(&decoded))
+	require.Len(t, decoded, 3)
+	assert.Equal(t, json.Number("1"), decoded[0]["id"])
+	assert.Nil(t, decoded[1]["name"])
+	assert.Equal(t, json.Number("123456789012.34"), decoded[0]["amount"])
+
+	out.Reset()
+	rows, _, err := writeResults(&out, "ndjson", testResults(t), 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), rows)
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	require.Len(t, lines, 6)
+	assert.Equal(t, `{"id":6,"name":"row, \"quoted\"","amount":740740734074.04}`, string(lines[5]))
+}
+
+func TestWriteResultsBinaryFormats(t *testing.T) {
+	var out bytes.Buffer
+	rows, _, err := writeResults(&out, "parquet", testResults(t), 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), rows)
+
+	parquetFile, err := file.NewParquetReader(bytes.NewReader(out.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), parquetFile.NumRows())
+	assert.Equal(t, 3, parquetFile.MetaData().Schema.NumColumns())
+
+	out.Reset()
+	rows, _, err = writeResults(&out, "arrow", testResults(t), 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), rows)
+
+	reader, err := ipc.NewReader(&out)
+	require.NoError(t, err)
+	defer reader.Release()
+	var read int64
+	for reader.Next() {
+		read += reader.Record().NumRows()
+	}
+	require.NoError(t, reader.Err())
+	assert.Equal(t, int64(5), read)
+
+	_, _, err = writeResults(&out, "xml", testResults(t), 0)
+	assert.Error(t, err)
+}
+
+func TestCollectRows(t *testing.T) {
+	columns, rows, more, err := collectRows(testResults(t), 6)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "name", "amount"}, columns)
+	assert.Len(t, rows, 6)
+	assert.False(t, more)
+
+	// Reading stops in the first batch once a row beyond the limit is seen
+	reader := testResults(t)
+	_, rows, more, err = collectRows(reader, 2)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, int64(2), rows[1][0])
+	assert.True(t, more)
+	require.True(t, reader.Next())
+	assert.Equal(t, int64(4), reader.Record().Column(0).(*array.Int64).Value(0))
+}
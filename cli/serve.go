@@ -1,39 +1,61 @@
 package cli
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/tls"
+	_ "embed"
+	"errors"
 	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
 	"net"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/TFMV/icebox/auth"
 	"github.com/TFMV/icebox/catalog"
-	"github.com/TFMV/icebox/catalog/sqlite"
+	"github.com/TFMV/icebox/catalog/grant"
 	"github.com/TFMV/icebox/config"
 	"github.com/TFMV/icebox/engine/duckdb"
 	"github.com/TFMV/icebox/importer"
+	"github.com/TFMV/icebox/jobs"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	"github.com/apache/arrow-go/v18/arrow/flight/flightsql"
+	"github.com/apache/iceberg-go"
+	icebergcatalog "github.com/apache/iceberg-go/catalog"
 	"github.com/apache/iceberg-go/table"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/gofiber/fiber/v2/utils"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 )
 
 var serveCmd = &cobra.Command{
 	Use:   "serve",
-	Short: "Start Icebox API server (REST or gRPC)",
+	Short: "Start Icebox API server (REST, gRPC, Flight SQL or PostgreSQL wire protocol)",
 	Long: `Start a high-performance API server to provide remote access to your Icebox catalog.
 
 The serve command supports multiple modes and deployment profiles:
 - REST API using Fiber for HTTP-based access with excellent performance
 - gRPC API for high-performance remote procedure calls
+- Arrow Flight SQL for ADBC and JDBC clients such as pandas, polars and BI tools
+- PostgreSQL wire protocol for psql, PostgreSQL drivers and BI tools
 - Different profiles optimized for local development, staging, and production
 
 Service Profiles:
@@ -44,9 +66,12 @@ Service Profiles:
 Examples:
   icebox serve                              # Start REST server on port 8080 (local profile)
   icebox serve --mode grpc --port 9090     # Start gRPC server on port 9090
+  icebox serve --mode flightsql --port 32010 # Start Flight SQL server on port 32010
+  icebox serve --mode pgwire --port 5432   # Start PostgreSQL wire-protocol server on port 5432
   icebox serve --profile prod --port 80    # Production REST server with optimizations
   icebox serve --profile dev --cors        # Dev server with CORS and metrics enabled
-  icebox serve --auth --metrics            # Enable authentication and metrics`,
+  icebox serve --auth --metrics            # Enable authentication and metrics
+  icebox serve --readonly                  # Only allow queries that read catalog tables`,
 	RunE: runServe,
 }
 
@@ -65,6 +90,11 @@ type serveOptions struct {
 	keyFile     string
 	prefork     bool
 	compress    bool
+	readonly    bool
+
+	importWorkers int
+	importQueue   int
+	maxUploadMB   int64
 }
 
 var serveOpts = &serveOptions{}
@@ -82,26 +112,67 @@ type ServerProfile struct {
 	Compress    bool
 }
 
+// bodyLimit bounds the request bodies buffered in memory. Only uploads to /api/v1/import
+// may be larger; they are streamed to disk.
+const bodyLimit = 50 * 1024 * 1024 // 50MB
+
+// limitBody rejects requests with bodies larger than limit, which the server streams
+// instead of rejecting, unless they are uploads. Bodies without a Content-Length, such as
+// chunked ones, are read up to the limit before the request is handled.
+func limitBody(limit int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Path() == "/api/v1/import" {
+			return c.Next()
+		}
+		if c.Request().Header.ContentLength() > limit {
+			return fiber.ErrRequestEntityTooLarge
+		}
+
+		if stream := c.Request().BodyStream(); stream != nil {
+			body, err := io.ReadAll(io.LimitReader(stream, int64(limit)+1))
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "Failed to read request body")
+			}
+			if len(body) > limit {
+				// The rest of the body is not read, so the connection can't be reused
+				c.Context().SetConnectionClose()
+				return fiber.ErrRequestEntityTooLarge
+			}
+			c.Request().SetBody(body)
+		}
+		return c.Next()
+	}
+}
+
 // Server start time for uptime calculation
 var serverStartTime time.Time
 
+// openAPIDocument describes the REST API and is served at /api/v1/openapi.json
+//
+//go:embed openapi.json
+var openAPIDocument []byte
+
 func init() {
 	rootCmd.AddCommand(serveCmd)
 
 	serveCmd.Flags().IntVar(&serveOpts.port, "port", 8080, "server port")
-	serveCmd.Flags().StringVar(&serveOpts.mode, "mode", "rest", "server mode: rest, grpc")
+	serveCmd.Flags().StringVar(&serveOpts.mode, "mode", "rest", "server mode: rest, grpc, flightsql, pgwire")
 	serveCmd.Flags().StringVar(&serveOpts.profile, "profile", "local", "service profile: local, dev, prod")
 	serveCmd.Flags().StringVar(&serveOpts.host, "host", "0.0.0.0", "server host to bind to")
 	serveCmd.Flags().BoolVar(&serveOpts.cors, "cors", false, "enable CORS support")
-	serveCmd.Flags().BoolVar(&serveOpts.auth, "auth", false, "enable authentication")
+	serveCmd.Flags().BoolVar(&serveOpts.auth, "auth", false, "require credentials configured in the auth section of .icebox.yml")
 	serveCmd.Flags().BoolVar(&serveOpts.metrics, "metrics", false, "enable metrics endpoint")
 	serveCmd.Flags().BoolVar(&serveOpts.healthCheck, "health", true, "enable health check endpoints")
 	serveCmd.Flags().BoolVar(&serveOpts.verbose, "verbose", false, "verbose logging")
 	serveCmd.Flags().StringVar(&serveOpts.logLevel, "log-level", "", "log level (debug, info, warn, error)")
 	serveCmd.Flags().StringVar(&serveOpts.certFile, "cert", "", "TLS certificate file")
 	serveCmd.Flags().StringVar(&serveOpts.keyFile, "key", "", "TLS private key file")
-	serveCmd.Flags().BoolVar(&serveOpts.prefork, "prefork", false, "enable prefork for production (Linux/macOS only)")
+	serveCmd.Flags().BoolVar(&serveOpts.prefork, "prefork", false, "enable prefork for --readonly REST servers (Linux/macOS only)")
 	serveCmd.Flags().BoolVar(&serveOpts.compress, "compress", false, "enable gzip compression")
+	serveCmd.Flags().BoolVar(&serveOpts.readonly, "readonly", false, "only allow queries that read catalog tables (REST mode)")
+	serveCmd.Flags().IntVar(&serveOpts.importWorkers, "import-workers", jobs.DefaultOptions().Workers, "number of imports run at the same time")
+	serveCmd.Flags().IntVar(&serveOpts.importQueue, "import-queue", jobs.DefaultOptions().QueueSize, "number of imports that may wait for a worker")
+	serveCmd.Flags().Int64Var(&serveOpts.maxUploadMB, "max-upload-mb", 1024, "largest file accepted by the upload import endpoint, in MB")
 }
 
 func runServe(cmd *cobra.Command, args []string) error {
@@ -125,17 +196,37 @@ func runServe(cmd *cobra.Command, args []string) error {
 	// Apply profile defaults if not overridden by flags
 	applyProfileDefaults(cmd, profile)
 
+	if err := validateServeOptions(); err != nil {
+		return err
+	}
+
 	printServerInfo(profile)
 
 	// Start the appropriate server
 	switch serveOpts.mode {
 	case "rest":
-		return startRESTServer(cfg, profile)
+		return startRESTServer(configPath, cfg, profile)
 	case "grpc":
-		return startGRPCServer(cfg, profile)
+		return startGRPCServer(configPath, cfg, profile)
+	case "flightsql":
+		return startFlightSQLServer(cfg, profile)
+	case "pgwire":
+		return startPGWireServer(cfg, profile)
 	default:
-		return fmt.Errorf("❌ Unknown server mode: %s (available: rest, grpc)", serveOpts.mode)
+		return fmt.Errorf("❌ Unknown server mode: %s (available: rest, grpc, flightsql, pgwire)", serveOpts.mode)
+	}
+}
+
+// validateServeOptions rejects combinations of flags a server cannot honor
+func validateServeOptions() error {
+	if serveOpts.readonly && serveOpts.mode != "rest" {
+		return fmt.Errorf("❌ --readonly is only supported by the REST server (mode %s)", serveOpts.mode)
 	}
+	// Every prefork child would run its own import jobs, unknown to the other children
+	if serveOpts.prefork && !serveOpts.readonly {
+		return fmt.Errorf("❌ --prefork is only supported with --readonly, which runs no imports")
+	}
+	return nil
 }
 
 func applyProfileDefaults(cmd *cobra.Command, profile *ServerProfile) {
@@ -152,7 +243,7 @@ func applyProfileDefaults(cmd *cobra.Command, profile *ServerProfile) {
 		serveOpts.metrics = profile.Metrics
 	}
 	if !cmd.Flags().Changed("prefork") {
-		serveOpts.prefork = profile.Prefork
+		serveOpts.prefork = profile.Prefork && serveOpts.readonly
 	}
 	if !cmd.Flags().Changed("compress") {
 		serveOpts.compress = profile.Compress
@@ -184,6 +275,9 @@ func printServerInfo(profile *ServerProfile) {
 	if serveOpts.compress {
 		features = append(features, "Compress")
 	}
+	if serveOpts.readonly {
+		features = append(features, "Read-Only")
+	}
 
 	if len(features) > 0 {
 		fmt.Printf("   Features: %v\n", features)
@@ -192,29 +286,91 @@ func printServerInfo(profile *ServerProfile) {
 	}
 }
 
-func startRESTServer(cfg *config.Config, profile *ServerProfile) error {
-	// Initialize server start time
-	serverStartTime = time.Now()
+// openServing opens what every server mode and the web UI serve: the project's storage,
+// catalog and SQL engine. Clients query tables by name, so they are registered up front
+// as in 'icebox sql'. The returned function releases everything again.
+func openServing(ctx context.Context, cfg *config.Config) (catalog.CatalogInterface, *duckdb.Engine, func(), error) {
+	// Make the embedded MinIO server reachable for projects that use it
+	stopStorage, err := startStorage(ctx, cfg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
 	// Create catalog
 	cat, err := catalog.NewCatalog(cfg)
 	if err != nil {
-		return fmt.Errorf("❌ Failed to create catalog: %w", err)
+		stopStorage()
+		return nil, nil, nil, fmt.Errorf("❌ Failed to create catalog: %w", err)
+	}
+
+	// Create SQL engine
+	engine, err := duckdb.NewEngine(cat)
+	if err != nil {
+		cat.Close()
+		stopStorage()
+		return nil, nil, nil, fmt.Errorf("❌ Failed to create SQL engine: %w", err)
+	}
+
+	if err := autoRegisterTables(ctx, engine, cat); err != nil {
+		fmt.Printf("⚠️  Warning: Failed to auto-register some tables: %v\n", err)
+	}
+
+	return cat, engine, func() {
+		engine.Close()
+		cat.Close()
+		stopStorage()
+	}, nil
+}
+
+func startRESTServer(configPath string, cfg *config.Config, profile *ServerProfile) error {
+	// Initialize server start time
+	serverStartTime = time.Now()
+
+	cat, engine, closeServing, err := openServing(context.Background(), cfg)
+	if err != nil {
+		return err
+	}
+	defer closeServing()
+
+	// Run imports in the background; the job history is kept next to the project's catalog.
+	// Read-only servers run no imports, so they keep no history and leave the history and
+	// uploads of other servers alone.
+	projectDir := filepath.Dir(configPath)
+	historyPath := filepath.Join(projectDir, ".icebox", "jobs.json")
+	if serveOpts.readonly {
+		historyPath = ""
+	}
+	importJobs, err := jobs.NewManager(jobs.Options{
+		Workers:     serveOpts.importWorkers,
+		QueueSize:   serveOpts.importQueue,
+		HistoryPath: historyPath,
+	})
+	if err != nil {
+		return fmt.Errorf("❌ Failed to start import jobs: %w", err)
 	}
-	defer cat.Close()
+	defer importJobs.Close()
 
-	// Create SQL engine - need to assert to concrete type
-	var engine *duckdb.Engine
-	switch catalogImpl := cat.(type) {
-	case *sqlite.Catalog:
-		engine, err = duckdb.NewEngine(catalogImpl)
+	var authenticator *auth.Authenticator
+	var access *accessControl
+	if serveOpts.auth {
+		authenticator, err = loadAuthenticator(cfg)
 		if err != nil {
-			return fmt.Errorf("❌ Failed to create SQL engine: %w", err)
+			return err
+		}
+		defer authenticator.Close()
+		if access, err = newAccessControl(cfg, cat, engine); err != nil {
+			return err
+		}
+	}
+
+	// Uploads left by a previous run belong to jobs that were interrupted. The lock on the
+	// job history makes sure no other server is importing them.
+	uploadDir := filepath.Join(projectDir, ".icebox", "uploads")
+	if !serveOpts.readonly {
+		if err := os.RemoveAll(uploadDir); err != nil {
+			fmt.Printf("⚠️  Warning: failed to clear stale uploads: %v\n", err)
 		}
-	default:
-		return fmt.Errorf("❌ API server currently only supports SQLite catalogs")
 	}
-	defer engine.Close()
 
 	// Create Fiber app with optimized configuration
 	app := fiber.New(fiber.Config{
@@ -225,28 +381,37 @@ func startRESTServer(cfg *config.Config, profile *ServerProfile) error {
 		ReadTimeout:             profile.Timeout,
 		WriteTimeout:            profile.Timeout,
 		IdleTimeout:             60 * time.Second,
-		BodyLimit:               50 * 1024 * 1024, // 50MB
+		BodyLimit:               bodyLimit,
+		StreamRequestBody:       true, // uploads beyond the body limit are streamed to disk
 		EnableTrustedProxyCheck: serveOpts.profile == "prod",
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			code := fiber.StatusInternalServerError
+			errorCode := errCodeInternal
 			message := "Internal Server Error"
 
 			if e, ok := err.(*fiber.Error); ok {
 				code = e.Code
 				message = e.Message
+				switch {
+				case code == fiber.StatusNotFound:
+					errorCode = errCodeNotFound
+				case code == fiber.StatusRequestEntityTooLarge:
+					errorCode = errCodePayloadTooLarge
+				case code < fiber.StatusInternalServerError:
+					errorCode = errCodeInvalidRequest
+				}
 			}
 
-			return c.Status(code).JSON(fiber.Map{
-				"error":     message,
-				"code":      code,
-				"timestamp": time.Now().Format(time.RFC3339),
-			})
+			return apiError(c, code, errorCode, message, nil)
 		},
+		// Uploads are read in the order sent, so their table is checked before the file
+		DisablePreParseMultipartForm: true,
 	})
 
 	// Add middleware based on configuration
 	app.Use(requestid.New())
 	app.Use(recover.New())
+	app.Use(limitBody(bodyLimit))
 
 	if serveOpts.verbose || profile.LogLevel == "debug" {
 		app.Use(logger.New(logger.Config{
@@ -275,6 +440,13 @@ func startRESTServer(cfg *config.Config, profile *ServerProfile) error {
 		engine:  engine,
 		config:  cfg,
 		profile: profile,
+		jobs:    importJobs,
+		auth:    authenticator,
+		access:  access,
+
+		readOnly:      serveOpts.readonly,
+		uploadDir:     uploadDir,
+		maxUploadSize: serveOpts.maxUploadMB * 1024 * 1024,
 	}
 
 	// Register routes
@@ -305,29 +477,39 @@ func startRESTServer(cfg *config.Config, profile *ServerProfile) error {
 	return app.Listen(addr)
 }
 
-func startGRPCServer(cfg *config.Config, profile *ServerProfile) error {
+func startGRPCServer(configPath string, cfg *config.Config, profile *ServerProfile) error {
 	// Initialize server start time
 	serverStartTime = time.Now()
 
-	// Create catalog
-	cat, err := catalog.NewCatalog(cfg)
+	cat, engine, closeServing, err := openServing(context.Background(), cfg)
 	if err != nil {
-		return fmt.Errorf("❌ Failed to create catalog: %w", err)
+		return err
 	}
-	defer cat.Close()
+	defer closeServing()
 
-	// Create SQL engine
-	var engine *duckdb.Engine
-	switch catalogImpl := cat.(type) {
-	case *sqlite.Catalog:
-		engine, err = duckdb.NewEngine(catalogImpl)
+	// Imports are kept in a job history of their own, apart from the REST server's
+	importJobs, err := jobs.NewManager(jobs.Options{
+		Workers:     serveOpts.importWorkers,
+		QueueSize:   serveOpts.importQueue,
+		HistoryPath: filepath.Join(filepath.Dir(configPath), ".icebox", "grpc-jobs.json"),
+	})
+	if err != nil {
+		return fmt.Errorf("❌ Failed to start import jobs: %w", err)
+	}
+	defer importJobs.Close()
+
+	var authenticator *auth.Authenticator
+	var access *accessControl
+	if serveOpts.auth {
+		authenticator, err = loadAuthenticator(cfg)
 		if err != nil {
-			return fmt.Errorf("❌ Failed to create SQL engine: %w", err)
+			return err
+		}
+		defer authenticator.Close()
+		if access, err = newAccessControl(cfg, cat, engine); err != nil {
+			return err
 		}
-	default:
-		return fmt.Errorf("❌ gRPC server currently only supports SQLite catalogs")
 	}
-	defer engine.Close()
 
 	// Create listener
 	addr := fmt.Sprintf("%s:%d", serveOpts.host, serveOpts.port)
@@ -336,16 +518,14 @@ func startGRPCServer(cfg *config.Config, profile *ServerProfile) error {
 		return fmt.Errorf("❌ Failed to listen on %s: %w", addr, err)
 	}
 
-	// Create gRPC server with optimizations
-	var opts []grpc.ServerOption
-
-	// Add TLS if certificates are provided
-	if serveOpts.certFile != "" && serveOpts.keyFile != "" {
-		// In production, load TLS credentials
-		fmt.Printf("🔒 TLS would be enabled (implementation needed)\n")
+	opts, err := grpcServerOptions(authenticator, "grpc")
+	if err != nil {
+		lis.Close()
+		return err
 	}
 
 	grpcServer := grpc.NewServer(opts...)
+	registerGRPCServices(grpcServer, cat, engine, cfg, importJobs, access)
 
 	// Register reflection service for development
 	if profile.Name == "local" || profile.Name == "dev" {
@@ -353,15 +533,11 @@ func startGRPCServer(cfg *config.Config, profile *ServerProfile) error {
 		fmt.Printf("🔍 gRPC reflection enabled\n")
 	}
 
-	// TODO: Register actual gRPC services here
-	// For now, we'll start a basic server
 	fmt.Printf("✅ gRPC server listening on %s\n", addr)
-	fmt.Printf("🚧 gRPC service implementations coming soon...\n")
-	fmt.Printf("📋 Services will include:\n")
-	fmt.Printf("   - CatalogService (namespaces, tables, schemas)\n")
-	fmt.Printf("   - QueryService (SQL execution, results streaming)\n")
-	fmt.Printf("   - ImportService (data ingestion)\n")
-	fmt.Printf("   - TimeTravelService (snapshot querying)\n")
+	fmt.Printf("📋 Services:\n")
+	for _, name := range grpcServiceNames() {
+		fmt.Printf("   - %s\n", name)
+	}
 
 	// Graceful shutdown
 	go func() {
@@ -377,6 +553,202 @@ func startGRPCServer(cfg *config.Config, profile *ServerProfile) error {
 	return grpcServer.Serve(lis)
 }
 
+// isLoopbackHost reports whether a host to bind to only accepts local connections
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// grpcServerOptions returns the options shared by the gRPC and Flight SQL servers,
+// enabling TLS when a certificate and key are provided and authenticating calls when an
+// authenticator is given
+func grpcServerOptions(authenticator *auth.Authenticator, transport string) ([]grpc.ServerOption, error) {
+	var opts []grpc.ServerOption
+	if authenticator != nil {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(authenticator.UnaryServerInterceptor(transport)),
+			grpc.ChainStreamInterceptor(authenticator.StreamServerInterceptor(transport)))
+	}
+	if serveOpts.certFile != "" && serveOpts.keyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(serveOpts.certFile, serveOpts.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("❌ Failed to load TLS certificate: %w", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+		fmt.Printf("🔒 TLS enabled\n")
+	}
+	return opts, nil
+}
+
+// loadAuthenticator creates the authenticator for servers started with --auth from the
+// auth section of .icebox.yml
+func loadAuthenticator(cfg *config.Config) (*auth.Authenticator, error) {
+	if cfg.Auth == nil {
+		return nil, fmt.Errorf("❌ Authentication is enabled but .icebox.yml has no auth section\n" +
+			"💡 Configure api_keys, jwt or basic users under auth, or start the server without --auth")
+	}
+	authenticator, err := auth.New(cfg.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to configure authentication: %w", err)
+	}
+	fmt.Printf("🔑 Authentication enabled: %v\n", authenticator.Methods())
+	return authenticator, nil
+}
+
+func startFlightSQLServer(cfg *config.Config, profile *ServerProfile) error {
+	// Initialize server start time
+	serverStartTime = time.Now()
+
+	cat, engine, closeServing, err := openServing(context.Background(), cfg)
+	if err != nil {
+		return err
+	}
+	defer closeServing()
+
+	flightSQL, err := newFlightSQLServer(cat, engine)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to create Flight SQL server: %w", err)
+	}
+
+	var authenticator *auth.Authenticator
+	var access *accessControl
+	if serveOpts.auth {
+		authenticator, err = loadAuthenticator(cfg)
+		if err != nil {
+			return err
+		}
+		defer authenticator.Close()
+		if access, err = newAccessControl(cfg, cat, engine); err != nil {
+			return err
+		}
+	}
+	flightSQL.access = access
+
+	// Create listener
+	addr := fmt.Sprintf("%s:%d", serveOpts.host, serveOpts.port)
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to listen on %s: %w", addr, err)
+	}
+
+	opts, err := grpcServerOptions(authenticator, "flightsql")
+	if err != nil {
+		lis.Close()
+		return err
+	}
+
+	grpcServer := grpc.NewServer(opts...)
+	flight.RegisterFlightServiceServer(grpcServer, flightsql.NewFlightServer(flightSQL))
+
+	fmt.Printf("✅ Flight SQL server listening on %s\n", addr)
+	fmt.Printf("💡 Connect with an ADBC or JDBC Flight SQL driver at grpc://%s\n", addr)
+
+	// Graceful shutdown
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+		<-quit
+
+		fmt.Printf("\n🛑 Shutting down Flight SQL server...\n")
+		grpcServer.GracefulStop()
+		fmt.Printf("✅ Flight SQL server stopped\n")
+	}()
+
+	return grpcServer.Serve(lis)
+}
+
+func startPGWireServer(cfg *config.Config, profile *ServerProfile) error {
+	// Initialize server start time
+	serverStartTime = time.Now()
+
+	cat, engine, closeServing, err := openServing(context.Background(), cfg)
+	if err != nil {
+		return err
+	}
+	defer closeServing()
+
+	var pgConfig *config.PGWireConfig
+	if cfg.Server != nil {
+		pgConfig = cfg.Server.PGWire
+	}
+
+	// With --auth, clients may also log in with the credentials of the auth section
+	var authenticator *auth.Authenticator
+	var access *accessControl
+	if serveOpts.auth {
+		authenticator, err = loadAuthenticator(cfg)
+		if err != nil {
+			return err
+		}
+		defer authenticator.Close()
+		if access, err = newAccessControl(cfg, cat, engine); err != nil {
+			return err
+		}
+	}
+
+	// Clients that request TLS are upgraded when a certificate and key are provided
+	var tlsConfig *tls.Config
+	if serveOpts.certFile != "" && serveOpts.keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(serveOpts.certFile, serveOpts.keyFile)
+		if err != nil {
+			return fmt.Errorf("❌ Failed to load TLS certificate: %w", err)
+		}
+		tlsConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+		fmt.Printf("🔒 TLS enabled\n")
+	}
+
+	pgServer, err := newPGServer(engine, pgConfig, authenticator, access, tlsConfig)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	// Clients send their passwords in clear text, so they need TLS unless they can only
+	// connect over the loopback interface
+	if pgServer.authenticates() && tlsConfig == nil {
+		if !isLoopbackHost(serveOpts.host) {
+			return fmt.Errorf("❌ PostgreSQL clients would send their passwords in clear text without TLS\n" +
+				"💡 Start the server with --cert and --key, or bind it to 127.0.0.1 with --host")
+		}
+		fmt.Printf("⚠️  Warning: TLS is not enabled; passwords are sent in clear text over the loopback interface\n")
+	}
+
+	// Without users anyone who can connect may run any query, so only local clients may
+	// connect
+	if !pgServer.authenticates() {
+		if !isLoopbackHost(serveOpts.host) {
+			return fmt.Errorf("❌ PostgreSQL clients could connect without a password from other hosts\n" +
+				"💡 Configure server.pgwire.users, start the server with --auth, or bind it to 127.0.0.1 with --host")
+		}
+		fmt.Printf("⚠️  Warning: No users configured under server.pgwire.users; local connections are not authenticated\n")
+	}
+
+	// Create listener
+	addr := fmt.Sprintf("%s:%d", serveOpts.host, serveOpts.port)
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to listen on %s: %w", addr, err)
+	}
+
+	fmt.Printf("✅ PostgreSQL wire-protocol server listening on %s\n", addr)
+	fmt.Printf("💡 Connect with: psql -h %s -p %d -U <user> %s\n", serveOpts.host, serveOpts.port, cfg.Name)
+
+	// Graceful shutdown
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+		<-quit
+
+		fmt.Printf("\n🛑 Shutting down PostgreSQL wire-protocol server...\n")
+		lis.Close()
+		fmt.Printf("✅ PostgreSQL wire-protocol server stopped\n")
+	}()
+
+	return pgServer.Serve(lis)
+}
+
 func getServerProfile(name string) *ServerProfile {
 	profiles := map[string]*ServerProfile{
 		"local": {
@@ -422,6 +794,15 @@ type RESTAPIHandler struct {
 	engine  *duckdb.Engine
 	config  *config.Config
 	profile *ServerProfile
+	jobs    *jobs.Manager
+	auth    *auth.Authenticator // nil when authentication is disabled
+	access  *accessControl      // nil when authentication is disabled
+
+	// readOnly limits the server to queries that only read catalog tables
+	readOnly bool
+	// uploadDir holds files uploaded to /api/v1/import until their import finishes
+	uploadDir     string
+	maxUploadSize int64
 }
 
 func registerRESTRoutes(app *fiber.App, api *RESTAPIHandler) {
@@ -443,6 +824,15 @@ func registerRESTRoutes(app *fiber.App, api *RESTAPIHandler) {
 
 	// API v1 routes
 	v1 := app.Group("/api/v1")
+	v1.Get("/openapi.json", api.openAPISpec)
+	if api.auth != nil {
+		v1.Use(api.auth.Fiber("rest", func(c *fiber.Ctx, err error) error {
+			return apiError(c, fiber.StatusUnauthorized, errCodeUnauthorized, "Valid credentials required: an API key, a bearer token or basic credentials", nil)
+		}))
+	}
+	if api.readOnly {
+		v1.Use(rejectWrites)
+	}
 
 	// Catalog operations
 	catalog := v1.Group("/catalog")
@@ -468,10 +858,13 @@ func registerRESTRoutes(app *fiber.App, api *RESTAPIHandler) {
 	query.Post("/explain", api.explainQuery)
 
 	// Import operations
+	v1.Post("/import", api.uploadImport)
 	import_ := v1.Group("/import")
 	import_.Post("/parquet", api.importParquet)
 	import_.Post("/avro", api.importAvro)
 	import_.Get("/status/:job_id", api.getImportStatus)
+	import_.Get("/jobs", api.listImportJobs)
+	import_.Post("/jobs/:job_id/cancel", api.cancelImportJob)
 
 	// Time travel operations
 	timetravel := v1.Group("/time-travel")
@@ -480,34 +873,45 @@ func registerRESTRoutes(app *fiber.App, api *RESTAPIHandler) {
 
 	// Administrative operations
 	admin := v1.Group("/admin")
-	if serveOpts.auth {
-		admin.Use(api.authMiddleware)
-	}
 	admin.Get("/config", api.getServerConfig)
 	admin.Post("/cache/clear", api.clearCache)
 	admin.Get("/stats", api.getServerStats)
 }
 
-// Middleware for authentication
-func (api *RESTAPIHandler) authMiddleware(c *fiber.Ctx) error {
-	authHeader := c.Get("Authorization")
-	if authHeader == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Authorization header required",
-			"code":  fiber.StatusUnauthorized,
-		})
-	}
+// readOnlyPosts are the POST endpoints a read-only server still serves. The queries
+// they run are checked by the handlers.
+var readOnlyPosts = map[string]bool{
+	"/api/v1/query/sql":         true,
+	"/api/v1/query/explain":     true,
+	"/api/v1/time-travel/query": true,
+	"/api/v1/admin/cache/clear": true,
+}
 
-	// Simple Bearer token validation for demo
-	// In production, this would validate JWT tokens, API keys, etc.
-	if !strings.HasPrefix(authHeader, "Bearer ") {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Invalid authorization format. Use 'Bearer <token>'",
-			"code":  fiber.StatusUnauthorized,
-		})
+// rejectWrites rejects the requests of a read-only server that would change the catalog
+func rejectWrites(c *fiber.Ctx) error {
+	switch c.Method() {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return c.Next()
+	case fiber.MethodPost:
+		if readOnlyPosts[strings.TrimSuffix(c.Path(), "/")] {
+			return c.Next()
+		}
 	}
+	return apiError(c, fiber.StatusForbidden, errCodeReadOnly, "The server is read-only", nil)
+}
 
-	return c.Next()
+// checkQuery checks that the caller may run a query and, on a read-only server, that
+// the query only reads catalog tables
+func (api *RESTAPIHandler) checkQuery(ctx context.Context, query string) error {
+	if api.readOnly {
+		if err := api.engine.CheckReadOnly(ctx, query); err != nil {
+			if errors.Is(err, duckdb.ErrNotReadOnly) {
+				return err
+			}
+			return fmt.Errorf("%w: %v", errInvalidQuery, err)
+		}
+	}
+	return api.access.authorizeQuery(ctx, query)
 }
 
 // API endpoint implementations
@@ -522,6 +926,7 @@ func (api *RESTAPIHandler) apiInfo(c *fiber.Ctx) error {
 			"health":      "/health",
 			"metrics":     "/metrics",
 			"api":         "/api/v1",
+			"openapi":     "/api/v1/openapi.json",
 			"catalog":     "/api/v1/catalog",
 			"query":       "/api/v1/query",
 			"import":      "/api/v1/import",
@@ -555,9 +960,10 @@ func (api *RESTAPIHandler) readinessCheck(c *fiber.Ctx) error {
 	_, err := api.catalog.ListNamespaces(ctx, nil)
 	if err != nil {
 		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
-			"status":    "not ready",
-			"error":     err.Error(),
-			"timestamp": time.Now().Format(time.RFC3339),
+			"status":     "not ready",
+			"error":      err.Error(),
+			"error_code": errCodeUnavailable,
+			"timestamp":  time.Now().Format(time.RFC3339),
 		})
 	}
 
@@ -596,14 +1002,24 @@ func (api *RESTAPIHandler) metrics(c *fiber.Ctx) error {
 }
 
 func (api *RESTAPIHandler) listNamespaces(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
-	namespaces, err := api.catalog.ListNamespaces(ctx, nil)
+	all, err := api.catalog.ListNamespaces(ctx, nil)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return catalogError(c, err)
+	}
+
+	// Only namespaces the caller holds a grant in are listed
+	namespaces := []table.Identifier{}
+	for _, namespace := range all {
+		ok, err := api.access.canAccess(ctx, namespace)
+		if err != nil {
+			return catalogError(c, err)
+		}
+		if ok {
+			namespaces = append(namespaces, namespace)
+		}
 	}
 
 	return c.JSON(fiber.Map{
@@ -620,126 +1036,269 @@ func (api *RESTAPIHandler) executeSQL(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&request); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "Invalid JSON body",
-			"details": err.Error(),
-		})
+		return apiError(c, fiber.StatusBadRequest, errCodeInvalidRequest, "Invalid JSON body", err)
 	}
 
 	if request.SQL == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "SQL query is required",
-		})
+		return apiError(c, fiber.StatusBadRequest, errCodeInvalidRequest, "SQL query is required", nil)
 	}
 
 	// Set defaults
-	if request.MaxRows == 0 {
-		request.MaxRows = 1000
-	}
 	if request.Format == "" {
 		request.Format = "json"
 	}
+	contentType, streamed := streamFormats[request.Format]
+	if !streamed {
+		return apiError(c, fiber.StatusBadRequest, errCodeInvalidRequest,
+			fmt.Sprintf("Unsupported format %q: use json, csv, ndjson, parquet or arrow", request.Format), nil)
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-	defer cancel()
+	ctx, cancel := context.WithTimeout(c.UserContext(), 60*time.Second)
 
-	result, err := api.engine.ExecuteQuery(ctx, request.SQL)
+	if err := api.checkQuery(ctx, request.SQL); err != nil {
+		cancel()
+		return queryAccessError(c, "Query execution failed", err)
+	}
+
+	start := time.Now()
+	reader, err := api.engine.ExecuteQueryStream(ctx, request.SQL)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error":   "Query execution failed",
-			"details": err.Error(),
-		})
+		cancel()
+		return apiError(c, fiber.StatusBadRequest, errCodeQueryFailed, "Query execution failed", err)
+	}
+
+	if request.Format == "json" {
+		defer cancel()
+		defer reader.Release()
+
+		if request.MaxRows == 0 {
+			request.MaxRows = 1000
+		}
+		return api.sqlResponse(c, reader, request.MaxRows, start)
 	}
 
-	// Limit rows if requested
-	rows := result.Rows
-	if len(rows) > request.MaxRows {
-		rows = rows[:request.MaxRows]
+	// Other formats stream the whole result, or max_rows rows when given, once the
+	// handler has returned
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+		defer reader.Release()
+
+		if _, _, err := writeResults(w, request.Format, reader, int64(request.MaxRows)); err != nil {
+			log.Printf("Failed to stream query results: %v", err)
+		}
+		w.Flush()
+	})
+	return nil
+}
+
+// sqlResponse responds with the first maxRows rows of a query result as JSON, and
+// whether the result has more rows than that
+func (api *RESTAPIHandler) sqlResponse(c *fiber.Ctx, reader *duckdb.QueryStream, maxRows int, start time.Time) error {
+	columns, rows, more, err := collectRows(reader, maxRows)
+	if err != nil {
+		return apiError(c, fiber.StatusBadRequest, errCodeQueryFailed, "Query execution failed", err)
 	}
 
 	return c.JSON(fiber.Map{
-		"query_id":    result.QueryID,
-		"columns":     result.Columns,
+		"query_id":    reader.QueryID(),
+		"columns":     columns,
 		"rows":        rows,
 		"row_count":   len(rows),
-		"total_rows":  result.RowCount,
-		"duration_ms": result.Duration.Milliseconds(),
-		"truncated":   len(result.Rows) > request.MaxRows,
+		"duration_ms": time.Since(start).Milliseconds(),
+		"truncated":   more,
 	})
 }
 
 // Placeholder implementations for other endpoints
 func (api *RESTAPIHandler) createNamespace(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
-		"error":    "Endpoint not yet implemented",
-		"endpoint": "POST /api/v1/catalog/namespaces",
-	})
+	if err := api.access.authorize(c.UserContext(), grant.Admin, nil, ""); err != nil {
+		return catalogError(c, err)
+	}
+	return apiError(c, fiber.StatusNotImplemented, errCodeNotImplemented,
+		"Endpoint not yet implemented: POST /api/v1/catalog/namespaces", nil)
 }
 
 func (api *RESTAPIHandler) dropNamespace(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
-		"error":    "Endpoint not yet implemented",
-		"endpoint": "DELETE /api/v1/catalog/namespaces/:namespace",
-	})
+	if err := api.access.authorize(c.UserContext(), grant.Admin, namespaceFromParam(c.Params("namespace")), ""); err != nil {
+		return catalogError(c, err)
+	}
+	return apiError(c, fiber.StatusNotImplemented, errCodeNotImplemented,
+		"Endpoint not yet implemented: DELETE /api/v1/catalog/namespaces/:namespace", nil)
 }
 
 func (api *RESTAPIHandler) getNamespaceProperties(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
-		"error": "Endpoint not yet implemented",
-	})
+	return apiError(c, fiber.StatusNotImplemented, errCodeNotImplemented, "Endpoint not yet implemented", nil)
 }
 
 func (api *RESTAPIHandler) listTables(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
-		"error": "Endpoint not yet implemented",
-	})
-}
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
 
-func (api *RESTAPIHandler) createTable(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
-		"error": "Endpoint not yet implemented",
-	})
-}
+	namespace := namespaceFromParam(c.Params("namespace"))
+	exists, err := api.catalog.CheckNamespaceExists(ctx, namespace)
+	if err != nil {
+		return catalogError(c, err)
+	}
+	if !exists {
+		return catalogError(c, icebergcatalog.ErrNoSuchNamespace)
+	}
 
-func (api *RESTAPIHandler) describeTable(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
-		"error": "Endpoint not yet implemented",
-	})
-}
+	tables := []fiber.Map{}
+	for ident, err := range api.catalog.ListTables(ctx, namespace) {
+		if err != nil {
+			return catalogError(c, err)
+		}
+		readable, err := api.access.canRead(ctx, ident)
+		if err != nil {
+			return catalogError(c, err)
+		}
+		if readable {
+			tables = append(tables, tableRef(ident))
+		}
+	}
 
-func (api *RESTAPIHandler) dropTable(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
-		"error": "Endpoint not yet implemented",
+	return c.JSON(fiber.Map{
+		"namespace": namespace,
+		"tables":    tables,
+		"count":     len(tables),
 	})
 }
 
-func (api *RESTAPIHandler) getTableSchema(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
-		"error": "Endpoint not yet implemented",
-	})
-}
+func (api *RESTAPIHandler) createTable(c *fiber.Ctx) error {
+	var request struct {
+		Name          string                 `json:"name"`
+		Schema        *iceberg.Schema        `json:"schema"`
+		PartitionSpec *iceberg.PartitionSpec `json:"partition_spec,omitempty"`
+		Location      string                 `json:"location,omitempty"`
+		Properties    iceberg.Properties     `json:"properties,omitempty"`
+	}
 
-func (api *RESTAPIHandler) getTableProperties(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
-		"error": "Endpoint not yet implemented",
-	})
-}
+	if err := c.BodyParser(&request); err != nil {
+		return apiError(c, fiber.StatusBadRequest, errCodeInvalidRequest, "Invalid JSON body", err)
+	}
+	if request.Name == "" || strings.Contains(request.Name, ".") {
+		return apiError(c, fiber.StatusBadRequest, errCodeInvalidRequest, "name is required and must not contain '.'", nil)
+	}
+	if request.Schema == nil || len(request.Schema.Fields()) == 0 {
+		return apiError(c, fiber.StatusBadRequest, errCodeInvalidRequest, "schema with at least one field is required", nil)
+	}
 
-func (api *RESTAPIHandler) listSnapshots(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
-		"error": "Endpoint not yet implemented",
-	})
-}
+	opts := []icebergcatalog.CreateTableOpt{}
+	if request.PartitionSpec != nil {
+		opts = append(opts, icebergcatalog.WithPartitionSpec(request.PartitionSpec))
+	}
+	if request.Location != "" {
+		opts = append(opts, icebergcatalog.WithLocation(request.Location))
+	}
+	if len(request.Properties) > 0 {
+		opts = append(opts, icebergcatalog.WithProperties(request.Properties))
+	}
 
-func (api *RESTAPIHandler) listRegisteredTables(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 30*time.Second)
 	defer cancel()
 
-	tables, err := api.engine.ListTables(ctx)
+	tableIdent := append(namespaceFromParam(c.Params("namespace")), request.Name)
+	var err error
+	if request.Location != "" {
+		err = api.access.authorizeTableLocation(ctx, tableIdent, request.Location)
+	} else {
+		err = api.access.authorizeTable(ctx, grant.Write, tableIdent)
+	}
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return catalogError(c, err)
+	}
+	tbl, err := api.catalog.CreateTable(ctx, tableIdent, request.Schema, opts...)
+	if err != nil {
+		return catalogError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(tableDetails(tbl))
+}
+
+func (api *RESTAPIHandler) describeTable(c *fiber.Ctx) error {
+	tbl, err := api.loadTable(c)
+	if err != nil {
+		return catalogError(c, err)
+	}
+	return c.JSON(tableDetails(tbl))
+}
+
+func (api *RESTAPIHandler) dropTable(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 30*time.Second)
+	defer cancel()
+
+	tableIdent := tableIdentFromParams(c)
+	if err := api.access.authorizeTable(ctx, grant.Admin, tableIdent); err != nil {
+		return catalogError(c, err)
+	}
+	if err := api.catalog.DropTable(ctx, tableIdent); err != nil {
+		return catalogError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message":    "Table dropped successfully",
+		"identifier": strings.Join(tableIdent, "."),
+	})
+}
+
+func (api *RESTAPIHandler) getTableSchema(c *fiber.Ctx) error {
+	tbl, err := api.loadTable(c)
+	if err != nil {
+		return catalogError(c, err)
+	}
+
+	schema := tbl.Schema()
+	return c.JSON(fiber.Map{
+		"identifier": strings.Join(tbl.Identifier(), "."),
+		"schema_id":  schema.ID,
+		"schema":     schema,
+	})
+}
+
+func (api *RESTAPIHandler) getTableProperties(c *fiber.Ctx) error {
+	tbl, err := api.loadTable(c)
+	if err != nil {
+		return catalogError(c, err)
+	}
+
+	properties := tbl.Properties()
+	if properties == nil {
+		properties = iceberg.Properties{}
+	}
+	return c.JSON(fiber.Map{
+		"identifier": strings.Join(tbl.Identifier(), "."),
+		"properties": properties,
+	})
+}
+
+func (api *RESTAPIHandler) listSnapshots(c *fiber.Ctx) error {
+	tbl, err := api.loadTable(c)
+	if err != nil {
+		return catalogError(c, err)
+	}
+	return c.JSON(snapshotList(tbl))
+}
+
+func (api *RESTAPIHandler) listRegisteredTables(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	registered, err := api.engine.ListTables(ctx)
+	if err != nil {
+		return apiError(c, fiber.StatusInternalServerError, errCodeInternal, "Failed to list tables", err)
+	}
+
+	// Only names the caller may query are listed
+	tables := []string{}
+	for _, name := range registered {
+		ok, err := api.access.canQuery(ctx, name)
+		if err != nil {
+			return apiError(c, fiber.StatusInternalServerError, errCodeInternal, "Failed to list tables", err)
+		}
+		if ok {
+			tables = append(tables, name)
+		}
 	}
 
 	return c.JSON(fiber.Map{
@@ -749,8 +1308,42 @@ func (api *RESTAPIHandler) listRegisteredTables(c *fiber.Ctx) error {
 }
 
 func (api *RESTAPIHandler) explainQuery(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
-		"error": "Endpoint not yet implemented",
+	var request struct {
+		SQL string `json:"sql"`
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return apiError(c, fiber.StatusBadRequest, errCodeInvalidRequest, "Invalid JSON body", err)
+	}
+	if request.SQL == "" {
+		return apiError(c, fiber.StatusBadRequest, errCodeInvalidRequest, "SQL query is required", nil)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 60*time.Second)
+	defer cancel()
+
+	if err := api.checkQuery(ctx, request.SQL); err != nil {
+		return queryAccessError(c, "Query explain failed", err)
+	}
+
+	result, err := api.engine.ExecuteQuery(ctx, "EXPLAIN "+request.SQL)
+	if err != nil {
+		return apiError(c, fiber.StatusBadRequest, errCodeQueryFailed, "Query explain failed", err)
+	}
+
+	// DuckDB returns the plan as (explain_key, explain_value) rows
+	var plan []string
+	for _, row := range result.Rows() {
+		if len(row) > 0 {
+			plan = append(plan, fmt.Sprint(row[len(row)-1]))
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"query_id":    result.QueryID,
+		"sql":         request.SQL,
+		"plan":        strings.Join(plan, "\n"),
+		"duration_ms": result.Duration.Milliseconds(),
 	})
 }
 
@@ -762,141 +1355,698 @@ func (api *RESTAPIHandler) importAvro(c *fiber.Ctx) error {
 	return api.importFile(c, "avro")
 }
 
-// importFile handles file imports for both Parquet and Avro formats
-func (api *RESTAPIHandler) importFile(c *fiber.Ctx, expectedFormat string) error {
-	// Parse request body
-	type ImportFileRequest struct {
-		FilePath    string   `json:"file_path"`
-		TableName   string   `json:"table_name"`
-		Namespace   string   `json:"namespace,omitempty"`
-		Overwrite   bool     `json:"overwrite,omitempty"`
-		PartitionBy []string `json:"partition_by,omitempty"`
-	}
+// importFileRequest is the body of the import endpoints. Uploads to /api/v1/import send
+// the same fields, except file_path, as form fields or query parameters.
+type importFileRequest struct {
+	FilePath    string   `json:"file_path"`
+	TableName   string   `json:"table_name"`
+	Namespace   string   `json:"namespace,omitempty"`
+	Overwrite   bool     `json:"overwrite,omitempty"`
+	PartitionBy []string `json:"partition_by,omitempty"`
+}
 
-	var req ImportFileRequest
+// importFile queues an import of a Parquet or Avro file and responds with the job that
+// runs it. Clients follow its progress at /api/v1/import/status/:job_id.
+func (api *RESTAPIHandler) importFile(c *fiber.Ctx, expectedFormat string) error {
+	var req importFileRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return apiError(c, fiber.StatusBadRequest, errCodeInvalidRequest, "Invalid request body", err)
 	}
 
 	// Validate required fields
 	if req.FilePath == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "file_path is required",
-		})
+		return apiError(c, fiber.StatusBadRequest, errCodeInvalidRequest, "file_path is required", nil)
 	}
-	if req.TableName == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "table_name is required",
-		})
+	tableIdent, err := api.importTarget(c.UserContext(), req)
+	if err != nil {
+		return importTargetError(c, err)
 	}
 
-	// Set default namespace if not provided
-	if req.Namespace == "" {
-		req.Namespace = "default"
+	// Detect the file type up front so bad requests fail before a job is queued
+	importerType, err := importer.NewImporterFactory(api.config).DetectFileType(req.FilePath)
+	if err != nil {
+		return apiError(c, fiber.StatusBadRequest, errCodeInvalidRequest, "Unsupported file type", err)
+	}
+
+	// Verify the file format matches the endpoint
+	if string(importerType) != expectedFormat {
+		return apiError(c, fiber.StatusBadRequest, errCodeInvalidRequest,
+			fmt.Sprintf("File format mismatch: expected %s, got %s", expectedFormat, importerType), nil)
+	}
+
+	return api.queueImport(c, req, tableIdent, importerType, map[string]string{"file_path": req.FilePath}, nil)
+}
+
+// uploadImport imports a Parquet or Avro file sent with the request, either as the "file"
+// field of a multipart/form-data body or as the raw request body. The upload is spooled
+// to the server's upload directory and its format detected from its magic bytes, so
+// clients can import files that are not on the server. The target table is checked
+// before the file is read, so multipart bodies must send table_name ahead of the file.
+func (api *RESTAPIHandler) uploadImport(c *fiber.Ctx) error {
+	req := importFileRequest{
+		TableName: c.Query("table_name"),
+		Namespace: c.Query("namespace"),
+		Overwrite: c.QueryBool("overwrite"),
+	}
+	if partitionBy := c.Query("partition_by"); partitionBy != "" {
+		req.PartitionBy = strings.Split(partitionBy, ",")
+	}
+
+	var tableIdent table.Identifier
+	var targetErr error
+	checkTarget := func() error {
+		tableIdent, targetErr = api.importTarget(c.UserContext(), req)
+		return targetErr
 	}
 
-	// Create importer factory
-	factory := importer.NewImporterFactory(api.config)
+	mediaType, mediaParams, _ := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+	isMultipart := mediaType == fiber.MIMEMultipartForm
+
+	// Raw bodies carry no fields, so their target is known before any of the body is read
+	if req.TableName != "" || !isMultipart {
+		if err := checkTarget(); err != nil {
+			c.Context().SetConnectionClose()
+			return importTargetError(c, err)
+		}
+	}
 
-	// Detect file type and create appropriate importer
-	imp, importerType, err := factory.CreateImporter(req.FilePath)
+	var upload *spooledUpload
+	var err error
+	if isMultipart {
+		upload, err = api.spoolMultipart(requestBody(c), mediaParams["boundary"], &req, checkTarget)
+	} else {
+		upload, err = api.spoolUpload(requestBody(c), c.Query("file_name"))
+	}
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": fmt.Sprintf("Failed to create importer: %v", err),
-		})
+		// The rest of a rejected upload is not read, so the connection cannot be reused
+		c.Context().SetConnectionClose()
+	}
+	if targetErr != nil {
+		return importTargetError(c, targetErr)
+	}
+	var uploadErr *uploadError
+	if errors.As(err, &uploadErr) {
+		return apiError(c, uploadErr.status, uploadErr.code, uploadErr.message, uploadErr.err)
+	}
+	if err != nil {
+		return apiError(c, fiber.StatusInternalServerError, errCodeInternal, "Failed to store upload", err)
 	}
-	defer imp.Close()
 
-	// Verify the file format matches the endpoint
-	if string(importerType) != expectedFormat {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": fmt.Sprintf("File format mismatch: expected %s, got %s", expectedFormat, importerType),
-		})
+	req.FilePath = upload.path
+	params := map[string]string{
+		"source":    "upload",
+		"file_name": upload.name,
+		"size":      strconv.FormatInt(upload.size, 10),
+	}
+	return api.queueImport(c, req, tableIdent, upload.format, params, upload.remove)
+}
+
+// importTarget returns the table a request imports into after checking that the caller
+// may write to it. Importing a file_path of the server also needs catalog admin. Requests
+// without a valid table name fail with an *uploadError.
+func (api *RESTAPIHandler) importTarget(ctx context.Context, req importFileRequest) (table.Identifier, error) {
+	if req.TableName == "" {
+		return nil, &uploadError{fiber.StatusBadRequest, errCodeInvalidRequest, "table_name is required", nil}
+	}
+
+	// Set default namespace if not provided
+	namespace := req.Namespace
+	if namespace == "" {
+		namespace = "default"
 	}
 
 	// Parse table identifier
-	var tableIdent, namespaceIdent table.Identifier
+	var tableIdent table.Identifier
 	if strings.Contains(req.TableName, ".") {
 		parts := strings.Split(req.TableName, ".")
 		if len(parts) != 2 {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Invalid table name format. Use 'namespace.table' or just 'table'",
-			})
+			return nil, &uploadError{fiber.StatusBadRequest, errCodeInvalidRequest,
+				"Invalid table name format. Use 'namespace.table' or just 'table'", nil}
 		}
-		namespaceIdent = table.Identifier{parts[0]}
 		tableIdent = table.Identifier{parts[0], parts[1]}
 	} else {
-		namespaceIdent = table.Identifier{req.Namespace}
-		tableIdent = table.Identifier{req.Namespace, req.TableName}
+		tableIdent = table.Identifier{namespace, req.TableName}
 	}
 
-	// Infer schema
-	schema, stats, err := imp.InferSchema(req.FilePath)
+	var err error
+	if req.FilePath != "" {
+		err = api.access.authorizeFileImport(ctx, tableIdent, req.FilePath)
+	} else {
+		err = api.access.authorizeTable(ctx, grant.Write, tableIdent)
+	}
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": fmt.Sprintf("Failed to infer schema: %v", err),
-		})
+		return nil, err
 	}
+	return tableIdent, nil
+}
 
-	// Perform import
-	ctx := context.Background()
-	result, err := imp.ImportTable(ctx, importer.ImportRequest{
-		ParquetFile:    req.FilePath, // Note: field name is ParquetFile but used for any file type
-		TableIdent:     tableIdent,
-		NamespaceIdent: namespaceIdent,
-		Schema:         schema,
-		Overwrite:      req.Overwrite,
-		PartitionBy:    req.PartitionBy,
-	})
+// importTargetError writes the response for a request whose target table is invalid or
+// may not be written
+func importTargetError(c *fiber.Ctx, err error) error {
+	var uploadErr *uploadError
+	if errors.As(err, &uploadErr) {
+		return apiError(c, uploadErr.status, uploadErr.code, uploadErr.message, uploadErr.err)
+	}
+	return catalogError(c, err)
+}
+
+// queueImport submits the job importing a validated request into tableIdent and responds
+// with it. params describe the source of the file; cleanup, when set, runs once the file
+// is no longer needed, whether or not the job was queued.
+func (api *RESTAPIHandler) queueImport(c *fiber.Ctx, req importFileRequest, tableIdent table.Identifier, importerType importer.ImporterType, params map[string]string, cleanup func()) error {
+	if cleanup == nil {
+		cleanup = func() {}
+	}
+
+	params["table"] = strings.Join(tableIdent, ".")
+	params["format"] = string(importerType)
+	params["overwrite"] = strconv.FormatBool(req.Overwrite)
+
+	job, err := api.jobs.Submit("import", params, importJob(api.config, importerType, req, tableIdent, cleanup))
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": fmt.Sprintf("Failed to import table: %v", err),
-		})
+		cleanup()
 	}
+	if errors.Is(err, jobs.ErrQueueFull) || errors.Is(err, jobs.ErrClosed) {
+		return apiError(c, fiber.StatusServiceUnavailable, errCodeUnavailable, "Import queue is full, retry later", err)
+	}
+	if err != nil {
+		return apiError(c, fiber.StatusInternalServerError, errCodeInternal, "Failed to queue import", err)
+	}
+
+	statusURL := "/api/v1/import/status/" + job.ID
+	c.Set(fiber.HeaderLocation, statusURL)
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message":    "Import queued",
+		"job_id":     job.ID,
+		"status_url": statusURL,
+		"job":        job,
+	})
+}
+
+// importJob returns the job importing req.FilePath into tableIdent. cleanup, when set, runs
+// once the job no longer needs the file.
+func importJob(cfg *config.Config, importerType importer.ImporterType, req importFileRequest, tableIdent table.Identifier, cleanup func()) jobs.Func {
+	factory := importer.NewImporterFactory(cfg)
+	namespaceIdent := tableIdent[:len(tableIdent)-1]
 
-	// Return success response
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"message": "File imported successfully",
-		"result": fiber.Map{
-			"table_identifier": result.TableIdent,
+	return func(ctx context.Context, report func(jobs.Progress)) (map[string]interface{}, error) {
+		if cleanup != nil {
+			defer cleanup()
+		}
+
+		imp, err := factory.CreateImporterByType(importerType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create importer: %w", err)
+		}
+		defer imp.Close()
+
+		schema, stats, err := imp.InferSchema(req.FilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to infer schema: %w", err)
+		}
+
+		result, err := imp.ImportTable(ctx, importer.ImportRequest{
+			ParquetFile:    req.FilePath, // Note: field name is ParquetFile but used for any file type
+			TableIdent:     tableIdent,
+			NamespaceIdent: namespaceIdent,
+			Schema:         schema,
+			Overwrite:      req.Overwrite,
+			PartitionBy:    req.PartitionBy,
+			OnProgress: func(p importer.Progress) {
+				report(jobs.Progress{
+					RowsRead:    p.RowsRead,
+					RowsWritten: p.RowsWritten,
+					BytesRead:   p.BytesRead,
+					TotalBytes:  p.TotalBytes,
+				})
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to import table: %w", err)
+		}
+
+		return map[string]interface{}{
+			"table_identifier": strings.Join(result.TableIdent, "."),
 			"record_count":     result.RecordCount,
 			"data_size":        result.DataSize,
 			"table_location":   result.TableLocation,
 			"file_format":      importerType,
-		},
-		"schema": schema,
-		"stats":  stats,
-	})
+			"schema":           schema,
+			"stats":            stats,
+		}, nil
+	}
+}
+
+// maxUploadFieldSize bounds the form fields read alongside an uploaded file
+const maxUploadFieldSize = 64 * 1024
+
+// spooledUpload is an uploaded file written to the upload directory
+type spooledUpload struct {
+	path   string
+	name   string // file name given by the client, if any
+	format importer.ImporterType
+	size   int64
+}
+
+// remove deletes the spooled file
+func (u *spooledUpload) remove() {
+	if err := os.Remove(u.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Warning: failed to remove upload %s: %v", u.path, err)
+	}
+}
+
+// uploadError is a rejected upload and the response describing it
+type uploadError struct {
+	status  int
+	code    string
+	message string
+	err     error
+}
+
+func (e *uploadError) Error() string {
+	if e.err != nil {
+		return e.message + ": " + e.err.Error()
+	}
+	return e.message
+}
+
+func (e *uploadError) Unwrap() error {
+	return e.err
+}
+
+// requestBody returns the request body as a reader. Bodies beyond the server's body limit
+// are streamed rather than buffered.
+func requestBody(c *fiber.Ctx) io.Reader {
+	if stream := c.Context().RequestBodyStream(); stream != nil {
+		return stream
+	}
+	return bytes.NewReader(c.Body())
+}
+
+// spoolUpload detects the format of an uploaded file from its first bytes and writes it
+// to the upload directory, failing once it exceeds the upload size limit
+func (api *RESTAPIHandler) spoolUpload(r io.Reader, name string) (*spooledUpload, error) {
+	br := bufio.NewReader(r)
+	header, _ := br.Peek(8)
+	if len(header) == 0 {
+		return nil, &uploadError{fiber.StatusBadRequest, errCodeInvalidRequest, "A file is required", nil}
+	}
+	format, err := importer.NewImporterFactory(api.config).DetectFileTypeFromHeader(header)
+	if err != nil {
+		return nil, &uploadError{fiber.StatusUnsupportedMediaType, errCodeUnsupportedFormat, "Unsupported file format", err}
+	}
+
+	if err := os.MkdirAll(api.uploadDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create upload directory: %w", err)
+	}
+	// The extension lets the importers recognize the spooled file
+	f, err := os.CreateTemp(api.uploadDir, "upload-*."+string(format))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload file: %w", err)
+	}
+	upload := &spooledUpload{path: f.Name(), name: name, format: format}
+
+	upload.size, err = io.Copy(f, io.LimitReader(br, api.maxUploadSize+1))
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		upload.remove()
+		return nil, fmt.Errorf("failed to write upload file: %w", closeErr)
+	}
+	if err != nil {
+		upload.remove()
+		return nil, &uploadError{fiber.StatusBadRequest, errCodeInvalidRequest, "Failed to read upload", err}
+	}
+	if upload.size > api.maxUploadSize {
+		upload.remove()
+		return nil, &uploadError{fiber.StatusRequestEntityTooLarge, errCodePayloadTooLarge,
+			fmt.Sprintf("Upload exceeds the limit of %d bytes", api.maxUploadSize), nil}
+	}
+	return upload, nil
+}
+
+// spoolMultipart spools the "file" field of a multipart/form-data body and reads the
+// other fields into req. checkTarget runs when the file field arrives, so an upload to a
+// missing or forbidden table is rejected before it is read.
+func (api *RESTAPIHandler) spoolMultipart(r io.Reader, boundary string, req *importFileRequest, checkTarget func() error) (*spooledUpload, error) {
+	if boundary == "" {
+		return nil, &uploadError{fiber.StatusBadRequest, errCodeInvalidRequest, "Multipart body has no boundary", nil}
+	}
+
+	var upload *spooledUpload
+	fail := func(err error) (*spooledUpload, error) {
+		if upload != nil {
+			upload.remove()
+		}
+		return nil, err
+	}
+
+	mr := multipart.NewReader(r, boundary)
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fail(&uploadError{fiber.StatusBadRequest, errCodeInvalidRequest, "Invalid multipart body", err})
+		}
+
+		if part.FormName() == "file" {
+			if upload != nil {
+				return fail(&uploadError{fiber.StatusBadRequest, errCodeInvalidRequest, "Only one file may be uploaded", nil})
+			}
+			if err := checkTarget(); err != nil {
+				return nil, err
+			}
+			if upload, err = api.spoolUpload(part, part.FileName()); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		value, err := io.ReadAll(io.LimitReader(part, maxUploadFieldSize))
+		if err != nil {
+			return fail(&uploadError{fiber.StatusBadRequest, errCodeInvalidRequest, "Invalid multipart body", err})
+		}
+		if upload != nil && (part.FormName() == "table_name" || part.FormName() == "namespace") {
+			// The file was accepted for the table named before it
+			return fail(&uploadError{fiber.StatusBadRequest, errCodeInvalidRequest,
+				"table_name and namespace must precede the file field", nil})
+		}
+		switch part.FormName() {
+		case "table_name":
+			req.TableName = string(value)
+		case "namespace":
+			req.Namespace = string(value)
+		case "overwrite":
+			if req.Overwrite, err = strconv.ParseBool(string(value)); err != nil {
+				return fail(&uploadError{fiber.StatusBadRequest, errCodeInvalidRequest, "overwrite must be true or false", err})
+			}
+		case "partition_by":
+			req.PartitionBy = append(req.PartitionBy, strings.Split(string(value), ",")...)
+		}
+	}
+
+	if upload == nil {
+		return nil, &uploadError{fiber.StatusBadRequest, errCodeInvalidRequest, "A file field is required", nil}
+	}
+	return upload, nil
 }
 
 func (api *RESTAPIHandler) getImportStatus(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
-		"error": "Endpoint not yet implemented",
+	job, err := api.jobs.Get(c.Params("job_id"))
+	if err != nil {
+		return apiError(c, fiber.StatusNotFound, errCodeJobNotFound, "Import job not found", err)
+	}
+	if err := api.access.authorizeJob(c.UserContext(), grant.Read, job); err != nil {
+		return catalogError(c, err)
+	}
+	return c.JSON(job)
+}
+
+func (api *RESTAPIHandler) listImportJobs(c *fiber.Ctx) error {
+	history := []jobs.Job{}
+	for _, job := range api.jobs.List() {
+		err := api.access.authorizeJob(c.UserContext(), grant.Read, job)
+		if errors.Is(err, auth.ErrPermissionDenied) {
+			continue
+		}
+		if err != nil {
+			return catalogError(c, err)
+		}
+		history = append(history, job)
+	}
+	return c.JSON(fiber.Map{
+		"jobs":  history,
+		"count": len(history),
 	})
 }
 
+func (api *RESTAPIHandler) cancelImportJob(c *fiber.Ctx) error {
+	job, err := api.jobs.Get(c.Params("job_id"))
+	if err == nil {
+		if err := api.access.authorizeJob(c.UserContext(), grant.Write, job); err != nil {
+			return catalogError(c, err)
+		}
+		job, err = api.jobs.Cancel(job.ID)
+	}
+	switch {
+	case errors.Is(err, jobs.ErrNotFound):
+		return apiError(c, fiber.StatusNotFound, errCodeJobNotFound, "Import job not found", err)
+	case errors.Is(err, jobs.ErrFinished):
+		return apiError(c, fiber.StatusConflict, errCodeJobFinished, fmt.Sprintf("Import job already %s", job.State), err)
+	case err != nil:
+		return apiError(c, fiber.StatusInternalServerError, errCodeInternal, "Failed to cancel import job", err)
+	}
+	return c.Status(fiber.StatusAccepted).JSON(job)
+}
+
 func (api *RESTAPIHandler) timeTravelQuery(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
-		"error": "Endpoint not yet implemented",
+	var request struct {
+		Table   string `json:"table"`
+		AsOf    string `json:"as_of"`
+		SQL     string `json:"sql,omitempty"`
+		MaxRows int    `json:"max_rows,omitempty"`
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return apiError(c, fiber.StatusBadRequest, errCodeInvalidRequest, "Invalid JSON body", err)
+	}
+	if request.AsOf == "" {
+		return apiError(c, fiber.StatusBadRequest, errCodeInvalidRequest,
+			"as_of is required: a timestamp (2025-01-01T10:00:00Z) or snapshot ID", nil)
+	}
+	tableIdent, _, err := parseTableIdentifier(request.Table, "")
+	if err != nil {
+		return apiError(c, fiber.StatusBadRequest, errCodeInvalidRequest, "Invalid table", err)
+	}
+	if _, err := strconv.ParseInt(request.AsOf, 10, 64); err != nil {
+		if _, err := parseTimestamp(request.AsOf); err != nil {
+			return apiError(c, fiber.StatusBadRequest, errCodeInvalidRequest, "Invalid as_of", err)
+		}
+	}
+	if request.MaxRows == 0 {
+		request.MaxRows = 1000
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 60*time.Second)
+	defer cancel()
+
+	if err := api.access.authorizeTable(ctx, grant.Read, tableIdent); err != nil {
+		return catalogError(c, err)
+	}
+	tbl, err := api.catalog.LoadTable(ctx, tableIdent, nil)
+	if err != nil {
+		return catalogError(c, err)
+	}
+
+	snapshotID, resolvedTime, err := resolveSnapshot(tbl, request.AsOf)
+	if err != nil {
+		return apiError(c, fiber.StatusNotFound, errCodeSnapshotNotFound, "Snapshot not found", err)
+	}
+
+	query := request.SQL
+	if query == "" {
+		query = defaultTimeTravelQuery(tableIdent)
+	}
+	if err := api.checkQuery(ctx, query); err != nil {
+		return queryAccessError(c, "Time-travel query failed", err)
+	}
+
+	start := time.Now()
+	reader, err := api.engine.ExecuteQueryStreamAtSnapshot(ctx, tableIdent, tbl, snapshotID, query)
+	if err != nil {
+		return apiError(c, fiber.StatusBadRequest, errCodeQueryFailed, "Time-travel query failed", err)
+	}
+	defer reader.Release()
+
+	columns, rows, more, err := collectRows(reader, request.MaxRows)
+	if err != nil {
+		return apiError(c, fiber.StatusBadRequest, errCodeQueryFailed, "Time-travel query failed", err)
+	}
+
+	return c.JSON(fiber.Map{
+		"query_id":    reader.QueryID(),
+		"snapshot_id": snapshotID,
+		"as_of":       resolvedTime.Format(time.RFC3339),
+		"sql":         query,
+		"columns":     columns,
+		"rows":        rows,
+		"row_count":   len(rows),
+		"duration_ms": time.Since(start).Milliseconds(),
+		"truncated":   more,
 	})
 }
 
 func (api *RESTAPIHandler) getTableSnapshots(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
-		"error": "Endpoint not yet implemented",
-	})
+	tbl, err := api.loadTable(c)
+	if err != nil {
+		return catalogError(c, err)
+	}
+
+	// The snapshot log records when each snapshot became current, which is what
+	// timestamp-based time travel resolves against
+	history := slices.Collect(tbl.Metadata().SnapshotLogs())
+	if history == nil {
+		history = []table.SnapshotLogEntry{}
+	}
+
+	response := snapshotList(tbl)
+	response["history"] = history
+	return c.JSON(response)
+}
+
+func (api *RESTAPIHandler) openAPISpec(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Send(openAPIDocument)
+}
+
+// loadTable loads the table named by the :namespace and :table path parameters, which
+// the caller must be allowed to read
+func (api *RESTAPIHandler) loadTable(c *fiber.Ctx) (*table.Table, error) {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	tableIdent := tableIdentFromParams(c)
+	if err := api.access.authorizeTable(ctx, grant.Read, tableIdent); err != nil {
+		return nil, err
+	}
+	return api.catalog.LoadTable(ctx, tableIdent, nil)
+}
+
+// namespaceFromParam parses a namespace path parameter. Nested namespaces are
+// separated by dots, as in "analytics.events".
+func namespaceFromParam(param string) table.Identifier {
+	// Fiber reuses the memory of path parameters once the handler returns
+	return strings.Split(utils.CopyString(param), ".")
+}
+
+// tableIdentFromParams returns the identifier of the table named by the :namespace and
+// :table path parameters
+func tableIdentFromParams(c *fiber.Ctx) table.Identifier {
+	return append(namespaceFromParam(c.Params("namespace")), utils.CopyString(c.Params("table")))
+}
+
+// tableRef describes a table in listings
+func tableRef(ident table.Identifier) fiber.Map {
+	return fiber.Map{
+		"namespace":  icebergcatalog.NamespaceFromIdent(ident),
+		"name":       icebergcatalog.TableNameFromIdent(ident),
+		"identifier": strings.Join(ident, "."),
+	}
+}
+
+// tableDetails describes a table's metadata. Schemas, partition specs, sort orders and
+// snapshots use the JSON formats of the Iceberg table spec.
+func tableDetails(tbl *table.Table) fiber.Map {
+	metadata := tbl.Metadata()
+	details := tableRef(tbl.Identifier())
+	details["uuid"] = metadata.TableUUID().String()
+	details["format_version"] = metadata.Version()
+	details["location"] = tbl.Location()
+	details["metadata_location"] = tbl.MetadataLocation()
+	details["last_updated_ms"] = metadata.LastUpdatedMillis()
+	details["schema"] = tbl.Schema()
+	details["partition_spec"] = tbl.Spec()
+	details["sort_order"] = tbl.SortOrder()
+	details["properties"] = tbl.Properties()
+	details["current_snapshot"] = tbl.CurrentSnapshot()
+	details["snapshot_count"] = len(metadata.Snapshots())
+	return details
+}
+
+// snapshotList describes the snapshots of a table, oldest first
+func snapshotList(tbl *table.Table) fiber.Map {
+	snapshots := tbl.Metadata().Snapshots()
+	if snapshots == nil {
+		snapshots = []table.Snapshot{}
+	}
+
+	var currentID *int64
+	if current := tbl.CurrentSnapshot(); current != nil {
+		currentID = &current.SnapshotID
+	}
+
+	return fiber.Map{
+		"identifier":          strings.Join(tbl.Identifier(), "."),
+		"current_snapshot_id": currentID,
+		"snapshots":           snapshots,
+		"count":               len(snapshots),
+	}
+}
+
+// Error codes returned in the "error_code" field of API errors. Clients should branch
+// on these rather than on messages.
+const (
+	errCodeInvalidRequest    = "INVALID_REQUEST"
+	errCodeUnauthorized      = "UNAUTHORIZED"
+	errCodeForbidden         = "FORBIDDEN"
+	errCodeReadOnly          = "READ_ONLY"
+	errCodeNotFound          = "NOT_FOUND"
+	errCodeNamespaceNotFound = "NAMESPACE_NOT_FOUND"
+	errCodeTableNotFound     = "TABLE_NOT_FOUND"
+	errCodeSnapshotNotFound  = "SNAPSHOT_NOT_FOUND"
+	errCodeJobNotFound       = "JOB_NOT_FOUND"
+	errCodeJobFinished       = "JOB_FINISHED"
+	errCodeUnsupportedFormat = "UNSUPPORTED_FORMAT"
+	errCodePayloadTooLarge   = "PAYLOAD_TOO_LARGE"
+	errCodeAlreadyExists     = "ALREADY_EXISTS"
+	errCodeQueryFailed       = "QUERY_FAILED"
+	errCodeNotImplemented    = "NOT_IMPLEMENTED"
+	errCodeUnavailable       = "UNAVAILABLE"
+	errCodeInternal          = "INTERNAL_ERROR"
+)
+
+// apiError writes an error response. Every API error has the same shape: a message,
+// the HTTP status, an error code and, when err is not nil, its details.
+func apiError(c *fiber.Ctx, status int, errorCode, message string, err error) error {
+	body := fiber.Map{
+		"error":      message,
+		"code":       status,
+		"error_code": errorCode,
+		"timestamp":  time.Now().Format(time.RFC3339),
+	}
+	if err != nil {
+		body["details"] = err.Error()
+	}
+	return c.Status(status).JSON(body)
+}
+
+// catalogError writes the response for a failed catalog operation
+func catalogError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, auth.ErrPermissionDenied):
+		return apiError(c, fiber.StatusForbidden, errCodeForbidden, "Permission denied", err)
+	case errors.Is(err, icebergcatalog.ErrNoSuchTable):
+		return apiError(c, fiber.StatusNotFound, errCodeTableNotFound, "Table not found", err)
+	case errors.Is(err, icebergcatalog.ErrNoSuchNamespace):
+		return apiError(c, fiber.StatusNotFound, errCodeNamespaceNotFound, "Namespace not found", err)
+	case errors.Is(err, icebergcatalog.ErrTableAlreadyExists), errors.Is(err, icebergcatalog.ErrViewAlreadyExists):
+		return apiError(c, fiber.StatusConflict, errCodeAlreadyExists, "Table or view already exists", err)
+	}
+	return apiError(c, fiber.StatusInternalServerError, errCodeInternal, "Catalog operation failed", err)
+}
+
+// queryAccessError writes the response for a query the caller may not run or that could
+// not be checked
+func queryAccessError(c *fiber.Ctx, message string, err error) error {
+	switch {
+	case errors.Is(err, errInvalidQuery):
+		return apiError(c, fiber.StatusBadRequest, errCodeQueryFailed, message, err)
+	case errors.Is(err, duckdb.ErrNotReadOnly):
+		return apiError(c, fiber.StatusForbidden, errCodeReadOnly, "Query rejected in read-only mode", err)
+	}
+	return catalogError(c, err)
 }
 
 func (api *RESTAPIHandler) getServerConfig(c *fiber.Ctx) error {
+	if err := api.access.authorize(c.UserContext(), grant.Admin, nil, ""); err != nil {
+		return catalogError(c, err)
+	}
+
 	// Sanitize sensitive information
 	config := map[string]interface{}{
 		"name":    api.config.Name,
 		"profile": api.profile.Name,
-		"catalog": map[string]interface{}{
-			"type": api.config.Catalog.Type,
-		},
+		"catalog": catalogDetails(api.config),
 		"storage": map[string]interface{}{
 			"type": api.config.Storage.Type,
 		},
@@ -908,7 +2058,57 @@ func (api *RESTAPIHandler) getServerConfig(c *fiber.Ctx) error {
 	})
 }
 
+// catalogDetails describes the project's catalog for the admin config endpoint and the
+// UI dashboard. Credentials and tokens are left out.
+func catalogDetails(cfg *config.Config) map[string]interface{} {
+	details := map[string]interface{}{
+		"type": cfg.Catalog.Type,
+	}
+
+	switch cfg.Catalog.Type {
+	case "sqlite":
+		if cfg.Catalog.SQLite != nil {
+			details["path"] = cfg.Catalog.SQLite.Path
+		}
+	case "json":
+		if cfg.Catalog.JSON != nil {
+			details["uri"] = cfg.Catalog.JSON.URI
+			details["warehouse"] = cfg.Catalog.JSON.Warehouse
+		}
+	case "rest":
+		if rest := cfg.Catalog.REST; rest != nil {
+			details["uri"] = rest.URI
+			if rest.WarehouseLocation != "" {
+				details["warehouse"] = rest.WarehouseLocation
+			}
+			if rest.Prefix != "" {
+				details["prefix"] = rest.Prefix
+			}
+
+			authentication := "none"
+			switch {
+			case rest.OAuth != nil && rest.OAuth.Token != "":
+				authentication = "oauth-token"
+			case rest.OAuth != nil && rest.OAuth.Credential != "":
+				authentication = "oauth-client-credentials"
+			case rest.SigV4 != nil && rest.SigV4.Enabled:
+				authentication = "sigv4"
+			case len(rest.Credentials) > 0:
+				authentication = "credentials"
+			}
+			details["authentication"] = authentication
+			details["tls_skip_verify"] = rest.TLS != nil && rest.TLS.SkipVerify
+		}
+	}
+
+	return details
+}
+
 func (api *RESTAPIHandler) clearCache(c *fiber.Ctx) error {
+	if err := api.access.authorize(c.UserContext(), grant.Admin, nil, ""); err != nil {
+		return catalogError(c, err)
+	}
+
 	api.engine.ClearTableCache()
 	return c.JSON(fiber.Map{
 		"message":   "Cache cleared successfully",
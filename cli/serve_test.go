@@ -0,0 +1,784 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TFMV/icebox/auth"
+	"github.com/TFMV/icebox/catalog"
+	"github.com/TFMV/icebox/catalog/grant"
+	"github.com/TFMV/icebox/catalog/sqlite"
+	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/engine/duckdb"
+	"github.com/TFMV/icebox/jobs"
+	"github.com/TFMV/icebox/tableops"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// appendTestRows commits a snapshot appending rows with the given ids to a table whose
+// only column is a required id
+func appendTestRows(t *testing.T, cat catalog.CatalogInterface, ident table.Identifier, ids ...int64) {
+	t.Helper()
+
+	ctx := context.Background()
+	tbl, err := cat.LoadTable(ctx, ident, nil)
+	require.NoError(t, err)
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer builder.Release()
+	builder.Field(0).(*array.Int64Builder).AppendValues(ids, nil)
+	record := builder.NewRecord()
+	defer record.Release()
+	rows := array.NewTableFromRecords(schema, []arrow.Record{record})
+	defer rows.Release()
+
+	require.NoError(t, tableops.NewWriter(cat).WriteArrowTable(ctx, tbl, rows, nil))
+}
+
+// newTestRESTServer creates the REST routes over a SQLite catalog in a temporary
+// directory. The handler has no SQL engine, so only catalog endpoints work.
+func newTestRESTServer(t *testing.T) (*fiber.App, *RESTAPIHandler) {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		Name: "test-catalog",
+		Catalog: config.CatalogConfig{
+			Type:   "sqlite",
+			SQLite: &config.SQLiteConfig{Path: filepath.Join(tempDir, "catalog.db")},
+		},
+		Storage: config.StorageConfig{
+			Type:       "fs",
+			FileSystem: &config.FileSystemConfig{RootPath: filepath.Join(tempDir, "data")},
+		},
+	}
+
+	cat, err := sqlite.NewCatalog(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { cat.Close() })
+
+	importJobs, err := jobs.NewManager(jobs.Options{HistoryPath: filepath.Join(tempDir, ".icebox", "jobs.json")})
+	require.NoError(t, err)
+	t.Cleanup(func() { importJobs.Close() })
+
+	api := &RESTAPIHandler{
+		catalog: cat,
+		config:  cfg,
+		profile: getServerProfile("local"),
+		jobs:    importJobs,
+
+		uploadDir:     filepath.Join(tempDir, ".icebox", "uploads"),
+		maxUploadSize: 10 * 1024 * 1024,
+	}
+	// A small body limit makes uploads take the streaming path of the server
+	app := fiber.New(fiber.Config{StreamRequestBody: true, DisablePreParseMultipartForm: true, BodyLimit: 4096})
+	registerRESTRoutes(app, api)
+	return app, api
+}
+
+// doJSON sends a request with an optional JSON body and decodes the JSON response
+func doJSON(t *testing.T, app *fiber.App, method, path string, body interface{}) (int, map[string]interface{}) {
+	t.Helper()
+	return doJSONWithKey(t, app, "", method, path, body)
+}
+
+// doJSONWithKey is doJSON for a client authenticating with an API key
+func doJSONWithKey(t *testing.T, app *fiber.App, apiKey, method, path string, body interface{}) (int, map[string]interface{}) {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(data)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&decoded))
+	return resp.StatusCode, decoded
+}
+
+func TestValidateServeOptions(t *testing.T) {
+	saved := *serveOpts
+	t.Cleanup(func() { *serveOpts = saved })
+
+	tests := []struct {
+		mode      string
+		readonly  bool
+		prefork   bool
+		wantError bool
+	}{
+		{mode: "rest"},
+		{mode: "rest", readonly: true, prefork: true},
+		{mode: "rest", prefork: true, wantError: true},
+		{mode: "grpc", readonly: true, wantError: true},
+	}
+	for _, tt := range tests {
+		serveOpts.mode, serveOpts.readonly, serveOpts.prefork = tt.mode, tt.readonly, tt.prefork
+		err := validateServeOptions()
+		assert.Equal(t, tt.wantError, err != nil, "%+v: %v", tt, err)
+	}
+}
+
+func TestRESTTableEndpoints(t *testing.T) {
+	app, api := newTestRESTServer(t)
+	require.NoError(t, api.catalog.CreateNamespace(context.Background(), table.Identifier{"sales"}, iceberg.Properties{}))
+
+	createBody := map[string]interface{}{
+		"name": "orders",
+		"schema": json.RawMessage(`{"type": "struct", "fields": [
+			{"id": 1, "name": "id", "type": "long", "required": true},
+			{"id": 2, "name": "region", "type": "string", "required": false}
+		]}`),
+		"partition_spec": json.RawMessage(`{"spec-id": 0, "fields": [
+			{"source-id": 2, "field-id": 1000, "name": "region", "transform": "identity"}
+		]}`),
+		"properties": map[string]string{"owner": "analytics"},
+	}
+	status, body := doJSON(t, app, http.MethodPost, "/api/v1/namespaces/sales/tables", createBody)
+	require.Equal(t, http.StatusCreated, status, body)
+	assert.Equal(t, "sales.orders", body["identifier"])
+	assert.Equal(t, float64(2), body["format_version"])
+	assert.Nil(t, body["current_snapshot"])
+	spec := body["partition_spec"].(map[string]interface{})
+	assert.Len(t, spec["fields"], 1)
+
+	status, body = doJSON(t, app, http.MethodPost, "/api/v1/namespaces/sales/tables", createBody)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Equal(t, errCodeAlreadyExists, body["error_code"])
+
+	status, body = doJSON(t, app, http.MethodGet, "/api/v1/namespaces/sales/tables", nil)
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Equal(t, float64(1), body["count"])
+
+	status, body = doJSON(t, app, http.MethodGet, "/api/v1/namespaces/sales/tables/orders", nil)
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Equal(t, "analytics", body["properties"].(map[string]interface{})["owner"])
+
+	status, body = doJSON(t, app, http.MethodGet, "/api/v1/namespaces/sales/tables/orders/schema", nil)
+	require.Equal(t, http.StatusOK, status, body)
+	schema := body["schema"].(map[string]interface{})
+	assert.Equal(t, "struct", schema["type"])
+	assert.Len(t, schema["fields"], 2)
+
+	status, body = doJSON(t, app, http.MethodGet, "/api/v1/namespaces/sales/tables/orders/properties", nil)
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Equal(t, "analytics", body["properties"].(map[string]interface{})["owner"])
+
+	status, body = doJSON(t, app, http.MethodGet, "/api/v1/namespaces/sales/tables/orders/snapshots", nil)
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Equal(t, float64(0), body["count"])
+	assert.Nil(t, body["current_snapshot_id"])
+
+	status, body = doJSON(t, app, http.MethodGet, "/api/v1/time-travel/snapshots/sales/orders", nil)
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Equal(t, []interface{}{}, body["history"])
+
+	status, body = doJSON(t, app, http.MethodDelete, "/api/v1/namespaces/sales/tables/orders", nil)
+	require.Equal(t, http.StatusOK, status, body)
+
+	status, body = doJSON(t, app, http.MethodGet, "/api/v1/namespaces/sales/tables/orders", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, errCodeTableNotFound, body["error_code"])
+}
+
+func TestRESTErrors(t *testing.T) {
+	app, _ := newTestRESTServer(t)
+
+	tests := []struct {
+		method    string
+		path      string
+		body      interface{}
+		status    int
+		errorCode string
+	}{
+		{http.MethodGet, "/api/v1/namespaces/missing/tables", nil, http.StatusNotFound, errCodeNamespaceNotFound},
+		{http.MethodPost, "/api/v1/namespaces/missing/tables", map[string]interface{}{
+			"name":   "t",
+			"schema": json.RawMessage(`{"type": "struct", "fields": [{"id": 1, "name": "id", "type": "long", "required": true}]}`),
+		}, http.StatusNotFound, errCodeNamespaceNotFound},
+		{http.MethodPost, "/api/v1/namespaces/default/tables", map[string]interface{}{"name": "t"}, http.StatusBadRequest, errCodeInvalidRequest},
+		{http.MethodDelete, "/api/v1/namespaces/default/tables/missing", nil, http.StatusNotFound, errCodeTableNotFound},
+		{http.MethodPost, "/api/v1/query/explain", map[string]interface{}{}, http.StatusBadRequest, errCodeInvalidRequest},
+		{http.MethodPost, "/api/v1/time-travel/query", map[string]interface{}{"table": "default.t"}, http.StatusBadRequest, errCodeInvalidRequest},
+		{http.MethodPost, "/api/v1/time-travel/query", map[string]interface{}{"table": "default.t", "as_of": "yesterday"}, http.StatusBadRequest, errCodeInvalidRequest},
+		{http.MethodGet, "/api/v1/import/status/1", nil, http.StatusNotFound, errCodeJobNotFound},
+		{http.MethodPost, "/api/v1/import/jobs/1/cancel", nil, http.StatusNotFound, errCodeJobNotFound},
+		{http.MethodPost, "/api/v1/import/parquet", map[string]interface{}{"table_name": "t"}, http.StatusBadRequest, errCodeInvalidRequest},
+		{http.MethodPost, "/api/v1/import/avro", map[string]interface{}{
+			"file_path":  filepath.Join("..", "testdata", "titanic.parquet"),
+			"table_name": "t",
+		}, http.StatusBadRequest, errCodeInvalidRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			status, body := doJSON(t, app, tt.method, tt.path, tt.body)
+			assert.Equal(t, tt.status, status, body)
+			assert.Equal(t, tt.errorCode, body["error_code"])
+			assert.Equal(t, float64(tt.status), body["code"])
+			assert.NotEmpty(t, body["error"])
+		})
+	}
+}
+
+// waitForImportJob polls the status of an import job until it finishes
+func waitForImportJob(t *testing.T, app *fiber.App, statusURL string) map[string]interface{} {
+	t.Helper()
+
+	var job map[string]interface{}
+	require.Eventually(t, func() bool {
+		var status int
+		status, job = doJSON(t, app, http.MethodGet, statusURL, nil)
+		require.Equal(t, http.StatusOK, status, job)
+		return job["state"] == "succeeded" || job["state"] == "failed"
+	}, 30*time.Second, 20*time.Millisecond)
+	return job
+}
+
+// multipartBody builds a multipart/form-data body with a file and form fields
+func multipartBody(t *testing.T, fileName string, data []byte, fields map[string]string) (*bytes.Buffer, string) {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	for name, value := range fields {
+		require.NoError(t, w.WriteField(name, value))
+	}
+	if data != nil {
+		part, err := w.CreateFormFile("file", fileName)
+		require.NoError(t, err)
+		_, err = part.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return body, w.FormDataContentType()
+}
+
+// doUpload sends a body to the upload import endpoint and decodes the JSON response
+func doUpload(t *testing.T, app *fiber.App, path string, body io.Reader, contentType string) (int, map[string]interface{}) {
+	t.Helper()
+	return doUploadWithKey(t, app, "", path, body, contentType)
+}
+
+// doUploadWithKey is doUpload for a client authenticating with an API key
+func doUploadWithKey(t *testing.T, app *fiber.App, apiKey, path string, body io.Reader, contentType string) (int, map[string]interface{}) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, path, body)
+	req.Header.Set("Content-Type", contentType)
+	if apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&decoded))
+	return resp.StatusCode, decoded
+}
+
+func TestRESTAuthentication(t *testing.T) {
+	_, api := newTestRESTServer(t)
+	digest := sha256.Sum256([]byte("secret-key"))
+	authenticator, err := auth.New(&config.AuthConfig{
+		APIKeys: []config.APIKeyConfig{{Name: "etl", Hash: "sha256:" + hex.EncodeToString(digest[:])}},
+	})
+	require.NoError(t, err)
+	authenticator.SetAuditWriter(io.Discard)
+	api.auth = authenticator
+
+	app := fiber.New()
+	registerRESTRoutes(app, api)
+
+	status, body := doJSON(t, app, http.MethodGet, "/api/v1/catalog/namespaces", nil)
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Equal(t, errCodeUnauthorized, body["error_code"])
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/catalog/namespaces", nil)
+	req.Header.Set("X-API-Key", "secret-key")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// The API description stays public
+	status, _ = doJSON(t, app, http.MethodGet, "/api/v1/openapi.json", nil)
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestRESTAccessControl(t *testing.T) {
+	_, api := newTestRESTServer(t)
+	ctx := context.Background()
+
+	keyHash := func(key string) string {
+		digest := sha256.Sum256([]byte(key))
+		return "sha256:" + hex.EncodeToString(digest[:])
+	}
+	authenticator, err := auth.New(&config.AuthConfig{
+		APIKeys: []config.APIKeyConfig{
+			{Name: "dashboards", Hash: keyHash("analyst-key"), Roles: []string{"analyst"}},
+			{Name: "etl", Hash: keyHash("etl-key")},
+		},
+	})
+	require.NoError(t, err)
+	authenticator.SetAuditWriter(io.Discard)
+	api.auth = authenticator
+
+	api.access, err = newAccessControl(api.config, api.catalog, fakeTableResolver{
+		refs: &duckdb.TableReferences{Tables: []table.Identifier{{"hr", "salaries"}}},
+	})
+	require.NoError(t, err)
+
+	grants := api.catalog.(*sqlite.Catalog)
+	require.NoError(t, grants.SetGrant(ctx, grant.Grant{Grantee: grant.Role("analyst"), Namespace: "sales", Privilege: grant.Read}))
+	require.NoError(t, grants.SetGrant(ctx, grant.Grant{Grantee: grant.User("etl"), Namespace: "sales", Table: "orders", Privilege: grant.Write}))
+	for _, namespace := range []string{"sales", "hr"} {
+		require.NoError(t, api.catalog.CreateNamespace(ctx, table.Identifier{namespace}, iceberg.Properties{}))
+	}
+
+	app := fiber.New()
+	registerRESTRoutes(app, api)
+
+	createBody := func(name string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":   name,
+			"schema": json.RawMessage(`{"type": "struct", "fields": [{"id": 1, "name": "id", "type": "long", "required": true}]}`),
+		}
+	}
+
+	// Write on a table allows creating it, but nothing else in the namespace
+	status, body := doJSONWithKey(t, app, "etl-key", http.MethodPost, "/api/v1/namespaces/sales/tables", createBody("orders"))
+	require.Equal(t, http.StatusCreated, status, body)
+	status, body = doJSONWithKey(t, app, "etl-key", http.MethodPost, "/api/v1/namespaces/sales/tables", createBody("returns"))
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, errCodeForbidden, body["error_code"])
+
+	// Choosing where a table is written needs catalog admin
+	located := createBody("orders")
+	located["location"] = t.TempDir()
+	status, body = doJSONWithKey(t, app, "etl-key", http.MethodPost, "/api/v1/namespaces/sales/tables", located)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, errCodeForbidden, body["error_code"])
+
+	// Read on a namespace covers its tables, and listings only show what can be read
+	status, body = doJSONWithKey(t, app, "analyst-key", http.MethodGet, "/api/v1/catalog/namespaces", nil)
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Equal(t, []interface{}{[]interface{}{"sales"}}, body["namespaces"])
+
+	status, body = doJSONWithKey(t, app, "analyst-key", http.MethodGet, "/api/v1/namespaces/sales/tables/orders", nil)
+	assert.Equal(t, http.StatusOK, status, body)
+	status, body = doJSONWithKey(t, app, "analyst-key", http.MethodGet, "/api/v1/namespaces/sales/tables", nil)
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Equal(t, float64(1), body["count"])
+
+	status, body = doJSONWithKey(t, app, "analyst-key", http.MethodDelete, "/api/v1/namespaces/sales/tables/orders", nil)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, errCodeForbidden, body["error_code"])
+	status, _ = doJSONWithKey(t, app, "analyst-key", http.MethodGet, "/api/v1/admin/config", nil)
+	assert.Equal(t, http.StatusForbidden, status)
+
+	// Queries are checked against the tables they read
+	status, body = doJSONWithKey(t, app, "analyst-key", http.MethodPost, "/api/v1/query/sql", map[string]interface{}{"sql": "SELECT * FROM hr_salaries"})
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Contains(t, body["details"], "hr.salaries")
+
+	// Uploads are rejected before they are spooled when the table may not be written
+	parquetData, err := os.ReadFile(filepath.Join("..", "testdata", "titanic.parquet"))
+	require.NoError(t, err)
+	status, body = doUploadWithKey(t, app, "analyst-key", "/api/v1/import?table_name=sales.orders",
+		bytes.NewReader(parquetData), "application/octet-stream")
+	assert.Equal(t, http.StatusForbidden, status, body)
+	uploadBody, contentType := multipartBody(t, "orders.parquet", parquetData, map[string]string{"table_name": "sales.returns"})
+	status, body = doUploadWithKey(t, app, "etl-key", "/api/v1/import", uploadBody, contentType)
+	assert.Equal(t, http.StatusForbidden, status, body)
+	assert.NoDirExists(t, api.uploadDir)
+
+	// Files of the server need catalog admin even with write on the table
+	status, body = doJSONWithKey(t, app, "etl-key", http.MethodPost, "/api/v1/import/parquet", map[string]interface{}{
+		"file_path":  "/etc/passwd",
+		"table_name": "sales.orders",
+	})
+	assert.Equal(t, http.StatusForbidden, status, body)
+	assert.Equal(t, errCodeForbidden, body["error_code"])
+	assert.Empty(t, api.jobs.List())
+}
+
+func TestRESTUploadImport(t *testing.T) {
+	app, api := newTestRESTServer(t)
+
+	parquetData, err := os.ReadFile(filepath.Join("..", "testdata", "titanic.parquet"))
+	require.NoError(t, err)
+	avroData, err := os.ReadFile(filepath.Join("..", "testdata", "simple_users.avro"))
+	require.NoError(t, err)
+
+	t.Run("multipart", func(t *testing.T) {
+		body, contentType := multipartBody(t, "passengers.parquet", parquetData, map[string]string{
+			"table_name": "uploads.titanic",
+		})
+		status, resp := doUpload(t, app, "/api/v1/import", body, contentType)
+		require.Equal(t, http.StatusAccepted, status, resp)
+
+		job := waitForImportJob(t, app, resp["status_url"].(string))
+		require.Equal(t, "succeeded", job["state"], job)
+		assert.Equal(t, float64(891), job["result"].(map[string]interface{})["record_count"])
+		params := job["params"].(map[string]interface{})
+		assert.Equal(t, "upload", params["source"])
+		assert.Equal(t, "passengers.parquet", params["file_name"])
+		assert.Equal(t, "parquet", params["format"])
+	})
+
+	t.Run("raw body", func(t *testing.T) {
+		status, resp := doUpload(t, app, "/api/v1/import?table_name=users&namespace=uploads",
+			bytes.NewReader(avroData), "application/octet-stream")
+		require.Equal(t, http.StatusAccepted, status, resp)
+
+		job := waitForImportJob(t, app, resp["status_url"].(string))
+		require.Equal(t, "succeeded", job["state"], job)
+		result := job["result"].(map[string]interface{})
+		assert.Equal(t, "uploads.users", result["table_identifier"])
+		assert.Equal(t, "avro", result["file_format"])
+	})
+
+	// Spooled files are removed once their import finishes
+	entries, err := os.ReadDir(api.uploadDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	csvBody, csvType := multipartBody(t, "data.csv", []byte("id,name\n1,a\n"), map[string]string{"table_name": "t"})
+	noFileBody, noFileType := multipartBody(t, "", nil, map[string]string{"table_name": "t"})
+	noTableBody, noTableType := multipartBody(t, "data.parquet", parquetData, nil)
+
+	// Fields naming the table must come before the file they apply to
+	lateTableBody := &bytes.Buffer{}
+	w := multipart.NewWriter(lateTableBody)
+	part, err := w.CreateFormFile("file", "data.parquet")
+	require.NoError(t, err)
+	_, err = part.Write(parquetData)
+	require.NoError(t, err)
+	require.NoError(t, w.WriteField("table_name", "t"))
+	require.NoError(t, w.Close())
+
+	tests := []struct {
+		name        string
+		path        string
+		body        io.Reader
+		contentType string
+		status      int
+		errorCode   string
+	}{
+		{"unsupported format", "/api/v1/import", csvBody, csvType, http.StatusUnsupportedMediaType, errCodeUnsupportedFormat},
+		{"missing file", "/api/v1/import", noFileBody, noFileType, http.StatusBadRequest, errCodeInvalidRequest},
+		{"missing table", "/api/v1/import", noTableBody, noTableType, http.StatusBadRequest, errCodeInvalidRequest},
+		{"table after file", "/api/v1/import", lateTableBody, w.FormDataContentType(), http.StatusBadRequest, errCodeInvalidRequest},
+		{"raw body without table", "/api/v1/import", bytes.NewReader(parquetData), "application/octet-stream", http.StatusBadRequest, errCodeInvalidRequest},
+		{"empty body", "/api/v1/import?table_name=t", bytes.NewReader(nil), "application/octet-stream", http.StatusBadRequest, errCodeInvalidRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := doUpload(t, app, tt.path, tt.body, tt.contentType)
+			assert.Equal(t, tt.status, status, body)
+			assert.Equal(t, tt.errorCode, body["error_code"])
+		})
+	}
+
+	api.maxUploadSize = 1024
+	status, body := doUpload(t, app, "/api/v1/import?table_name=t", bytes.NewReader(parquetData), "application/octet-stream")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, status, body)
+	assert.Equal(t, errCodePayloadTooLarge, body["error_code"])
+
+	// Rejected uploads leave nothing behind
+	entries, err = os.ReadDir(api.uploadDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestRESTBodyLimit(t *testing.T) {
+	app := fiber.New(fiber.Config{StreamRequestBody: true, BodyLimit: 4096})
+	app.Use(limitBody(1024))
+	handler := func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"size": len(c.Body())})
+	}
+	app.Post("/api/v1/sql", handler)
+	app.Post("/api/v1/import", handler)
+
+	// send posts a body without a Content-Length, which is sent chunked
+	send := func(path string, size int) int {
+		req := httptest.NewRequest(http.MethodPost, path, io.LimitReader(strings.NewReader(strings.Repeat("x", size)), int64(size)))
+		req.ContentLength = -1
+		req.TransferEncoding = []string{"chunked"}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusOK, send("/api/v1/sql", 1000))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send("/api/v1/sql", 2000))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send("/api/v1/sql", 100*1024))
+	// Uploads are streamed to disk and limited by the upload handler instead
+	assert.Equal(t, http.StatusOK, send("/api/v1/import", 100*1024))
+}
+
+func TestRESTImportJobs(t *testing.T) {
+	app, _ := newTestRESTServer(t)
+
+	status, body := doJSON(t, app, http.MethodPost, "/api/v1/import/parquet", map[string]interface{}{
+		"file_path":  filepath.Join("..", "testdata", "titanic.parquet"),
+		"table_name": "imports.titanic",
+	})
+	require.Equal(t, http.StatusAccepted, status, body)
+	jobID, ok := body["job_id"].(string)
+	require.True(t, ok, body)
+	statusURL := "/api/v1/import/status/" + jobID
+	assert.Equal(t, statusURL, body["status_url"])
+
+	job := waitForImportJob(t, app, statusURL)
+	require.Equal(t, "succeeded", job["state"], job)
+
+	var states []interface{}
+	for _, transition := range job["transitions"].([]interface{}) {
+		states = append(states, transition.(map[string]interface{})["state"])
+	}
+	assert.Equal(t, []interface{}{"queued", "running", "succeeded"}, states)
+
+	result := job["result"].(map[string]interface{})
+	assert.Equal(t, "imports.titanic", result["table_identifier"])
+	assert.Equal(t, float64(891), result["record_count"])
+	progress := job["progress"].(map[string]interface{})
+	assert.Equal(t, float64(891), progress["rows_written"])
+	assert.Positive(t, progress["bytes_read"])
+
+	status, body = doJSON(t, app, http.MethodGet, "/api/v1/import/jobs", nil)
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Equal(t, float64(1), body["count"])
+
+	status, body = doJSON(t, app, http.MethodPost, "/api/v1/import/jobs/"+jobID+"/cancel", nil)
+	assert.Equal(t, http.StatusConflict, status, body)
+	assert.Equal(t, errCodeJobFinished, body["error_code"])
+}
+
+func TestRESTExplainAndTimeTravel(t *testing.T) {
+	app, api := newTestRESTServer(t)
+
+	engine, err := duckdb.NewEngine(api.catalog)
+	if err != nil {
+		t.Skipf("DuckDB engine unavailable: %v", err)
+	}
+	defer engine.Close()
+	api.engine = engine
+
+	status, body := doJSON(t, app, http.MethodPost, "/api/v1/query/explain", map[string]interface{}{"sql": "SELECT 42"})
+	require.Equal(t, http.StatusOK, status, body)
+	assert.NotEmpty(t, body["plan"])
+
+	ctx := context.Background()
+	require.NoError(t, api.catalog.CreateNamespace(ctx, table.Identifier{"sales"}, iceberg.Properties{}))
+	_, err = api.catalog.CreateTable(ctx, table.Identifier{"sales", "orders"},
+		iceberg.NewSchema(0, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true}))
+	require.NoError(t, err)
+
+	// A table without snapshots has nothing to travel to
+	status, body = doJSON(t, app, http.MethodPost, "/api/v1/time-travel/query", map[string]interface{}{
+		"table": "sales.orders",
+		"as_of": "2025-01-01T00:00:00Z",
+	})
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, errCodeSnapshotNotFound, body["error_code"])
+
+	// Queries at an older snapshot read the rows it had, not the current ones
+	appendTestRows(t, api.catalog, table.Identifier{"sales", "orders"}, 1, 2)
+	appendTestRows(t, api.catalog, table.Identifier{"sales", "orders"}, 3)
+	tbl, err := api.catalog.LoadTable(ctx, table.Identifier{"sales", "orders"}, nil)
+	require.NoError(t, err)
+	first := tbl.Metadata().Snapshots()[0].SnapshotID
+
+	status, body = doJSON(t, app, http.MethodPost, "/api/v1/time-travel/query", map[string]interface{}{
+		"table": "sales.orders",
+		"as_of": strconv.FormatInt(first, 10),
+	})
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Equal(t, float64(2), body["row_count"])
+	for _, query := range []string{"SELECT max(id) FROM orders", "SELECT max(o.id) FROM sales.orders o", "SELECT max(orders.id) FROM sales_orders"} {
+		status, body = doJSON(t, app, http.MethodPost, "/api/v1/time-travel/query", map[string]interface{}{
+			"table": "sales.orders",
+			"as_of": strconv.FormatInt(first, 10),
+			"sql":   query,
+		})
+		require.Equal(t, http.StatusOK, status, body)
+		assert.Equal(t, []interface{}{[]interface{}{float64(2)}}, body["rows"], query)
+	}
+
+	// The snapshot is only registered while the query runs
+	result, err := engine.ExecuteQuery(ctx, "SELECT count(*) FROM duckdb_views() WHERE view_name LIKE '%snapshot%'")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), result.Rows()[0][0])
+}
+
+func TestRESTReadOnly(t *testing.T) {
+	_, api := newTestRESTServer(t)
+	api.readOnly = true
+	app := fiber.New()
+	registerRESTRoutes(app, api)
+
+	// Reads are served, anything that changes the catalog is rejected
+	status, body := doJSON(t, app, http.MethodGet, "/api/v1/catalog/namespaces", nil)
+	assert.Equal(t, http.StatusOK, status, body)
+	for _, path := range []string{"/api/v1/catalog/namespaces", "/api/v1/namespaces/sales/tables", "/api/v1/import/parquet"} {
+		status, body = doJSON(t, app, http.MethodPost, path, map[string]interface{}{})
+		assert.Equal(t, http.StatusForbidden, status, path)
+		assert.Equal(t, errCodeReadOnly, body["error_code"], path)
+	}
+	status, body = doJSON(t, app, http.MethodDelete, "/api/v1/namespaces/sales/tables/orders", nil)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, errCodeReadOnly, body["error_code"])
+
+	engine, err := duckdb.NewEngine(api.catalog)
+	if err != nil {
+		t.Skipf("DuckDB engine unavailable: %v", err)
+	}
+	defer engine.Close()
+	api.engine = engine
+
+	status, body = doJSON(t, app, http.MethodPost, "/api/v1/query/sql", map[string]interface{}{"sql": "SELECT 42 AS answer"})
+	assert.Equal(t, http.StatusOK, status, body)
+	for _, query := range []string{"CREATE TABLE t AS SELECT 1", "SELECT 1; DROP TABLE t", "SELECT * FROM 'data.csv'"} {
+		status, body = doJSON(t, app, http.MethodPost, "/api/v1/query/sql", map[string]interface{}{"sql": query})
+		assert.Equal(t, http.StatusForbidden, status, query)
+		assert.Equal(t, errCodeReadOnly, body["error_code"], query)
+	}
+	status, body = doJSON(t, app, http.MethodPost, "/api/v1/query/explain", map[string]interface{}{"sql": "SELECT 1; DROP TABLE t"})
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, errCodeReadOnly, body["error_code"])
+}
+
+func TestRESTQueryFormats(t *testing.T) {
+	app, api := newTestRESTServer(t)
+
+	engine, err := duckdb.NewEngine(api.catalog)
+	if err != nil {
+		t.Skipf("DuckDB engine unavailable: %v", err)
+	}
+	defer engine.Close()
+	api.engine = engine
+
+	query := "SELECT i AS id, i::DECIMAL(10,2) / 4 AS amount FROM range(5000) t(i)"
+
+	// JSON responses hold the first max_rows rows and say whether there are more
+	status, body := doJSON(t, app, http.MethodPost, "/api/v1/query/sql", map[string]interface{}{"sql": query, "max_rows": 10})
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Equal(t, float64(10), body["row_count"])
+	assert.NotContains(t, body, "total_rows")
+	assert.Equal(t, true, body["truncated"])
+	assert.NotEmpty(t, body["query_id"])
+
+	// Other formats stream the whole result
+	payload, err := json.Marshal(map[string]interface{}{"sql": query, "format": "csv"})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/query/sql", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 5001)
+	assert.Equal(t, "id,amount", lines[0])
+	assert.Equal(t, "4999,1249.75", lines[5000])
+
+	status, body = doJSON(t, app, http.MethodPost, "/api/v1/query/sql", map[string]interface{}{"sql": query, "format": "xml"})
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, errCodeInvalidRequest, body["error_code"])
+}
+
+func TestServerConfigCatalogDetails(t *testing.T) {
+	app, api := newTestRESTServer(t)
+
+	status, body := doJSON(t, app, http.MethodGet, "/api/v1/admin/config", nil)
+	require.Equal(t, http.StatusOK, status, body)
+	catalogConfig := body["config"].(map[string]interface{})["catalog"].(map[string]interface{})
+	assert.Equal(t, "sqlite", catalogConfig["type"])
+	assert.Equal(t, api.config.Catalog.SQLite.Path, catalogConfig["path"])
+
+	details := catalogDetails(&config.Config{Catalog: config.CatalogConfig{
+		Type: "json",
+		JSON: &config.JSONConfig{URI: "catalog.json", Warehouse: "warehouse"},
+	}})
+	assert.Equal(t, map[string]interface{}{"type": "json", "uri": "catalog.json", "warehouse": "warehouse"}, details)
+
+	// REST catalogs report how they authenticate but never the secrets
+	details = catalogDetails(&config.Config{Catalog: config.CatalogConfig{
+		Type: "rest",
+		REST: &config.RESTConfig{
+			URI:               "https://catalog.example.com",
+			WarehouseLocation: "s3://warehouse",
+			OAuth:             &config.OAuthConfig{Credential: "client:secret"},
+		},
+	}})
+	assert.Equal(t, "https://catalog.example.com", details["uri"])
+	assert.Equal(t, "s3://warehouse", details["warehouse"])
+	assert.Equal(t, "oauth-client-credentials", details["authentication"])
+	assert.NotContains(t, fmt.Sprint(details), "client:secret")
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	app, _ := newTestRESTServer(t)
+
+	status, doc := doJSON(t, app, http.MethodGet, "/api/v1/openapi.json", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.True(t, strings.HasPrefix(doc["openapi"].(string), "3."))
+
+	// Every route must be documented
+	paths := doc["paths"].(map[string]interface{})
+	param := regexp.MustCompile(`:(\w+)`)
+	for _, route := range app.GetRoutes(true) {
+		method := strings.ToLower(route.Method)
+		if method != "get" && method != "post" && method != "delete" {
+			continue
+		}
+		path := param.ReplaceAllString(route.Path, "{$1}")
+		if path != "/" {
+			path = strings.TrimSuffix(path, "/")
+		}
+
+		item, ok := paths[path].(map[string]interface{})
+		if assert.True(t, ok, "path %s is not documented", path) {
+			assert.Contains(t, item, method, "%s %s is not documented", route.Method, path)
+		}
+	}
+}
@@ -51,6 +51,7 @@ type shellState struct {
 	buffer       []string
 	config       *config.Config
 	sessionStart time.Time
+	readOnly     bool
 }
 
 func init() {
@@ -59,6 +60,7 @@ func init() {
 	shellCmd.Flags().BoolP("timing", "t", true, "show query execution time")
 	shellCmd.Flags().Bool("metrics", false, "show engine metrics on startup")
 	shellCmd.Flags().Bool("query-log", false, "enable query logging")
+	shellCmd.Flags().Bool("readonly", false, "only allow queries that read catalog tables")
 }
 
 func runShell(cmd *cobra.Command, args []string) error {
@@ -73,6 +75,13 @@ func runShell(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Using configuration: %s\n", configPath)
 	}
 
+	// Make the embedded MinIO server reachable for projects that use it
+	stopStorage, err := startStorage(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	defer stopStorage()
+
 	// Create catalog
 	catalog, err := sqlite.NewCatalog(cfg)
 	if err != nil {
@@ -104,6 +113,7 @@ func runShell(cmd *cobra.Command, args []string) error {
 		buffer:       make([]string, 0),
 		config:       cfg,
 		sessionStart: time.Now(),
+		readOnly:     cmd.Flag("readonly").Value.String() == "true",
 	}
 
 	// Welcome message with ASCII art
@@ -111,6 +121,9 @@ func runShell(cmd *cobra.Command, args []string) error {
 	fmt.Println("🧊 Icebox SQL Shell v0.1.0")
 	fmt.Println("Enterprise-grade SQL querying for Apache Iceberg")
 	fmt.Println("Type \\help for help, \\quit to exit")
+	if state.readOnly {
+		fmt.Println("🔒 Read-only mode: only queries of catalog tables can run")
+	}
 
 	// Auto-register tables
 	if err := autoRegisterTables(cmd.Context(), engine, catalog); err != nil {
@@ -207,6 +220,21 @@ func executeShellCommand(state *shellState, query string) error {
 	// Add to history
 	state.history = append(state.history, query)
 
+	// Read-only mode rejects everything but queries of catalog tables, views included
+	if state.readOnly {
+		if err := state.engine.CheckReadOnly(context.Background(), query); err != nil {
+			return err
+		}
+	}
+
+	// CREATE VIEW persists the view in the catalog, DROP VIEW removes catalog views
+	if stmt, ok := parseViewStatement(query); ok {
+		handled, err := executeViewStatement(context.Background(), state.catalog, state.engine, stmt)
+		if handled {
+			return err
+		}
+	}
+
 	// Execute query
 	start := time.Now()
 	result, err := state.engine.ExecuteQuery(context.Background(), query)
@@ -228,14 +256,12 @@ func executeShellCommand(state *shellState, query string) error {
 	fmt.Printf("📊 %d rows returned\n", result.RowCount)
 
 	// Display results in table format (limited for shell)
-	rows := result.Rows
-	maxRows := 100 // Limit for shell display
-	if int64(len(rows)) > int64(maxRows) {
-		rows = rows[:maxRows]
+	maxRows := int64(100) // Limit for shell display
+	if result.RowCount > maxRows {
 		fmt.Printf("⚠️  Showing first %d rows\n", maxRows)
 	}
 
-	return displayTableFormat(result.Columns, rows)
+	return displayTableFormat(result.Columns, tableRows(result.Table, maxRows))
 }
 
 // handleSpecialCommand handles special shell commands starting with backslash
@@ -311,6 +337,11 @@ SQL Commands:
   SELECT * FROM table_name;     Query a table
   SHOW TABLES;                  List all available tables
   DESCRIBE table_name;          Show table schema
+  SELECT * FROM "table$files";  Query a metadata table (snapshots, history,
+                                manifests, files, partitions, refs,
+                                metadata_log_entries)
+  CREATE VIEW v AS SELECT ...;  Create a view and save it in the catalog
+  DROP VIEW v;                  Drop a view from the catalog
   
 Special Commands:
   \help, \h                     Show this help
@@ -362,7 +393,7 @@ func showTableSchema(state *shellState, tableName string) error {
 	}
 
 	fmt.Printf("📋 Schema for table '%s':\n", tableName)
-	return displayTableFormat(result.Columns, result.Rows)
+	return displayTableFormat(result.Columns, tableRows(result.Table, result.RowCount))
 }
 
 // showHistory displays command history
@@ -2,7 +2,9 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -11,6 +13,8 @@ import (
 	"github.com/TFMV/icebox/catalog/sqlite"
 	"github.com/TFMV/icebox/config"
 	"github.com/TFMV/icebox/engine/duckdb"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/spf13/cobra"
 )
 
@@ -22,11 +26,21 @@ var sqlCmd = &cobra.Command{
 The SQL engine automatically discovers and registers all tables from your
 Icebox catalog, making them available for querying.
 
+Each table also has metadata tables named "<table>$<name>", built from its
+Iceberg metadata: snapshots, history, manifests, files, partitions, refs and
+metadata_log_entries. Quote them, since '$' is not valid in bare identifiers.
+
 Examples:
   icebox sql "SELECT COUNT(*) FROM sales"
   icebox sql "SELECT region, SUM(amount) FROM sales GROUP BY region"
   icebox sql "SHOW TABLES"
-  icebox sql "DESCRIBE sales"`,
+  icebox sql "DESCRIBE sales"
+  icebox sql 'SELECT file_path, record_count FROM "sales$files"'
+  icebox sql --readonly "SELECT * FROM sales"
+  icebox sql --format parquet --output sales.parquet "SELECT * FROM sales"
+
+The csv, json, ndjson, parquet and arrow formats stream their results, so
+exports are not limited in size. --max-rows only limits them when given.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runSQL,
 }
@@ -37,6 +51,8 @@ type sqlOptions struct {
 	showSchema   bool
 	timing       bool
 	autoRegister bool
+	readonly     bool
+	output       string
 }
 
 var sqlOpts = &sqlOptions{}
@@ -44,12 +60,14 @@ var sqlOpts = &sqlOptions{}
 func init() {
 	rootCmd.AddCommand(sqlCmd)
 
-	sqlCmd.Flags().StringVar(&sqlOpts.format, "format", "table", "output format: table, csv, json")
+	sqlCmd.Flags().StringVar(&sqlOpts.format, "format", "table", "output format: table, csv, json, ndjson, parquet, arrow")
 	sqlCmd.Flags().IntVar(&sqlOpts.maxRows, "max-rows", 1000, "maximum number of rows to display")
+	sqlCmd.Flags().StringVarP(&sqlOpts.output, "output", "o", "", "write streamed results to a file instead of stdout")
 	sqlCmd.Flags().BoolVar(&sqlOpts.showSchema, "show-schema", false, "show column schema information")
 	sqlCmd.Flags().BoolVar(&sqlOpts.timing, "timing", true, "show query execution time")
 	sqlCmd.Flags().BoolVar(&sqlOpts.autoRegister, "auto-register", true, "automatically register catalog tables")
 	sqlCmd.Flags().Bool("metrics", false, "show engine performance metrics after query")
+	sqlCmd.Flags().BoolVar(&sqlOpts.readonly, "readonly", false, "only allow queries that read catalog tables")
 }
 
 func runSQL(cmd *cobra.Command, args []string) error {
@@ -66,6 +84,13 @@ func runSQL(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Using configuration: %s\n", configPath)
 	}
 
+	// Make the embedded MinIO server reachable for projects that use it
+	stopStorage, err := startStorage(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	defer stopStorage()
+
 	// Create catalog
 	catalog, err := sqlite.NewCatalog(cfg)
 	if err != nil {
@@ -95,49 +120,123 @@ func runSQL(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Execute the query
-	start := time.Now()
-	result, err := engine.ExecuteQuery(cmd.Context(), query)
-	if err != nil {
-		// Enhanced error handling with helpful suggestions
-		if strings.Contains(err.Error(), "timeout") {
-			return fmt.Errorf("❌ Query timed out: %w\n"+
-				"💡 Try simplifying your query or increasing --timeout", err)
-		}
-		if strings.Contains(err.Error(), "table") && strings.Contains(err.Error(), "not found") {
-			return fmt.Errorf("❌ Table not found: %w\n"+
-				"💡 Run 'icebox sql \"SHOW TABLES\"' to see available tables", err)
+	// Read-only mode only runs queries that read catalog tables
+	if sqlOpts.readonly {
+		if err := engine.CheckReadOnly(cmd.Context(), query); err != nil {
+			if errors.Is(err, duckdb.ErrNotReadOnly) {
+				return fmt.Errorf("❌ Query rejected in read-only mode: %w\n"+
+					"💡 Run the statement without --readonly", err)
+			}
+			return fmt.Errorf("❌ Query failed: %w", err)
 		}
-		return fmt.Errorf("❌ Query failed: %w", err)
 	}
-	duration := time.Since(start)
 
-	// Display results
-	if err := displayResults(result, duration); err != nil {
-		return fmt.Errorf("❌ Failed to display results: %w", err)
+	// Streamed formats write their results as they are read, with status messages on
+	// stderr so that they stay out of the output
+	status := os.Stdout
+	if _, ok := streamFormats[sqlOpts.format]; ok {
+		status = os.Stderr
+		var maxRows int64
+		if cmd.Flags().Changed("max-rows") {
+			maxRows = int64(sqlOpts.maxRows)
+		}
+		if err := exportResults(cmd.Context(), engine, query, maxRows); err != nil {
+			return err
+		}
+	} else {
+		// Execute the query
+		start := time.Now()
+		result, err := engine.ExecuteQuery(cmd.Context(), query)
+		if err != nil {
+			return sqlQueryError(err)
+		}
+		duration := time.Since(start)
+
+		// Display results
+		if err := displayResults(result, duration); err != nil {
+			return fmt.Errorf("❌ Failed to display results: %w", err)
+		}
 	}
 
 	// Show metrics if requested
 	showMetrics, _ := cmd.Flags().GetBool("metrics")
 	if showMetrics {
 		metrics := engine.GetMetrics()
-		fmt.Printf("\n📈 Engine Metrics:\n")
-		fmt.Printf("  Queries Executed: %d\n", metrics.QueriesExecuted)
-		fmt.Printf("  Tables Registered: %d\n", metrics.TablesRegistered)
-		fmt.Printf("  Cache Hits: %d\n", metrics.CacheHits)
-		fmt.Printf("  Cache Misses: %d\n", metrics.CacheMisses)
-		fmt.Printf("  Total Query Time: %v\n", metrics.TotalQueryTime)
-		fmt.Printf("  Error Count: %d\n", metrics.ErrorCount)
+		fmt.Fprintf(status, "\n📈 Engine Metrics:\n")
+		fmt.Fprintf(status, "  Queries Executed: %d\n", metrics.QueriesExecuted)
+		fmt.Fprintf(status, "  Tables Registered: %d\n", metrics.TablesRegistered)
+		fmt.Fprintf(status, "  Cache Hits: %d\n", metrics.CacheHits)
+		fmt.Fprintf(status, "  Cache Misses: %d\n", metrics.CacheMisses)
+		fmt.Fprintf(status, "  Total Query Time: %v\n", metrics.TotalQueryTime)
+		fmt.Fprintf(status, "  Error Count: %d\n", metrics.ErrorCount)
 		if metrics.QueriesExecuted > 0 {
 			avgTime := metrics.TotalQueryTime / time.Duration(metrics.QueriesExecuted)
-			fmt.Printf("  Average Query Time: %v\n", avgTime)
+			fmt.Fprintf(status, "  Average Query Time: %v\n", avgTime)
 		}
 	}
 
 	return nil
 }
 
-// autoRegisterTables automatically registers all catalog tables with the SQL engine
+// sqlQueryError adds suggestions to the error of a failed query
+func sqlQueryError(err error) error {
+	if strings.Contains(err.Error(), "timeout") {
+		return fmt.Errorf("❌ Query timed out: %w\n"+
+			"💡 Try simplifying your query or increasing --timeout", err)
+	}
+	if strings.Contains(err.Error(), "table") && strings.Contains(err.Error(), "not found") {
+		return fmt.Errorf("❌ Table not found: %w\n"+
+			"💡 Run 'icebox sql \"SHOW TABLES\"' to see available tables", err)
+	}
+	return fmt.Errorf("❌ Query failed: %w", err)
+}
+
+// exportResults streams the results of a query in one of the streamFormats to the
+// --output file or stdout, writing at most maxRows rows when maxRows is positive
+func exportResults(ctx context.Context, engine *duckdb.Engine, query string, maxRows int64) error {
+	start := time.Now()
+	reader, err := engine.ExecuteQueryStream(ctx, query)
+	if err != nil {
+		return sqlQueryError(err)
+	}
+	defer reader.Release()
+
+	var out io.Writer = os.Stdout
+	var file *os.File
+	if sqlOpts.output != "" {
+		file, err = os.Create(sqlOpts.output)
+		if err != nil {
+			return fmt.Errorf("❌ Failed to create output file: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	rows, truncated, err := writeResults(out, sqlOpts.format, reader, maxRows)
+	if err != nil {
+		return sqlQueryError(err)
+	}
+	if file != nil {
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("❌ Failed to write output file: %w", err)
+		}
+	}
+
+	if sqlOpts.timing {
+		fmt.Fprintf(os.Stderr, "⏱️  Query executed in %v\n", time.Since(start))
+	}
+	if sqlOpts.output != "" {
+		fmt.Fprintf(os.Stderr, "📊 %d rows written to %s\n", rows, sqlOpts.output)
+	} else {
+		fmt.Fprintf(os.Stderr, "📊 %d rows written\n", rows)
+	}
+	if truncated {
+		fmt.Fprintf(os.Stderr, "⚠️  Output limited to %d rows (use --max-rows to adjust)\n", maxRows)
+	}
+	return nil
+}
+
+// autoRegisterTables automatically registers all catalog tables and views with the SQL engine
 func autoRegisterTables(ctx context.Context, engine *duckdb.Engine, catalog catalog.CatalogInterface) error {
 	// Get all namespaces
 	namespaces, err := catalog.ListNamespaces(ctx, nil)
@@ -192,17 +291,25 @@ func autoRegisterTables(ctx context.Context, engine *duckdb.Engine, catalog cata
 		}
 	}
 
+	// Views are registered once all tables are, since they usually query them
+	viewCount, viewErrors := registerCatalogViews(ctx, engine, catalog, namespaces)
+	errors = append(errors, viewErrors...)
+
 	if len(errors) > 0 {
 		return fmt.Errorf("registration errors: %s", strings.Join(errors, "; "))
 	}
 
 	if registeredCount > 0 {
 		fmt.Printf("📋 Registered %d tables for querying\n", registeredCount)
-	} else {
+	} else if viewCount == 0 {
 		fmt.Printf("📭 No tables found to register\n")
 		fmt.Printf("💡 Try running 'icebox table list' to see what tables exist in your catalog\n")
 	}
 
+	if viewCount > 0 {
+		fmt.Printf("📋 Registered %d views for querying\n", viewCount)
+	}
+
 	return nil
 }
 
@@ -219,9 +326,9 @@ func displayResults(result *duckdb.QueryResult, duration time.Duration) error {
 		return nil
 	}
 
-	// Handle large result sets with user-friendly messaging
-	if result.RowCount >= 100000 {
-		fmt.Printf("⚠️  Large result set detected (%d rows) - performance may vary\n", result.RowCount)
+	// The engine stops collecting rows of large results
+	if result.Truncated {
+		fmt.Printf("⚠️  Result truncated at %d rows - use --format csv, json or parquet to export all rows\n", result.RowCount)
 	}
 
 	fmt.Printf("📊 %d rows returned\n", result.RowCount)
@@ -236,25 +343,51 @@ func displayResults(result *duckdb.QueryResult, duration time.Duration) error {
 	}
 
 	// Limit rows if necessary
-	rows := result.Rows
-	if int64(len(rows)) > int64(sqlOpts.maxRows) {
-		rows = rows[:sqlOpts.maxRows]
+	if result.RowCount > int64(sqlOpts.maxRows) {
 		fmt.Printf("⚠️  Showing first %d rows (use --max-rows to adjust)\n", sqlOpts.maxRows)
 	}
 
-	// Display results based on format
-	switch sqlOpts.format {
+	return displayArrowResults(result, sqlOpts.format, int64(sqlOpts.maxRows))
+}
+
+// displayArrowResults displays up to maxRows rows of a query result, formatting values
+// from their Arrow types
+func displayArrowResults(result *duckdb.QueryResult, format string, maxRows int64) error {
+	switch format {
 	case "table":
-		return displayTableFormat(result.Columns, rows)
-	case "csv":
-		return displayCSVFormat(result.Columns, rows)
-	case "json":
-		return displayJSONFormat(result.Columns, rows)
+		return displayTableFormat(result.Columns, tableRows(result.Table, maxRows))
+	case "csv", "json":
+		reader := array.NewTableReader(result.Table, -1)
+		defer reader.Release()
+		_, _, err := writeResults(os.Stdout, format, reader, maxRows)
+		return err
 	default:
-		return fmt.Errorf("unsupported format: %s", sqlOpts.format)
+		return fmt.Errorf("unsupported format: %s", format)
 	}
 }
 
+// tableRows returns up to maxRows rows of an Arrow table as display strings, with nil
+// for nulls
+func tableRows(tbl arrow.Table, maxRows int64) [][]interface{} {
+	reader := array.NewTableReader(tbl, -1)
+	defer reader.Release()
+
+	var rows [][]interface{}
+	for reader.Next() && int64(len(rows)) < maxRows {
+		record := reader.Record()
+		for i := 0; i < int(record.NumRows()) && int64(len(rows)) < maxRows; i++ {
+			row := make([]interface{}, record.NumCols())
+			for j, column := range record.Columns() {
+				if !column.IsNull(i) {
+					row[j] = duckdb.FormatArrowValue(column, i)
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
 // displayTableFormat displays results in a formatted table
 func displayTableFormat(columns []string, rows [][]interface{}) error {
 	if len(rows) == 0 {
@@ -338,47 +471,6 @@ func displayTableFormat(columns []string, rows [][]interface{}) error {
 	return nil
 }
 
-// displayCSVFormat displays results in CSV format
-func displayCSVFormat(columns []string, rows [][]interface{}) error {
-	// Print header
-	fmt.Println(strings.Join(columns, ","))
-
-	// Print rows
-	for _, row := range rows {
-		values := make([]string, len(row))
-		for i, value := range row {
-			values[i] = formatValueCSV(value)
-		}
-		fmt.Println(strings.Join(values, ","))
-	}
-
-	return nil
-}
-
-// displayJSONFormat displays results in JSON format
-func displayJSONFormat(columns []string, rows [][]interface{}) error {
-	fmt.Println("[")
-	for i, row := range rows {
-		fmt.Print("  {")
-		for j, col := range columns {
-			if j < len(row) {
-				fmt.Printf(`"%s": "%s"`, col, formatValue(row[j]))
-				if j < len(columns)-1 {
-					fmt.Print(", ")
-				}
-			}
-		}
-		fmt.Print("}")
-		if i < len(rows)-1 {
-			fmt.Print(",")
-		}
-		fmt.Println()
-	}
-	fmt.Println("]")
-
-	return nil
-}
-
 // formatValue formats a value for display
 func formatValue(value interface{}) string {
 	if value == nil {
@@ -387,24 +479,6 @@ func formatValue(value interface{}) string {
 	return fmt.Sprintf("%v", value)
 }
 
-// formatValueCSV formats a value for CSV output
-func formatValueCSV(value interface{}) string {
-	if value == nil {
-		return ""
-	}
-	str := fmt.Sprintf("%v", value)
-	// Escape newlines and other special characters for cleaner CSV output
-	str = strings.ReplaceAll(str, "\n", "\\n")
-	str = strings.ReplaceAll(str, "\r", "\\r")
-	str = strings.ReplaceAll(str, "\t", "\\t")
-	// Escape quotes and wrap in quotes if contains comma or quotes
-	if strings.Contains(str, ",") || strings.Contains(str, "\"") || strings.Contains(str, "\\") {
-		str = strings.ReplaceAll(str, "\"", "\"\"")
-		str = "\"" + str + "\""
-	}
-	return str
-}
-
 // truncateString truncates a string to the specified length
 func truncateString(str string, maxLen int) string {
 	if len(str) <= maxLen {
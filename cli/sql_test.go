@@ -151,30 +151,21 @@ func TestDisplayTableFormat(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func TestDisplayCSVFormat(t *testing.T) {
-	columns := []string{"id", "name", "amount"}
-	rows := [][]interface{}{
-		{1, "Alice", 100.50},
-		{2, "Bob", 250.00},
-		{3, "Charlie", 75.25},
-	}
+func TestDisplayArrowResults(t *testing.T) {
+	result := testQueryResult(t)
 
 	// This should not panic or error
-	err := displayCSVFormat(columns, rows)
-	assert.NoError(t, err)
-}
-
-func TestDisplayJSONFormat(t *testing.T) {
-	columns := []string{"id", "name", "amount"}
-	rows := [][]interface{}{
-		{1, "Alice", 100.50},
-		{2, "Bob", 250.00},
-		{3, "Charlie", 75.25},
+	for _, format := range []string{"table", "csv", "json"} {
+		assert.NoError(t, displayArrowResults(result, format, 4), format)
 	}
+	assert.Error(t, displayArrowResults(result, "xml", 4))
+}
 
-	// This should not panic or error
-	err := displayJSONFormat(columns, rows)
-	assert.NoError(t, err)
+func TestTableRows(t *testing.T) {
+	rows := tableRows(testQueryResult(t).Table, 4)
+	require.Len(t, rows, 4)
+	assert.Equal(t, []interface{}{"2", nil, "246913578024.68"}, rows[1])
+	assert.Equal(t, []interface{}{"4", "row, \"quoted\"", "493827156049.36"}, rows[3])
 }
 
 func TestFormatValue(t *testing.T) {
@@ -195,25 +186,6 @@ func TestFormatValue(t *testing.T) {
 	}
 }
 
-func TestFormatValueCSV(t *testing.T) {
-	tests := []struct {
-		input    interface{}
-		expected string
-	}{
-		{nil, ""},
-		{42, "42"},
-		{"hello", "hello"},
-		{"hello,world", `"hello,world"`},
-		{"say \"hello\"", `"say ""hello"""`},
-		{"line1\nline2", `"line1\nline2"`},
-	}
-
-	for _, test := range tests {
-		result := formatValueCSV(test.input)
-		assert.Equal(t, test.expected, result)
-	}
-}
-
 func TestTruncateString(t *testing.T) {
 	tests := []struct {
 		input    string
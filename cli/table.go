@@ -2,13 +2,17 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/TFMV/icebox/catalog"
 	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/display"
+	"github.com/TFMV/icebox/tableops"
 	"github.com/apache/iceberg-go"
 	"github.com/apache/iceberg-go/table"
 	"github.com/spf13/cobra"
@@ -24,12 +28,14 @@ This command provides subcommands for table operations:
 - list: List all tables in a namespace
 - describe: Show detailed information about a table
 - history: Show the snapshot history of a table
+- stats: Show file, record and column statistics of a table
 
 Examples:
   icebox table list                           # List tables in default namespace
   icebox table list --namespace analytics     # List tables in specific namespace
   icebox table describe sales                 # Describe a table
   icebox table history sales --max-snapshots 10
+  icebox table stats sales --ndv
   icebox table create test_table --schema schema.json`,
 }
 
@@ -108,14 +114,46 @@ var tableDropCmd = &cobra.Command{
 This permanently removes the table and all its metadata from the catalog.
 The table data files may be left in storage depending on the catalog implementation.
 
+Use --purge to also delete every data file, manifest, manifest list and
+metadata file reachable from any snapshot of the table. Purging asks for
+confirmation unless --force is given.
+
 Examples:
   icebox table drop sales
   icebox table drop analytics.user_events
-  icebox table drop warehouse.inventory.products`,
+  icebox table drop warehouse.inventory.products
+  icebox table drop sales --purge
+  icebox table drop sales --purge --force`,
 	Args: cobra.ExactArgs(1),
 	RunE: runTableDrop,
 }
 
+var tableStatsCmd = &cobra.Command{
+	Use:   "stats <table>",
+	Short: "Show table and column statistics",
+	Long: `Show statistics of a table's current snapshot, read from its manifests:
+- Data file count, total size and record count
+- Per-column value counts, null counts and min/max bounds
+- Per-column distinct value estimates
+
+Distinct value estimates are read from the table's Puffin statistics file
+when one was written for the current snapshot. Use --ndv to compute them by
+scanning the table with theta sketches, and --write-puffin to store the
+sketches in a Puffin file so later runs can reuse them.
+
+With --format csv or json, the column statistics are printed. Add --summary
+to print the table-level statistics instead.
+
+Examples:
+  icebox table stats sales
+  icebox table stats sales --ndv
+  icebox table stats sales --write-puffin
+  icebox table stats sales --format json
+  icebox table stats sales --format csv --summary`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTableStats,
+}
+
 type tableListOptions struct {
 	namespace      string
 	allNamespaces  bool
@@ -145,8 +183,16 @@ type tableCreateOptions struct {
 	location    string
 }
 
+type tableStatsOptions struct {
+	format      string
+	summary     bool
+	ndv         bool
+	writePuffin bool
+}
+
 type tableDropOptions struct {
 	force bool
+	purge bool
 }
 
 var (
@@ -155,6 +201,7 @@ var (
 	tableHistoryOpts  = &tableHistoryOptions{}
 	tableCreateOpts   = &tableCreateOptions{}
 	tableDropOpts     = &tableDropOptions{}
+	tableStatsOpts    = &tableStatsOptions{}
 )
 
 func init() {
@@ -166,6 +213,7 @@ func init() {
 	tableCmd.AddCommand(tableHistoryCmd)
 	tableCmd.AddCommand(tableCreateCmd)
 	tableCmd.AddCommand(tableDropCmd)
+	tableCmd.AddCommand(tableStatsCmd)
 
 	// Table list flags
 	tableListCmd.Flags().StringVar(&tableListOpts.namespace, "namespace", "default", "namespace to list tables from")
@@ -194,6 +242,13 @@ func init() {
 
 	// Table drop flags
 	tableDropCmd.Flags().BoolVar(&tableDropOpts.force, "force", false, "force drop table")
+	tableDropCmd.Flags().BoolVar(&tableDropOpts.purge, "purge", false, "delete the table's data and metadata files")
+
+	// Table stats flags
+	tableStatsCmd.Flags().StringVar(&tableStatsOpts.format, "format", "table", "output format: table, csv, json")
+	tableStatsCmd.Flags().BoolVar(&tableStatsOpts.summary, "summary", false, "print table-level statistics only (csv and json formats)")
+	tableStatsCmd.Flags().BoolVar(&tableStatsOpts.ndv, "ndv", false, "scan the table to estimate distinct values per column")
+	tableStatsCmd.Flags().BoolVar(&tableStatsOpts.writePuffin, "write-puffin", false, "store distinct value sketches in a Puffin statistics file (implies --ndv)")
 }
 
 func runTableList(cmd *cobra.Command, args []string) error {
@@ -260,6 +315,13 @@ func runTableDescribe(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("❌ Failed to find Icebox configuration: %w", err)
 	}
 
+	// Make the embedded MinIO server reachable for projects that use it
+	stopStorage, err := startStorage(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	defer stopStorage()
+
 	// Create catalog
 	cat, err := catalog.NewCatalog(cfg)
 	if err != nil {
@@ -285,6 +347,17 @@ func runTableDescribe(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("❌ Failed to display table description: %w", err)
 	}
 
+	if tableDescribeOpts.showStats {
+		stats, err := tableops.CollectTableStats(cmd.Context(), icebergTable, nil)
+		if err != nil {
+			return fmt.Errorf("❌ Failed to collect table statistics: %w", err)
+		}
+		fmt.Println()
+		if err := displayTableStats(cmd.Context(), stats, display.FormatTable, false); err != nil {
+			return fmt.Errorf("❌ Failed to display table statistics: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -297,6 +370,13 @@ func runTableHistory(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("❌ Failed to find Icebox configuration: %w", err)
 	}
 
+	// Make the embedded MinIO server reachable for projects that use it
+	stopStorage, err := startStorage(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	defer stopStorage()
+
 	// Create catalog
 	cat, err := catalog.NewCatalog(cfg)
 	if err != nil {
@@ -324,6 +404,67 @@ func runTableHistory(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runTableStats(cmd *cobra.Command, args []string) error {
+	tableName := args[0]
+
+	format, err := parseStatsFormat(tableStatsOpts.format)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	// Find the Icebox configuration
+	_, cfg, err := config.FindConfig()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to find Icebox configuration: %w", err)
+	}
+
+	// Make the embedded MinIO server reachable for projects that use it
+	stopStorage, err := startStorage(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	defer stopStorage()
+
+	// Create catalog
+	cat, err := catalog.NewCatalog(cfg)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to create catalog: %w", err)
+	}
+	defer cat.Close()
+
+	// Parse table identifier
+	tableIdent, _, err := parseTableIdentifier(tableName, "")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to parse table identifier: %w", err)
+	}
+
+	// Load the table
+	icebergTable, err := cat.LoadTable(cmd.Context(), tableIdent, nil)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load table '%s': %w\n"+
+			"💡 Use 'icebox table list' to see available tables", tableName, err)
+	}
+
+	statsOpts := &tableops.StatsOptions{
+		ComputeDistinct: tableStatsOpts.ndv || tableStatsOpts.writePuffin,
+	}
+	stats, err := tableops.CollectTableStats(cmd.Context(), icebergTable, statsOpts)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to collect table statistics: %w", err)
+	}
+
+	if tableStatsOpts.writePuffin {
+		if icebergTable.CurrentSnapshot() == nil {
+			return fmt.Errorf("❌ Table '%s' has no snapshots to compute statistics for", tableName)
+		}
+		if _, err := tableops.NewWriter(cat).WriteStatisticsFile(cmd.Context(), icebergTable, stats); err != nil {
+			return fmt.Errorf("❌ Failed to write statistics file: %w", err)
+		}
+	}
+
+	return displayTableStats(cmd.Context(), stats, format, tableStatsOpts.summary)
+}
+
 func runTableCreate(cmd *cobra.Command, args []string) error {
 	tableName := args[0]
 
@@ -333,6 +474,13 @@ func runTableCreate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("❌ Failed to find Icebox configuration: %w", err)
 	}
 
+	// Make the embedded MinIO server reachable for projects that use it
+	stopStorage, err := startStorage(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	defer stopStorage()
+
 	// Create catalog
 	cat, err := catalog.NewCatalog(cfg)
 	if err != nil {
@@ -431,6 +579,13 @@ func runTableDrop(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("❌ Failed to find Icebox configuration: %w", err)
 	}
 
+	// Make the embedded MinIO server reachable for projects that use it
+	stopStorage, err := startStorage(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	defer stopStorage()
+
 	// Create catalog
 	cat, err := catalog.NewCatalog(cfg)
 	if err != nil {
@@ -444,12 +599,62 @@ func runTableDrop(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("❌ Failed to parse table identifier: %w", err)
 	}
 
+	if tableDropOpts.purge {
+		return purgeTable(cmd, cat, tableIdent, tableName)
+	}
+
+	// Drop the table
+	if err := cat.DropTable(cmd.Context(), tableIdent); err != nil {
+		return fmt.Errorf("❌ Failed to drop table: %w", err)
+	}
+
+	fmt.Printf("✅ Successfully dropped table!\n")
+	return nil
+}
+
+// purgeTable drops a table and deletes all files reachable from its metadata
+func purgeTable(cmd *cobra.Command, cat catalog.CatalogInterface, tableIdent table.Identifier, tableName string) error {
+	// Files have to be collected before the drop, while the metadata is still reachable
+	tbl, err := cat.LoadTable(cmd.Context(), tableIdent, nil)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load table: %w", err)
+	}
+
+	files, err := tableops.CollectTableFiles(tbl)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to collect table files: %w", err)
+	}
+
+	fmt.Printf("🗑️  Purging table %s will delete %d files (%s):\n", tableName, files.Count(), formatBytes(files.TotalSize()))
+	fmt.Printf("   Data files: %d\n", len(files.DataFiles))
+	fmt.Printf("   Manifests: %d\n", len(files.ManifestFiles))
+	fmt.Printf("   Manifest lists: %d\n", len(files.ManifestLists))
+	fmt.Printf("   Metadata files: %d\n", len(files.MetadataFiles))
+
+	if !tableDropOpts.force {
+		if !display.GetDisplayOrDefault(cmd.Context()).Confirm("Permanently delete these files?") {
+			return fmt.Errorf("❌ Purge of table %s cancelled\n"+
+				"💡 Use --force to purge without confirmation", tableName)
+		}
+	}
+
 	// Drop the table
 	if err := cat.DropTable(cmd.Context(), tableIdent); err != nil {
 		return fmt.Errorf("❌ Failed to drop table: %w", err)
 	}
 
+	result := tableops.DeleteTableFiles(tbl.FS(), files)
+
 	fmt.Printf("✅ Successfully dropped table!\n")
+	fmt.Printf("   Files deleted: %d\n", result.DeletedFiles)
+	fmt.Printf("   Bytes reclaimed: %s\n", formatBytes(result.ReclaimedBytes))
+
+	if len(result.Failed) > 0 {
+		fmt.Printf("⚠️  Failed to delete %d files:\n", len(result.Failed))
+		for path, err := range result.Failed {
+			fmt.Printf("   %s: %v\n", path, err)
+		}
+	}
 	return nil
 }
 
@@ -628,6 +833,89 @@ func displayTableDescription(tbl *table.Table, opts *tableDescribeOptions) error
 	return nil
 }
 
+func parseStatsFormat(format string) (display.OutputFormat, error) {
+	switch format {
+	case "table":
+		return display.FormatTable, nil
+	case "csv":
+		return display.FormatCSV, nil
+	case "json":
+		return display.FormatJSON, nil
+	default:
+		return display.FormatTable, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+func displayTableStats(ctx context.Context, stats *tableops.TableStats, format display.OutputFormat, summaryOnly bool) error {
+	d := display.GetDisplayOrDefault(ctx)
+
+	summary := display.TableData{
+		Headers: []string{"Metric", "Value"},
+		Rows: [][]interface{}{
+			{"snapshot_id", stats.SnapshotID},
+			{"data_files", stats.DataFiles},
+			{"delete_files", stats.DeleteFiles},
+			{"total_size_bytes", stats.TotalSize},
+			{"record_count", stats.RecordCount},
+		},
+	}
+	if stats.StatisticsFile != "" {
+		summary.Rows = append(summary.Rows, []interface{}{"statistics_file", stats.StatisticsFile})
+	}
+
+	columns := display.TableData{
+		Headers: []string{"column", "type", "values", "nulls", "nans", "min", "max", "distinct"},
+	}
+	for _, column := range stats.Columns {
+		columns.Rows = append(columns.Rows, []interface{}{
+			column.Name,
+			column.Type,
+			column.ValueCount,
+			column.NullCount,
+			column.NaNCount,
+			literalString(column.LowerBound),
+			literalString(column.UpperBound),
+			distinctString(column.DistinctCount),
+		})
+	}
+
+	if format != display.FormatTable {
+		if summaryOnly {
+			return d.Table(summary).WithFormat(format).Render()
+		}
+		return d.Table(columns).WithFormat(format).Render()
+	}
+
+	if stats.SnapshotID == 0 {
+		fmt.Println("📭 Table has no snapshots yet")
+		return nil
+	}
+
+	// Show sizes in a human readable form in the table view
+	summary.Rows[3][1] = formatBytes(stats.TotalSize)
+	if err := d.Table(summary).WithTitle("📊 Table Statistics").WithCompactMode().Render(); err != nil {
+		return err
+	}
+	if len(columns.Rows) == 0 {
+		return nil
+	}
+	return d.Table(columns).WithTitle("📋 Column Statistics").WithCompactMode().Render()
+}
+
+func literalString(lit iceberg.Literal) string {
+	if lit == nil {
+		return ""
+	}
+	return lit.String()
+}
+
+func distinctString(count *int64) string {
+	if count == nil {
+		return ""
+	}
+	return strconv.FormatInt(*count, 10)
+}
+
 func displayTableHistoryDetailed(tbl *table.Table, opts *tableHistoryOptions) error {
 	snapshots := tbl.Metadata().Snapshots()
 	if len(snapshots) == 0 {
@@ -783,10 +1071,17 @@ func readSchemaFromFile(filename string) (*iceberg.Schema, error) {
 	return parseSchemaFromJSON(string(data))
 }
 
+// parseSchemaFromJSON parses a schema in the Iceberg JSON format, as in
+// {"type": "struct", "fields": [{"id": 1, "name": "id", "type": "long", "required": true}]}
 func parseSchemaFromJSON(schemaJSON string) (*iceberg.Schema, error) {
-	// TODO: Implement JSON schema parsing
-	// For now, return a simple default schema
-	return createDefaultSchema(), nil
+	var schema iceberg.Schema
+	if err := json.Unmarshal([]byte(schemaJSON), &schema); err != nil {
+		return nil, fmt.Errorf("invalid schema JSON: %w", err)
+	}
+	if len(schema.Fields()) == 0 {
+		return nil, fmt.Errorf("schema must have at least one field")
+	}
+	return &schema, nil
 }
 
 func createDefaultSchema() *iceberg.Schema {
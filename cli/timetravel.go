@@ -94,6 +94,13 @@ func runTimeTravel(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Using configuration: %s\n", configPath)
 	}
 
+	// Make the embedded MinIO server reachable for projects that use it
+	stopStorage, err := startStorage(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	defer stopStorage()
+
 	// Create catalog using factory pattern
 	cat, err := catalog.NewCatalog(cfg)
 	if err != nil {
@@ -148,35 +155,17 @@ func runTimeTravel(cmd *cobra.Command, args []string) error {
 	}
 	defer engine.Close()
 
-	// Create a new table with the specific snapshot for querying
-	snapshotTable, err := createSnapshotTable(icebergTable, snapshotID)
-	if err != nil {
-		return fmt.Errorf("❌ Failed to create snapshot table: %w", err)
-	}
-
-	// Register the snapshot table with a unique name
-	snapshotTableIdent := append(tableIdent, fmt.Sprintf("snapshot_%d", snapshotID))
-	if err := engine.RegisterTable(cmd.Context(), snapshotTableIdent, snapshotTable); err != nil {
-		return fmt.Errorf("❌ Failed to register table at snapshot: %w", err)
-	}
-
 	// Determine the query to execute
 	query := timeTravelOpts.query
 	if query == "" {
 		// Default query - show a sample of data
-		tablePath := strings.Join(tableIdent, ".")
-		query = fmt.Sprintf("SELECT * FROM %s LIMIT 10", tablePath)
+		query = defaultTimeTravelQuery(tableIdent)
 		fmt.Printf("🔍 Default query: %s\n", query)
 	}
 
-	// Replace original table name with snapshot table name in query
-	originalTablePath := strings.Join(tableIdent, ".")
-	snapshotTablePath := strings.Join(snapshotTableIdent, ".")
-	query = strings.ReplaceAll(query, originalTablePath, snapshotTablePath)
-
 	// Execute the query
 	start := time.Now()
-	result, err := engine.ExecuteQuery(cmd.Context(), query)
+	result, err := engine.ExecuteQueryAtSnapshot(cmd.Context(), tableIdent, icebergTable, snapshotID, query)
 	if err != nil {
 		// Enhanced error handling
 		if strings.Contains(err.Error(), "timeout") {
@@ -199,17 +188,10 @@ func runTimeTravel(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// createSnapshotTable creates a new table instance pointing to a specific snapshot
-func createSnapshotTable(originalTable *table.Table, snapshotID int64) (*table.Table, error) {
-	// Find the specific snapshot
-	snapshot := originalTable.SnapshotByID(snapshotID)
-	if snapshot == nil {
-		return nil, fmt.Errorf("snapshot with ID %d not found", snapshotID)
-	}
-
-	// For now, return the original table (Iceberg-go will handle the snapshot internally)
-	// In a full implementation, we would create a new table instance with the specific snapshot
-	return originalTable, nil
+// defaultTimeTravelQuery returns the query run when time-traveling without one, which
+// shows a sample of the table's rows
+func defaultTimeTravelQuery(tableIdent table.Identifier) string {
+	return fmt.Sprintf("SELECT * FROM %s LIMIT 10", strings.Join(tableIdent, "."))
 }
 
 // resolveSnapshot resolves an as-of parameter to a snapshot ID and timestamp
@@ -353,21 +335,10 @@ func displayTimeTravelResults(result *duckdb.QueryResult, duration time.Duration
 	}
 
 	// Limit rows if necessary
-	rows := result.Rows
-	if int64(len(rows)) > int64(timeTravelOpts.maxRows) {
-		rows = rows[:timeTravelOpts.maxRows]
+	if result.RowCount > int64(timeTravelOpts.maxRows) {
 		fmt.Printf("⚠️  Showing first %d rows (use --max-rows to adjust)\n", timeTravelOpts.maxRows)
 	}
 
 	// Display results based on format (reuse the display functions from sql.go)
-	switch timeTravelOpts.format {
-	case "table":
-		return displayTableFormat(result.Columns, rows)
-	case "csv":
-		return displayCSVFormat(result.Columns, rows)
-	case "json":
-		return displayJSONFormat(result.Columns, rows)
-	default:
-		return fmt.Errorf("unsupported format: %s", timeTravelOpts.format)
-	}
+	return displayArrowResults(result, timeTravelOpts.format, int64(timeTravelOpts.maxRows))
 }
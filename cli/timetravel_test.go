@@ -156,51 +156,6 @@ func TestResolveSnapshot(t *testing.T) {
 	}
 }
 
-func TestCreateSnapshotTable(t *testing.T) {
-	mockTable := createMockTableWithSnapshots(t)
-
-	tests := []struct {
-		name       string
-		snapshotID int64
-		wantError  bool
-	}{
-		{
-			name:       "Valid snapshot ID",
-			snapshotID: 1000,
-		},
-		{
-			name:       "Another valid snapshot ID",
-			snapshotID: 2000,
-		},
-		{
-			name:       "Invalid snapshot ID",
-			snapshotID: 9999,
-			wantError:  true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, err := createSnapshotTable(mockTable, tt.snapshotID)
-			if tt.wantError {
-				if err == nil {
-					t.Errorf("createSnapshotTable() expected error, got nil")
-				}
-				return
-			}
-
-			if err != nil {
-				t.Errorf("createSnapshotTable() unexpected error: %v", err)
-				return
-			}
-
-			if got == nil {
-				t.Errorf("createSnapshotTable() returned nil table")
-			}
-		})
-	}
-}
-
 func TestShowTableHistory(t *testing.T) {
 	// Test with table that has snapshots
 	mockTable := createMockTableWithSnapshots(t)
@@ -5,16 +5,19 @@ import (
 	"fmt"
 	"html/template"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/TFMV/icebox/auth"
 	"github.com/TFMV/icebox/catalog"
-	"github.com/TFMV/icebox/catalog/sqlite"
 	"github.com/TFMV/icebox/config"
 	"github.com/TFMV/icebox/engine/duckdb"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/iceberg-go/table"
 	"github.com/spf13/cobra"
 )
 
@@ -55,13 +58,16 @@ type uiOptions struct {
 
 var uiOpts = &uiOptions{}
 
+// uiResultRows is the number of rows of a query result sent to the query editor
+const uiResultRows = 1000
+
 func init() {
 	rootCmd.AddCommand(uiCmd)
 
 	uiCmd.Flags().IntVar(&uiOpts.port, "port", 9090, "UI server port")
 	uiCmd.Flags().StringVar(&uiOpts.host, "host", "localhost", "UI server host")
-	uiCmd.Flags().BoolVar(&uiOpts.readonly, "readonly", false, "enable read-only mode (no DDL/DML)")
-	uiCmd.Flags().BoolVar(&uiOpts.auth, "auth", false, "enable authentication")
+	uiCmd.Flags().BoolVar(&uiOpts.readonly, "readonly", false, "only allow queries that read catalog tables")
+	uiCmd.Flags().BoolVar(&uiOpts.auth, "auth", false, "require credentials configured in the auth section of .icebox.yml")
 	uiCmd.Flags().BoolVar(&uiOpts.autoOpen, "open", true, "automatically open browser")
 	uiCmd.Flags().StringVar(&uiOpts.certFile, "cert", "", "TLS certificate file")
 	uiCmd.Flags().StringVar(&uiOpts.keyFile, "key", "", "TLS private key file")
@@ -80,25 +86,11 @@ func runUI(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Using configuration: %s\n", configPath)
 	}
 
-	// Create catalog
-	cat, err := catalog.NewCatalog(cfg)
+	cat, engine, closeServing, err := openServing(cmd.Context(), cfg)
 	if err != nil {
-		return fmt.Errorf("❌ Failed to create catalog: %w", err)
+		return err
 	}
-	defer cat.Close()
-
-	// Create SQL engine
-	var engine *duckdb.Engine
-	switch catalogImpl := cat.(type) {
-	case *sqlite.Catalog:
-		engine, err = duckdb.NewEngine(catalogImpl)
-		if err != nil {
-			return fmt.Errorf("❌ Failed to create SQL engine: %w", err)
-		}
-	default:
-		return fmt.Errorf("❌ UI currently only supports SQLite catalogs")
-	}
-	defer engine.Close()
+	defer closeServing()
 
 	fmt.Printf("🎨 Starting Icebox Web UI\n")
 	fmt.Printf("   Address: %s://%s:%d\n", getUIScheme(), uiOpts.host, uiOpts.port)
@@ -114,21 +106,33 @@ func runUI(cmd *cobra.Command, args []string) error {
 		fmt.Printf("   Features: %v\n", features)
 	}
 
+	var authenticator *auth.Authenticator
+	var access *accessControl
+	if uiOpts.auth {
+		authenticator, err = loadAuthenticator(cfg)
+		if err != nil {
+			return err
+		}
+		defer authenticator.Close()
+		if access, err = newAccessControl(cfg, cat, engine); err != nil {
+			return err
+		}
+	}
+
 	// Create web UI handler
 	ui := &WebUIHandler{
 		catalog: cat,
 		engine:  engine,
 		config:  cfg,
 		options: *uiOpts,
+		auth:    authenticator,
+		access:  access,
 	}
 
 	// Create HTTP server
-	mux := http.NewServeMux()
-	ui.registerRoutes(mux)
-
 	server := &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", uiOpts.host, uiOpts.port),
-		Handler:      mux,
+		Handler:      ui.handler(),
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -181,6 +185,27 @@ type WebUIHandler struct {
 	engine  *duckdb.Engine
 	config  *config.Config
 	options uiOptions
+	auth    *auth.Authenticator // nil when authentication is disabled
+	access  *accessControl      // nil when authentication is disabled
+}
+
+// handler returns the UI's routes, which require credentials when authentication is
+// enabled. The browser asks for them through the WWW-Authenticate challenge.
+func (ui *WebUIHandler) handler() http.Handler {
+	mux := http.NewServeMux()
+	ui.registerRoutes(mux)
+	if ui.auth == nil {
+		return mux
+	}
+
+	protected := ui.auth.HTTP("ui", mux)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+		protected.ServeHTTP(w, r)
+	})
 }
 
 func (ui *WebUIHandler) registerRoutes(mux *http.ServeMux) {
@@ -203,26 +228,17 @@ func (ui *WebUIHandler) registerRoutes(mux *http.ServeMux) {
 }
 
 func (ui *WebUIHandler) handleDashboard(w http.ResponseWriter, r *http.Request) {
-	if ui.options.auth && !ui.isAuthenticated(r) {
-		ui.redirectToLogin(w, r)
-		return
-	}
-
 	// Render dashboard template
 	ui.renderTemplate(w, "dashboard.html", map[string]interface{}{
 		"Title":     "Icebox Dashboard",
 		"Config":    ui.config.Name,
+		"Catalog":   catalogDetails(ui.config),
 		"ReadOnly":  ui.options.readonly,
 		"Timestamp": time.Now().Format(time.RFC3339),
 	})
 }
 
 func (ui *WebUIHandler) handleQueryEditor(w http.ResponseWriter, r *http.Request) {
-	if ui.options.auth && !ui.isAuthenticated(r) {
-		ui.redirectToLogin(w, r)
-		return
-	}
-
 	ui.renderTemplate(w, "query.html", map[string]interface{}{
 		"Title":    "SQL Query Editor",
 		"ReadOnly": ui.options.readonly,
@@ -230,33 +246,18 @@ func (ui *WebUIHandler) handleQueryEditor(w http.ResponseWriter, r *http.Request
 }
 
 func (ui *WebUIHandler) handleTables(w http.ResponseWriter, r *http.Request) {
-	if ui.options.auth && !ui.isAuthenticated(r) {
-		ui.redirectToLogin(w, r)
-		return
-	}
-
 	ui.renderTemplate(w, "tables.html", map[string]interface{}{
 		"Title": "Tables & Schemas",
 	})
 }
 
 func (ui *WebUIHandler) handleNamespaces(w http.ResponseWriter, r *http.Request) {
-	if ui.options.auth && !ui.isAuthenticated(r) {
-		ui.redirectToLogin(w, r)
-		return
-	}
-
 	ui.renderTemplate(w, "namespaces.html", map[string]interface{}{
 		"Title": "Namespaces",
 	})
 }
 
 func (ui *WebUIHandler) handleMetrics(w http.ResponseWriter, r *http.Request) {
-	if ui.options.auth && !ui.isAuthenticated(r) {
-		ui.redirectToLogin(w, r)
-		return
-	}
-
 	ui.renderTemplate(w, "metrics.html", map[string]interface{}{
 		"Title": "System Metrics",
 	})
@@ -268,8 +269,10 @@ func (ui *WebUIHandler) handleExecuteQuery(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	if ui.options.auth && !ui.isAuthenticated(r) {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	// Browsers send the credentials of the UI with form posts from any site, so only
+	// the UI's own pages may run queries
+	if !sameOrigin(r) {
+		http.Error(w, "Cross-origin requests are not allowed", http.StatusForbidden)
 		return
 	}
 
@@ -285,19 +288,54 @@ func (ui *WebUIHandler) handleExecuteQuery(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// Execute query
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
 	// Check read-only mode
-	if ui.options.readonly && ui.isWriteOperation(query) {
+	if ui.options.readonly {
+		if err := ui.engine.CheckReadOnly(ctx, query); err != nil {
+			ui.writeJSON(w, map[string]interface{}{
+				"error": err.Error(),
+			})
+			return
+		}
+	}
+
+	if err := ui.access.authorizeQuery(ctx, query); err != nil {
 		ui.writeJSON(w, map[string]interface{}{
-			"error": "Write operations are disabled in read-only mode",
+			"error": err.Error(),
 		})
 		return
 	}
 
-	// Execute query
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-	defer cancel()
+	start := time.Now()
+	reader, err := ui.engine.ExecuteQueryStream(ctx, query)
+	if err != nil {
+		ui.writeJSON(w, map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	defer reader.Release()
 
-	result, err := ui.engine.ExecuteQuery(ctx, query)
+	// Downloads stream the whole result in the requested format
+	if format := r.Form.Get("format"); format != "" && format != "json" {
+		contentType, ok := streamFormats[format]
+		if !ok {
+			http.Error(w, "Unsupported format: "+format, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="results.%s"`, format))
+		if _, _, err := writeResults(w, format, reader, 0); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Failed to stream query results: %v\n", err)
+		}
+		return
+	}
+
+	// The page shows the first rows of the result
+	columns, rows, more, err := collectRows(reader, uiResultRows)
 	if err != nil {
 		ui.writeJSON(w, map[string]interface{}{
 			"error": err.Error(),
@@ -307,20 +345,36 @@ func (ui *WebUIHandler) handleExecuteQuery(w http.ResponseWriter, r *http.Reques
 
 	// Return results
 	ui.writeJSON(w, map[string]interface{}{
-		"query_id":    result.QueryID,
-		"columns":     result.Columns,
-		"rows":        result.Rows,
-		"row_count":   result.RowCount,
-		"duration_ms": result.Duration.Milliseconds(),
+		"query_id":    reader.QueryID(),
+		"columns":     columns,
+		"rows":        rows,
+		"row_count":   len(rows),
+		"truncated":   more,
+		"duration_ms": time.Since(start).Milliseconds(),
 		"success":     true,
 	})
 }
 
+// sameOrigin reports whether a request was sent by a page of the UI, going by the
+// Sec-Fetch-Site or Origin header browsers add to POST requests. Requests with neither
+// header did not come from a browser, so they carry no credentials a page could borrow.
+func sameOrigin(r *http.Request) bool {
+	if site := r.Header.Get("Sec-Fetch-Site"); site != "" {
+		return site == "same-origin" || site == "none"
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
 func (ui *WebUIHandler) handleAPINamespaces(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
-	namespaces, err := ui.catalog.ListNamespaces(ctx, nil)
+	all, err := ui.catalog.ListNamespaces(ctx, nil)
 	if err != nil {
 		ui.writeJSON(w, map[string]interface{}{
 			"error": err.Error(),
@@ -328,6 +382,20 @@ func (ui *WebUIHandler) handleAPINamespaces(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	namespaces := []table.Identifier{}
+	for _, namespace := range all {
+		ok, err := ui.access.canAccess(ctx, namespace)
+		if err != nil {
+			ui.writeJSON(w, map[string]interface{}{
+				"error": err.Error(),
+			})
+			return
+		}
+		if ok {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+
 	ui.writeJSON(w, map[string]interface{}{
 		"namespaces": namespaces,
 		"count":      len(namespaces),
@@ -335,10 +403,10 @@ func (ui *WebUIHandler) handleAPINamespaces(w http.ResponseWriter, r *http.Reque
 }
 
 func (ui *WebUIHandler) handleAPITables(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
-	tables, err := ui.engine.ListTables(ctx)
+	registered, err := ui.engine.ListTables(ctx)
 	if err != nil {
 		ui.writeJSON(w, map[string]interface{}{
 			"error": err.Error(),
@@ -346,6 +414,20 @@ func (ui *WebUIHandler) handleAPITables(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	tables := []string{}
+	for _, name := range registered {
+		ok, err := ui.access.canQuery(ctx, name)
+		if err != nil {
+			ui.writeJSON(w, map[string]interface{}{
+				"error": err.Error(),
+			})
+			return
+		}
+		if ok {
+			tables = append(tables, name)
+		}
+	}
+
 	ui.writeJSON(w, map[string]interface{}{
 		"tables": tables,
 		"count":  len(tables),
@@ -361,9 +443,16 @@ func (ui *WebUIHandler) handleAPITableInfo(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
+	if err := ui.access.authorizeName(ctx, tableName); err != nil {
+		ui.writeJSON(w, map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
 	result, err := ui.engine.DescribeTable(ctx, tableName)
 	if err != nil {
 		ui.writeJSON(w, map[string]interface{}{
@@ -372,9 +461,19 @@ func (ui *WebUIHandler) handleAPITableInfo(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	reader := array.NewTableReader(result.Table, -1)
+	defer reader.Release()
+	_, rows, _, err := collectRows(reader, int(result.RowCount))
+	if err != nil {
+		ui.writeJSON(w, map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
 	ui.writeJSON(w, map[string]interface{}{
 		"columns": result.Columns,
-		"rows":    result.Rows,
+		"rows":    rows,
 		"count":   result.RowCount,
 	})
 }
@@ -404,43 +503,6 @@ func (ui *WebUIHandler) handleHealth(w http.ResponseWriter, r *http.Request) {
 }
 
 // Helper methods
-func (ui *WebUIHandler) isAuthenticated(r *http.Request) bool {
-	// Simple authentication check - in production this would be more sophisticated
-	if !ui.options.auth {
-		return true
-	}
-
-	// Check for session cookie or auth header
-	if cookie, err := r.Cookie("auth"); err == nil && cookie.Value != "" {
-		return true
-	}
-
-	if auth := r.Header.Get("Authorization"); auth != "" {
-		return true
-	}
-
-	return false
-}
-
-func (ui *WebUIHandler) redirectToLogin(w http.ResponseWriter, r *http.Request) {
-	http.Redirect(w, r, "/login", http.StatusFound)
-}
-
-func (ui *WebUIHandler) isWriteOperation(query string) bool {
-	// Simple check for write operations
-	query = strings.ToUpper(strings.TrimSpace(query))
-	writeKeywords := []string{
-		"INSERT", "UPDATE", "DELETE", "DROP", "CREATE", "ALTER", "TRUNCATE",
-	}
-
-	for _, keyword := range writeKeywords {
-		if strings.HasPrefix(query, keyword) {
-			return true
-		}
-	}
-	return false
-}
-
 func (ui *WebUIHandler) renderTemplate(w http.ResponseWriter, templateName string, data interface{}) {
 	// Create a simple HTML template if embedded files aren't available
 	tmpl := `<!DOCTYPE html>
@@ -479,6 +541,13 @@ func (ui *WebUIHandler) renderTemplate(w http.ResponseWriter, templateName strin
         <div class="section">
             <h2>Welcome to Icebox</h2>
             <p>Your Apache Iceberg data lakehouse is ready! Start exploring your data with the tools below.</p>
+
+            <div style="border: 1px solid #ddd; padding: 20px; border-radius: 8px;">
+                <h3>🗄️ Catalog</h3>
+                <table>
+                    {{range $key, $value := .Catalog}}<tr><td style="padding-right: 20px; color: #666;">{{$key}}</td><td>{{$value}}</td></tr>{{end}}
+                </table>
+            </div>
             
             <div style="display: grid; grid-template-columns: repeat(auto-fit, minmax(250px, 1fr)); gap: 20px; margin: 20px 0;">
                 <div style="border: 1px solid #ddd; padding: 20px; border-radius: 8px;">
@@ -518,6 +587,7 @@ func (ui *WebUIHandler) renderTemplate(w http.ResponseWriter, templateName strin
                     <textarea name="sql" placeholder="Enter your SQL query here..." style="width: 100%; height: 200px; padding: 10px; border: 1px solid #ddd; border-radius: 4px; font-family: monospace;"></textarea>
                     <br><br>
                     <button type="submit" class="button">Execute Query</button>
+                    <button type="submit" class="button" name="format" value="csv">Download CSV</button>
                     {{if .ReadOnly}}<span style="margin-left: 10px; color: #666;">(Read-only mode)</span>{{end}}
                 </form>
                 <div id="results" style="margin-top: 20px;"></div>
@@ -525,6 +595,10 @@ func (ui *WebUIHandler) renderTemplate(w http.ResponseWriter, templateName strin
             
             <script>
             function executeQuery(event) {
+                // Downloads are submitted as a regular form so that the browser saves them
+                if (event.submitter && event.submitter.name === 'format') {
+                    return true;
+                }
                 event.preventDefault();
                 const form = event.target;
                 const formData = new FormData(form);
@@ -542,7 +616,7 @@ func (ui *WebUIHandler) renderTemplate(w http.ResponseWriter, templateName strin
                         resultsDiv.innerHTML = '<div style="color: red; padding: 10px; border: 1px solid red; border-radius: 4px;">Error: ' + data.error + '</div>';
                     } else {
                         let html = '<div style="border: 1px solid #ddd; border-radius: 4px; padding: 10px;">';
-                        html += '<h4>Query Results (' + data.row_count + ' rows, ' + data.duration_ms + 'ms)</h4>';
+                        html += '<h4>Query Results (' + data.row_count + (data.truncated ? '+' : '') + ' rows, ' + data.duration_ms + 'ms)</h4>';
                         
                         if (data.columns && data.columns.length > 0) {
                             html += '<div style="overflow-x: auto;"><table style="border-collapse: collapse; width: 100%;">';
@@ -562,8 +636,8 @@ func (ui *WebUIHandler) renderTemplate(w http.ResponseWriter, templateName strin
                             
                             html += '</tbody></table></div>';
                             
-                            if (data.rows.length > 50) {
-                                html += '<p style="color: #666; font-style: italic;">Showing first 50 rows of ' + data.row_count + ' total.</p>';
+                            if (data.row_count > 50) {
+                                html += '<p style="color: #666; font-style: italic;">Showing first 50 rows of ' + data.row_count + (data.truncated ? '+' : '') + '.</p>';
                             }
                         }
                         
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUIExecuteRejectsCrossOriginRequests(t *testing.T) {
+	ui := &WebUIHandler{}
+	handler := ui.handler()
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		status int
+	}{
+		{"cross-site form post", map[string]string{"Sec-Fetch-Site": "cross-site"}, http.StatusForbidden},
+		{"same-site form post", map[string]string{"Sec-Fetch-Site": "same-site"}, http.StatusForbidden},
+		{"other origin", map[string]string{"Origin": "https://attacker.example"}, http.StatusForbidden},
+		{"UI page", map[string]string{"Sec-Fetch-Site": "same-origin", "Origin": "http://icebox.local:8081"}, http.StatusBadRequest},
+		{"UI origin", map[string]string{"Origin": "http://icebox.local:8081"}, http.StatusBadRequest},
+		{"non-browser client", nil, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The requests have no SQL, so those that are let through fail validation
+			req := httptest.NewRequest(http.MethodPost, "http://icebox.local:8081/api/execute", strings.NewReader("sql="))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			for key, value := range tt.header {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,592 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TFMV/icebox/catalog"
+	"github.com/TFMV/icebox/catalog/view"
+	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/display"
+	"github.com/TFMV/icebox/engine/duckdb"
+	"github.com/apache/iceberg-go"
+	icebergcatalog "github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
+	"github.com/spf13/cobra"
+)
+
+var viewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Manage Iceberg views",
+	Long: `Manage Iceberg views in your catalog.
+
+Views are named SQL queries stored in the catalog as Iceberg view metadata.
+They are registered with the SQL engine next to tables, so they can be
+queried from 'icebox sql', 'icebox shell' and the UI.
+
+This command provides subcommands for view operations:
+- create: Create a view from a SQL query
+- list: List all views in a namespace
+- describe: Show the definition and schema of a view
+- drop: Drop a view from the catalog
+
+Examples:
+  icebox view create top_customers --sql "SELECT customer_id, SUM(amount) AS total FROM sales GROUP BY 1"
+  icebox view list
+  icebox view describe top_customers
+  icebox view drop top_customers`,
+}
+
+var viewCreateCmd = &cobra.Command{
+	Use:   "create <view>",
+	Short: "Create a view from a SQL query",
+	Long: `Create a new Iceberg view from a SQL query.
+
+DuckDB views are validated against the catalog's tables and views before they
+are stored, and their schema is recorded in the view metadata. Views written
+in another dialect are stored as-is.
+
+Examples:
+  icebox view create recent_orders --sql "SELECT * FROM orders WHERE order_date > current_date - 30"
+  icebox view create analytics.daily_revenue --sql "SELECT order_date, SUM(amount) FROM sales GROUP BY 1"
+  icebox view create recent_orders --sql "..." --replace
+  icebox view create spark_view --sql "..." --dialect spark`,
+	Args: cobra.ExactArgs(1),
+	RunE: runViewCreate,
+}
+
+var viewListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List views in a namespace",
+	Long: `List all views in the specified namespace.
+
+If no namespace is specified, lists views in the default namespace.
+
+Examples:
+  icebox view list
+  icebox view list --namespace analytics
+  icebox view list --all-namespaces --format json`,
+	RunE: runViewList,
+}
+
+var viewDescribeCmd = &cobra.Command{
+	Use:   "describe <view>",
+	Short: "Describe a view's definition and schema",
+	Long: `Show detailed information about a view including:
+- SQL definition and dialect
+- Current version and version history
+- Schema (columns, types, nullability)
+- View properties
+
+Examples:
+  icebox view describe top_customers
+  icebox view describe analytics.daily_revenue`,
+	Args: cobra.ExactArgs(1),
+	RunE: runViewDescribe,
+}
+
+var viewDropCmd = &cobra.Command{
+	Use:   "drop <view>",
+	Short: "Drop a view from the catalog",
+	Long: `Drop an existing view from the catalog.
+
+Dropping a view never touches the data of the tables it queries.
+
+Examples:
+  icebox view drop top_customers
+  icebox view drop analytics.daily_revenue`,
+	Args: cobra.ExactArgs(1),
+	RunE: runViewDrop,
+}
+
+type viewCreateOptions struct {
+	sql        string
+	dialect    string
+	properties map[string]string
+	replace    bool
+}
+
+type viewListOptions struct {
+	namespace     string
+	allNamespaces bool
+	format        string
+}
+
+var (
+	viewCreateOpts = &viewCreateOptions{}
+	viewListOpts   = &viewListOptions{}
+)
+
+func init() {
+	rootCmd.AddCommand(viewCmd)
+
+	// Add subcommands
+	viewCmd.AddCommand(viewCreateCmd)
+	viewCmd.AddCommand(viewListCmd)
+	viewCmd.AddCommand(viewDescribeCmd)
+	viewCmd.AddCommand(viewDropCmd)
+
+	// View create flags
+	viewCreateCmd.Flags().StringVar(&viewCreateOpts.sql, "sql", "", "SQL query defining the view")
+	viewCreateCmd.Flags().StringVar(&viewCreateOpts.dialect, "dialect", view.DefaultDialect, "SQL dialect of the query")
+	viewCreateCmd.Flags().StringToStringVar(&viewCreateOpts.properties, "property", nil, "view properties (key=value)")
+	viewCreateCmd.Flags().BoolVar(&viewCreateOpts.replace, "replace", false, "replace the view if it already exists")
+	_ = viewCreateCmd.MarkFlagRequired("sql")
+
+	// View list flags
+	viewListCmd.Flags().StringVar(&viewListOpts.namespace, "namespace", "default", "namespace to list views from")
+	viewListCmd.Flags().BoolVar(&viewListOpts.allNamespaces, "all-namespaces", false, "list views from all namespaces")
+	viewListCmd.Flags().StringVar(&viewListOpts.format, "format", "table", "output format: table, csv, json")
+}
+
+func runViewCreate(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	cfg, cat, views, err := openViewCatalog()
+	if err != nil {
+		return err
+	}
+	defer cat.Close()
+
+	viewIdent, _, err := parseTableIdentifier(args[0], "")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to parse view identifier: %w", err)
+	}
+
+	if err := ensureNamespace(ctx, cat, viewIdent[:len(viewIdent)-1]); err != nil {
+		return fmt.Errorf("❌ Failed to create namespace: %w", err)
+	}
+
+	if viewCreateOpts.dialect != view.DefaultDialect {
+		// Other dialects can't be validated by DuckDB, so they are stored without a schema
+		if viewCreateOpts.replace {
+			if err := dropViewIfExists(ctx, views, viewIdent); err != nil {
+				return fmt.Errorf("❌ Failed to replace view: %w", err)
+			}
+		}
+		if _, err := views.CreateView(ctx, viewIdent, viewCreateOpts.sql, viewCreateOpts.dialect, nil, viewCreateOpts.properties); err != nil {
+			return fmt.Errorf("❌ Failed to create view: %w", err)
+		}
+		fmt.Printf("✅ Successfully created %s view %s\n", viewCreateOpts.dialect, strings.Join(viewIdent, "."))
+		return nil
+	}
+
+	// Make the embedded MinIO server reachable for projects that use it
+	stopStorage, err := startStorage(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer stopStorage()
+
+	engine, err := duckdb.NewEngine(cat)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to create SQL engine: %w\n"+
+			"💡 This might be a DuckDB installation issue", err)
+	}
+	defer engine.Close()
+
+	// The view's query may reference any table or view in the catalog
+	if err := autoRegisterTables(ctx, engine, cat); err != nil {
+		fmt.Printf("⚠️  Warning: Failed to auto-register some tables: %v\n", err)
+	}
+
+	created, err := createCatalogView(ctx, views, engine, viewIdent, viewCreateOpts.sql, viewCreateOpts.properties, viewCreateOpts.replace)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to create view: %w", err)
+	}
+
+	fmt.Printf("✅ Successfully created view %s with %d columns\n", strings.Join(viewIdent, "."), len(created.Schema().Fields))
+	return nil
+}
+
+func runViewList(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	_, cat, views, err := openViewCatalog()
+	if err != nil {
+		return err
+	}
+	defer cat.Close()
+
+	namespaces := []table.Identifier{{viewListOpts.namespace}}
+	if viewListOpts.allNamespaces {
+		namespaces, err = cat.ListNamespaces(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("❌ Failed to list namespaces: %w", err)
+		}
+	} else {
+		exists, err := cat.CheckNamespaceExists(ctx, namespaces[0])
+		if err != nil {
+			return fmt.Errorf("❌ Failed to check namespace existence: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("❌ Namespace '%s' does not exist\n"+
+				"💡 Use 'icebox catalog list' to see available namespaces", viewListOpts.namespace)
+		}
+	}
+
+	format, err := parseStatsFormat(viewListOpts.format)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	data := display.TableData{Headers: []string{"namespace", "view", "dialect", "sql"}}
+	for _, namespace := range namespaces {
+		for identifier, err := range views.ListViews(ctx, namespace) {
+			if err != nil {
+				return fmt.Errorf("❌ Failed to list views: %w", err)
+			}
+
+			loaded, err := views.LoadView(ctx, identifier)
+			if err != nil {
+				return fmt.Errorf("❌ Failed to load view '%s': %w", strings.Join(identifier, "."), err)
+			}
+
+			sql := loaded.SQL()
+			if format == display.FormatTable {
+				sql = truncateString(strings.Join(strings.Fields(sql), " "), 60)
+			}
+			data.Rows = append(data.Rows, []interface{}{
+				strings.Join(identifier[:len(identifier)-1], "."),
+				identifier[len(identifier)-1],
+				loaded.Dialect(),
+				sql,
+			})
+		}
+	}
+
+	if len(data.Rows) == 0 && format == display.FormatTable {
+		if viewListOpts.allNamespaces {
+			fmt.Println("📭 No views found")
+		} else {
+			fmt.Printf("📭 No views found in namespace '%s'\n", viewListOpts.namespace)
+		}
+		fmt.Println("💡 Use 'icebox view create <name> --sql \"...\"' to create one")
+		return nil
+	}
+
+	d := display.GetDisplayOrDefault(ctx)
+	if format != display.FormatTable {
+		return d.Table(data).WithFormat(format).Render()
+	}
+	return d.Table(data).WithTitle(fmt.Sprintf("👁️  Views (%d)", len(data.Rows))).WithCompactMode().Render()
+}
+
+func runViewDescribe(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	_, cat, views, err := openViewCatalog()
+	if err != nil {
+		return err
+	}
+	defer cat.Close()
+
+	viewIdent, _, err := parseTableIdentifier(args[0], "")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to parse view identifier: %w", err)
+	}
+
+	loaded, err := views.LoadView(ctx, viewIdent)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load view '%s': %w\n"+
+			"💡 Use 'icebox view list' to see available views", args[0], err)
+	}
+
+	metadata := loaded.Metadata()
+	fmt.Printf("👁️  View: %s\n", strings.Join(viewIdent, "."))
+	fmt.Printf("🆔 UUID: %s\n", metadata.ViewUUID)
+	fmt.Printf("📍 Location: %s\n", metadata.Location)
+	if version := loaded.CurrentVersion(); version != nil {
+		fmt.Printf("🔢 Version: %d of %d (created %s)\n", version.VersionID, len(metadata.Versions),
+			time.UnixMilli(version.TimestampMs).Format("2006-01-02 15:04:05"))
+		if len(version.DefaultNamespace) > 0 {
+			fmt.Printf("📁 Default Namespace: %s\n", strings.Join(version.DefaultNamespace, "."))
+		}
+	}
+	fmt.Printf("🗣️  Dialect: %s\n", loaded.Dialect())
+	fmt.Printf("\n📝 SQL:\n%s\n", loaded.SQL())
+
+	d := display.GetDisplayOrDefault(ctx)
+	if schema := loaded.Schema(); schema != nil && len(schema.Fields) > 0 {
+		data := display.TableData{Headers: []string{"ID", "Name", "Type", "Required", "Doc"}}
+		for _, field := range schema.Fields {
+			data.Rows = append(data.Rows, []interface{}{field.ID, field.Name, field.Type, field.Required, field.Doc})
+		}
+		fmt.Println()
+		if err := d.Table(data).WithTitle("📋 Schema").WithCompactMode().Render(); err != nil {
+			return fmt.Errorf("❌ Failed to display view schema: %w", err)
+		}
+	}
+
+	if len(metadata.Properties) > 0 {
+		keys := make([]string, 0, len(metadata.Properties))
+		for key := range metadata.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		data := display.TableData{Headers: []string{"Property", "Value"}}
+		for _, key := range keys {
+			data.Rows = append(data.Rows, []interface{}{key, metadata.Properties[key]})
+		}
+		fmt.Println()
+		if err := d.Table(data).WithTitle("⚙️  Properties").WithCompactMode().Render(); err != nil {
+			return fmt.Errorf("❌ Failed to display view properties: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func runViewDrop(cmd *cobra.Command, args []string) error {
+	_, cat, views, err := openViewCatalog()
+	if err != nil {
+		return err
+	}
+	defer cat.Close()
+
+	viewIdent, _, err := parseTableIdentifier(args[0], "")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to parse view identifier: %w", err)
+	}
+
+	if err := views.DropView(cmd.Context(), viewIdent); err != nil {
+		return fmt.Errorf("❌ Failed to drop view: %w", err)
+	}
+
+	fmt.Printf("✅ Successfully dropped view %s\n", strings.Join(viewIdent, "."))
+	return nil
+}
+
+// openViewCatalog opens the project catalog and checks that it can store views
+func openViewCatalog() (*config.Config, catalog.CatalogInterface, catalog.ViewCatalog, error) {
+	_, cfg, err := config.FindConfig()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("❌ Failed to find Icebox configuration\n"+
+			"💡 Try running 'icebox init' first to create a new project: %w", err)
+	}
+
+	cat, err := catalog.NewCatalog(cfg)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("❌ Failed to create catalog: %w", err)
+	}
+
+	views, ok := cat.(catalog.ViewCatalog)
+	if !ok {
+		cat.Close()
+		return nil, nil, nil, fmt.Errorf("❌ The %s catalog does not support views\n"+
+			"💡 Views are available with the sqlite and json catalogs", cfg.Catalog.Type)
+	}
+
+	return cfg, cat, views, nil
+}
+
+// createCatalogView validates a DuckDB query, stores it as a view with the schema it
+// produces and registers the view with the engine
+func createCatalogView(ctx context.Context, views catalog.ViewCatalog, engine *duckdb.Engine, identifier table.Identifier, sql string, properties map[string]string, replace bool) (*view.View, error) {
+	schema, err := engine.QuerySchema(ctx, sql)
+	if err != nil {
+		return nil, fmt.Errorf("invalid view query: %w", err)
+	}
+
+	if replace {
+		if err := dropViewIfExists(ctx, views, identifier); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := views.CreateView(ctx, identifier, sql, view.DefaultDialect, schema, properties)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := engine.RegisterView(ctx, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// ensureNamespace creates a namespace if it doesn't exist yet
+func ensureNamespace(ctx context.Context, cat catalog.CatalogInterface, namespace table.Identifier) error {
+	exists, err := cat.CheckNamespaceExists(ctx, namespace)
+	if err != nil || exists {
+		return err
+	}
+	if err := cat.CreateNamespace(ctx, namespace, iceberg.Properties{}); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Created namespace: %v\n", namespace)
+	return nil
+}
+
+// dropViewIfExists drops a view, ignoring views that don't exist
+func dropViewIfExists(ctx context.Context, views catalog.ViewCatalog, identifier table.Identifier) error {
+	exists, err := views.ViewExists(ctx, identifier)
+	if err != nil || !exists {
+		return err
+	}
+	return views.DropView(ctx, identifier)
+}
+
+// registerCatalogViews registers the views of the given namespaces with the engine.
+// Views may query other views, so views that fail are retried as long as each pass
+// registers at least one more view.
+func registerCatalogViews(ctx context.Context, engine *duckdb.Engine, cat catalog.CatalogInterface, namespaces []table.Identifier) (int, []string) {
+	views, ok := cat.(catalog.ViewCatalog)
+	if !ok {
+		return 0, nil
+	}
+
+	var pending []*view.View
+	var errs []string
+	for _, namespace := range namespaces {
+		for identifier, err := range views.ListViews(ctx, namespace) {
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("failed to list views in namespace %v: %v", namespace, err))
+				break
+			}
+
+			loaded, err := views.LoadView(ctx, identifier)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("failed to load view %v: %v", identifier, err))
+				continue
+			}
+			pending = append(pending, loaded)
+		}
+	}
+
+	registered := 0
+	failures := make(map[string]error)
+	for len(pending) > 0 {
+		var retry []*view.View
+		for _, v := range pending {
+			name := strings.Join(v.Identifier(), ".")
+			if err := engine.RegisterView(ctx, v); err != nil {
+				failures[name] = err
+				retry = append(retry, v)
+				continue
+			}
+			delete(failures, name)
+			fmt.Printf("✅ Successfully registered view: %s\n", name)
+			registered++
+		}
+
+		if len(retry) == len(pending) {
+			break
+		}
+		pending = retry
+	}
+
+	names := make([]string, 0, len(failures))
+	for name := range failures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		errs = append(errs, fmt.Sprintf("failed to register view %s: %v", name, failures[name]))
+	}
+
+	return registered, errs
+}
+
+var (
+	createViewPattern = regexp.MustCompile(`(?is)^\s*CREATE\s+(OR\s+REPLACE\s+)?VIEW\s+(IF\s+NOT\s+EXISTS\s+)?("[^"]+"|[\w.]+)\s+AS\s+(.+)$`)
+	dropViewPattern   = regexp.MustCompile(`(?is)^\s*DROP\s+VIEW\s+(IF\s+EXISTS\s+)?("[^"]+"|[\w.]+)\s*$`)
+)
+
+// viewStatement is a CREATE VIEW or DROP VIEW statement that targets the catalog
+type viewStatement struct {
+	drop        bool
+	identifier  table.Identifier
+	sql         string
+	replace     bool
+	ifNotExists bool
+	ifExists    bool
+}
+
+// parseViewStatement recognizes CREATE [OR REPLACE] VIEW and DROP VIEW statements.
+// Temporary views and views with column lists are left to DuckDB.
+func parseViewStatement(query string) (*viewStatement, bool) {
+	query = strings.TrimSuffix(strings.TrimSpace(query), ";")
+
+	if match := createViewPattern.FindStringSubmatch(query); match != nil {
+		identifier, _, err := parseTableIdentifier(strings.Trim(match[3], `"`), "")
+		if err != nil {
+			return nil, false
+		}
+		return &viewStatement{
+			identifier:  identifier,
+			sql:         strings.TrimSpace(match[4]),
+			replace:     match[1] != "",
+			ifNotExists: match[2] != "",
+		}, true
+	}
+
+	if match := dropViewPattern.FindStringSubmatch(query); match != nil {
+		identifier, _, err := parseTableIdentifier(strings.Trim(match[2], `"`), "")
+		if err != nil {
+			return nil, false
+		}
+		return &viewStatement{
+			drop:       true,
+			identifier: identifier,
+			ifExists:   match[1] != "",
+		}, true
+	}
+
+	return nil, false
+}
+
+// executeViewStatement persists a CREATE VIEW statement in the catalog, or drops a
+// catalog view. It reports false for DROP VIEW statements on views the catalog
+// doesn't know, which are left to DuckDB.
+func executeViewStatement(ctx context.Context, cat catalog.CatalogInterface, engine *duckdb.Engine, stmt *viewStatement) (bool, error) {
+	views, ok := cat.(catalog.ViewCatalog)
+	if !ok {
+		if stmt.drop {
+			return false, nil
+		}
+		return true, fmt.Errorf("the %s catalog does not support views, use CREATE TEMP VIEW for a view in this session only", cat.CatalogType())
+	}
+
+	name := strings.Join(stmt.identifier, ".")
+	exists, err := views.ViewExists(ctx, stmt.identifier)
+	if err != nil {
+		return true, err
+	}
+
+	if stmt.drop {
+		if !exists {
+			return false, nil
+		}
+		if err := views.DropView(ctx, stmt.identifier); err != nil {
+			return true, err
+		}
+		if err := engine.UnregisterView(ctx, stmt.identifier); err != nil {
+			return true, err
+		}
+		fmt.Printf("✅ Dropped view %s from the catalog\n", name)
+		return true, nil
+	}
+
+	if exists && stmt.ifNotExists {
+		fmt.Printf("📭 View %s already exists\n", name)
+		return true, nil
+	}
+	if exists && !stmt.replace {
+		return true, fmt.Errorf("view %s: %w", name, icebergcatalog.ErrViewAlreadyExists)
+	}
+
+	if err := ensureNamespace(ctx, cat, stmt.identifier[:len(stmt.identifier)-1]); err != nil {
+		return true, err
+	}
+	if _, err := createCatalogView(ctx, views, engine, stmt.identifier, stmt.sql, nil, stmt.replace); err != nil {
+		return true, err
+	}
+	fmt.Printf("✅ Created view %s in the catalog\n", name)
+	return true, nil
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseViewStatement(t *testing.T) {
+	stmt, ok := parseViewStatement("CREATE VIEW top_users AS SELECT * FROM users ORDER BY score DESC LIMIT 10;")
+	require.True(t, ok)
+	assert.False(t, stmt.drop)
+	assert.Equal(t, table.Identifier{"default", "top_users"}, stmt.identifier)
+	assert.Equal(t, "SELECT * FROM users ORDER BY score DESC LIMIT 10", stmt.sql)
+	assert.False(t, stmt.replace)
+
+	stmt, ok = parseViewStatement("create or replace view analytics.daily\nas\nselect 1 as one")
+	require.True(t, ok)
+	assert.Equal(t, table.Identifier{"analytics", "daily"}, stmt.identifier)
+	assert.Equal(t, "select 1 as one", stmt.sql)
+	assert.True(t, stmt.replace)
+
+	stmt, ok = parseViewStatement(`CREATE VIEW IF NOT EXISTS "recent" AS SELECT 1`)
+	require.True(t, ok)
+	assert.True(t, stmt.ifNotExists)
+	assert.Equal(t, table.Identifier{"default", "recent"}, stmt.identifier)
+
+	stmt, ok = parseViewStatement("DROP VIEW IF EXISTS analytics.daily")
+	require.True(t, ok)
+	assert.True(t, stmt.drop)
+	assert.True(t, stmt.ifExists)
+	assert.Equal(t, table.Identifier{"analytics", "daily"}, stmt.identifier)
+
+	// Statements that only make sense in DuckDB are not intercepted
+	for _, query := range []string{
+		"CREATE TEMP VIEW scratch AS SELECT 1",
+		"CREATE VIEW v(a, b) AS SELECT 1, 2",
+		"SELECT * FROM view_definitions",
+		"DROP TABLE sales",
+	} {
+		_, ok := parseViewStatement(query)
+		assert.False(t, ok, query)
+	}
+}
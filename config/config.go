@@ -14,6 +14,8 @@ type Config struct {
 	Catalog  CatalogConfig `yaml:"catalog"`
 	Storage  StorageConfig `yaml:"storage"`
 	Metadata Metadata      `yaml:"metadata,omitempty"`
+	Server   *ServerConfig `yaml:"server,omitempty"`
+	Auth     *AuthConfig   `yaml:"auth,omitempty"`
 }
 
 // CatalogConfig holds catalog-specific configuration
@@ -68,6 +70,7 @@ type StorageConfig struct {
 	FileSystem *FileSystemConfig `yaml:"filesystem,omitempty"`
 	Memory     *MemoryConfig     `yaml:"memory,omitempty"`
 	S3         *S3Config         `yaml:"s3,omitempty"`
+	MinIO      *MinIOConfig      `yaml:"minio,omitempty"`
 }
 
 // FileSystemConfig holds local filesystem storage configuration
@@ -82,11 +85,26 @@ type MemoryConfig struct {
 
 // S3Config holds S3-compatible storage configuration
 type S3Config struct {
-	Bucket          string `yaml:"bucket"`
-	Region          string `yaml:"region,omitempty"`
-	Endpoint        string `yaml:"endpoint,omitempty"`
-	AccessKeyID     string `yaml:"access_key_id,omitempty"`
-	SecretAccessKey string `yaml:"secret_access_key,omitempty"`
+	Bucket           string `yaml:"bucket"`
+	Region           string `yaml:"region,omitempty"`
+	Endpoint         string `yaml:"endpoint,omitempty"`
+	AccessKeyID      string `yaml:"access_key_id,omitempty"`
+	SecretAccessKey  string `yaml:"secret_access_key,omitempty"`
+	Prefix           string `yaml:"prefix,omitempty"`             // Key prefix for the warehouse within the bucket
+	VirtualHostStyle bool   `yaml:"virtual_host_style,omitempty"` // Use virtual-hosted instead of path-style URLs
+}
+
+// MinIOConfig holds configuration for the embedded S3-compatible server. Projects that
+// use it also carry an S3Config pointing at the server's endpoint.
+type MinIOConfig struct {
+	Address   string `yaml:"address"`
+	Port      int    `yaml:"port"`
+	DataDir   string `yaml:"data_dir"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+	Region    string `yaml:"region,omitempty"`
+	Bucket    string `yaml:"bucket"`
+	AutoStart bool   `yaml:"auto_start"` // Start the server in-process when it is not already running
 }
 
 // Metadata holds additional project metadata
@@ -97,6 +115,54 @@ type Metadata struct {
 	Properties  map[string]string `yaml:"properties,omitempty"`
 }
 
+// ServerConfig holds configuration for 'icebox serve'
+type ServerConfig struct {
+	PGWire *PGWireConfig `yaml:"pgwire,omitempty"`
+}
+
+// PGWireConfig holds configuration for the PostgreSQL wire-protocol server
+type PGWireConfig struct {
+	// Users maps user names to password hashes, given as a bcrypt hash or as
+	// "sha256:<hex digest>". Connections are not authenticated when no users are configured.
+	Users map[string]string `yaml:"users,omitempty"`
+}
+
+// AuthConfig holds the credentials accepted by 'icebox serve' and 'icebox ui' when they
+// run with --auth
+type AuthConfig struct {
+	APIKeys []APIKeyConfig `yaml:"api_keys,omitempty"`
+	JWT     *JWTConfig     `yaml:"jwt,omitempty"`
+	Basic   []BasicUser    `yaml:"basic,omitempty"`
+	// AuditLog is the file failed authentication attempts are appended to as JSON lines;
+	// they go to standard error when it is empty
+	AuditLog string `yaml:"audit_log,omitempty"`
+}
+
+// APIKeyConfig holds an API key, which clients send as a Bearer token or an X-API-Key
+// header. Only the key's hash is stored.
+type APIKeyConfig struct {
+	Name  string   `yaml:"name"`
+	Hash  string   `yaml:"hash"` // "sha256:<hex digest>" of the key
+	Roles []string `yaml:"roles,omitempty"`
+}
+
+// JWTConfig holds the keys that JWT bearer tokens are validated with: a shared secret for
+// HS256 and a local JWKS file of public keys for RS256
+type JWTConfig struct {
+	Secret     string `yaml:"secret,omitempty"`
+	JWKSFile   string `yaml:"jwks_file,omitempty"`
+	Issuer     string `yaml:"issuer,omitempty"`
+	Audience   string `yaml:"audience,omitempty"`
+	RolesClaim string `yaml:"roles_claim,omitempty"` // defaults to "roles"
+}
+
+// BasicUser holds a user for HTTP basic authentication
+type BasicUser struct {
+	Username     string   `yaml:"username"`
+	PasswordHash string   `yaml:"password_hash"` // bcrypt hash or "sha256:<hex digest>"
+	Roles        []string `yaml:"roles,omitempty"`
+}
+
 // JSONConfig holds JSON catalog configuration
 type JSONConfig struct {
 	URI       string `yaml:"uri"`       // Path to the catalog.json file
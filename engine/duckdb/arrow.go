@@ -0,0 +1,239 @@
+package duckdb
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	duckdb "github.com/marcboeker/go-duckdb/v2"
+)
+
+// ArrowValue returns the value at index i of an Arrow array as a Go value DuckDB accepts
+// as a query argument. Types without a natural Go counterpart are passed as strings.
+func ArrowValue(arr arrow.Array, i int) interface{} {
+	if arr.IsNull(i) {
+		return nil
+	}
+
+	switch arr := arr.(type) {
+	case *array.Boolean:
+		return arr.Value(i)
+	case *array.Int8:
+		return arr.Value(i)
+	case *array.Int16:
+		return arr.Value(i)
+	case *array.Int32:
+		return arr.Value(i)
+	case *array.Int64:
+		return arr.Value(i)
+	case *array.Uint8:
+		return arr.Value(i)
+	case *array.Uint16:
+		return arr.Value(i)
+	case *array.Uint32:
+		return arr.Value(i)
+	case *array.Uint64:
+		return arr.Value(i)
+	case *array.Float32:
+		return arr.Value(i)
+	case *array.Float64:
+		return arr.Value(i)
+	case *array.String:
+		return arr.Value(i)
+	case *array.LargeString:
+		return arr.Value(i)
+	case *array.Binary:
+		return arr.Value(i)
+	case *array.LargeBinary:
+		return arr.Value(i)
+	case *array.Date32:
+		return arr.Value(i).ToTime()
+	case *array.Date64:
+		return arr.Value(i).ToTime()
+	case *array.Timestamp:
+		return arr.Value(i).ToTime(arr.DataType().(*arrow.TimestampType).Unit)
+	default:
+		return arr.ValueStr(i)
+	}
+}
+
+// RowValue returns the value at index i of an Arrow array as the Go value database/sql
+// scans from DuckDB: decimals as duckdb.Decimal, intervals as duckdb.Interval, dates,
+// times and timestamps as time.Time in UTC, lists as []interface{}, structs as
+// map[string]interface{} and maps as duckdb.Map.
+func RowValue(arr arrow.Array, i int) interface{} {
+	if arr.IsNull(i) {
+		return nil
+	}
+
+	if unscaled, ok := decimalValue(arr, i); ok {
+		decimalType := arr.DataType().(arrow.DecimalType)
+		return duckdb.Decimal{Width: uint8(decimalType.GetPrecision()), Scale: uint8(decimalType.GetScale()), Value: unscaled}
+	}
+
+	switch arr := arr.(type) {
+	case *array.Binary:
+		return bytes.Clone(arr.Value(i))
+	case *array.LargeBinary:
+		return bytes.Clone(arr.Value(i))
+	case *array.FixedSizeBinary:
+		return bytes.Clone(arr.Value(i))
+	case *array.Date32:
+		return arr.Value(i).ToTime()
+	case *array.Date64:
+		return arr.Value(i).ToTime()
+	case *array.Time32:
+		return time.Time{}.Add(arr.Value(i).ToTime(arr.DataType().(*arrow.Time32Type).Unit).Sub(time.Unix(0, 0).UTC()))
+	case *array.Time64:
+		return time.Time{}.Add(arr.Value(i).ToTime(arr.DataType().(*arrow.Time64Type).Unit).Sub(time.Unix(0, 0).UTC()))
+	case *array.Timestamp:
+		return arr.Value(i).ToTime(arr.DataType().(*arrow.TimestampType).Unit).UTC()
+	case *array.MonthDayNanoInterval:
+		interval := arr.Value(i)
+		return duckdb.Interval{Months: interval.Months, Days: interval.Days, Micros: interval.Nanoseconds / 1000}
+	case *array.Map:
+		keys, items := arr.Keys(), arr.Items()
+		start, end := arr.ValueOffsets(i)
+		entries := make(duckdb.Map, end-start)
+		for j := int(start); j < int(end); j++ {
+			entries[RowValue(keys, j)] = RowValue(items, j)
+		}
+		return entries
+	case array.ListLike:
+		values := arr.ListValues()
+		start, end := arr.ValueOffsets(i)
+		items := make([]interface{}, 0, end-start)
+		for j := int(start); j < int(end); j++ {
+			items = append(items, RowValue(values, j))
+		}
+		return items
+	case *array.Struct:
+		structType := arr.DataType().(*arrow.StructType)
+		members := make(map[string]interface{}, arr.NumField())
+		for j := 0; j < arr.NumField(); j++ {
+			members[structType.Field(j).Name] = RowValue(arr.Field(j), i)
+		}
+		return members
+	default:
+		return ArrowValue(arr, i)
+	}
+}
+
+// FormatArrowValue formats the non-null value at index i of an Arrow array as text:
+// decimals exactly, strings as they are and other values as Arrow prints them, which
+// is JSON for nested types
+func FormatArrowValue(arr arrow.Array, i int) string {
+	if unscaled, ok := decimalValue(arr, i); ok {
+		return formatDecimal(unscaled, arr.DataType().(arrow.DecimalType).GetScale())
+	}
+
+	switch arr := arr.(type) {
+	case *array.String:
+		return arr.Value(i)
+	case *array.LargeString:
+		return arr.Value(i)
+	default:
+		return arr.ValueStr(i)
+	}
+}
+
+// decimalValue returns the unscaled value at index i of a decimal array
+func decimalValue(arr arrow.Array, i int) (*big.Int, bool) {
+	switch arr := arr.(type) {
+	case *array.Decimal32:
+		return big.NewInt(int64(arr.Value(i))), true
+	case *array.Decimal64:
+		return big.NewInt(int64(arr.Value(i))), true
+	case *array.Decimal128:
+		return arr.Value(i).BigInt(), true
+	case *array.Decimal256:
+		return arr.Value(i).BigInt(), true
+	default:
+		return nil, false
+	}
+}
+
+// formatDecimal formats an unscaled decimal value with scale digits after the point
+func formatDecimal(unscaled *big.Int, scale int32) string {
+	digits := new(big.Int).Abs(unscaled).String()
+	if scale > 0 {
+		if pad := int(scale) + 1 - len(digits); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		digits = digits[:len(digits)-int(scale)] + "." + digits[len(digits)-int(scale):]
+	}
+	if unscaled.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// duckDBTypeName returns the DuckDB type name of values of an Arrow type, as DuckDB
+// returns them through its Arrow interface
+func duckDBTypeName(dataType arrow.DataType) string {
+	switch dt := dataType.(type) {
+	case *arrow.BooleanType:
+		return "BOOLEAN"
+	case *arrow.Int8Type:
+		return "TINYINT"
+	case *arrow.Int16Type:
+		return "SMALLINT"
+	case *arrow.Int32Type:
+		return "INTEGER"
+	case *arrow.Int64Type:
+		return "BIGINT"
+	case *arrow.Uint8Type:
+		return "UTINYINT"
+	case *arrow.Uint16Type:
+		return "USMALLINT"
+	case *arrow.Uint32Type:
+		return "UINTEGER"
+	case *arrow.Uint64Type:
+		return "UBIGINT"
+	case *arrow.Float32Type:
+		return "FLOAT"
+	case *arrow.Float64Type:
+		return "DOUBLE"
+	case *arrow.BinaryType, *arrow.LargeBinaryType, *arrow.FixedSizeBinaryType:
+		return "BLOB"
+	case *arrow.Date32Type, *arrow.Date64Type:
+		return "DATE"
+	case *arrow.Time32Type, *arrow.Time64Type:
+		return "TIME"
+	case *arrow.TimestampType:
+		switch {
+		case dt.TimeZone != "":
+			return "TIMESTAMPTZ"
+		case dt.Unit == arrow.Second:
+			return "TIMESTAMP_S"
+		case dt.Unit == arrow.Millisecond:
+			return "TIMESTAMP_MS"
+		case dt.Unit == arrow.Nanosecond:
+			return "TIMESTAMP_NS"
+		default:
+			return "TIMESTAMP"
+		}
+	case *arrow.MonthDayNanoIntervalType:
+		return "INTERVAL"
+	case arrow.DecimalType:
+		return fmt.Sprintf("DECIMAL(%d,%d)", dt.GetPrecision(), dt.GetScale())
+	case *arrow.MapType:
+		return fmt.Sprintf("MAP(%s, %s)", duckDBTypeName(dt.KeyType()), duckDBTypeName(dt.ItemType()))
+	case *arrow.FixedSizeListType:
+		return fmt.Sprintf("%s[%d]", duckDBTypeName(dt.Elem()), dt.Len())
+	case arrow.ListLikeType:
+		return duckDBTypeName(dt.Elem()) + "[]"
+	case *arrow.StructType:
+		members := make([]string, dt.NumFields())
+		for i, field := range dt.Fields() {
+			members[i] = quoteIdentifier(field.Name) + " " + duckDBTypeName(field.Type)
+		}
+		return "STRUCT(" + strings.Join(members, ", ") + ")"
+	default:
+		return "VARCHAR"
+	}
+}
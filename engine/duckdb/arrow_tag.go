@@ -0,0 +1,7 @@
+//go:build !duckdb_arrow
+
+package duckdb
+
+// The engine reads results through go-duckdb's Arrow interface, which go-duckdb only
+// compiles with the duckdb_arrow build tag: build with -tags duckdb_arrow.
+var _ = buildWithTagDuckDBArrow
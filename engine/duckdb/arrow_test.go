@@ -0,0 +1,131 @@
+package duckdb
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	duckdb "github.com/marcboeker/go-duckdb/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRowValue(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	created := time.Date(2025, 1, 2, 3, 4, 5, 123456000, time.UTC)
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "amount", Type: &arrow.Decimal128Type{Precision: 18, Scale: 3}},
+		{Name: "small", Type: &arrow.Decimal64Type{Precision: 10, Scale: 2}},
+		{Name: "created_at", Type: &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}},
+		{Name: "day", Type: arrow.FixedWidthTypes.Date32},
+		{Name: "at", Type: arrow.FixedWidthTypes.Time64us},
+		{Name: "wait", Type: arrow.FixedWidthTypes.MonthDayNanoInterval},
+		{Name: "tags", Type: arrow.ListOf(arrow.PrimitiveTypes.Int32)},
+		{Name: "point", Type: arrow.StructOf(arrow.Field{Name: "x", Type: arrow.PrimitiveTypes.Int32})},
+		{Name: "counts", Type: arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Int32)},
+	}, nil)
+
+	builder := array.NewRecordBuilder(mem, schema)
+	defer builder.Release()
+	builder.Field(0).(*array.Int64Builder).AppendNull()
+	builder.Field(1).(*array.Decimal128Builder).Append(decimal128.FromI64(-1500))
+	builder.Field(2).(*array.Decimal64Builder).Append(12345)
+	builder.Field(3).(*array.TimestampBuilder).Append(arrow.Timestamp(created.UnixMicro()))
+	builder.Field(4).(*array.Date32Builder).Append(arrow.Date32FromTime(created))
+	builder.Field(5).(*array.Time64Builder).Append(arrow.Time64(3723 * time.Second / time.Microsecond))
+	builder.Field(6).(*array.MonthDayNanoIntervalBuilder).Append(arrow.MonthDayNanoInterval{Months: 1, Days: 2, Nanoseconds: 3000})
+	tags := builder.Field(7).(*array.ListBuilder)
+	tags.Append(true)
+	tags.ValueBuilder().(*array.Int32Builder).AppendValues([]int32{1, 2}, nil)
+	point := builder.Field(8).(*array.StructBuilder)
+	point.Append(true)
+	point.FieldBuilder(0).(*array.Int32Builder).Append(7)
+	counts := builder.Field(9).(*array.MapBuilder)
+	counts.Append(true)
+	counts.KeyBuilder().(*array.StringBuilder).Append("a")
+	counts.ItemBuilder().(*array.Int32Builder).Append(1)
+
+	record := builder.NewRecord()
+	defer record.Release()
+
+	expected := []interface{}{
+		nil,
+		duckdb.Decimal{Width: 18, Scale: 3, Value: big.NewInt(-1500)},
+		duckdb.Decimal{Width: 10, Scale: 2, Value: big.NewInt(12345)},
+		created,
+		time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(1, 1, 1, 1, 2, 3, 0, time.UTC),
+		duckdb.Interval{Months: 1, Days: 2, Micros: 3},
+		[]interface{}{int32(1), int32(2)},
+		map[string]interface{}{"x": int32(7)},
+		duckdb.Map{"a": int32(1)},
+	}
+	for i, value := range expected {
+		assert.Equal(t, value, RowValue(record.Column(i), 0), "column %s", schema.Field(i).Name)
+	}
+
+	assert.Equal(t, "-1.500", FormatArrowValue(record.Column(1), 0))
+	assert.Equal(t, "123.45", FormatArrowValue(record.Column(2), 0))
+	assert.Equal(t, "[1,2]", FormatArrowValue(record.Column(7), 0))
+}
+
+func TestQueryResultRows(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+	builder := array.NewRecordBuilder(mem, schema)
+	defer builder.Release()
+
+	var records []arrow.Record
+	for batch := int64(0); batch < 2; batch++ {
+		builder.Field(0).(*array.Int64Builder).AppendValues([]int64{2*batch + 1, 2*batch + 2}, nil)
+		builder.Field(1).(*array.StringBuilder).AppendValues([]string{"a", ""}, []bool{true, false})
+		record := builder.NewRecord()
+		defer record.Release()
+		records = append(records, record)
+	}
+	tbl := array.NewTableFromRecords(schema, records)
+	defer tbl.Release()
+
+	// Rows are converted from every batch of the table
+	result := &QueryResult{Schema: schema, Table: tbl, RowCount: tbl.NumRows()}
+	assert.Equal(t, [][]interface{}{{int64(1), "a"}, {int64(2), nil}, {int64(3), "a"}, {int64(4), nil}}, result.Rows())
+
+	assert.Empty(t, (&QueryResult{}).Rows())
+}
+
+func TestFormatDecimal(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890123456", 10)
+
+	assert.Equal(t, "0.05", formatDecimal(big.NewInt(5), 2))
+	assert.Equal(t, "-0.005", formatDecimal(big.NewInt(-5), 3))
+	assert.Equal(t, "42", formatDecimal(big.NewInt(42), 0))
+	assert.Equal(t, "123456789012345678901234567890.123456", formatDecimal(huge, 6))
+}
+
+func TestDuckDBTypeName(t *testing.T) {
+	tests := map[string]arrow.DataType{
+		"INTEGER":                         arrow.PrimitiveTypes.Int32,
+		"DECIMAL(38,0)":                   &arrow.Decimal128Type{Precision: 38},
+		"TIMESTAMPTZ":                     &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"},
+		"TIMESTAMP_NS":                    &arrow.TimestampType{Unit: arrow.Nanosecond},
+		"INTEGER[]":                       arrow.ListOf(arrow.PrimitiveTypes.Int32),
+		"VARCHAR[2]":                      arrow.FixedSizeListOf(2, arrow.BinaryTypes.String),
+		`STRUCT("a" INTEGER, "b c" DATE)`: arrow.StructOf(arrow.Field{Name: "a", Type: arrow.PrimitiveTypes.Int32}, arrow.Field{Name: "b c", Type: arrow.FixedWidthTypes.Date32}),
+		"MAP(VARCHAR, DOUBLE)":            arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Float64),
+		"INTERVAL":                        arrow.FixedWidthTypes.MonthDayNanoInterval,
+	}
+	for name, dataType := range tests {
+		assert.Equal(t, name, duckDBTypeName(dataType))
+	}
+}
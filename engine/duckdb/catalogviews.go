@@ -0,0 +1,248 @@
+package duckdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/apache/iceberg-go/table"
+)
+
+// catalogViewFilters are the filters of the catalog views FilterCatalogViews can filter,
+// by schema and name. Each returns the condition on the view's rows that keeps the rows
+// about the given relations; views without rows about tables keep all of them.
+var catalogViewFilters = map[string]func(relations []catalogRelation) string{
+	"information_schema.tables":                  relationsFilter("table_catalog", "table_schema", "table_name"),
+	"information_schema.columns":                 relationsFilter("table_catalog", "table_schema", "table_name"),
+	"information_schema.views":                   relationsFilter("table_catalog", "table_schema", "table_name"),
+	"information_schema.table_constraints":       relationsFilter("table_catalog", "table_schema", "table_name"),
+	"information_schema.key_column_usage":        relationsFilter("table_catalog", "table_schema", "table_name"),
+	"information_schema.constraint_column_usage": relationsFilter("table_catalog", "table_schema", "table_name"),
+	"information_schema.constraint_table_usage":  relationsFilter("table_catalog", "table_schema", "table_name"),
+	"information_schema.schemata":                schemasFilter("catalog_name", "schema_name"),
+	"information_schema.character_sets":          nil,
+	"pg_catalog.pg_class":                        oidsFilter("oid"),
+	"pg_catalog.pg_attribute":                    oidsFilter("attrelid"),
+	"pg_catalog.pg_attrdef":                      oidsFilter("adrelid"),
+	"pg_catalog.pg_constraint":                   oidsFilter("conrelid"),
+	"pg_catalog.pg_index":                        oidsFilter("indrelid"),
+	"pg_catalog.pg_description":                  oidsFilter("objoid"),
+	"pg_catalog.pg_tables":                       namesFilter("schemaname", "tablename"),
+	"pg_catalog.pg_views":                        namesFilter("schemaname", "viewname"),
+	"pg_catalog.pg_indexes":                      namesFilter("schemaname", "tablename"),
+	"pg_catalog.pg_namespace":                    schemasFilter("", "nspname"),
+	"pg_catalog.pg_type":                         nil,
+	"pg_catalog.pg_database":                     nil,
+	"pg_catalog.pg_am":                           nil,
+	"pg_catalog.pg_enum":                         nil,
+	"pg_catalog.pg_tablespace":                   nil,
+}
+
+// systemSchemas are listed by the filtered schema views whatever tables are visible
+var systemSchemas = []string{"main", "information_schema", "pg_catalog"}
+
+// catalogRelation is a table or view as DuckDB lists it in its catalog views
+type catalogRelation struct {
+	database, schema, name string
+	oid                    int64
+}
+
+// FilterCatalogViews returns query with its reads of DuckDB's catalog views, such as
+// information_schema.tables or pg_catalog.pg_class, replaced by reads of their rows about
+// the tables and views visible reports true for. DuckDB's own tables and views and the
+// views of other queries, such as those of time travel, are never visible. The views
+// that can be filtered are the query's CatalogViews; its other reads are left alone.
+func (e *Engine) FilterCatalogViews(ctx context.Context, query string, visible func(table.Identifier) (bool, error)) (string, error) {
+	tree, err := e.parseQuery(ctx, query)
+	if err != nil {
+		return "", err
+	}
+
+	type viewRef struct {
+		node map[string]interface{}
+		ref  tableRef
+		view string
+	}
+	var views []viewRef
+	e.mutex.RLock()
+	walkReferences(tree["statements"], nil, func(node map[string]interface{}, ref tableRef) {
+		if view, ok := catalogViewName(ref); ok && e.resolveTableRef(ref) == nil {
+			views = append(views, viewRef{node: node, ref: ref, view: view})
+		}
+	}, func(string) {})
+	e.mutex.RUnlock()
+	if len(views) == 0 {
+		return query, nil
+	}
+
+	relations, err := e.visibleRelations(ctx, visible)
+	if err != nil {
+		return "", err
+	}
+
+	// The references are replaced once the walk is done, since the subqueries read the
+	// views they replace
+	for _, view := range views {
+		condition := "true"
+		if filter := catalogViewFilters[view.view]; filter != nil {
+			condition = filter(relations)
+		}
+		alias := stringField(view.node, "alias")
+		if alias == "" {
+			alias = view.ref.name
+		}
+		subquery, err := e.parseQuery(ctx, fmt.Sprintf("SELECT * FROM (SELECT * FROM system.%s WHERE %s) AS %s",
+			view.view, condition, e.quoteName(alias)))
+		if err != nil {
+			return "", fmt.Errorf("failed to filter %s: %w", view.view, err)
+		}
+		statement := subquery["statements"].([]interface{})[0].(map[string]interface{})
+		from := statement["node"].(map[string]interface{})["from_table"].(map[string]interface{})
+		from["column_name_alias"] = view.node["column_name_alias"]
+
+		clear(view.node)
+		for key, value := range from {
+			view.node[key] = value
+		}
+	}
+
+	serialized, err := json.Marshal(tree)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode parse tree: %w", err)
+	}
+	var rewritten string
+	if err := e.db.QueryRowContext(ctx, "SELECT json_deserialize_sql(?::JSON)", string(serialized)).Scan(&rewritten); err != nil {
+		return "", fmt.Errorf("failed to rewrite query: %w", err)
+	}
+	return rewritten, nil
+}
+
+// catalogViewName returns the schema and name of the filterable catalog view a name in a
+// query reads, if any. information_schema views are only found by their schema, while
+// pg_catalog is also searched for unqualified names.
+func catalogViewName(ref tableRef) (string, bool) {
+	if ref.catalog != "" && !strings.EqualFold(ref.catalog, "system") {
+		return "", false
+	}
+	schema := strings.ToLower(ref.schema)
+	if schema == "" && ref.catalog == "" {
+		schema = "pg_catalog"
+	}
+	view := schema + "." + strings.ToLower(ref.name)
+	_, ok := catalogViewFilters[view]
+	return view, ok
+}
+
+// visibleRelations returns the tables and views of DuckDB's catalog that are names of
+// registered tables and views visible reports true for
+func (e *Engine) visibleRelations(ctx context.Context, visible func(table.Identifier) (bool, error)) ([]catalogRelation, error) {
+	rows, err := e.db.QueryContext(ctx, `
+		SELECT database_name, schema_name, table_name, table_oid FROM duckdb_tables() WHERE NOT internal
+		UNION ALL
+		SELECT database_name, schema_name, view_name, view_oid FROM duckdb_views() WHERE NOT internal`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list relations: %w", err)
+	}
+	defer rows.Close()
+
+	var relations []catalogRelation
+	for rows.Next() {
+		var relation catalogRelation
+		if err := rows.Scan(&relation.database, &relation.schema, &relation.name, &relation.oid); err != nil {
+			return nil, fmt.Errorf("failed to list relations: %w", err)
+		}
+		relations = append(relations, relation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list relations: %w", err)
+	}
+
+	visibleRelations := relations[:0]
+	for _, relation := range relations {
+		e.mutex.RLock()
+		identifier := e.resolveTableRef(tableRef{catalog: relation.database, schema: relation.schema, name: relation.name})
+		e.mutex.RUnlock()
+		if identifier == nil {
+			continue
+		}
+		ok, err := visible(identifier)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			visibleRelations = append(visibleRelations, relation)
+		}
+	}
+	return visibleRelations, nil
+}
+
+// relationsFilter keeps the rows whose database, schema and name columns name one of
+// the relations
+func relationsFilter(database, schema, name string) func([]catalogRelation) string {
+	return func(relations []catalogRelation) string {
+		var tuples []string
+		for _, relation := range relations {
+			tuples = append(tuples, fmt.Sprintf("(%s, %s, %s)",
+				quoteLiteral(relation.database), quoteLiteral(relation.schema), quoteLiteral(relation.name)))
+		}
+		return inCondition(fmt.Sprintf("(%s, %s, %s)", database, schema, name), tuples)
+	}
+}
+
+// namesFilter keeps the rows whose schema and name columns name one of the relations
+func namesFilter(schema, name string) func([]catalogRelation) string {
+	return func(relations []catalogRelation) string {
+		var tuples []string
+		for _, relation := range relations {
+			tuples = append(tuples, fmt.Sprintf("(%s, %s)", quoteLiteral(relation.schema), quoteLiteral(relation.name)))
+		}
+		return inCondition(fmt.Sprintf("(%s, %s)", schema, name), tuples)
+	}
+}
+
+// oidsFilter keeps the rows whose column holds the OID of one of the relations
+func oidsFilter(column string) func([]catalogRelation) string {
+	return func(relations []catalogRelation) string {
+		var oids []string
+		for _, relation := range relations {
+			oids = append(oids, strconv.FormatInt(relation.oid, 10))
+		}
+		return inCondition(column, oids)
+	}
+}
+
+// schemasFilter keeps the rows of DuckDB's own schemas and of the schemas holding one of
+// the relations. Without a database column, schemas are matched by name alone.
+func schemasFilter(database, schema string) func([]catalogRelation) string {
+	return func(relations []catalogRelation) string {
+		var names []string
+		for _, name := range systemSchemas {
+			names = append(names, quoteLiteral(name))
+		}
+		condition := inCondition(schema, names)
+
+		var schemas []string
+		for _, relation := range relations {
+			if database == "" {
+				schemas = append(schemas, quoteLiteral(relation.schema))
+			} else {
+				schemas = append(schemas, fmt.Sprintf("(%s, %s)", quoteLiteral(relation.database), quoteLiteral(relation.schema)))
+			}
+		}
+		if database == "" {
+			return condition + " OR " + inCondition(schema, schemas)
+		}
+		return condition + " OR " + inCondition(fmt.Sprintf("(%s, %s)", database, schema), schemas)
+	}
+}
+
+// inCondition returns the condition that an expression is one of values, which is false
+// for no values
+func inCondition(expr string, values []string) string {
+	if len(values) == 0 {
+		return "false"
+	}
+	return fmt.Sprintf("%s IN (%s)", expr, strings.Join(values, ", "))
+}
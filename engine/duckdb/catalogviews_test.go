@@ -0,0 +1,79 @@
+package duckdb
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"testing"
+
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterCatalogViews(t *testing.T) {
+	db, err := sql.Open("duckdb", "")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	engine := &Engine{db: db, config: DefaultEngineConfig(), registered: make(map[string]table.Identifier)}
+
+	// Registered tables are views in main and in a schema named after their namespace
+	ctx := context.Background()
+	for _, statement := range []string{
+		"CREATE TABLE sales_orders (id INTEGER)",
+		"CREATE TABLE crm_customers (id INTEGER, email VARCHAR)",
+		"CREATE SCHEMA sales",
+		"CREATE VIEW sales.orders AS SELECT * FROM main.sales_orders",
+		"CREATE SCHEMA crm",
+		"CREATE VIEW crm.customers AS SELECT * FROM main.crm_customers",
+		"CREATE TABLE unregistered (id INTEGER)",
+	} {
+		_, err := db.ExecContext(ctx, statement)
+		require.NoError(t, err, statement)
+	}
+	engine.recordNames(table.Identifier{"sales", "orders"}, "sales_orders")
+	engine.recordNames(table.Identifier{"crm", "customers"}, "crm_customers")
+	sales := func(identifier table.Identifier) (bool, error) {
+		return identifier[0] == "sales", nil
+	}
+
+	query := func(sql string, args ...interface{}) []string {
+		filtered, err := engine.FilterCatalogViews(ctx, sql, sales)
+		require.NoError(t, err)
+		rows, err := db.QueryContext(ctx, filtered, args...)
+		require.NoError(t, err, filtered)
+		defer rows.Close()
+		var values []string
+		for rows.Next() {
+			var value string
+			require.NoError(t, rows.Scan(&value))
+			values = append(values, value)
+		}
+		require.NoError(t, rows.Err())
+		slices.Sort(values)
+		return values
+	}
+
+	assert.Equal(t, []string{"main.sales_orders", "sales.orders"},
+		query("SELECT table_schema || '.' || table_name FROM information_schema.tables"))
+	assert.Equal(t, []string{"sales_orders"},
+		query("SELECT c.relname FROM pg_class c JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = $1", "main"))
+	assert.Equal(t, []string{"id"},
+		query("SELECT a.attname FROM pg_attribute a JOIN pg_class c ON c.oid = a.attrelid WHERE c.relkind = 'r'"))
+	assert.Equal(t, []string{"main", "sales"}, query("SELECT nspname FROM pg_namespace"))
+	assert.Equal(t, []string{"information_schema", "main", "pg_catalog", "sales"},
+		query("SELECT DISTINCT schema_name FROM information_schema.schemata"))
+	assert.Equal(t, []string{"sales_orders"}, query("SELECT t.name FROM pg_tables AS t(schema, name)"))
+	assert.Contains(t, query("SELECT typname FROM pg_type"), "int4")
+
+	// Queries without catalog views are left alone
+	filtered, err := engine.FilterCatalogViews(ctx, "SELECT * FROM crm_customers", sales)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM crm_customers", filtered)
+
+	refs, err := engine.ReferencedTables(ctx, "SELECT * FROM pg_class JOIN information_schema.tables ON true JOIN pg_settings ON true")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"pg_class", "information_schema.tables", "pg_settings"}, refs.Unresolved)
+	assert.ElementsMatch(t, []string{"pg_class", "information_schema.tables"}, refs.CatalogViews)
+}
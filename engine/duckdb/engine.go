@@ -3,19 +3,28 @@ package duckdb
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/TFMV/icebox/catalog"
+	"github.com/TFMV/icebox/config"
 	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/apache/iceberg-go/table"
 	_ "github.com/marcboeker/go-duckdb/v2"
 )
 
+// DatabaseName is the name DuckDB gives the engine's in-memory database, the catalog
+// that registered tables are queried in
+const DatabaseName = "memory"
+
 // Engine provides SQL query capabilities using DuckDB with native Iceberg support
 type Engine struct {
 	db               *sql.DB
@@ -27,6 +36,14 @@ type Engine struct {
 	metrics          *EngineMetrics
 	mutex            sync.RWMutex
 	icebergAvailable bool // Track if iceberg extension is available
+	catalogAttached  bool // The catalog's tables are queryable through the attached Iceberg catalog
+
+	// registered maps the lower-cased DuckDB names of registered tables and views,
+	// including their aliases, to their catalog identifiers
+	registered map[string]table.Identifier
+
+	// snapshotViews numbers the views ExecuteQueryAtSnapshot registers
+	snapshotViews atomic.Int64
 }
 
 // EngineConfig holds configuration options for the engine
@@ -37,6 +54,7 @@ type EngineConfig struct {
 	EnableOptimization bool
 	CacheSize          int
 	IcebergCatalogName string // Name for the attached Iceberg catalog in DuckDB
+	MaxResultRows      int64  // Rows ExecuteQuery collects before truncating; 0 for no limit
 }
 
 // EngineMetrics tracks engine performance metrics
@@ -52,13 +70,37 @@ type EngineMetrics struct {
 
 // QueryResult represents the result of a SQL query
 type QueryResult struct {
-	Columns  []string
-	Rows     [][]interface{}
-	Schema   *arrow.Schema
-	Table    arrow.Table
-	RowCount int64
-	Duration time.Duration
-	QueryID  string
+	Columns     []string
+	ColumnTypes []string // DuckDB type names of the columns, such as BIGINT or DECIMAL(18,3)
+	Schema      *arrow.Schema
+	Table       arrow.Table // The result rows as Arrow record batches
+	RowCount    int64
+	Truncated   bool // More rows were available than the engine's MaxResultRows
+	Duration    time.Duration
+	QueryID     string
+}
+
+// Rows returns the values of the result's rows as database/sql scans them. They are
+// converted from Table on every call, so callers that only need some values should
+// read Table instead.
+func (r *QueryResult) Rows() [][]interface{} {
+	rows := make([][]interface{}, 0, r.RowCount)
+	if r.Table == nil {
+		return rows
+	}
+	reader := array.NewTableReader(r.Table, -1)
+	defer reader.Release()
+	for reader.Next() {
+		record := reader.Record()
+		for i := 0; i < int(record.NumRows()); i++ {
+			row := make([]interface{}, record.NumCols())
+			for j, column := range record.Columns() {
+				row[j] = RowValue(column, i)
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
 }
 
 // DefaultEngineConfig returns a default configuration for the engine
@@ -70,6 +112,7 @@ func DefaultEngineConfig() *EngineConfig {
 		EnableOptimization: true,
 		CacheSize:          100,
 		IcebergCatalogName: "iceberg_catalog",
+		MaxResultRows:      100000,
 	}
 }
 
@@ -106,12 +149,13 @@ func NewEngineWithConfig(cat catalog.CatalogInterface, config *EngineConfig) (*E
 	db.SetConnMaxLifetime(time.Hour)
 
 	engine := &Engine{
-		db:        db,
-		catalog:   cat,
-		allocator: memory.NewGoAllocator(),
-		config:    config,
-		metrics:   &EngineMetrics{},
-		logger:    log.Default(),
+		db:         db,
+		catalog:    cat,
+		allocator:  memory.NewGoAllocator(),
+		config:     config,
+		metrics:    &EngineMetrics{},
+		logger:     log.Default(),
+		registered: make(map[string]table.Identifier),
 	}
 
 	// Initialize the engine with optimizations
@@ -154,6 +198,12 @@ func (e *Engine) initialize() error {
 		return fmt.Errorf("failed to initialize extensions: %w", err)
 	}
 
+	// Give httpfs the catalog's S3 credentials for s3:// table locations. Metadata
+	// tables still work without them, so a failure only produces a warning.
+	if err := e.configureS3(); err != nil {
+		e.logger.Printf("Warning: Failed to configure S3 access: %v", err)
+	}
+
 	// Attach the Iceberg catalog to DuckDB
 	if err := e.attachIcebergCatalog(); err != nil {
 		return fmt.Errorf("failed to attach Iceberg catalog: %w", err)
@@ -215,28 +265,24 @@ func (e *Engine) initializeExtensions() error {
 
 // attachIcebergCatalog attaches the Iceberg catalog to DuckDB using the appropriate method
 func (e *Engine) attachIcebergCatalog() error {
-	catalogName := e.config.IcebergCatalogName
-
 	// Determine the catalog type and create appropriate ATTACH statement
-	switch e.catalog.(type) {
+	switch cat := e.catalog.(type) {
 	case interface{ GetJSONConfig() interface{} }: // JSON catalog
 		// For JSON catalogs, we'll use file-based access
 		// This is a simplified approach. Might want to expose the JSON catalog as a REST endpoint
 		e.logger.Printf("Info: JSON catalog detected - using direct file access")
 		return nil // JSON catalogs don't need ATTACH - we'll handle them differently
 
-	case interface{ GetRESTConfig() interface{} }: // REST catalog
-		// For REST catalogs, use ATTACH with REST endpoint
-		attachSQL := fmt.Sprintf(`
-			ATTACH '%s' AS %s (
-				TYPE iceberg,
-				ENDPOINT_TYPE rest
-			)`, "rest_endpoint", catalogName) // You'd need to extract the actual REST endpoint
-
-		if _, err := e.db.Exec(attachSQL); err != nil {
-			return fmt.Errorf("failed to attach REST catalog: %w", err)
+	case interface{ GetRESTConfig() *config.RESTConfig }: // REST catalog
+		// The iceberg extension talks to REST catalogs itself. Without it, or when the
+		// catalog cannot be attached, tables are still registered through iceberg_scan.
+		if !e.icebergAvailable {
+			e.logger.Printf("Info: REST catalog detected - Iceberg extension unavailable, using direct file access")
+			return nil
+		}
+		if err := e.attachRESTCatalog(cat.GetRESTConfig()); err != nil {
+			e.logger.Printf("Warning: %v - using direct file access", err)
 		}
-		e.logger.Printf("Info: Attached REST catalog as '%s'", catalogName)
 
 	case interface{ GetSQLiteConfig() interface{} }: // SQLite catalog
 		// For SQLite catalogs, we'll use file-based access to the underlying data
@@ -291,22 +337,18 @@ func (e *Engine) GetConfig() *EngineConfig {
 		EnableOptimization: e.config.EnableOptimization,
 		CacheSize:          e.config.CacheSize,
 		IcebergCatalogName: e.config.IcebergCatalogName,
+		MaxResultRows:      e.config.MaxResultRows,
 	}
 }
 
-// ExecuteQuery executes a SQL query and returns the results
-func (e *Engine) ExecuteQuery(ctx context.Context, query string) (*QueryResult, error) {
+// ExecuteQuery executes a SQL query and returns the results. args bind the query's
+// placeholders, if any.
+func (e *Engine) ExecuteQuery(ctx context.Context, query string, args ...interface{}) (*QueryResult, error) {
 	if !e.initialized {
 		return nil, fmt.Errorf("engine not initialized")
 	}
 
-	// Update metrics and generate unique query ID for tracking
-	e.metrics.mu.Lock()
-	currentQueryCount := e.metrics.QueriesExecuted
-	e.metrics.QueriesExecuted++
-	e.metrics.mu.Unlock()
-
-	queryID := fmt.Sprintf("query_%d_%d", time.Now().UnixNano(), currentQueryCount)
+	queryID := e.newQueryID()
 
 	// Add query timeout if configured
 	if e.config.QueryTimeoutSec > 0 {
@@ -329,58 +371,58 @@ func (e *Engine) ExecuteQuery(ctx context.Context, query string) (*QueryResult,
 		return nil, fmt.Errorf("failed to preprocess query [%s]: %w", queryID, err)
 	}
 
-	// Execute the query with timeout context
-	rows, err := e.db.QueryContext(ctx, processedQuery)
-	if err != nil {
-		e.incrementErrorCount()
-		// Provide better error messages for common issues
-		if strings.Contains(err.Error(), "timeout") {
-			return nil, fmt.Errorf("query [%s] timed out after %ds: %w", queryID, e.config.QueryTimeoutSec, err)
-		}
-		if strings.Contains(err.Error(), "table") && strings.Contains(err.Error(), "not found") {
-			return nil, fmt.Errorf("table not found in query [%s]. Use 'SHOW TABLES' to see available tables: %w", queryID, err)
-		}
-		return nil, fmt.Errorf("failed to execute query [%s]: %w", queryID, err)
-	}
-	defer rows.Close()
-
-	// Get column information
-	columns, err := rows.Columns()
+	// Execute the query with timeout context, fetching its results as Arrow records
+	reader, err := e.queryRecords(ctx, processedQuery, args...)
 	if err != nil {
 		e.incrementErrorCount()
-		return nil, fmt.Errorf("failed to get columns for query [%s]: %w", queryID, err)
+		return nil, e.queryError(queryID, err)
 	}
+	defer reader.Release()
 
-	// Fetch all rows with memory management
-	var resultRows [][]interface{}
-	rowCount := int64(0)
+	schema := reader.Schema()
 
-	for rows.Next() {
-		// Memory management: limit result size for very large queries
-		if rowCount > 100000 { // 100k row limit
-			e.logger.Printf("Warning: Query [%s] result truncated at 100,000 rows", queryID)
-			break
+	// Keep the records up to the configured limit; ExecuteQueryStream serves larger results
+	var records []arrow.Record
+	defer func() {
+		for _, record := range records {
+			record.Release()
 		}
+	}()
+	rowCount := int64(0)
+	truncated := false
 
-		// Create slice to hold row values
-		values := make([]interface{}, len(columns))
-		valuePtrs := make([]interface{}, len(columns))
-		for i := range values {
-			valuePtrs[i] = &values[i]
+	for reader.Next() {
+		record := reader.Record()
+		if record.NumRows() == 0 {
+			continue
 		}
-
-		if err := rows.Scan(valuePtrs...); err != nil {
-			e.incrementErrorCount()
-			return nil, fmt.Errorf("failed to scan row %d in query [%s]: %w", rowCount, queryID, err)
+		if e.config.MaxResultRows > 0 && rowCount+record.NumRows() > e.config.MaxResultRows {
+			truncated = true
+			e.logger.Printf("Warning: Query [%s] result truncated at %d rows", queryID, e.config.MaxResultRows)
+			if remaining := e.config.MaxResultRows - rowCount; remaining > 0 {
+				records = append(records, record.NewSlice(0, remaining))
+				rowCount += remaining
+			}
+			break
 		}
 
-		resultRows = append(resultRows, values)
-		rowCount++
+		record.Retain()
+		records = append(records, record)
+		rowCount += record.NumRows()
 	}
 
-	if err := rows.Err(); err != nil {
+	if err := reader.Err(); err != nil {
 		e.incrementErrorCount()
-		return nil, fmt.Errorf("error iterating rows in query [%s]: %w", queryID, err)
+		return nil, e.queryError(queryID, err)
+	}
+
+	tbl := array.NewTableFromRecords(schema, records)
+
+	columns := make([]string, schema.NumFields())
+	typeNames := make([]string, schema.NumFields())
+	for i, field := range schema.Fields() {
+		columns[i] = field.Name
+		typeNames[i] = duckDBTypeName(field.Type)
 	}
 
 	duration := time.Since(start)
@@ -396,14 +438,38 @@ func (e *Engine) ExecuteQuery(ctx context.Context, query string) (*QueryResult,
 	}
 
 	return &QueryResult{
-		Columns:  columns,
-		Rows:     resultRows,
-		RowCount: rowCount,
-		Duration: duration,
-		QueryID:  queryID,
+		Columns:     columns,
+		ColumnTypes: typeNames,
+		Schema:      schema,
+		Table:       tbl,
+		RowCount:    rowCount,
+		Truncated:   truncated,
+		Duration:    duration,
+		QueryID:     queryID,
 	}, nil
 }
 
+// newQueryID counts a query in the metrics and returns a unique ID for tracking it
+func (e *Engine) newQueryID() string {
+	e.metrics.mu.Lock()
+	currentQueryCount := e.metrics.QueriesExecuted
+	e.metrics.QueriesExecuted++
+	e.metrics.mu.Unlock()
+
+	return fmt.Sprintf("query_%d_%d", time.Now().UnixNano(), currentQueryCount)
+}
+
+// queryError wraps an error executing a query, with better messages for common issues
+func (e *Engine) queryError(queryID string, err error) error {
+	if strings.Contains(err.Error(), "timeout") || errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("query [%s] timed out after %ds: %w", queryID, e.config.QueryTimeoutSec, err)
+	}
+	if strings.Contains(err.Error(), "table") && strings.Contains(err.Error(), "not found") {
+		return fmt.Errorf("table not found in query [%s]. Use 'SHOW TABLES' to see available tables: %w", queryID, err)
+	}
+	return fmt.Errorf("failed to execute query [%s]: %w", queryID, err)
+}
+
 // RegisterTable registers an Iceberg table for querying using DuckDB's native Iceberg support
 func (e *Engine) RegisterTable(ctx context.Context, identifier table.Identifier, icebergTable *table.Table) error {
 	if !e.initialized {
@@ -421,6 +487,7 @@ func (e *Engine) RegisterTable(ctx context.Context, identifier table.Identifier,
 	tableName := e.identifierToTableName(identifier)
 
 	start := time.Now()
+	e.recordNames(identifier, tableName)
 
 	// Check if Iceberg extension is available
 	if !e.icebergAvailable {
@@ -448,6 +515,10 @@ func (e *Engine) RegisterTable(ctx context.Context, identifier table.Identifier,
 				e.logger.Printf("Warning: Could not create alias %s for placeholder table %s: %v", simpleTableName, tableName, err)
 			}
 		}
+		e.createNamespaceViewOrWarn(ctx, identifier, tableName)
+
+		// Metadata tables only need the loaded metadata, so they work without the extension
+		e.registerMetadataTablesOrWarn(ctx, icebergTable, tableName, simpleTableName)
 
 		// Update metrics
 		e.metrics.mu.Lock()
@@ -468,12 +539,17 @@ func (e *Engine) RegisterTable(ctx context.Context, identifier table.Identifier,
 	// DuckDB v1.3.0 has significantly improved Iceberg support
 	// We can now use iceberg_scan directly with both SQLite and JSON catalogs
 	// Use the metadata location directly from the table object
-	metadataLocation := icebergTable.MetadataLocation()
+	source := fmt.Sprintf("iceberg_scan('%s')", icebergTable.MetadataLocation())
+	if e.catalogAttached && slices.Equal(identifier, icebergTable.Identifier()) {
+		// Catalog tables registered under their own name are read through the attached
+		// catalog; other registrations keep their metadata file
+		source = e.attachedTableName(identifier)
+	}
 
 	createViewSQL := fmt.Sprintf(`
 		CREATE OR REPLACE VIEW %s AS 
-		SELECT * FROM iceberg_scan('%s')
-	`, e.quoteName(tableName), metadataLocation)
+		SELECT * FROM %s
+	`, e.quoteName(tableName), source)
 
 	if _, err := e.db.Exec(createViewSQL); err != nil {
 		e.incrementErrorCount()
@@ -492,6 +568,10 @@ func (e *Engine) RegisterTable(ctx context.Context, identifier table.Identifier,
 			e.logger.Printf("Info: Created alias '%s' -> '%s'", simpleTableName, tableName)
 		}
 	}
+	e.createNamespaceViewOrWarn(ctx, identifier, tableName)
+
+	// Expose metadata tables such as "sales$snapshots" and "sales$files"
+	e.registerMetadataTablesOrWarn(ctx, icebergTable, tableName, simpleTableName)
 
 	// Update metrics
 	e.metrics.mu.Lock()
@@ -504,7 +584,42 @@ func (e *Engine) RegisterTable(ctx context.Context, identifier table.Identifier,
 	return nil
 }
 
-// ListTables returns a list of all registered tables
+// registerMetadataTablesOrWarn registers the metadata tables of a table under its full
+// name and, when different, its simple name. Failures only produce a warning since the
+// table itself is still queryable.
+func (e *Engine) registerMetadataTablesOrWarn(ctx context.Context, icebergTable *table.Table, tableName, simpleTableName string) {
+	names := []string{tableName}
+	if simpleTableName != tableName && simpleTableName != "" {
+		names = append(names, simpleTableName)
+	}
+
+	if err := e.registerMetadataTables(ctx, icebergTable, names...); err != nil {
+		e.logger.Printf("Warning: Could not create metadata tables for %s: %v", tableName, err)
+	}
+}
+
+// createNamespaceViewOrWarn makes a registered table or view queryable as
+// <namespace>.<name> too, through a view in a DuckDB schema named after its namespace.
+// Failures only produce a warning since the table itself is still queryable.
+func (e *Engine) createNamespaceViewOrWarn(ctx context.Context, identifier table.Identifier, name string) {
+	if len(identifier) < 2 {
+		return
+	}
+	schema := e.quoteName(strings.Join(identifier[:len(identifier)-1], "."))
+	statements := []string{
+		fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", schema),
+		fmt.Sprintf("CREATE OR REPLACE VIEW %s.%s AS SELECT * FROM main.%s",
+			schema, e.quoteName(identifier[len(identifier)-1]), e.quoteName(name)),
+	}
+	for _, statement := range statements {
+		if _, err := e.db.ExecContext(ctx, statement); err != nil {
+			e.logger.Printf("Warning: Could not create view %s.%s for %s: %v", schema, identifier[len(identifier)-1], name, err)
+			return
+		}
+	}
+}
+
+// ListTables returns a list of all registered tables, excluding metadata tables
 func (e *Engine) ListTables(ctx context.Context) ([]string, error) {
 	rows, err := e.db.QueryContext(ctx, "SHOW TABLES")
 	if err != nil {
@@ -518,6 +633,9 @@ func (e *Engine) ListTables(ctx context.Context) ([]string, error) {
 		if err := rows.Scan(&tableName); err != nil {
 			return nil, fmt.Errorf("failed to scan table name: %w", err)
 		}
+		if IsMetadataTableName(tableName) {
+			continue
+		}
 		tables = append(tables, tableName)
 	}
 
@@ -563,9 +681,14 @@ func (e *Engine) identifierToTableName(identifier table.Identifier) string {
 	return strings.Join(identifier, "_")
 }
 
+// attachedTableName returns the qualified name of a table in the attached Iceberg
+// catalog, where namespaces are schemas
+func (e *Engine) attachedTableName(identifier table.Identifier) string {
+	namespace := strings.Join(identifier[:len(identifier)-1], ".")
+	return e.quoteName(e.config.IcebergCatalogName) + "." + e.quoteName(namespace) + "." + e.quoteName(identifier[len(identifier)-1])
+}
+
 // quoteName quotes a SQL identifier to make it safe for use in queries
 func (e *Engine) quoteName(name string) string {
-	// Escape any existing quotes by doubling them
-	escaped := strings.ReplaceAll(name, `"`, `""`)
-	return fmt.Sprintf(`"%s"`, escaped)
+	return quoteIdentifier(name)
 }
@@ -726,10 +726,10 @@ func TestEngineWithoutIcebergExtension(t *testing.T) {
 	}
 
 	// Check that the error message is present
-	if len(result.Rows) > 0 && len(result.Rows[0]) > 0 {
-		errorMsg, ok := result.Rows[0][0].(string)
+	if len(result.Rows()) > 0 && len(result.Rows()[0]) > 0 {
+		errorMsg, ok := result.Rows()[0][0].(string)
 		if !ok || errorMsg != "Iceberg extension not available on this platform" {
-			t.Errorf("Expected error message in first column, got: %v", result.Rows[0][0])
+			t.Errorf("Expected error message in first column, got: %v", result.Rows()[0][0])
 		}
 	}
 }
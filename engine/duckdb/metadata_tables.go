@@ -0,0 +1,590 @@
+package duckdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+)
+
+// MetadataTableSeparator separates a table name from a metadata table name, as in "sales$files"
+const MetadataTableSeparator = "$"
+
+// MetadataTableNames lists the metadata tables registered for every Iceberg table
+var MetadataTableNames = []string{
+	"snapshots",
+	"history",
+	"manifests",
+	"files",
+	"partitions",
+	"refs",
+	"metadata_log_entries",
+}
+
+// IsMetadataTableName reports whether name refers to a metadata table, such as "sales$files"
+func IsMetadataTableName(name string) bool {
+	idx := strings.LastIndex(name, MetadataTableSeparator)
+	if idx <= 0 {
+		return false
+	}
+	return slices.Contains(MetadataTableNames, name[idx+1:])
+}
+
+// metadataColumn is a column of a metadata table. Columns with a placeholder other
+// than "?" consume one argument per "?" in it.
+type metadataColumn struct {
+	name        string
+	sqlType     string
+	placeholder string
+}
+
+// metadataTable is a metadata table built from Iceberg table metadata
+type metadataTable struct {
+	name    string
+	columns []metadataColumn
+	rows    [][]interface{}
+}
+
+// mapValue is a MAP cell, bound as separate key and value lists
+type mapValue struct {
+	keys   interface{}
+	values interface{}
+}
+
+func col(name, sqlType string) metadataColumn {
+	return metadataColumn{name: name, sqlType: sqlType, placeholder: "?"}
+}
+
+func mapCol(name, keyType, valueType string) metadataColumn {
+	return metadataColumn{
+		name:        name,
+		sqlType:     fmt.Sprintf("MAP(%s, %s)", keyType, valueType),
+		placeholder: fmt.Sprintf("MAP(?::%s[], ?::%s[])", keyType, valueType),
+	}
+}
+
+// registerMetadataTables creates the metadata tables of an Iceberg table under each of
+// the given names, e.g. "default_sales$files" and "sales$files". The tables are
+// snapshots of the metadata at registration time and are rebuilt on re-registration.
+func (e *Engine) registerMetadataTables(ctx context.Context, icebergTable *table.Table, tableNames ...string) error {
+	tables, err := buildMetadataTables(icebergTable)
+	if err != nil {
+		return err
+	}
+
+	tx, err := e.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, mt := range tables {
+		primary := tableNames[0] + MetadataTableSeparator + mt.name
+
+		var columnDefs, placeholders []string
+		for _, c := range mt.columns {
+			columnDefs = append(columnDefs, fmt.Sprintf("%s %s", e.quoteName(c.name), c.sqlType))
+			placeholders = append(placeholders, c.placeholder)
+		}
+
+		createSQL := fmt.Sprintf("CREATE OR REPLACE TABLE %s (%s)", e.quoteName(primary), strings.Join(columnDefs, ", "))
+		if _, err := tx.ExecContext(ctx, createSQL); err != nil {
+			return fmt.Errorf("failed to create metadata table %s: %w", primary, err)
+		}
+
+		if len(mt.rows) > 0 {
+			insertSQL := fmt.Sprintf("INSERT INTO %s VALUES (%s)", e.quoteName(primary), strings.Join(placeholders, ", "))
+			stmt, err := tx.PrepareContext(ctx, insertSQL)
+			if err != nil {
+				return fmt.Errorf("failed to prepare insert into metadata table %s: %w", primary, err)
+			}
+			for _, row := range mt.rows {
+				if _, err := stmt.ExecContext(ctx, bindArgs(row)...); err != nil {
+					stmt.Close()
+					return fmt.Errorf("failed to populate metadata table %s: %w", primary, err)
+				}
+			}
+			stmt.Close()
+		}
+
+		// Aliases are views, matching how the table itself is aliased
+		for _, alias := range tableNames[1:] {
+			aliasSQL := fmt.Sprintf("CREATE OR REPLACE VIEW %s AS SELECT * FROM %s",
+				e.quoteName(alias+MetadataTableSeparator+mt.name), e.quoteName(primary))
+			if _, err := tx.ExecContext(ctx, aliasSQL); err != nil {
+				return fmt.Errorf("failed to create alias for metadata table %s: %w", primary, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit metadata tables: %w", err)
+	}
+	return nil
+}
+
+// bindArgs flattens MAP cells into their key and value lists
+func bindArgs(row []interface{}) []interface{} {
+	args := make([]interface{}, 0, len(row))
+	for _, value := range row {
+		if m, ok := value.(mapValue); ok {
+			args = append(args, m.keys, m.values)
+			continue
+		}
+		args = append(args, value)
+	}
+	return args
+}
+
+// buildMetadataTables builds all metadata tables from the table's loaded metadata.
+// Manifest-based tables describe the current snapshot.
+func buildMetadataTables(tbl *table.Table) ([]metadataTable, error) {
+	metadata := tbl.Metadata()
+
+	var (
+		manifests []iceberg.ManifestFile
+		entries   []iceberg.ManifestEntry
+	)
+	if current := tbl.CurrentSnapshot(); current != nil {
+		var err error
+		manifests, err = current.Manifests(tbl.FS())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest list: %w", err)
+		}
+		for _, manifest := range manifests {
+			manifestEntries, err := manifest.FetchEntries(tbl.FS(), true)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read manifest %s: %w", manifest.FilePath(), err)
+			}
+			entries = append(entries, manifestEntries...)
+		}
+	}
+
+	return []metadataTable{
+		snapshotsTable(metadata),
+		historyTable(metadata),
+		manifestsTable(manifests),
+		filesTable(metadata, entries),
+		partitionsTable(metadata, entries),
+		refsTable(metadata),
+		metadataLogEntriesTable(tbl),
+	}, nil
+}
+
+func snapshotsTable(metadata table.Metadata) metadataTable {
+	mt := metadataTable{
+		name: "snapshots",
+		columns: []metadataColumn{
+			col("committed_at", "TIMESTAMPTZ"),
+			col("snapshot_id", "BIGINT"),
+			col("parent_id", "BIGINT"),
+			col("operation", "VARCHAR"),
+			col("manifest_list", "VARCHAR"),
+			mapCol("summary", "VARCHAR", "VARCHAR"),
+		},
+	}
+
+	for _, snapshot := range metadata.Snapshots() {
+		var operation interface{}
+		keys, values := []string{}, []string{}
+		if snapshot.Summary != nil {
+			operation = string(snapshot.Summary.Operation)
+			for _, k := range sortedKeys(snapshot.Summary.Properties) {
+				keys = append(keys, k)
+				values = append(values, snapshot.Summary.Properties[k])
+			}
+		}
+		mt.rows = append(mt.rows, []interface{}{
+			time.UnixMilli(snapshot.TimestampMs),
+			snapshot.SnapshotID,
+			nullableInt64(snapshot.ParentSnapshotID),
+			operation,
+			snapshot.ManifestList,
+			mapValue{keys: keys, values: values},
+		})
+	}
+	return mt
+}
+
+func historyTable(metadata table.Metadata) metadataTable {
+	mt := metadataTable{
+		name: "history",
+		columns: []metadataColumn{
+			col("made_current_at", "TIMESTAMPTZ"),
+			col("snapshot_id", "BIGINT"),
+			col("parent_id", "BIGINT"),
+			col("is_current_ancestor", "BOOLEAN"),
+		},
+	}
+
+	ancestors := make(map[int64]bool)
+	for snapshot := metadata.CurrentSnapshot(); snapshot != nil; {
+		ancestors[snapshot.SnapshotID] = true
+		if snapshot.ParentSnapshotID == nil {
+			break
+		}
+		snapshot = metadata.SnapshotByID(*snapshot.ParentSnapshotID)
+	}
+
+	for entry := range metadata.SnapshotLogs() {
+		var parentID interface{}
+		if snapshot := metadata.SnapshotByID(entry.SnapshotID); snapshot != nil {
+			parentID = nullableInt64(snapshot.ParentSnapshotID)
+		}
+		mt.rows = append(mt.rows, []interface{}{
+			time.UnixMilli(entry.TimestampMs),
+			entry.SnapshotID,
+			parentID,
+			ancestors[entry.SnapshotID],
+		})
+	}
+	return mt
+}
+
+func manifestsTable(manifests []iceberg.ManifestFile) metadataTable {
+	mt := metadataTable{
+		name: "manifests",
+		columns: []metadataColumn{
+			col("content", "INTEGER"),
+			col("path", "VARCHAR"),
+			col("length", "BIGINT"),
+			col("partition_spec_id", "INTEGER"),
+			col("added_snapshot_id", "BIGINT"),
+			col("added_data_files_count", "INTEGER"),
+			col("existing_data_files_count", "INTEGER"),
+			col("deleted_data_files_count", "INTEGER"),
+			col("added_delete_files_count", "INTEGER"),
+			col("existing_delete_files_count", "INTEGER"),
+			col("deleted_delete_files_count", "INTEGER"),
+		},
+	}
+
+	for _, manifest := range manifests {
+		// Manifest file counts refer to delete files in delete manifests
+		var dataCounts, deleteCounts [3]int32
+		counts := [3]int32{manifest.AddedDataFiles(), manifest.ExistingDataFiles(), manifest.DeletedDataFiles()}
+		if manifest.ManifestContent() == iceberg.ManifestContentDeletes {
+			deleteCounts = counts
+		} else {
+			dataCounts = counts
+		}
+
+		mt.rows = append(mt.rows, []interface{}{
+			int32(manifest.ManifestContent()),
+			manifest.FilePath(),
+			manifest.Length(),
+			manifest.PartitionSpecID(),
+			manifest.SnapshotID(),
+			dataCounts[0], dataCounts[1], dataCounts[2],
+			deleteCounts[0], deleteCounts[1], deleteCounts[2],
+		})
+	}
+	return mt
+}
+
+func filesTable(metadata table.Metadata, entries []iceberg.ManifestEntry) metadataTable {
+	mt := metadataTable{
+		name: "files",
+		columns: []metadataColumn{
+			col("content", "INTEGER"),
+			col("file_path", "VARCHAR"),
+			col("file_format", "VARCHAR"),
+			col("spec_id", "INTEGER"),
+			col("partition", "VARCHAR"),
+			col("record_count", "BIGINT"),
+			col("file_size_in_bytes", "BIGINT"),
+			mapCol("column_sizes", "INTEGER", "BIGINT"),
+			mapCol("value_counts", "INTEGER", "BIGINT"),
+			mapCol("null_value_counts", "INTEGER", "BIGINT"),
+			mapCol("nan_value_counts", "INTEGER", "BIGINT"),
+			mapCol("lower_bounds", "INTEGER", "VARCHAR"),
+			mapCol("upper_bounds", "INTEGER", "VARCHAR"),
+			col("split_offsets", "BIGINT[]"),
+			col("sort_order_id", "INTEGER"),
+		},
+	}
+
+	schema := metadata.CurrentSchema()
+	for _, entry := range entries {
+		df := entry.DataFile()
+
+		var sortOrderID interface{}
+		if id := df.SortOrderID(); id != nil {
+			sortOrderID = int32(*id)
+		}
+		splitOffsets := df.SplitOffsets()
+		if splitOffsets == nil {
+			splitOffsets = []int64{}
+		}
+
+		mt.rows = append(mt.rows, []interface{}{
+			int32(df.ContentType()),
+			df.FilePath(),
+			string(df.FileFormat()),
+			df.SpecID(),
+			partitionString(metadata, df),
+			df.Count(),
+			df.FileSizeBytes(),
+			countsMap(df.ColumnSizes()),
+			countsMap(df.ValueCounts()),
+			countsMap(df.NullValueCounts()),
+			countsMap(df.NaNValueCounts()),
+			boundsMap(schema, df.LowerBoundValues()),
+			boundsMap(schema, df.UpperBoundValues()),
+			splitOffsets,
+			sortOrderID,
+		})
+	}
+	return mt
+}
+
+func partitionsTable(metadata table.Metadata, entries []iceberg.ManifestEntry) metadataTable {
+	mt := metadataTable{
+		name: "partitions",
+		columns: []metadataColumn{
+			col("partition", "VARCHAR"),
+			col("spec_id", "INTEGER"),
+			col("record_count", "BIGINT"),
+			col("file_count", "INTEGER"),
+			col("total_data_file_size_in_bytes", "BIGINT"),
+			col("position_delete_record_count", "BIGINT"),
+			col("position_delete_file_count", "INTEGER"),
+			col("equality_delete_record_count", "BIGINT"),
+			col("equality_delete_file_count", "INTEGER"),
+			col("last_updated_at", "TIMESTAMPTZ"),
+			col("last_updated_snapshot_id", "BIGINT"),
+		},
+	}
+
+	type partitionStats struct {
+		partition            string
+		specID               int32
+		recordCount          int64
+		fileCount            int32
+		totalSize            int64
+		posDeleteRecords     int64
+		posDeleteFiles       int32
+		eqDeleteRecords      int64
+		eqDeleteFiles        int32
+		lastUpdatedSnapshot  int64
+		lastUpdatedTimestamp int64
+	}
+
+	byKey := make(map[string]*partitionStats)
+	var keys []string
+	for _, entry := range entries {
+		df := entry.DataFile()
+		partition := partitionString(metadata, df)
+		key := fmt.Sprintf("%d/%s", df.SpecID(), partition)
+
+		stats, ok := byKey[key]
+		if !ok {
+			stats = &partitionStats{partition: partition, specID: df.SpecID()}
+			byKey[key] = stats
+			keys = append(keys, key)
+		}
+
+		switch df.ContentType() {
+		case iceberg.EntryContentPosDeletes:
+			stats.posDeleteRecords += df.Count()
+			stats.posDeleteFiles++
+		case iceberg.EntryContentEqDeletes:
+			stats.eqDeleteRecords += df.Count()
+			stats.eqDeleteFiles++
+		default:
+			stats.recordCount += df.Count()
+			stats.fileCount++
+			stats.totalSize += df.FileSizeBytes()
+		}
+
+		if snapshot := metadata.SnapshotByID(entry.SnapshotID()); snapshot != nil && snapshot.TimestampMs >= stats.lastUpdatedTimestamp {
+			stats.lastUpdatedTimestamp = snapshot.TimestampMs
+			stats.lastUpdatedSnapshot = snapshot.SnapshotID
+		}
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		stats := byKey[key]
+		var lastUpdatedAt, lastUpdatedSnapshot interface{}
+		if stats.lastUpdatedSnapshot != 0 {
+			lastUpdatedAt = time.UnixMilli(stats.lastUpdatedTimestamp)
+			lastUpdatedSnapshot = stats.lastUpdatedSnapshot
+		}
+		mt.rows = append(mt.rows, []interface{}{
+			stats.partition,
+			stats.specID,
+			stats.recordCount,
+			stats.fileCount,
+			stats.totalSize,
+			stats.posDeleteRecords,
+			stats.posDeleteFiles,
+			stats.eqDeleteRecords,
+			stats.eqDeleteFiles,
+			lastUpdatedAt,
+			lastUpdatedSnapshot,
+		})
+	}
+	return mt
+}
+
+func refsTable(metadata table.Metadata) metadataTable {
+	mt := metadataTable{
+		name: "refs",
+		columns: []metadataColumn{
+			col("name", "VARCHAR"),
+			col("type", "VARCHAR"),
+			col("snapshot_id", "BIGINT"),
+			col("max_reference_age_in_ms", "BIGINT"),
+			col("min_snapshots_to_keep", "INTEGER"),
+			col("max_snapshot_age_in_ms", "BIGINT"),
+		},
+	}
+
+	refs := make(map[string]table.SnapshotRef)
+	for name, ref := range metadata.Refs() {
+		refs[name] = ref
+	}
+	for _, name := range sortedKeys(refs) {
+		ref := refs[name]
+		var minSnapshotsToKeep interface{}
+		if ref.MinSnapshotsToKeep != nil {
+			minSnapshotsToKeep = int32(*ref.MinSnapshotsToKeep)
+		}
+		mt.rows = append(mt.rows, []interface{}{
+			name,
+			strings.ToUpper(string(ref.SnapshotRefType)),
+			ref.SnapshotID,
+			nullableInt64(ref.MaxRefAgeMs),
+			minSnapshotsToKeep,
+			nullableInt64(ref.MaxSnapshotAgeMs),
+		})
+	}
+	return mt
+}
+
+func metadataLogEntriesTable(tbl *table.Table) metadataTable {
+	mt := metadataTable{
+		name: "metadata_log_entries",
+		columns: []metadataColumn{
+			col("timestamp", "TIMESTAMPTZ"),
+			col("file", "VARCHAR"),
+			col("latest_snapshot_id", "BIGINT"),
+			col("latest_schema_id", "INTEGER"),
+			col("latest_sequence_number", "BIGINT"),
+		},
+	}
+
+	metadata := tbl.Metadata()
+	var snapshotLog []table.SnapshotLogEntry
+	for entry := range metadata.SnapshotLogs() {
+		snapshotLog = append(snapshotLog, entry)
+	}
+
+	// The latest snapshot of a metadata file is the last one made current before it was written
+	addEntry := func(file string, timestampMs int64) {
+		var snapshotID, schemaID, sequenceNumber interface{}
+		for _, entry := range snapshotLog {
+			if entry.TimestampMs > timestampMs {
+				continue
+			}
+			if snapshot := metadata.SnapshotByID(entry.SnapshotID); snapshot != nil {
+				snapshotID = snapshot.SnapshotID
+				sequenceNumber = snapshot.SequenceNumber
+				schemaID = nil
+				if snapshot.SchemaID != nil {
+					schemaID = int32(*snapshot.SchemaID)
+				}
+			}
+		}
+		mt.rows = append(mt.rows, []interface{}{
+			time.UnixMilli(timestampMs),
+			file,
+			snapshotID,
+			schemaID,
+			sequenceNumber,
+		})
+	}
+
+	for entry := range metadata.PreviousFiles() {
+		addEntry(entry.MetadataFile, entry.TimestampMs)
+	}
+	addEntry(tbl.MetadataLocation(), metadata.LastUpdatedMillis())
+	return mt
+}
+
+// partitionString renders a data file's partition as JSON keyed by partition field name
+func partitionString(metadata table.Metadata, df iceberg.DataFile) string {
+	values := df.Partition()
+	if len(values) == 0 {
+		return "{}"
+	}
+
+	named := make(map[string]interface{}, len(values))
+	for _, spec := range metadata.PartitionSpecs() {
+		if int32(spec.ID()) != df.SpecID() {
+			continue
+		}
+		for field := range spec.Fields() {
+			if value, ok := values[field.FieldID]; ok {
+				named[field.Name] = value
+			}
+		}
+	}
+
+	data, err := json.Marshal(named)
+	if err != nil {
+		return fmt.Sprintf("%v", named)
+	}
+	return string(data)
+}
+
+// countsMap converts per-column counts to a MAP cell
+func countsMap(counts map[int]int64) mapValue {
+	keys, values := []int32{}, []int64{}
+	for _, id := range sortedKeys(counts) {
+		keys = append(keys, int32(id))
+		values = append(values, counts[id])
+	}
+	return mapValue{keys: keys, values: values}
+}
+
+// boundsMap decodes per-column bounds into a MAP cell of readable values
+func boundsMap(schema *iceberg.Schema, bounds map[int][]byte) mapValue {
+	keys, values := []int32{}, []string{}
+	for _, id := range sortedKeys(bounds) {
+		field, ok := schema.FindFieldByID(id)
+		if !ok {
+			continue
+		}
+		lit, err := iceberg.LiteralFromBytes(field.Type, bounds[id])
+		if err != nil {
+			continue
+		}
+		keys = append(keys, int32(id))
+		values = append(values, lit.String())
+	}
+	return mapValue{keys: keys, values: values}
+}
+
+func nullableInt64(v *int64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func sortedKeys[K int | string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
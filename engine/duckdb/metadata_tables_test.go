@@ -0,0 +1,157 @@
+package duckdb
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/TFMV/icebox/catalog/sqlite"
+	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/tableops"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadataTables(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		Name: "test-catalog",
+		Catalog: config.CatalogConfig{
+			Type: "sqlite",
+			SQLite: &config.SQLiteConfig{
+				Path: filepath.Join(tempDir, "test.db"),
+			},
+		},
+		Storage: config.StorageConfig{
+			FileSystem: &config.FileSystemConfig{
+				RootPath: tempDir,
+			},
+		},
+	}
+
+	cat, err := sqlite.NewCatalog(cfg)
+	require.NoError(t, err)
+	defer cat.Close()
+
+	engine, err := NewEngine(cat)
+	require.NoError(t, err)
+	defer engine.Close()
+
+	// Metadata tables are built from loaded metadata and do not need the extension
+	engine.icebergAvailable = false
+
+	ctx := context.Background()
+	require.NoError(t, cat.CreateNamespace(ctx, table.Identifier{"sales"}, iceberg.Properties{}))
+
+	tableID := table.Identifier{"sales", "orders"}
+	schema := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64},
+		iceberg.NestedField{ID: 2, Name: "amount", Type: iceberg.PrimitiveTypes.Float64},
+	)
+	_, err = cat.CreateTable(ctx, tableID, schema)
+	require.NoError(t, err)
+
+	// Two appends give two snapshots, each adding a manifest and a data file
+	writer := tableops.NewWriter(cat)
+	arrowSchema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "amount", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}, nil)
+	for i := 0; i < 2; i++ {
+		idBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+		idBuilder.AppendValues([]int64{int64(i*2 + 1), int64(i*2 + 2)}, nil)
+		amountBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+		amountBuilder.AppendValues([]float64{1.5, 2.5}, nil)
+		ids, amounts := idBuilder.NewArray(), amountBuilder.NewArray()
+		idBuilder.Release()
+		amountBuilder.Release()
+
+		record := array.NewRecord(arrowSchema, []arrow.Array{ids, amounts}, 2)
+		arrowTable := array.NewTableFromRecords(arrowSchema, []arrow.Record{record})
+
+		tbl, err := cat.LoadTable(ctx, tableID, nil)
+		require.NoError(t, err)
+		require.NoError(t, writer.WriteArrowTable(ctx, tbl, arrowTable, nil))
+
+		arrowTable.Release()
+		record.Release()
+		ids.Release()
+		amounts.Release()
+	}
+
+	icebergTable, err := cat.LoadTable(ctx, tableID, nil)
+	require.NoError(t, err)
+	require.NoError(t, engine.RegisterTable(ctx, tableID, icebergTable))
+	currentID := icebergTable.CurrentSnapshot().SnapshotID
+
+	// Every metadata table is queryable under the full and the simple name
+	for _, name := range MetadataTableNames {
+		for _, prefix := range []string{"sales_orders", "orders"} {
+			_, err := engine.ExecuteQuery(ctx, `SELECT * FROM "`+prefix+`$`+name+`"`)
+			assert.NoError(t, err, "querying %s$%s", prefix, name)
+		}
+	}
+
+	result, err := engine.ExecuteQuery(ctx, `SELECT snapshot_id, parent_id, operation, summary['added-records'] FROM "orders$snapshots" ORDER BY committed_at`)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), result.RowCount)
+	assert.Nil(t, result.Rows()[0][1])
+	assert.Equal(t, result.Rows()[0][0], result.Rows()[1][1])
+	assert.Equal(t, currentID, result.Rows()[1][0])
+	assert.Equal(t, "append", result.Rows()[1][2])
+	assert.Equal(t, "2", result.Rows()[1][3])
+
+	result, err = engine.ExecuteQuery(ctx, `SELECT count(*) FROM "orders$history" WHERE is_current_ancestor`)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.Rows()[0][0])
+
+	result, err = engine.ExecuteQuery(ctx, `SELECT sum(added_data_files_count)::BIGINT FROM "orders$manifests"`)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.Rows()[0][0])
+
+	result, err = engine.ExecuteQuery(ctx, `SELECT record_count, null_value_counts[1], lower_bounds[1], upper_bounds[1] FROM "orders$files" ORDER BY lower_bounds[1]`)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), result.RowCount)
+	assert.Equal(t, int64(2), result.Rows()[0][0])
+	assert.Equal(t, int64(0), result.Rows()[0][1])
+	assert.Equal(t, "1", result.Rows()[0][2])
+	assert.Equal(t, "4", result.Rows()[1][3])
+
+	result, err = engine.ExecuteQuery(ctx, `SELECT partition, record_count, file_count, last_updated_snapshot_id FROM "orders$partitions"`)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), result.RowCount)
+	assert.Equal(t, "{}", result.Rows()[0][0])
+	assert.Equal(t, int64(4), result.Rows()[0][1])
+	assert.Equal(t, int32(2), result.Rows()[0][2])
+	assert.Equal(t, currentID, result.Rows()[0][3])
+
+	result, err = engine.ExecuteQuery(ctx, `SELECT name, type, snapshot_id FROM "orders$refs"`)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), result.RowCount)
+	assert.Equal(t, []interface{}{"main", "BRANCH", currentID}, result.Rows()[0])
+
+	result, err = engine.ExecuteQuery(ctx, `SELECT file, latest_snapshot_id FROM "orders$metadata_log_entries" ORDER BY timestamp`)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, result.RowCount, int64(3))
+	last := result.Rows()[len(result.Rows())-1]
+	assert.Equal(t, icebergTable.MetadataLocation(), last[0])
+	assert.Equal(t, currentID, last[1])
+
+	// Metadata tables are not listed as tables
+	tables, err := engine.ListTables(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"sales_orders", "orders"}, tables)
+}
+
+func TestIsMetadataTableName(t *testing.T) {
+	assert.True(t, IsMetadataTableName("sales$files"))
+	assert.True(t, IsMetadataTableName("default_sales$metadata_log_entries"))
+	assert.False(t, IsMetadataTableName("sales"))
+	assert.False(t, IsMetadataTableName("$files"))
+	assert.False(t, IsMetadataTableName("price$usd"))
+}
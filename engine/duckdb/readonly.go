@@ -0,0 +1,53 @@
+package duckdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotReadOnly is returned by CheckReadOnly for statements that may change data or
+// settings, or read outside the catalog
+var ErrNotReadOnly = errors.New("statement is not allowed in read-only mode")
+
+// safeTableFunctions are the table functions that generate rows rather than read files,
+// other databases or DuckDB's state. Most duckdb_* functions describe the tables, views
+// and secrets DuckDB holds, so only those listing fixed facts about DuckDB itself are safe.
+var safeTableFunctions = map[string]bool{
+	"range":             true,
+	"generate_series":   true,
+	"unnest":            true,
+	"json_each":         true,
+	"json_tree":         true,
+	"duckdb_keywords":   true,
+	"duckdb_optimizers": true,
+}
+
+// IsSafeTableFunction reports whether a table function only generates rows, so that
+// calling it touches no files or other databases and reveals nothing DuckDB holds
+func IsSafeTableFunction(name string) bool {
+	return safeTableFunctions[strings.ToLower(name)]
+}
+
+// CheckReadOnly returns ErrNotReadOnly unless every statement of the query is a query
+// that only reads the catalog. The statements are classified from DuckDB's parse tree,
+// so writes hidden behind a CTE or a second statement, COPY, ATTACH, INSTALL and SET are
+// all rejected, as are queries reading files or URLs through table functions or
+// replacement scans and queries reading names that are not catalog tables, such as
+// information_schema or duckdb_views. Queries that fail to parse return the parser's
+// error.
+func (e *Engine) CheckReadOnly(ctx context.Context, query string) error {
+	refs, err := e.ReferencedTables(ctx, query)
+	if errors.Is(err, ErrUnresolvableStatement) {
+		return fmt.Errorf("%w: only queries can run", ErrNotReadOnly)
+	}
+	if err != nil {
+		return err
+	}
+
+	if external := refs.External(); len(external) > 0 {
+		return fmt.Errorf("%w: the query reads %s", ErrNotReadOnly, strings.Join(external, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package duckdb
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckReadOnly(t *testing.T) {
+	db, err := sql.Open("duckdb", "")
+	require.NoError(t, err)
+	defer db.Close()
+	engine := &Engine{db: db, config: DefaultEngineConfig(), registered: make(map[string]table.Identifier)}
+	engine.recordNames(table.Identifier{"sales", "orders"}, "sales_orders")
+
+	ctx := context.Background()
+	allowed := []string{
+		"SELECT * FROM orders",
+		"  select count(*) from sales_orders; SELECT 1",
+		"WITH recent AS (SELECT * FROM orders) SELECT * FROM recent",
+		"DESCRIBE orders",
+		"SELECT * FROM range(10)",
+		"SELECT * FROM duckdb_keywords()",
+	}
+	for _, query := range allowed {
+		assert.NoError(t, engine.CheckReadOnly(ctx, query), query)
+	}
+
+	rejected := []string{
+		"INSERT INTO orders VALUES (1)",
+		"WITH x AS (SELECT 1 AS id) INSERT INTO orders SELECT * FROM x",
+		"SELECT 1; DROP TABLE orders",
+		"COPY orders TO 'orders.csv'",
+		"ATTACH 'other.db' AS other",
+		"INSTALL httpfs",
+		"SET memory_limit = '1GB'",
+		"CREATE TABLE copy AS SELECT * FROM orders",
+		"EXPLAIN ANALYZE DELETE FROM orders",
+		"SELECT * FROM read_parquet('data.parquet')",
+		"SELECT * FROM read_text('/etc/passwd')",
+		"SELECT * FROM 'data/orders.csv'",
+		"FROM 's3://bucket/orders.parquet'",
+		"SELECT * FROM duckdb_tables()",
+		"SELECT sql FROM duckdb_views()",
+		"SELECT * FROM duckdb_secrets()",
+		"SELECT * FROM duckdb_views",
+		"SELECT * FROM information_schema.tables",
+		"SELECT relname FROM pg_catalog.pg_class",
+		"SELECT * FROM orders JOIN duckdb_settings ON true",
+	}
+	for _, query := range rejected {
+		assert.ErrorIs(t, engine.CheckReadOnly(ctx, query), ErrNotReadOnly, query)
+	}
+
+	err = engine.CheckReadOnly(ctx, "SELEC 1")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNotReadOnly)
+}
@@ -0,0 +1,230 @@
+package duckdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/apache/iceberg-go/table"
+)
+
+// ErrUnresolvableStatement is returned by ReferencedTables for statements other than
+// queries, whose parse tree DuckDB cannot serialize
+var ErrUnresolvableStatement = errors.New("only queries can be resolved to the tables they read")
+
+// TableReferences are the catalog tables and views, the table functions, the files and
+// the other names a query reads
+type TableReferences struct {
+	Tables     []table.Identifier // registered tables and views, also when read through a metadata table
+	Functions  []string           // lower-cased names of table functions, such as read_parquet
+	Files      []string           // paths and URLs read by replacement scans, such as FROM 'data.parquet'
+	Unresolved []string           // other names, such as information_schema.tables or duckdb_views
+
+	// CatalogViews are the Unresolved names that are catalog views FilterCatalogViews
+	// can filter, such as information_schema.tables or pg_catalog.pg_class
+	CatalogViews []string
+}
+
+// External returns the table functions that read files, other databases or DuckDB's
+// state, the files a query reads directly and the names that are not catalog tables,
+// that is everything it reads outside the catalog
+func (r *TableReferences) External() []string {
+	var external []string
+	for _, function := range r.Functions {
+		if !IsSafeTableFunction(function) {
+			external = append(external, function)
+		}
+	}
+	external = append(external, r.Files...)
+	return append(external, r.Unresolved...)
+}
+
+// ReferencedTables returns what a query reads, resolved from DuckDB's parse tree rather
+// than from the query text, so that CTEs, subqueries, DESCRIBE and quoted names are
+// handled like DuckDB handles them. Names that are not registered tables or views, such
+// as DuckDB's own catalog views, are returned as Unresolved. Statements other than
+// queries fail with ErrUnresolvableStatement.
+func (e *Engine) ReferencedTables(ctx context.Context, query string) (*TableReferences, error) {
+	tree, err := e.parseQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []tableRef
+	refs := &TableReferences{}
+	for _, statement := range tree["statements"].([]interface{}) {
+		collectReferences(statement, &names, &refs.Functions)
+	}
+
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	seen := make(map[string]bool)
+	for _, name := range names {
+		identifier := e.resolveTableRef(name)
+		if identifier == nil {
+			// DuckDB reads unknown names that look like paths or URLs as files
+			if name.catalog == "" && name.schema == "" && strings.ContainsAny(name.name, "./\\:") {
+				refs.Files = append(refs.Files, name.name)
+			} else {
+				refs.Unresolved = append(refs.Unresolved, name.String())
+				if _, ok := catalogViewName(name); ok {
+					refs.CatalogViews = append(refs.CatalogViews, name.String())
+				}
+			}
+			continue
+		}
+		if key := strings.Join(identifier, "\x00"); !seen[key] {
+			seen[key] = true
+			refs.Tables = append(refs.Tables, identifier)
+		}
+	}
+	return refs, nil
+}
+
+// parseQuery returns DuckDB's serialized parse tree of a query. Numbers are kept as
+// json.Number, so the tree can be encoded again without losing precision. Statements
+// other than queries fail with ErrUnresolvableStatement.
+func (e *Engine) parseQuery(ctx context.Context, query string) (map[string]interface{}, error) {
+	var serialized string
+	if err := e.db.QueryRowContext(ctx, "SELECT json_serialize_sql(?::VARCHAR)::VARCHAR", query).Scan(&serialized); err != nil {
+		return nil, fmt.Errorf("failed to parse query: %w", err)
+	}
+
+	var tree map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(serialized))
+	decoder.UseNumber()
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, fmt.Errorf("failed to decode parse tree: %w", err)
+	}
+	if failed, _ := tree["error"].(bool); failed {
+		if stringField(tree, "error_type") == "not implemented" {
+			return nil, ErrUnresolvableStatement
+		}
+		return nil, fmt.Errorf("%s error: %s", stringField(tree, "error_type"), stringField(tree, "error_message"))
+	}
+	if _, ok := tree["statements"].([]interface{}); !ok {
+		return nil, errors.New("failed to decode parse tree: no statements")
+	}
+	return tree, nil
+}
+
+// tableRef is a table name as written in a query
+type tableRef struct {
+	catalog, schema, name string
+}
+
+// String returns the name as qualified in the query
+func (r tableRef) String() string {
+	var parts []string
+	for _, part := range []string{r.catalog, r.schema, r.name} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// collectReferences walks a serialized parse tree, collecting base table and table
+// function references
+func collectReferences(node interface{}, tables *[]tableRef, functions *[]string) {
+	walkReferences(node, nil, func(_ map[string]interface{}, ref tableRef) {
+		*tables = append(*tables, ref)
+	}, func(name string) {
+		*functions = append(*functions, name)
+	})
+}
+
+// walkReferences walks a serialized parse tree, calling table with every base table
+// reference and its node and function with the lower-cased name of every table
+// function. ctes holds the lower-cased names of the CTEs in scope; they hide tables of
+// the same name when not qualified.
+func walkReferences(node interface{}, ctes map[string]bool, table func(node map[string]interface{}, ref tableRef), function func(name string)) {
+	switch node := node.(type) {
+	case []interface{}:
+		for _, child := range node {
+			walkReferences(child, ctes, table, function)
+		}
+	case map[string]interface{}:
+		switch node["type"] {
+		case "BASE_TABLE":
+			ref := tableRef{catalog: stringField(node, "catalog_name"), schema: stringField(node, "schema_name"), name: stringField(node, "table_name")}
+			if ref.catalog != "" || ref.schema != "" || !ctes[strings.ToLower(ref.name)] {
+				table(node, ref)
+			}
+		case "TABLE_FUNCTION":
+			if fn, ok := node["function"].(map[string]interface{}); ok {
+				function(strings.ToLower(stringField(fn, "function_name")))
+			}
+		}
+
+		// CTE definitions are walked with the outer scope, so a CTE never hides a table
+		// its own definition reads
+		inner := ctes
+		if cteMap, ok := node["cte_map"].(map[string]interface{}); ok {
+			entries, _ := cteMap["map"].([]interface{})
+			inner = make(map[string]bool, len(ctes)+len(entries))
+			for name := range ctes {
+				inner[name] = true
+			}
+			for _, entry := range entries {
+				if entry, ok := entry.(map[string]interface{}); ok {
+					inner[strings.ToLower(stringField(entry, "key"))] = true
+					walkReferences(entry["value"], ctes, table, function)
+				}
+			}
+		}
+
+		for key, child := range node {
+			if key != "cte_map" {
+				walkReferences(child, inner, table, function)
+			}
+		}
+	}
+}
+
+func stringField(node map[string]interface{}, key string) string {
+	value, _ := node[key].(string)
+	return value
+}
+
+// resolveTableRef returns the catalog identifier of a registered table or view, or of
+// the table a metadata table belongs to. Registered tables are named by their DuckDB
+// names or by their namespace and name, as are tables of the attached Iceberg catalog.
+func (e *Engine) resolveTableRef(ref tableRef) table.Identifier {
+	if ref.catalog != "" && strings.EqualFold(ref.catalog, e.config.IcebergCatalogName) && ref.schema != "" {
+		return append(strings.Split(ref.schema, "."), ref.name)
+	}
+	if ref.catalog != "" && !strings.EqualFold(ref.catalog, DatabaseName) {
+		return nil
+	}
+	if ref.schema != "" && !strings.EqualFold(ref.schema, "main") {
+		// Registered tables are also queryable as <namespace>.<name>
+		identifier := e.registered[strings.ToLower(e.identifierToTableName(append(strings.Split(ref.schema, "."), ref.name)))]
+		if identifier == nil || !strings.EqualFold(strings.Join(identifier[:len(identifier)-1], "."), ref.schema) ||
+			!strings.EqualFold(identifier[len(identifier)-1], ref.name) {
+			return nil
+		}
+		return identifier
+	}
+
+	name := strings.ToLower(ref.name)
+	if identifier, ok := e.registered[name]; ok {
+		return identifier
+	}
+	if IsMetadataTableName(name) {
+		return e.registered[name[:strings.LastIndex(name, MetadataTableSeparator)]]
+	}
+	return nil
+}
+
+// recordNames remembers the DuckDB names of a registered table or view. The caller
+// holds the engine's lock.
+func (e *Engine) recordNames(identifier table.Identifier, name string) {
+	e.registered[strings.ToLower(name)] = identifier
+	if simpleName := identifier[len(identifier)-1]; simpleName != "" {
+		e.registered[strings.ToLower(simpleName)] = identifier
+	}
+}
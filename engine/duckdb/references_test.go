@@ -0,0 +1,69 @@
+package duckdb
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferencedTables(t *testing.T) {
+	// Parsing needs no extensions, so a bare connection is enough
+	db, err := sql.Open("duckdb", "")
+	require.NoError(t, err)
+	defer db.Close()
+	engine := &Engine{db: db, config: DefaultEngineConfig(), registered: make(map[string]table.Identifier)}
+
+	orders := table.Identifier{"sales", "orders"}
+	customers := table.Identifier{"crm", "customers"}
+	engine.recordNames(orders, "sales_orders")
+	engine.recordNames(customers, "crm_customers")
+
+	ctx := context.Background()
+	tests := []struct {
+		query      string
+		tables     []table.Identifier
+		functions  []string
+		unresolved []string
+	}{
+		{"SELECT * FROM sales_orders", []table.Identifier{orders}, nil, nil},
+		{`SELECT o.id FROM "Orders" o JOIN main.customers c USING (id)`, []table.Identifier{orders, customers}, nil, nil},
+		{"SELECT * FROM range(3) WHERE range IN (SELECT id FROM crm_customers)", []table.Identifier{customers}, []string{"range"}, nil},
+		{`SELECT * FROM "sales_orders$snapshots"`, []table.Identifier{orders}, nil, nil},
+		{"SELECT * FROM sales.orders JOIN memory.crm.customers USING (id)", []table.Identifier{orders, customers}, nil, nil},
+		{"SELECT * FROM crm.orders", nil, nil, []string{"crm.orders"}},
+		{"DESCRIBE orders", []table.Identifier{orders}, nil, nil},
+		{"FROM read_parquet('data.parquet')", nil, []string{"read_parquet"}, nil},
+		{"SELECT * FROM duckdb_tables() JOIN information_schema.tables USING (table_name)", nil, []string{"duckdb_tables"}, []string{"information_schema.tables"}},
+		// DuckDB's catalog views and unknown names are not catalog tables
+		{"SELECT * FROM duckdb_views", nil, nil, []string{"duckdb_views"}},
+		{"SELECT relname FROM pg_catalog.pg_class", nil, nil, []string{"pg_catalog.pg_class"}},
+		{"SELECT * FROM system.main.duckdb_settings JOIN orders ON true", []table.Identifier{orders}, nil, []string{"system.main.duckdb_settings"}},
+		{"SELECT * FROM other.orders", nil, nil, []string{"other.orders"}},
+		{"SELECT * FROM missing", nil, nil, []string{"missing"}},
+		// A CTE hides a table of the same name, but not inside its own definition
+		{"WITH orders AS (SELECT 1 AS id) SELECT * FROM orders", nil, nil, nil},
+		{"WITH customers AS (SELECT * FROM customers) SELECT * FROM customers", []table.Identifier{customers}, nil, nil},
+		{"WITH duckdb_views AS (SELECT 1 AS id) SELECT * FROM duckdb_views", nil, nil, nil},
+		{"SELECT 1; SELECT * FROM orders", []table.Identifier{orders}, nil, nil},
+		{"SELECT * FROM orders WHERE id = $1", []table.Identifier{orders}, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			refs, err := engine.ReferencedTables(ctx, tt.query)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.tables, refs.Tables)
+			assert.ElementsMatch(t, tt.functions, refs.Functions)
+			assert.ElementsMatch(t, tt.unresolved, refs.Unresolved)
+		})
+	}
+
+	_, err = engine.ReferencedTables(ctx, "INSERT INTO sales_orders SELECT * FROM crm_customers")
+	assert.ErrorIs(t, err, ErrUnresolvableStatement)
+	_, err = engine.ReferencedTables(ctx, "SELEC 1")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrUnresolvableStatement)
+}
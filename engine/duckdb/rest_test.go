@@ -0,0 +1,61 @@
+package duckdb
+
+import (
+	"testing"
+
+	"github.com/TFMV/icebox/config"
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRESTAttachSQL(t *testing.T) {
+	statements, err := restAttachSQL(&config.RESTConfig{
+		URI:               "https://catalog.example.com/",
+		WarehouseLocation: "s3://warehouse",
+		OAuth:             &config.OAuthConfig{Token: "it's a token"},
+		TLS:               &config.TLSConfig{SkipVerify: true},
+	}, "iceberg_catalog")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"SET enable_server_cert_verification = false",
+		"CREATE OR REPLACE SECRET icebox_rest (TYPE ICEBERG, TOKEN 'it''s a token')",
+		`ATTACH 's3://warehouse' AS "iceberg_catalog" (TYPE ICEBERG, ENDPOINT 'https://catalog.example.com', SECRET icebox_rest)`,
+	}, statements)
+
+	statements, err = restAttachSQL(&config.RESTConfig{
+		URI:   "http://localhost:8181",
+		OAuth: &config.OAuthConfig{Credential: "client:secret", Scope: "PRINCIPAL_ROLE:ALL"},
+	}, "iceberg_catalog")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"CREATE OR REPLACE SECRET icebox_rest (TYPE ICEBERG, CLIENT_ID 'client', CLIENT_SECRET 'secret', " +
+			"OAUTH2_SERVER_URI 'http://localhost:8181/v1/oauth/tokens', OAUTH2_SCOPE 'PRINCIPAL_ROLE:ALL')",
+		`ATTACH '' AS "iceberg_catalog" (TYPE ICEBERG, ENDPOINT 'http://localhost:8181', SECRET icebox_rest)`,
+	}, statements)
+
+	statements, err = restAttachSQL(&config.RESTConfig{URI: "http://localhost:8181", SigV4: &config.SigV4Config{Enabled: true}}, "lake")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`ATTACH '' AS "lake" (TYPE ICEBERG, ENDPOINT 'http://localhost:8181', AUTHORIZATION_TYPE 'sigv4')`,
+	}, statements)
+
+	statements, err = restAttachSQL(&config.RESTConfig{URI: "http://localhost:8181"}, "lake")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`ATTACH '' AS "lake" (TYPE ICEBERG, ENDPOINT 'http://localhost:8181', AUTHORIZATION_TYPE 'none')`,
+	}, statements)
+
+	_, err = restAttachSQL(&config.RESTConfig{}, "lake")
+	assert.Error(t, err)
+}
+
+func TestAttachedTableName(t *testing.T) {
+	engine := &Engine{config: DefaultEngineConfig(), registered: make(map[string]table.Identifier)}
+	assert.Equal(t, `"iceberg_catalog"."sales"."orders"`, engine.attachedTableName(table.Identifier{"sales", "orders"}))
+	assert.Equal(t, `"iceberg_catalog"."sales.eu"."orders"`, engine.attachedTableName(table.Identifier{"sales", "eu", "orders"}))
+
+	// Names in the attached catalog resolve back to their identifiers
+	assert.Equal(t, table.Identifier{"sales", "eu", "orders"},
+		engine.resolveTableRef(tableRef{catalog: "iceberg_catalog", schema: "sales.eu", name: "orders"}))
+}
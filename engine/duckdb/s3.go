@@ -0,0 +1,85 @@
+package duckdb
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/fs/s3"
+)
+
+// s3SecretName is the DuckDB secret holding the catalog's S3 credentials
+const s3SecretName = "icebox_s3"
+
+// configureS3 creates a DuckDB secret matching the catalog's S3 storage so that
+// iceberg_scan can read s3:// metadata and data files
+func (e *Engine) configureS3() error {
+	provider, ok := e.catalog.(interface{ GetS3Config() *config.S3Config })
+	if !ok || provider.GetS3Config() == nil {
+		return nil
+	}
+
+	secretSQL, err := s3SecretSQL(provider.GetS3Config())
+	if err != nil {
+		return err
+	}
+
+	if _, err := e.db.Exec(secretSQL); err != nil {
+		return fmt.Errorf("failed to create S3 secret: %w", err)
+	}
+
+	e.logger.Printf("Info: Configured S3 access for bucket %s", provider.GetS3Config().Bucket)
+	return nil
+}
+
+// s3SecretSQL builds the CREATE SECRET statement for an S3 configuration
+func s3SecretSQL(cfg *config.S3Config) (string, error) {
+	if err := s3.Validate(cfg); err != nil {
+		return "", err
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = s3.DefaultRegion
+	}
+
+	options := []string{
+		"TYPE S3",
+		"SCOPE " + quoteLiteral("s3://"+cfg.Bucket),
+		"REGION " + quoteLiteral(region),
+	}
+
+	if cfg.AccessKeyID != "" || cfg.SecretAccessKey != "" {
+		options = append(options,
+			"KEY_ID "+quoteLiteral(cfg.AccessKeyID),
+			"SECRET "+quoteLiteral(cfg.SecretAccessKey))
+	} else {
+		// Fall back to the environment, profiles and instance metadata like the AWS SDK does
+		options = append(options, "PROVIDER CREDENTIAL_CHAIN")
+	}
+
+	if cfg.Endpoint != "" {
+		// DuckDB takes the endpoint without a scheme and a separate SSL flag
+		endpoint, err := url.Parse(cfg.Endpoint)
+		if err != nil {
+			return "", fmt.Errorf("invalid S3 endpoint %q: %w", cfg.Endpoint, err)
+		}
+		options = append(options,
+			"ENDPOINT "+quoteLiteral(endpoint.Host),
+			fmt.Sprintf("USE_SSL %t", endpoint.Scheme == "https"))
+	}
+
+	if cfg.VirtualHostStyle {
+		options = append(options, "URL_STYLE 'vhost'")
+	} else {
+		options = append(options, "URL_STYLE 'path'")
+	}
+
+	return fmt.Sprintf("CREATE OR REPLACE SECRET %s (%s)", s3SecretName, strings.Join(options, ", ")), nil
+}
+
+// quoteLiteral quotes a string as a SQL literal
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
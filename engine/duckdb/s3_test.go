@@ -0,0 +1,29 @@
+package duckdb
+
+import (
+	"testing"
+
+	"github.com/TFMV/icebox/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestS3SecretSQL(t *testing.T) {
+	secretSQL, err := s3SecretSQL(&config.S3Config{
+		Bucket:          "warehouse",
+		Endpoint:        "http://localhost:9000",
+		AccessKeyID:     "minio",
+		SecretAccessKey: "it's secret",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE OR REPLACE SECRET icebox_s3 (TYPE S3, SCOPE 's3://warehouse', REGION 'us-east-1', "+
+		"KEY_ID 'minio', SECRET 'it''s secret', ENDPOINT 'localhost:9000', USE_SSL false, URL_STYLE 'path')", secretSQL)
+
+	secretSQL, err = s3SecretSQL(&config.S3Config{Bucket: "warehouse", Region: "eu-west-1", VirtualHostStyle: true})
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE OR REPLACE SECRET icebox_s3 (TYPE S3, SCOPE 's3://warehouse', REGION 'eu-west-1', "+
+		"PROVIDER CREDENTIAL_CHAIN, URL_STYLE 'vhost')", secretSQL)
+
+	_, err = s3SecretSQL(&config.S3Config{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,127 @@
+package duckdb
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// ExecuteQueryStream executes a SQL query and returns the Arrow record batches DuckDB's
+// Arrow interface produces for it, so that results can be written out batch by batch
+// rather than being collected into a table or rows first. The driver imports DuckDB's
+// result before returning it, so the batches are held until the reader is released.
+// The caller must release the reader, which ends the query. args bind the query's
+// placeholders, if any.
+func (e *Engine) ExecuteQueryStream(ctx context.Context, query string, args ...interface{}) (*QueryStream, error) {
+	if !e.initialized {
+		return nil, fmt.Errorf("engine not initialized")
+	}
+
+	queryID := e.newQueryID()
+
+	// The query runs until the reader is released, or the timeout if one is configured
+	var cancel context.CancelFunc
+	if e.config.QueryTimeoutSec > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(e.config.QueryTimeoutSec)*time.Second)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+
+	if e.config.EnableQueryLog {
+		e.logger.Printf("Streaming query [%s]: %s", queryID, query)
+	}
+
+	start := time.Now()
+
+	processedQuery, err := e.preprocessQuery(ctx, query)
+	if err != nil {
+		cancel()
+		e.incrementErrorCount()
+		return nil, fmt.Errorf("failed to preprocess query [%s]: %w", queryID, err)
+	}
+
+	reader, err := e.queryRecords(ctx, processedQuery, args...)
+	if err != nil {
+		cancel()
+		e.incrementErrorCount()
+		return nil, e.queryError(queryID, err)
+	}
+
+	stream := &QueryStream{reader: reader, engine: e, queryID: queryID, start: start, cancel: cancel}
+	stream.refCount.Store(1)
+	return stream, nil
+}
+
+// QueryStream is the array.RecordReader returned by ExecuteQueryStream. It ends the
+// query and records its metrics once released.
+type QueryStream struct {
+	reader   array.RecordReader
+	refCount atomic.Int64
+	engine   *Engine
+	queryID  string
+	start    time.Time
+	cancel   context.CancelFunc
+	rows     int64
+	release  func() // cleans up after the query, such as dropping the views it read
+}
+
+// QueryID returns the ID the query is tracked by in logs and errors
+func (s *QueryStream) QueryID() string {
+	return s.queryID
+}
+
+func (s *QueryStream) Retain() {
+	s.refCount.Add(1)
+}
+
+func (s *QueryStream) Release() {
+	if s.refCount.Add(-1) != 0 {
+		return
+	}
+
+	failed := s.reader.Err() != nil
+	s.reader.Release()
+	s.cancel()
+	if s.release != nil {
+		s.release()
+	}
+
+	duration := time.Since(s.start)
+	s.engine.metrics.mu.Lock()
+	s.engine.metrics.TotalQueryTime += duration
+	if failed {
+		s.engine.metrics.ErrorCount++
+	}
+	s.engine.metrics.mu.Unlock()
+
+	if s.engine.config.EnableQueryLog {
+		s.engine.logger.Printf("Query [%s] streamed %d rows in %v", s.queryID, s.rows, duration)
+	}
+}
+
+func (s *QueryStream) Schema() *arrow.Schema {
+	return s.reader.Schema()
+}
+
+func (s *QueryStream) Record() arrow.Record {
+	return s.reader.Record()
+}
+
+func (s *QueryStream) Next() bool {
+	if !s.reader.Next() {
+		return false
+	}
+	s.rows += s.reader.Record().NumRows()
+	return true
+}
+
+func (s *QueryStream) Err() error {
+	if err := s.reader.Err(); err != nil {
+		return s.engine.queryError(s.queryID, err)
+	}
+	return nil
+}
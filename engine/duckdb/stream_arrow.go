@@ -0,0 +1,40 @@
+package duckdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	duckdb "github.com/marcboeker/go-duckdb/v2"
+)
+
+// queryRecords runs a query through DuckDB's Arrow interface, so DuckDB picks the Arrow
+// types of the results itself. The driver only offers DuckDB's materialized Arrow
+// result, which it imports batch by batch before returning the reader, so the whole
+// result is held in memory while it is read.
+func (e *Engine) queryRecords(ctx context.Context, query string, args ...interface{}) (array.RecordReader, error) {
+	conn, err := e.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var reader array.RecordReader
+	err = conn.Raw(func(driverConn any) error {
+		dc, ok := driverConn.(driver.Conn)
+		if !ok {
+			return fmt.Errorf("unexpected DuckDB connection %T", driverConn)
+		}
+		arrowConn, err := duckdb.NewArrowFromConn(dc)
+		if err != nil {
+			return err
+		}
+		reader, err = arrowConn.QueryContext(ctx, query, args...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reader, nil
+}
@@ -0,0 +1,189 @@
+package duckdb
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/iceberg-go/table"
+	duckdb "github.com/marcboeker/go-duckdb/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestEngine returns an engine on an in-memory DuckDB database, without extensions
+// or a catalog
+func newTestEngine(t *testing.T, config *EngineConfig) *Engine {
+	db, err := sql.Open("duckdb", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return &Engine{
+		db:          db,
+		allocator:   memory.NewGoAllocator(),
+		initialized: true,
+		config:      config,
+		logger:      log.Default(),
+		metrics:     &EngineMetrics{},
+		registered:  make(map[string]table.Identifier),
+	}
+}
+
+func TestExecuteQueryStream(t *testing.T) {
+	engine := newTestEngine(t, DefaultEngineConfig())
+	ctx := context.Background()
+
+	reader, err := engine.ExecuteQueryStream(ctx, "SELECT i AS id, 'row ' || i AS name FROM range(10000) t(i) WHERE i >= ?", 5000)
+	require.NoError(t, err)
+	defer reader.Release()
+
+	require.Equal(t, 2, reader.Schema().NumFields())
+	assert.Equal(t, "id", reader.Schema().Field(0).Name)
+	assert.Equal(t, "name", reader.Schema().Field(1).Name)
+
+	var rows, sum int64
+	for reader.Next() {
+		record := reader.Record()
+		ids := record.Column(0).(*array.Int64)
+		names := record.Column(1).(*array.String)
+		for i := 0; i < int(record.NumRows()); i++ {
+			sum += ids.Value(i)
+			if rows == 0 {
+				assert.Equal(t, "row 5000", names.Value(i))
+			}
+			rows++
+		}
+	}
+	require.NoError(t, reader.Err())
+	assert.Equal(t, int64(5000), rows)
+	assert.Equal(t, int64(5000*(5000+9999)/2), sum)
+
+	// Errors are reported before any records
+	_, err = engine.ExecuteQueryStream(ctx, "SELECT * FROM missing_table")
+	assert.Error(t, err)
+	assert.Equal(t, int64(1), engine.GetMetrics().ErrorCount)
+}
+
+func TestExecuteQueryTruncation(t *testing.T) {
+	config := DefaultEngineConfig()
+	config.MaxResultRows = 100
+	engine := newTestEngine(t, config)
+	ctx := context.Background()
+
+	result, err := engine.ExecuteQuery(ctx, "SELECT * FROM range(101)")
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), result.RowCount)
+	assert.Len(t, result.Rows(), 100)
+	assert.Equal(t, int64(100), result.Table.NumRows())
+	assert.True(t, result.Truncated)
+
+	result, err = engine.ExecuteQuery(ctx, "SELECT * FROM range(100)")
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), result.RowCount)
+	assert.False(t, result.Truncated)
+
+	// The stream is not limited
+	reader, err := engine.ExecuteQueryStream(ctx, "SELECT * FROM range(101)")
+	require.NoError(t, err)
+	defer reader.Release()
+	var rows int64
+	for reader.Next() {
+		rows += reader.Record().NumRows()
+	}
+	require.NoError(t, reader.Err())
+	assert.Equal(t, int64(101), rows)
+}
+
+func TestExecuteQueryArrowResult(t *testing.T) {
+	engine := newTestEngine(t, DefaultEngineConfig())
+
+	result, err := engine.ExecuteQuery(context.Background(), `SELECT
+		i::INTEGER AS id,
+		(i * 1.25)::DECIMAL(10,2) AS amount,
+		[i, i + 1] AS pair,
+		{'n': i} AS info,
+		TIMESTAMPTZ '2025-01-02 03:04:05+00' AS created_at
+		FROM range(5000) t(i) ORDER BY i`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"id", "amount", "pair", "info", "created_at"}, result.Columns)
+	assert.Equal(t, []string{"INTEGER", "DECIMAL(10,2)", "BIGINT[]", `STRUCT("n" BIGINT)`, "TIMESTAMPTZ"}, result.ColumnTypes)
+	assert.Equal(t, result.Schema, result.Table.Schema())
+	assert.Equal(t, int64(5000), result.Table.NumRows())
+	assert.Equal(t, int64(5000), result.RowCount)
+	require.Len(t, result.Rows(), 5000)
+
+	// Rows hold the values database/sql would scan
+	last := result.Rows()[4999]
+	assert.Equal(t, int32(4999), last[0])
+	assert.Equal(t, duckdb.Decimal{Width: 10, Scale: 2, Value: big.NewInt(624875)}, last[1])
+	assert.Equal(t, []interface{}{int64(4999), int64(5000)}, last[2])
+	assert.Equal(t, map[string]interface{}{"n": int64(4999)}, last[3])
+	assert.Equal(t, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), last[4])
+
+	amounts := result.Table.Column(1).Data()
+	lastChunk := amounts.Chunk(len(amounts.Chunks()) - 1)
+	assert.Equal(t, "6248.75", FormatArrowValue(lastChunk, lastChunk.Len()-1))
+}
+
+func TestExecuteQueryStreamTypes(t *testing.T) {
+	engine := newTestEngine(t, DefaultEngineConfig())
+
+	reader, err := engine.ExecuteQueryStream(context.Background(), `SELECT
+		12.345::DECIMAL(18,3) AS amount,
+		170141183460469231731687303715884105727::HUGEINT AS huge,
+		TIMESTAMPTZ '2025-01-02 03:04:05.123456+00' AS created_at,
+		DATE '2025-01-02' AS day,
+		TIME '10:30:00' AS at,
+		'6ba7b810-9dad-11d1-80b4-00c04fd430c8'::UUID AS id,
+		[1, 2] AS list,
+		{'a': 1} AS struct,
+		MAP {'k': 2} AS map,
+		INTERVAL 1 DAY AS wait,
+		NULL::INTEGER AS missing`)
+	require.NoError(t, err)
+	defer reader.Release()
+
+	// DuckDB picks the Arrow types, such as the narrowest decimal that fits
+	expected := []arrow.DataType{
+		&arrow.Decimal64Type{Precision: 18, Scale: 3},
+		&arrow.Decimal128Type{Precision: 38, Scale: 0},
+		&arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "Etc/UTC"},
+		arrow.FixedWidthTypes.Date32,
+		arrow.FixedWidthTypes.Time64us,
+		arrow.BinaryTypes.String,
+		arrow.ListOf(arrow.PrimitiveTypes.Int32),
+		arrow.StructOf(arrow.Field{Name: "a", Type: arrow.PrimitiveTypes.Int32, Nullable: true}),
+		arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Int32),
+		arrow.FixedWidthTypes.MonthDayNanoInterval,
+		arrow.PrimitiveTypes.Int32,
+	}
+	schema := reader.Schema()
+	require.Equal(t, len(expected), schema.NumFields())
+	for i, dt := range expected {
+		assert.True(t, arrow.TypeEqual(dt, schema.Field(i).Type), "column %s: got %s", schema.Field(i).Name, schema.Field(i).Type)
+	}
+
+	require.True(t, reader.Next())
+	record := reader.Record()
+	assert.Equal(t, "12.345", record.Column(0).ValueStr(0))
+	assert.Equal(t, "170141183460469231731687303715884105727", record.Column(1).(*array.Decimal128).Value(0).BigInt().String())
+	assert.Equal(t, "2025-01-02 03:04:05.123456Z", record.Column(2).ValueStr(0))
+	assert.Equal(t, "2025-01-02", record.Column(3).ValueStr(0))
+	assert.Equal(t, "10:30:00.000000", record.Column(4).ValueStr(0))
+	assert.Equal(t, "6ba7b810-9dad-11d1-80b4-00c04fd430c8", record.Column(5).ValueStr(0))
+	assert.Equal(t, "[1,2]", record.Column(6).ValueStr(0))
+	assert.Equal(t, `{"a":1}`, record.Column(7).ValueStr(0))
+	assert.Equal(t, `[{"key":"k","value":2}]`, record.Column(8).ValueStr(0))
+	assert.Equal(t, `{"months":0,"days":1,"nanoseconds":0}`, record.Column(9).ValueStr(0))
+	assert.True(t, record.Column(10).(*array.Int32).IsNull(0))
+
+	assert.False(t, reader.Next())
+	assert.NoError(t, reader.Err())
+}
@@ -0,0 +1,129 @@
+package duckdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/apache/iceberg-go/table"
+)
+
+// ExecuteQueryAtSnapshot runs a query with its reads of a table replaced by reads of
+// the table as of one of its snapshots. The snapshot is registered as a view that only
+// exists while the query runs, and the query's references to the table are rewritten
+// to it in DuckDB's parse tree, so only queries can be run at a snapshot.
+func (e *Engine) ExecuteQueryAtSnapshot(ctx context.Context, identifier table.Identifier, icebergTable *table.Table, snapshotID int64, query string) (*QueryResult, error) {
+	rewritten, drop, err := e.registerSnapshot(ctx, identifier, icebergTable, snapshotID, query)
+	if err != nil {
+		return nil, err
+	}
+	defer drop()
+
+	return e.ExecuteQuery(ctx, rewritten)
+}
+
+// ExecuteQueryStreamAtSnapshot is ExecuteQueryAtSnapshot returning the results as a
+// stream, like ExecuteQueryStream. The snapshot's view is dropped when the stream is
+// released.
+func (e *Engine) ExecuteQueryStreamAtSnapshot(ctx context.Context, identifier table.Identifier, icebergTable *table.Table, snapshotID int64, query string) (*QueryStream, error) {
+	rewritten, drop, err := e.registerSnapshot(ctx, identifier, icebergTable, snapshotID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := e.ExecuteQueryStream(ctx, rewritten)
+	if err != nil {
+		drop()
+		return nil, err
+	}
+	stream.release = drop
+	return stream, nil
+}
+
+// registerSnapshot registers a table as of a snapshot as a view and returns query
+// rewritten to read it, along with a function that drops the view
+func (e *Engine) registerSnapshot(ctx context.Context, identifier table.Identifier, icebergTable *table.Table, snapshotID int64, query string) (string, func(), error) {
+	if !e.initialized {
+		return "", nil, fmt.Errorf("engine not initialized")
+	}
+	if icebergTable == nil {
+		return "", nil, fmt.Errorf("iceberg table cannot be nil")
+	}
+	if icebergTable.SnapshotByID(snapshotID) == nil {
+		return "", nil, fmt.Errorf("snapshot with ID %d not found", snapshotID)
+	}
+	if !e.icebergAvailable {
+		return "", nil, fmt.Errorf("time travel requires DuckDB's iceberg extension")
+	}
+
+	// Concurrent queries at the same snapshot each get their own view
+	viewName := fmt.Sprintf("%s_snapshot_%d_%d", e.identifierToTableName(identifier), snapshotID, e.snapshotViews.Add(1))
+	rewritten, err := e.rewriteTableRefs(ctx, query, identifier, viewName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	createViewSQL := fmt.Sprintf("CREATE VIEW %s AS SELECT * FROM iceberg_scan(%s, snapshot_from_id = %d)",
+		e.quoteName(viewName), quoteLiteral(icebergTable.MetadataLocation()), snapshotID)
+	if _, err := e.db.ExecContext(ctx, createViewSQL); err != nil {
+		e.incrementErrorCount()
+		return "", nil, fmt.Errorf("failed to register table %s at snapshot %d: %w", strings.Join(identifier, "."), snapshotID, err)
+	}
+	drop := func() {
+		if _, err := e.db.ExecContext(context.WithoutCancel(ctx), "DROP VIEW IF EXISTS "+e.quoteName(viewName)); err != nil {
+			e.logger.Printf("Warning: Could not drop snapshot view %s: %v", viewName, err)
+		}
+	}
+	return rewritten, drop, nil
+}
+
+// rewriteTableRefs returns query with its references to a table renamed to name. The
+// references keep the name they were written with as their alias, so columns qualified
+// by it still resolve.
+func (e *Engine) rewriteTableRefs(ctx context.Context, query string, identifier table.Identifier, name string) (string, error) {
+	tree, err := e.parseQuery(ctx, query)
+	if err != nil {
+		return "", err
+	}
+
+	walkReferences(tree["statements"], nil, func(node map[string]interface{}, ref tableRef) {
+		if !e.namesTable(ref, identifier) {
+			return
+		}
+		if stringField(node, "alias") == "" {
+			node["alias"] = ref.name
+		}
+		node["catalog_name"] = ""
+		node["schema_name"] = ""
+		node["table_name"] = name
+	}, func(string) {})
+
+	serialized, err := json.Marshal(tree)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode parse tree: %w", err)
+	}
+	var rewritten string
+	if err := e.db.QueryRowContext(ctx, "SELECT json_deserialize_sql(?::JSON)", string(serialized)).Scan(&rewritten); err != nil {
+		return "", fmt.Errorf("failed to rewrite query: %w", err)
+	}
+	return rewritten, nil
+}
+
+// namesTable reports whether a name in a query is one of the names a table is
+// registered under: its DuckDB name, its simple name, its namespace and name, or its
+// name in the attached Iceberg catalog. The table need not be registered.
+func (e *Engine) namesTable(ref tableRef, identifier table.Identifier) bool {
+	namespace := strings.Join(identifier[:len(identifier)-1], ".")
+	simpleName := identifier[len(identifier)-1]
+	switch {
+	case ref.catalog != "" && strings.EqualFold(ref.catalog, e.config.IcebergCatalogName):
+		return strings.EqualFold(ref.schema, namespace) && strings.EqualFold(ref.name, simpleName)
+	case ref.catalog != "" && !strings.EqualFold(ref.catalog, DatabaseName):
+		return false
+	case ref.schema != "" && !strings.EqualFold(ref.schema, "main"):
+		return strings.EqualFold(ref.schema, namespace) && strings.EqualFold(ref.name, simpleName)
+	default:
+		return strings.EqualFold(ref.name, e.identifierToTableName(identifier)) || strings.EqualFold(ref.name, simpleName)
+	}
+}
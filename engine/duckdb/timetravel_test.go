@@ -0,0 +1,57 @@
+package duckdb
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteTableRefs(t *testing.T) {
+	// Parsing needs no extensions, so a bare connection is enough
+	db, err := sql.Open("duckdb", "")
+	require.NoError(t, err)
+	defer db.Close()
+	engine := &Engine{db: db, config: DefaultEngineConfig(), registered: make(map[string]table.Identifier)}
+
+	ctx := context.Background()
+	_, err = db.ExecContext(ctx, "CREATE TABLE orders_at_snapshot AS SELECT 1 AS id")
+	require.NoError(t, err)
+
+	tests := []struct {
+		query string
+		want  int64
+	}{
+		{"SELECT id FROM sales.orders", 1},
+		{"SELECT orders.id FROM orders", 1},
+		{`SELECT o.id FROM "Sales"."Orders" o`, 1},
+		{"SELECT id FROM memory.sales.orders JOIN sales_orders USING (id)", 1},
+		{"SELECT max(id) FROM (SELECT id FROM iceberg_catalog.sales.orders)", 1},
+		{"SELECT count(*) FROM orders WHERE id < 1234567890123456789", 1},
+		{"WITH orders AS (SELECT 2 AS id) SELECT id FROM orders", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rewritten, err := engine.rewriteTableRefs(ctx, tt.query, table.Identifier{"sales", "orders"}, "orders_at_snapshot")
+			require.NoError(t, err)
+
+			var got int64
+			require.NoError(t, db.QueryRowContext(ctx, rewritten).Scan(&got), rewritten)
+			assert.Equal(t, tt.want, got, rewritten)
+		})
+	}
+
+	// Numbers, metadata tables and other tables are kept as they are
+	rewritten, err := engine.rewriteTableRefs(ctx, `SELECT 1234567890123456789 FROM "orders$snapshots", crm.orders`, table.Identifier{"sales", "orders"}, "orders_at_snapshot")
+	require.NoError(t, err)
+	assert.Contains(t, rewritten, "1234567890123456789")
+	assert.Contains(t, rewritten, `"orders$snapshots"`)
+	assert.Contains(t, rewritten, "crm.orders")
+	assert.NotContains(t, rewritten, "orders_at_snapshot")
+
+	_, err = engine.rewriteTableRefs(ctx, "DELETE FROM orders", table.Identifier{"sales", "orders"}, "orders_at_snapshot")
+	assert.ErrorIs(t, err, ErrUnresolvableStatement)
+}
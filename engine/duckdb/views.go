@@ -0,0 +1,171 @@
+package duckdb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/TFMV/icebox/catalog/view"
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+)
+
+// RegisterView registers a catalog view as a DuckDB view under its full name and,
+// like tables, an alias with just the view name
+func (e *Engine) RegisterView(ctx context.Context, v *view.View) error {
+	if !e.initialized {
+		return fmt.Errorf("engine not initialized")
+	}
+
+	identifier := v.Identifier()
+	query := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(v.SQL()), ";"))
+	if query == "" {
+		return fmt.Errorf("view %s has no SQL representation", strings.Join(identifier, "."))
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	viewName := e.identifierToTableName(identifier)
+	createSQL := fmt.Sprintf("CREATE OR REPLACE VIEW %s AS %s", e.quoteName(viewName), query)
+	if _, err := e.db.ExecContext(ctx, createSQL); err != nil {
+		e.incrementErrorCount()
+		return fmt.Errorf("failed to register view %s (%s dialect): %w", viewName, v.Dialect(), err)
+	}
+	e.recordNames(identifier, viewName)
+
+	simpleViewName := identifier[len(identifier)-1]
+	if simpleViewName != viewName && simpleViewName != "" {
+		aliasSQL := fmt.Sprintf("CREATE OR REPLACE VIEW %s AS SELECT * FROM %s",
+			e.quoteName(simpleViewName), e.quoteName(viewName))
+
+		if _, err := e.db.ExecContext(ctx, aliasSQL); err != nil {
+			e.logger.Printf("Warning: Could not create alias %s for view %s: %v", simpleViewName, viewName, err)
+		}
+	}
+	e.createNamespaceViewOrWarn(ctx, identifier, viewName)
+
+	e.logger.Printf("Registered view %s", viewName)
+	return nil
+}
+
+// UnregisterView removes a view registered with RegisterView
+func (e *Engine) UnregisterView(ctx context.Context, identifier table.Identifier) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	viewName := e.identifierToTableName(identifier)
+	names := []string{viewName}
+	if simpleViewName := identifier[len(identifier)-1]; simpleViewName != viewName {
+		names = append(names, simpleViewName)
+	}
+
+	for _, name := range names {
+		if _, err := e.db.ExecContext(ctx, fmt.Sprintf("DROP VIEW IF EXISTS %s", e.quoteName(name))); err != nil {
+			return fmt.Errorf("failed to drop view %s: %w", name, err)
+		}
+		delete(e.registered, strings.ToLower(name))
+	}
+
+	// The view's schema stays, as other tables of its namespace may be in it
+	if len(identifier) > 1 {
+		namespaceView := e.quoteName(strings.Join(identifier[:len(identifier)-1], ".")) + "." + e.quoteName(identifier[len(identifier)-1])
+		if _, err := e.db.ExecContext(ctx, "DROP VIEW IF EXISTS "+namespaceView); err != nil {
+			return fmt.Errorf("failed to drop view %s: %w", namespaceView, err)
+		}
+	}
+	return nil
+}
+
+// QuerySchema returns the Iceberg schema of the rows a query would produce,
+// without running it
+func (e *Engine) QuerySchema(ctx context.Context, query string) (*iceberg.Schema, error) {
+	if !e.initialized {
+		return nil, fmt.Errorf("engine not initialized")
+	}
+
+	query = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(query), ";"))
+	rows, err := e.db.QueryContext(ctx, "DESCRIBE "+query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe query: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read describe columns: %w", err)
+	}
+
+	var fields []iceberg.NestedField
+	for rows.Next() {
+		// DESCRIBE returns column_name, column_type, null, key, default, extra
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("failed to scan describe row: %w", err)
+		}
+
+		name, _ := values[0].(string)
+		columnType, _ := values[1].(string)
+		nullable, _ := values[2].(string)
+		fields = append(fields, iceberg.NestedField{
+			ID:       len(fields) + 1,
+			Name:     name,
+			Type:     duckDBTypeToIceberg(columnType),
+			Required: nullable == "NO",
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating describe rows: %w", err)
+	}
+
+	return iceberg.NewSchema(0, fields...), nil
+}
+
+var decimalTypePattern = regexp.MustCompile(`^DECIMAL\((\d+),\s*(\d+)\)$`)
+
+// duckDBTypeToIceberg maps a DuckDB column type to the closest Iceberg type. Nested
+// and unknown types are reported as strings since view schemas are informational.
+func duckDBTypeToIceberg(columnType string) iceberg.Type {
+	columnType = strings.ToUpper(strings.TrimSpace(columnType))
+
+	if match := decimalTypePattern.FindStringSubmatch(columnType); match != nil {
+		precision, _ := strconv.Atoi(match[1])
+		scale, _ := strconv.Atoi(match[2])
+		return iceberg.DecimalTypeOf(precision, scale)
+	}
+
+	switch columnType {
+	case "BOOLEAN":
+		return iceberg.PrimitiveTypes.Bool
+	case "TINYINT", "SMALLINT", "INTEGER", "UTINYINT", "USMALLINT":
+		return iceberg.PrimitiveTypes.Int32
+	case "BIGINT", "UINTEGER":
+		return iceberg.PrimitiveTypes.Int64
+	case "HUGEINT", "UBIGINT", "UHUGEINT":
+		return iceberg.DecimalTypeOf(38, 0)
+	case "FLOAT":
+		return iceberg.PrimitiveTypes.Float32
+	case "DOUBLE":
+		return iceberg.PrimitiveTypes.Float64
+	case "DATE":
+		return iceberg.PrimitiveTypes.Date
+	case "TIME":
+		return iceberg.PrimitiveTypes.Time
+	case "TIMESTAMP", "TIMESTAMP_MS", "TIMESTAMP_S", "TIMESTAMP_NS":
+		return iceberg.PrimitiveTypes.Timestamp
+	case "TIMESTAMP WITH TIME ZONE":
+		return iceberg.PrimitiveTypes.TimestampTz
+	case "BLOB":
+		return iceberg.PrimitiveTypes.Binary
+	case "UUID":
+		return iceberg.PrimitiveTypes.UUID
+	default:
+		return iceberg.PrimitiveTypes.String
+	}
+}
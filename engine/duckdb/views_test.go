@@ -0,0 +1,87 @@
+package duckdb
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/TFMV/icebox/catalog/sqlite"
+	"github.com/TFMV/icebox/config"
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterView(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		Name: "test-catalog",
+		Catalog: config.CatalogConfig{
+			Type: "sqlite",
+			SQLite: &config.SQLiteConfig{
+				Path: filepath.Join(tempDir, "test.db"),
+			},
+		},
+		Storage: config.StorageConfig{
+			FileSystem: &config.FileSystemConfig{
+				RootPath: tempDir,
+			},
+		},
+	}
+
+	cat, err := sqlite.NewCatalog(cfg)
+	require.NoError(t, err)
+	defer cat.Close()
+
+	engine, err := NewEngine(cat)
+	require.NoError(t, err)
+	defer engine.Close()
+
+	ctx := context.Background()
+	query := "SELECT range AS n, range * 2 AS doubled FROM range(5)"
+
+	schema, err := engine.QuerySchema(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, schema.Fields(), 2)
+	assert.Equal(t, "n", schema.Fields()[0].Name)
+	assert.Equal(t, iceberg.PrimitiveTypes.Int64, schema.Fields()[0].Type)
+
+	_, err = engine.QuerySchema(ctx, "SELECT * FROM missing_table")
+	assert.Error(t, err)
+
+	require.NoError(t, cat.CreateNamespace(ctx, table.Identifier{"default"}, iceberg.Properties{}))
+	identifier := table.Identifier{"default", "doubles"}
+	v, err := cat.CreateView(ctx, identifier, query, "duckdb", schema, nil)
+	require.NoError(t, err)
+	require.NoError(t, engine.RegisterView(ctx, v))
+
+	// Views are queryable by their full and simple names, and by namespace and name
+	for _, name := range []string{"default_doubles", "doubles", `"default".doubles`} {
+		result, err := engine.ExecuteQuery(ctx, "SELECT sum(doubled)::BIGINT FROM "+name)
+		require.NoError(t, err)
+		assert.Equal(t, int64(20), result.Rows()[0][0])
+	}
+
+	require.NoError(t, engine.UnregisterView(ctx, identifier))
+	for _, name := range []string{"doubles", `"default".doubles`} {
+		_, err = engine.ExecuteQuery(ctx, "SELECT * FROM "+name)
+		assert.Error(t, err)
+	}
+}
+
+func TestDuckDBTypeToIceberg(t *testing.T) {
+	tests := map[string]iceberg.Type{
+		"BIGINT":                   iceberg.PrimitiveTypes.Int64,
+		"INTEGER":                  iceberg.PrimitiveTypes.Int32,
+		"VARCHAR":                  iceberg.PrimitiveTypes.String,
+		"DOUBLE":                   iceberg.PrimitiveTypes.Float64,
+		"DECIMAL(18,3)":            iceberg.DecimalTypeOf(18, 3),
+		"TIMESTAMP WITH TIME ZONE": iceberg.PrimitiveTypes.TimestampTz,
+		"INTEGER[]":                iceberg.PrimitiveTypes.String,
+	}
+
+	for columnType, expected := range tests {
+		assert.True(t, expected.Equals(duckDBTypeToIceberg(columnType)), columnType)
+	}
+}
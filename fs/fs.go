@@ -0,0 +1,121 @@
+// Package fs resolves storage locations to file systems. Each backend handles the
+// locations of one or more URI schemes, and callers that only hold a location string,
+// such as a table or warehouse location, look up the backend with Resolve.
+package fs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	iofs "io/fs"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/fs/httpfs"
+	"github.com/TFMV/icebox/fs/local"
+	"github.com/TFMV/icebox/fs/memory"
+	"github.com/TFMV/icebox/fs/s3"
+	icebergio "github.com/apache/iceberg-go/io"
+)
+
+// FileSystem is implemented by every storage backend. Locations are full URIs or, for
+// the local file system, plain paths.
+type FileSystem interface {
+	// Open opens a file for reading
+	Open(location string) (icebergio.File, error)
+	// Create creates or truncates a file; its contents are written when it is closed
+	Create(location string) (io.WriteCloser, error)
+	// Remove deletes a file
+	Remove(location string) error
+	// Stat returns information about a file or directory
+	Stat(location string) (iofs.FileInfo, error)
+	// List returns the entries directly below a directory, sorted by name
+	List(location string) ([]iofs.FileInfo, error)
+	// Walk calls fn for every file below a location in lexical order
+	Walk(location string, fn func(location string, info iofs.FileInfo) error) error
+}
+
+// Factory creates the file system for a location. The configuration carries the
+// credentials and endpoints of remote storage and may be nil.
+type Factory func(ctx context.Context, location string, cfg *config.Config) (FileSystem, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+func init() {
+	Register(local.Scheme, func(ctx context.Context, location string, cfg *config.Config) (FileSystem, error) {
+		return local.NewFileSystem(""), nil
+	})
+	Register(memory.Scheme, func(ctx context.Context, location string, cfg *config.Config) (FileSystem, error) {
+		return memory.Shared(), nil
+	})
+	for _, scheme := range s3.Schemes {
+		Register(scheme, newS3FileSystem)
+	}
+	for _, scheme := range httpfs.Schemes {
+		Register(scheme, func(ctx context.Context, location string, cfg *config.Config) (FileSystem, error) {
+			return httpfs.NewFileSystem(ctx, nil), nil
+		})
+	}
+}
+
+// newS3FileSystem creates an S3 file system from the project's storage configuration
+func newS3FileSystem(ctx context.Context, location string, cfg *config.Config) (FileSystem, error) {
+	if cfg == nil || cfg.Storage.S3 == nil {
+		return nil, fmt.Errorf("location %s requires S3 storage configuration", location)
+	}
+	return s3.NewFileSystem(cfg.Storage.S3)
+}
+
+// Register makes a factory available for the locations of a scheme, replacing any
+// factory registered for it before
+func Register(scheme string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[strings.ToLower(scheme)] = factory
+}
+
+// Schemes returns the registered schemes in sorted order
+func Schemes() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	schemes := make([]string, 0, len(registry))
+	for scheme := range registry {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// Scheme returns the URI scheme of a location. Locations without one, including
+// Windows paths with a drive letter, are local files.
+func Scheme(location string) string {
+	scheme, _, found := strings.Cut(location, "://")
+	if !found || len(scheme) < 2 || strings.ContainsAny(scheme, `/\`) {
+		return local.Scheme
+	}
+	return strings.ToLower(scheme)
+}
+
+// Resolve returns the file system for a location
+func Resolve(ctx context.Context, location string, cfg *config.Config) (FileSystem, error) {
+	scheme := Scheme(location)
+
+	registryMu.RLock()
+	factory, ok := registry[scheme]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported storage scheme %q in %s (supported: %s)", scheme, location, strings.Join(Schemes(), ", "))
+	}
+
+	fs, err := factory(ctx, location, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open storage for %s: %w", location, err)
+	}
+	return fs, nil
+}
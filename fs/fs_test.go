@@ -0,0 +1,140 @@
+package fs
+
+import (
+	"context"
+	"io"
+	iofs "io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/fs/httpfs"
+	"github.com/TFMV/icebox/fs/local"
+	"github.com/TFMV/icebox/fs/memory"
+	"github.com/TFMV/icebox/fs/minio"
+	"github.com/TFMV/icebox/fs/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Every backend implements FileSystem, and those that list can resume walks
+var (
+	_ FileSystem = (*local.FileSystem)(nil)
+	_ FileSystem = (*memory.MemoryFileSystem)(nil)
+	_ FileSystem = (*s3.FileSystem)(nil)
+	_ FileSystem = (*minio.MinIOFileSystem)(nil)
+	_ FileSystem = (*httpfs.FileSystem)(nil)
+
+	_ ResumableWalker = (*local.FileSystem)(nil)
+	_ ResumableWalker = (*memory.MemoryFileSystem)(nil)
+	_ ResumableWalker = (*s3.FileSystem)(nil)
+	_ ResumableWalker = (*minio.MinIOFileSystem)(nil)
+)
+
+func TestScheme(t *testing.T) {
+	assert.Equal(t, "file", Scheme("/tmp/warehouse"))
+	assert.Equal(t, "file", Scheme("file:///tmp/warehouse"))
+	assert.Equal(t, "file", Scheme(`C:\warehouse`))
+	assert.Equal(t, "mem", Scheme("mem://warehouse"))
+	assert.Equal(t, "s3", Scheme("S3://bucket/warehouse"))
+	assert.Equal(t, "s3a", Scheme("s3a://bucket/warehouse"))
+}
+
+func TestResolve(t *testing.T) {
+	ctx := context.Background()
+
+	fs, err := Resolve(ctx, "/tmp/warehouse", nil)
+	require.NoError(t, err)
+	assert.IsType(t, &local.FileSystem{}, fs)
+
+	fs, err = Resolve(ctx, "mem://warehouse", nil)
+	require.NoError(t, err)
+	assert.Same(t, memory.Shared(), fs)
+
+	cfg := &config.Config{Storage: config.StorageConfig{S3: &config.S3Config{Bucket: "warehouse", Region: "us-east-1"}}}
+	fs, err = Resolve(ctx, "s3://warehouse/data", cfg)
+	require.NoError(t, err)
+	assert.IsType(t, &s3.FileSystem{}, fs)
+
+	// S3 locations need credentials and endpoints from the configuration
+	_, err = Resolve(ctx, "s3://warehouse/data", nil)
+	assert.Error(t, err)
+
+	fs, err = Resolve(ctx, "https://example.com/data/file.parquet", nil)
+	require.NoError(t, err)
+	assert.IsType(t, &httpfs.FileSystem{}, fs)
+
+	_, err = Resolve(ctx, "gs://bucket/data", nil)
+	assert.ErrorContains(t, err, "unsupported storage scheme")
+
+	Register("test", func(ctx context.Context, location string, cfg *config.Config) (FileSystem, error) {
+		return memory.NewMemoryFileSystem(), nil
+	})
+	assert.Contains(t, Schemes(), "test")
+	_, err = Resolve(ctx, "test://data", nil)
+	assert.NoError(t, err)
+}
+
+func TestLocalFileSystem(t *testing.T) {
+	dir := t.TempDir()
+	fs, err := Resolve(context.Background(), "file://"+dir, nil)
+	require.NoError(t, err)
+
+	for _, name := range []string{"b/2.txt", "a.txt", "b/1.txt"} {
+		w, err := fs.Create("file://" + filepath.Join(dir, name))
+		require.NoError(t, err)
+		_, err = io.WriteString(w, name)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	}
+
+	infos, err := fs.List(dir)
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	assert.Equal(t, "a.txt", infos[0].Name())
+	assert.True(t, infos[1].IsDir())
+
+	var walked []string
+	require.NoError(t, fs.Walk("file://"+dir, func(location string, info iofs.FileInfo) error {
+		walked = append(walked, location)
+		return nil
+	}))
+	assert.Equal(t, []string{
+		"file://" + filepath.Join(dir, "a.txt"),
+		"file://" + filepath.Join(dir, "b/1.txt"),
+		"file://" + filepath.Join(dir, "b/2.txt"),
+	}, walked)
+
+	require.NoError(t, fs.Remove(filepath.Join(dir, "a.txt")))
+	_, err = fs.Stat(filepath.Join(dir, "a.txt"))
+	assert.ErrorIs(t, err, iofs.ErrNotExist)
+}
+
+func TestMemoryFileSystem(t *testing.T) {
+	fs, err := Resolve(context.Background(), "mem://", nil)
+	require.NoError(t, err)
+
+	w, err := fs.Create("mem:///warehouse/t/metadata/v1.metadata.json")
+	require.NoError(t, err)
+	_, err = io.WriteString(w, "{}")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	defer fs.Remove("mem:///warehouse/t/metadata/v1.metadata.json")
+
+	file, err := fs.Open("mem:///warehouse/t/metadata/v1.metadata.json")
+	require.NoError(t, err)
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(data))
+
+	info, err := fs.Stat("mem:///warehouse/t")
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+
+	var walked []string
+	require.NoError(t, fs.Walk("mem:///warehouse", func(location string, info iofs.FileInfo) error {
+		walked = append(walked, location)
+		return nil
+	}))
+	assert.Equal(t, []string{"mem:///warehouse/t/metadata/v1.metadata.json"}, walked)
+}
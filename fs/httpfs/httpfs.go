@@ -0,0 +1,250 @@
+// Package httpfs provides read-only access to files served over HTTP(S). Files are
+// read with range requests, so readers that seek, such as Parquet readers starting at
+// the footer, only download the parts they need.
+package httpfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	iofs "io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	icebergio "github.com/apache/iceberg-go/io"
+)
+
+// Schemes lists the URI schemes of HTTP locations
+var Schemes = []string{"http", "https"}
+
+// DefaultTimeout limits each request of a FileSystem created without a client,
+// including reading its response
+const DefaultTimeout = 5 * time.Minute
+
+// FileSystem reads files from HTTP(S) URLs
+type FileSystem struct {
+	ctx    context.Context
+	client *http.Client
+}
+
+// NewFileSystem creates a FileSystem whose requests are canceled with ctx. It uses
+// client, or a client with DefaultTimeout when nil.
+func NewFileSystem(ctx context.Context, client *http.Client) *FileSystem {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	return &FileSystem{ctx: ctx, client: client}
+}
+
+// Open opens a URL for reading
+func (fs *FileSystem) Open(location string) (icebergio.File, error) {
+	info, err := fs.Stat(location)
+	if err != nil {
+		return nil, err
+	}
+	return &file{fs: fs, url: location, info: info.(*fileInfo)}, nil
+}
+
+// Create is not supported; HTTP locations are read-only
+func (fs *FileSystem) Create(location string) (io.WriteCloser, error) {
+	return nil, &iofs.PathError{Op: "create", Path: location, Err: errors.ErrUnsupported}
+}
+
+// Remove is not supported; HTTP locations are read-only
+func (fs *FileSystem) Remove(location string) error {
+	return &iofs.PathError{Op: "remove", Path: location, Err: errors.ErrUnsupported}
+}
+
+// Stat returns the size and modification time the server reports for a URL
+func (fs *FileSystem) Stat(location string) (iofs.FileInfo, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %s: %w", location, err)
+	}
+
+	req, err := http.NewRequestWithContext(fs.ctx, http.MethodHead, location, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %s: %w", location, err)
+	}
+	resp, err := fs.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", location, err)
+	}
+	resp.Body.Close()
+	if err := statusError("stat", location, resp); err != nil {
+		return nil, err
+	}
+
+	info := &fileInfo{name: path.Base(u.Path), size: resp.ContentLength}
+	if modified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.modTime = modified
+	}
+	return info, nil
+}
+
+// List is not supported; HTTP servers have no standard way to list files
+func (fs *FileSystem) List(location string) ([]iofs.FileInfo, error) {
+	return nil, &iofs.PathError{Op: "list", Path: location, Err: errors.ErrUnsupported}
+}
+
+// Walk calls fn for the file at location, the only file a URL names
+func (fs *FileSystem) Walk(location string, fn func(location string, info iofs.FileInfo) error) error {
+	info, err := fs.Stat(location)
+	if err != nil {
+		return err
+	}
+	return fn(location, info)
+}
+
+// statusError converts an unsuccessful response into an error, matching
+// fs.ErrNotExist for missing files
+func statusError(op, location string, resp *http.Response) error {
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return &iofs.PathError{Op: op, Path: location, Err: iofs.ErrNotExist}
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("failed to %s %s: %s", op, location, resp.Status)
+	}
+	return nil
+}
+
+// file reads a URL. Sequential reads stream a single response from the current
+// offset; ReadAt issues a range request per call.
+type file struct {
+	fs     *FileSystem
+	url    string
+	info   *fileInfo
+	offset int64
+	body   io.ReadCloser
+}
+
+func (f *file) Read(p []byte) (int, error) {
+	// A size of -1 means the server did not report one
+	if f.info.size >= 0 && f.offset >= f.info.size {
+		return 0, io.EOF
+	}
+	if f.body == nil {
+		body, err := f.get(f.offset, -1)
+		if err != nil {
+			return 0, err
+		}
+		f.body = body
+	}
+
+	n, err := f.body.Read(p)
+	f.offset += int64(n)
+	if err == io.EOF && f.info.size >= 0 && f.offset < f.info.size {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func (f *file) ReadAt(p []byte, off int64) (int, error) {
+	if f.info.size < 0 {
+		return 0, fmt.Errorf("cannot read %s at an offset: the server did not report its size", f.url)
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if off >= f.info.size {
+		return 0, io.EOF
+	}
+	length := min(int64(len(p)), f.info.size-off)
+
+	body, err := f.get(off, length)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	n, err := io.ReadFull(body, p[:length])
+	if err == nil && length < int64(len(p)) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func (f *file) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.info.size
+	default:
+		return 0, fmt.Errorf("invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("negative position %d", offset)
+	}
+
+	if offset != f.offset && f.body != nil {
+		f.body.Close()
+		f.body = nil
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *file) Stat() (iofs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *file) Close() error {
+	if f.body != nil {
+		f.body.Close()
+		f.body = nil
+	}
+	return nil
+}
+
+// get requests length bytes from off, or the rest of the file when length is -1
+func (f *file) get(off, length int64) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(f.fs.ctx, http.MethodGet, f.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if off > 0 || length >= 0 {
+		end := ""
+		if length >= 0 {
+			end = strconv.FormatInt(off+length-1, 10)
+		}
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%s", off, end))
+	}
+
+	resp, err := f.fs.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", f.url, err)
+	}
+	if err := statusError("read", f.url, resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	// Servers without range support send the whole file, so skip to the offset
+	if resp.StatusCode != http.StatusPartialContent && off > 0 {
+		if _, err := io.CopyN(io.Discard, resp.Body, off); err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to read %s: %w", f.url, err)
+		}
+	}
+	return resp.Body, nil
+}
+
+// fileInfo describes a file served over HTTP
+type fileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+func (i *fileInfo) Name() string        { return i.name }
+func (i *fileInfo) Size() int64         { return i.size }
+func (i *fileInfo) Mode() iofs.FileMode { return 0444 }
+func (i *fileInfo) ModTime() time.Time  { return i.modTime }
+func (i *fileInfo) IsDir() bool         { return false }
+func (i *fileInfo) Sys() interface{}    { return nil }
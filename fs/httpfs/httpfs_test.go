@@ -0,0 +1,127 @@
+package httpfs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	iofs "io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testContent = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// newTestServer serves testContent at /data/file.parquet, counting the bytes sent
+func newTestServer(t *testing.T, ranges bool) (string, *atomic.Int64) {
+	t.Helper()
+
+	var sent atomic.Int64
+	modified := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data/file.parquet" {
+			http.NotFound(w, r)
+			return
+		}
+		if !ranges {
+			r.Header.Del("Range")
+		}
+		rec := httptest.NewRecorder()
+		http.ServeContent(rec, r, "file.parquet", modified, strings.NewReader(testContent))
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(rec.Code)
+		n, _ := w.Write(rec.Body.Bytes())
+		sent.Add(int64(n))
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL + "/data/file.parquet", &sent
+}
+
+func TestStat(t *testing.T) {
+	location, _ := newTestServer(t, true)
+	fs := NewFileSystem(context.Background(), nil)
+
+	info, err := fs.Stat(location)
+	require.NoError(t, err)
+	assert.Equal(t, "file.parquet", info.Name())
+	assert.Equal(t, int64(len(testContent)), info.Size())
+	assert.Equal(t, 2025, info.ModTime().Year())
+
+	_, err = fs.Stat(strings.TrimSuffix(location, "file.parquet") + "missing.parquet")
+	assert.ErrorIs(t, err, iofs.ErrNotExist)
+
+	_, err = fs.Create(location)
+	assert.True(t, errors.Is(err, errors.ErrUnsupported))
+}
+
+func TestRangedReads(t *testing.T) {
+	location, sent := newTestServer(t, true)
+	file, err := NewFileSystem(context.Background(), nil).Open(location)
+	require.NoError(t, err)
+	defer file.Close()
+
+	// Reading the last bytes, as a Parquet footer is read, only fetches those bytes
+	footer := make([]byte, 4)
+	n, err := file.ReadAt(footer, int64(len(testContent)-4))
+	require.NoError(t, err)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, "wxyz", string(footer))
+	assert.Equal(t, int64(4), sent.Load())
+
+	// Reads past the end are short
+	n, err = file.ReadAt(make([]byte, 10), int64(len(testContent)-2))
+	assert.Equal(t, 2, n)
+	assert.Equal(t, io.EOF, err)
+
+	// Sequential reads stream from the current offset
+	_, err = file.Seek(10, io.SeekStart)
+	require.NoError(t, err)
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	assert.Equal(t, testContent[10:], string(data))
+}
+
+func TestReadsWithoutRangeSupport(t *testing.T) {
+	location, _ := newTestServer(t, false)
+	file, err := NewFileSystem(context.Background(), nil).Open(location)
+	require.NoError(t, err)
+	defer file.Close()
+
+	buf := make([]byte, 5)
+	_, err = file.ReadAt(buf, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "abcde", string(buf))
+
+	var all bytes.Buffer
+	_, err = io.Copy(&all, file)
+	require.NoError(t, err)
+	assert.Equal(t, testContent, all.String())
+}
+
+func TestCanceledContext(t *testing.T) {
+	location, sent := newTestServer(t, true)
+	ctx, cancel := context.WithCancel(context.Background())
+	fs := NewFileSystem(ctx, nil)
+
+	file, err := fs.Open(location)
+	require.NoError(t, err)
+	defer file.Close()
+
+	// Requests made after the import is canceled fail without reaching the server
+	cancel()
+	_, err = file.ReadAt(make([]byte, 4), 0)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = fs.Stat(location)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, sent.Load())
+}
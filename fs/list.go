@@ -0,0 +1,127 @@
+package fs
+
+import (
+	"errors"
+	iofs "io/fs"
+	"strings"
+)
+
+// DefaultPageSize is the number of entries in a page when no limit is given
+const DefaultPageSize = 1000
+
+// ResumableWalker is implemented by file systems that can resume a walk after a
+// location without revisiting the files before it. Object stores use it to start a
+// listing at a key rather than at the beginning of the prefix.
+type ResumableWalker interface {
+	WalkFrom(location, startAfter string, fn func(location string, info iofs.FileInfo) error) error
+}
+
+// Entry is a file found by a listing
+type Entry struct {
+	Location string
+	Info     iofs.FileInfo
+}
+
+// Page is one page of a prefix listing
+type Page struct {
+	Entries []Entry
+	// NextToken resumes the listing after the last entry; it is empty on the last page
+	NextToken string
+}
+
+// errPageFull stops a walk once a page has been filled
+var errPageFull = errors.New("page full")
+
+// ListPrefix lists the files whose locations start with prefix in walk order, at most
+// limit at a time. The listing starts after token, which is the NextToken of the
+// previous page or empty for the first page. A prefix ending in "/" lists a directory;
+// otherwise it may end part way through a name, as in "s3://bucket/data/part-".
+func ListPrefix(fsys FileSystem, prefix, token string, limit int) (*Page, error) {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+
+	page := &Page{}
+	collect := func(location string, info iofs.FileInfo) error {
+		if !strings.HasPrefix(location, prefix) {
+			return nil
+		}
+		if len(page.Entries) == limit {
+			// A further entry exists, so the listing continues after the last one
+			page.NextToken = page.Entries[limit-1].Location
+			return errPageFull
+		}
+		page.Entries = append(page.Entries, Entry{Location: location, Info: info})
+		return nil
+	}
+
+	var err error
+	base := listBase(prefix)
+	if walker, ok := fsys.(ResumableWalker); ok {
+		err = walker.WalkFrom(base, token, collect)
+	} else {
+		// Without resumable walks, skip the files up to and including the token
+		skipping := token != ""
+		err = fsys.Walk(base, func(location string, info iofs.FileInfo) error {
+			if skipping {
+				skipping = location != token
+				return nil
+			}
+			return collect(location, info)
+		})
+	}
+
+	switch {
+	case errors.Is(err, errPageFull):
+	case errors.Is(err, iofs.ErrNotExist):
+		// Nothing has been written below the prefix yet
+		return &Page{}, nil
+	case err != nil:
+		return nil, err
+	}
+	return page, nil
+}
+
+// WalkPrefix calls fn for every file whose location starts with prefix, fetching the
+// listing a page at a time
+func WalkPrefix(fsys FileSystem, prefix string, fn func(location string, info iofs.FileInfo) error) error {
+	token := ""
+	for {
+		page, err := ListPrefix(fsys, prefix, token, DefaultPageSize)
+		if err != nil {
+			return err
+		}
+		for _, entry := range page.Entries {
+			if err := fn(entry.Location, entry.Info); err != nil {
+				return err
+			}
+		}
+		if page.NextToken == "" {
+			return nil
+		}
+		token = page.NextToken
+	}
+}
+
+// listBase returns the directory to walk for a prefix: the prefix itself when it ends
+// in "/", or the directory containing the partial name it ends with
+func listBase(prefix string) string {
+	// The directory of a prefix ending in "/" is the directory of any name below it
+	if strings.HasSuffix(prefix, "/") {
+		prefix += "-"
+	}
+
+	slash := strings.LastIndex(prefix, "/")
+	switch {
+	case slash < 0:
+		// A relative name without a directory
+		return "."
+	case strings.HasSuffix(prefix[:slash+1], "://"):
+		// A bucket, as in "s3://bucket"
+		return prefix
+	case slash == 0 || strings.HasSuffix(prefix[:slash], "://"):
+		// A name in the root directory, as in "/part-" or "file:///part-"
+		return prefix[:slash+1]
+	}
+	return prefix[:slash]
+}
@@ -0,0 +1,138 @@
+package fs
+
+import (
+	"context"
+	"io"
+	iofs "io/fs"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/fs/memory"
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFiles(t *testing.T, fsys FileSystem, locations ...string) {
+	t.Helper()
+
+	for _, location := range locations {
+		w, err := fsys.Create(location)
+		require.NoError(t, err)
+		_, err = io.WriteString(w, location)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	}
+}
+
+// listAll pages through a prefix and returns the locations of every page
+func listAll(t *testing.T, fsys FileSystem, prefix string, limit int) [][]string {
+	t.Helper()
+
+	var pages [][]string
+	token := ""
+	for {
+		page, err := ListPrefix(fsys, prefix, token, limit)
+		require.NoError(t, err)
+
+		var locations []string
+		for _, entry := range page.Entries {
+			locations = append(locations, entry.Location)
+		}
+		pages = append(pages, locations)
+
+		if page.NextToken == "" {
+			return pages
+		}
+		token = page.NextToken
+	}
+}
+
+func TestListBase(t *testing.T) {
+	tests := map[string]string{
+		"s3://bucket/data/part-": "s3://bucket/data",
+		"s3://bucket/data/":      "s3://bucket/data",
+		"s3://bucket/":           "s3://bucket",
+		"s3://bucket":            "s3://bucket",
+		"file:///tmp/part-":      "file:///tmp",
+		"file:///part-":          "file:///",
+		"/part-":                 "/",
+		"/":                      "/",
+		"part-":                  ".",
+	}
+	for prefix, base := range tests {
+		assert.Equal(t, base, listBase(prefix), prefix)
+	}
+}
+
+func TestListPrefixLocal(t *testing.T) {
+	dir := t.TempDir()
+	fsys, err := Resolve(context.Background(), dir, nil)
+	require.NoError(t, err)
+
+	// Files are walked by path element, so "a/b" comes before "a-c"
+	writeTestFiles(t, fsys,
+		filepath.Join(dir, "a-c"),
+		filepath.Join(dir, "a", "b"),
+		filepath.Join(dir, "a", "c"),
+		filepath.Join(dir, "b"),
+	)
+
+	pages := listAll(t, fsys, dir+"/", 2)
+	assert.Equal(t, [][]string{
+		{filepath.Join(dir, "a", "b"), filepath.Join(dir, "a", "c")},
+		{filepath.Join(dir, "a-c"), filepath.Join(dir, "b")},
+	}, pages)
+
+	// Prefixes may end part way through a name
+	pages = listAll(t, fsys, filepath.Join(dir, "a"), 10)
+	assert.Equal(t, [][]string{{filepath.Join(dir, "a", "b"), filepath.Join(dir, "a", "c"), filepath.Join(dir, "a-c")}}, pages)
+
+	pages = listAll(t, fsys, filepath.Join(dir, "missing")+"/", 10)
+	assert.Equal(t, [][]string{nil}, pages)
+}
+
+func TestListPrefixMemory(t *testing.T) {
+	fsys := memory.NewMemoryFileSystem()
+	writeTestFiles(t, fsys, "/warehouse/t/data/part-1", "/warehouse/t/data/part-2", "/warehouse/t/data/part-3", "/warehouse/t/data/other")
+
+	pages := listAll(t, fsys, "/warehouse/t/data/part-", 2)
+	assert.Equal(t, [][]string{
+		{"/warehouse/t/data/part-1", "/warehouse/t/data/part-2"},
+		{"/warehouse/t/data/part-3"},
+	}, pages)
+}
+
+func TestListPrefixS3(t *testing.T) {
+	backend := s3mem.New()
+	require.NoError(t, backend.CreateBucket("warehouse"))
+	server := httptest.NewServer(gofakes3.New(backend).Server())
+	defer server.Close()
+
+	cfg := &config.Config{Storage: config.StorageConfig{S3: &config.S3Config{
+		Bucket:          "warehouse",
+		Endpoint:        server.URL,
+		AccessKeyID:     "test",
+		SecretAccessKey: "test",
+	}}}
+	fsys, err := Resolve(context.Background(), "s3://warehouse", cfg)
+	require.NoError(t, err)
+
+	writeTestFiles(t, fsys, "s3://warehouse/t/data/part-1", "s3://warehouse/t/data/part-2", "s3://warehouse/t/data/part-3", "s3://warehouse/t2/data/part-1")
+
+	pages := listAll(t, fsys, "s3://warehouse/t/data/part-", 2)
+	assert.Equal(t, [][]string{
+		{"s3://warehouse/t/data/part-1", "s3://warehouse/t/data/part-2"},
+		{"s3://warehouse/t/data/part-3"},
+	}, pages)
+
+	var walked []string
+	require.NoError(t, WalkPrefix(fsys, "s3://warehouse/", func(location string, info iofs.FileInfo) error {
+		walked = append(walked, location)
+		return nil
+	}))
+	assert.Len(t, walked, 4)
+}
@@ -3,12 +3,19 @@ package local
 import (
 	"fmt"
 	"io"
+	iofs "io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+
+	icebergio "github.com/apache/iceberg-go/io"
 )
 
-// FileSystem implements the iceberg-go FileIO interface for local filesystem
+// Scheme is the URI scheme of local file locations
+const Scheme = "file"
+
+// FileSystem implements file operations on the local filesystem
 type FileSystem struct {
 	basePath string
 }
@@ -29,7 +36,7 @@ func EnsureDir(dir string) error {
 }
 
 // Open opens a file for reading
-func (fs *FileSystem) Open(path string) (io.ReadCloser, error) {
+func (fs *FileSystem) Open(path string) (icebergio.File, error) {
 	localPath := fs.toLocalPath(path)
 	file, err := os.Open(localPath)
 	if err != nil {
@@ -76,10 +83,96 @@ func (fs *FileSystem) Exists(path string) (bool, error) {
 	return true, nil
 }
 
+// Stat returns information about a file or directory
+func (fs *FileSystem) Stat(path string) (os.FileInfo, error) {
+	info, err := os.Stat(fs.toLocalPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	return info, nil
+}
+
+// List returns the entries of a directory sorted by name
+func (fs *FileSystem) List(path string) ([]os.FileInfo, error) {
+	entries, err := os.ReadDir(fs.toLocalPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list directory %s: %w", path, err)
+	}
+
+	infos := make([]os.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			// Removed while listing
+			continue
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name() < infos[j].Name() })
+	return infos, nil
+}
+
+// Walk calls fn for every file below path in lexical order. Locations passed to fn
+// keep the file:// scheme when path has it.
+func (fs *FileSystem) Walk(path string, fn func(location string, info os.FileInfo) error) error {
+	return fs.WalkFrom(path, "", fn)
+}
+
+// WalkFrom is like Walk but skips the files up to and including startAfter, so that a
+// walk can be resumed where an earlier one stopped. Directories that sort entirely
+// before startAfter are not read.
+func (fs *FileSystem) WalkFrom(path, startAfter string, fn func(location string, info os.FileInfo) error) error {
+	prefix := ""
+	if strings.HasPrefix(path, Scheme+"://") {
+		prefix = Scheme + "://"
+	}
+	after := ""
+	if startAfter != "" {
+		after = filepath.Clean(fs.toLocalPath(startAfter))
+	}
+
+	err := filepath.WalkDir(fs.toLocalPath(path), func(p string, d iofs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if after != "" && !walksAfter(p, after) {
+			if d.IsDir() && !strings.HasPrefix(after, p+string(filepath.Separator)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return fn(prefix+p, info)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk %s: %w", path, err)
+	}
+	return nil
+}
+
+// walksAfter reports whether WalkDir visits p after after. WalkDir orders by path
+// element rather than by the whole path, so "a/b" comes before "a-c".
+func walksAfter(p, after string) bool {
+	pElems := strings.Split(p, string(filepath.Separator))
+	afterElems := strings.Split(after, string(filepath.Separator))
+	for i := 0; i < len(pElems) && i < len(afterElems); i++ {
+		if pElems[i] != afterElems[i] {
+			return pElems[i] > afterElems[i]
+		}
+	}
+	return len(pElems) > len(afterElems)
+}
+
 // toLocalPath converts a URI to a local filesystem path
 func (fs *FileSystem) toLocalPath(uri string) string {
 	// Remove file:// prefix if present
-	path := strings.TrimPrefix(uri, "file://")
+	path := strings.TrimPrefix(uri, Scheme+"://")
 
 	// If path is relative to base, join with base path
 	if !filepath.IsAbs(path) {
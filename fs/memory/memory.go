@@ -45,6 +45,12 @@ type memoryFileInfo struct {
 	isDir   bool
 }
 
+// Scheme is the URI scheme of locations in the shared memory file system
+const Scheme = "mem"
+
+// shared backs mem:// locations, so every user of the scheme sees the same files
+var shared = NewMemoryFileSystem()
+
 // NewMemoryFileSystem creates a new in-memory file system
 func NewMemoryFileSystem() *MemoryFileSystem {
 	return &MemoryFileSystem{
@@ -53,12 +59,22 @@ func NewMemoryFileSystem() *MemoryFileSystem {
 	}
 }
 
+// Shared returns the process-wide memory file system used for mem:// locations
+func Shared() *MemoryFileSystem {
+	return shared
+}
+
+// memoryPath converts a path or mem:// location to the key files are stored under
+func memoryPath(location string) string {
+	return filepath.Clean(strings.TrimPrefix(location, Scheme+"://"))
+}
+
 // Open opens a file for reading
 func (mfs *MemoryFileSystem) Open(path string) (io.File, error) {
 	mfs.mu.RLock()
 	defer mfs.mu.RUnlock()
 
-	cleanPath := filepath.Clean(path)
+	cleanPath := memoryPath(path)
 	file, exists := mfs.files[cleanPath]
 	if !exists {
 		return nil, &os.PathError{
@@ -81,9 +97,9 @@ func (mfs *MemoryFileSystem) Open(path string) (io.File, error) {
 	}, nil
 }
 
-// Create creates a new file for writing
-func (mfs *MemoryFileSystem) Create(path string) (io.File, error) {
-	cleanPath := filepath.Clean(path)
+// Create creates a new file for writing; its contents become visible when it is closed
+func (mfs *MemoryFileSystem) Create(path string) (stdio.WriteCloser, error) {
+	cleanPath := memoryPath(path)
 
 	// Ensure parent directories exist
 	if err := mfs.ensureParentDirs(cleanPath); err != nil {
@@ -102,7 +118,7 @@ func (mfs *MemoryFileSystem) Remove(path string) error {
 	mfs.mu.Lock()
 	defer mfs.mu.Unlock()
 
-	cleanPath := filepath.Clean(path)
+	cleanPath := memoryPath(path)
 	if _, exists := mfs.files[cleanPath]; !exists {
 		return &os.PathError{
 			Op:   "remove",
@@ -120,7 +136,7 @@ func (mfs *MemoryFileSystem) Exists(path string) (bool, error) {
 	mfs.mu.RLock()
 	defer mfs.mu.RUnlock()
 
-	cleanPath := filepath.Clean(path)
+	cleanPath := memoryPath(path)
 	_, fileExists := mfs.files[cleanPath]
 	dirExists := mfs.dirs[cleanPath]
 
@@ -132,7 +148,7 @@ func (mfs *MemoryFileSystem) Stat(path string) (os.FileInfo, error) {
 	mfs.mu.RLock()
 	defer mfs.mu.RUnlock()
 
-	cleanPath := filepath.Clean(path)
+	cleanPath := memoryPath(path)
 
 	// Check if it's a file
 	if file, exists := mfs.files[cleanPath]; exists {
@@ -166,7 +182,7 @@ func (mfs *MemoryFileSystem) ListDir(path string) ([]os.FileInfo, error) {
 	mfs.mu.RLock()
 	defer mfs.mu.RUnlock()
 
-	cleanPath := filepath.Clean(path)
+	cleanPath := memoryPath(path)
 	if !mfs.dirs[cleanPath] {
 		return nil, &os.PathError{
 			Op:   "readdir",
@@ -218,12 +234,62 @@ func (mfs *MemoryFileSystem) ListDir(path string) ([]os.FileInfo, error) {
 	return infos, nil
 }
 
+// List lists directory contents sorted by name
+func (mfs *MemoryFileSystem) List(path string) ([]fs.FileInfo, error) {
+	return mfs.ListDir(path)
+}
+
+// Walk calls fn for every file below path in lexical order. Locations passed to fn
+// keep the mem:// scheme when path has it.
+func (mfs *MemoryFileSystem) Walk(path string, fn func(location string, info fs.FileInfo) error) error {
+	return mfs.WalkFrom(path, "", fn)
+}
+
+// WalkFrom is like Walk but skips the files up to and including startAfter, so that a
+// walk can be resumed where an earlier one stopped
+func (mfs *MemoryFileSystem) WalkFrom(path, startAfter string, fn func(location string, info fs.FileInfo) error) error {
+	root := memoryPath(path)
+	after := ""
+	if startAfter != "" {
+		after = memoryPath(startAfter)
+	}
+
+	mfs.mu.RLock()
+	var paths []string
+	for filePath := range mfs.files {
+		if after != "" && filePath <= after {
+			continue
+		}
+		if filePath == root || strings.HasPrefix(filePath, strings.TrimSuffix(root, "/")+"/") {
+			paths = append(paths, filePath)
+		}
+	}
+	mfs.mu.RUnlock()
+	sort.Strings(paths)
+
+	prefix := ""
+	if strings.HasPrefix(path, Scheme+"://") {
+		prefix = Scheme + "://"
+	}
+	for _, filePath := range paths {
+		info, err := mfs.Stat(filePath)
+		if err != nil {
+			// Removed while walking
+			continue
+		}
+		if err := fn(prefix+filePath, info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // MkdirAll creates directories recursively
 func (mfs *MemoryFileSystem) MkdirAll(path string, perm os.FileMode) error {
 	mfs.mu.Lock()
 	defer mfs.mu.Unlock()
 
-	cleanPath := filepath.Clean(path)
+	cleanPath := memoryPath(path)
 	// First ensure parent directories exist
 	if err := mfs.ensureParentDirsLocked(cleanPath); err != nil {
 		return err
@@ -239,15 +305,12 @@ func (mfs *MemoryFileSystem) WriteFile(path string, data []byte) error {
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
-	// Cast to our memory write file to access Write method
-	if writeFile, ok := file.(*memoryWriteFile); ok {
-		_, err = writeFile.Write(data)
+	if _, err := file.Write(data); err != nil {
+		file.Close()
 		return err
 	}
-
-	return fmt.Errorf("file does not support writing")
+	return file.Close()
 }
 
 // ReadFile reads data from a file (convenience method)
@@ -259,7 +322,7 @@ func (mfs *MemoryFileSystem) ReadFile(path string) ([]byte, error) {
 	defer file.Close()
 
 	mfs.mu.RLock()
-	cleanPath := filepath.Clean(path)
+	cleanPath := memoryPath(path)
 	memFile := mfs.files[cleanPath]
 	mfs.mu.RUnlock()
 
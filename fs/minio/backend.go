@@ -0,0 +1,1008 @@
+package minio
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johannesboyne/gofakes3"
+)
+
+// Layout of a FileBackend data directory:
+//
+//	buckets/<bucket>/<key>       object contents, one file per key
+//	metadata/<bucket>.json       bucket metadata
+//	metadata/<bucket>/<key>      object metadata as JSON
+//	uploads/<upload-id>/         pending multipart uploads and their parts
+//	tmp/                         staging area for atomic writes
+const (
+	bucketsDirName  = "buckets"
+	metadataDirName = "metadata"
+	uploadsDirName  = "uploads"
+	tmpDirName      = "tmp"
+
+	uploadFileName = "upload.json"
+)
+
+var (
+	_ gofakes3.Backend          = &FileBackend{}
+	_ gofakes3.MultipartBackend = &FileBackend{}
+)
+
+// FileBackend is a gofakes3 backend that stores buckets and objects on the local
+// filesystem, so the embedded server keeps its data across restarts.
+//
+// Object keys map to nested files, which means a key cannot also be the prefix
+// directory of another key (for example "a" and "a/b").
+type FileBackend struct {
+	root string
+	mu   sync.RWMutex
+}
+
+// bucketMetadata is persisted for every bucket
+type bucketMetadata struct {
+	CreationDate time.Time `json:"creation_date"`
+}
+
+// objectMetadata is persisted next to every object
+type objectMetadata struct {
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	Size         int64             `json:"size"`
+	Hash         string            `json:"hash"`
+	LastModified time.Time         `json:"last_modified"`
+}
+
+// uploadMetadata describes a pending multipart upload
+type uploadMetadata struct {
+	Bucket    string            `json:"bucket"`
+	Object    string            `json:"object"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Initiated time.Time         `json:"initiated"`
+}
+
+// partMetadata describes an uploaded part of a multipart upload
+type partMetadata struct {
+	ETag         string    `json:"etag"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// NewFileBackend creates a filesystem backend rooted at root
+func NewFileBackend(root string) (*FileBackend, error) {
+	for _, dir := range []string{bucketsDirName, metadataDirName, uploadsDirName, tmpDirName} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0750); err != nil {
+			return nil, fmt.Errorf("failed to create storage directory: %w", err)
+		}
+	}
+	return &FileBackend{root: root}, nil
+}
+
+// ListBuckets returns all buckets sorted by name
+func (b *FileBackend) ListBuckets() ([]gofakes3.BucketInfo, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	entries, err := os.ReadDir(filepath.Join(b.root, bucketsDirName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list buckets: %w", err)
+	}
+
+	buckets := make([]gofakes3.BucketInfo, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		meta, err := b.readBucketMetadata(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, gofakes3.BucketInfo{
+			Name:         entry.Name(),
+			CreationDate: gofakes3.NewContentTime(meta.CreationDate),
+		})
+	}
+	return buckets, nil
+}
+
+// ListBucket lists the objects of a bucket in key order
+func (b *FileBackend) ListBucket(name string, prefix *gofakes3.Prefix, page gofakes3.ListBucketPage) (*gofakes3.ObjectList, error) {
+	if prefix == nil {
+		prefix = &gofakes3.Prefix{}
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if !b.bucketExists(name) {
+		return nil, gofakes3.BucketNotFound(name)
+	}
+
+	keys, err := b.listKeys(name, prefix.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	response := gofakes3.NewObjectList()
+	var match gofakes3.PrefixMatch
+	var lastMatchedPart string
+	var count int64
+
+	for i, key := range keys {
+		if page.HasMarker && key <= page.Marker {
+			continue
+		}
+
+		switch {
+		case !prefix.Match(key, &match):
+			continue
+		case match.CommonPrefix:
+			if match.MatchedPart == lastMatchedPart {
+				continue
+			}
+			response.AddPrefix(match.MatchedPart)
+			lastMatchedPart = match.MatchedPart
+		default:
+			meta, err := b.readObjectMetadata(name, key)
+			if err != nil {
+				return nil, err
+			}
+			response.Add(&gofakes3.Content{
+				Key:          key,
+				LastModified: gofakes3.NewContentTime(meta.LastModified),
+				ETag:         `"` + meta.Hash + `"`,
+				Size:         meta.Size,
+			})
+		}
+
+		count++
+		if page.MaxKeys > 0 && count >= page.MaxKeys {
+			response.NextMarker = key
+			response.IsTruncated = i < len(keys)-1
+			break
+		}
+	}
+
+	return response, nil
+}
+
+// CreateBucket creates an empty bucket
+func (b *FileBackend) CreateBucket(name string) error {
+	if err := validateBackendBucket(name); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.bucketExists(name) {
+		return gofakes3.ResourceError(gofakes3.ErrBucketAlreadyExists, name)
+	}
+
+	if err := os.Mkdir(b.bucketDir(name), 0750); err != nil {
+		return fmt.Errorf("failed to create bucket %s: %w", name, err)
+	}
+	return b.writeJSON(filepath.Join(b.root, metadataDirName, name+".json"), &bucketMetadata{CreationDate: time.Now().UTC()})
+}
+
+// BucketExists reports whether a bucket exists
+func (b *FileBackend) BucketExists(name string) (bool, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.bucketExists(name), nil
+}
+
+// DeleteBucket deletes an empty bucket
+func (b *FileBackend) DeleteBucket(name string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.bucketExists(name) {
+		return gofakes3.BucketNotFound(name)
+	}
+
+	keys, err := b.listKeys(name, "")
+	if err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		return gofakes3.ResourceError(gofakes3.ErrBucketNotEmpty, name)
+	}
+	return b.removeBucket(name)
+}
+
+// ForceDeleteBucket deletes a bucket and all of its objects
+func (b *FileBackend) ForceDeleteBucket(name string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.bucketExists(name) {
+		return gofakes3.BucketNotFound(name)
+	}
+	return b.removeBucket(name)
+}
+
+// HeadObject returns an object's metadata without its contents
+func (b *FileBackend) HeadObject(bucketName, objectName string) (*gofakes3.Object, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	meta, err := b.statObject(bucketName, objectName)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, _ := hex.DecodeString(meta.Hash)
+	return &gofakes3.Object{
+		Name:     objectName,
+		Metadata: meta.Metadata,
+		Size:     meta.Size,
+		Hash:     hash,
+		Contents: io.NopCloser(strings.NewReader("")),
+	}, nil
+}
+
+// GetObject returns an object, or the requested range of it
+func (b *FileBackend) GetObject(bucketName, objectName string, rangeRequest *gofakes3.ObjectRangeRequest) (*gofakes3.Object, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	meta, err := b.statObject(bucketName, objectName)
+	if err != nil {
+		return nil, err
+	}
+
+	objectRange, err := rangeRequest.Range(meta.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	// An open file keeps its contents even if the object is replaced while it is read
+	file, err := os.Open(b.objectPath(bucketName, objectName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open object %s: %w", objectName, err)
+	}
+
+	var contents io.ReadCloser = file
+	if objectRange != nil {
+		if _, err := file.Seek(objectRange.Start, io.SeekStart); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to seek object %s: %w", objectName, err)
+		}
+		contents = struct {
+			io.Reader
+			io.Closer
+		}{io.LimitReader(file, objectRange.Length), file}
+	}
+
+	hash, _ := hex.DecodeString(meta.Hash)
+	return &gofakes3.Object{
+		Name:     objectName,
+		Metadata: meta.Metadata,
+		Size:     meta.Size,
+		Hash:     hash,
+		Range:    objectRange,
+		Contents: contents,
+	}, nil
+}
+
+// PutObject stores an object, keeping metadata of a replaced object that is not overridden
+func (b *FileBackend) PutObject(bucketName, key string, meta map[string]string, input io.Reader, size int64) (gofakes3.PutObjectResult, error) {
+	var result gofakes3.PutObjectResult
+	if err := validateBackendKey(key); err != nil {
+		return result, err
+	}
+
+	// Stage the contents without holding the lock, as a slow client could block it
+	staged, hash, written, err := b.stage(input, size)
+	if err != nil {
+		return result, err
+	}
+	defer os.Remove(staged)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return result, b.commitObject(bucketName, key, meta, staged, hex.EncodeToString(hash), written)
+}
+
+// CopyObject copies an object within or between buckets
+func (b *FileBackend) CopyObject(srcBucket, srcKey, dstBucket, dstKey string, meta map[string]string) (gofakes3.CopyObjectResult, error) {
+	return gofakes3.CopyObject(b, srcBucket, srcKey, dstBucket, dstKey, meta)
+}
+
+// DeleteObject deletes an object; deleting a missing object is not an error
+func (b *FileBackend) DeleteObject(bucketName, objectName string) (gofakes3.ObjectDeleteResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.bucketExists(bucketName) {
+		return gofakes3.ObjectDeleteResult{}, gofakes3.BucketNotFound(bucketName)
+	}
+	return gofakes3.ObjectDeleteResult{}, b.removeObject(bucketName, objectName)
+}
+
+// DeleteMulti deletes several objects, reporting failures per object
+func (b *FileBackend) DeleteMulti(bucketName string, objects ...string) (gofakes3.MultiDeleteResult, error) {
+	var result gofakes3.MultiDeleteResult
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.bucketExists(bucketName) {
+		return result, gofakes3.BucketNotFound(bucketName)
+	}
+
+	for _, object := range objects {
+		if err := b.removeObject(bucketName, object); err != nil {
+			result.Error = append(result.Error, gofakes3.ErrorResultFromError(err))
+			continue
+		}
+		result.Deleted = append(result.Deleted, gofakes3.ObjectID{Key: object})
+	}
+	return result, nil
+}
+
+// CreateMultipartUpload starts a multipart upload
+func (b *FileBackend) CreateMultipartUpload(bucket, object string, meta map[string]string) (gofakes3.UploadID, error) {
+	if err := validateBackendKey(object); err != nil {
+		return "", err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.bucketExists(bucket) {
+		return "", gofakes3.BucketNotFound(bucket)
+	}
+
+	id, err := newUploadID()
+	if err != nil {
+		return "", err
+	}
+
+	dir := b.uploadDir(id)
+	if err := os.Mkdir(dir, 0750); err != nil {
+		return "", fmt.Errorf("failed to create upload %s: %w", id, err)
+	}
+
+	upload := &uploadMetadata{Bucket: bucket, Object: object, Metadata: meta, Initiated: time.Now().UTC()}
+	if err := b.writeJSON(filepath.Join(dir, uploadFileName), upload); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return id, nil
+}
+
+// UploadPart stores one part of a multipart upload
+func (b *FileBackend) UploadPart(bucket, object string, id gofakes3.UploadID, partNumber int, contentLength int64, input io.Reader) (string, error) {
+	if partNumber <= 0 || partNumber > gofakes3.MaxUploadPartNumber {
+		return "", gofakes3.ErrInvalidPart
+	}
+
+	staged, hash, written, err := b.stage(input, contentLength)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(staged)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, err := b.readUpload(bucket, object, id); err != nil {
+		return "", err
+	}
+
+	etag := `"` + hex.EncodeToString(hash) + `"`
+	partPath := b.partPath(id, partNumber)
+	if err := os.Rename(staged, partPath); err != nil {
+		return "", fmt.Errorf("failed to store part %d: %w", partNumber, err)
+	}
+	part := &partMetadata{ETag: etag, Size: written, LastModified: time.Now().UTC()}
+	if err := b.writeJSON(partPath+".json", part); err != nil {
+		return "", err
+	}
+	return etag, nil
+}
+
+// ListMultipartUploads lists the pending uploads of a bucket ordered by key and initiation time
+func (b *FileBackend) ListMultipartUploads(bucket string, marker *gofakes3.UploadListMarker, prefix gofakes3.Prefix, limit int64) (*gofakes3.ListMultipartUploadsResult, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if !b.bucketExists(bucket) {
+		return nil, gofakes3.BucketNotFound(bucket)
+	}
+
+	type pendingUpload struct {
+		id gofakes3.UploadID
+		*uploadMetadata
+	}
+
+	entries, err := os.ReadDir(filepath.Join(b.root, uploadsDirName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list uploads: %w", err)
+	}
+
+	var uploads []pendingUpload
+	for _, entry := range entries {
+		id := gofakes3.UploadID(entry.Name())
+		var upload uploadMetadata
+		if err := b.readJSON(filepath.Join(b.uploadDir(id), uploadFileName), &upload); err != nil {
+			continue
+		}
+		if upload.Bucket == bucket {
+			uploads = append(uploads, pendingUpload{id: id, uploadMetadata: &upload})
+		}
+	}
+	sort.Slice(uploads, func(i, j int) bool {
+		if uploads[i].Object != uploads[j].Object {
+			return uploads[i].Object < uploads[j].Object
+		}
+		return uploads[i].Initiated.Before(uploads[j].Initiated)
+	})
+
+	result := &gofakes3.ListMultipartUploadsResult{
+		Bucket:     bucket,
+		Delimiter:  prefix.Delimiter,
+		Prefix:     prefix.Prefix,
+		MaxUploads: limit,
+	}
+	if marker != nil {
+		result.KeyMarker = marker.Object
+		result.UploadIDMarker = marker.UploadID
+	}
+
+	seenPrefixes := map[string]bool{}
+	var match gofakes3.PrefixMatch
+	var last *pendingUpload
+	var count int64
+
+	for i := range uploads {
+		upload := &uploads[i]
+		if marker != nil {
+			if upload.Object < marker.Object {
+				continue
+			}
+			// Without an upload ID marker, listing starts after the marker key
+			if upload.Object == marker.Object && (marker.UploadID == "" || upload.id <= marker.UploadID) {
+				continue
+			}
+		}
+		if !prefix.Match(upload.Object, &match) {
+			continue
+		}
+
+		if match.CommonPrefix {
+			if !seenPrefixes[match.MatchedPart] {
+				result.CommonPrefixes = append(result.CommonPrefixes, match.AsCommonPrefix())
+				seenPrefixes[match.MatchedPart] = true
+			}
+			continue
+		}
+
+		if limit > 0 && count >= limit {
+			result.IsTruncated = true
+			result.NextKeyMarker = last.Object
+			result.NextUploadIDMarker = last.id
+			break
+		}
+		result.Uploads = append(result.Uploads, gofakes3.ListMultipartUploadItem{
+			StorageClass: "STANDARD",
+			Key:          upload.Object,
+			UploadID:     upload.id,
+			Initiated:    gofakes3.NewContentTime(upload.Initiated),
+		})
+		last = upload
+		count++
+	}
+
+	return result, nil
+}
+
+// ListParts lists the uploaded parts of a multipart upload after marker
+func (b *FileBackend) ListParts(bucket, object string, uploadID gofakes3.UploadID, marker int, limit int64) (*gofakes3.ListMultipartUploadPartsResult, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if _, err := b.readUpload(bucket, object, uploadID); err != nil {
+		return nil, err
+	}
+
+	partNumbers, err := b.listParts(uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &gofakes3.ListMultipartUploadPartsResult{
+		Bucket:           bucket,
+		Key:              object,
+		UploadID:         uploadID,
+		MaxParts:         limit,
+		PartNumberMarker: marker,
+		StorageClass:     "STANDARD",
+	}
+
+	var count int64
+	for _, partNumber := range partNumbers {
+		if partNumber <= marker {
+			continue
+		}
+		if limit > 0 && count >= limit {
+			result.IsTruncated = true
+			break
+		}
+
+		var part partMetadata
+		if err := b.readJSON(b.partPath(uploadID, partNumber)+".json", &part); err != nil {
+			return nil, err
+		}
+		result.Parts = append(result.Parts, gofakes3.ListMultipartUploadPartItem{
+			PartNumber:   partNumber,
+			ETag:         part.ETag,
+			Size:         part.Size,
+			LastModified: gofakes3.NewContentTime(part.LastModified),
+		})
+		result.NextPartNumberMarker = partNumber
+		count++
+	}
+
+	return result, nil
+}
+
+// AbortMultipartUpload discards a multipart upload and its parts
+func (b *FileBackend) AbortMultipartUpload(bucket, object string, id gofakes3.UploadID) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, err := b.readUpload(bucket, object, id); err != nil {
+		return err
+	}
+	return os.RemoveAll(b.uploadDir(id))
+}
+
+// CompleteMultipartUpload assembles the listed parts into the object
+func (b *FileBackend) CompleteMultipartUpload(bucket, object string, id gofakes3.UploadID, input *gofakes3.CompleteMultipartUploadRequest) (gofakes3.VersionID, string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	upload, err := b.readUpload(bucket, object, id)
+	if err != nil {
+		return "", "", err
+	}
+
+	staged, err := os.CreateTemp(filepath.Join(b.root, tmpDirName), "object-*")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to stage object: %w", err)
+	}
+	defer os.Remove(staged.Name())
+	defer staged.Close()
+
+	objectHash := md5.New()
+	etagHash := md5.New()
+	var size int64
+	lastPart := 0
+
+	for _, completed := range input.Parts {
+		if completed.PartNumber <= lastPart {
+			return "", "", gofakes3.ErrInvalidPartOrder
+		}
+		lastPart = completed.PartNumber
+
+		var part partMetadata
+		if err := b.readJSON(b.partPath(id, completed.PartNumber)+".json", &part); err != nil {
+			return "", "", gofakes3.ErrorMessagef(gofakes3.ErrInvalidPart, "unexpected part number %d in complete request", completed.PartNumber)
+		}
+		if strings.Trim(completed.ETag, `"`) != strings.Trim(part.ETag, `"`) {
+			return "", "", gofakes3.ErrorMessagef(gofakes3.ErrInvalidPart, "unexpected part etag for number %d in complete request", completed.PartNumber)
+		}
+
+		written, err := appendFile(io.MultiWriter(staged, objectHash), b.partPath(id, completed.PartNumber))
+		if err != nil {
+			return "", "", err
+		}
+		size += written
+
+		partHash, err := hex.DecodeString(strings.Trim(part.ETag, `"`))
+		if err != nil {
+			return "", "", gofakes3.ErrorMessagef(gofakes3.ErrInternal, "invalid etag stored for part %d: %s", completed.PartNumber, err)
+		}
+		etagHash.Write(partHash)
+	}
+
+	if err := staged.Close(); err != nil {
+		return "", "", fmt.Errorf("failed to stage object: %w", err)
+	}
+
+	meta := upload.Metadata
+	if meta == nil {
+		meta = map[string]string{}
+	}
+	if err := b.commitObject(bucket, object, meta, staged.Name(), hex.EncodeToString(objectHash.Sum(nil)), size); err != nil {
+		return "", "", err
+	}
+	if err := os.RemoveAll(b.uploadDir(id)); err != nil {
+		return "", "", fmt.Errorf("failed to remove upload %s: %w", id, err)
+	}
+
+	etag := fmt.Sprintf(`"%s-%d"`, hex.EncodeToString(etagHash.Sum(nil)), len(input.Parts))
+	return "", etag, nil
+}
+
+// commitObject moves a staged file into place and records its metadata. The caller
+// must hold the write lock.
+func (b *FileBackend) commitObject(bucketName, key string, meta map[string]string, staged, hash string, size int64) error {
+	if !b.bucketExists(bucketName) {
+		return gofakes3.BucketNotFound(bucketName)
+	}
+
+	if meta == nil {
+		meta = map[string]string{}
+	}
+
+	objectPath := b.objectPath(bucketName, key)
+	if info, err := os.Stat(objectPath); err == nil && info.IsDir() {
+		return gofakes3.ErrorMessagef(gofakes3.ErrInvalidArgument, "key %q is a prefix of other keys", key)
+	}
+	if err := os.MkdirAll(filepath.Dir(objectPath), 0750); err != nil {
+		return gofakes3.ErrorMessagef(gofakes3.ErrInvalidArgument, "key %q conflicts with an existing key", key)
+	}
+	if err := os.Rename(staged, objectPath); err != nil {
+		return fmt.Errorf("failed to store object %s: %w", key, err)
+	}
+
+	return b.writeJSON(b.objectMetadataPath(bucketName, key), &objectMetadata{
+		Metadata:     meta,
+		Size:         size,
+		Hash:         hash,
+		LastModified: time.Now().UTC(),
+	})
+}
+
+// stage copies size bytes of input, or all of it when size is negative, to a temporary
+// file and returns its path, MD5 hash and size. Input is not read past size, as the
+// decoder of chunked uploads fails when read beyond the final chunk.
+func (b *FileBackend) stage(input io.Reader, size int64) (string, []byte, int64, error) {
+	file, err := os.CreateTemp(filepath.Join(b.root, tmpDirName), "object-*")
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("failed to stage object: %w", err)
+	}
+
+	hash := md5.New()
+	var written int64
+	if size >= 0 {
+		written, err = io.CopyN(io.MultiWriter(file, hash), input, size)
+		if errors.Is(err, io.EOF) {
+			err = gofakes3.ErrIncompleteBody
+		}
+	} else {
+		written, err = io.Copy(io.MultiWriter(file, hash), input)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		if errors.Is(err, gofakes3.ErrIncompleteBody) {
+			return "", nil, 0, err
+		}
+		return "", nil, 0, fmt.Errorf("failed to stage object: %w", err)
+	}
+	return file.Name(), hash.Sum(nil), written, nil
+}
+
+// statObject returns the metadata of an existing object. Objects written without
+// metadata, for example by copying files into the data directory, get it derived
+// from the file.
+func (b *FileBackend) statObject(bucketName, key string) (*objectMetadata, error) {
+	if !b.bucketExists(bucketName) {
+		return nil, gofakes3.BucketNotFound(bucketName)
+	}
+	if validateBackendKey(key) != nil {
+		return nil, gofakes3.KeyNotFound(key)
+	}
+
+	info, err := os.Stat(b.objectPath(bucketName, key))
+	if err != nil || info.IsDir() {
+		return nil, gofakes3.KeyNotFound(key)
+	}
+	return b.readObjectMetadata(bucketName, key)
+}
+
+// readObjectMetadata reads an object's metadata, deriving it from the file when missing
+func (b *FileBackend) readObjectMetadata(bucketName, key string) (*objectMetadata, error) {
+	var meta objectMetadata
+	err := b.readJSON(b.objectMetadataPath(bucketName, key), &meta)
+	if err == nil {
+		return &meta, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	objectPath := b.objectPath(bucketName, key)
+	info, err := os.Stat(objectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat object %s: %w", key, err)
+	}
+	hash := md5.New()
+	if _, err := appendFile(hash, objectPath); err != nil {
+		return nil, err
+	}
+	return &objectMetadata{
+		Size:         info.Size(),
+		Hash:         hex.EncodeToString(hash.Sum(nil)),
+		LastModified: info.ModTime().UTC(),
+	}, nil
+}
+
+// readBucketMetadata reads a bucket's metadata, falling back to the directory's timestamp
+func (b *FileBackend) readBucketMetadata(name string) (*bucketMetadata, error) {
+	var meta bucketMetadata
+	err := b.readJSON(filepath.Join(b.root, metadataDirName, name+".json"), &meta)
+	if err == nil {
+		return &meta, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	info, err := os.Stat(b.bucketDir(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat bucket %s: %w", name, err)
+	}
+	return &bucketMetadata{CreationDate: info.ModTime().UTC()}, nil
+}
+
+// listKeys returns the sorted keys of a bucket that start with prefix
+func (b *FileBackend) listKeys(bucketName, prefix string) ([]string, error) {
+	bucketDir := b.bucketDir(bucketName)
+
+	// Only walk the directory that can contain keys with the prefix
+	start := bucketDir
+	if i := strings.LastIndex(prefix, "/"); i > 0 && validateBackendKey(prefix[:i]) == nil {
+		start = filepath.Join(bucketDir, filepath.FromSlash(prefix[:i]))
+	}
+
+	var keys []string
+	err := filepath.WalkDir(start, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(bucketDir, p)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bucket %s: %w", bucketName, err)
+	}
+
+	// Walk order differs from key order when names contain characters sorting before "/"
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// listParts returns the sorted part numbers stored for an upload
+func (b *FileBackend) listParts(id gofakes3.UploadID) ([]int, error) {
+	entries, err := os.ReadDir(b.uploadDir(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list parts of upload %s: %w", id, err)
+	}
+
+	var partNumbers []int
+	for _, entry := range entries {
+		if partNumber, err := strconv.Atoi(entry.Name()); err == nil {
+			partNumbers = append(partNumbers, partNumber)
+		}
+	}
+	sort.Ints(partNumbers)
+	return partNumbers, nil
+}
+
+// readUpload returns a pending upload, checking that it belongs to bucket and object
+func (b *FileBackend) readUpload(bucket, object string, id gofakes3.UploadID) (*uploadMetadata, error) {
+	if !isValidUploadID(id) {
+		return nil, gofakes3.ErrNoSuchUpload
+	}
+
+	var upload uploadMetadata
+	if err := b.readJSON(filepath.Join(b.uploadDir(id), uploadFileName), &upload); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, gofakes3.ErrNoSuchUpload
+		}
+		return nil, err
+	}
+	if upload.Bucket != bucket || upload.Object != object {
+		return nil, gofakes3.ErrNoSuchUpload
+	}
+	return &upload, nil
+}
+
+// removeObject deletes an object and its metadata, pruning empty prefix directories
+func (b *FileBackend) removeObject(bucketName, key string) error {
+	if validateBackendKey(key) != nil {
+		return nil
+	}
+
+	objectPath := b.objectPath(bucketName, key)
+	if info, err := os.Stat(objectPath); err != nil || info.IsDir() {
+		return nil
+	}
+	if err := os.Remove(objectPath); err != nil {
+		return fmt.Errorf("failed to delete object %s: %w", key, err)
+	}
+
+	metadataPath := b.objectMetadataPath(bucketName, key)
+	if err := os.Remove(metadataPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete metadata of object %s: %w", key, err)
+	}
+
+	pruneEmptyDirs(filepath.Dir(objectPath), b.bucketDir(bucketName))
+	pruneEmptyDirs(filepath.Dir(metadataPath), filepath.Join(b.root, metadataDirName, bucketName))
+	return nil
+}
+
+// removeBucket deletes a bucket with its objects and metadata
+func (b *FileBackend) removeBucket(name string) error {
+	paths := []string{
+		b.bucketDir(name),
+		filepath.Join(b.root, metadataDirName, name),
+		filepath.Join(b.root, metadataDirName, name+".json"),
+	}
+	for _, p := range paths {
+		if err := os.RemoveAll(p); err != nil {
+			return fmt.Errorf("failed to delete bucket %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (b *FileBackend) bucketExists(name string) bool {
+	if validateBackendBucket(name) != nil {
+		return false
+	}
+	info, err := os.Stat(b.bucketDir(name))
+	return err == nil && info.IsDir()
+}
+
+func (b *FileBackend) bucketDir(name string) string {
+	return filepath.Join(b.root, bucketsDirName, name)
+}
+
+func (b *FileBackend) objectPath(bucketName, key string) string {
+	return filepath.Join(b.bucketDir(bucketName), filepath.FromSlash(key))
+}
+
+func (b *FileBackend) objectMetadataPath(bucketName, key string) string {
+	return filepath.Join(b.root, metadataDirName, bucketName, filepath.FromSlash(key))
+}
+
+func (b *FileBackend) uploadDir(id gofakes3.UploadID) string {
+	return filepath.Join(b.root, uploadsDirName, string(id))
+}
+
+func (b *FileBackend) partPath(id gofakes3.UploadID, partNumber int) string {
+	return filepath.Join(b.uploadDir(id), fmt.Sprintf("%05d", partNumber))
+}
+
+// writeJSON atomically writes v as JSON to p, creating parent directories
+func (b *FileBackend) writeJSON(p string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0750); err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+
+	file, err := os.CreateTemp(filepath.Join(b.root, tmpDirName), "metadata-*")
+	if err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), p)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+	return nil
+}
+
+// readJSON decodes the JSON file at p into v
+func (b *FileBackend) readJSON(p string, v interface{}) error {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode metadata %s: %w", p, err)
+	}
+	return nil
+}
+
+// appendFile copies the file at p into w
+func appendFile(w io.Writer, p string) (int64, error) {
+	file, err := os.Open(p)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open %s: %w", p, err)
+	}
+	defer file.Close()
+
+	n, err := io.Copy(w, file)
+	if err != nil {
+		return n, fmt.Errorf("failed to read %s: %w", p, err)
+	}
+	return n, nil
+}
+
+// pruneEmptyDirs removes empty directories from dir up to, but excluding, stop
+func pruneEmptyDirs(dir, stop string) {
+	for dir != stop && strings.HasPrefix(dir, stop) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// validateBackendBucket rejects bucket names that cannot be stored as a directory
+func validateBackendBucket(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return gofakes3.ResourceError(gofakes3.ErrInvalidBucketName, name)
+	}
+	return nil
+}
+
+// validateBackendKey rejects keys that cannot be stored as a file below the bucket
+// directory, such as keys with empty, "." or ".." segments or a trailing slash
+func validateBackendKey(key string) error {
+	if key == "" || strings.Contains(key, `\`) || path.Clean("/"+key) != "/"+key {
+		return gofakes3.ErrorMessagef(gofakes3.ErrInvalidArgument, "key %q is not supported by the filesystem backend", key)
+	}
+	return nil
+}
+
+// newUploadID returns a random multipart upload ID
+func newUploadID() (gofakes3.UploadID, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate upload ID: %w", err)
+	}
+	return gofakes3.UploadID(hex.EncodeToString(buf)), nil
+}
+
+// isValidUploadID reports whether id has the form generated by newUploadID
+func isValidUploadID(id gofakes3.UploadID) bool {
+	decoded, err := hex.DecodeString(string(id))
+	return err == nil && len(decoded) == 16
+}
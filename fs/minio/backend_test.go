@@ -0,0 +1,188 @@
+package minio
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/johannesboyne/gofakes3"
+	miniogo "github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func putTestObject(t *testing.T, backend *FileBackend, bucket, key, data string) {
+	_, err := backend.PutObject(bucket, key, map[string]string{}, strings.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+}
+
+func readTestObject(t *testing.T, backend *FileBackend, bucket, key string, rangeRequest *gofakes3.ObjectRangeRequest) string {
+	obj, err := backend.GetObject(bucket, key, rangeRequest)
+	require.NoError(t, err)
+	defer obj.Contents.Close()
+
+	data, err := io.ReadAll(obj.Contents)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestFileBackendObjects(t *testing.T) {
+	root := t.TempDir()
+	backend, err := NewFileBackend(root)
+	require.NoError(t, err)
+
+	require.NoError(t, backend.CreateBucket(testBucket))
+	assert.True(t, gofakes3.HasErrorCode(backend.CreateBucket(testBucket), gofakes3.ErrBucketAlreadyExists))
+
+	_, err = backend.PutObject(testBucket, "warehouse/t/data/a.parquet", map[string]string{"X-Amz-Meta-Owner": "icebox"}, strings.NewReader("hello world"), 11)
+	require.NoError(t, err)
+	putTestObject(t, backend, testBucket, "warehouse/t/metadata/v1.json", "{}")
+	putTestObject(t, backend, testBucket, "warehouse/t-other/file", "x")
+
+	assert.Equal(t, "hello world", readTestObject(t, backend, testBucket, "warehouse/t/data/a.parquet", nil))
+	assert.Equal(t, "world", readTestObject(t, backend, testBucket, "warehouse/t/data/a.parquet", &gofakes3.ObjectRangeRequest{Start: 6, End: gofakes3.RangeNoEnd}))
+
+	head, err := backend.HeadObject(testBucket, "warehouse/t/data/a.parquet")
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), head.Size)
+	assert.Equal(t, "icebox", head.Metadata["X-Amz-Meta-Owner"])
+
+	// Overwriting an object replaces its metadata rather than merging it
+	putTestObject(t, backend, testBucket, "warehouse/t/data/a.parquet", "hello world")
+	head, err = backend.HeadObject(testBucket, "warehouse/t/data/a.parquet")
+	require.NoError(t, err)
+	assert.NotContains(t, head.Metadata, "X-Amz-Meta-Owner")
+
+	_, err = backend.HeadObject(testBucket, "warehouse/t/data")
+	assert.True(t, gofakes3.HasErrorCode(err, gofakes3.ErrNoSuchKey))
+
+	// Listing is in key order, with delimiters grouping prefixes
+	list, err := backend.ListBucket(testBucket, &gofakes3.Prefix{Prefix: "warehouse/", HasPrefix: true}, gofakes3.ListBucketPage{})
+	require.NoError(t, err)
+	require.Len(t, list.Contents, 3)
+	assert.Equal(t, "warehouse/t-other/file", list.Contents[0].Key)
+	assert.Equal(t, "warehouse/t/data/a.parquet", list.Contents[1].Key)
+
+	list, err = backend.ListBucket(testBucket, &gofakes3.Prefix{Prefix: "warehouse/", HasPrefix: true, Delimiter: "/", HasDelimiter: true}, gofakes3.ListBucketPage{})
+	require.NoError(t, err)
+	assert.Empty(t, list.Contents)
+	assert.Equal(t, []gofakes3.CommonPrefix{{Prefix: "warehouse/t-other/"}, {Prefix: "warehouse/t/"}}, list.CommonPrefixes)
+
+	list, err = backend.ListBucket(testBucket, nil, gofakes3.ListBucketPage{MaxKeys: 2})
+	require.NoError(t, err)
+	assert.Len(t, list.Contents, 2)
+	assert.True(t, list.IsTruncated)
+
+	// Keys that cannot be stored as files are rejected
+	_, err = backend.PutObject(testBucket, "../escape", nil, strings.NewReader("x"), 1)
+	assert.Error(t, err)
+	_, err = backend.PutObject(testBucket, "warehouse/t/data", nil, strings.NewReader("x"), 1)
+	assert.Error(t, err)
+
+	// Deleting removes empty prefix directories, and the bucket can then be deleted
+	assert.True(t, gofakes3.HasErrorCode(backend.DeleteBucket(testBucket), gofakes3.ErrBucketNotEmpty))
+	result, err := backend.DeleteMulti(testBucket, "warehouse/t/data/a.parquet", "warehouse/t/metadata/v1.json", "warehouse/t-other/file", "missing")
+	require.NoError(t, err)
+	assert.Len(t, result.Deleted, 4)
+	assert.NoDirExists(t, backend.objectPath(testBucket, "warehouse"))
+	require.NoError(t, backend.DeleteBucket(testBucket))
+
+	buckets, err := backend.ListBuckets()
+	require.NoError(t, err)
+	assert.Empty(t, buckets)
+}
+
+func TestFileBackendMultipartUpload(t *testing.T) {
+	root := t.TempDir()
+	backend, err := NewFileBackend(root)
+	require.NoError(t, err)
+	require.NoError(t, backend.CreateBucket(testBucket))
+
+	id, err := backend.CreateMultipartUpload(testBucket, "large/object", map[string]string{"Content-Type": "application/octet-stream"})
+	require.NoError(t, err)
+
+	etag1, err := backend.UploadPart(testBucket, "large/object", id, 1, 5, strings.NewReader("hello"))
+	require.NoError(t, err)
+	_, err = backend.UploadPart(testBucket, "large/object", id, 2, 10, strings.NewReader("short"))
+	assert.True(t, gofakes3.HasErrorCode(err, gofakes3.ErrIncompleteBody))
+
+	// Pending uploads survive a restart of the backend
+	backend, err = NewFileBackend(root)
+	require.NoError(t, err)
+
+	etag2, err := backend.UploadPart(testBucket, "large/object", id, 2, 6, strings.NewReader(" world"))
+	require.NoError(t, err)
+
+	uploads, err := backend.ListMultipartUploads(testBucket, nil, gofakes3.Prefix{}, 1000)
+	require.NoError(t, err)
+	require.Len(t, uploads.Uploads, 1)
+	assert.Equal(t, id, uploads.Uploads[0].UploadID)
+
+	parts, err := backend.ListParts(testBucket, "large/object", id, 0, 1000)
+	require.NoError(t, err)
+	require.Len(t, parts.Parts, 2)
+	assert.Equal(t, etag1, parts.Parts[0].ETag)
+	assert.Equal(t, int64(6), parts.Parts[1].Size)
+
+	_, _, err = backend.CompleteMultipartUpload(testBucket, "large/object", id, &gofakes3.CompleteMultipartUploadRequest{
+		Parts: []gofakes3.CompletedPart{{PartNumber: 2, ETag: etag2}, {PartNumber: 1, ETag: etag1}},
+	})
+	assert.True(t, gofakes3.HasErrorCode(err, gofakes3.ErrInvalidPartOrder))
+
+	_, etag, err := backend.CompleteMultipartUpload(testBucket, "large/object", id, &gofakes3.CompleteMultipartUploadRequest{
+		Parts: []gofakes3.CompletedPart{{PartNumber: 1, ETag: etag1}, {PartNumber: 2, ETag: etag2}},
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(etag, `-2"`))
+	assert.Equal(t, "hello world", readTestObject(t, backend, testBucket, "large/object", nil))
+
+	_, err = backend.ListParts(testBucket, "large/object", id, 0, 1000)
+	assert.True(t, gofakes3.HasErrorCode(err, gofakes3.ErrNoSuchUpload))
+
+	// Aborted uploads are discarded
+	id, err = backend.CreateMultipartUpload(testBucket, "aborted", nil)
+	require.NoError(t, err)
+	require.NoError(t, backend.AbortMultipartUpload(testBucket, "aborted", id))
+	assert.True(t, gofakes3.HasErrorCode(backend.AbortMultipartUpload(testBucket, "aborted", id), gofakes3.ErrNoSuchUpload))
+}
+
+func TestEmbeddedMinIOPersistence(t *testing.T) {
+	config := createTestConfig()
+	config.DataDir = t.TempDir()
+	config.Port = testPort + 200
+
+	server, cleanup := setupPersistentTestServer(t, config)
+	ctx := context.Background()
+
+	data := generateTestData(testLargeFileSize)
+	_, err := server.GetClient().PutObject(ctx, testBucket, "persisted/object", bytes.NewReader(data), int64(len(data)), miniogo.PutObjectOptions{})
+	require.NoError(t, err)
+	cleanup()
+
+	// A new server on the same data directory sees the bucket and object
+	server, cleanup = setupPersistentTestServer(t, config)
+	defer cleanup()
+
+	obj, err := server.GetClient().GetObject(ctx, testBucket, "persisted/object", miniogo.GetObjectOptions{})
+	require.NoError(t, err)
+	defer obj.Close()
+
+	read, err := io.ReadAll(obj)
+	require.NoError(t, err)
+	assert.Equal(t, data, read)
+}
+
+// setupPersistentTestServer starts a server that keeps its data directory when stopped
+func setupPersistentTestServer(t *testing.T, config *EmbeddedMinIOConfig) (*EmbeddedMinIO, func()) {
+	t.Setenv("MINIO_TEST_MODE", "true")
+
+	server, err := NewEmbeddedMinIO(config)
+	require.NoError(t, err)
+	require.NoError(t, server.Start(context.Background()))
+
+	return server, func() {
+		assert.NoError(t, server.Stop(context.Background()))
+	}
+}
@@ -4,13 +4,14 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io"
 	"log"
 	"net"
 	"net/http"
-	"net/http/httptest"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -19,7 +20,6 @@ import (
 
 	icebergio "github.com/apache/iceberg-go/io"
 	"github.com/johannesboyne/gofakes3"
-	"github.com/johannesboyne/gofakes3/backend/s3mem"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 )
@@ -195,12 +195,11 @@ type ServerMetrics struct {
 type EmbeddedMinIO struct {
 	config       *EmbeddedMinIOConfig
 	server       *http.Server
-	fakeS3Server *httptest.Server
-	s3Backend    *s3mem.Backend
+	s3Backend    *FileBackend
 	client       *minio.Client
 	running      int32  // Use atomic for thread-safe access
 	endpoint     string // Original configured endpoint
-	actualURL    string // Actual server URL (for fake server)
+	actualURL    string // Bound host:port of the listener
 	startTime    time.Time
 	metrics      *ServerMetrics
 	logger       *log.Logger
@@ -336,21 +335,6 @@ func (m *EmbeddedMinIO) Start(ctx context.Context) error {
 		}
 	}()
 
-	// Check if port is available (only for real servers, not fake ones)
-	// For testing, we use httptest.NewServer which handles port allocation
-	if os.Getenv("MINIO_TEST_MODE") == "" {
-		if !isPortAvailable(m.config.Port) {
-			atomic.StoreInt32(&m.running, 0)
-			return &MinIOError{
-				Op:  "port_check",
-				Err: fmt.Errorf("port %d is already in use", m.config.Port),
-				Context: map[string]interface{}{
-					"port": m.config.Port,
-				},
-			}
-		}
-	}
-
 	m.logger.Printf("🗄️  Starting embedded MinIO server...")
 	m.logger.Printf("   Endpoint: %s://%s", m.getScheme(), m.endpoint)
 	m.logger.Printf("   Data Directory: %s", m.config.DataDir)
@@ -362,19 +346,12 @@ func (m *EmbeddedMinIO) Start(ctx context.Context) error {
 	// Set MinIO environment variables
 	m.setEnvironmentVariables()
 
-	// Start MinIO server in a goroutine
-	serverCtx, serverCancel := context.WithCancel(ctx)
-	defer serverCancel()
-
+	// Bind the configured endpoint and serve requests in the background
 	serverErrCh := make(chan error, 1)
-	go func() {
-		if err := m.startMinIOServer(serverCtx); err != nil && err != http.ErrServerClosed {
-			serverErrCh <- &MinIOError{
-				Op:  "server_start",
-				Err: err,
-			}
-		}
-	}()
+	if err := m.startMinIOServer(serverErrCh); err != nil {
+		atomic.StoreInt32(&m.running, 0)
+		return err
+	}
 
 	// Wait for server to be ready with timeout
 	readyCtx, readyCancel := context.WithTimeout(ctx, m.config.StartupTimeout)
@@ -382,6 +359,7 @@ func (m *EmbeddedMinIO) Start(ctx context.Context) error {
 
 	if err := m.waitForReady(readyCtx); err != nil {
 		atomic.StoreInt32(&m.running, 0)
+		m.closeServer()
 		return &MinIOError{
 			Op:  "wait_ready",
 			Err: err,
@@ -392,6 +370,7 @@ func (m *EmbeddedMinIO) Start(ctx context.Context) error {
 	client, err := m.createClient()
 	if err != nil {
 		atomic.StoreInt32(&m.running, 0)
+		m.closeServer()
 		return &MinIOError{
 			Op:  "create_client",
 			Err: err,
@@ -407,6 +386,7 @@ func (m *EmbeddedMinIO) Start(ctx context.Context) error {
 	if m.config.DefaultBucket != "" {
 		if err := m.ensureBucket(ctx, m.config.DefaultBucket); err != nil {
 			atomic.StoreInt32(&m.running, 0)
+			m.closeServer()
 			return &MinIOError{
 				Op:  "create_default_bucket",
 				Err: err,
@@ -431,6 +411,7 @@ func (m *EmbeddedMinIO) Start(ctx context.Context) error {
 	select {
 	case err := <-serverErrCh:
 		atomic.StoreInt32(&m.running, 0)
+		m.closeServer()
 		return err
 	default:
 		// Server started successfully
@@ -463,7 +444,7 @@ func (m *EmbeddedMinIO) createClient() (*minio.Client, error) {
 		transport.TLSClientConfig = tlsConfig
 	}
 
-	// Use the actual server URL if available (for fake server), otherwise use configured endpoint
+	// Use the bound address if available, otherwise use configured endpoint
 	endpoint := m.endpoint
 	if m.actualURL != "" {
 		endpoint = m.actualURL
@@ -564,6 +545,19 @@ func (m *EmbeddedMinIO) createTLSConfig() (*tls.Config, error) {
 		tlsConfig.Certificates = []tls.Certificate{cert}
 	}
 
+	// Trust the CA that signed the server certificate
+	if m.config.TLS.CAFile != "" {
+		caPEM, err := os.ReadFile(m.config.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", m.config.TLS.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
 	return tlsConfig, nil
 }
 
@@ -663,10 +657,7 @@ func (m *EmbeddedMinIO) Stop(ctx context.Context) error {
 	m.mu.Lock()
 	m.client = nil
 	m.server = nil
-	if m.fakeS3Server != nil {
-		m.fakeS3Server.Close()
-		m.fakeS3Server = nil
-	}
+	m.actualURL = ""
 	m.s3Backend = nil
 	m.mu.Unlock()
 
@@ -684,17 +675,15 @@ func (m *EmbeddedMinIO) GetEndpoint() string {
 	return m.endpoint
 }
 
-// GetActualURL returns the actual server URL (for testing with fake server)
+// GetActualURL returns the URL of the address the server is bound to
 func (m *EmbeddedMinIO) GetActualURL() string {
-	if m.fakeS3Server != nil {
-		return m.fakeS3Server.URL
-	}
-	return fmt.Sprintf("http://%s", m.endpoint)
-}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 
-// GetFakeServer returns the fake server for testing (internal use)
-func (m *EmbeddedMinIO) GetFakeServer() *httptest.Server {
-	return m.fakeS3Server
+	if m.actualURL != "" {
+		return fmt.Sprintf("%s://%s", m.getScheme(), m.actualURL)
+	}
+	return fmt.Sprintf("%s://%s", m.getScheme(), m.endpoint)
 }
 
 // GetClient returns the MinIO client instance (thread-safe)
@@ -914,7 +903,7 @@ func (fs *MinIOFileSystem) Open(location string) (icebergio.File, error) {
 }
 
 // Create creates a new file for writing to MinIO with enhanced buffering
-func (fs *MinIOFileSystem) Create(location string) (icebergio.File, error) {
+func (fs *MinIOFileSystem) Create(location string) (io.WriteCloser, error) {
 	fs.mu.RLock()
 	defer fs.mu.RUnlock()
 
@@ -995,6 +984,108 @@ func (fs *MinIOFileSystem) Remove(location string) error {
 	}
 }
 
+// Stat returns information about an object, or about a prefix with objects below it,
+// which is reported as a directory
+func (fs *MinIOFileSystem) Stat(location string) (os.FileInfo, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	objectName := fs.getObjectName(location)
+	ctx, cancel := context.WithTimeout(context.Background(), fs.config.OperationTimeout)
+	defer cancel()
+
+	info, err := fs.client.StatObject(ctx, fs.bucket, objectName, minio.StatObjectOptions{})
+	if err == nil {
+		return &minioFileInfo{objInfo: info}, nil
+	}
+	if minio.ToErrorResponse(err).Code != "NoSuchKey" {
+		fs.incrementErrorMetric("read")
+		return nil, &MinIOError{Op: "stat", Err: err, Context: map[string]interface{}{"location": location, "object_name": objectName}}
+	}
+
+	for obj := range fs.client.ListObjects(ctx, fs.bucket, minio.ListObjectsOptions{Prefix: objectName + "/", MaxKeys: 1}) {
+		if obj.Err != nil {
+			fs.incrementErrorMetric("read")
+			return nil, &MinIOError{Op: "stat", Err: obj.Err, Context: map[string]interface{}{"location": location, "object_name": objectName}}
+		}
+		return &minioDirInfo{name: path.Base(objectName)}, nil
+	}
+	return nil, &os.PathError{Op: "stat", Path: location, Err: os.ErrNotExist}
+}
+
+// List returns the objects and prefixes directly below a location, sorted by name
+func (fs *MinIOFileSystem) List(location string) ([]os.FileInfo, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	prefix := fs.listPrefix(location)
+	ctx, cancel := context.WithTimeout(context.Background(), fs.config.OperationTimeout)
+	defer cancel()
+
+	start := time.Now()
+	defer func() { fs.updateMetrics("list", time.Since(start), 0, false) }()
+
+	var infos []os.FileInfo
+	for obj := range fs.client.ListObjects(ctx, fs.bucket, minio.ListObjectsOptions{Prefix: prefix}) {
+		if obj.Err != nil {
+			fs.incrementErrorMetric("list")
+			return nil, &MinIOError{Op: "list", Err: obj.Err, Context: map[string]interface{}{"location": location, "prefix": prefix}}
+		}
+		if strings.HasSuffix(obj.Key, "/") {
+			infos = append(infos, &minioDirInfo{name: path.Base(obj.Key)})
+			continue
+		}
+		infos = append(infos, &minioFileInfo{objInfo: obj})
+	}
+	return infos, nil
+}
+
+// Walk calls fn for every object below a location in key order. Locations passed to
+// fn are relative to the file system's prefix, like the locations it accepts.
+func (fs *MinIOFileSystem) Walk(location string, fn func(location string, info os.FileInfo) error) error {
+	return fs.WalkFrom(location, "", fn)
+}
+
+// WalkFrom is like Walk but starts listing after startAfter, so that a walk can be
+// resumed where an earlier one stopped without listing the objects before it
+func (fs *MinIOFileSystem) WalkFrom(location, startAfter string, fn func(location string, info os.FileInfo) error) error {
+	start := time.Now()
+
+	fs.mu.RLock()
+	opts := minio.ListObjectsOptions{Prefix: fs.listPrefix(location), Recursive: true}
+	if startAfter != "" {
+		opts.StartAfter = fs.getObjectName(startAfter)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	objects := fs.client.ListObjects(ctx, fs.bucket, opts)
+	fs.mu.RUnlock()
+	defer cancel()
+	defer func() { fs.updateMetrics("list", time.Since(start), 0, false) }()
+
+	for obj := range objects {
+		if obj.Err != nil {
+			fs.incrementErrorMetric("list")
+			return &MinIOError{Op: "walk", Err: obj.Err, Context: map[string]interface{}{"location": location, "prefix": opts.Prefix}}
+		}
+		relative := strings.TrimPrefix(strings.TrimPrefix(obj.Key, fs.prefix), "/")
+		if err := fn(relative, &minioFileInfo{objInfo: obj}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listPrefix returns the object prefix of the entries below a location
+func (fs *MinIOFileSystem) listPrefix(location string) string {
+	if location == "" || location == "." || location == "/" {
+		if fs.prefix == "" {
+			return ""
+		}
+		return fs.prefix + "/"
+	}
+	return strings.TrimSuffix(fs.getObjectName(location), "/") + "/"
+}
+
 // updateMetrics updates filesystem operation metrics
 func (fs *MinIOFileSystem) updateMetrics(operation string, duration time.Duration, bytes int64, isError bool) {
 	if !fs.config.EnableMetrics {
@@ -1025,6 +1116,8 @@ func (fs *MinIOFileSystem) updateMetrics(operation string, duration time.Duratio
 		if isError {
 			fs.metrics.DeleteErrors++
 		}
+	case "list":
+		fs.metrics.ListOperations++
 	}
 }
 
@@ -1044,6 +1137,9 @@ func (fs *MinIOFileSystem) incrementErrorMetric(operation string) {
 		fs.metrics.WriteErrors++
 	case "delete":
 		fs.metrics.DeleteErrors++
+	case "list":
+		// Listing reads from the server
+		fs.metrics.ReadErrors++
 	}
 }
 
@@ -1403,19 +1499,23 @@ func validateObjectName(objectName string) error {
 	return nil
 }
 
-// startMinIOServer starts the actual MinIO server process with comprehensive HTTP configuration
-func (m *EmbeddedMinIO) startMinIOServer(ctx context.Context) error {
-	// Create in-memory S3 backend
-	m.s3Backend = s3mem.New()
+// startMinIOServer binds the configured endpoint and serves the S3 API in the background.
+// Errors from serving after a successful bind are sent to errCh.
+func (m *EmbeddedMinIO) startMinIOServer(errCh chan<- error) error {
+	// Store buckets and objects in the data directory so they survive restarts
+	backend, err := NewFileBackend(m.config.DataDir)
+	if err != nil {
+		return &MinIOError{Op: "server_start", Err: fmt.Errorf("failed to create storage backend: %w", err)}
+	}
 
 	// Create fake S3 server with gofakes3
-	faker := gofakes3.New(m.s3Backend)
+	faker := gofakes3.New(backend)
 
 	// Create the main mux that will handle both S3 API and health endpoints
 	mainMux := http.NewServeMux()
 
 	// Mount the S3 API at the root
-	mainMux.Handle("/", faker.Server())
+	mainMux.Handle("/", decodeChunkedUploads(faker.Server()))
 
 	// Add health check endpoints
 	mainMux.HandleFunc("/minio/health/live", m.handleHealthLive)
@@ -1439,28 +1539,85 @@ func (m *EmbeddedMinIO) startMinIOServer(ctx context.Context) error {
 		handler = m.metricsMiddleware(handler)
 	}
 
-	// Create httptest server instead of regular server for testing
-	m.fakeS3Server = httptest.NewServer(handler)
+	server := &http.Server{
+		Addr:              m.endpoint,
+		Handler:           handler,
+		ReadTimeout:       m.config.ReadTimeout,
+		ReadHeaderTimeout: m.config.ReadTimeout,
+		WriteTimeout:      m.config.WriteTimeout,
+		IdleTimeout:       m.config.IdleTimeout,
+		ErrorLog:          m.logger,
+	}
 
-	// Store the actual server URL for client connections
-	m.actualURL = strings.TrimPrefix(m.fakeS3Server.URL, "http://")
+	if m.config.Secure {
+		if m.config.TLS == nil || m.config.TLS.CertFile == "" || m.config.TLS.KeyFile == "" {
+			return &MinIOError{Op: "server_start", Err: fmt.Errorf("secure mode requires tls.cert_file and tls.key_file")}
+		}
+		tlsConfig, err := m.createTLSConfig()
+		if err != nil {
+			return &MinIOError{Op: "server_start", Err: fmt.Errorf("failed to create TLS config: %w", err)}
+		}
+		server.TLSConfig = tlsConfig
+	}
 
-	// Create a dummy server for compatibility
-	m.server = &http.Server{
-		Addr:         m.endpoint,
-		Handler:      handler,
-		ReadTimeout:  m.config.ReadTimeout,
-		WriteTimeout: m.config.WriteTimeout,
-		IdleTimeout:  m.config.IdleTimeout,
-		ErrorLog:     m.logger,
+	listener, err := net.Listen("tcp", m.endpoint)
+	if err != nil {
+		return &MinIOError{
+			Op:  "port_check",
+			Err: fmt.Errorf("failed to listen on %s: %w", m.endpoint, err),
+			Context: map[string]interface{}{
+				"port": m.config.Port,
+			},
+		}
+	}
+	if server.TLSConfig != nil {
+		listener = tls.NewListener(listener, server.TLSConfig)
 	}
 
-	// The fake server will be closed in the Stop() method
-	// No need to close it when the start context is done
+	m.mu.Lock()
+	m.server = server
+	m.s3Backend = backend
+	m.actualURL = boundAddress(m.config.Address, listener.Addr())
+	m.mu.Unlock()
+
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			errCh <- &MinIOError{
+				Op:  "server_start",
+				Err: err,
+			}
+		}
+	}()
 
 	return nil
 }
 
+// closeServer stops serving after a failed start
+func (m *EmbeddedMinIO) closeServer() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.server != nil {
+		m.server.Close()
+		m.server = nil
+	}
+	m.actualURL = ""
+	m.s3Backend = nil
+}
+
+// boundAddress returns the host:port clients use to reach a listener. Wildcard
+// addresses are reached through localhost.
+func boundAddress(address string, addr net.Addr) string {
+	_, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	if ip := net.ParseIP(address); address == "" || (ip != nil && ip.IsUnspecified()) {
+		address = "localhost"
+	}
+	return net.JoinHostPort(address, port)
+}
+
 // HTTP handlers for health checks and metrics
 func (m *EmbeddedMinIO) handleHealthLive(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -1663,20 +1820,14 @@ func (m *EmbeddedMinIO) waitForReady(ctx context.Context) error {
 
 // isServerReady checks if the server is ready by attempting a connection
 func (m *EmbeddedMinIO) isServerReady() bool {
-	// For fake S3 server, check if the server is not nil and running
-	if m.fakeS3Server != nil {
-		// Try a simple HTTP request to the health endpoint
-		client := &http.Client{Timeout: 1 * time.Second}
-		resp, err := client.Get(m.fakeS3Server.URL + "/minio/health/live")
-		if err != nil {
-			return false
-		}
-		defer resp.Body.Close()
-		return resp.StatusCode == http.StatusOK
+	m.mu.RLock()
+	address := m.actualURL
+	m.mu.RUnlock()
+	if address == "" {
+		address = m.endpoint
 	}
 
-	// Fallback to TCP connection for real servers
-	conn, err := net.DialTimeout("tcp", m.endpoint, 1*time.Second)
+	conn, err := net.DialTimeout("tcp", address, 1*time.Second)
 	if err != nil {
 		return false
 	}
@@ -1684,17 +1835,6 @@ func (m *EmbeddedMinIO) isServerReady() bool {
 	return true
 }
 
-// isPortAvailable checks if a port is available for binding
-func isPortAvailable(port int) bool {
-	address := fmt.Sprintf("localhost:%d", port)
-	listener, err := net.Listen("tcp", address)
-	if err != nil {
-		return false
-	}
-	defer listener.Close()
-	return true
-}
-
 // getObjectName converts a location to MinIO object name with proper path handling
 func (fs *MinIOFileSystem) getObjectName(location string) string {
 	// Clean the location path and convert backslashes to forward slashes
@@ -2056,6 +2196,18 @@ func (fi *minioFileInfo) ModTime() time.Time { return fi.objInfo.LastModified }
 func (fi *minioFileInfo) IsDir() bool        { return false }
 func (fi *minioFileInfo) Sys() interface{}   { return fi.objInfo }
 
+// minioDirInfo describes a prefix that has objects below it
+type minioDirInfo struct {
+	name string
+}
+
+func (fi *minioDirInfo) Name() string       { return fi.name }
+func (fi *minioDirInfo) Size() int64        { return 0 }
+func (fi *minioDirInfo) Mode() os.FileMode  { return os.ModeDir | 0755 }
+func (fi *minioDirInfo) ModTime() time.Time { return time.Time{} }
+func (fi *minioDirInfo) IsDir() bool        { return true }
+func (fi *minioDirInfo) Sys() interface{}   { return nil }
+
 type minioWriteFileInfo struct {
 	name string
 	size int64
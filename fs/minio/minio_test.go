@@ -2,18 +2,25 @@ package minio
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	cryptorand "crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
 	"io"
+	"math/big"
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
-	icebergio "github.com/apache/iceberg-go/io"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -44,7 +51,7 @@ func generateTestData(size int) []byte {
 }
 
 // Helper function to write data to a MinIO file
-func writeToMinIOFile(t *testing.T, file icebergio.File, data []byte) (int, error) {
+func writeToMinIOFile(t *testing.T, file io.WriteCloser, data []byte) (int, error) {
 	minioWriteFile, ok := file.(*minioWriteFile)
 	require.True(t, ok, "file should be of type *minioWriteFile")
 	return minioWriteFile.Write(data)
@@ -420,14 +427,6 @@ func TestHealthChecks(t *testing.T) {
 	// Verify server is running
 	require.True(t, server.IsRunning(), "Server should be running before health checks")
 
-	// Debug: Check if fake server is accessible
-	fakeServer := server.GetFakeServer()
-	if fakeServer != nil {
-		t.Logf("Fake server URL: %s", fakeServer.URL)
-	} else {
-		t.Fatal("Fake server is nil")
-	}
-
 	// Test health endpoints - use the actual server URL
 	baseURL := server.GetActualURL()
 	t.Logf("Testing health endpoints at: %s", baseURL)
@@ -736,8 +735,10 @@ func TestWriteFileOperations(t *testing.T) {
 	fs, err := NewMinIOFileSystem(server, testBucket, testPrefix)
 	require.NoError(t, err)
 
-	writeFile, err := fs.Create("test-write.txt")
+	created, err := fs.Create("test-write.txt")
 	require.NoError(t, err)
+	writeFile, ok := created.(*minioWriteFile)
+	require.True(t, ok, "created file should be of type *minioWriteFile")
 
 	// Test unsupported operations on write file
 	tests := []struct {
@@ -1665,3 +1666,152 @@ func BenchmarkFileOperations(b *testing.B) {
 		}
 	})
 }
+
+func TestEmbeddedMinIOListensOnConfiguredPort(t *testing.T) {
+	config := createTestConfig()
+	config.DataDir = t.TempDir()
+	config.Port = testPort + 300
+
+	server, cleanup := setupPersistentTestServer(t, config)
+	defer cleanup()
+
+	expected := fmt.Sprintf("http://localhost:%d", config.Port)
+	assert.Equal(t, expected, server.GetActualURL())
+	assert.True(t, IsHealthy(context.Background(), expected))
+
+	// A second server cannot bind the same endpoint
+	other, err := NewEmbeddedMinIO(config)
+	require.NoError(t, err)
+	err = other.Start(context.Background())
+	require.Error(t, err)
+	assert.False(t, other.IsRunning())
+
+	cleanup()
+	assert.False(t, IsHealthy(context.Background(), expected))
+}
+
+func TestEmbeddedMinIOTLS(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t)
+
+	config := createTestConfig()
+	config.DataDir = t.TempDir()
+	config.Port = testPort + 301
+	config.Secure = true
+	config.TLS = &TLSConfig{CertFile: certFile, KeyFile: keyFile, CAFile: certFile}
+
+	server, cleanup := setupPersistentTestServer(t, config)
+	defer cleanup()
+
+	url := server.GetActualURL()
+	assert.Equal(t, fmt.Sprintf("https://localhost:%d", config.Port), url)
+
+	// The client created at startup trusts the configured CA
+	_, err := server.GetClient().ListBuckets(context.Background())
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	certPEM, err := os.ReadFile(certFile)
+	require.NoError(t, err)
+	require.True(t, pool.AppendCertsFromPEM(certPEM))
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+
+	resp, err := client.Get(url + "/minio/health/live")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Plain HTTP is not served on a secure endpoint
+	assert.False(t, IsHealthy(context.Background(), fmt.Sprintf("http://localhost:%d", config.Port)))
+
+	// Secure mode without a certificate fails to start
+	config.TLS = nil
+	config.Port = testPort + 302
+	insecure, err := NewEmbeddedMinIO(config)
+	require.NoError(t, err)
+	assert.Error(t, insecure.Start(context.Background()))
+}
+
+// writeTestCertificate writes a self-signed certificate for localhost and returns
+// the certificate and key file paths
+func writeTestCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), cryptorand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(cryptorand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certFile, keyFile
+}
+
+func TestMinIOFileSystemListing(t *testing.T) {
+	config := createTestConfig()
+	config.DataDir = t.TempDir()
+	config.Port = testPort + 400
+	server, cleanup := setupPersistentTestServer(t, config)
+	defer cleanup()
+
+	fs, err := NewMinIOFileSystem(server, testBucket, testPrefix)
+	require.NoError(t, err)
+
+	for _, location := range []string{"t/data/a.parquet", "t/metadata/v1.json", "t2/data/b.parquet"} {
+		file, err := fs.Create(location)
+		require.NoError(t, err)
+		_, err = writeToMinIOFile(t, file, []byte(location))
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+	}
+
+	info, err := fs.Stat("t/data/a.parquet")
+	require.NoError(t, err)
+	assert.False(t, info.IsDir())
+	assert.Equal(t, int64(len("t/data/a.parquet")), info.Size())
+
+	info, err = fs.Stat("t")
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+
+	_, err = fs.Stat("missing")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	infos, err := fs.List("t")
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	assert.Equal(t, "data", infos[0].Name())
+	assert.True(t, infos[0].IsDir())
+
+	var walked []string
+	require.NoError(t, fs.Walk("", func(location string, info os.FileInfo) error {
+		walked = append(walked, location)
+		return nil
+	}))
+	assert.Equal(t, []string{"t/data/a.parquet", "t/metadata/v1.json", "t2/data/b.parquet"}, walked)
+
+	// A walk resumes after a location without listing the objects before it
+	walked = nil
+	require.NoError(t, fs.WalkFrom("", "t/data/a.parquet", func(location string, info os.FileInfo) error {
+		walked = append(walked, location)
+		return nil
+	}))
+	assert.Equal(t, []string{"t/metadata/v1.json", "t2/data/b.parquet"}, walked)
+
+	assert.Equal(t, int64(3), fs.GetMetrics().ListOperations)
+}
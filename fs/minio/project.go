@@ -0,0 +1,167 @@
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/TFMV/icebox/config"
+)
+
+// stateFileName is written to the data directory while a server started by
+// "icebox minio start" is running, so other processes can find it
+const stateFileName = ".icebox-server.json"
+
+// ServerState describes a running embedded server
+type ServerState struct {
+	PID       int       `json:"pid"`
+	Endpoint  string    `json:"endpoint"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// DefaultProjectConfig returns the embedded server settings for a new project
+func DefaultProjectConfig(dataDir string) *config.MinIOConfig {
+	return &config.MinIOConfig{
+		Address:   DefaultAddress,
+		Port:      DefaultPort,
+		DataDir:   dataDir,
+		AccessKey: DefaultAccessKey,
+		SecretKey: DefaultSecretKey,
+		Region:    DefaultRegion,
+		Bucket:    DefaultBucket,
+		AutoStart: true,
+	}
+}
+
+// S3ConfigFromProject returns the S3 settings that catalogs and the query engine use
+// to reach the embedded server
+func S3ConfigFromProject(cfg *config.MinIOConfig) *config.S3Config {
+	return &config.S3Config{
+		Bucket:          cfg.Bucket,
+		Region:          cfg.Region,
+		Endpoint:        fmt.Sprintf("http://%s:%d", cfg.Address, cfg.Port),
+		AccessKeyID:     cfg.AccessKey,
+		SecretAccessKey: cfg.SecretKey,
+	}
+}
+
+// ServerConfigFromProject converts a project's settings into a server configuration
+func ServerConfigFromProject(cfg *config.MinIOConfig) *EmbeddedMinIOConfig {
+	serverConfig := DefaultMinIOConfig()
+	serverConfig.Address = cfg.Address
+	serverConfig.Port = cfg.Port
+	serverConfig.DataDir = cfg.DataDir
+	serverConfig.AccessKey = cfg.AccessKey
+	serverConfig.SecretKey = cfg.SecretKey
+	serverConfig.DefaultBucket = cfg.Bucket
+	if cfg.Region != "" {
+		serverConfig.Region = cfg.Region
+	}
+	return serverConfig
+}
+
+// WriteState records a running server in its data directory
+func WriteState(dataDir string, state *ServerState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode server state: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dataDir, stateFileName), data, 0600); err != nil {
+		return fmt.Errorf("failed to write server state: %w", err)
+	}
+	return nil
+}
+
+// ReadState returns the recorded server state, or nil when no server was recorded
+func ReadState(dataDir string) (*ServerState, error) {
+	data, err := os.ReadFile(filepath.Join(dataDir, stateFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read server state: %w", err)
+	}
+
+	var state ServerState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode server state: %w", err)
+	}
+	return &state, nil
+}
+
+// RemoveState deletes the recorded server state
+func RemoveState(dataDir string) error {
+	if err := os.Remove(filepath.Join(dataDir, stateFileName)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove server state: %w", err)
+	}
+	return nil
+}
+
+// IsHealthy reports whether an embedded server answers its liveness check at endpoint
+func IsHealthy(ctx context.Context, endpoint string) bool {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"/minio/health/live", nil)
+	if err != nil {
+		return false
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// RunningEndpoint returns the endpoint of a healthy server for the project, checking
+// the recorded state before the configured address. It returns "" when none is running.
+func RunningEndpoint(ctx context.Context, cfg *config.MinIOConfig) string {
+	if state, err := ReadState(cfg.DataDir); err == nil && state != nil && IsHealthy(ctx, state.Endpoint) {
+		return state.Endpoint
+	}
+
+	if endpoint := S3ConfigFromProject(cfg).Endpoint; IsHealthy(ctx, endpoint) {
+		return endpoint
+	}
+	return ""
+}
+
+// EnsureRunning makes the embedded server of a MinIO-backed project reachable. A server
+// that is already running is reused; otherwise one is started in-process when the project
+// allows auto-start. The project's S3 endpoint is updated to the server's actual URL.
+// The returned server is nil when an existing one was reused.
+func EnsureRunning(ctx context.Context, cfg *config.Config) (*EmbeddedMinIO, error) {
+	projectConfig := cfg.Storage.MinIO
+	if projectConfig == nil {
+		return nil, nil
+	}
+	if cfg.Storage.S3 == nil {
+		cfg.Storage.S3 = S3ConfigFromProject(projectConfig)
+	}
+
+	if endpoint := RunningEndpoint(ctx, projectConfig); endpoint != "" {
+		cfg.Storage.S3.Endpoint = endpoint
+		return nil, nil
+	}
+
+	if !projectConfig.AutoStart {
+		return nil, fmt.Errorf("embedded MinIO server is not running; start it with 'icebox minio start'")
+	}
+
+	server, err := NewEmbeddedMinIO(ServerConfigFromProject(projectConfig))
+	if err != nil {
+		return nil, err
+	}
+	if err := server.Start(ctx); err != nil {
+		return nil, err
+	}
+
+	cfg.Storage.S3.Endpoint = server.GetActualURL()
+	return server, nil
+}
@@ -0,0 +1,92 @@
+package minio
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/TFMV/icebox/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectConfigConversion(t *testing.T) {
+	projectConfig := DefaultProjectConfig("/data/minio")
+	assert.True(t, projectConfig.AutoStart)
+	assert.Equal(t, DefaultBucket, projectConfig.Bucket)
+
+	s3Config := S3ConfigFromProject(projectConfig)
+	assert.Equal(t, "http://localhost:9000", s3Config.Endpoint)
+	assert.Equal(t, DefaultBucket, s3Config.Bucket)
+	assert.Equal(t, DefaultAccessKey, s3Config.AccessKeyID)
+
+	serverConfig := ServerConfigFromProject(projectConfig)
+	assert.Equal(t, "/data/minio", serverConfig.DataDir)
+	assert.Equal(t, DefaultBucket, serverConfig.DefaultBucket)
+	assert.Equal(t, DefaultPort, serverConfig.Port)
+}
+
+func TestServerState(t *testing.T) {
+	dataDir := t.TempDir()
+
+	state, err := ReadState(dataDir)
+	require.NoError(t, err)
+	assert.Nil(t, state)
+
+	written := &ServerState{PID: 42, Endpoint: "http://127.0.0.1:9000", StartedAt: time.Now().UTC().Truncate(time.Second)}
+	require.NoError(t, WriteState(dataDir, written))
+
+	state, err = ReadState(dataDir)
+	require.NoError(t, err)
+	assert.Equal(t, written, state)
+
+	require.NoError(t, RemoveState(dataDir))
+	require.NoError(t, RemoveState(dataDir))
+	state, err = ReadState(dataDir)
+	require.NoError(t, err)
+	assert.Nil(t, state)
+}
+
+func TestEnsureRunning(t *testing.T) {
+	os.Setenv("MINIO_TEST_MODE", "true")
+	ctx := context.Background()
+
+	projectConfig := DefaultProjectConfig(t.TempDir())
+	projectConfig.Port = testPort + 100
+
+	// Projects without embedded storage are left alone
+	plain := &config.Config{}
+	server, err := EnsureRunning(ctx, plain)
+	require.NoError(t, err)
+	assert.Nil(t, server)
+	assert.Nil(t, plain.Storage.S3)
+
+	// Without auto-start, a missing server is an error
+	projectConfig.AutoStart = false
+	_, err = EnsureRunning(ctx, &config.Config{Storage: config.StorageConfig{MinIO: projectConfig}})
+	assert.Error(t, err)
+
+	// With auto-start, a server is started and the S3 endpoint follows it
+	projectConfig.AutoStart = true
+	cfg := &config.Config{Storage: config.StorageConfig{MinIO: projectConfig}}
+	server, err = EnsureRunning(ctx, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, server)
+	defer server.Stop(ctx)
+
+	assert.Equal(t, server.GetActualURL(), cfg.Storage.S3.Endpoint)
+	assert.True(t, IsHealthy(ctx, cfg.Storage.S3.Endpoint))
+
+	// A recorded server is reused
+	require.NoError(t, WriteState(projectConfig.DataDir, &ServerState{PID: os.Getpid(), Endpoint: server.GetActualURL()}))
+	cfg = &config.Config{Storage: config.StorageConfig{MinIO: projectConfig}}
+	reused, err := EnsureRunning(ctx, cfg)
+	require.NoError(t, err)
+	assert.Nil(t, reused)
+	assert.Equal(t, server.GetActualURL(), cfg.Storage.S3.Endpoint)
+	assert.Equal(t, server.GetActualURL(), RunningEndpoint(ctx, projectConfig))
+
+	require.NoError(t, server.Stop(ctx))
+	assert.Empty(t, RunningEndpoint(ctx, projectConfig))
+}
@@ -0,0 +1,334 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	iofs "io/fs"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/TFMV/icebox/config"
+	icebergio "github.com/apache/iceberg-go/io"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// Schemes lists the URI schemes of S3 locations
+var Schemes = []string{"s3", "s3a", "s3n"}
+
+// uploadPartSize bounds the memory used when streaming a file of unknown size
+const uploadPartSize = 16 << 20
+
+// FileSystem provides file operations on s3:// locations. The bucket is taken from
+// each location, so one FileSystem can reach every bucket the credentials allow.
+type FileSystem struct {
+	client *minio.Client
+}
+
+// NewFileSystem creates a FileSystem for the endpoint and credentials in cfg. Without
+// static credentials, the AWS environment variables, shared credentials file and
+// instance role are tried in order.
+func NewFileSystem(cfg *config.S3Config) (*FileSystem, error) {
+	if err := Validate(cfg); err != nil {
+		return nil, err
+	}
+
+	endpoint, secure := "s3.amazonaws.com", true
+	lookup := minio.BucketLookupAuto
+	if cfg.Endpoint != "" {
+		u, _ := url.Parse(cfg.Endpoint)
+		endpoint, secure = u.Host, u.Scheme == "https"
+		// Custom endpoints use path-style URLs unless configured otherwise, as in the FileIO
+		lookup = minio.BucketLookupPath
+	}
+	if cfg.VirtualHostStyle {
+		lookup = minio.BucketLookupDNS
+	}
+
+	creds := credentials.NewChainCredentials([]credentials.Provider{
+		&credentials.EnvAWS{},
+		&credentials.FileAWSCredentials{},
+		&credentials.IAM{},
+	})
+	if cfg.AccessKeyID != "" {
+		creds = credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, "")
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = DefaultRegion
+	}
+
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:        creds,
+		Secure:       secure,
+		Region:       region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client for %s: %w", endpoint, err)
+	}
+	return &FileSystem{client: client}, nil
+}
+
+// Open opens an object for reading
+func (fs *FileSystem) Open(location string) (icebergio.File, error) {
+	bucket, key, err := splitLocation(location)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	info, err := fs.client.StatObject(ctx, bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return nil, objectError("open", location, err)
+	}
+	obj, err := fs.client.GetObject(ctx, bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, objectError("open", location, err)
+	}
+	return &objectFile{Object: obj, info: newObjectInfo(key, info)}, nil
+}
+
+// Create starts writing an object. The upload is streamed and completes when the
+// writer is closed.
+func (fs *FileSystem) Create(location string) (io.WriteCloser, error) {
+	bucket, key, err := splitLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	if key == "" {
+		return nil, fmt.Errorf("cannot create %s: location has no object key", location)
+	}
+
+	reader, writer := io.Pipe()
+	w := &objectWriter{pipe: writer, done: make(chan error, 1)}
+	go func() {
+		// Unsigned payloads avoid the aws-chunked encoding, which not every
+		// S3-compatible server decodes
+		opts := minio.PutObjectOptions{PartSize: uploadPartSize, DisableContentSha256: true}
+		_, err := fs.client.PutObject(context.Background(), bucket, key, reader, -1, opts)
+		reader.CloseWithError(err)
+		w.done <- err
+	}()
+	return w, nil
+}
+
+// Remove deletes an object
+func (fs *FileSystem) Remove(location string) error {
+	bucket, key, err := splitLocation(location)
+	if err != nil {
+		return err
+	}
+	if err := fs.client.RemoveObject(context.Background(), bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return objectError("remove", location, err)
+	}
+	return nil
+}
+
+// Stat returns information about an object, or about a prefix that contains objects,
+// which is reported as a directory
+func (fs *FileSystem) Stat(location string) (iofs.FileInfo, error) {
+	bucket, key, err := splitLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+
+	if key == "" {
+		exists, err := fs.client.BucketExists(ctx, bucket)
+		if err != nil {
+			return nil, objectError("stat", location, err)
+		}
+		if !exists {
+			return nil, &iofs.PathError{Op: "stat", Path: location, Err: iofs.ErrNotExist}
+		}
+		return &objectInfo{name: bucket, dir: true}, nil
+	}
+
+	info, err := fs.client.StatObject(ctx, bucket, key, minio.StatObjectOptions{})
+	if err == nil {
+		return newObjectInfo(key, info), nil
+	}
+	if minio.ToErrorResponse(err).Code != "NoSuchKey" {
+		return nil, objectError("stat", location, err)
+	}
+
+	// S3 has no directories; a prefix with objects below it stands in for one
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for obj := range fs.client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: dirPrefix(key), MaxKeys: 1}) {
+		if obj.Err != nil {
+			return nil, objectError("stat", location, obj.Err)
+		}
+		return &objectInfo{name: path.Base(key), dir: true}, nil
+	}
+	return nil, &iofs.PathError{Op: "stat", Path: location, Err: iofs.ErrNotExist}
+}
+
+// List returns the objects and prefixes directly below a location, sorted by name
+func (fs *FileSystem) List(location string) ([]iofs.FileInfo, error) {
+	bucket, key, err := splitLocation(location)
+	if err != nil {
+		return nil, err
+	}
+
+	var infos []iofs.FileInfo
+	for obj := range fs.client.ListObjects(context.Background(), bucket, minio.ListObjectsOptions{Prefix: dirPrefix(key)}) {
+		if obj.Err != nil {
+			return nil, objectError("list", location, obj.Err)
+		}
+		if strings.HasSuffix(obj.Key, "/") {
+			infos = append(infos, &objectInfo{name: path.Base(obj.Key), dir: true})
+			continue
+		}
+		infos = append(infos, newObjectInfo(obj.Key, obj))
+	}
+
+	if len(infos) == 0 && key != "" {
+		return nil, &iofs.PathError{Op: "list", Path: location, Err: iofs.ErrNotExist}
+	}
+	return infos, nil
+}
+
+// Walk calls fn for every object at or below a location in key order
+func (fs *FileSystem) Walk(location string, fn func(location string, info iofs.FileInfo) error) error {
+	return fs.WalkFrom(location, "", fn)
+}
+
+// WalkFrom is like Walk but starts listing after the key of startAfter, so that a walk
+// can be resumed where an earlier one stopped without listing the objects before it
+func (fs *FileSystem) WalkFrom(location, startAfter string, fn func(location string, info iofs.FileInfo) error) error {
+	bucket, key, err := splitLocation(location)
+	if err != nil {
+		return err
+	}
+	scheme, _, _ := strings.Cut(location, "://")
+
+	opts := minio.ListObjectsOptions{Prefix: key, Recursive: true}
+	if startAfter != "" {
+		if _, opts.StartAfter, err = splitLocation(startAfter); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for obj := range fs.client.ListObjects(ctx, bucket, opts) {
+		if obj.Err != nil {
+			return objectError("walk", location, obj.Err)
+		}
+		// A prefix such as "t" also matches "t2/..."
+		if key != "" && obj.Key != key && !strings.HasPrefix(obj.Key, dirPrefix(key)) {
+			continue
+		}
+		if err := fn(fmt.Sprintf("%s://%s/%s", scheme, bucket, obj.Key), newObjectInfo(obj.Key, obj)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitLocation splits an S3 location into its bucket and object key
+func splitLocation(location string) (string, string, error) {
+	scheme, rest, found := strings.Cut(location, "://")
+	if !found || !isS3Scheme(scheme) {
+		return "", "", fmt.Errorf("not an S3 location: %s", location)
+	}
+
+	bucket, key, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return "", "", fmt.Errorf("S3 location has no bucket: %s", location)
+	}
+	return bucket, key, nil
+}
+
+func isS3Scheme(scheme string) bool {
+	for _, s := range Schemes {
+		if s == scheme {
+			return true
+		}
+	}
+	return false
+}
+
+// dirPrefix returns the listing prefix for the entries below key
+func dirPrefix(key string) string {
+	if key == "" || strings.HasSuffix(key, "/") {
+		return key
+	}
+	return key + "/"
+}
+
+// objectError converts S3 not-found responses into errors matching fs.ErrNotExist
+func objectError(op, location string, err error) error {
+	switch minio.ToErrorResponse(err).Code {
+	case "NoSuchKey", "NoSuchBucket":
+		return &iofs.PathError{Op: op, Path: location, Err: iofs.ErrNotExist}
+	}
+	return fmt.Errorf("failed to %s %s: %w", op, location, err)
+}
+
+// objectFile is an open S3 object
+type objectFile struct {
+	*minio.Object
+	info *objectInfo
+}
+
+// Stat returns the object's information as a FileInfo
+func (f *objectFile) Stat() (iofs.FileInfo, error) {
+	return f.info, nil
+}
+
+// objectWriter streams writes to a pending upload
+type objectWriter struct {
+	pipe *io.PipeWriter
+	done chan error
+}
+
+func (w *objectWriter) Write(p []byte) (int, error) {
+	return w.pipe.Write(p)
+}
+
+// Close finishes the upload and reports whether it succeeded
+func (w *objectWriter) Close() error {
+	if w.done == nil {
+		return nil
+	}
+	w.pipe.Close()
+	err := <-w.done
+	w.done = nil
+	if err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	return nil
+}
+
+// objectInfo describes an object or a prefix
+type objectInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+}
+
+func newObjectInfo(key string, info minio.ObjectInfo) *objectInfo {
+	return &objectInfo{name: path.Base(key), size: info.Size, modTime: info.LastModified}
+}
+
+func (i *objectInfo) Name() string       { return i.name }
+func (i *objectInfo) Size() int64        { return i.size }
+func (i *objectInfo) ModTime() time.Time { return i.modTime }
+func (i *objectInfo) IsDir() bool        { return i.dir }
+func (i *objectInfo) Sys() interface{}   { return nil }
+
+func (i *objectInfo) Mode() iofs.FileMode {
+	if i.dir {
+		return iofs.ModeDir | 0755
+	}
+	return 0644
+}
@@ -0,0 +1,80 @@
+package s3
+
+import (
+	"io"
+	iofs "io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestObject(t *testing.T, fs *FileSystem, location, data string) {
+	t.Helper()
+
+	w, err := fs.Create(location)
+	require.NoError(t, err)
+	_, err = io.WriteString(w, data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+}
+
+func TestFileSystem(t *testing.T) {
+	cfg := newTestServer(t)
+	fs, err := NewFileSystem(cfg)
+	require.NoError(t, err)
+
+	writeTestObject(t, fs, "s3://warehouse/db/t/metadata/v1.metadata.json", `{"format-version": 2}`)
+	writeTestObject(t, fs, "s3://warehouse/db/t/data/a.parquet", "parquet")
+	writeTestObject(t, fs, "s3://warehouse/db/t2/data/b.parquet", "other")
+
+	file, err := fs.Open("s3://warehouse/db/t/metadata/v1.metadata.json")
+	require.NoError(t, err)
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	assert.Equal(t, `{"format-version": 2}`, string(data))
+	info, err := file.Stat()
+	require.NoError(t, err)
+	assert.Equal(t, "v1.metadata.json", info.Name())
+	require.NoError(t, file.Close())
+
+	// Prefixes with objects below them are directories
+	info, err = fs.Stat("s3://warehouse/db/t/data/a.parquet")
+	require.NoError(t, err)
+	assert.False(t, info.IsDir())
+	assert.Equal(t, int64(7), info.Size())
+
+	info, err = fs.Stat("s3://warehouse/db/t")
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+
+	_, err = fs.Stat("s3://warehouse/db/missing")
+	assert.ErrorIs(t, err, iofs.ErrNotExist)
+	_, err = fs.Open("s3://warehouse/db/missing")
+	assert.ErrorIs(t, err, iofs.ErrNotExist)
+
+	infos, err := fs.List("s3://warehouse/db/t")
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	assert.Equal(t, "data", infos[0].Name())
+	assert.True(t, infos[0].IsDir())
+	assert.Equal(t, "metadata", infos[1].Name())
+
+	// Walking a prefix does not include siblings that share it
+	var walked []string
+	require.NoError(t, fs.Walk("s3://warehouse/db/t", func(location string, info iofs.FileInfo) error {
+		walked = append(walked, location)
+		return nil
+	}))
+	assert.Equal(t, []string{
+		"s3://warehouse/db/t/data/a.parquet",
+		"s3://warehouse/db/t/metadata/v1.metadata.json",
+	}, walked)
+
+	require.NoError(t, fs.Remove("s3://warehouse/db/t/data/a.parquet"))
+	_, err = fs.Stat("s3://warehouse/db/t/data/a.parquet")
+	assert.ErrorIs(t, err, iofs.ErrNotExist)
+
+	_, err = fs.Open("/local/path")
+	assert.Error(t, err)
+}
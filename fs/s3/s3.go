@@ -0,0 +1,88 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/TFMV/icebox/config"
+	"github.com/apache/iceberg-go"
+	icebergio "github.com/apache/iceberg-go/io"
+)
+
+// DefaultRegion is used when the configuration does not name a region
+const DefaultRegion = "us-east-1"
+
+// Warehouse returns the s3:// location that tables and metadata are written under
+func Warehouse(cfg *config.S3Config) string {
+	warehouse := "s3://" + cfg.Bucket
+	if prefix := strings.Trim(cfg.Prefix, "/"); prefix != "" {
+		warehouse += "/" + prefix
+	}
+	return warehouse
+}
+
+// IsRemoteLocation reports whether a location is an object store URL rather than a local path
+func IsRemoteLocation(location string) bool {
+	scheme, _, found := strings.Cut(location, "://")
+	return found && scheme != "file"
+}
+
+// Properties converts an S3 configuration into iceberg-go FileIO properties
+func Properties(cfg *config.S3Config) iceberg.Properties {
+	props := iceberg.Properties{
+		icebergio.S3Region: DefaultRegion,
+	}
+
+	if cfg.Region != "" {
+		props[icebergio.S3Region] = cfg.Region
+	}
+	if cfg.Endpoint != "" {
+		props[icebergio.S3EndpointURL] = cfg.Endpoint
+	}
+	if cfg.AccessKeyID != "" {
+		props[icebergio.S3AccessKeyID] = cfg.AccessKeyID
+	}
+	if cfg.SecretAccessKey != "" {
+		props[icebergio.S3SecretAccessKey] = cfg.SecretAccessKey
+	}
+	if cfg.VirtualHostStyle {
+		props[icebergio.S3ForceVirtualAddressing] = "true"
+	}
+
+	return props
+}
+
+// NewFileIO creates an iceberg-go FileIO that reads and writes the configured bucket
+func NewFileIO(ctx context.Context, cfg *config.S3Config) (icebergio.IO, error) {
+	if err := Validate(cfg); err != nil {
+		return nil, err
+	}
+
+	fileIO, err := icebergio.LoadFS(ctx, Properties(cfg), Warehouse(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 file IO for bucket %s: %w", cfg.Bucket, err)
+	}
+	return fileIO, nil
+}
+
+// Validate checks that an S3 configuration names a bucket and a usable endpoint
+func Validate(cfg *config.S3Config) error {
+	if cfg == nil {
+		return fmt.Errorf("S3 storage configuration is required")
+	}
+	if cfg.Bucket == "" {
+		return fmt.Errorf("S3 bucket is required")
+	}
+	if strings.ContainsAny(cfg.Bucket, "/:") {
+		return fmt.Errorf("invalid S3 bucket name: %s", cfg.Bucket)
+	}
+	if cfg.Endpoint != "" {
+		endpoint, err := url.Parse(cfg.Endpoint)
+		if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+			return fmt.Errorf("invalid S3 endpoint %q: expected http(s)://host[:port]", cfg.Endpoint)
+		}
+	}
+	return nil
+}
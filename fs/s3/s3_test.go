@@ -0,0 +1,99 @@
+package s3
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TFMV/icebox/config"
+	icebergio "github.com/apache/iceberg-go/io"
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarehouse(t *testing.T) {
+	assert.Equal(t, "s3://warehouse", Warehouse(&config.S3Config{Bucket: "warehouse"}))
+	assert.Equal(t, "s3://warehouse/a/b", Warehouse(&config.S3Config{Bucket: "warehouse", Prefix: "/a/b/"}))
+}
+
+func TestIsRemoteLocation(t *testing.T) {
+	assert.True(t, IsRemoteLocation("s3://bucket/key"))
+	assert.True(t, IsRemoteLocation("s3a://bucket/key"))
+	assert.False(t, IsRemoteLocation("file:///tmp/data"))
+	assert.False(t, IsRemoteLocation("/tmp/data"))
+	assert.False(t, IsRemoteLocation(""))
+}
+
+func TestProperties(t *testing.T) {
+	props := Properties(&config.S3Config{
+		Bucket:          "warehouse",
+		Endpoint:        "http://localhost:9000",
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+	})
+
+	assert.Equal(t, DefaultRegion, props[icebergio.S3Region])
+	assert.Equal(t, "http://localhost:9000", props[icebergio.S3EndpointURL])
+	assert.Equal(t, "key", props[icebergio.S3AccessKeyID])
+	assert.Equal(t, "secret", props[icebergio.S3SecretAccessKey])
+	assert.NotContains(t, props, icebergio.S3ForceVirtualAddressing)
+
+	props = Properties(&config.S3Config{Bucket: "warehouse", Region: "eu-west-1", VirtualHostStyle: true})
+	assert.Equal(t, "eu-west-1", props[icebergio.S3Region])
+	assert.Equal(t, "true", props[icebergio.S3ForceVirtualAddressing])
+	assert.NotContains(t, props, icebergio.S3AccessKeyID)
+}
+
+func TestValidate(t *testing.T) {
+	assert.Error(t, Validate(nil))
+	assert.Error(t, Validate(&config.S3Config{}))
+	assert.Error(t, Validate(&config.S3Config{Bucket: "a/b"}))
+	assert.Error(t, Validate(&config.S3Config{Bucket: "warehouse", Endpoint: "localhost:9000"}))
+	assert.NoError(t, Validate(&config.S3Config{Bucket: "warehouse"}))
+	assert.NoError(t, Validate(&config.S3Config{Bucket: "warehouse", Endpoint: "https://s3.example.com"}))
+}
+
+func TestNewFileIO(t *testing.T) {
+	cfg := newTestServer(t)
+	fileIO, err := NewFileIO(context.Background(), cfg)
+	require.NoError(t, err)
+
+	writer, ok := fileIO.(icebergio.WriteFileIO)
+	require.True(t, ok)
+
+	location := Warehouse(cfg) + "/metadata/v1.metadata.json"
+	require.NoError(t, writer.WriteFile(location, []byte(`{"format-version": 2}`)))
+
+	file, err := fileIO.Open(location)
+	require.NoError(t, err)
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	assert.Equal(t, `{"format-version": 2}`, string(data))
+
+	require.NoError(t, fileIO.Remove(location))
+	_, err = fileIO.Open(location)
+	assert.Error(t, err)
+}
+
+// newTestServer starts an in-memory S3 server for the duration of a test and returns
+// a configuration pointing at an empty bucket on it
+func newTestServer(t testing.TB) *config.S3Config {
+	t.Helper()
+
+	backend := s3mem.New()
+	require.NoError(t, backend.CreateBucket("warehouse"))
+
+	server := httptest.NewServer(gofakes3.New(backend).Server())
+	t.Cleanup(server.Close)
+
+	return &config.S3Config{
+		Bucket:          "warehouse",
+		Endpoint:        server.URL,
+		AccessKeyID:     "test",
+		SecretAccessKey: "test",
+	}
+}
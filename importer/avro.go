@@ -3,13 +3,12 @@ package importer
 import (
 	"context"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/TFMV/icebox/catalog"
 	"github.com/TFMV/icebox/config"
-	"github.com/TFMV/icebox/fs/local"
+	"github.com/TFMV/icebox/fs/s3"
 	"github.com/TFMV/icebox/tableops"
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
@@ -57,7 +56,7 @@ func (a *AvroImporter) Close() error {
 // InferSchema reads an Avro file and infers the schema
 func (a *AvroImporter) InferSchema(avroFile string) (*Schema, *FileStats, error) {
 	// Get file stats
-	fileInfo, err := os.Stat(avroFile)
+	fileInfo, err := statSource(context.Background(), a.config, avroFile)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to stat file: %w", err)
 	}
@@ -82,6 +81,10 @@ func (a *AvroImporter) InferSchema(avroFile string) (*Schema, *FileStats, error)
 
 // GetTableLocation returns the location where table data would be stored
 func (a *AvroImporter) GetTableLocation(tableIdent table.Identifier) string {
+	if a.config.Storage.S3 != nil {
+		return s3.Warehouse(a.config.Storage.S3) + "/" + strings.Join(tableIdent, "/")
+	}
+
 	if a.config.Storage.FileSystem == nil {
 		return ""
 	}
@@ -97,6 +100,8 @@ func (a *AvroImporter) GetTableLocation(tableIdent table.Identifier) string {
 
 // ImportTable imports an Avro file into an Iceberg table
 func (a *AvroImporter) ImportTable(ctx context.Context, req ImportRequest) (*ImportResult, error) {
+	ctx = withProgress(ctx, req.OnProgress)
+
 	// 1. Create namespace if it doesn't exist
 	exists, err := a.catalog.CheckNamespaceExists(ctx, req.NamespaceIdent)
 	if err != nil {
@@ -133,6 +138,9 @@ func (a *AvroImporter) ImportTable(ctx context.Context, req ImportRequest) (*Imp
 	}
 
 	// 3. Read the Avro file to get the proper Arrow table
+	if info, err := statSource(ctx, a.config, req.ParquetFile); err == nil {
+		progressFrom(ctx).setTotalBytes(info.Size())
+	}
 	arrowTable, err := a.readAvroFileWithFallback(ctx, req.ParquetFile) // Note: reusing ParquetFile field for Avro file path
 	if err != nil {
 		return nil, fmt.Errorf("failed to read Avro file: %w", err)
@@ -158,7 +166,7 @@ func (a *AvroImporter) ImportTable(ctx context.Context, req ImportRequest) (*Imp
 	writeOpts.SnapshotProperties["icebox.import.format"] = "avro"
 
 	// Get file info for metadata
-	fileInfo, err := os.Stat(req.ParquetFile)
+	fileInfo, err := statSource(ctx, a.config, req.ParquetFile)
 	if err == nil {
 		writeOpts.SnapshotProperties["icebox.import.timestamp"] = fmt.Sprintf("%d", fileInfo.ModTime().Unix())
 	}
@@ -167,13 +175,14 @@ func (a *AvroImporter) ImportTable(ctx context.Context, req ImportRequest) (*Imp
 	if err != nil {
 		return nil, fmt.Errorf("failed to write data to table: %w", err)
 	}
+	progressFrom(ctx).setRowsWritten(arrowTable.NumRows())
 
 	// 7. Get table location and file info for result
 	tableLocation := a.GetTableLocation(req.TableIdent)
 
 	// Re-read file info if not already available
 	if fileInfo == nil {
-		fileInfo, _ = os.Stat(req.ParquetFile)
+		fileInfo, _ = statSource(ctx, a.config, req.ParquetFile)
 	}
 
 	fmt.Printf("📁 Copied data to: %s\n", tableLocation)
@@ -204,7 +213,7 @@ func (a *AvroImporter) readAvroSchemaWithFallback(avroFile string) (*arrow.Schem
 	}, nil)
 
 	// Try to estimate record count from file size (very rough estimate)
-	fileInfo, statErr := os.Stat(avroFile)
+	fileInfo, statErr := statSource(context.Background(), a.config, avroFile)
 	if statErr != nil {
 		return fallbackSchema, 0, nil
 	}
@@ -220,10 +229,10 @@ func (a *AvroImporter) readAvroSchemaWithFallback(avroFile string) (*arrow.Schem
 
 // readAvroSchema reads the schema and metadata from an Avro file without loading all data
 func (a *AvroImporter) readAvroSchema(avroFile string) (*arrow.Schema, int64, error) {
-	// Open the Avro file
-	f, err := os.Open(avroFile)
+	// Open the Avro file; records are streamed from it
+	f, err := openSource(context.Background(), a.config, avroFile)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to open file: %w", err)
+		return nil, 0, err
 	}
 	defer f.Close()
 
@@ -285,25 +294,10 @@ func (a *AvroImporter) readAvroFileWithFallback(ctx context.Context, path string
 
 // readAvroFile reads an Avro file and returns an Arrow table
 func (a *AvroImporter) readAvroFile(ctx context.Context, path string) (arrow.Table, error) {
-	// Remove file:// prefix if present
-	localPath := path
-	if strings.HasPrefix(path, "file://") {
-		localPath = path[7:]
-	}
-
-	// Ensure the file exists
-	exists, err := local.NewFileSystem("").Exists(localPath)
+	// Open the Avro file; records are streamed from it
+	f, err := openSource(ctx, a.config, path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check file existence: %w", err)
-	}
-	if !exists {
-		return nil, fmt.Errorf("avro file does not exist: %s", localPath)
-	}
-
-	// Open the Avro file
-	f, err := os.Open(localPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, err
 	}
 	defer f.Close()
 
@@ -323,6 +317,13 @@ func (a *AvroImporter) readAvroFile(ctx context.Context, path string) (arrow.Tab
 		}
 		record.Retain() // Keep the record alive after reader.Next()
 		records = append(records, record)
+		progressFrom(ctx).addRowsRead(record.NumRows())
+	}
+	if err := reader.Err(); err != nil {
+		for _, record := range records {
+			record.Release()
+		}
+		return nil, fmt.Errorf("failed to read avro records: %w", err)
 	}
 
 	if len(records) == 0 {
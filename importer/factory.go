@@ -1,10 +1,9 @@
 package importer
 
 import (
+	"bytes"
 	"context"
 	"fmt"
-	"path/filepath"
-	"strings"
 
 	"github.com/TFMV/icebox/config"
 	"github.com/apache/iceberg-go/table"
@@ -45,9 +44,9 @@ func NewImporterFactory(cfg *config.Config) *ImporterFactory {
 	}
 }
 
-// CreateImporter creates an importer based on the file extension
+// CreateImporter creates an importer based on the file extension of a path or URL
 func (f *ImporterFactory) CreateImporter(filePath string) (Importer, ImporterType, error) {
-	ext := strings.ToLower(filepath.Ext(filePath))
+	ext := sourceExt(filePath)
 
 	switch ext {
 	case ".parquet":
@@ -90,7 +89,7 @@ func (f *ImporterFactory) GetSupportedFormats() []string {
 
 // DetectFileType detects the file type based on file extension
 func (f *ImporterFactory) DetectFileType(filePath string) (ImporterType, error) {
-	ext := strings.ToLower(filepath.Ext(filePath))
+	ext := sourceExt(filePath)
 
 	switch ext {
 	case ".parquet":
@@ -101,3 +100,22 @@ func (f *ImporterFactory) DetectFileType(filePath string) (ImporterType, error)
 		return "", fmt.Errorf("unsupported file format: %s", ext)
 	}
 }
+
+// File signatures checked by DetectFileTypeFromHeader
+var (
+	parquetMagic = []byte("PAR1")
+	avroMagic    = []byte("Obj\x01")
+)
+
+// DetectFileTypeFromHeader detects the file type from the magic bytes at the start of a
+// file, for sources without a meaningful name such as uploads
+func (f *ImporterFactory) DetectFileTypeFromHeader(header []byte) (ImporterType, error) {
+	switch {
+	case bytes.HasPrefix(header, parquetMagic):
+		return ImporterTypeParquet, nil
+	case bytes.HasPrefix(header, avroMagic):
+		return ImporterTypeAvro, nil
+	default:
+		return "", fmt.Errorf("unsupported file format: expected a Parquet or Avro file")
+	}
+}
@@ -1,6 +1,8 @@
 package importer
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/TFMV/icebox/config"
@@ -176,6 +178,48 @@ func TestImporterFactory_DetectFileType(t *testing.T) {
 	}
 }
 
+func TestImporterFactory_DetectFileTypeFromHeader(t *testing.T) {
+	factory := &ImporterFactory{}
+
+	tests := []struct {
+		name         string
+		header       []byte
+		expectedType ImporterType
+		shouldError  bool
+	}{
+		{"parquet", []byte("PAR1\x15\x04"), ImporterTypeParquet, false},
+		{"avro", []byte("Obj\x01\x04\x14"), ImporterTypeAvro, false},
+		{"csv", []byte("id,name\n"), "", true},
+		{"truncated", []byte("PA"), "", true},
+		{"empty", nil, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileType, err := factory.DetectFileTypeFromHeader(tt.header)
+			if tt.shouldError {
+				assert.Error(t, err)
+				assert.Equal(t, ImporterType(""), fileType)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedType, fileType)
+			}
+		})
+	}
+
+	// The signatures match real files
+	for file, expectedType := range map[string]ImporterType{
+		"titanic.parquet":   ImporterTypeParquet,
+		"simple_users.avro": ImporterTypeAvro,
+	} {
+		data, err := os.ReadFile(filepath.Join("..", "testdata", file))
+		require.NoError(t, err)
+		fileType, err := factory.DetectFileTypeFromHeader(data[:8])
+		require.NoError(t, err)
+		assert.Equal(t, expectedType, fileType, file)
+	}
+}
+
 func TestImporterFactory_GetSupportedFormats(t *testing.T) {
 	cfg := &config.Config{}
 	factory := NewImporterFactory(cfg)
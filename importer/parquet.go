@@ -3,13 +3,12 @@ package importer
 import (
 	"context"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/TFMV/icebox/catalog"
 	"github.com/TFMV/icebox/config"
-	"github.com/TFMV/icebox/fs/local"
+	"github.com/TFMV/icebox/fs/s3"
 	"github.com/TFMV/icebox/tableops"
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/memory"
@@ -46,6 +45,8 @@ type ImportRequest struct {
 	Schema         *Schema
 	Overwrite      bool
 	PartitionBy    []string
+	// OnProgress, when set, is called as the file is read and written
+	OnProgress func(Progress)
 }
 
 // ImportResult contains the results of a table import
@@ -94,7 +95,7 @@ func (p *ParquetImporter) Close() error {
 // InferSchema reads a Parquet file and infers the schema
 func (p *ParquetImporter) InferSchema(parquetFile string) (*Schema, *FileStats, error) {
 	// Get file stats
-	fileInfo, err := os.Stat(parquetFile)
+	fileInfo, err := statSource(context.Background(), p.config, parquetFile)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to stat file: %w", err)
 	}
@@ -119,6 +120,10 @@ func (p *ParquetImporter) InferSchema(parquetFile string) (*Schema, *FileStats,
 
 // GetTableLocation returns the location where table data would be stored
 func (p *ParquetImporter) GetTableLocation(tableIdent table.Identifier) string {
+	if p.config.Storage.S3 != nil {
+		return s3.Warehouse(p.config.Storage.S3) + "/" + strings.Join(tableIdent, "/")
+	}
+
 	if p.config.Storage.FileSystem == nil {
 		return ""
 	}
@@ -134,6 +139,8 @@ func (p *ParquetImporter) GetTableLocation(tableIdent table.Identifier) string {
 
 // ImportTable imports a Parquet file into an Iceberg table
 func (p *ParquetImporter) ImportTable(ctx context.Context, req ImportRequest) (*ImportResult, error) {
+	ctx = withProgress(ctx, req.OnProgress)
+
 	// 1. Create namespace if it doesn't exist
 	exists, err := p.catalog.CheckNamespaceExists(ctx, req.NamespaceIdent)
 	if err != nil {
@@ -170,6 +177,9 @@ func (p *ParquetImporter) ImportTable(ctx context.Context, req ImportRequest) (*
 	}
 
 	// 3. Read the Parquet file to get the proper Arrow schema
+	if info, err := statSource(ctx, p.config, req.ParquetFile); err == nil {
+		progressFrom(ctx).setTotalBytes(info.Size())
+	}
 	arrowTable, err := p.readParquetFile(ctx, req.ParquetFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read Parquet file: %w", err)
@@ -194,7 +204,7 @@ func (p *ParquetImporter) ImportTable(ctx context.Context, req ImportRequest) (*
 	writeOpts.SnapshotProperties["icebox.import.source"] = req.ParquetFile
 
 	// Get file info for metadata
-	fileInfo, err := os.Stat(req.ParquetFile)
+	fileInfo, err := statSource(ctx, p.config, req.ParquetFile)
 	if err == nil {
 		writeOpts.SnapshotProperties["icebox.import.timestamp"] = fmt.Sprintf("%d", fileInfo.ModTime().Unix())
 	}
@@ -203,13 +213,14 @@ func (p *ParquetImporter) ImportTable(ctx context.Context, req ImportRequest) (*
 	if err != nil {
 		return nil, fmt.Errorf("failed to write data to table: %w", err)
 	}
+	progressFrom(ctx).setRowsWritten(arrowTable.NumRows())
 
 	// 7. Get table location and file info for result
 	tableLocation := p.GetTableLocation(req.TableIdent)
 
 	// Re-read file info if not already available
 	if fileInfo == nil {
-		fileInfo, _ = os.Stat(req.ParquetFile)
+		fileInfo, _ = statSource(ctx, p.config, req.ParquetFile)
 	}
 
 	fmt.Printf("📁 Copied data to: %s\n", tableLocation)
@@ -222,16 +233,17 @@ func (p *ParquetImporter) ImportTable(ctx context.Context, req ImportRequest) (*
 	}, nil
 }
 
-// readParquetSchema reads the schema and metadata from a Parquet file without loading all data
+// readParquetSchema reads the schema and record count from a Parquet file's footer
+// without loading any data
 func (p *ParquetImporter) readParquetSchema(parquetFile string) (*arrow.Schema, int64, error) {
 	// Open the Parquet file
-	f, err := os.Open(parquetFile)
+	f, err := openSource(context.Background(), p.config, parquetFile)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to open file: %w", err)
+		return nil, 0, err
 	}
 	defer f.Close()
 
-	// Create parquet reader
+	// Create parquet reader, which reads the footer
 	parquetReader, err := file.NewParquetReader(f)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to create parquet reader: %w", err)
@@ -250,38 +262,15 @@ func (p *ParquetImporter) readParquetSchema(parquetFile string) (*arrow.Schema,
 		return nil, 0, fmt.Errorf("failed to get arrow schema: %w", err)
 	}
 
-	// TODO: For record count, we'll read the table to get the exact count
-	// This is more reliable than trying to parse metadata directly
-	arrowTable, err := arrowReader.ReadTable(context.Background())
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to read arrow table for record count: %w", err)
-	}
-	defer arrowTable.Release()
-
-	return schema, arrowTable.NumRows(), nil
+	return schema, parquetReader.NumRows(), nil
 }
 
 // readParquetFile reads a Parquet file and returns an Arrow table
 func (p *ParquetImporter) readParquetFile(ctx context.Context, path string) (arrow.Table, error) {
-	// Remove file:// prefix if present
-	localPath := path
-	if strings.HasPrefix(path, "file://") {
-		localPath = path[7:]
-	}
-
-	// Ensure the file exists
-	exists, err := local.NewFileSystem("").Exists(localPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to check file existence: %w", err)
-	}
-	if !exists {
-		return nil, fmt.Errorf("parquet file does not exist: %s", localPath)
-	}
-
 	// Open the Parquet file
-	f, err := os.Open(localPath)
+	f, err := openSource(ctx, p.config, path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, err
 	}
 	defer f.Close()
 
@@ -305,6 +294,7 @@ func (p *ParquetImporter) readParquetFile(ctx context.Context, path string) (arr
 	if err != nil {
 		return nil, fmt.Errorf("failed to read arrow table: %w", err)
 	}
+	progressFrom(ctx).setRowsRead(arrowTable.NumRows())
 
 	return arrowTable, nil
 }
@@ -0,0 +1,101 @@
+package importer
+
+import (
+	"context"
+	"sync"
+
+	icebergio "github.com/apache/iceberg-go/io"
+)
+
+// Progress reports how far an import has got
+type Progress struct {
+	// BytesRead counts the bytes read from the source file so far
+	BytesRead int64
+	// TotalBytes is the size of the source file, or zero when unknown
+	TotalBytes int64
+	// RowsRead counts the records read from the source file so far
+	RowsRead int64
+	// RowsWritten counts the records committed to the table
+	RowsWritten int64
+}
+
+// progressTracker accumulates the progress of an import and passes every update to the
+// request's OnProgress callback. A nil tracker ignores updates, so imports without a
+// callback need no checks.
+type progressTracker struct {
+	mu       sync.Mutex
+	progress Progress
+	report   func(Progress)
+}
+
+type progressKey struct{}
+
+// withProgress returns a context carrying a tracker for report, or ctx itself when
+// report is nil. Sources opened with the returned context count the bytes read.
+func withProgress(ctx context.Context, report func(Progress)) context.Context {
+	if report == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, &progressTracker{report: report})
+}
+
+// progressFrom returns the tracker of ctx, or nil
+func progressFrom(ctx context.Context) *progressTracker {
+	tracker, _ := ctx.Value(progressKey{}).(*progressTracker)
+	return tracker
+}
+
+func (t *progressTracker) update(fn func(*Progress)) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	fn(&t.progress)
+	progress := t.progress
+	t.mu.Unlock()
+
+	t.report(progress)
+}
+
+func (t *progressTracker) setTotalBytes(n int64) {
+	t.update(func(p *Progress) { p.TotalBytes = n })
+}
+
+func (t *progressTracker) addRowsRead(n int64) {
+	t.update(func(p *Progress) { p.RowsRead += n })
+}
+
+func (t *progressTracker) setRowsRead(n int64) {
+	t.update(func(p *Progress) { p.RowsRead = n })
+}
+
+func (t *progressTracker) setRowsWritten(n int64) {
+	t.update(func(p *Progress) { p.RowsWritten = n })
+}
+
+// trackedFile counts the bytes read from a source file and stops reads once the import
+// is canceled
+type trackedFile struct {
+	icebergio.File
+	ctx     context.Context
+	tracker *progressTracker
+}
+
+func (f *trackedFile) Read(p []byte) (int, error) {
+	if err := f.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := f.File.Read(p)
+	f.tracker.update(func(p *Progress) { p.BytesRead += int64(n) })
+	return n, err
+}
+
+func (f *trackedFile) ReadAt(p []byte, off int64) (int, error) {
+	if err := f.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := f.File.ReadAt(p, off)
+	f.tracker.update(func(p *Progress) { p.BytesRead += int64(n) })
+	return n, err
+}
@@ -0,0 +1,65 @@
+package importer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportProgress(t *testing.T) {
+	importer, err := NewParquetImporter(createTestConfig(t))
+	require.NoError(t, err)
+	defer importer.Close()
+
+	source := filepath.Join("..", "testdata", "titanic.parquet")
+	info, err := os.Stat(source)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var last Progress
+	updates := 0
+	_, err = importer.ImportTable(context.Background(), ImportRequest{
+		ParquetFile:    source,
+		TableIdent:     table.Identifier{"progress", "titanic"},
+		NamespaceIdent: table.Identifier{"progress"},
+		OnProgress: func(p Progress) {
+			mu.Lock()
+			defer mu.Unlock()
+			last = p
+			updates++
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Greater(t, updates, 1)
+	assert.Equal(t, info.Size(), last.TotalBytes)
+	assert.Greater(t, last.BytesRead, int64(0))
+	assert.Equal(t, int64(891), last.RowsRead)
+	assert.Equal(t, int64(891), last.RowsWritten)
+}
+
+func TestImportCanceled(t *testing.T) {
+	importer, err := NewParquetImporter(createTestConfig(t))
+	require.NoError(t, err)
+	defer importer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err = importer.ImportTable(ctx, ImportRequest{
+		ParquetFile:    filepath.Join("..", "testdata", "titanic.parquet"),
+		TableIdent:     table.Identifier{"progress", "titanic"},
+		NamespaceIdent: table.Identifier{"progress"},
+		OnProgress: func(p Progress) {
+			// Cancel as soon as the file starts being read
+			if p.BytesRead > 0 {
+				cancel()
+			}
+		},
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,63 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	iofs "io/fs"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/fs"
+	"github.com/TFMV/icebox/fs/local"
+	icebergio "github.com/apache/iceberg-go/io"
+)
+
+// IsRemoteSource reports whether a file to import is given as a URL, such as
+// s3://bucket/data.parquet or https://host/data.parquet, rather than a local path
+func IsRemoteSource(location string) bool {
+	return fs.Scheme(location) != local.Scheme
+}
+
+// sourceExt returns the file extension of a local path or URL, ignoring any query
+// string of the URL
+func sourceExt(location string) string {
+	if IsRemoteSource(location) {
+		if u, err := url.Parse(location); err == nil {
+			return strings.ToLower(path.Ext(u.Path))
+		}
+	}
+	return strings.ToLower(filepath.Ext(location))
+}
+
+// statSource returns information about a file to import. Object store credentials
+// come from the project's storage configuration.
+func statSource(ctx context.Context, cfg *config.Config, location string) (iofs.FileInfo, error) {
+	storage, err := fs.Resolve(ctx, location, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return storage.Stat(location)
+}
+
+// openSource opens a file to import. Remote files are read with ranged requests, so
+// a reader that seeks only downloads the parts it reads. Reads fail once ctx is
+// canceled and count towards the import's progress.
+func openSource(ctx context.Context, cfg *config.Config, location string) (icebergio.File, error) {
+	storage, err := fs.Resolve(ctx, location, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := storage.Open(location)
+	if errors.Is(err, iofs.ErrNotExist) {
+		return nil, fmt.Errorf("file does not exist: %s", location)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return &trackedFile{File: f, ctx: ctx, tracker: progressFrom(ctx)}, nil
+}
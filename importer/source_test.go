@@ -0,0 +1,108 @@
+package importer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/fs"
+	"github.com/apache/iceberg-go/table"
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceExt(t *testing.T) {
+	assert.Equal(t, ".parquet", sourceExt("/data/file.PARQUET"))
+	assert.Equal(t, ".parquet", sourceExt("s3://bucket/data/file.parquet"))
+	assert.Equal(t, ".avro", sourceExt("https://example.com/data/file.avro?X-Amz-Signature=abc.def"))
+	assert.True(t, IsRemoteSource("https://example.com/file.parquet"))
+	assert.False(t, IsRemoteSource("/data/file.parquet"))
+}
+
+func TestImportFromHTTP(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir(filepath.Join("..", "testdata"))))
+	defer server.Close()
+
+	importer, err := NewParquetImporter(createTestConfig(t))
+	require.NoError(t, err)
+	defer importer.Close()
+
+	location := server.URL + "/titanic.parquet"
+	schema, stats, err := importer.InferSchema(location)
+	require.NoError(t, err)
+	assert.NotEmpty(t, schema.Fields)
+	assert.Equal(t, int64(891), stats.RecordCount)
+
+	result, err := importer.ImportTable(context.Background(), ImportRequest{
+		ParquetFile:    location,
+		TableIdent:     table.Identifier{"remote", "titanic"},
+		NamespaceIdent: table.Identifier{"remote"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(891), result.RecordCount)
+
+	_, _, err = importer.InferSchema(server.URL + "/missing.parquet")
+	assert.Error(t, err)
+}
+
+func TestImportFromS3(t *testing.T) {
+	backend := s3mem.New()
+	require.NoError(t, backend.CreateBucket("datasets"))
+	server := httptest.NewServer(gofakes3.New(backend).Server())
+	defer server.Close()
+
+	cfg := createTestConfig(t)
+	cfg.Storage.S3 = &config.S3Config{
+		Bucket:          "datasets",
+		Endpoint:        server.URL,
+		AccessKeyID:     "test",
+		SecretAccessKey: "test",
+	}
+
+	// Upload a sample dataset using the project's credentials
+	location := "s3://datasets/samples/titanic.parquet"
+	storage, err := fs.Resolve(context.Background(), location, cfg)
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join("..", "testdata", "titanic.parquet"))
+	require.NoError(t, err)
+	w, err := storage.Create(location)
+	require.NoError(t, err)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	importer, err := NewParquetImporter(cfg)
+	require.NoError(t, err)
+	defer importer.Close()
+
+	_, stats, err := importer.InferSchema(location)
+	require.NoError(t, err)
+	assert.Equal(t, int64(891), stats.RecordCount)
+	assert.Equal(t, int64(len(data)), stats.FileSize)
+
+	result, err := importer.ImportTable(context.Background(), ImportRequest{
+		ParquetFile:    location,
+		TableIdent:     table.Identifier{"remote", "titanic"},
+		NamespaceIdent: table.Identifier{"remote"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(891), result.RecordCount)
+
+	// Avro files are streamed from the same storage
+	_, err = openSource(context.Background(), cfg, "s3://datasets/samples/missing.avro")
+	assert.ErrorContains(t, err, "does not exist")
+	file, err := openSource(context.Background(), cfg, location)
+	require.NoError(t, err)
+	defer file.Close()
+	head := make([]byte, 4)
+	_, err = io.ReadFull(file, head)
+	require.NoError(t, err)
+	assert.Equal(t, "PAR1", string(head))
+}
@@ -104,7 +104,7 @@ func testEngineWithCatalogType(t *testing.T, catalogType string) {
 		require.NoError(t, err)
 		assert.Equal(t, int64(1), result.RowCount)
 		assert.Equal(t, []string{"answer", "engine"}, result.Columns)
-		assert.Equal(t, []interface{}{int32(42), "DuckDB"}, result.Rows[0])
+		assert.Equal(t, []interface{}{int32(42), "DuckDB"}, result.Rows()[0])
 
 		// Test table listing (should be empty initially)
 		tables, err := engine.ListTables(ctx)
@@ -149,7 +149,7 @@ func testEngineWithCatalogType(t *testing.T, catalogType string) {
 
 		// Verify schema columns are present
 		columnNames := make(map[string]bool)
-		for _, row := range result.Rows {
+		for _, row := range result.Rows() {
 			if len(row) > 0 {
 				if colName, ok := row[0].(string); ok {
 					columnNames[colName] = true
@@ -271,8 +271,8 @@ func testEngineWithCatalogType(t *testing.T, catalogType string) {
 		assert.Len(t, result.Columns, 3)
 
 		// Verify memory usage is reasonable
-		assert.True(t, len(result.Rows) == 100)
-		assert.True(t, len(result.Rows[0]) == 3)
+		assert.True(t, len(result.Rows()) == 100)
+		assert.True(t, len(result.Rows()[0]) == 3)
 	})
 
 	t.Logf("Successfully completed all integration tests with %s catalog", catalogType)
@@ -0,0 +1,467 @@
+// Package jobs runs long operations, such as imports, in the background on a bounded
+// pool of workers. Each job moves from queued to running to a final state, and the
+// history of jobs is persisted to a JSON file so it survives restarts. A history file
+// is locked by the manager using it, so that two managers never overwrite each other's
+// history.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNotFound is returned for unknown job IDs
+	ErrNotFound = errors.New("job not found")
+	// ErrQueueFull is returned when a job is submitted while every queue slot is taken
+	ErrQueueFull = errors.New("job queue is full")
+	// ErrFinished is returned when canceling a job that has already finished
+	ErrFinished = errors.New("job has already finished")
+	// ErrClosed is returned when submitting a job after the manager was closed
+	ErrClosed = errors.New("job manager is closed")
+	// ErrHistoryLocked is returned by NewManager when another manager, in this or
+	// another process, uses the same history file
+	ErrHistoryLocked = errors.New("job history is in use by another job manager")
+
+	errCanceledByRequest = errors.New("canceled by request")
+	errShutdown          = errors.New("canceled by server shutdown")
+)
+
+// State is the stage of a job's life
+type State string
+
+const (
+	StateQueued    State = "queued"
+	StateRunning   State = "running"
+	StateSucceeded State = "succeeded"
+	StateFailed    State = "failed"
+	StateCanceled  State = "canceled"
+)
+
+// Final reports whether a job in this state will not change again
+func (s State) Final() bool {
+	return s == StateSucceeded || s == StateFailed || s == StateCanceled
+}
+
+// Progress reports how far a job has got. Fields that do not apply to a job stay zero.
+type Progress struct {
+	RowsRead    int64 `json:"rows_read"`
+	RowsWritten int64 `json:"rows_written"`
+	BytesRead   int64 `json:"bytes_read"`
+	// TotalBytes is the number of bytes the job will read, or zero when unknown
+	TotalBytes int64 `json:"total_bytes"`
+}
+
+// Transition records a job entering a state
+type Transition struct {
+	State   State     `json:"state"`
+	At      time.Time `json:"at"`
+	Message string    `json:"message,omitempty"`
+}
+
+// Job describes a submitted job
+type Job struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	// Params describe what the job does, such as the file and table of an import
+	Params      map[string]string      `json:"params,omitempty"`
+	State       State                  `json:"state"`
+	Progress    Progress               `json:"progress"`
+	Result      map[string]interface{} `json:"result,omitempty"`
+	Error       string                 `json:"error,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+	StartedAt   *time.Time             `json:"started_at,omitempty"`
+	FinishedAt  *time.Time             `json:"finished_at,omitempty"`
+	Transitions []Transition           `json:"transitions"`
+}
+
+// Func does the work of a job. It should return promptly once ctx is canceled and may
+// call report to publish progress.
+type Func func(ctx context.Context, report func(Progress)) (map[string]interface{}, error)
+
+// Options configures a Manager
+type Options struct {
+	// Workers is the number of jobs run at the same time
+	Workers int
+	// QueueSize is the number of jobs that may wait for a worker
+	QueueSize int
+	// HistoryPath is the JSON file the job history is persisted to; empty keeps the
+	// history in memory only. It is locked through a ".lock" file next to it.
+	HistoryPath string
+	// MaxHistory is the number of finished jobs kept in the history
+	MaxHistory int
+}
+
+// DefaultOptions returns the options used for unset fields
+func DefaultOptions() Options {
+	return Options{
+		Workers:    2,
+		QueueSize:  100,
+		MaxHistory: 1000,
+	}
+}
+
+// Manager queues jobs and runs them on a pool of workers
+type Manager struct {
+	opts Options
+
+	mu      sync.Mutex
+	jobs    map[string]*Job
+	order   []string // job IDs in submission order
+	cancels map[string]context.CancelCauseFunc
+	queue   chan queuedJob
+	closed  bool
+	lock    *os.File // holds the lock on the history file
+
+	ctx  context.Context
+	stop context.CancelCauseFunc
+	wg   sync.WaitGroup
+}
+
+type queuedJob struct {
+	id string
+	fn Func
+}
+
+// historyFile is the format of the persisted job history
+type historyFile struct {
+	Jobs []*Job `json:"jobs"`
+}
+
+// NewManager creates a Manager and starts its workers. Jobs in the history that had not
+// finished when it was last written are marked as failed, since their work was lost.
+func NewManager(opts Options) (*Manager, error) {
+	defaults := DefaultOptions()
+	if opts.Workers <= 0 {
+		opts.Workers = defaults.Workers
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaults.QueueSize
+	}
+	if opts.MaxHistory <= 0 {
+		opts.MaxHistory = defaults.MaxHistory
+	}
+
+	ctx, stop := context.WithCancelCause(context.Background())
+	m := &Manager{
+		opts:    opts,
+		jobs:    make(map[string]*Job),
+		cancels: make(map[string]context.CancelCauseFunc),
+		queue:   make(chan queuedJob, opts.QueueSize),
+		ctx:     ctx,
+		stop:    stop,
+	}
+
+	if err := m.lockHistory(); err != nil {
+		stop(nil)
+		return nil, err
+	}
+	if err := m.loadHistory(); err != nil {
+		stop(nil)
+		m.unlockHistory()
+		return nil, err
+	}
+
+	for i := 0; i < opts.Workers; i++ {
+		m.wg.Add(1)
+		go m.worker()
+	}
+	return m, nil
+}
+
+// Submit queues a job. It fails with ErrQueueFull rather than waiting for a slot.
+func (m *Manager) Submit(kind string, params map[string]string, fn Func) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return Job{}, ErrClosed
+	}
+
+	now := time.Now().UTC()
+	job := &Job{
+		ID:          uuid.NewString(),
+		Kind:        kind,
+		Params:      params,
+		State:       StateQueued,
+		CreatedAt:   now,
+		Transitions: []Transition{{State: StateQueued, At: now}},
+	}
+
+	select {
+	case m.queue <- queuedJob{id: job.ID, fn: fn}:
+	default:
+		return Job{}, ErrQueueFull
+	}
+
+	m.jobs[job.ID] = job
+	m.order = append(m.order, job.ID)
+	m.persistLocked()
+	return job.copy(), nil
+}
+
+// Get returns a job by ID
+func (m *Manager) Get(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	return job.copy(), nil
+}
+
+// List returns all jobs, most recently submitted first
+func (m *Manager) List() []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	jobs := make([]Job, 0, len(m.order))
+	for i := len(m.order) - 1; i >= 0; i-- {
+		jobs = append(jobs, m.jobs[m.order[i]].copy())
+	}
+	return jobs
+}
+
+// Cancel stops a job. Queued jobs are canceled at once; running jobs are canceled once
+// their work returns.
+func (m *Manager) Cancel(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+
+	switch {
+	case job.State.Final():
+		return job.copy(), ErrFinished
+	case job.State == StateQueued:
+		m.finishLocked(job, StateCanceled, errCanceledByRequest.Error())
+		m.persistLocked()
+	default:
+		m.cancels[id](errCanceledByRequest)
+	}
+	return job.copy(), nil
+}
+
+// Close cancels queued and running jobs, waits for the workers to stop and writes the
+// final history
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+
+	for _, id := range m.order {
+		if job := m.jobs[id]; job.State == StateQueued {
+			m.finishLocked(job, StateCanceled, errShutdown.Error())
+		}
+	}
+	m.stop(errShutdown)
+	close(m.queue)
+	m.mu.Unlock()
+
+	m.wg.Wait()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	defer m.unlockHistory()
+	return m.writeHistoryLocked()
+}
+
+func (m *Manager) worker() {
+	defer m.wg.Done()
+
+	for queued := range m.queue {
+		m.run(queued)
+	}
+}
+
+// run runs a queued job unless it was canceled while waiting
+func (m *Manager) run(queued queuedJob) {
+	m.mu.Lock()
+	job := m.jobs[queued.id]
+	if job.State != StateQueued {
+		m.mu.Unlock()
+		return
+	}
+
+	ctx, cancel := context.WithCancelCause(m.ctx)
+	defer cancel(nil)
+	m.cancels[job.ID] = cancel
+
+	now := time.Now().UTC()
+	job.State = StateRunning
+	job.StartedAt = &now
+	job.Transitions = append(job.Transitions, Transition{State: StateRunning, At: now})
+	m.persistLocked()
+	m.mu.Unlock()
+
+	report := func(progress Progress) {
+		m.mu.Lock()
+		job.Progress = progress
+		m.mu.Unlock()
+	}
+	result, err := queued.fn(ctx, report)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.cancels, job.ID)
+
+	switch {
+	case err == nil:
+		job.Result = result
+		m.finishLocked(job, StateSucceeded, "")
+	case ctx.Err() != nil:
+		m.finishLocked(job, StateCanceled, context.Cause(ctx).Error())
+	default:
+		job.Error = err.Error()
+		m.finishLocked(job, StateFailed, err.Error())
+	}
+	m.persistLocked()
+}
+
+// finishLocked moves a job to a final state
+func (m *Manager) finishLocked(job *Job, state State, message string) {
+	now := time.Now().UTC()
+	job.State = state
+	job.FinishedAt = &now
+	job.Transitions = append(job.Transitions, Transition{State: state, At: now, Message: message})
+}
+
+// persistLocked writes the history, logging rather than failing the job on errors
+func (m *Manager) persistLocked() {
+	if err := m.writeHistoryLocked(); err != nil {
+		log.Printf("Warning: failed to persist job history: %v", err)
+	}
+}
+
+// writeHistoryLocked trims the oldest finished jobs beyond MaxHistory and writes the
+// history file
+func (m *Manager) writeHistoryLocked() error {
+	finished := 0
+	for _, id := range m.order {
+		if m.jobs[id].State.Final() {
+			finished++
+		}
+	}
+	if excess := finished - m.opts.MaxHistory; excess > 0 {
+		kept := m.order[:0]
+		for _, id := range m.order {
+			if excess > 0 && m.jobs[id].State.Final() {
+				delete(m.jobs, id)
+				excess--
+				continue
+			}
+			kept = append(kept, id)
+		}
+		m.order = kept
+	}
+
+	if m.opts.HistoryPath == "" {
+		return nil
+	}
+
+	history := historyFile{Jobs: make([]*Job, 0, len(m.order))}
+	for _, id := range m.order {
+		history.Jobs = append(history.Jobs, m.jobs[id])
+	}
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize job history: %w", err)
+	}
+
+	// Write to a temporary file first so readers never see a partial history
+	if err := os.MkdirAll(filepath.Dir(m.opts.HistoryPath), 0755); err != nil {
+		return fmt.Errorf("failed to create job history directory: %w", err)
+	}
+	tmp := m.opts.HistoryPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write job history: %w", err)
+	}
+	if err := os.Rename(tmp, m.opts.HistoryPath); err != nil {
+		return fmt.Errorf("failed to write job history: %w", err)
+	}
+	return nil
+}
+
+// lockHistory locks the history file, failing with ErrHistoryLocked rather than
+// waiting when another manager holds it
+func (m *Manager) lockHistory() error {
+	if m.opts.HistoryPath == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(m.opts.HistoryPath), 0755); err != nil {
+		return fmt.Errorf("failed to create job history directory: %w", err)
+	}
+	f, err := os.OpenFile(m.opts.HistoryPath+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to lock job history: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return fmt.Errorf("%w: %s", ErrHistoryLocked, m.opts.HistoryPath)
+	}
+	m.lock = f
+	return nil
+}
+
+// unlockHistory releases the lock on the history file, which closing the lock file does
+func (m *Manager) unlockHistory() {
+	if m.lock != nil {
+		m.lock.Close()
+		m.lock = nil
+	}
+}
+
+// loadHistory reads the persisted history, if any
+func (m *Manager) loadHistory() error {
+	if m.opts.HistoryPath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(m.opts.HistoryPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read job history: %w", err)
+	}
+
+	var history historyFile
+	if err := json.Unmarshal(data, &history); err != nil {
+		return fmt.Errorf("failed to parse job history %s: %w", m.opts.HistoryPath, err)
+	}
+
+	for _, job := range history.Jobs {
+		if !job.State.Final() {
+			job.Error = "interrupted: the server stopped before the job finished"
+			m.finishLocked(job, StateFailed, job.Error)
+		}
+		m.jobs[job.ID] = job
+		m.order = append(m.order, job.ID)
+	}
+	return nil
+}
+
+// copy returns a copy of the job that is safe to use without holding the lock
+func (j *Job) copy() Job {
+	c := *j
+	c.Transitions = append([]Transition(nil), j.Transitions...)
+	return c
+}
@@ -0,0 +1,222 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForState polls a job until it reaches state
+func waitForState(t *testing.T, m *Manager, id string, state State) Job {
+	t.Helper()
+
+	var job Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = m.Get(id)
+		require.NoError(t, err)
+		return job.State == state
+	}, 5*time.Second, 5*time.Millisecond, "job %s never reached %s", id, state)
+	return job
+}
+
+func transitionStates(job Job) []State {
+	var states []State
+	for _, transition := range job.Transitions {
+		states = append(states, transition.State)
+	}
+	return states
+}
+
+func TestJobLifecycle(t *testing.T) {
+	m, err := NewManager(Options{Workers: 1})
+	require.NoError(t, err)
+	defer m.Close()
+
+	job, err := m.Submit("import", map[string]string{"table": "sales"}, func(ctx context.Context, report func(Progress)) (map[string]interface{}, error) {
+		report(Progress{RowsRead: 10, BytesRead: 100, TotalBytes: 100})
+		return map[string]interface{}{"record_count": 10}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, StateQueued, job.State)
+	assert.NotEmpty(t, job.ID)
+
+	job = waitForState(t, m, job.ID, StateSucceeded)
+	assert.Equal(t, []State{StateQueued, StateRunning, StateSucceeded}, transitionStates(job))
+	assert.Equal(t, int64(10), job.Progress.RowsRead)
+	assert.Equal(t, 10, job.Result["record_count"])
+	assert.NotNil(t, job.StartedAt)
+	assert.NotNil(t, job.FinishedAt)
+
+	failed, err := m.Submit("import", nil, func(ctx context.Context, report func(Progress)) (map[string]interface{}, error) {
+		return nil, errors.New("bad file")
+	})
+	require.NoError(t, err)
+	failed = waitForState(t, m, failed.ID, StateFailed)
+	assert.Equal(t, "bad file", failed.Error)
+
+	jobs := m.List()
+	require.Len(t, jobs, 2)
+	assert.Equal(t, failed.ID, jobs[0].ID)
+
+	_, err = m.Get("missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestCancel(t *testing.T) {
+	m, err := NewManager(Options{Workers: 1})
+	require.NoError(t, err)
+	defer m.Close()
+
+	started := make(chan struct{})
+	running, err := m.Submit("import", nil, func(ctx context.Context, report func(Progress)) (map[string]interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	require.NoError(t, err)
+	<-started
+
+	// The only worker is busy, so this job waits in the queue
+	queued, err := m.Submit("import", nil, func(ctx context.Context, report func(Progress)) (map[string]interface{}, error) {
+		t.Error("canceled job must not run")
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	queuedJob, err := m.Cancel(queued.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StateCanceled, queuedJob.State)
+
+	_, err = m.Cancel(running.ID)
+	require.NoError(t, err)
+	job := waitForState(t, m, running.ID, StateCanceled)
+	assert.Equal(t, []State{StateQueued, StateRunning, StateCanceled}, transitionStates(job))
+	assert.Equal(t, "canceled by request", job.Transitions[2].Message)
+
+	_, err = m.Cancel(running.ID)
+	assert.ErrorIs(t, err, ErrFinished)
+}
+
+func TestQueueFull(t *testing.T) {
+	m, err := NewManager(Options{Workers: 1, QueueSize: 1})
+	require.NoError(t, err)
+	defer m.Close()
+
+	release := make(chan struct{})
+	defer close(release)
+	block := func(ctx context.Context, report func(Progress)) (map[string]interface{}, error) {
+		<-release
+		return nil, nil
+	}
+
+	first, err := m.Submit("import", nil, block)
+	require.NoError(t, err)
+	waitForState(t, m, first.ID, StateRunning)
+
+	_, err = m.Submit("import", nil, block)
+	require.NoError(t, err)
+	_, err = m.Submit("import", nil, block)
+	assert.ErrorIs(t, err, ErrQueueFull)
+}
+
+func TestPersistedHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".icebox", "jobs.json")
+
+	m, err := NewManager(Options{Workers: 1, HistoryPath: path})
+	require.NoError(t, err)
+
+	done, err := m.Submit("import", map[string]string{"file": "a.parquet"}, func(ctx context.Context, report func(Progress)) (map[string]interface{}, error) {
+		return map[string]interface{}{"record_count": 3}, nil
+	})
+	require.NoError(t, err)
+	waitForState(t, m, done.ID, StateSucceeded)
+
+	started := make(chan struct{})
+	interrupted, err := m.Submit("import", nil, func(ctx context.Context, report func(Progress)) (map[string]interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	require.NoError(t, err)
+	<-started
+	require.NoError(t, m.Close())
+
+	reopened, err := NewManager(Options{Workers: 1, HistoryPath: path})
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	job, err := reopened.Get(done.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StateSucceeded, job.State)
+	assert.Equal(t, "a.parquet", job.Params["file"])
+	assert.Equal(t, float64(3), job.Result["record_count"])
+
+	job, err = reopened.Get(interrupted.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StateCanceled, job.State)
+	assert.Equal(t, "canceled by server shutdown", job.Transitions[len(job.Transitions)-1].Message)
+}
+
+func TestInterruptedJobsFail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.json")
+	history := `{"jobs": [{"id": "abc", "kind": "import", "state": "running", "created_at": "2025-01-01T00:00:00Z",
+		"transitions": [{"state": "queued", "at": "2025-01-01T00:00:00Z"}, {"state": "running", "at": "2025-01-01T00:00:01Z"}]}]}`
+	require.NoError(t, os.WriteFile(path, []byte(history), 0644))
+
+	m, err := NewManager(Options{HistoryPath: path})
+	require.NoError(t, err)
+	defer m.Close()
+
+	job, err := m.Get("abc")
+	require.NoError(t, err)
+	assert.Equal(t, StateFailed, job.State)
+	assert.Contains(t, job.Error, "interrupted")
+	assert.Equal(t, []State{StateQueued, StateRunning, StateFailed}, transitionStates(job))
+}
+
+func TestHistoryLocked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.json")
+
+	m, err := NewManager(Options{HistoryPath: path})
+	require.NoError(t, err)
+
+	// A second manager would overwrite the first one's history
+	_, err = NewManager(Options{HistoryPath: path})
+	assert.ErrorIs(t, err, ErrHistoryLocked)
+
+	other, err := NewManager(Options{HistoryPath: filepath.Join(t.TempDir(), "jobs.json")})
+	require.NoError(t, err)
+	require.NoError(t, other.Close())
+
+	require.NoError(t, m.Close())
+	reopened, err := NewManager(Options{HistoryPath: path})
+	require.NoError(t, err)
+	require.NoError(t, reopened.Close())
+}
+
+func TestHistoryLimit(t *testing.T) {
+	m, err := NewManager(Options{Workers: 1, MaxHistory: 2})
+	require.NoError(t, err)
+	defer m.Close()
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		job, err := m.Submit("import", nil, func(ctx context.Context, report func(Progress)) (map[string]interface{}, error) {
+			return nil, nil
+		})
+		require.NoError(t, err)
+		waitForState(t, m, job.ID, StateSucceeded)
+		ids = append(ids, job.ID)
+	}
+
+	_, err = m.Get(ids[0])
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Len(t, m.List(), 2)
+}
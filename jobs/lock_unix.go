@@ -0,0 +1,15 @@
+//go:build unix
+
+package jobs
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// lockFile takes an exclusive lock on f, failing rather than waiting when another open
+// file holds it. The lock is released when f is closed.
+func lockFile(f *os.File) error {
+	return unix.Flock(int(f.Fd()), unix.LOCK_EX|unix.LOCK_NB)
+}
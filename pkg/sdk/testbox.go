@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	stdio "io"
 	"os"
 	"path/filepath"
 	"testing"
@@ -13,8 +12,9 @@ import (
 	"github.com/TFMV/icebox/config"
 	"github.com/TFMV/icebox/engine/duckdb"
 	"github.com/TFMV/icebox/fs/memory"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/iceberg-go"
-	icebergio "github.com/apache/iceberg-go/io"
 	"github.com/apache/iceberg-go/table"
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -110,14 +110,8 @@ func NewTestBox(t *testing.T, opts ...TestBoxOption) *TestBox {
 			t.Fatalf("Failed to create in-memory database: %v", err)
 		}
 
-		// Create adapter for memory filesystem
-		memoryAdapter := &memoryFileSystemAdapter{memFS: testBox.memoryFS}
-
-		// Create memory IO for iceberg-go
-		memoryIOAdapter := &memoryIO{memFS: testBox.memoryFS}
-
-		// Create catalog with memory filesystem
-		catalog, err = sqlite.NewCatalogWithIO(cfg.Name, ":memory:", db, memoryAdapter, memoryIOAdapter, "/test")
+		// The memory filesystem serves both as the catalog's filesystem and as iceberg-go's IO
+		catalog, err = sqlite.NewCatalogWithIO(cfg.Name, ":memory:", db, testBox.memoryFS, testBox.memoryFS, "/test")
 		if err != nil {
 			testBox.cleanupAll()
 			t.Fatalf("Failed to create memory catalog: %v", err)
@@ -300,6 +294,50 @@ func (tb *TestBox) MustExecuteSQL(query string) *duckdb.QueryResult {
 	return result
 }
 
+// QueryTable executes a SQL query and returns its results as an Arrow table, failing
+// the test on error
+func (tb *TestBox) QueryTable(query string) arrow.Table {
+	tb.t.Helper()
+
+	return tb.MustExecuteSQL(query).Table
+}
+
+// AssertQueryResult executes a SQL query and fails the test unless its results equal
+// the expected Arrow table. Column names, types and values are compared; nullability,
+// metadata and how rows are split into chunks are not.
+func (tb *TestBox) AssertQueryResult(query string, expected arrow.Table) {
+	tb.t.Helper()
+
+	if diff := tableDiff(expected, tb.QueryTable(query)); diff != "" {
+		tb.t.Errorf("Unexpected results for SQL query '%s': %s", query, diff)
+	}
+}
+
+// tableDiff describes the first difference between two Arrow tables, or returns an
+// empty string if they hold the same columns and rows
+func tableDiff(expected, actual arrow.Table) string {
+	if expected.NumCols() != actual.NumCols() {
+		return fmt.Sprintf("expected %d columns, got %d", expected.NumCols(), actual.NumCols())
+	}
+	if expected.NumRows() != actual.NumRows() {
+		return fmt.Sprintf("expected %d rows, got %d", expected.NumRows(), actual.NumRows())
+	}
+
+	for i := 0; i < int(expected.NumCols()); i++ {
+		want, got := expected.Schema().Field(i), actual.Schema().Field(i)
+		if want.Name != got.Name {
+			return fmt.Sprintf("expected column %d to be named %q, got %q", i, want.Name, got.Name)
+		}
+		if !arrow.TypeEqual(want.Type, got.Type) {
+			return fmt.Sprintf("expected column %s to be of type %s, got %s", want.Name, want.Type, got.Type)
+		}
+		if !array.ChunkedEqual(expected.Column(i).Data(), actual.Column(i).Data()) {
+			return fmt.Sprintf("column %s differs:\nexpected: %v\nactual:   %v", want.Name, expected.Column(i).Data().Chunks(), actual.Column(i).Data().Chunks())
+		}
+	}
+	return ""
+}
+
 // RegisterTable registers a table with the SQL engine for querying
 func (tb *TestBox) RegisterTable(icebergTable *table.Table) {
 	tb.t.Helper()
@@ -393,51 +431,3 @@ const (
 	LocalDefaults
 	FastDefaults
 )
-
-// memoryFileSystemAdapter adapts memory filesystem to match FileSystemInterface
-type memoryFileSystemAdapter struct {
-	memFS *memory.MemoryFileSystem
-}
-
-func (m *memoryFileSystemAdapter) Create(path string) (stdio.WriteCloser, error) {
-	// For now, just use the memory filesystem's WriteFile method
-	// This is a simplified adapter that creates a temporary writer
-	return &memoryFileWriter{
-		adapter: m,
-		path:    path,
-		buffer:  make([]byte, 0),
-	}, nil
-}
-
-// memoryFileWriter implements io.WriteCloser for the memory filesystem
-type memoryFileWriter struct {
-	adapter *memoryFileSystemAdapter
-	path    string
-	buffer  []byte
-}
-
-func (w *memoryFileWriter) Write(p []byte) (n int, err error) {
-	w.buffer = append(w.buffer, p...)
-	return len(p), nil
-}
-
-func (w *memoryFileWriter) Close() error {
-	return w.adapter.memFS.WriteFile(w.path, w.buffer)
-}
-
-// memoryIO implements iceberg-go io.IO interface for memory filesystem
-type memoryIO struct {
-	memFS *memory.MemoryFileSystem
-}
-
-func (m *memoryIO) Open(path string) (icebergio.File, error) {
-	return m.memFS.Open(path)
-}
-
-func (m *memoryIO) Create(path string) (icebergio.File, error) {
-	return m.memFS.Create(path)
-}
-
-func (m *memoryIO) Remove(path string) error {
-	return m.memFS.Remove(path)
-}
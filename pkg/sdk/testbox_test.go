@@ -4,6 +4,9 @@ import (
 	"os"
 	"testing"
 
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/apache/iceberg-go"
 	"github.com/apache/iceberg-go/table"
 	"github.com/stretchr/testify/assert"
@@ -102,13 +105,13 @@ func TestExecuteSQL(t *testing.T) {
 	assert.Equal(t, int64(1), result.RowCount) // COUNT(*) returns 1 row
 
 	// Check if this is a placeholder table (when Iceberg extension is not available)
-	if len(result.Rows) > 0 && len(result.Rows[0]) > 0 {
-		count := result.Rows[0][0]
+	if len(result.Rows()) > 0 && len(result.Rows()[0]) > 0 {
+		count := result.Rows()[0][0]
 
 		// First check if this might be a placeholder table by querying the table directly
 		directResult := testBox.MustExecuteSQL("SELECT * FROM test_ns_test_table")
-		if len(directResult.Rows) > 0 && len(directResult.Rows[0]) > 0 {
-			if firstCol, ok := directResult.Rows[0][0].(string); ok &&
+		if len(directResult.Rows()) > 0 && len(directResult.Rows()[0]) > 0 {
+			if firstCol, ok := directResult.Rows()[0][0].(string); ok &&
 				firstCol == "Iceberg extension not available on this platform" {
 				// This is a placeholder table, so COUNT(*) will return 1 (the placeholder row)
 				assert.Equal(t, int64(1), count) // Placeholder table has 1 row
@@ -129,6 +132,42 @@ func TestExecuteSQLWithError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestAssertQueryResult(t *testing.T) {
+	testBox := NewTestBox(t)
+
+	// Decimals keep their precision and nested values their types
+	expected := testBox.QueryTable("SELECT * FROM (VALUES (1, 12.50::DECIMAL(10,2), [1, 2])) t(id, amount, tags)")
+	assert.Equal(t, int64(1), expected.NumRows())
+	amount, ok := expected.Schema().Field(1).Type.(arrow.DecimalType)
+	require.True(t, ok, "amount is %s", expected.Schema().Field(1).Type)
+	assert.Equal(t, int32(2), amount.GetScale())
+
+	testBox.AssertQueryResult("SELECT 1 AS id, 12.5::DECIMAL(10,2) AS amount, [1, 2] AS tags", expected)
+}
+
+func TestTableDiff(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	newTable := func(chunks ...[]int64) arrow.Table {
+		builder := array.NewInt64Builder(memory.DefaultAllocator)
+		defer builder.Release()
+		var arrays []arrow.Array
+		for _, values := range chunks {
+			builder.AppendValues(values, nil)
+			arrays = append(arrays, builder.NewArray())
+		}
+		return array.NewTableFromSlice(schema, [][]arrow.Array{arrays})
+	}
+
+	// Chunking does not matter
+	assert.Empty(t, tableDiff(newTable([]int64{1, 2, 3}), newTable([]int64{1}, []int64{2, 3})))
+	assert.Contains(t, tableDiff(newTable([]int64{1, 2}), newTable([]int64{1, 2, 3})), "expected 2 rows, got 3")
+	assert.Contains(t, tableDiff(newTable([]int64{1, 2}), newTable([]int64{1, 3})), "column id differs")
+
+	renamed := arrow.NewSchema([]arrow.Field{{Name: "key", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	other := array.NewTableFromSlice(renamed, [][]arrow.Array{newTable([]int64{1}).Column(0).Data().Chunks()})
+	assert.Contains(t, tableDiff(newTable([]int64{1}), other), `named "id", got "key"`)
+}
+
 func TestRegisterTable(t *testing.T) {
 	// Use file system instead of memory filesystem for DuckDB compatibility
 	testBox := NewTestBox(t, WithFileSystem())
@@ -0,0 +1,5 @@
+package iceboxv1
+
+// icebox.pb.go and icebox_grpc.pb.go are generated from icebox.proto with the plugin
+// versions pinned in proto/buf.gen.yaml. Regenerate them with `go generate ./proto/...`.
+//go:generate sh -c "cd ../.. && go run github.com/bufbuild/buf/cmd/buf@v1.50.0 generate"
@@ -0,0 +1,2163 @@
+// Services of `icebox serve --mode grpc`. Schemas, partition specs and sort orders
+// use the JSON formats of the Apache Iceberg table spec; query results are Arrow IPC.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: icebox/v1/icebox.proto
+
+package iceboxv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// JobState is the stage of an import job's life.
+type JobState int32
+
+const (
+	JobState_JOB_STATE_UNSPECIFIED JobState = 0
+	JobState_JOB_STATE_QUEUED      JobState = 1
+	JobState_JOB_STATE_RUNNING     JobState = 2
+	JobState_JOB_STATE_SUCCEEDED   JobState = 3
+	JobState_JOB_STATE_FAILED      JobState = 4
+	JobState_JOB_STATE_CANCELED    JobState = 5
+)
+
+// Enum value maps for JobState.
+var (
+	JobState_name = map[int32]string{
+		0: "JOB_STATE_UNSPECIFIED",
+		1: "JOB_STATE_QUEUED",
+		2: "JOB_STATE_RUNNING",
+		3: "JOB_STATE_SUCCEEDED",
+		4: "JOB_STATE_FAILED",
+		5: "JOB_STATE_CANCELED",
+	}
+	JobState_value = map[string]int32{
+		"JOB_STATE_UNSPECIFIED": 0,
+		"JOB_STATE_QUEUED":      1,
+		"JOB_STATE_RUNNING":     2,
+		"JOB_STATE_SUCCEEDED":   3,
+		"JOB_STATE_FAILED":      4,
+		"JOB_STATE_CANCELED":    5,
+	}
+)
+
+func (x JobState) Enum() *JobState {
+	p := new(JobState)
+	*p = x
+	return p
+}
+
+func (x JobState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JobState) Descriptor() protoreflect.EnumDescriptor {
+	return file_icebox_v1_icebox_proto_enumTypes[0].Descriptor()
+}
+
+func (JobState) Type() protoreflect.EnumType {
+	return &file_icebox_v1_icebox_proto_enumTypes[0]
+}
+
+func (x JobState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JobState.Descriptor instead.
+func (JobState) EnumDescriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{0}
+}
+
+// TableIdentifier names a table by its namespace levels and name.
+type TableIdentifier struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     []string               `protobuf:"bytes,1,rep,name=namespace,proto3" json:"namespace,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TableIdentifier) Reset() {
+	*x = TableIdentifier{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TableIdentifier) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TableIdentifier) ProtoMessage() {}
+
+func (x *TableIdentifier) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TableIdentifier.ProtoReflect.Descriptor instead.
+func (*TableIdentifier) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TableIdentifier) GetNamespace() []string {
+	if x != nil {
+		return x.Namespace
+	}
+	return nil
+}
+
+func (x *TableIdentifier) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Namespace is a namespace and its properties.
+type Namespace struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Levels of the namespace, such as ["analytics", "events"].
+	Levels        []string          `protobuf:"bytes,1,rep,name=levels,proto3" json:"levels,omitempty"`
+	Properties    map[string]string `protobuf:"bytes,2,rep,name=properties,proto3" json:"properties,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Namespace) Reset() {
+	*x = Namespace{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Namespace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Namespace) ProtoMessage() {}
+
+func (x *Namespace) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Namespace.ProtoReflect.Descriptor instead.
+func (*Namespace) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Namespace) GetLevels() []string {
+	if x != nil {
+		return x.Levels
+	}
+	return nil
+}
+
+func (x *Namespace) GetProperties() map[string]string {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+type ListNamespacesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Parent namespace levels; empty lists the top-level namespaces.
+	Parent        []string `protobuf:"bytes,1,rep,name=parent,proto3" json:"parent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNamespacesRequest) Reset() {
+	*x = ListNamespacesRequest{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNamespacesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNamespacesRequest) ProtoMessage() {}
+
+func (x *ListNamespacesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNamespacesRequest.ProtoReflect.Descriptor instead.
+func (*ListNamespacesRequest) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListNamespacesRequest) GetParent() []string {
+	if x != nil {
+		return x.Parent
+	}
+	return nil
+}
+
+type ListNamespacesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespaces    []*Namespace           `protobuf:"bytes,1,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNamespacesResponse) Reset() {
+	*x = ListNamespacesResponse{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNamespacesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNamespacesResponse) ProtoMessage() {}
+
+func (x *ListNamespacesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNamespacesResponse.ProtoReflect.Descriptor instead.
+func (*ListNamespacesResponse) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListNamespacesResponse) GetNamespaces() []*Namespace {
+	if x != nil {
+		return x.Namespaces
+	}
+	return nil
+}
+
+type CreateNamespaceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Levels        []string               `protobuf:"bytes,1,rep,name=levels,proto3" json:"levels,omitempty"`
+	Properties    map[string]string      `protobuf:"bytes,2,rep,name=properties,proto3" json:"properties,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateNamespaceRequest) Reset() {
+	*x = CreateNamespaceRequest{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateNamespaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateNamespaceRequest) ProtoMessage() {}
+
+func (x *CreateNamespaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateNamespaceRequest.ProtoReflect.Descriptor instead.
+func (*CreateNamespaceRequest) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateNamespaceRequest) GetLevels() []string {
+	if x != nil {
+		return x.Levels
+	}
+	return nil
+}
+
+func (x *CreateNamespaceRequest) GetProperties() map[string]string {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+type GetNamespaceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Levels        []string               `protobuf:"bytes,1,rep,name=levels,proto3" json:"levels,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNamespaceRequest) Reset() {
+	*x = GetNamespaceRequest{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNamespaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNamespaceRequest) ProtoMessage() {}
+
+func (x *GetNamespaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNamespaceRequest.ProtoReflect.Descriptor instead.
+func (*GetNamespaceRequest) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetNamespaceRequest) GetLevels() []string {
+	if x != nil {
+		return x.Levels
+	}
+	return nil
+}
+
+type DropNamespaceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Levels        []string               `protobuf:"bytes,1,rep,name=levels,proto3" json:"levels,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DropNamespaceRequest) Reset() {
+	*x = DropNamespaceRequest{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DropNamespaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropNamespaceRequest) ProtoMessage() {}
+
+func (x *DropNamespaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropNamespaceRequest.ProtoReflect.Descriptor instead.
+func (*DropNamespaceRequest) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DropNamespaceRequest) GetLevels() []string {
+	if x != nil {
+		return x.Levels
+	}
+	return nil
+}
+
+type DropNamespaceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DropNamespaceResponse) Reset() {
+	*x = DropNamespaceResponse{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DropNamespaceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropNamespaceResponse) ProtoMessage() {}
+
+func (x *DropNamespaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropNamespaceResponse.ProtoReflect.Descriptor instead.
+func (*DropNamespaceResponse) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{7}
+}
+
+type ListTablesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     []string               `protobuf:"bytes,1,rep,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTablesRequest) Reset() {
+	*x = ListTablesRequest{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTablesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTablesRequest) ProtoMessage() {}
+
+func (x *ListTablesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTablesRequest.ProtoReflect.Descriptor instead.
+func (*ListTablesRequest) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListTablesRequest) GetNamespace() []string {
+	if x != nil {
+		return x.Namespace
+	}
+	return nil
+}
+
+type ListTablesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tables        []*TableIdentifier     `protobuf:"bytes,1,rep,name=tables,proto3" json:"tables,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTablesResponse) Reset() {
+	*x = ListTablesResponse{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTablesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTablesResponse) ProtoMessage() {}
+
+func (x *ListTablesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTablesResponse.ProtoReflect.Descriptor instead.
+func (*ListTablesResponse) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListTablesResponse) GetTables() []*TableIdentifier {
+	if x != nil {
+		return x.Tables
+	}
+	return nil
+}
+
+type CreateTableRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Identifier *TableIdentifier       `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	// Iceberg schema as JSON.
+	SchemaJson string `protobuf:"bytes,2,opt,name=schema_json,json=schemaJson,proto3" json:"schema_json,omitempty"`
+	// Iceberg partition spec as JSON; empty creates an unpartitioned table.
+	PartitionSpecJson string `protobuf:"bytes,3,opt,name=partition_spec_json,json=partitionSpecJson,proto3" json:"partition_spec_json,omitempty"`
+	// Table location; empty uses the warehouse default.
+	Location      string            `protobuf:"bytes,4,opt,name=location,proto3" json:"location,omitempty"`
+	Properties    map[string]string `protobuf:"bytes,5,rep,name=properties,proto3" json:"properties,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTableRequest) Reset() {
+	*x = CreateTableRequest{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTableRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTableRequest) ProtoMessage() {}
+
+func (x *CreateTableRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTableRequest.ProtoReflect.Descriptor instead.
+func (*CreateTableRequest) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateTableRequest) GetIdentifier() *TableIdentifier {
+	if x != nil {
+		return x.Identifier
+	}
+	return nil
+}
+
+func (x *CreateTableRequest) GetSchemaJson() string {
+	if x != nil {
+		return x.SchemaJson
+	}
+	return ""
+}
+
+func (x *CreateTableRequest) GetPartitionSpecJson() string {
+	if x != nil {
+		return x.PartitionSpecJson
+	}
+	return ""
+}
+
+func (x *CreateTableRequest) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *CreateTableRequest) GetProperties() map[string]string {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+type LoadTableRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Identifier    *TableIdentifier       `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoadTableRequest) Reset() {
+	*x = LoadTableRequest{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadTableRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadTableRequest) ProtoMessage() {}
+
+func (x *LoadTableRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadTableRequest.ProtoReflect.Descriptor instead.
+func (*LoadTableRequest) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *LoadTableRequest) GetIdentifier() *TableIdentifier {
+	if x != nil {
+		return x.Identifier
+	}
+	return nil
+}
+
+type DropTableRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Identifier    *TableIdentifier       `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DropTableRequest) Reset() {
+	*x = DropTableRequest{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DropTableRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropTableRequest) ProtoMessage() {}
+
+func (x *DropTableRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropTableRequest.ProtoReflect.Descriptor instead.
+func (*DropTableRequest) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DropTableRequest) GetIdentifier() *TableIdentifier {
+	if x != nil {
+		return x.Identifier
+	}
+	return nil
+}
+
+type DropTableResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DropTableResponse) Reset() {
+	*x = DropTableResponse{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DropTableResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropTableResponse) ProtoMessage() {}
+
+func (x *DropTableResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropTableResponse.ProtoReflect.Descriptor instead.
+func (*DropTableResponse) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{13}
+}
+
+// Table is the current metadata of a table.
+type Table struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Identifier       *TableIdentifier       `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	Location         string                 `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	MetadataLocation string                 `protobuf:"bytes,3,opt,name=metadata_location,json=metadataLocation,proto3" json:"metadata_location,omitempty"`
+	FormatVersion    int32                  `protobuf:"varint,4,opt,name=format_version,json=formatVersion,proto3" json:"format_version,omitempty"`
+	// Current Iceberg schema as JSON.
+	SchemaJson string `protobuf:"bytes,5,opt,name=schema_json,json=schemaJson,proto3" json:"schema_json,omitempty"`
+	// Default Iceberg partition spec as JSON.
+	PartitionSpecJson string `protobuf:"bytes,6,opt,name=partition_spec_json,json=partitionSpecJson,proto3" json:"partition_spec_json,omitempty"`
+	// Default Iceberg sort order as JSON.
+	SortOrderJson string `protobuf:"bytes,7,opt,name=sort_order_json,json=sortOrderJson,proto3" json:"sort_order_json,omitempty"`
+	// Zero when the table has no snapshots.
+	CurrentSnapshotId int64             `protobuf:"varint,8,opt,name=current_snapshot_id,json=currentSnapshotId,proto3" json:"current_snapshot_id,omitempty"`
+	Properties        map[string]string `protobuf:"bytes,9,rep,name=properties,proto3" json:"properties,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Table) Reset() {
+	*x = Table{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Table) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Table) ProtoMessage() {}
+
+func (x *Table) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Table.ProtoReflect.Descriptor instead.
+func (*Table) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Table) GetIdentifier() *TableIdentifier {
+	if x != nil {
+		return x.Identifier
+	}
+	return nil
+}
+
+func (x *Table) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *Table) GetMetadataLocation() string {
+	if x != nil {
+		return x.MetadataLocation
+	}
+	return ""
+}
+
+func (x *Table) GetFormatVersion() int32 {
+	if x != nil {
+		return x.FormatVersion
+	}
+	return 0
+}
+
+func (x *Table) GetSchemaJson() string {
+	if x != nil {
+		return x.SchemaJson
+	}
+	return ""
+}
+
+func (x *Table) GetPartitionSpecJson() string {
+	if x != nil {
+		return x.PartitionSpecJson
+	}
+	return ""
+}
+
+func (x *Table) GetSortOrderJson() string {
+	if x != nil {
+		return x.SortOrderJson
+	}
+	return ""
+}
+
+func (x *Table) GetCurrentSnapshotId() int64 {
+	if x != nil {
+		return x.CurrentSnapshotId
+	}
+	return 0
+}
+
+func (x *Table) GetProperties() map[string]string {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+type ExecuteQueryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Sql   string                 `protobuf:"bytes,1,opt,name=sql,proto3" json:"sql,omitempty"`
+	// Number of rows per record batch; zero uses the server default.
+	BatchSize     int32 `protobuf:"varint,2,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteQueryRequest) Reset() {
+	*x = ExecuteQueryRequest{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteQueryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteQueryRequest) ProtoMessage() {}
+
+func (x *ExecuteQueryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteQueryRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteQueryRequest) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ExecuteQueryRequest) GetSql() string {
+	if x != nil {
+		return x.Sql
+	}
+	return ""
+}
+
+func (x *ExecuteQueryRequest) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+// QueryResultChunk carries part of a query result. The arrow_ipc fields of a stream's
+// chunks, concatenated in order, form one Arrow IPC stream: the first chunk starts with
+// the schema and every chunk holds whole IPC messages.
+type QueryResultChunk struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ArrowIpc []byte                 `protobuf:"bytes,1,opt,name=arrow_ipc,json=arrowIpc,proto3" json:"arrow_ipc,omitempty"`
+	// Rows in the record batches of this chunk.
+	RowCount      int64 `protobuf:"varint,2,opt,name=row_count,json=rowCount,proto3" json:"row_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryResultChunk) Reset() {
+	*x = QueryResultChunk{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryResultChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryResultChunk) ProtoMessage() {}
+
+func (x *QueryResultChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryResultChunk.ProtoReflect.Descriptor instead.
+func (*QueryResultChunk) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *QueryResultChunk) GetArrowIpc() []byte {
+	if x != nil {
+		return x.ArrowIpc
+	}
+	return nil
+}
+
+func (x *QueryResultChunk) GetRowCount() int64 {
+	if x != nil {
+		return x.RowCount
+	}
+	return 0
+}
+
+type ImportFileRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Local path or s3:// or https:// URL of a Parquet or Avro file.
+	FilePath      string           `protobuf:"bytes,1,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
+	Table         *TableIdentifier `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"`
+	Overwrite     bool             `protobuf:"varint,3,opt,name=overwrite,proto3" json:"overwrite,omitempty"`
+	PartitionBy   []string         `protobuf:"bytes,4,rep,name=partition_by,json=partitionBy,proto3" json:"partition_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportFileRequest) Reset() {
+	*x = ImportFileRequest{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportFileRequest) ProtoMessage() {}
+
+func (x *ImportFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportFileRequest.ProtoReflect.Descriptor instead.
+func (*ImportFileRequest) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ImportFileRequest) GetFilePath() string {
+	if x != nil {
+		return x.FilePath
+	}
+	return ""
+}
+
+func (x *ImportFileRequest) GetTable() *TableIdentifier {
+	if x != nil {
+		return x.Table
+	}
+	return nil
+}
+
+func (x *ImportFileRequest) GetOverwrite() bool {
+	if x != nil {
+		return x.Overwrite
+	}
+	return false
+}
+
+func (x *ImportFileRequest) GetPartitionBy() []string {
+	if x != nil {
+		return x.PartitionBy
+	}
+	return nil
+}
+
+type GetImportJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetImportJobRequest) Reset() {
+	*x = GetImportJobRequest{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetImportJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetImportJobRequest) ProtoMessage() {}
+
+func (x *GetImportJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetImportJobRequest.ProtoReflect.Descriptor instead.
+func (*GetImportJobRequest) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetImportJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type ListImportJobsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListImportJobsRequest) Reset() {
+	*x = ListImportJobsRequest{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListImportJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListImportJobsRequest) ProtoMessage() {}
+
+func (x *ListImportJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListImportJobsRequest.ProtoReflect.Descriptor instead.
+func (*ListImportJobsRequest) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{19}
+}
+
+type ListImportJobsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Jobs          []*ImportJob           `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListImportJobsResponse) Reset() {
+	*x = ListImportJobsResponse{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListImportJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListImportJobsResponse) ProtoMessage() {}
+
+func (x *ListImportJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListImportJobsResponse.ProtoReflect.Descriptor instead.
+func (*ListImportJobsResponse) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListImportJobsResponse) GetJobs() []*ImportJob {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+type CancelImportJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelImportJobRequest) Reset() {
+	*x = CancelImportJobRequest{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelImportJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelImportJobRequest) ProtoMessage() {}
+
+func (x *CancelImportJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelImportJobRequest.ProtoReflect.Descriptor instead.
+func (*CancelImportJobRequest) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *CancelImportJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+// ImportProgress reports how far an import has got.
+type ImportProgress struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	RowsRead    int64                  `protobuf:"varint,1,opt,name=rows_read,json=rowsRead,proto3" json:"rows_read,omitempty"`
+	RowsWritten int64                  `protobuf:"varint,2,opt,name=rows_written,json=rowsWritten,proto3" json:"rows_written,omitempty"`
+	BytesRead   int64                  `protobuf:"varint,3,opt,name=bytes_read,json=bytesRead,proto3" json:"bytes_read,omitempty"`
+	// Size of the source file, or zero when unknown.
+	TotalBytes    int64 `protobuf:"varint,4,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportProgress) Reset() {
+	*x = ImportProgress{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportProgress) ProtoMessage() {}
+
+func (x *ImportProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportProgress.ProtoReflect.Descriptor instead.
+func (*ImportProgress) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ImportProgress) GetRowsRead() int64 {
+	if x != nil {
+		return x.RowsRead
+	}
+	return 0
+}
+
+func (x *ImportProgress) GetRowsWritten() int64 {
+	if x != nil {
+		return x.RowsWritten
+	}
+	return 0
+}
+
+func (x *ImportProgress) GetBytesRead() int64 {
+	if x != nil {
+		return x.BytesRead
+	}
+	return 0
+}
+
+func (x *ImportProgress) GetTotalBytes() int64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
+// JobTransition records a job entering a state.
+type JobTransition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         JobState               `protobuf:"varint,1,opt,name=state,proto3,enum=icebox.v1.JobState" json:"state,omitempty"`
+	At            *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=at,proto3" json:"at,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobTransition) Reset() {
+	*x = JobTransition{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobTransition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobTransition) ProtoMessage() {}
+
+func (x *JobTransition) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobTransition.ProtoReflect.Descriptor instead.
+func (*JobTransition) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *JobTransition) GetState() JobState {
+	if x != nil {
+		return x.State
+	}
+	return JobState_JOB_STATE_UNSPECIFIED
+}
+
+func (x *JobTransition) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+func (x *JobTransition) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// ImportJob describes an import job, as reported by the REST API's job endpoints.
+type ImportJob struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	State JobState               `protobuf:"varint,2,opt,name=state,proto3,enum=icebox.v1.JobState" json:"state,omitempty"`
+	// The file, table, format and overwrite flag of the import.
+	Params   map[string]string `protobuf:"bytes,3,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Progress *ImportProgress   `protobuf:"bytes,4,opt,name=progress,proto3" json:"progress,omitempty"`
+	// Imported table, row count and schema, once the import has succeeded.
+	Result        *structpb.Struct       `protobuf:"bytes,5,opt,name=result,proto3" json:"result,omitempty"`
+	Error         string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt    *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	Transitions   []*JobTransition       `protobuf:"bytes,10,rep,name=transitions,proto3" json:"transitions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportJob) Reset() {
+	*x = ImportJob{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportJob) ProtoMessage() {}
+
+func (x *ImportJob) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportJob.ProtoReflect.Descriptor instead.
+func (*ImportJob) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ImportJob) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ImportJob) GetState() JobState {
+	if x != nil {
+		return x.State
+	}
+	return JobState_JOB_STATE_UNSPECIFIED
+}
+
+func (x *ImportJob) GetParams() map[string]string {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *ImportJob) GetProgress() *ImportProgress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *ImportJob) GetResult() *structpb.Struct {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *ImportJob) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ImportJob) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ImportJob) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *ImportJob) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+func (x *ImportJob) GetTransitions() []*JobTransition {
+	if x != nil {
+		return x.Transitions
+	}
+	return nil
+}
+
+type ListSnapshotsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Identifier    *TableIdentifier       `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSnapshotsRequest) Reset() {
+	*x = ListSnapshotsRequest{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSnapshotsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSnapshotsRequest) ProtoMessage() {}
+
+func (x *ListSnapshotsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSnapshotsRequest.ProtoReflect.Descriptor instead.
+func (*ListSnapshotsRequest) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListSnapshotsRequest) GetIdentifier() *TableIdentifier {
+	if x != nil {
+		return x.Identifier
+	}
+	return nil
+}
+
+// Snapshot is a snapshot of a table.
+type Snapshot struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	SnapshotId int64                  `protobuf:"varint,1,opt,name=snapshot_id,json=snapshotId,proto3" json:"snapshot_id,omitempty"`
+	// Zero for the first snapshot of a table.
+	ParentSnapshotId int64                  `protobuf:"varint,2,opt,name=parent_snapshot_id,json=parentSnapshotId,proto3" json:"parent_snapshot_id,omitempty"`
+	SequenceNumber   int64                  `protobuf:"varint,3,opt,name=sequence_number,json=sequenceNumber,proto3" json:"sequence_number,omitempty"`
+	Timestamp        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ManifestList     string                 `protobuf:"bytes,5,opt,name=manifest_list,json=manifestList,proto3" json:"manifest_list,omitempty"`
+	Operation        string                 `protobuf:"bytes,6,opt,name=operation,proto3" json:"operation,omitempty"`
+	Summary          map[string]string      `protobuf:"bytes,7,rep,name=summary,proto3" json:"summary,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Snapshot) Reset() {
+	*x = Snapshot{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Snapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Snapshot) ProtoMessage() {}
+
+func (x *Snapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Snapshot.ProtoReflect.Descriptor instead.
+func (*Snapshot) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Snapshot) GetSnapshotId() int64 {
+	if x != nil {
+		return x.SnapshotId
+	}
+	return 0
+}
+
+func (x *Snapshot) GetParentSnapshotId() int64 {
+	if x != nil {
+		return x.ParentSnapshotId
+	}
+	return 0
+}
+
+func (x *Snapshot) GetSequenceNumber() int64 {
+	if x != nil {
+		return x.SequenceNumber
+	}
+	return 0
+}
+
+func (x *Snapshot) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Snapshot) GetManifestList() string {
+	if x != nil {
+		return x.ManifestList
+	}
+	return ""
+}
+
+func (x *Snapshot) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *Snapshot) GetSummary() map[string]string {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+// SnapshotLogEntry records when a snapshot became current.
+type SnapshotLogEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SnapshotId    int64                  `protobuf:"varint,1,opt,name=snapshot_id,json=snapshotId,proto3" json:"snapshot_id,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotLogEntry) Reset() {
+	*x = SnapshotLogEntry{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotLogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotLogEntry) ProtoMessage() {}
+
+func (x *SnapshotLogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotLogEntry.ProtoReflect.Descriptor instead.
+func (*SnapshotLogEntry) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *SnapshotLogEntry) GetSnapshotId() int64 {
+	if x != nil {
+		return x.SnapshotId
+	}
+	return 0
+}
+
+func (x *SnapshotLogEntry) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type ListSnapshotsResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Snapshots []*Snapshot            `protobuf:"bytes,1,rep,name=snapshots,proto3" json:"snapshots,omitempty"`
+	// Zero when the table has no snapshots.
+	CurrentSnapshotId int64               `protobuf:"varint,2,opt,name=current_snapshot_id,json=currentSnapshotId,proto3" json:"current_snapshot_id,omitempty"`
+	History           []*SnapshotLogEntry `protobuf:"bytes,3,rep,name=history,proto3" json:"history,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ListSnapshotsResponse) Reset() {
+	*x = ListSnapshotsResponse{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSnapshotsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSnapshotsResponse) ProtoMessage() {}
+
+func (x *ListSnapshotsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSnapshotsResponse.ProtoReflect.Descriptor instead.
+func (*ListSnapshotsResponse) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListSnapshotsResponse) GetSnapshots() []*Snapshot {
+	if x != nil {
+		return x.Snapshots
+	}
+	return nil
+}
+
+func (x *ListSnapshotsResponse) GetCurrentSnapshotId() int64 {
+	if x != nil {
+		return x.CurrentSnapshotId
+	}
+	return 0
+}
+
+func (x *ListSnapshotsResponse) GetHistory() []*SnapshotLogEntry {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+type QueryAsOfRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Table *TableIdentifier       `protobuf:"bytes,1,opt,name=table,proto3" json:"table,omitempty"`
+	// Types that are valid to be assigned to AsOf:
+	//
+	//	*QueryAsOfRequest_SnapshotId
+	//	*QueryAsOfRequest_Timestamp
+	AsOf isQueryAsOfRequest_AsOf `protobuf_oneof:"as_of"`
+	// Query referencing the table by its full name; defaults to a sample of its rows.
+	Sql string `protobuf:"bytes,4,opt,name=sql,proto3" json:"sql,omitempty"`
+	// Number of rows per record batch; zero uses the server default.
+	BatchSize     int32 `protobuf:"varint,5,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryAsOfRequest) Reset() {
+	*x = QueryAsOfRequest{}
+	mi := &file_icebox_v1_icebox_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryAsOfRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryAsOfRequest) ProtoMessage() {}
+
+func (x *QueryAsOfRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_icebox_v1_icebox_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryAsOfRequest.ProtoReflect.Descriptor instead.
+func (*QueryAsOfRequest) Descriptor() ([]byte, []int) {
+	return file_icebox_v1_icebox_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *QueryAsOfRequest) GetTable() *TableIdentifier {
+	if x != nil {
+		return x.Table
+	}
+	return nil
+}
+
+func (x *QueryAsOfRequest) GetAsOf() isQueryAsOfRequest_AsOf {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
+func (x *QueryAsOfRequest) GetSnapshotId() int64 {
+	if x != nil {
+		if x, ok := x.AsOf.(*QueryAsOfRequest_SnapshotId); ok {
+			return x.SnapshotId
+		}
+	}
+	return 0
+}
+
+func (x *QueryAsOfRequest) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		if x, ok := x.AsOf.(*QueryAsOfRequest_Timestamp); ok {
+			return x.Timestamp
+		}
+	}
+	return nil
+}
+
+func (x *QueryAsOfRequest) GetSql() string {
+	if x != nil {
+		return x.Sql
+	}
+	return ""
+}
+
+func (x *QueryAsOfRequest) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+type isQueryAsOfRequest_AsOf interface {
+	isQueryAsOfRequest_AsOf()
+}
+
+type QueryAsOfRequest_SnapshotId struct {
+	SnapshotId int64 `protobuf:"varint,2,opt,name=snapshot_id,json=snapshotId,proto3,oneof"`
+}
+
+type QueryAsOfRequest_Timestamp struct {
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3,oneof"`
+}
+
+func (*QueryAsOfRequest_SnapshotId) isQueryAsOfRequest_AsOf() {}
+
+func (*QueryAsOfRequest_Timestamp) isQueryAsOfRequest_AsOf() {}
+
+var File_icebox_v1_icebox_proto protoreflect.FileDescriptor
+
+const file_icebox_v1_icebox_proto_rawDesc = "" +
+	"\n" +
+	"\x16icebox/v1/icebox.proto\x12\ticebox.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"C\n" +
+	"\x0fTableIdentifier\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x03(\tR\tnamespace\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\xa8\x01\n" +
+	"\tNamespace\x12\x16\n" +
+	"\x06levels\x18\x01 \x03(\tR\x06levels\x12D\n" +
+	"\n" +
+	"properties\x18\x02 \x03(\v2$.icebox.v1.Namespace.PropertiesEntryR\n" +
+	"properties\x1a=\n" +
+	"\x0fPropertiesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"/\n" +
+	"\x15ListNamespacesRequest\x12\x16\n" +
+	"\x06parent\x18\x01 \x03(\tR\x06parent\"N\n" +
+	"\x16ListNamespacesResponse\x124\n" +
+	"\n" +
+	"namespaces\x18\x01 \x03(\v2\x14.icebox.v1.NamespaceR\n" +
+	"namespaces\"\xc2\x01\n" +
+	"\x16CreateNamespaceRequest\x12\x16\n" +
+	"\x06levels\x18\x01 \x03(\tR\x06levels\x12Q\n" +
+	"\n" +
+	"properties\x18\x02 \x03(\v21.icebox.v1.CreateNamespaceRequest.PropertiesEntryR\n" +
+	"properties\x1a=\n" +
+	"\x0fPropertiesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"-\n" +
+	"\x13GetNamespaceRequest\x12\x16\n" +
+	"\x06levels\x18\x01 \x03(\tR\x06levels\".\n" +
+	"\x14DropNamespaceRequest\x12\x16\n" +
+	"\x06levels\x18\x01 \x03(\tR\x06levels\"\x17\n" +
+	"\x15DropNamespaceResponse\"1\n" +
+	"\x11ListTablesRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x03(\tR\tnamespace\"H\n" +
+	"\x12ListTablesResponse\x122\n" +
+	"\x06tables\x18\x01 \x03(\v2\x1a.icebox.v1.TableIdentifierR\x06tables\"\xcb\x02\n" +
+	"\x12CreateTableRequest\x12:\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\v2\x1a.icebox.v1.TableIdentifierR\n" +
+	"identifier\x12\x1f\n" +
+	"\vschema_json\x18\x02 \x01(\tR\n" +
+	"schemaJson\x12.\n" +
+	"\x13partition_spec_json\x18\x03 \x01(\tR\x11partitionSpecJson\x12\x1a\n" +
+	"\blocation\x18\x04 \x01(\tR\blocation\x12M\n" +
+	"\n" +
+	"properties\x18\x05 \x03(\v2-.icebox.v1.CreateTableRequest.PropertiesEntryR\n" +
+	"properties\x1a=\n" +
+	"\x0fPropertiesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"N\n" +
+	"\x10LoadTableRequest\x12:\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\v2\x1a.icebox.v1.TableIdentifierR\n" +
+	"identifier\"N\n" +
+	"\x10DropTableRequest\x12:\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\v2\x1a.icebox.v1.TableIdentifierR\n" +
+	"identifier\"\x13\n" +
+	"\x11DropTableResponse\"\xdd\x03\n" +
+	"\x05Table\x12:\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\v2\x1a.icebox.v1.TableIdentifierR\n" +
+	"identifier\x12\x1a\n" +
+	"\blocation\x18\x02 \x01(\tR\blocation\x12+\n" +
+	"\x11metadata_location\x18\x03 \x01(\tR\x10metadataLocation\x12%\n" +
+	"\x0eformat_version\x18\x04 \x01(\x05R\rformatVersion\x12\x1f\n" +
+	"\vschema_json\x18\x05 \x01(\tR\n" +
+	"schemaJson\x12.\n" +
+	"\x13partition_spec_json\x18\x06 \x01(\tR\x11partitionSpecJson\x12&\n" +
+	"\x0fsort_order_json\x18\a \x01(\tR\rsortOrderJson\x12.\n" +
+	"\x13current_snapshot_id\x18\b \x01(\x03R\x11currentSnapshotId\x12@\n" +
+	"\n" +
+	"properties\x18\t \x03(\v2 .icebox.v1.Table.PropertiesEntryR\n" +
+	"properties\x1a=\n" +
+	"\x0fPropertiesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"F\n" +
+	"\x13ExecuteQueryRequest\x12\x10\n" +
+	"\x03sql\x18\x01 \x01(\tR\x03sql\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x02 \x01(\x05R\tbatchSize\"L\n" +
+	"\x10QueryResultChunk\x12\x1b\n" +
+	"\tarrow_ipc\x18\x01 \x01(\fR\barrowIpc\x12\x1b\n" +
+	"\trow_count\x18\x02 \x01(\x03R\browCount\"\xa3\x01\n" +
+	"\x11ImportFileRequest\x12\x1b\n" +
+	"\tfile_path\x18\x01 \x01(\tR\bfilePath\x120\n" +
+	"\x05table\x18\x02 \x01(\v2\x1a.icebox.v1.TableIdentifierR\x05table\x12\x1c\n" +
+	"\toverwrite\x18\x03 \x01(\bR\toverwrite\x12!\n" +
+	"\fpartition_by\x18\x04 \x03(\tR\vpartitionBy\",\n" +
+	"\x13GetImportJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"\x17\n" +
+	"\x15ListImportJobsRequest\"B\n" +
+	"\x16ListImportJobsResponse\x12(\n" +
+	"\x04jobs\x18\x01 \x03(\v2\x14.icebox.v1.ImportJobR\x04jobs\"/\n" +
+	"\x16CancelImportJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"\x90\x01\n" +
+	"\x0eImportProgress\x12\x1b\n" +
+	"\trows_read\x18\x01 \x01(\x03R\browsRead\x12!\n" +
+	"\frows_written\x18\x02 \x01(\x03R\vrowsWritten\x12\x1d\n" +
+	"\n" +
+	"bytes_read\x18\x03 \x01(\x03R\tbytesRead\x12\x1f\n" +
+	"\vtotal_bytes\x18\x04 \x01(\x03R\n" +
+	"totalBytes\"\x80\x01\n" +
+	"\rJobTransition\x12)\n" +
+	"\x05state\x18\x01 \x01(\x0e2\x13.icebox.v1.JobStateR\x05state\x12*\n" +
+	"\x02at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\xa8\x04\n" +
+	"\tImportJob\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x05state\x18\x02 \x01(\x0e2\x13.icebox.v1.JobStateR\x05state\x128\n" +
+	"\x06params\x18\x03 \x03(\v2 .icebox.v1.ImportJob.ParamsEntryR\x06params\x125\n" +
+	"\bprogress\x18\x04 \x01(\v2\x19.icebox.v1.ImportProgressR\bprogress\x12/\n" +
+	"\x06result\x18\x05 \x01(\v2\x17.google.protobuf.StructR\x06result\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"started_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12;\n" +
+	"\vfinished_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt\x12:\n" +
+	"\vtransitions\x18\n" +
+	" \x03(\v2\x18.icebox.v1.JobTransitionR\vtransitions\x1a9\n" +
+	"\vParamsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"R\n" +
+	"\x14ListSnapshotsRequest\x12:\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\v2\x1a.icebox.v1.TableIdentifierR\n" +
+	"identifier\"\xf7\x02\n" +
+	"\bSnapshot\x12\x1f\n" +
+	"\vsnapshot_id\x18\x01 \x01(\x03R\n" +
+	"snapshotId\x12,\n" +
+	"\x12parent_snapshot_id\x18\x02 \x01(\x03R\x10parentSnapshotId\x12'\n" +
+	"\x0fsequence_number\x18\x03 \x01(\x03R\x0esequenceNumber\x128\n" +
+	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12#\n" +
+	"\rmanifest_list\x18\x05 \x01(\tR\fmanifestList\x12\x1c\n" +
+	"\toperation\x18\x06 \x01(\tR\toperation\x12:\n" +
+	"\asummary\x18\a \x03(\v2 .icebox.v1.Snapshot.SummaryEntryR\asummary\x1a:\n" +
+	"\fSummaryEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"m\n" +
+	"\x10SnapshotLogEntry\x12\x1f\n" +
+	"\vsnapshot_id\x18\x01 \x01(\x03R\n" +
+	"snapshotId\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xb1\x01\n" +
+	"\x15ListSnapshotsResponse\x121\n" +
+	"\tsnapshots\x18\x01 \x03(\v2\x13.icebox.v1.SnapshotR\tsnapshots\x12.\n" +
+	"\x13current_snapshot_id\x18\x02 \x01(\x03R\x11currentSnapshotId\x125\n" +
+	"\ahistory\x18\x03 \x03(\v2\x1b.icebox.v1.SnapshotLogEntryR\ahistory\"\xdd\x01\n" +
+	"\x10QueryAsOfRequest\x120\n" +
+	"\x05table\x18\x01 \x01(\v2\x1a.icebox.v1.TableIdentifierR\x05table\x12!\n" +
+	"\vsnapshot_id\x18\x02 \x01(\x03H\x00R\n" +
+	"snapshotId\x12:\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampH\x00R\ttimestamp\x12\x10\n" +
+	"\x03sql\x18\x04 \x01(\tR\x03sql\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x05 \x01(\x05R\tbatchSizeB\a\n" +
+	"\x05as_of*\x99\x01\n" +
+	"\bJobState\x12\x19\n" +
+	"\x15JOB_STATE_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10JOB_STATE_QUEUED\x10\x01\x12\x15\n" +
+	"\x11JOB_STATE_RUNNING\x10\x02\x12\x17\n" +
+	"\x13JOB_STATE_SUCCEEDED\x10\x03\x12\x14\n" +
+	"\x10JOB_STATE_FAILED\x10\x04\x12\x16\n" +
+	"\x12JOB_STATE_CANCELED\x10\x052\xdc\x04\n" +
+	"\x0eCatalogService\x12U\n" +
+	"\x0eListNamespaces\x12 .icebox.v1.ListNamespacesRequest\x1a!.icebox.v1.ListNamespacesResponse\x12J\n" +
+	"\x0fCreateNamespace\x12!.icebox.v1.CreateNamespaceRequest\x1a\x14.icebox.v1.Namespace\x12D\n" +
+	"\fGetNamespace\x12\x1e.icebox.v1.GetNamespaceRequest\x1a\x14.icebox.v1.Namespace\x12R\n" +
+	"\rDropNamespace\x12\x1f.icebox.v1.DropNamespaceRequest\x1a .icebox.v1.DropNamespaceResponse\x12I\n" +
+	"\n" +
+	"ListTables\x12\x1c.icebox.v1.ListTablesRequest\x1a\x1d.icebox.v1.ListTablesResponse\x12>\n" +
+	"\vCreateTable\x12\x1d.icebox.v1.CreateTableRequest\x1a\x10.icebox.v1.Table\x12:\n" +
+	"\tLoadTable\x12\x1b.icebox.v1.LoadTableRequest\x1a\x10.icebox.v1.Table\x12F\n" +
+	"\tDropTable\x12\x1b.icebox.v1.DropTableRequest\x1a\x1c.icebox.v1.DropTableResponse2]\n" +
+	"\fQueryService\x12M\n" +
+	"\fExecuteQuery\x12\x1e.icebox.v1.ExecuteQueryRequest\x1a\x1b.icebox.v1.QueryResultChunk0\x012\x84\x03\n" +
+	"\rImportService\x12@\n" +
+	"\n" +
+	"ImportFile\x12\x1c.icebox.v1.ImportFileRequest\x1a\x14.icebox.v1.ImportJob\x12D\n" +
+	"\fGetImportJob\x12\x1e.icebox.v1.GetImportJobRequest\x1a\x14.icebox.v1.ImportJob\x12U\n" +
+	"\x0eListImportJobs\x12 .icebox.v1.ListImportJobsRequest\x1a!.icebox.v1.ListImportJobsResponse\x12J\n" +
+	"\x0fCancelImportJob\x12!.icebox.v1.CancelImportJobRequest\x1a\x14.icebox.v1.ImportJob\x12H\n" +
+	"\x0eWatchImportJob\x12\x1e.icebox.v1.GetImportJobRequest\x1a\x14.icebox.v1.ImportJob0\x012\xb0\x01\n" +
+	"\x11TimeTravelService\x12R\n" +
+	"\rListSnapshots\x12\x1f.icebox.v1.ListSnapshotsRequest\x1a .icebox.v1.ListSnapshotsResponse\x12G\n" +
+	"\tQueryAsOf\x12\x1b.icebox.v1.QueryAsOfRequest\x1a\x1b.icebox.v1.QueryResultChunk0\x01B1Z/github.com/TFMV/icebox/proto/icebox/v1;iceboxv1b\x06proto3"
+
+var (
+	file_icebox_v1_icebox_proto_rawDescOnce sync.Once
+	file_icebox_v1_icebox_proto_rawDescData []byte
+)
+
+func file_icebox_v1_icebox_proto_rawDescGZIP() []byte {
+	file_icebox_v1_icebox_proto_rawDescOnce.Do(func() {
+		file_icebox_v1_icebox_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_icebox_v1_icebox_proto_rawDesc), len(file_icebox_v1_icebox_proto_rawDesc)))
+	})
+	return file_icebox_v1_icebox_proto_rawDescData
+}
+
+var file_icebox_v1_icebox_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_icebox_v1_icebox_proto_msgTypes = make([]protoimpl.MessageInfo, 36)
+var file_icebox_v1_icebox_proto_goTypes = []any{
+	(JobState)(0),                  // 0: icebox.v1.JobState
+	(*TableIdentifier)(nil),        // 1: icebox.v1.TableIdentifier
+	(*Namespace)(nil),              // 2: icebox.v1.Namespace
+	(*ListNamespacesRequest)(nil),  // 3: icebox.v1.ListNamespacesRequest
+	(*ListNamespacesResponse)(nil), // 4: icebox.v1.ListNamespacesResponse
+	(*CreateNamespaceRequest)(nil), // 5: icebox.v1.CreateNamespaceRequest
+	(*GetNamespaceRequest)(nil),    // 6: icebox.v1.GetNamespaceRequest
+	(*DropNamespaceRequest)(nil),   // 7: icebox.v1.DropNamespaceRequest
+	(*DropNamespaceResponse)(nil),  // 8: icebox.v1.DropNamespaceResponse
+	(*ListTablesRequest)(nil),      // 9: icebox.v1.ListTablesRequest
+	(*ListTablesResponse)(nil),     // 10: icebox.v1.ListTablesResponse
+	(*CreateTableRequest)(nil),     // 11: icebox.v1.CreateTableRequest
+	(*LoadTableRequest)(nil),       // 12: icebox.v1.LoadTableRequest
+	(*DropTableRequest)(nil),       // 13: icebox.v1.DropTableRequest
+	(*DropTableResponse)(nil),      // 14: icebox.v1.DropTableResponse
+	(*Table)(nil),                  // 15: icebox.v1.Table
+	(*ExecuteQueryRequest)(nil),    // 16: icebox.v1.ExecuteQueryRequest
+	(*QueryResultChunk)(nil),       // 17: icebox.v1.QueryResultChunk
+	(*ImportFileRequest)(nil),      // 18: icebox.v1.ImportFileRequest
+	(*GetImportJobRequest)(nil),    // 19: icebox.v1.GetImportJobRequest
+	(*ListImportJobsRequest)(nil),  // 20: icebox.v1.ListImportJobsRequest
+	(*ListImportJobsResponse)(nil), // 21: icebox.v1.ListImportJobsResponse
+	(*CancelImportJobRequest)(nil), // 22: icebox.v1.CancelImportJobRequest
+	(*ImportProgress)(nil),         // 23: icebox.v1.ImportProgress
+	(*JobTransition)(nil),          // 24: icebox.v1.JobTransition
+	(*ImportJob)(nil),              // 25: icebox.v1.ImportJob
+	(*ListSnapshotsRequest)(nil),   // 26: icebox.v1.ListSnapshotsRequest
+	(*Snapshot)(nil),               // 27: icebox.v1.Snapshot
+	(*SnapshotLogEntry)(nil),       // 28: icebox.v1.SnapshotLogEntry
+	(*ListSnapshotsResponse)(nil),  // 29: icebox.v1.ListSnapshotsResponse
+	(*QueryAsOfRequest)(nil),       // 30: icebox.v1.QueryAsOfRequest
+	nil,                            // 31: icebox.v1.Namespace.PropertiesEntry
+	nil,                            // 32: icebox.v1.CreateNamespaceRequest.PropertiesEntry
+	nil,                            // 33: icebox.v1.CreateTableRequest.PropertiesEntry
+	nil,                            // 34: icebox.v1.Table.PropertiesEntry
+	nil,                            // 35: icebox.v1.ImportJob.ParamsEntry
+	nil,                            // 36: icebox.v1.Snapshot.SummaryEntry
+	(*timestamppb.Timestamp)(nil),  // 37: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),        // 38: google.protobuf.Struct
+}
+var file_icebox_v1_icebox_proto_depIdxs = []int32{
+	31, // 0: icebox.v1.Namespace.properties:type_name -> icebox.v1.Namespace.PropertiesEntry
+	2,  // 1: icebox.v1.ListNamespacesResponse.namespaces:type_name -> icebox.v1.Namespace
+	32, // 2: icebox.v1.CreateNamespaceRequest.properties:type_name -> icebox.v1.CreateNamespaceRequest.PropertiesEntry
+	1,  // 3: icebox.v1.ListTablesResponse.tables:type_name -> icebox.v1.TableIdentifier
+	1,  // 4: icebox.v1.CreateTableRequest.identifier:type_name -> icebox.v1.TableIdentifier
+	33, // 5: icebox.v1.CreateTableRequest.properties:type_name -> icebox.v1.CreateTableRequest.PropertiesEntry
+	1,  // 6: icebox.v1.LoadTableRequest.identifier:type_name -> icebox.v1.TableIdentifier
+	1,  // 7: icebox.v1.DropTableRequest.identifier:type_name -> icebox.v1.TableIdentifier
+	1,  // 8: icebox.v1.Table.identifier:type_name -> icebox.v1.TableIdentifier
+	34, // 9: icebox.v1.Table.properties:type_name -> icebox.v1.Table.PropertiesEntry
+	1,  // 10: icebox.v1.ImportFileRequest.table:type_name -> icebox.v1.TableIdentifier
+	25, // 11: icebox.v1.ListImportJobsResponse.jobs:type_name -> icebox.v1.ImportJob
+	0,  // 12: icebox.v1.JobTransition.state:type_name -> icebox.v1.JobState
+	37, // 13: icebox.v1.JobTransition.at:type_name -> google.protobuf.Timestamp
+	0,  // 14: icebox.v1.ImportJob.state:type_name -> icebox.v1.JobState
+	35, // 15: icebox.v1.ImportJob.params:type_name -> icebox.v1.ImportJob.ParamsEntry
+	23, // 16: icebox.v1.ImportJob.progress:type_name -> icebox.v1.ImportProgress
+	38, // 17: icebox.v1.ImportJob.result:type_name -> google.protobuf.Struct
+	37, // 18: icebox.v1.ImportJob.created_at:type_name -> google.protobuf.Timestamp
+	37, // 19: icebox.v1.ImportJob.started_at:type_name -> google.protobuf.Timestamp
+	37, // 20: icebox.v1.ImportJob.finished_at:type_name -> google.protobuf.Timestamp
+	24, // 21: icebox.v1.ImportJob.transitions:type_name -> icebox.v1.JobTransition
+	1,  // 22: icebox.v1.ListSnapshotsRequest.identifier:type_name -> icebox.v1.TableIdentifier
+	37, // 23: icebox.v1.Snapshot.timestamp:type_name -> google.protobuf.Timestamp
+	36, // 24: icebox.v1.Snapshot.summary:type_name -> icebox.v1.Snapshot.SummaryEntry
+	37, // 25: icebox.v1.SnapshotLogEntry.timestamp:type_name -> google.protobuf.Timestamp
+	27, // 26: icebox.v1.ListSnapshotsResponse.snapshots:type_name -> icebox.v1.Snapshot
+	28, // 27: icebox.v1.ListSnapshotsResponse.history:type_name -> icebox.v1.SnapshotLogEntry
+	1,  // 28: icebox.v1.QueryAsOfRequest.table:type_name -> icebox.v1.TableIdentifier
+	37, // 29: icebox.v1.QueryAsOfRequest.timestamp:type_name -> google.protobuf.Timestamp
+	3,  // 30: icebox.v1.CatalogService.ListNamespaces:input_type -> icebox.v1.ListNamespacesRequest
+	5,  // 31: icebox.v1.CatalogService.CreateNamespace:input_type -> icebox.v1.CreateNamespaceRequest
+	6,  // 32: icebox.v1.CatalogService.GetNamespace:input_type -> icebox.v1.GetNamespaceRequest
+	7,  // 33: icebox.v1.CatalogService.DropNamespace:input_type -> icebox.v1.DropNamespaceRequest
+	9,  // 34: icebox.v1.CatalogService.ListTables:input_type -> icebox.v1.ListTablesRequest
+	11, // 35: icebox.v1.CatalogService.CreateTable:input_type -> icebox.v1.CreateTableRequest
+	12, // 36: icebox.v1.CatalogService.LoadTable:input_type -> icebox.v1.LoadTableRequest
+	13, // 37: icebox.v1.CatalogService.DropTable:input_type -> icebox.v1.DropTableRequest
+	16, // 38: icebox.v1.QueryService.ExecuteQuery:input_type -> icebox.v1.ExecuteQueryRequest
+	18, // 39: icebox.v1.ImportService.ImportFile:input_type -> icebox.v1.ImportFileRequest
+	19, // 40: icebox.v1.ImportService.GetImportJob:input_type -> icebox.v1.GetImportJobRequest
+	20, // 41: icebox.v1.ImportService.ListImportJobs:input_type -> icebox.v1.ListImportJobsRequest
+	22, // 42: icebox.v1.ImportService.CancelImportJob:input_type -> icebox.v1.CancelImportJobRequest
+	19, // 43: icebox.v1.ImportService.WatchImportJob:input_type -> icebox.v1.GetImportJobRequest
+	26, // 44: icebox.v1.TimeTravelService.ListSnapshots:input_type -> icebox.v1.ListSnapshotsRequest
+	30, // 45: icebox.v1.TimeTravelService.QueryAsOf:input_type -> icebox.v1.QueryAsOfRequest
+	4,  // 46: icebox.v1.CatalogService.ListNamespaces:output_type -> icebox.v1.ListNamespacesResponse
+	2,  // 47: icebox.v1.CatalogService.CreateNamespace:output_type -> icebox.v1.Namespace
+	2,  // 48: icebox.v1.CatalogService.GetNamespace:output_type -> icebox.v1.Namespace
+	8,  // 49: icebox.v1.CatalogService.DropNamespace:output_type -> icebox.v1.DropNamespaceResponse
+	10, // 50: icebox.v1.CatalogService.ListTables:output_type -> icebox.v1.ListTablesResponse
+	15, // 51: icebox.v1.CatalogService.CreateTable:output_type -> icebox.v1.Table
+	15, // 52: icebox.v1.CatalogService.LoadTable:output_type -> icebox.v1.Table
+	14, // 53: icebox.v1.CatalogService.DropTable:output_type -> icebox.v1.DropTableResponse
+	17, // 54: icebox.v1.QueryService.ExecuteQuery:output_type -> icebox.v1.QueryResultChunk
+	25, // 55: icebox.v1.ImportService.ImportFile:output_type -> icebox.v1.ImportJob
+	25, // 56: icebox.v1.ImportService.GetImportJob:output_type -> icebox.v1.ImportJob
+	21, // 57: icebox.v1.ImportService.ListImportJobs:output_type -> icebox.v1.ListImportJobsResponse
+	25, // 58: icebox.v1.ImportService.CancelImportJob:output_type -> icebox.v1.ImportJob
+	25, // 59: icebox.v1.ImportService.WatchImportJob:output_type -> icebox.v1.ImportJob
+	29, // 60: icebox.v1.TimeTravelService.ListSnapshots:output_type -> icebox.v1.ListSnapshotsResponse
+	17, // 61: icebox.v1.TimeTravelService.QueryAsOf:output_type -> icebox.v1.QueryResultChunk
+	46, // [46:62] is the sub-list for method output_type
+	30, // [30:46] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
+}
+
+func init() { file_icebox_v1_icebox_proto_init() }
+func file_icebox_v1_icebox_proto_init() {
+	if File_icebox_v1_icebox_proto != nil {
+		return
+	}
+	file_icebox_v1_icebox_proto_msgTypes[29].OneofWrappers = []any{
+		(*QueryAsOfRequest_SnapshotId)(nil),
+		(*QueryAsOfRequest_Timestamp)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_icebox_v1_icebox_proto_rawDesc), len(file_icebox_v1_icebox_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   36,
+			NumExtensions: 0,
+			NumServices:   4,
+		},
+		GoTypes:           file_icebox_v1_icebox_proto_goTypes,
+		DependencyIndexes: file_icebox_v1_icebox_proto_depIdxs,
+		EnumInfos:         file_icebox_v1_icebox_proto_enumTypes,
+		MessageInfos:      file_icebox_v1_icebox_proto_msgTypes,
+	}.Build()
+	File_icebox_v1_icebox_proto = out.File
+	file_icebox_v1_icebox_proto_goTypes = nil
+	file_icebox_v1_icebox_proto_depIdxs = nil
+}
@@ -0,0 +1,916 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: icebox/v1/icebox.proto
+
+package iceboxv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// CatalogServiceClient is the client API for CatalogService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CatalogServiceClient interface {
+	// ListNamespaces lists the namespaces below a parent, or the top-level ones.
+	ListNamespaces(ctx context.Context, in *ListNamespacesRequest, opts ...grpc.CallOption) (*ListNamespacesResponse, error)
+	// CreateNamespace creates a namespace with optional properties.
+	CreateNamespace(ctx context.Context, in *CreateNamespaceRequest, opts ...grpc.CallOption) (*Namespace, error)
+	// GetNamespace returns a namespace and its properties.
+	GetNamespace(ctx context.Context, in *GetNamespaceRequest, opts ...grpc.CallOption) (*Namespace, error)
+	// DropNamespace drops an empty namespace.
+	DropNamespace(ctx context.Context, in *DropNamespaceRequest, opts ...grpc.CallOption) (*DropNamespaceResponse, error)
+	// ListTables lists the tables of a namespace.
+	ListTables(ctx context.Context, in *ListTablesRequest, opts ...grpc.CallOption) (*ListTablesResponse, error)
+	// CreateTable creates a table from an Iceberg schema.
+	CreateTable(ctx context.Context, in *CreateTableRequest, opts ...grpc.CallOption) (*Table, error)
+	// LoadTable returns the current metadata of a table.
+	LoadTable(ctx context.Context, in *LoadTableRequest, opts ...grpc.CallOption) (*Table, error)
+	// DropTable drops a table from the catalog.
+	DropTable(ctx context.Context, in *DropTableRequest, opts ...grpc.CallOption) (*DropTableResponse, error)
+}
+
+type catalogServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCatalogServiceClient(cc grpc.ClientConnInterface) CatalogServiceClient {
+	return &catalogServiceClient{cc}
+}
+
+func (c *catalogServiceClient) ListNamespaces(ctx context.Context, in *ListNamespacesRequest, opts ...grpc.CallOption) (*ListNamespacesResponse, error) {
+	out := new(ListNamespacesResponse)
+	err := c.cc.Invoke(ctx, "/icebox.v1.CatalogService/ListNamespaces", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) CreateNamespace(ctx context.Context, in *CreateNamespaceRequest, opts ...grpc.CallOption) (*Namespace, error) {
+	out := new(Namespace)
+	err := c.cc.Invoke(ctx, "/icebox.v1.CatalogService/CreateNamespace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) GetNamespace(ctx context.Context, in *GetNamespaceRequest, opts ...grpc.CallOption) (*Namespace, error) {
+	out := new(Namespace)
+	err := c.cc.Invoke(ctx, "/icebox.v1.CatalogService/GetNamespace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) DropNamespace(ctx context.Context, in *DropNamespaceRequest, opts ...grpc.CallOption) (*DropNamespaceResponse, error) {
+	out := new(DropNamespaceResponse)
+	err := c.cc.Invoke(ctx, "/icebox.v1.CatalogService/DropNamespace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) ListTables(ctx context.Context, in *ListTablesRequest, opts ...grpc.CallOption) (*ListTablesResponse, error) {
+	out := new(ListTablesResponse)
+	err := c.cc.Invoke(ctx, "/icebox.v1.CatalogService/ListTables", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) CreateTable(ctx context.Context, in *CreateTableRequest, opts ...grpc.CallOption) (*Table, error) {
+	out := new(Table)
+	err := c.cc.Invoke(ctx, "/icebox.v1.CatalogService/CreateTable", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) LoadTable(ctx context.Context, in *LoadTableRequest, opts ...grpc.CallOption) (*Table, error) {
+	out := new(Table)
+	err := c.cc.Invoke(ctx, "/icebox.v1.CatalogService/LoadTable", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) DropTable(ctx context.Context, in *DropTableRequest, opts ...grpc.CallOption) (*DropTableResponse, error) {
+	out := new(DropTableResponse)
+	err := c.cc.Invoke(ctx, "/icebox.v1.CatalogService/DropTable", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CatalogServiceServer is the server API for CatalogService service.
+// All implementations must embed UnimplementedCatalogServiceServer
+// for forward compatibility
+type CatalogServiceServer interface {
+	// ListNamespaces lists the namespaces below a parent, or the top-level ones.
+	ListNamespaces(context.Context, *ListNamespacesRequest) (*ListNamespacesResponse, error)
+	// CreateNamespace creates a namespace with optional properties.
+	CreateNamespace(context.Context, *CreateNamespaceRequest) (*Namespace, error)
+	// GetNamespace returns a namespace and its properties.
+	GetNamespace(context.Context, *GetNamespaceRequest) (*Namespace, error)
+	// DropNamespace drops an empty namespace.
+	DropNamespace(context.Context, *DropNamespaceRequest) (*DropNamespaceResponse, error)
+	// ListTables lists the tables of a namespace.
+	ListTables(context.Context, *ListTablesRequest) (*ListTablesResponse, error)
+	// CreateTable creates a table from an Iceberg schema.
+	CreateTable(context.Context, *CreateTableRequest) (*Table, error)
+	// LoadTable returns the current metadata of a table.
+	LoadTable(context.Context, *LoadTableRequest) (*Table, error)
+	// DropTable drops a table from the catalog.
+	DropTable(context.Context, *DropTableRequest) (*DropTableResponse, error)
+	mustEmbedUnimplementedCatalogServiceServer()
+}
+
+// UnimplementedCatalogServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedCatalogServiceServer struct {
+}
+
+func (UnimplementedCatalogServiceServer) ListNamespaces(context.Context, *ListNamespacesRequest) (*ListNamespacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNamespaces not implemented")
+}
+func (UnimplementedCatalogServiceServer) CreateNamespace(context.Context, *CreateNamespaceRequest) (*Namespace, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateNamespace not implemented")
+}
+func (UnimplementedCatalogServiceServer) GetNamespace(context.Context, *GetNamespaceRequest) (*Namespace, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNamespace not implemented")
+}
+func (UnimplementedCatalogServiceServer) DropNamespace(context.Context, *DropNamespaceRequest) (*DropNamespaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DropNamespace not implemented")
+}
+func (UnimplementedCatalogServiceServer) ListTables(context.Context, *ListTablesRequest) (*ListTablesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTables not implemented")
+}
+func (UnimplementedCatalogServiceServer) CreateTable(context.Context, *CreateTableRequest) (*Table, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTable not implemented")
+}
+func (UnimplementedCatalogServiceServer) LoadTable(context.Context, *LoadTableRequest) (*Table, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoadTable not implemented")
+}
+func (UnimplementedCatalogServiceServer) DropTable(context.Context, *DropTableRequest) (*DropTableResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DropTable not implemented")
+}
+func (UnimplementedCatalogServiceServer) mustEmbedUnimplementedCatalogServiceServer() {}
+
+// UnsafeCatalogServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CatalogServiceServer will
+// result in compilation errors.
+type UnsafeCatalogServiceServer interface {
+	mustEmbedUnimplementedCatalogServiceServer()
+}
+
+func RegisterCatalogServiceServer(s grpc.ServiceRegistrar, srv CatalogServiceServer) {
+	s.RegisterService(&CatalogService_ServiceDesc, srv)
+}
+
+func _CatalogService_ListNamespaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNamespacesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).ListNamespaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/icebox.v1.CatalogService/ListNamespaces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).ListNamespaces(ctx, req.(*ListNamespacesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_CreateNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateNamespaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).CreateNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/icebox.v1.CatalogService/CreateNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).CreateNamespace(ctx, req.(*CreateNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_GetNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNamespaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).GetNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/icebox.v1.CatalogService/GetNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).GetNamespace(ctx, req.(*GetNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_DropNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DropNamespaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).DropNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/icebox.v1.CatalogService/DropNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).DropNamespace(ctx, req.(*DropNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_ListTables_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTablesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).ListTables(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/icebox.v1.CatalogService/ListTables",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).ListTables(ctx, req.(*ListTablesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_CreateTable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTableRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).CreateTable(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/icebox.v1.CatalogService/CreateTable",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).CreateTable(ctx, req.(*CreateTableRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_LoadTable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadTableRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).LoadTable(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/icebox.v1.CatalogService/LoadTable",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).LoadTable(ctx, req.(*LoadTableRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_DropTable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DropTableRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).DropTable(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/icebox.v1.CatalogService/DropTable",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).DropTable(ctx, req.(*DropTableRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CatalogService_ServiceDesc is the grpc.ServiceDesc for CatalogService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CatalogService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "icebox.v1.CatalogService",
+	HandlerType: (*CatalogServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListNamespaces",
+			Handler:    _CatalogService_ListNamespaces_Handler,
+		},
+		{
+			MethodName: "CreateNamespace",
+			Handler:    _CatalogService_CreateNamespace_Handler,
+		},
+		{
+			MethodName: "GetNamespace",
+			Handler:    _CatalogService_GetNamespace_Handler,
+		},
+		{
+			MethodName: "DropNamespace",
+			Handler:    _CatalogService_DropNamespace_Handler,
+		},
+		{
+			MethodName: "ListTables",
+			Handler:    _CatalogService_ListTables_Handler,
+		},
+		{
+			MethodName: "CreateTable",
+			Handler:    _CatalogService_CreateTable_Handler,
+		},
+		{
+			MethodName: "LoadTable",
+			Handler:    _CatalogService_LoadTable_Handler,
+		},
+		{
+			MethodName: "DropTable",
+			Handler:    _CatalogService_DropTable_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "icebox/v1/icebox.proto",
+}
+
+// QueryServiceClient is the client API for QueryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type QueryServiceClient interface {
+	// ExecuteQuery runs a query and streams its result as Arrow IPC record batches.
+	ExecuteQuery(ctx context.Context, in *ExecuteQueryRequest, opts ...grpc.CallOption) (QueryService_ExecuteQueryClient, error)
+}
+
+type queryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewQueryServiceClient(cc grpc.ClientConnInterface) QueryServiceClient {
+	return &queryServiceClient{cc}
+}
+
+func (c *queryServiceClient) ExecuteQuery(ctx context.Context, in *ExecuteQueryRequest, opts ...grpc.CallOption) (QueryService_ExecuteQueryClient, error) {
+	stream, err := c.cc.NewStream(ctx, &QueryService_ServiceDesc.Streams[0], "/icebox.v1.QueryService/ExecuteQuery", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &queryServiceExecuteQueryClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type QueryService_ExecuteQueryClient interface {
+	Recv() (*QueryResultChunk, error)
+	grpc.ClientStream
+}
+
+type queryServiceExecuteQueryClient struct {
+	grpc.ClientStream
+}
+
+func (x *queryServiceExecuteQueryClient) Recv() (*QueryResultChunk, error) {
+	m := new(QueryResultChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// QueryServiceServer is the server API for QueryService service.
+// All implementations must embed UnimplementedQueryServiceServer
+// for forward compatibility
+type QueryServiceServer interface {
+	// ExecuteQuery runs a query and streams its result as Arrow IPC record batches.
+	ExecuteQuery(*ExecuteQueryRequest, QueryService_ExecuteQueryServer) error
+	mustEmbedUnimplementedQueryServiceServer()
+}
+
+// UnimplementedQueryServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedQueryServiceServer struct {
+}
+
+func (UnimplementedQueryServiceServer) ExecuteQuery(*ExecuteQueryRequest, QueryService_ExecuteQueryServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExecuteQuery not implemented")
+}
+func (UnimplementedQueryServiceServer) mustEmbedUnimplementedQueryServiceServer() {}
+
+// UnsafeQueryServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to QueryServiceServer will
+// result in compilation errors.
+type UnsafeQueryServiceServer interface {
+	mustEmbedUnimplementedQueryServiceServer()
+}
+
+func RegisterQueryServiceServer(s grpc.ServiceRegistrar, srv QueryServiceServer) {
+	s.RegisterService(&QueryService_ServiceDesc, srv)
+}
+
+func _QueryService_ExecuteQuery_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExecuteQueryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(QueryServiceServer).ExecuteQuery(m, &queryServiceExecuteQueryServer{stream})
+}
+
+type QueryService_ExecuteQueryServer interface {
+	Send(*QueryResultChunk) error
+	grpc.ServerStream
+}
+
+type queryServiceExecuteQueryServer struct {
+	grpc.ServerStream
+}
+
+func (x *queryServiceExecuteQueryServer) Send(m *QueryResultChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// QueryService_ServiceDesc is the grpc.ServiceDesc for QueryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var QueryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "icebox.v1.QueryService",
+	HandlerType: (*QueryServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExecuteQuery",
+			Handler:       _QueryService_ExecuteQuery_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "icebox/v1/icebox.proto",
+}
+
+// ImportServiceClient is the client API for ImportService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ImportServiceClient interface {
+	// ImportFile queues an import of a file readable by the server and returns its job.
+	ImportFile(ctx context.Context, in *ImportFileRequest, opts ...grpc.CallOption) (*ImportJob, error)
+	// GetImportJob returns the state and progress of an import job.
+	GetImportJob(ctx context.Context, in *GetImportJobRequest, opts ...grpc.CallOption) (*ImportJob, error)
+	// ListImportJobs lists import jobs, most recently submitted first.
+	ListImportJobs(ctx context.Context, in *ListImportJobsRequest, opts ...grpc.CallOption) (*ListImportJobsResponse, error)
+	// CancelImportJob cancels a queued or running import job.
+	CancelImportJob(ctx context.Context, in *CancelImportJobRequest, opts ...grpc.CallOption) (*ImportJob, error)
+	// WatchImportJob streams the job whenever its state or progress changes, until it
+	// finishes.
+	WatchImportJob(ctx context.Context, in *GetImportJobRequest, opts ...grpc.CallOption) (ImportService_WatchImportJobClient, error)
+}
+
+type importServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewImportServiceClient(cc grpc.ClientConnInterface) ImportServiceClient {
+	return &importServiceClient{cc}
+}
+
+func (c *importServiceClient) ImportFile(ctx context.Context, in *ImportFileRequest, opts ...grpc.CallOption) (*ImportJob, error) {
+	out := new(ImportJob)
+	err := c.cc.Invoke(ctx, "/icebox.v1.ImportService/ImportFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *importServiceClient) GetImportJob(ctx context.Context, in *GetImportJobRequest, opts ...grpc.CallOption) (*ImportJob, error) {
+	out := new(ImportJob)
+	err := c.cc.Invoke(ctx, "/icebox.v1.ImportService/GetImportJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *importServiceClient) ListImportJobs(ctx context.Context, in *ListImportJobsRequest, opts ...grpc.CallOption) (*ListImportJobsResponse, error) {
+	out := new(ListImportJobsResponse)
+	err := c.cc.Invoke(ctx, "/icebox.v1.ImportService/ListImportJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *importServiceClient) CancelImportJob(ctx context.Context, in *CancelImportJobRequest, opts ...grpc.CallOption) (*ImportJob, error) {
+	out := new(ImportJob)
+	err := c.cc.Invoke(ctx, "/icebox.v1.ImportService/CancelImportJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *importServiceClient) WatchImportJob(ctx context.Context, in *GetImportJobRequest, opts ...grpc.CallOption) (ImportService_WatchImportJobClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ImportService_ServiceDesc.Streams[0], "/icebox.v1.ImportService/WatchImportJob", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &importServiceWatchImportJobClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ImportService_WatchImportJobClient interface {
+	Recv() (*ImportJob, error)
+	grpc.ClientStream
+}
+
+type importServiceWatchImportJobClient struct {
+	grpc.ClientStream
+}
+
+func (x *importServiceWatchImportJobClient) Recv() (*ImportJob, error) {
+	m := new(ImportJob)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ImportServiceServer is the server API for ImportService service.
+// All implementations must embed UnimplementedImportServiceServer
+// for forward compatibility
+type ImportServiceServer interface {
+	// ImportFile queues an import of a file readable by the server and returns its job.
+	ImportFile(context.Context, *ImportFileRequest) (*ImportJob, error)
+	// GetImportJob returns the state and progress of an import job.
+	GetImportJob(context.Context, *GetImportJobRequest) (*ImportJob, error)
+	// ListImportJobs lists import jobs, most recently submitted first.
+	ListImportJobs(context.Context, *ListImportJobsRequest) (*ListImportJobsResponse, error)
+	// CancelImportJob cancels a queued or running import job.
+	CancelImportJob(context.Context, *CancelImportJobRequest) (*ImportJob, error)
+	// WatchImportJob streams the job whenever its state or progress changes, until it
+	// finishes.
+	WatchImportJob(*GetImportJobRequest, ImportService_WatchImportJobServer) error
+	mustEmbedUnimplementedImportServiceServer()
+}
+
+// UnimplementedImportServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedImportServiceServer struct {
+}
+
+func (UnimplementedImportServiceServer) ImportFile(context.Context, *ImportFileRequest) (*ImportJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportFile not implemented")
+}
+func (UnimplementedImportServiceServer) GetImportJob(context.Context, *GetImportJobRequest) (*ImportJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetImportJob not implemented")
+}
+func (UnimplementedImportServiceServer) ListImportJobs(context.Context, *ListImportJobsRequest) (*ListImportJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListImportJobs not implemented")
+}
+func (UnimplementedImportServiceServer) CancelImportJob(context.Context, *CancelImportJobRequest) (*ImportJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelImportJob not implemented")
+}
+func (UnimplementedImportServiceServer) WatchImportJob(*GetImportJobRequest, ImportService_WatchImportJobServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchImportJob not implemented")
+}
+func (UnimplementedImportServiceServer) mustEmbedUnimplementedImportServiceServer() {}
+
+// UnsafeImportServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ImportServiceServer will
+// result in compilation errors.
+type UnsafeImportServiceServer interface {
+	mustEmbedUnimplementedImportServiceServer()
+}
+
+func RegisterImportServiceServer(s grpc.ServiceRegistrar, srv ImportServiceServer) {
+	s.RegisterService(&ImportService_ServiceDesc, srv)
+}
+
+func _ImportService_ImportFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImportServiceServer).ImportFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/icebox.v1.ImportService/ImportFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImportServiceServer).ImportFile(ctx, req.(*ImportFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImportService_GetImportJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetImportJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImportServiceServer).GetImportJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/icebox.v1.ImportService/GetImportJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImportServiceServer).GetImportJob(ctx, req.(*GetImportJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImportService_ListImportJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListImportJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImportServiceServer).ListImportJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/icebox.v1.ImportService/ListImportJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImportServiceServer).ListImportJobs(ctx, req.(*ListImportJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImportService_CancelImportJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelImportJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImportServiceServer).CancelImportJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/icebox.v1.ImportService/CancelImportJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImportServiceServer).CancelImportJob(ctx, req.(*CancelImportJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImportService_WatchImportJob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetImportJobRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ImportServiceServer).WatchImportJob(m, &importServiceWatchImportJobServer{stream})
+}
+
+type ImportService_WatchImportJobServer interface {
+	Send(*ImportJob) error
+	grpc.ServerStream
+}
+
+type importServiceWatchImportJobServer struct {
+	grpc.ServerStream
+}
+
+func (x *importServiceWatchImportJobServer) Send(m *ImportJob) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// ImportService_ServiceDesc is the grpc.ServiceDesc for ImportService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ImportService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "icebox.v1.ImportService",
+	HandlerType: (*ImportServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ImportFile",
+			Handler:    _ImportService_ImportFile_Handler,
+		},
+		{
+			MethodName: "GetImportJob",
+			Handler:    _ImportService_GetImportJob_Handler,
+		},
+		{
+			MethodName: "ListImportJobs",
+			Handler:    _ImportService_ListImportJobs_Handler,
+		},
+		{
+			MethodName: "CancelImportJob",
+			Handler:    _ImportService_CancelImportJob_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchImportJob",
+			Handler:       _ImportService_WatchImportJob_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "icebox/v1/icebox.proto",
+}
+
+// TimeTravelServiceClient is the client API for TimeTravelService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TimeTravelServiceClient interface {
+	// ListSnapshots lists the snapshots of a table and the log of when each became
+	// current.
+	ListSnapshots(ctx context.Context, in *ListSnapshotsRequest, opts ...grpc.CallOption) (*ListSnapshotsResponse, error)
+	// QueryAsOf runs a query against a table as of a snapshot or point in time and
+	// streams its result as Arrow IPC record batches.
+	QueryAsOf(ctx context.Context, in *QueryAsOfRequest, opts ...grpc.CallOption) (TimeTravelService_QueryAsOfClient, error)
+}
+
+type timeTravelServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTimeTravelServiceClient(cc grpc.ClientConnInterface) TimeTravelServiceClient {
+	return &timeTravelServiceClient{cc}
+}
+
+func (c *timeTravelServiceClient) ListSnapshots(ctx context.Context, in *ListSnapshotsRequest, opts ...grpc.CallOption) (*ListSnapshotsResponse, error) {
+	out := new(ListSnapshotsResponse)
+	err := c.cc.Invoke(ctx, "/icebox.v1.TimeTravelService/ListSnapshots", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timeTravelServiceClient) QueryAsOf(ctx context.Context, in *QueryAsOfRequest, opts ...grpc.CallOption) (TimeTravelService_QueryAsOfClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TimeTravelService_ServiceDesc.Streams[0], "/icebox.v1.TimeTravelService/QueryAsOf", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &timeTravelServiceQueryAsOfClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TimeTravelService_QueryAsOfClient interface {
+	Recv() (*QueryResultChunk, error)
+	grpc.ClientStream
+}
+
+type timeTravelServiceQueryAsOfClient struct {
+	grpc.ClientStream
+}
+
+func (x *timeTravelServiceQueryAsOfClient) Recv() (*QueryResultChunk, error) {
+	m := new(QueryResultChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TimeTravelServiceServer is the server API for TimeTravelService service.
+// All implementations must embed UnimplementedTimeTravelServiceServer
+// for forward compatibility
+type TimeTravelServiceServer interface {
+	// ListSnapshots lists the snapshots of a table and the log of when each became
+	// current.
+	ListSnapshots(context.Context, *ListSnapshotsRequest) (*ListSnapshotsResponse, error)
+	// QueryAsOf runs a query against a table as of a snapshot or point in time and
+	// streams its result as Arrow IPC record batches.
+	QueryAsOf(*QueryAsOfRequest, TimeTravelService_QueryAsOfServer) error
+	mustEmbedUnimplementedTimeTravelServiceServer()
+}
+
+// UnimplementedTimeTravelServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedTimeTravelServiceServer struct {
+}
+
+func (UnimplementedTimeTravelServiceServer) ListSnapshots(context.Context, *ListSnapshotsRequest) (*ListSnapshotsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSnapshots not implemented")
+}
+func (UnimplementedTimeTravelServiceServer) QueryAsOf(*QueryAsOfRequest, TimeTravelService_QueryAsOfServer) error {
+	return status.Errorf(codes.Unimplemented, "method QueryAsOf not implemented")
+}
+func (UnimplementedTimeTravelServiceServer) mustEmbedUnimplementedTimeTravelServiceServer() {}
+
+// UnsafeTimeTravelServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TimeTravelServiceServer will
+// result in compilation errors.
+type UnsafeTimeTravelServiceServer interface {
+	mustEmbedUnimplementedTimeTravelServiceServer()
+}
+
+func RegisterTimeTravelServiceServer(s grpc.ServiceRegistrar, srv TimeTravelServiceServer) {
+	s.RegisterService(&TimeTravelService_ServiceDesc, srv)
+}
+
+func _TimeTravelService_ListSnapshots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSnapshotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimeTravelServiceServer).ListSnapshots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/icebox.v1.TimeTravelService/ListSnapshots",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimeTravelServiceServer).ListSnapshots(ctx, req.(*ListSnapshotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimeTravelService_QueryAsOf_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(QueryAsOfRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TimeTravelServiceServer).QueryAsOf(m, &timeTravelServiceQueryAsOfServer{stream})
+}
+
+type TimeTravelService_QueryAsOfServer interface {
+	Send(*QueryResultChunk) error
+	grpc.ServerStream
+}
+
+type timeTravelServiceQueryAsOfServer struct {
+	grpc.ServerStream
+}
+
+func (x *timeTravelServiceQueryAsOfServer) Send(m *QueryResultChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// TimeTravelService_ServiceDesc is the grpc.ServiceDesc for TimeTravelService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TimeTravelService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "icebox.v1.TimeTravelService",
+	HandlerType: (*TimeTravelServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListSnapshots",
+			Handler:    _TimeTravelService_ListSnapshots_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "QueryAsOf",
+			Handler:       _TimeTravelService_QueryAsOf_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "icebox/v1/icebox.proto",
+}
@@ -0,0 +1,339 @@
+package tableops
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/iceberg-go"
+	icebergio "github.com/apache/iceberg-go/io"
+	"github.com/apache/iceberg-go/table"
+	"github.com/google/uuid"
+)
+
+// RewriteManifestsOptions configures a manifest rewrite
+type RewriteManifestsOptions struct {
+	// TargetSizeBytes is the approximate size of each rewritten manifest.
+	// Defaults to the table's commit.manifest.target-size-bytes property.
+	TargetSizeBytes int64
+	// SpecID restricts the rewrite to manifests of a single partition spec
+	SpecID *int
+	// SnapshotProperties are added to the summary of the replace snapshot
+	SnapshotProperties iceberg.Properties
+}
+
+// RewriteManifestsResult describes the outcome of a manifest rewrite
+type RewriteManifestsResult struct {
+	SnapshotID         int64
+	RewrittenManifests int
+	AddedManifests     int
+	KeptManifests      int
+	ProcessedEntries   int
+	Table              *table.Table
+}
+
+// specEntries holds the live entries collected from the manifests of one partition spec
+type specEntries struct {
+	spec      iceberg.PartitionSpec
+	manifests []iceberg.ManifestFile
+	entries   []iceberg.ManifestEntry
+	bytes     int64
+}
+
+// RewriteManifests merges the data manifests of the current snapshot per partition
+// spec and clusters their entries by partition, committing the result as a replace
+// snapshot. Data files are not touched, and delete manifests are carried over as-is.
+func (w *Writer) RewriteManifests(ctx context.Context, tbl *table.Table, opts *RewriteManifestsOptions) (*RewriteManifestsResult, error) {
+	if opts == nil {
+		opts = &RewriteManifestsOptions{}
+	}
+
+	result := &RewriteManifestsResult{Table: tbl}
+
+	current := tbl.CurrentSnapshot()
+	if current == nil {
+		return result, nil
+	}
+
+	wfs, ok := tbl.FS().(icebergio.WriteFileIO)
+	if !ok {
+		return nil, fmt.Errorf("table file IO does not support writing")
+	}
+
+	manifests, err := current.Manifests(tbl.FS())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest list: %w", err)
+	}
+
+	metadata := tbl.Metadata()
+	targetSize := opts.TargetSizeBytes
+	if targetSize <= 0 {
+		targetSize = int64(tbl.Properties().GetInt(table.ManifestTargetSizeBytesKey, table.ManifestTargetSizeBytesDefault))
+	}
+
+	// Group data manifests by partition spec, keeping everything else unchanged
+	groups := make(map[int]*specEntries)
+	var kept []iceberg.ManifestFile
+	for _, manifest := range manifests {
+		specID := int(manifest.PartitionSpecID())
+		if manifest.ManifestContent() != iceberg.ManifestContentData || (opts.SpecID != nil && *opts.SpecID != specID) {
+			kept = append(kept, manifest)
+			continue
+		}
+
+		group, exists := groups[specID]
+		if !exists {
+			spec, err := partitionSpecByID(metadata, specID)
+			if err != nil {
+				return nil, err
+			}
+			group = &specEntries{spec: spec}
+			groups[specID] = group
+		}
+		group.manifests = append(group.manifests, manifest)
+	}
+
+	snapshotID := newSnapshotID()
+	commitUUID := uuid.New()
+	locProvider, err := tbl.LocationProvider()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get location provider: %w", err)
+	}
+
+	specIDs := make([]int, 0, len(groups))
+	for specID := range groups {
+		specIDs = append(specIDs, specID)
+	}
+	sort.Ints(specIDs)
+
+	var newManifests []iceberg.ManifestFile
+	for _, specID := range specIDs {
+		group := groups[specID]
+
+		// A single manifest per spec is already as compact as it gets
+		if len(group.manifests) < 2 {
+			kept = append(kept, group.manifests...)
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		for _, manifest := range group.manifests {
+			entries, err := manifest.FetchEntries(tbl.FS(), true)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read manifest %s: %w", manifest.FilePath(), err)
+			}
+			group.entries = append(group.entries, entries...)
+			group.bytes += manifest.Length()
+		}
+		result.RewrittenManifests += len(group.manifests)
+		result.ProcessedEntries += len(group.entries)
+
+		if len(group.entries) == 0 {
+			continue
+		}
+
+		for _, bin := range clusterByPartition(group, targetSize) {
+			location := locProvider.NewMetadataLocation(fmt.Sprintf("%s-m%d.avro", commitUUID, len(newManifests)))
+			manifest, err := writeExistingManifest(wfs, location, metadata, group.spec, snapshotID, bin)
+			if err != nil {
+				return nil, err
+			}
+			newManifests = append(newManifests, manifest)
+		}
+	}
+
+	result.AddedManifests = len(newManifests)
+	result.KeptManifests = len(kept)
+	if result.RewrittenManifests == 0 {
+		return result, nil
+	}
+
+	// Write the manifest list for the replace snapshot
+	var sequenceNumber int64
+	if metadata.Version() > 1 {
+		sequenceNumber = metadata.LastSequenceNumber() + 1
+	}
+	parentID := current.SnapshotID
+	manifestListLocation := locProvider.NewMetadataLocation(fmt.Sprintf("snap-%d-0-%s.avro", snapshotID, commitUUID))
+
+	out, err := wfs.Create(manifestListLocation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create manifest list: %w", err)
+	}
+	allManifests := append(newManifests, kept...)
+	if err := iceberg.WriteManifestList(metadata.Version(), out, snapshotID, &parentID, &sequenceNumber, allManifests); err != nil {
+		out.Close()
+		return nil, fmt.Errorf("failed to write manifest list: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close manifest list: %w", err)
+	}
+
+	// A manifest rewrite does not change table contents, so carry the totals forward
+	summaryProps := iceberg.Properties{}
+	if current.Summary != nil {
+		for k, v := range current.Summary.Properties {
+			if strings.HasPrefix(k, "total-") {
+				summaryProps[k] = v
+			}
+		}
+	}
+	summaryProps["manifests-created"] = strconv.Itoa(result.AddedManifests)
+	summaryProps["manifests-replaced"] = strconv.Itoa(result.RewrittenManifests)
+	summaryProps["manifests-kept"] = strconv.Itoa(result.KeptManifests)
+	summaryProps["entries-processed"] = strconv.Itoa(result.ProcessedEntries)
+	for k, v := range opts.SnapshotProperties {
+		summaryProps[k] = v
+	}
+
+	schemaID := metadata.CurrentSchema().ID
+	snapshot := table.Snapshot{
+		SnapshotID:       snapshotID,
+		ParentSnapshotID: &parentID,
+		SequenceNumber:   sequenceNumber,
+		TimestampMs:      time.Now().UnixMilli(),
+		ManifestList:     manifestListLocation,
+		Summary:          &table.Summary{Operation: table.OpReplace, Properties: summaryProps},
+		SchemaID:         &schemaID,
+	}
+
+	updates := []table.Update{
+		table.NewAddSnapshotUpdate(&snapshot),
+		table.NewSetSnapshotRefUpdate("main", snapshotID, table.BranchRef, -1, -1, -1),
+	}
+	reqs := []table.Requirement{
+		table.AssertRefSnapshotID("main", &parentID),
+	}
+
+	newMetadata, newLocation, err := w.catalog.CommitTable(ctx, tbl, reqs, updates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit manifest rewrite: %w", err)
+	}
+
+	result.SnapshotID = snapshotID
+	result.Table = table.New(tbl.Identifier(), newMetadata, newLocation, tbl.FS(), w.catalog)
+	return result, nil
+}
+
+// clusterByPartition sorts entries by partition and splits them into bins of roughly
+// targetSize bytes. Bins are only split on partition boundaries so a partition's files
+// land in a single manifest whenever it fits.
+func clusterByPartition(group *specEntries, targetSize int64) [][]iceberg.ManifestEntry {
+	keys := make([]string, len(group.entries))
+	order := make([]int, len(group.entries))
+	for i, entry := range group.entries {
+		keys[i] = partitionKey(group.spec, entry.DataFile().Partition())
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ka, kb := keys[order[a]], keys[order[b]]
+		if ka != kb {
+			return ka < kb
+		}
+		return group.entries[order[a]].DataFile().FilePath() < group.entries[order[b]].DataFile().FilePath()
+	})
+
+	// Estimate entry size from the manifests being replaced
+	entrySize := max(group.bytes/int64(len(group.entries)), 1)
+
+	var (
+		bins    [][]iceberg.ManifestEntry
+		bin     []iceberg.ManifestEntry
+		binSize int64
+	)
+	for i, idx := range order {
+		if len(bin) > 0 && binSize+entrySize > targetSize && keys[idx] != keys[order[i-1]] {
+			bins = append(bins, bin)
+			bin, binSize = nil, 0
+		}
+		bin = append(bin, group.entries[idx])
+		binSize += entrySize
+	}
+	if len(bin) > 0 {
+		bins = append(bins, bin)
+	}
+
+	return bins
+}
+
+// partitionKey renders a data file's partition tuple in spec field order
+func partitionKey(spec iceberg.PartitionSpec, partition map[int]any) string {
+	parts := make([]string, 0, spec.NumFields())
+	for field := range spec.Fields() {
+		parts = append(parts, fmt.Sprintf("%v", partition[field.FieldID]))
+	}
+	return strings.Join(parts, "/")
+}
+
+// writeExistingManifest writes entries as EXISTING entries of a new manifest file
+func writeExistingManifest(wfs icebergio.WriteFileIO, location string, metadata table.Metadata, spec iceberg.PartitionSpec, snapshotID int64, entries []iceberg.ManifestEntry) (iceberg.ManifestFile, error) {
+	out, err := wfs.Create(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create manifest %s: %w", location, err)
+	}
+	defer out.Close()
+
+	counter := &countingWriter{w: out}
+	writer, err := iceberg.NewManifestWriter(metadata.Version(), counter, spec, metadata.CurrentSchema(), snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create manifest writer: %w", err)
+	}
+
+	for _, entry := range entries {
+		if err := writer.Existing(entry); err != nil {
+			return nil, fmt.Errorf("failed to write manifest entry: %w", err)
+		}
+	}
+
+	// Close before taking the length so the final block is counted
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close manifest writer: %w", err)
+	}
+
+	return writer.ToManifestFile(location, counter.n)
+}
+
+// partitionSpecByID finds a partition spec in the table metadata
+func partitionSpecByID(metadata table.Metadata, specID int) (iceberg.PartitionSpec, error) {
+	for _, spec := range metadata.PartitionSpecs() {
+		if spec.ID() == specID {
+			return spec, nil
+		}
+	}
+	return iceberg.PartitionSpec{}, fmt.Errorf("partition spec %d not found in table metadata", specID)
+}
+
+// newSnapshotID generates a positive random snapshot ID, following iceberg-go
+func newSnapshotID() int64 {
+	id := uuid.New()
+	var out [8]byte
+	for i := range 8 {
+		out[i] = id[i] ^ id[i+8]
+	}
+
+	snapshotID := int64(binary.LittleEndian.Uint64(out[:]))
+	if snapshotID < 0 {
+		snapshotID = -snapshotID
+	}
+	return snapshotID
+}
+
+// countingWriter counts bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
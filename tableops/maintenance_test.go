@@ -0,0 +1,114 @@
+package tableops
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/TFMV/icebox/catalog"
+	"github.com/TFMV/icebox/config"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteManifests(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		Name: "test-catalog",
+		Catalog: config.CatalogConfig{
+			Type: "sqlite",
+			SQLite: &config.SQLiteConfig{
+				Path: filepath.Join(tempDir, "catalog.db"),
+			},
+		},
+		Storage: config.StorageConfig{
+			FileSystem: &config.FileSystemConfig{
+				RootPath: filepath.Join(tempDir, "data"),
+			},
+		},
+	}
+
+	cat, err := catalog.NewCatalog(cfg)
+	require.NoError(t, err)
+	defer cat.Close()
+
+	ctx := context.Background()
+	identifier := table.Identifier{"default", "events"}
+	require.NoError(t, cat.CreateNamespace(ctx, table.Identifier{"default"}, iceberg.Properties{}))
+
+	schema := iceberg.NewSchema(0, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64})
+	_, err = cat.CreateTable(ctx, identifier, schema)
+	require.NoError(t, err)
+
+	writer := NewWriter(cat)
+
+	// Nothing to rewrite before the first snapshot
+	tbl, err := cat.LoadTable(ctx, identifier, nil)
+	require.NoError(t, err)
+	result, err := writer.RewriteManifests(ctx, tbl, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.RewrittenManifests)
+
+	// Every append adds a manifest
+	arrowSchema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true}}, nil)
+	for i := 0; i < 3; i++ {
+		builder := array.NewInt64Builder(memory.DefaultAllocator)
+		builder.AppendValues([]int64{int64(i * 3), int64(i*3 + 1), int64(i*3 + 2)}, nil)
+		arr := builder.NewArray()
+		builder.Release()
+
+		record := array.NewRecord(arrowSchema, []arrow.Array{arr}, 3)
+		arrowTable := array.NewTableFromRecords(arrowSchema, []arrow.Record{record})
+
+		tbl, err := cat.LoadTable(ctx, identifier, nil)
+		require.NoError(t, err)
+		require.NoError(t, writer.WriteArrowTable(ctx, tbl, arrowTable, nil))
+
+		arrowTable.Release()
+		record.Release()
+		arr.Release()
+	}
+
+	tbl, err = cat.LoadTable(ctx, identifier, nil)
+	require.NoError(t, err)
+	manifests, err := tbl.CurrentSnapshot().Manifests(tbl.FS())
+	require.NoError(t, err)
+	require.Len(t, manifests, 3)
+	parentID := tbl.CurrentSnapshot().SnapshotID
+
+	result, err = writer.RewriteManifests(ctx, tbl, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.RewrittenManifests)
+	assert.Equal(t, 1, result.AddedManifests)
+	assert.Equal(t, 3, result.ProcessedEntries)
+
+	// The rewrite is committed as a replace snapshot with a single manifest
+	tbl, err = cat.LoadTable(ctx, identifier, nil)
+	require.NoError(t, err)
+	snapshot := tbl.CurrentSnapshot()
+	require.NotNil(t, snapshot)
+	assert.Equal(t, result.SnapshotID, snapshot.SnapshotID)
+	assert.Equal(t, parentID, *snapshot.ParentSnapshotID)
+	assert.Equal(t, table.OpReplace, snapshot.Summary.Operation)
+
+	manifests, err = snapshot.Manifests(tbl.FS())
+	require.NoError(t, err)
+	require.Len(t, manifests, 1)
+	assert.Equal(t, int32(3), manifests[0].ExistingDataFiles())
+
+	// Table contents are unchanged
+	scanned, err := tbl.Scan().ToArrowTable(ctx)
+	require.NoError(t, err)
+	defer scanned.Release()
+	assert.Equal(t, int64(9), scanned.NumRows())
+
+	// A second rewrite has nothing left to merge
+	result, err = writer.RewriteManifests(ctx, tbl, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.RewrittenManifests)
+}
@@ -0,0 +1,282 @@
+package tableops
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+
+	"github.com/twmb/murmur3"
+)
+
+// Puffin files hold statistics blobs that are too large for table metadata.
+// See https://iceberg.apache.org/puffin-spec/
+const (
+	puffinMagic = "PFA1"
+
+	// ThetaSketchBlobType is the Puffin blob type for Apache DataSketches theta sketches
+	ThetaSketchBlobType = "apache-datasketches-theta-v1"
+
+	// puffinNDVProperty is the blob property holding the estimated number of distinct values
+	puffinNDVProperty = "ndv"
+)
+
+// PuffinBlobMetadata describes a blob in the footer of a Puffin file
+type PuffinBlobMetadata struct {
+	Type             string            `json:"type"`
+	Fields           []int             `json:"fields"`
+	SnapshotID       int64             `json:"snapshot-id"`
+	SequenceNumber   int64             `json:"sequence-number"`
+	Offset           int64             `json:"offset"`
+	Length           int64             `json:"length"`
+	CompressionCodec string            `json:"compression-codec,omitempty"`
+	Properties       map[string]string `json:"properties,omitempty"`
+}
+
+// PuffinFooter is the JSON payload stored in the footer of a Puffin file
+type PuffinFooter struct {
+	Blobs      []PuffinBlobMetadata `json:"blobs"`
+	Properties map[string]string    `json:"properties,omitempty"`
+}
+
+// PuffinBlob is a blob to be written to a Puffin file
+type PuffinBlob struct {
+	Type           string
+	Fields         []int
+	SnapshotID     int64
+	SequenceNumber int64
+	Properties     map[string]string
+	Data           []byte
+}
+
+// WritePuffin writes the blobs as an uncompressed Puffin file and returns the footer
+func WritePuffin(w io.Writer, blobs []PuffinBlob, properties map[string]string) (*PuffinFooter, error) {
+	var buf bytes.Buffer
+	buf.WriteString(puffinMagic)
+
+	footer := &PuffinFooter{Properties: properties}
+	for _, blob := range blobs {
+		footer.Blobs = append(footer.Blobs, PuffinBlobMetadata{
+			Type:           blob.Type,
+			Fields:         blob.Fields,
+			SnapshotID:     blob.SnapshotID,
+			SequenceNumber: blob.SequenceNumber,
+			Offset:         int64(buf.Len()),
+			Length:         int64(len(blob.Data)),
+			Properties:     blob.Properties,
+		})
+		buf.Write(blob.Data)
+	}
+
+	payload, err := json.Marshal(footer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode puffin footer: %w", err)
+	}
+
+	// Footer: magic, payload, payload size, flags, magic
+	buf.WriteString(puffinMagic)
+	buf.Write(payload)
+	_ = binary.Write(&buf, binary.LittleEndian, int32(len(payload)))
+	buf.Write([]byte{0, 0, 0, 0})
+	buf.WriteString(puffinMagic)
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return nil, fmt.Errorf("failed to write puffin file: %w", err)
+	}
+	return footer, nil
+}
+
+// ReadPuffinFooter reads the footer of a Puffin file
+func ReadPuffinFooter(r io.ReaderAt, size int64) (*PuffinFooter, error) {
+	// Trailing payload size, flags and magic
+	const tailSize = 12
+	if size < int64(2*len(puffinMagic)+tailSize) {
+		return nil, fmt.Errorf("file too small to be a puffin file")
+	}
+
+	tail := make([]byte, tailSize)
+	if _, err := r.ReadAt(tail, size-tailSize); err != nil {
+		return nil, fmt.Errorf("failed to read puffin footer: %w", err)
+	}
+	if string(tail[8:]) != puffinMagic {
+		return nil, fmt.Errorf("invalid puffin footer magic")
+	}
+	if tail[4]&0x1 != 0 {
+		return nil, fmt.Errorf("compressed puffin footers are not supported")
+	}
+
+	payloadSize := int64(binary.LittleEndian.Uint32(tail[:4]))
+	payloadStart := size - tailSize - payloadSize
+	if payloadStart < int64(2*len(puffinMagic)) {
+		return nil, fmt.Errorf("invalid puffin footer payload size %d", payloadSize)
+	}
+
+	payload := make([]byte, payloadSize)
+	if _, err := r.ReadAt(payload, payloadStart); err != nil {
+		return nil, fmt.Errorf("failed to read puffin footer payload: %w", err)
+	}
+
+	var footer PuffinFooter
+	if err := json.Unmarshal(payload, &footer); err != nil {
+		return nil, fmt.Errorf("failed to decode puffin footer: %w", err)
+	}
+	return &footer, nil
+}
+
+// Theta sketch constants, compatible with Apache DataSketches
+const (
+	thetaDefaultSeed    = 9001
+	thetaDefaultLgK     = 12
+	thetaMax            = math.MaxInt64
+	thetaSerialVersion  = 3
+	thetaCompactFamily  = 3
+	thetaFlagReadOnly   = 1 << 1
+	thetaFlagEmpty      = 1 << 2
+	thetaFlagCompact    = 1 << 3
+	thetaFlagOrdered    = 1 << 4
+	thetaFlagSingleItem = 1 << 5
+)
+
+// thetaSketch is a k-minimum-values sketch that estimates the number of distinct
+// values. It hashes values the same way as Apache DataSketches and serializes to the
+// DataSketches compact format, so the blobs can be read by other Iceberg engines.
+type thetaSketch struct {
+	k      int
+	theta  uint64
+	hashes map[uint64]struct{}
+}
+
+func newThetaSketch() *thetaSketch {
+	return &thetaSketch{
+		k:      1 << thetaDefaultLgK,
+		theta:  thetaMax,
+		hashes: make(map[uint64]struct{}),
+	}
+}
+
+// Update adds a value, given in its Iceberg single-value binary serialization
+func (s *thetaSketch) Update(value []byte) {
+	h1, _ := murmur3.SeedSum128(thetaDefaultSeed, thetaDefaultSeed, value)
+	hash := h1 >> 1
+	if hash == 0 || hash >= s.theta {
+		return
+	}
+	s.hashes[hash] = struct{}{}
+
+	// Rebuild lazily so updates stay cheap
+	if len(s.hashes) >= 2*s.k {
+		s.rebuild()
+	}
+}
+
+// rebuild keeps only the k smallest hashes and lowers theta accordingly
+func (s *thetaSketch) rebuild() {
+	if len(s.hashes) <= s.k {
+		return
+	}
+	sorted := s.sortedHashes()
+	s.theta = sorted[s.k]
+	for _, hash := range sorted[s.k:] {
+		delete(s.hashes, hash)
+	}
+}
+
+func (s *thetaSketch) sortedHashes() []uint64 {
+	sorted := make([]uint64, 0, len(s.hashes))
+	for hash := range s.hashes {
+		sorted = append(sorted, hash)
+	}
+	slices.Sort(sorted)
+	return sorted
+}
+
+// Estimate returns the estimated number of distinct values
+func (s *thetaSketch) Estimate() float64 {
+	s.rebuild()
+	if s.theta == thetaMax {
+		return float64(len(s.hashes))
+	}
+	return float64(len(s.hashes)) / (float64(s.theta) / float64(thetaMax))
+}
+
+// Serialize encodes the sketch in the DataSketches compact, ordered format
+func (s *thetaSketch) Serialize() []byte {
+	s.rebuild()
+	hashes := s.sortedHashes()
+	estimating := s.theta < thetaMax
+
+	preambleLongs := byte(1)
+	flags := byte(thetaFlagReadOnly | thetaFlagCompact | thetaFlagOrdered)
+	switch {
+	case estimating:
+		preambleLongs = 3
+	case len(hashes) == 0:
+		flags |= thetaFlagEmpty
+	case len(hashes) == 1:
+		flags |= thetaFlagSingleItem
+	default:
+		preambleLongs = 2
+	}
+
+	var buf bytes.Buffer
+	buf.Write([]byte{preambleLongs, thetaSerialVersion, thetaCompactFamily, 0, 0, flags})
+	_ = binary.Write(&buf, binary.LittleEndian, thetaSeedHash(thetaDefaultSeed))
+	if preambleLongs > 1 {
+		_ = binary.Write(&buf, binary.LittleEndian, uint32(len(hashes)))
+		_ = binary.Write(&buf, binary.LittleEndian, float32(1.0))
+	}
+	if preambleLongs > 2 {
+		_ = binary.Write(&buf, binary.LittleEndian, s.theta)
+	}
+	for _, hash := range hashes {
+		_ = binary.Write(&buf, binary.LittleEndian, hash)
+	}
+	return buf.Bytes()
+}
+
+// thetaEstimateFromCompact returns the distinct value estimate of a serialized compact sketch
+func thetaEstimateFromCompact(data []byte) (float64, error) {
+	if len(data) < 8 || data[1] != thetaSerialVersion || data[2] != thetaCompactFamily {
+		return 0, fmt.Errorf("unsupported theta sketch serialization")
+	}
+
+	preambleLongs := int(data[0])
+	flags := data[5]
+	if flags&thetaFlagEmpty != 0 {
+		return 0, nil
+	}
+
+	count := 1
+	theta := uint64(thetaMax)
+	if preambleLongs > 1 {
+		if len(data) < 16 {
+			return 0, fmt.Errorf("truncated theta sketch")
+		}
+		count = int(binary.LittleEndian.Uint32(data[8:12]))
+	}
+	if preambleLongs > 2 {
+		if len(data) < 24 {
+			return 0, fmt.Errorf("truncated theta sketch")
+		}
+		theta = binary.LittleEndian.Uint64(data[16:24])
+	}
+	if len(data) < preambleLongs*8+count*8 {
+		return 0, fmt.Errorf("truncated theta sketch")
+	}
+
+	if theta == thetaMax {
+		return float64(count), nil
+	}
+	return float64(count) / (float64(theta) / float64(thetaMax)), nil
+}
+
+// thetaSeedHash is the 16-bit seed fingerprint DataSketches stores in every sketch
+func thetaSeedHash(seed int64) uint16 {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(seed))
+	h1, _ := murmur3.SeedSum128(0, 0, b[:])
+	return uint16(h1 & 0xffff)
+}
@@ -0,0 +1,160 @@
+package tableops
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	icebergio "github.com/apache/iceberg-go/io"
+	"github.com/apache/iceberg-go/table"
+)
+
+// FileRef is a file referenced by table metadata and its size in bytes
+type FileRef struct {
+	Path string
+	Size int64
+}
+
+// TableFiles lists every file reachable from a table's metadata, across all snapshots
+type TableFiles struct {
+	DataFiles     []FileRef
+	ManifestFiles []FileRef
+	ManifestLists []FileRef
+	MetadataFiles []FileRef
+}
+
+// Count returns the total number of files
+func (f *TableFiles) Count() int {
+	return len(f.DataFiles) + len(f.ManifestFiles) + len(f.ManifestLists) + len(f.MetadataFiles)
+}
+
+// TotalSize returns the total size of all files in bytes
+func (f *TableFiles) TotalSize() int64 {
+	var total int64
+	for _, group := range [][]FileRef{f.DataFiles, f.ManifestFiles, f.ManifestLists, f.MetadataFiles} {
+		for _, ref := range group {
+			total += ref.Size
+		}
+	}
+	return total
+}
+
+// PurgeResult describes the outcome of deleting a table's files
+type PurgeResult struct {
+	DeletedFiles   int
+	ReclaimedBytes int64
+	Failed         map[string]error
+}
+
+// CollectTableFiles walks all snapshots of a table and collects its data files,
+// delete files, manifests, manifest lists and metadata files
+func CollectTableFiles(tbl *table.Table) (*TableFiles, error) {
+	fs := tbl.FS()
+	files := &TableFiles{}
+	seen := make(map[string]struct{})
+
+	add := func(group *[]FileRef, path string, size int64) {
+		if path == "" {
+			return
+		}
+		if _, ok := seen[path]; ok {
+			return
+		}
+		seen[path] = struct{}{}
+		*group = append(*group, FileRef{Path: path, Size: size})
+	}
+
+	for _, snapshot := range tbl.Metadata().Snapshots() {
+		if snapshot.ManifestList == "" {
+			continue
+		}
+		add(&files.ManifestLists, snapshot.ManifestList, fileSize(fs, snapshot.ManifestList))
+
+		manifests, err := snapshot.Manifests(fs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest list of snapshot %d: %w", snapshot.SnapshotID, err)
+		}
+
+		for _, manifest := range manifests {
+			if _, ok := seen[manifest.FilePath()]; ok {
+				continue
+			}
+			add(&files.ManifestFiles, manifest.FilePath(), manifest.Length())
+
+			// Deleted entries still point at files that may exist in storage
+			entries, err := manifest.FetchEntries(fs, false)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read manifest %s: %w", manifest.FilePath(), err)
+			}
+			for _, entry := range entries {
+				df := entry.DataFile()
+				add(&files.DataFiles, df.FilePath(), df.FileSizeBytes())
+			}
+		}
+	}
+
+	metadataLocation := tbl.MetadataLocation()
+	add(&files.MetadataFiles, metadataLocation, fileSize(fs, metadataLocation))
+	for entry := range tbl.Metadata().PreviousFiles() {
+		add(&files.MetadataFiles, entry.MetadataFile, fileSize(fs, entry.MetadataFile))
+	}
+	if statsFile := tbl.Properties()[StatisticsFileProperty]; statsFile != "" {
+		add(&files.MetadataFiles, statsFile, fileSize(fs, statsFile))
+	}
+
+	// Older metadata versions are not always in the metadata log. On local storage
+	// they can be found next to the current metadata file.
+	if _, isLocalFS := fs.(icebergio.LocalFS); isLocalFS {
+		metadataDir := filepath.Dir(strings.TrimPrefix(metadataLocation, "file://"))
+		prefix := strings.TrimSuffix(metadataLocation, filepath.Base(metadataLocation))
+		if entries, err := os.ReadDir(metadataDir); err == nil {
+			for _, entry := range entries {
+				if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".metadata.json") {
+					continue
+				}
+				path := prefix + entry.Name()
+				add(&files.MetadataFiles, path, fileSize(fs, path))
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// DeleteTableFiles deletes the given files through the FileIO, in order: data files,
+// manifests, manifest lists, then metadata files. Files that no longer exist are skipped
+// and other failures are collected rather than aborting the purge.
+func DeleteTableFiles(fs icebergio.IO, files *TableFiles) *PurgeResult {
+	result := &PurgeResult{Failed: make(map[string]error)}
+
+	for _, group := range [][]FileRef{files.DataFiles, files.ManifestFiles, files.ManifestLists, files.MetadataFiles} {
+		for _, ref := range group {
+			if err := fs.Remove(ref.Path); err != nil {
+				if !os.IsNotExist(err) {
+					result.Failed[ref.Path] = err
+				}
+				continue
+			}
+			result.DeletedFiles++
+			result.ReclaimedBytes += ref.Size
+		}
+	}
+
+	return result
+}
+
+// fileSize returns the size of a file through the FileIO, or 0 when it cannot be determined
+func fileSize(fs icebergio.IO, path string) int64 {
+	f, err := fs.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
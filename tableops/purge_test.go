@@ -0,0 +1,100 @@
+package tableops
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TFMV/icebox/catalog"
+	"github.com/TFMV/icebox/config"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgeTableFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		Name: "test-catalog",
+		Catalog: config.CatalogConfig{
+			Type: "sqlite",
+			SQLite: &config.SQLiteConfig{
+				Path: filepath.Join(tempDir, "catalog.db"),
+			},
+		},
+		Storage: config.StorageConfig{
+			FileSystem: &config.FileSystemConfig{
+				RootPath: filepath.Join(tempDir, "data"),
+			},
+		},
+	}
+
+	cat, err := catalog.NewCatalog(cfg)
+	require.NoError(t, err)
+	defer cat.Close()
+
+	ctx := context.Background()
+	identifier := table.Identifier{"default", "events"}
+	require.NoError(t, cat.CreateNamespace(ctx, table.Identifier{"default"}, iceberg.Properties{}))
+
+	schema := iceberg.NewSchema(0, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64})
+	_, err = cat.CreateTable(ctx, identifier, schema)
+	require.NoError(t, err)
+
+	writer := NewWriter(cat)
+	arrowSchema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true}}, nil)
+	for i := 0; i < 2; i++ {
+		builder := array.NewInt64Builder(memory.DefaultAllocator)
+		builder.AppendValues([]int64{int64(i * 2), int64(i*2 + 1)}, nil)
+		arr := builder.NewArray()
+		builder.Release()
+
+		record := array.NewRecord(arrowSchema, []arrow.Array{arr}, 2)
+		arrowTable := array.NewTableFromRecords(arrowSchema, []arrow.Record{record})
+
+		tbl, err := cat.LoadTable(ctx, identifier, nil)
+		require.NoError(t, err)
+		require.NoError(t, writer.WriteArrowTable(ctx, tbl, arrowTable, nil))
+
+		arrowTable.Release()
+		record.Release()
+		arr.Release()
+	}
+
+	tbl, err := cat.LoadTable(ctx, identifier, nil)
+	require.NoError(t, err)
+
+	// Files from both snapshots are collected, not just the current one
+	files, err := CollectTableFiles(tbl)
+	require.NoError(t, err)
+	assert.Len(t, files.DataFiles, 2)
+	assert.Len(t, files.ManifestFiles, 2)
+	assert.Len(t, files.ManifestLists, 2)
+	assert.GreaterOrEqual(t, len(files.MetadataFiles), 3)
+	assert.Positive(t, files.TotalSize())
+
+	require.NoError(t, cat.DropTable(ctx, identifier))
+
+	result := DeleteTableFiles(tbl.FS(), files)
+	assert.Empty(t, result.Failed)
+	assert.Equal(t, files.Count(), result.DeletedFiles)
+	assert.Equal(t, files.TotalSize(), result.ReclaimedBytes)
+
+	for _, group := range [][]FileRef{files.DataFiles, files.ManifestFiles, files.ManifestLists, files.MetadataFiles} {
+		for _, ref := range group {
+			_, err := os.Stat(strings.TrimPrefix(ref.Path, "file://"))
+			assert.True(t, os.IsNotExist(err), "expected %s to be deleted", ref.Path)
+		}
+	}
+
+	// Deleting again is a no-op rather than an error
+	result = DeleteTableFiles(tbl.FS(), files)
+	assert.Empty(t, result.Failed)
+	assert.Equal(t, 0, result.DeletedFiles)
+}
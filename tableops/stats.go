@@ -0,0 +1,423 @@
+package tableops
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/iceberg-go"
+	icebergio "github.com/apache/iceberg-go/io"
+	"github.com/apache/iceberg-go/table"
+	"github.com/google/uuid"
+)
+
+// iceberg-go does not model the "statistics" field of table metadata yet, so the
+// Puffin file holding the current statistics is tracked through table properties.
+const (
+	// StatisticsFileProperty is the table property holding the location of the Puffin statistics file
+	StatisticsFileProperty = "icebox.statistics.path"
+	// StatisticsSnapshotProperty is the table property holding the snapshot the statistics were computed for
+	StatisticsSnapshotProperty = "icebox.statistics.snapshot-id"
+)
+
+// ColumnStats holds the statistics of a single top-level column
+type ColumnStats struct {
+	FieldID    int
+	Name       string
+	Type       string
+	ValueCount int64
+	NullCount  int64
+	NaNCount   int64
+	// LowerBound and UpperBound are nil when no data file recorded bounds for the column
+	LowerBound iceberg.Literal
+	UpperBound iceberg.Literal
+	// DistinctCount is an estimate, nil when no estimate is available
+	DistinctCount *int64
+}
+
+// TableStats holds the statistics of a table snapshot, aggregated from its manifests
+type TableStats struct {
+	SnapshotID     int64
+	DataFiles      int
+	DeleteFiles    int
+	TotalSize      int64
+	RecordCount    int64
+	Columns        []ColumnStats
+	StatisticsFile string
+
+	sketches map[int]*thetaSketch
+}
+
+// StatsOptions configures statistics collection
+type StatsOptions struct {
+	// ComputeDistinct scans the table data to estimate distinct values per column
+	// with theta sketches. Without it, estimates are only read from a Puffin
+	// statistics file written for the snapshot.
+	ComputeDistinct bool
+}
+
+// CollectTableStats aggregates file, record and column statistics of the current snapshot
+// from its manifests. Tables without snapshots yield empty statistics.
+func CollectTableStats(ctx context.Context, tbl *table.Table, opts *StatsOptions) (*TableStats, error) {
+	if opts == nil {
+		opts = &StatsOptions{}
+	}
+
+	schema := tbl.Schema()
+	stats := &TableStats{}
+	columnIndex := make(map[int]int)
+	for _, field := range schema.Fields() {
+		if _, ok := field.Type.(iceberg.PrimitiveType); !ok {
+			continue
+		}
+		columnIndex[field.ID] = len(stats.Columns)
+		stats.Columns = append(stats.Columns, ColumnStats{
+			FieldID: field.ID,
+			Name:    field.Name,
+			Type:    field.Type.String(),
+		})
+	}
+
+	snapshot := tbl.CurrentSnapshot()
+	if snapshot == nil {
+		return stats, nil
+	}
+	stats.SnapshotID = snapshot.SnapshotID
+
+	manifests, err := snapshot.Manifests(tbl.FS())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest list: %w", err)
+	}
+
+	for _, manifest := range manifests {
+		entries, err := manifest.FetchEntries(tbl.FS(), true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest %s: %w", manifest.FilePath(), err)
+		}
+
+		for _, entry := range entries {
+			df := entry.DataFile()
+			stats.TotalSize += df.FileSizeBytes()
+			if df.ContentType() != iceberg.EntryContentData {
+				stats.DeleteFiles++
+				continue
+			}
+			stats.DataFiles++
+			stats.RecordCount += df.Count()
+
+			for fieldID, idx := range columnIndex {
+				column := &stats.Columns[idx]
+				column.ValueCount += df.ValueCounts()[fieldID]
+				column.NullCount += df.NullValueCounts()[fieldID]
+				column.NaNCount += df.NaNValueCounts()[fieldID]
+
+				field, _ := schema.FindFieldByID(fieldID)
+				if lower, ok := df.LowerBoundValues()[fieldID]; ok {
+					if lit, err := iceberg.LiteralFromBytes(field.Type, lower); err == nil {
+						if column.LowerBound == nil || compareLiterals(lit, column.LowerBound) < 0 {
+							column.LowerBound = lit
+						}
+					}
+				}
+				if upper, ok := df.UpperBoundValues()[fieldID]; ok {
+					if lit, err := iceberg.LiteralFromBytes(field.Type, upper); err == nil {
+						if column.UpperBound == nil || compareLiterals(lit, column.UpperBound) > 0 {
+							column.UpperBound = lit
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if opts.ComputeDistinct {
+		if err := stats.computeDistinctCounts(ctx, tbl); err != nil {
+			return nil, err
+		}
+	} else if err := stats.loadDistinctCounts(tbl); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// computeDistinctCounts scans the snapshot and feeds every non-null value into a theta sketch
+func (s *TableStats) computeDistinctCounts(ctx context.Context, tbl *table.Table) error {
+	s.sketches = make(map[int]*thetaSketch)
+	for _, column := range s.Columns {
+		s.sketches[column.FieldID] = newThetaSketch()
+	}
+
+	arrowSchema, records, err := tbl.Scan(table.WithSnapshotID(s.SnapshotID)).ToArrowRecords(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to scan table: %w", err)
+	}
+
+	fieldIDs := make([]int, len(arrowSchema.Fields()))
+	for i, field := range arrowSchema.Fields() {
+		fieldIDs[i] = -1
+		if schemaField, ok := tbl.Schema().FindFieldByName(field.Name); ok {
+			fieldIDs[i] = schemaField.ID
+		}
+	}
+
+	for record, err := range records {
+		if err != nil {
+			return fmt.Errorf("failed to read table data: %w", err)
+		}
+		for col, fieldID := range fieldIDs {
+			sketch, ok := s.sketches[fieldID]
+			if !ok {
+				continue
+			}
+			arr := record.Column(col)
+			for i := 0; i < arr.Len(); i++ {
+				if arr.IsNull(i) {
+					continue
+				}
+				if value, ok := singleValueBytes(arr, i); ok {
+					sketch.Update(value)
+				}
+			}
+		}
+		record.Release()
+	}
+
+	for i := range s.Columns {
+		estimate := int64(math.Round(s.sketches[s.Columns[i].FieldID].Estimate()))
+		s.Columns[i].DistinctCount = &estimate
+	}
+	return nil
+}
+
+// loadDistinctCounts reads the NDV estimates of the Puffin statistics file, if one was
+// written for the current snapshot
+func (s *TableStats) loadDistinctCounts(tbl *table.Table) error {
+	props := tbl.Properties()
+	location := props[StatisticsFileProperty]
+	if location == "" || props[StatisticsSnapshotProperty] != strconv.FormatInt(s.SnapshotID, 10) {
+		return nil
+	}
+
+	f, err := tbl.FS().Open(location)
+	if err != nil {
+		return fmt.Errorf("failed to open statistics file %s: %w", location, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat statistics file %s: %w", location, err)
+	}
+	footer, err := ReadPuffinFooter(f, info.Size())
+	if err != nil {
+		return fmt.Errorf("failed to read statistics file %s: %w", location, err)
+	}
+
+	for _, blob := range footer.Blobs {
+		if blob.Type != ThetaSketchBlobType || len(blob.Fields) != 1 || blob.SnapshotID != s.SnapshotID {
+			continue
+		}
+		ndv, err := strconv.ParseInt(blob.Properties[puffinNDVProperty], 10, 64)
+		if err != nil {
+			// Fall back to the sketch itself when the property is missing
+			data := make([]byte, blob.Length)
+			if _, err := f.ReadAt(data, blob.Offset); err != nil {
+				return fmt.Errorf("failed to read statistics blob: %w", err)
+			}
+			estimate, err := thetaEstimateFromCompact(data)
+			if err != nil {
+				continue
+			}
+			ndv = int64(math.Round(estimate))
+		}
+		for i := range s.Columns {
+			if s.Columns[i].FieldID == blob.Fields[0] {
+				s.Columns[i].DistinctCount = &ndv
+			}
+		}
+	}
+
+	s.StatisticsFile = location
+	return nil
+}
+
+// WriteStatisticsFile writes the theta sketches of the statistics to a Puffin file in the
+// table's metadata directory and records it in the table properties. The statistics must
+// have been collected with ComputeDistinct. A statistics file it replaces is deleted.
+func (w *Writer) WriteStatisticsFile(ctx context.Context, tbl *table.Table, stats *TableStats) (string, error) {
+	if stats.sketches == nil {
+		return "", fmt.Errorf("statistics were collected without distinct value sketches")
+	}
+
+	snapshot := tbl.CurrentSnapshot()
+	if snapshot == nil || snapshot.SnapshotID != stats.SnapshotID {
+		return "", fmt.Errorf("statistics are not for the current snapshot")
+	}
+
+	wfs, ok := tbl.FS().(icebergio.WriteFileIO)
+	if !ok {
+		return "", fmt.Errorf("table file IO does not support writing")
+	}
+
+	var blobs []PuffinBlob
+	for _, column := range stats.Columns {
+		sketch := stats.sketches[column.FieldID]
+		blobs = append(blobs, PuffinBlob{
+			Type:           ThetaSketchBlobType,
+			Fields:         []int{column.FieldID},
+			SnapshotID:     snapshot.SnapshotID,
+			SequenceNumber: snapshot.SequenceNumber,
+			Properties:     map[string]string{puffinNDVProperty: strconv.FormatInt(int64(math.Round(sketch.Estimate())), 10)},
+			Data:           sketch.Serialize(),
+		})
+	}
+
+	locProvider, err := tbl.LocationProvider()
+	if err != nil {
+		return "", fmt.Errorf("failed to get location provider: %w", err)
+	}
+	location := locProvider.NewMetadataLocation(fmt.Sprintf("%d-%s.stats", snapshot.SnapshotID, uuid.New()))
+
+	out, err := wfs.Create(location)
+	if err != nil {
+		return "", fmt.Errorf("failed to create statistics file: %w", err)
+	}
+	if _, err := WritePuffin(out, blobs, map[string]string{"created-by": "icebox"}); err != nil {
+		out.Close()
+		return "", err
+	}
+	if err := out.Close(); err != nil {
+		return "", fmt.Errorf("failed to close statistics file: %w", err)
+	}
+
+	previous := tbl.Properties()[StatisticsFileProperty]
+	updates := []table.Update{
+		table.NewSetPropertiesUpdate(iceberg.Properties{
+			StatisticsFileProperty:     location,
+			StatisticsSnapshotProperty: strconv.FormatInt(snapshot.SnapshotID, 10),
+		}),
+	}
+	// The statistics are only for the snapshot they were collected from
+	reqs := []table.Requirement{
+		table.AssertTableUUID(tbl.Metadata().TableUUID()),
+		table.AssertRefSnapshotID(table.MainBranch, &snapshot.SnapshotID),
+	}
+	if _, _, err := w.catalog.CommitTable(ctx, tbl, reqs, updates); err != nil {
+		_ = wfs.Remove(location)
+		return "", fmt.Errorf("failed to commit statistics file: %w", err)
+	}
+
+	// The previous file is only deleted when the table, read back after the commit, still
+	// records the new file; otherwise a later commit replaced it and owns the cleanup
+	if previous != "" && previous != location {
+		current, err := w.catalog.LoadTable(ctx, tbl.Identifier(), nil)
+		if err == nil && current.Properties()[StatisticsFileProperty] == location {
+			_ = wfs.Remove(previous)
+		}
+	}
+
+	stats.StatisticsFile = location
+	return location, nil
+}
+
+// singleValueBytes returns the Iceberg single-value serialization of an Arrow value,
+// which is the input theta sketches are computed over
+func singleValueBytes(arr arrow.Array, i int) ([]byte, bool) {
+	var lit iceberg.Literal
+	switch a := arr.(type) {
+	case *array.Boolean:
+		lit = iceberg.NewLiteral(a.Value(i))
+	case *array.Int8:
+		lit = iceberg.NewLiteral(int32(a.Value(i)))
+	case *array.Int16:
+		lit = iceberg.NewLiteral(int32(a.Value(i)))
+	case *array.Int32:
+		lit = iceberg.NewLiteral(a.Value(i))
+	case *array.Int64:
+		lit = iceberg.NewLiteral(a.Value(i))
+	case *array.Float32:
+		lit = iceberg.NewLiteral(a.Value(i))
+	case *array.Float64:
+		lit = iceberg.NewLiteral(a.Value(i))
+	case *array.Date32:
+		lit = iceberg.NewLiteral(iceberg.Date(a.Value(i)))
+	case *array.Time64:
+		micros := int64(a.Value(i))
+		if a.DataType().(*arrow.Time64Type).Unit == arrow.Nanosecond {
+			micros /= 1000
+		}
+		lit = iceberg.NewLiteral(iceberg.Time(micros))
+	case *array.Timestamp:
+		unit := a.DataType().(*arrow.TimestampType).Unit
+		micros := int64(a.Value(i)) * int64(unit.Multiplier()) / int64(arrow.Microsecond.Multiplier())
+		lit = iceberg.NewLiteral(iceberg.Timestamp(micros))
+	case *array.String:
+		lit = iceberg.NewLiteral(a.Value(i))
+	case *array.LargeString:
+		lit = iceberg.NewLiteral(a.Value(i))
+	case *array.Binary:
+		lit = iceberg.NewLiteral(a.Value(i))
+	case *array.LargeBinary:
+		lit = iceberg.NewLiteral(a.Value(i))
+	case *array.FixedSizeBinary:
+		lit = iceberg.NewLiteral(a.Value(i))
+	case *array.Decimal128:
+		lit = iceberg.NewLiteral(iceberg.Decimal{
+			Val:   a.Value(i),
+			Scale: int(a.DataType().(*arrow.Decimal128Type).Scale),
+		})
+	case array.ExtensionArray:
+		return singleValueBytes(a.Storage(), i)
+	default:
+		return nil, false
+	}
+
+	data, err := lit.MarshalBinary()
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// compareLiterals orders two literals of the same type
+func compareLiterals(a, b iceberg.Literal) int {
+	switch a := a.(type) {
+	case iceberg.TypedLiteral[bool]:
+		return compareTyped(a, b)
+	case iceberg.TypedLiteral[int32]:
+		return compareTyped(a, b)
+	case iceberg.TypedLiteral[int64]:
+		return compareTyped(a, b)
+	case iceberg.TypedLiteral[float32]:
+		return compareTyped(a, b)
+	case iceberg.TypedLiteral[float64]:
+		return compareTyped(a, b)
+	case iceberg.TypedLiteral[iceberg.Date]:
+		return compareTyped(a, b)
+	case iceberg.TypedLiteral[iceberg.Time]:
+		return compareTyped(a, b)
+	case iceberg.TypedLiteral[iceberg.Timestamp]:
+		return compareTyped(a, b)
+	case iceberg.TypedLiteral[string]:
+		return compareTyped(a, b)
+	case iceberg.TypedLiteral[[]byte]:
+		return compareTyped(a, b)
+	case iceberg.TypedLiteral[uuid.UUID]:
+		return compareTyped(a, b)
+	case iceberg.TypedLiteral[iceberg.Decimal]:
+		return compareTyped(a, b)
+	}
+	return 0
+}
+
+func compareTyped[T iceberg.LiteralType](a iceberg.TypedLiteral[T], b iceberg.Literal) int {
+	other, ok := b.(iceberg.TypedLiteral[T])
+	if !ok {
+		return 0
+	}
+	return a.Comparator()(a.Value(), other.Value())
+}
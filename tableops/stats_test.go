@@ -0,0 +1,206 @@
+package tableops
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/TFMV/icebox/catalog"
+	"github.com/TFMV/icebox/config"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectTableStats(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		Name: "test-catalog",
+		Catalog: config.CatalogConfig{
+			Type: "sqlite",
+			SQLite: &config.SQLiteConfig{
+				Path: filepath.Join(tempDir, "catalog.db"),
+			},
+		},
+		Storage: config.StorageConfig{
+			FileSystem: &config.FileSystemConfig{
+				RootPath: filepath.Join(tempDir, "data"),
+			},
+		},
+	}
+
+	cat, err := catalog.NewCatalog(cfg)
+	require.NoError(t, err)
+	defer cat.Close()
+
+	ctx := context.Background()
+	identifier := table.Identifier{"default", "users"}
+	require.NoError(t, cat.CreateNamespace(ctx, table.Identifier{"default"}, iceberg.Properties{}))
+
+	schema := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64},
+		iceberg.NestedField{ID: 2, Name: "city", Type: iceberg.PrimitiveTypes.String},
+	)
+	_, err = cat.CreateTable(ctx, identifier, schema)
+	require.NoError(t, err)
+
+	// Empty tables have no file or record statistics
+	tbl, err := cat.LoadTable(ctx, identifier, nil)
+	require.NoError(t, err)
+	stats, err := CollectTableStats(ctx, tbl, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.DataFiles)
+	assert.Len(t, stats.Columns, 2)
+
+	writer := NewWriter(cat)
+	arrowSchema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "city", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+	batches := []struct {
+		ids    []int64
+		cities []string
+		valid  []bool
+	}{
+		{ids: []int64{1, 2, 3}, cities: []string{"Paris", "Oslo", ""}, valid: []bool{true, true, false}},
+		{ids: []int64{4, 5, 6}, cities: []string{"Lima", "Paris", "Oslo"}, valid: nil},
+	}
+	for _, batch := range batches {
+		idBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+		idBuilder.AppendValues(batch.ids, nil)
+		cityBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+		cityBuilder.AppendValues(batch.cities, batch.valid)
+		ids, cities := idBuilder.NewArray(), cityBuilder.NewArray()
+		idBuilder.Release()
+		cityBuilder.Release()
+
+		record := array.NewRecord(arrowSchema, []arrow.Array{ids, cities}, int64(len(batch.ids)))
+		arrowTable := array.NewTableFromRecords(arrowSchema, []arrow.Record{record})
+
+		tbl, err := cat.LoadTable(ctx, identifier, nil)
+		require.NoError(t, err)
+		require.NoError(t, writer.WriteArrowTable(ctx, tbl, arrowTable, nil))
+
+		arrowTable.Release()
+		record.Release()
+		ids.Release()
+		cities.Release()
+	}
+
+	tbl, err = cat.LoadTable(ctx, identifier, nil)
+	require.NoError(t, err)
+
+	stats, err = CollectTableStats(ctx, tbl, nil)
+	require.NoError(t, err)
+	assert.Equal(t, tbl.CurrentSnapshot().SnapshotID, stats.SnapshotID)
+	assert.Equal(t, 2, stats.DataFiles)
+	assert.Equal(t, int64(6), stats.RecordCount)
+	assert.Positive(t, stats.TotalSize)
+
+	require.Len(t, stats.Columns, 2)
+	id, city := stats.Columns[0], stats.Columns[1]
+	assert.Equal(t, "id", id.Name)
+	assert.Equal(t, int64(0), id.NullCount)
+	assert.Equal(t, "1", id.LowerBound.String())
+	assert.Equal(t, "6", id.UpperBound.String())
+	assert.Nil(t, id.DistinctCount)
+
+	assert.Equal(t, int64(1), city.NullCount)
+	assert.Equal(t, "Lima", city.LowerBound.String())
+	assert.Equal(t, "Paris", city.UpperBound.String())
+
+	// Distinct values are estimated by scanning the data
+	stats, err = CollectTableStats(ctx, tbl, &StatsOptions{ComputeDistinct: true})
+	require.NoError(t, err)
+	require.NotNil(t, stats.Columns[0].DistinctCount)
+	assert.Equal(t, int64(6), *stats.Columns[0].DistinctCount)
+	assert.Equal(t, int64(3), *stats.Columns[1].DistinctCount)
+
+	// Estimates persist in a Puffin file and are read back without a scan
+	location, err := writer.WriteStatisticsFile(ctx, tbl, stats)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(location, ".stats"))
+
+	tbl, err = cat.LoadTable(ctx, identifier, nil)
+	require.NoError(t, err)
+	assert.Equal(t, location, tbl.Properties()[StatisticsFileProperty])
+	assert.Equal(t, strconv.FormatInt(stats.SnapshotID, 10), tbl.Properties()[StatisticsSnapshotProperty])
+
+	reloaded, err := CollectTableStats(ctx, tbl, nil)
+	require.NoError(t, err)
+	assert.Equal(t, location, reloaded.StatisticsFile)
+	require.NotNil(t, reloaded.Columns[1].DistinctCount)
+	assert.Equal(t, int64(3), *reloaded.Columns[1].DistinctCount)
+
+	data, err := os.ReadFile(strings.TrimPrefix(location, "file://"))
+	require.NoError(t, err)
+	footer, err := ReadPuffinFooter(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	require.Len(t, footer.Blobs, 2)
+	assert.Equal(t, ThetaSketchBlobType, footer.Blobs[0].Type)
+	assert.Equal(t, []int{1}, footer.Blobs[0].Fields)
+	assert.Equal(t, "6", footer.Blobs[0].Properties["ndv"])
+
+	// Writing the statistics again replaces the previous file
+	previous := location
+	location, err = writer.WriteStatisticsFile(ctx, tbl, stats)
+	require.NoError(t, err)
+	assert.NotEqual(t, previous, location)
+	_, err = os.Stat(strings.TrimPrefix(previous, "file://"))
+	assert.True(t, os.IsNotExist(err), "previous statistics file should be removed")
+
+	tbl, err = cat.LoadTable(ctx, identifier, nil)
+	require.NoError(t, err)
+	assert.Equal(t, location, tbl.Properties()[StatisticsFileProperty])
+
+	// Purging the table also removes the statistics file
+	files, err := CollectTableFiles(tbl)
+	require.NoError(t, err)
+	var found bool
+	for _, ref := range files.MetadataFiles {
+		found = found || ref.Path == location
+	}
+	assert.True(t, found, "statistics file should be collected for purge")
+}
+
+func TestThetaSketch(t *testing.T) {
+	sketch := newThetaSketch()
+	assert.Equal(t, float64(0), sketch.Estimate())
+
+	empty := sketch.Serialize()
+	assert.Len(t, empty, 8)
+	estimate, err := thetaEstimateFromCompact(empty)
+	require.NoError(t, err)
+	assert.Equal(t, float64(0), estimate)
+
+	// Exact mode below the nominal size, duplicates are ignored
+	var value [8]byte
+	for i := 0; i < 1000; i++ {
+		binary.LittleEndian.PutUint64(value[:], uint64(i%500))
+		sketch.Update(value[:])
+	}
+	assert.Equal(t, float64(500), sketch.Estimate())
+
+	// Estimation mode above it, within a few percent of the true count
+	for i := 0; i < 100000; i++ {
+		binary.LittleEndian.PutUint64(value[:], uint64(i))
+		sketch.Update(value[:])
+	}
+	assert.InEpsilon(t, 100000, sketch.Estimate(), 0.05)
+
+	serialized := sketch.Serialize()
+	assert.Equal(t, byte(3), serialized[0], "estimation mode sketches have three preamble longs")
+	assert.Len(t, serialized, 24+8*sketch.k)
+	estimate, err = thetaEstimateFromCompact(serialized)
+	require.NoError(t, err)
+	assert.Equal(t, sketch.Estimate(), estimate)
+}
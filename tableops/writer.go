@@ -2,13 +2,14 @@ package tableops
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
-	"strings"
+	iofs "io/fs"
 	"time"
 
 	"github.com/TFMV/icebox/catalog"
-	"github.com/TFMV/icebox/fs/local"
+	"github.com/TFMV/icebox/config"
+	"github.com/TFMV/icebox/fs"
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
@@ -147,25 +148,34 @@ func (w *Writer) WriteParquetFile(ctx context.Context, icebergTable *table.Table
 	return w.WriteArrowTable(ctx, icebergTable, arrowTable, opts)
 }
 
+// storageConfig returns the storage configuration of the catalog, which locations of
+// input files on object stores need
+func (w *Writer) storageConfig() *config.Config {
+	provider, ok := w.catalog.(interface{ GetS3Config() *config.S3Config })
+	if !ok {
+		return nil
+	}
+	return &config.Config{Storage: config.StorageConfig{S3: provider.GetS3Config()}}
+}
+
 // readParquetFile reads a Parquet file and returns an Arrow table
 func (w *Writer) readParquetFile(ctx context.Context, path string) (arrow.Table, error) {
-	// Remove file:// prefix if present
-	localPath := path
-	if strings.HasPrefix(path, "file://") {
-		localPath = path[7:]
+	// Resolve the storage holding the file from its location
+	storage, err := fs.Resolve(ctx, path, w.storageConfig())
+	if err != nil {
+		return nil, err
 	}
 
 	// Ensure the file exists
-	exists, err := local.NewFileSystem("").Exists(localPath)
-	if err != nil {
+	if _, err := storage.Stat(path); err != nil {
+		if errors.Is(err, iofs.ErrNotExist) {
+			return nil, fmt.Errorf("parquet file does not exist: %s", path)
+		}
 		return nil, fmt.Errorf("failed to check file existence: %w", err)
 	}
-	if !exists {
-		return nil, fmt.Errorf("parquet file does not exist: %s", localPath)
-	}
 
 	// Open the Parquet file
-	f, err := os.Open(localPath)
+	f, err := storage.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}